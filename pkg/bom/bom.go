@@ -58,6 +58,10 @@ type BomComponent struct {
 type BomSubComponent struct {
 	Name string `json:"name"`
 
+	// Version is the version of the Helm chart of the subcomponent.  It is empty for the subcomponents
+	// that are not installed with their own chart.
+	Version string `json:"version,omitempty"`
+
 	// Repository is the name of the repository within a registry.  This is combined
 	// with the registry Value to form the image URL prefix, for example: ghcr.io/verrazzano,
 	// where ghci.io is the registry and Verrazzano is the repository name.
@@ -19,6 +19,8 @@ type RetryableError struct {
 	Cause error
 	// An optional Result type to return to the controllerruntime
 	Result controllerruntime.Result
+	// An optional description of what the operation is waiting for
+	Progress string
 }
 
 // HasCause indicates whether or not the error has a root cause
@@ -6,14 +6,18 @@ package helm
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
 	vzos "github.com/verrazzano/verrazzano/pkg/os"
 	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/strvals"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/yaml"
 )
 
 // Debug is set from a platform-operator arg and sets the helm --debug flag
@@ -66,6 +70,21 @@ func SetDefaultReleaseAppVersionFunction() {
 	releaseAppVersionFn = getReleaseAppVersion
 }
 
+// ReleaseChartFnType - Package-level var and functions to allow overriding GetReleaseChart for unit test purposes
+type ReleaseChartFnType func(releaseName string, namespace string) (string, error)
+
+var releaseChartFn ReleaseChartFnType = getReleaseChart
+
+// SetReleaseChartFunction Override the GetReleaseChart for unit testing
+func SetReleaseChartFunction(f ReleaseChartFnType) {
+	releaseChartFn = f
+}
+
+// SetDefaultReleaseChartFunction Reset the GetReleaseChart function
+func SetDefaultReleaseChartFunction() {
+	releaseChartFn = getReleaseChart
+}
+
 // Package-level var and functions to allow overriding getReleaseState for unit test purposes
 type releaseStateFnType func(releaseName string, namespace string) (string, error)
 
@@ -190,6 +209,37 @@ func Uninstall(log vzlog.VerrazzanoLogger, releaseName string, namespace string,
 	return stdout, stderr, nil
 }
 
+// Rollback will roll back the release in the specified namespace to the revision using helm rollback
+func Rollback(log vzlog.VerrazzanoLogger, releaseName string, namespace string, revision int, wait bool, dryRun bool) (stdout []byte, stderr []byte, err error) {
+	args := []string{strconv.Itoa(revision)}
+
+	stdout, stderr, err = runHelm(log, releaseName, namespace, "", "rollback", wait, args, dryRun)
+	if err != nil {
+		return stdout, stderr, err
+	}
+
+	return stdout, stderr, nil
+}
+
+// Pull downloads a chart from an OCI registry or chart repository, and extracts it into the destination directory
+func Pull(log vzlog.VerrazzanoLogger, chartRef string, version string, destDir string, insecureSkipTLSVerify bool) (stdout []byte, stderr []byte, err error) {
+	cmdArgs := []string{"pull", chartRef, "--untar", "--untardir", destDir}
+	if len(version) > 0 {
+		cmdArgs = append(cmdArgs, "--version", version)
+	}
+	if insecureSkipTLSVerify {
+		cmdArgs = append(cmdArgs, "--insecure-skip-tls-verify")
+	}
+	cmd := exec.Command("helm", cmdArgs...)
+	log.Progressf("Running Helm command %s", cmd.String())
+	stdout, stderr, err = runner.Run(cmd)
+	if err != nil {
+		log.Errorf("Failed pulling Helm chart %s: stderr %s", chartRef, string(stderr))
+		return stdout, stderr, err
+	}
+	return stdout, stderr, nil
+}
+
 // runHelm is a helper function to execute the helm CLI and return a result
 func runHelm(log vzlog.VerrazzanoLogger, releaseName string, namespace string, chartDir string, operation string, wait bool, args []string, dryRun bool) (stdout []byte, stderr []byte, err error) {
 	cmdArgs := []string{operation, releaseName}
@@ -339,6 +389,59 @@ func getChartStatus(releaseName string, namespace string) (string, error) {
 	return "", fmt.Errorf("No chart status found for %s/%s", namespace, releaseName)
 }
 
+// GetReleaseRevision returns the revision of the release from the JSON output of helm status, 0 if the release is
+// not found
+func GetReleaseRevision(releaseName string, namespace string) (int, error) {
+	statusInfo, err := getReleaseStatus(releaseName, namespace)
+	if err != nil || statusInfo == nil {
+		return 0, err
+	}
+	if revision, ok := statusInfo["version"].(float64); ok {
+		return int(revision), nil
+	}
+	return 0, fmt.Errorf("No release revision found for %s/%s", namespace, releaseName)
+}
+
+// GetReleaseChartVersion returns the chart version of the release from the JSON output of helm status, an empty
+// string if the release is not found
+func GetReleaseChartVersion(releaseName string, namespace string) (string, error) {
+	statusInfo, err := getReleaseStatus(releaseName, namespace)
+	if err != nil || statusInfo == nil {
+		return "", err
+	}
+	chart, _ := statusInfo["chart"].(map[string]interface{})
+	metadata, _ := chart["metadata"].(map[string]interface{})
+	if version, ok := metadata["version"].(string); ok {
+		return version, nil
+	}
+	return "", fmt.Errorf("No chart version found for %s/%s", namespace, releaseName)
+}
+
+// getReleaseStatus returns the JSON output of helm status for the release, nil if the release is not found
+func getReleaseStatus(releaseName string, namespace string) (map[string]interface{}, error) {
+	args := []string{"status", releaseName}
+	if namespace != "" {
+		args = append(args, "--namespace")
+		args = append(args, namespace)
+	}
+	args = append(args, "-o")
+	args = append(args, "json")
+	cmd := exec.Command("helm", args...)
+	stdout, stderr, err := runner.Run(cmd)
+	if err != nil {
+		if strings.Contains(string(stderr), "not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("helm status for release %s failed with stderr: %s", releaseName, string(stderr))
+	}
+
+	var statusInfo map[string]interface{}
+	if err := json.Unmarshal(stdout, &statusInfo); err != nil {
+		return nil, err
+	}
+	return statusInfo, nil
+}
+
 // getReleaseState extracts the release state from an "ls -o json" command for a specific release/namespace
 func getReleaseState(releaseName string, namespace string) (string, error) {
 	statusInfo, err := getReleases(namespace)
@@ -400,6 +503,33 @@ func GetReleaseValues(log vzlog.VerrazzanoLogger, valueKeys []string, releaseNam
 	return values, nil
 }
 
+// GetReleaseChart returns the chart name and version of the release, for example mychart-1.0.0, or an empty string
+// if the release is not found
+func GetReleaseChart(releaseName string, namespace string) (string, error) {
+	return releaseChartFn(releaseName, namespace)
+}
+
+// getReleaseChart extracts the release chart from a "ls -o json" command for a specific release/namespace, an empty
+// string is returned if the release is not found
+func getReleaseChart(releaseName string, namespace string) (string, error) {
+	statusInfo, err := getReleases(namespace)
+	if err != nil {
+		if err.Error() == ChartNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+
+	var chart string
+	for _, info := range statusInfo {
+		if info["name"] == releaseName {
+			chart, _ = info["chart"].(string)
+			break
+		}
+	}
+	return strings.TrimSpace(chart), nil
+}
+
 // getReleaseAppVersion extracts the release app_version from a "ls -o json" command for a specific release/namespace
 func getReleaseAppVersion(releaseName string, namespace string) (string, error) {
 	statusInfo, err := getReleases(namespace)
@@ -456,3 +586,94 @@ func SetCmdRunner(r vzos.CmdRunner) {
 func SetDefaultRunner() {
 	runner = vzos.DefaultRunner{}
 }
+
+// MergeOverrides merges the Helm overrides into a single map of values, the same way that the Helm CLI does.  The
+// value files are merged in order, followed by the --set, --set-string and --set-file values.
+func MergeOverrides(overrides []HelmOverrides) (map[string]interface{}, error) {
+	base := map[string]interface{}{}
+	for _, override := range overrides {
+		if len(override.FileOverride) == 0 {
+			continue
+		}
+		data, err := os.ReadFile(override.FileOverride)
+		if err != nil {
+			return nil, err
+		}
+		fileValues := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &fileValues); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %v", override.FileOverride, err)
+		}
+		base = MergeMaps(base, fileValues)
+	}
+	for _, override := range overrides {
+		if len(override.SetOverrides) > 0 {
+			if err := strvals.ParseInto(override.SetOverrides, base); err != nil {
+				return nil, fmt.Errorf("Failed parsing --set data: %v", err)
+			}
+		}
+	}
+	for _, override := range overrides {
+		if len(override.SetStringOverrides) > 0 {
+			if err := strvals.ParseIntoString(override.SetStringOverrides, base); err != nil {
+				return nil, fmt.Errorf("Failed parsing --set-string data: %v", err)
+			}
+		}
+	}
+	for _, override := range overrides {
+		if len(override.SetFileOverrides) > 0 {
+			reader := func(rs []rune) (interface{}, error) {
+				data, err := os.ReadFile(string(rs))
+				return string(data), err
+			}
+			if err := strvals.ParseIntoFile(override.SetFileOverrides, base, reader); err != nil {
+				return nil, fmt.Errorf("Failed parsing --set-file data: %v", err)
+			}
+		}
+	}
+	return base, nil
+}
+
+// MergeMaps merges the values in b into a, nested maps are merged and all other values in b replace the values in a
+func MergeMaps(a, b map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(a))
+	for k, v := range a {
+		out[k] = v
+	}
+	for k, v := range b {
+		if v, ok := v.(map[string]interface{}); ok {
+			if av, ok := out[k].(map[string]interface{}); ok {
+				out[k] = MergeMaps(av, v)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// FlattenValues returns the JSON encoded leaf values of the Helm values, keyed by the dotted path of the value
+func FlattenValues(values map[string]interface{}) map[string]string {
+	flattened := map[string]string{}
+	flattenValues("", values, flattened)
+	return flattened
+}
+
+// flattenValues adds the JSON encoded leaf values of the map to the flattened values
+func flattenValues(prefix string, values map[string]interface{}, flattened map[string]string) {
+	for key, value := range values {
+		path := key
+		if len(prefix) > 0 {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenValues(path, nested, flattened)
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			flattened[path] = fmt.Sprintf("%v", value)
+			continue
+		}
+		flattened[path] = string(data)
+	}
+}
@@ -40,6 +40,19 @@ type foundRunner struct {
 	t *testing.T
 }
 
+// rollbackRunner is used to test Helm rollback without actually running an OS exec command
+type rollbackRunner struct {
+	t *testing.T
+
+	expectedArgs []string
+}
+
+// Run rollbackRunner executor
+func (r rollbackRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	assert.Equal(r.t, r.expectedArgs, cmd.Args)
+	return []byte("success"), []byte(""), nil
+}
+
 // genericTestRunner is used to run generic OS commands with expected results
 type genericTestRunner struct {
 	stdOut []byte
@@ -173,6 +186,50 @@ func TestUninstallError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// TestRollback tests the Helm Rollback fn
+// GIVEN a call to Rollback
+//  WHEN the command executes successfully
+//  THEN the release is rolled back to the revision
+func TestRollback(t *testing.T) {
+	SetCmdRunner(rollbackRunner{
+		t:            t,
+		expectedArgs: []string{"helm", "rollback", release, "--wait", "--namespace", ns, "3"},
+	})
+	defer SetDefaultRunner()
+	_, _, err := Rollback(vzlog.DefaultLogger(), release, ns, 3, true, false)
+	assert.NoError(t, err)
+}
+
+// TestPull tests the Helm Pull fn
+// GIVEN a call to Pull
+//  WHEN the command executes successfully
+//  THEN the chart is pulled with the requested version into the destination directory
+func TestPull(t *testing.T) {
+	SetCmdRunner(rollbackRunner{
+		t: t,
+		expectedArgs: []string{"helm", "pull", "oci://registry.local/charts/addon", "--untar", "--untardir", "/tmp/charts",
+			"--version", "1.0.0", "--insecure-skip-tls-verify"},
+	})
+	defer SetDefaultRunner()
+	_, _, err := Pull(vzlog.DefaultLogger(), "oci://registry.local/charts/addon", "1.0.0", "/tmp/charts", true)
+	assert.NoError(t, err)
+}
+
+// TestRollbackError tests the Helm Rollback fn
+// GIVEN a call to Rollback
+//  WHEN the command executes and returns an error
+//  THEN the function returns an error
+func TestRollbackError(t *testing.T) {
+	SetCmdRunner(genericTestRunner{
+		stdOut: []byte{},
+		stdErr: []byte{},
+		err:    fmt.Errorf("Unexpected rollback error"),
+	})
+	defer SetDefaultRunner()
+	_, _, err := Rollback(vzlog.DefaultLogger(), release, ns, 3, true, false)
+	assert.Error(t, err)
+}
+
 // TestIsReleaseInstalled tests checking if a Helm release is installed
 // GIVEN a release name and namespace
 //  WHEN I call IsReleaseInstalled
@@ -839,3 +896,162 @@ func Test_GetReleaseAppVersion(t *testing.T) {
 		})
 	}
 }
+
+// TestMergeOverrides tests merging Helm overrides into a map of values
+// GIVEN a values file and set overrides
+//  WHEN I call MergeOverrides
+//  THEN the values are merged with the set overrides taking precedence over the file
+func TestMergeOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	merged, err := MergeOverrides([]HelmOverrides{
+		{FileOverride: "./testdata/values.yaml"},
+		{SetOverrides: "image.tag=2.0"},
+		{SetStringOverrides: "replicas=3"},
+	})
+	assert.NoError(err)
+	assert.Equal(map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "ghcr.io/verrazzano/foo",
+			"tag":        "2.0",
+		},
+		"replicas": "3",
+	}, merged)
+
+	_, err = MergeOverrides([]HelmOverrides{{FileOverride: "./testdata/missing.yaml"}})
+	assert.Error(err)
+}
+
+// TestMergeMaps tests merging maps of Helm values
+// GIVEN two maps of Helm values
+//  WHEN I call MergeMaps
+//  THEN nested maps are merged and all other values are replaced
+func TestMergeMaps(t *testing.T) {
+	assert := assert.New(t)
+
+	a := map[string]interface{}{
+		"image":    map[string]interface{}{"repository": "foo", "tag": "1.0"},
+		"replicas": 1,
+		"args":     []interface{}{"a"},
+	}
+	b := map[string]interface{}{
+		"image": map[string]interface{}{"tag": "2.0"},
+		"args":  []interface{}{"b"},
+	}
+	assert.Equal(map[string]interface{}{
+		"image":    map[string]interface{}{"repository": "foo", "tag": "2.0"},
+		"replicas": 1,
+		"args":     []interface{}{"b"},
+	}, MergeMaps(a, b))
+	// The input maps are not changed
+	assert.Equal("1.0", a["image"].(map[string]interface{})["tag"])
+}
+
+// TestGetReleaseRevision tests the GetReleaseRevision fn
+// GIVEN a call to GetReleaseRevision
+//  WHEN the release is found or not found
+//  THEN the revision of the release is returned, or 0 if the release is not found
+func TestGetReleaseRevision(t *testing.T) {
+	SetCmdRunner(genericTestRunner{
+		stdOut: []byte(`{"name": "weblogic-operator", "info": {"status": "deployed"}, "version": 4, "namespace": "verrazzano-system"}`),
+		stdErr: []byte{},
+	})
+	defer SetDefaultRunner()
+	revision, err := GetReleaseRevision("weblogic-operator", "verrazzano-system")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, revision)
+
+	SetCmdRunner(genericTestRunner{
+		stdOut: []byte{},
+		stdErr: []byte("Error: release: not found"),
+		err:    errors.New("not found error"),
+	})
+	revision, err = GetReleaseRevision("weblogic-operator", "verrazzano-system")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, revision)
+
+	SetCmdRunner(genericTestRunner{
+		stdOut: []byte{},
+		stdErr: []byte("Error: unexpected"),
+		err:    errors.New("unexpected error"),
+	})
+	_, err = GetReleaseRevision("weblogic-operator", "verrazzano-system")
+	assert.Error(t, err)
+}
+
+// TestGetReleaseChartVersion tests the GetReleaseChartVersion fn
+// GIVEN a call to GetReleaseChartVersion
+//  WHEN the release is found or not found
+//  THEN the chart version of the release is returned, or an empty string if the release is not found
+func TestGetReleaseChartVersion(t *testing.T) {
+	SetCmdRunner(genericTestRunner{
+		stdOut: []byte(`{"name": "weblogic-operator", "chart": {"metadata": {"name": "weblogic-operator", "version": "3.4.0"}}, "version": 4}`),
+		stdErr: []byte{},
+	})
+	defer SetDefaultRunner()
+	version, err := GetReleaseChartVersion("weblogic-operator", "verrazzano-system")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.4.0", version)
+
+	SetCmdRunner(genericTestRunner{
+		stdOut: []byte{},
+		stdErr: []byte("Error: release: not found"),
+		err:    errors.New("not found error"),
+	})
+	version, err = GetReleaseChartVersion("weblogic-operator", "verrazzano-system")
+	assert.NoError(t, err)
+	assert.Equal(t, "", version)
+
+	SetCmdRunner(genericTestRunner{
+		stdOut: []byte(`{"name": "weblogic-operator", "version": 4}`),
+		stdErr: []byte{},
+	})
+	_, err = GetReleaseChartVersion("weblogic-operator", "verrazzano-system")
+	assert.Error(t, err)
+}
+
+// TestGetReleaseChart tests the GetReleaseChart fn
+// GIVEN a call to GetReleaseChart
+//  WHEN the release is found or not found
+//  THEN the chart of the release is returned, or an empty string if the release is not found
+func TestGetReleaseChart(t *testing.T) {
+	SetCmdRunner(genericTestRunner{
+		stdOut: []byte(`[{"name": "verrazzano", "namespace": "verrazzano-system", "chart": "verrazzano-1.3.0", "app_version": "1.3.0"}]`),
+		stdErr: []byte{},
+	})
+	defer SetDefaultRunner()
+	chart, err := GetReleaseChart("verrazzano", "verrazzano-system")
+	assert.NoError(t, err)
+	assert.Equal(t, "verrazzano-1.3.0", chart)
+
+	chart, err = GetReleaseChart("unknown", "verrazzano-system")
+	assert.NoError(t, err)
+	assert.Equal(t, "", chart)
+
+	SetCmdRunner(genericTestRunner{
+		stdOut: []byte{},
+		stdErr: []byte("Error: unexpected"),
+		err:    errors.New("unexpected error"),
+	})
+	_, err = GetReleaseChart("verrazzano", "verrazzano-system")
+	assert.Error(t, err)
+}
+
+// TestFlattenValues tests the FlattenValues fn
+// GIVEN a map of Helm values
+//  WHEN I call FlattenValues
+//  THEN the JSON encoded leaf values are returned keyed by their dotted path
+func TestFlattenValues(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"image.repository": `"foo"`,
+		"image.tag":        `"1.0"`,
+		"replicas":         "1",
+		"args":             `["a"]`,
+		"empty":            "{}",
+	}, FlattenValues(map[string]interface{}{
+		"image":    map[string]interface{}{"repository": "foo", "tag": "1.0"},
+		"replicas": 1,
+		"args":     []interface{}{"a"},
+		"empty":    map[string]interface{}{},
+	}))
+}
@@ -74,6 +74,19 @@ func Install(log vzlog.VerrazzanoLogger, overrideStrings string, overridesFiles
 	return stdout, stderr, nil
 }
 
+// Uninstall removes Istio and all of its control plane resources from the cluster
+func Uninstall(log vzlog.VerrazzanoLogger) (stdout []byte, stderr []byte, err error) {
+	args := []string{"x", "uninstall", "--purge", "-y"}
+
+	// Perform istioctl call of type uninstall
+	stdout, stderr, err = runIstioctl(log, args, "uninstall")
+	if err != nil {
+		return stdout, stderr, err
+	}
+
+	return stdout, stderr, nil
+}
+
 // IsInstalled returns true if Istio is installed
 func IsInstalled(log vzlog.VerrazzanoLogger) (bool, error) {
 
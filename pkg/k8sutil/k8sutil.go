@@ -8,6 +8,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -17,13 +20,16 @@ import (
 	istioClient "istio.io/client-go/pkg/clientset/versioned"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/portforward"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
 	"k8s.io/client-go/util/homedir"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 )
@@ -261,6 +267,54 @@ func ExecPod(client kubernetes.Interface, cfg *rest.Config, pod *v1.Pod, contain
 	return stdout.String(), stderr.String(), nil
 }
 
+// NewPortForwardDialer is to be overridden during unit tests
+var NewPortForwardDialer = func(cfg *rest.Config, method string, url *url.URL) (httpstream.Dialer, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return spdy.NewDialer(upgrader, &http.Client{Transport: transport}, method, url), nil
+}
+
+// PortForwardPod forwards a free local port to a port of a pod, returning the local port and a function that stops
+// the forwarding.  The forwarded port is only bound to the loopback address 127.0.0.1.
+func PortForwardPod(client kubernetes.Interface, cfg *rest.Config, pod *v1.Pod, port int) (uint16, func(), error) {
+	request := client.
+		CoreV1().
+		RESTClient().
+		Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("portforward")
+	dialer, err := NewPortForwardDialer(cfg, "POST", request.URL())
+	if err != nil {
+		return 0, nil, err
+	}
+	stopChan := make(chan struct{})
+	readyChan := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)},
+		stopChan, readyChan, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return 0, nil, err
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- forwarder.ForwardPorts()
+	}()
+	select {
+	case <-readyChan:
+	case err = <-errChan:
+		return 0, nil, fmt.Errorf("error forwarding port %d of %v/%v: %v", port, pod.Namespace, pod.Name, err)
+	}
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		close(stopChan)
+		return 0, nil, fmt.Errorf("error getting the local port forwarded to port %d of %v/%v: %v", port, pod.Namespace, pod.Name, err)
+	}
+	return ports[0].Local, func() { close(stopChan) }, nil
+}
+
 // GetGoClient returns a go-client
 func GetGoClient(log ...vzlog.VerrazzanoLogger) (kubernetes.Interface, error) {
 	var logger vzlog.VerrazzanoLogger
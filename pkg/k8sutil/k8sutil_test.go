@@ -3,10 +3,14 @@
 package k8sutil_test
 
 import (
+	"errors"
 	"fmt"
 	spdyfake "github.com/verrazzano/verrazzano/pkg/k8sutil/fake"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/rest"
+	"net/url"
 	"os"
 	"testing"
 
@@ -304,3 +308,33 @@ func TestExecPod(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, spdyfake.PodSTDOUT, stdout)
 }
+
+// failingDialer is a port forward dialer that can't connect
+type failingDialer struct{}
+
+// Dial returns an error
+func (d failingDialer) Dial(_ ...string) (httpstream.Connection, string, error) {
+	return nil, "", errors.New("connection refused")
+}
+
+// TestPortForwardPodFail tests forwarding a port to a pod
+// GIVEN a pod in a cluster that can't be connected to
+//  WHEN PortForwardPod is called
+//  THEN PortForwardPod returns an error
+func TestPortForwardPodFail(t *testing.T) {
+	origDialer := k8sutil.NewPortForwardDialer
+	defer func() { k8sutil.NewPortForwardDialer = origDialer }()
+	k8sutil.NewPortForwardDialer = func(_ *rest.Config, _ string, _ *url.URL) (httpstream.Dialer, error) {
+		return failingDialer{}, nil
+	}
+	cfg, client := spdyfake.NewClientsetConfig()
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "name",
+		},
+	}
+	_, _, err := k8sutil.PortForwardPod(client, cfg, pod, 8080)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+}
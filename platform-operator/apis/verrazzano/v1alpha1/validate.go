@@ -115,15 +115,46 @@ func ValidateVersion(requestedVersion string) error {
 	return nil
 }
 
-// ValidateProfile check that requestedProfile is valid
-func ValidateProfile(requestedProfile ProfileType) error {
-	if len(requestedProfile) != 0 {
-		switch requestedProfile {
+// ValidateProfile check that each profile in requestedProfile is a built-in profile or a custom profile ConfigMap, and
+// that the profiles can be merged
+func ValidateProfile(client client.Client, requestedProfile ProfileType) error {
+	if len(requestedProfile) == 0 {
+		return nil
+	}
+	for _, profile := range strings.Split(string(requestedProfile), ",") {
+		profile = strings.TrimSpace(profile)
+		switch ProfileType(profile) {
 		case Prod, Dev, ManagedCluster:
+			continue
+		}
+		cm := &corev1.ConfigMap{}
+		err := client.Get(context.TODO(), types.NamespacedName{Namespace: constants.VerrazzanoInstallNamespace, Name: profile}, cm)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		if err != nil || cm.Labels[constants.VerrazzanoProfileLabel] != "true" {
+			return fmt.Errorf("Requested profile %s is invalid, valid options are dev, prod, managed-cluster, or the name of a ConfigMap in namespace %s with label %s=true",
+				profile, constants.VerrazzanoInstallNamespace, constants.VerrazzanoProfileLabel)
+		}
+	}
+	// Resolve and merge the whole chain of profiles, a custom profile can be invalid or layered on missing profiles
+	if profileMerger != nil {
+		if err := profileMerger(requestedProfile); err != nil {
+			return fmt.Errorf("Requested profile %s is invalid: %v", requestedProfile, err)
+		}
+	}
+	return nil
+}
+
+// ValidateDriftPolicy check that requestedPolicy is valid
+func ValidateDriftPolicy(requestedPolicy DriftPolicy) error {
+	if len(requestedPolicy) != 0 {
+		switch requestedPolicy {
+		case DriftPolicyReport, DriftPolicyRemediate:
 			return nil
 		default:
-			return fmt.Errorf("Requested profile %s is invalid, valid options are dev, prod, or managed-cluster",
-				requestedProfile)
+			return fmt.Errorf("Requested drift policy %s is invalid, valid options are %s or %s",
+				requestedPolicy, DriftPolicyReport, DriftPolicyRemediate)
 		}
 	}
 	return nil
@@ -463,3 +494,89 @@ func ValidateHelmValueOverrides(Overrides []Overrides) error {
 	}
 	return nil
 }
+
+// ValidateComponentHelmValueOverrides checks the Helm value overrides of each component
+func ValidateComponentHelmValueOverrides(components *ComponentSpec) error {
+	for _, comp := range getComponentHelmValueOverrides(components) {
+		if err := ValidateHelmValueOverrides(comp.overrides.ValueOverrides); err != nil {
+			return fmt.Errorf("Component %s: %v", comp.jsonName, err)
+		}
+	}
+	return nil
+}
+
+// componentHelmValueOverrides has the Helm value overrides of a component and the JSON name of the component
+type componentHelmValueOverrides struct {
+	jsonName  string
+	overrides *HelmValueOverrides
+}
+
+// getComponentHelmValueOverrides returns the Helm value overrides of the components that are specified
+func getComponentHelmValueOverrides(components *ComponentSpec) []componentHelmValueOverrides {
+	var result []componentHelmValueOverrides
+	add := func(jsonName string, overrides *HelmValueOverrides) {
+		result = append(result, componentHelmValueOverrides{jsonName: jsonName, overrides: overrides})
+	}
+	if components.ApplicationOperator != nil {
+		add("applicationOperator", &components.ApplicationOperator.HelmValueOverrides)
+	}
+	if components.AuthProxy != nil {
+		add("authProxy", &components.AuthProxy.HelmValueOverrides)
+	}
+	if components.CertManager != nil {
+		add("certManager", &components.CertManager.HelmValueOverrides)
+	}
+	if components.CoherenceOperator != nil {
+		add("coherenceOperator", &components.CoherenceOperator.HelmValueOverrides)
+	}
+	if components.Console != nil {
+		add("console", &components.Console.HelmValueOverrides)
+	}
+	if components.DNS != nil {
+		add("dns", &components.DNS.HelmValueOverrides)
+	}
+	if components.Fluentd != nil {
+		add("fluentd", &components.Fluentd.HelmValueOverrides)
+	}
+	if components.Ingress != nil {
+		add("ingress", &components.Ingress.HelmValueOverrides)
+	}
+	if components.Keycloak != nil {
+		add("keycloak", &components.Keycloak.HelmValueOverrides)
+		add("keycloak.mysql", &components.Keycloak.MySQL.HelmValueOverrides)
+	}
+	if components.Kiali != nil {
+		add("kiali", &components.Kiali.HelmValueOverrides)
+	}
+	if components.KubeStateMetrics != nil {
+		add("kubeStateMetrics", &components.KubeStateMetrics.HelmValueOverrides)
+	}
+	if components.OAM != nil {
+		add("oam", &components.OAM.HelmValueOverrides)
+	}
+	if components.Prometheus != nil {
+		add("prometheus", &components.Prometheus.HelmValueOverrides)
+	}
+	if components.PrometheusAdapter != nil {
+		add("prometheusAdapter", &components.PrometheusAdapter.HelmValueOverrides)
+	}
+	if components.PrometheusNodeExporter != nil {
+		add("prometheusNodeExporter", &components.PrometheusNodeExporter.HelmValueOverrides)
+	}
+	if components.PrometheusOperator != nil {
+		add("prometheusOperator", &components.PrometheusOperator.HelmValueOverrides)
+	}
+	if components.PrometheusPushgateway != nil {
+		add("prometheusPushgateway", &components.PrometheusPushgateway.HelmValueOverrides)
+	}
+	if components.Rancher != nil {
+		add("rancher", &components.Rancher.HelmValueOverrides)
+	}
+	if components.Verrazzano != nil {
+		add("verrazzano", &components.Verrazzano.HelmValueOverrides)
+	}
+	if components.WebLogicOperator != nil {
+		add("weblogicOperator", &components.WebLogicOperator.HelmValueOverrides)
+	}
+	return result
+}
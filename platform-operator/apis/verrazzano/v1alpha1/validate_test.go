@@ -1583,7 +1583,7 @@ func TestValidateVersionHigherOrEqualLowerVersion(t *testing.T) {
 // WHEN the profile provided is empty
 // THEN no error is returned
 func TestValidateProfileEmptyProfile(t *testing.T) {
-	assert.NoError(t, ValidateProfile(""))
+	assert.NoError(t, ValidateProfile(fake.NewFakeClientWithScheme(newScheme()), ""))
 }
 
 // TestValidateProfileEmptyProfile Tests ValidateProfile() for d pevrofile
@@ -1591,7 +1591,7 @@ func TestValidateProfileEmptyProfile(t *testing.T) {
 // WHEN the profile provided is dev
 // THEN no error is returned
 func TestValidateProfileDevProfile(t *testing.T) {
-	assert.NoError(t, ValidateProfile(Dev))
+	assert.NoError(t, ValidateProfile(fake.NewFakeClientWithScheme(newScheme()), Dev))
 }
 
 // TestValidateProfileInvalidProfile Tests ValidateProfile() for invalid profile
@@ -1599,7 +1599,64 @@ func TestValidateProfileDevProfile(t *testing.T) {
 // WHEN the profile provided is invalid
 // THEN an error is returned
 func TestValidateProfileInvalidProfile(t *testing.T) {
-	assert.Error(t, ValidateProfile("wrong-profile"))
+	assert.Error(t, ValidateProfile(fake.NewFakeClientWithScheme(newScheme()), "wrong-profile"))
+}
+
+// TestValidateProfileCustomProfile Tests ValidateProfile() for custom profiles
+// GIVEN a request for a custom profile
+// WHEN the profile is the name of a labelled ConfigMap in the install namespace
+// THEN no error is returned, otherwise an error is returned
+func TestValidateProfileCustomProfile(t *testing.T) {
+	client := fake.NewFakeClientWithScheme(newScheme(),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: constants.VerrazzanoInstallNamespace,
+			Name:      "staging",
+			Labels:    map[string]string{constants.VerrazzanoProfileLabel: "true"},
+		}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: constants.VerrazzanoInstallNamespace,
+			Name:      "unlabelled",
+		}},
+	)
+	assert.NoError(t, ValidateProfile(client, "staging"))
+	assert.NoError(t, ValidateProfile(client, "prod,staging"))
+	assert.Error(t, ValidateProfile(client, "unlabelled"))
+	assert.Error(t, ValidateProfile(client, "prod,edge"))
+}
+
+// TestValidateProfileMergeError Tests ValidateProfile() when the profiles can not be merged
+// GIVEN a request for a custom profile that exists
+// WHEN the chain of profiles can not be resolved or merged
+// THEN an error is returned
+func TestValidateProfileMergeError(t *testing.T) {
+	defer SetProfileMerger(nil)
+	client := fake.NewFakeClientWithScheme(newScheme(),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: constants.VerrazzanoInstallNamespace,
+			Name:      "staging",
+			Labels:    map[string]string{constants.VerrazzanoProfileLabel: "true"},
+		}},
+	)
+	var merged ProfileType
+	SetProfileMerger(func(requestedProfile ProfileType) error {
+		merged = requestedProfile
+		return fmt.Errorf("Profile staging is layered on itself")
+	})
+	err := ValidateProfile(client, "prod,staging")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "layered on itself")
+	assert.Equal(t, ProfileType("prod,staging"), merged)
+}
+
+// TestValidateDriftPolicy Tests ValidateDriftPolicy()
+// GIVEN a request for a drift policy
+// WHEN the drift policy is empty, valid or invalid
+// THEN an error is only returned for the invalid drift policy
+func TestValidateDriftPolicy(t *testing.T) {
+	assert.NoError(t, ValidateDriftPolicy(""))
+	assert.NoError(t, ValidateDriftPolicy(DriftPolicyReport))
+	assert.NoError(t, ValidateDriftPolicy(DriftPolicyRemediate))
+	assert.Error(t, ValidateDriftPolicy("Ignore"))
 }
 
 // TestValidateProfileInvalidProfile Tests cleanTempFiles()
@@ -1664,6 +1721,34 @@ func TestValidateHelmValueOverrides(t *testing.T) {
 	assert.NoError(err3)
 }
 
+// TestValidateComponentHelmValueOverrides tests the ValidateComponentHelmValueOverrides function
+// GIVEN components with Helm value overrides
+// WHEN the overrides of a component are invalid
+// THEN an error naming the component is returned
+func TestValidateComponentHelmValueOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	goodOverrides := HelmValueOverrides{
+		ValueOverrides: []Overrides{{ConfigMapRef: &corev1.ConfigMapKeySelector{Key: "values.yaml"}}},
+	}
+	components := ComponentSpec{
+		Kiali:    &KialiComponent{HelmValueOverrides: goodOverrides},
+		Keycloak: &KeycloakComponent{MySQL: MySQLComponent{HelmValueOverrides: goodOverrides}},
+	}
+	assert.NoError(ValidateComponentHelmValueOverrides(&components))
+
+	components.Keycloak.MySQL.ValueOverrides = []Overrides{{}}
+	err := ValidateComponentHelmValueOverrides(&components)
+	assert.Error(err)
+	assert.Contains(err.Error(), "keycloak.mysql")
+
+	components.Keycloak = nil
+	components.Rancher = &RancherComponent{HelmValueOverrides: HelmValueOverrides{ValueOverrides: []Overrides{{}}}}
+	err = ValidateComponentHelmValueOverrides(&components)
+	assert.Error(err)
+	assert.Contains(err.Error(), "rancher")
+}
+
 var testKey = []byte{}
 
 // Generate RSA for testing.
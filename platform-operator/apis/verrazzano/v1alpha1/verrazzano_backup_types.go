@@ -0,0 +1,209 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupPhase is the phase of a backup or a restore, or of one of their components
+type BackupPhase string
+
+const (
+	// BackupPhasePending is the phase when the backup or restore is waiting to start
+	BackupPhasePending BackupPhase = "Pending"
+
+	// BackupPhaseInProgress is the phase when the backup or restore is running
+	BackupPhaseInProgress BackupPhase = "InProgress"
+
+	// BackupPhaseCompleted is the phase when the backup or restore completed successfully
+	BackupPhaseCompleted BackupPhase = "Completed"
+
+	// BackupPhaseFailed is the phase when the backup or restore failed
+	BackupPhaseFailed BackupPhase = "Failed"
+
+	// BackupPhaseSkipped is the phase of a component that is not installed, so it is not backed up or restored
+	BackupPhaseSkipped BackupPhase = "Skipped"
+
+	// BackupPhaseScheduled is the phase of a backup with a schedule, which creates a backup at each scheduled time
+	BackupPhaseScheduled BackupPhase = "Scheduled"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=verrazzanobackups
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=vzbackup;vzbackups
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The phase of the backup"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule",description="The schedule of the backup"
+// +kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".status.completionTime",description="The time the backup completed"
+// +genclient
+
+// VerrazzanoBackup is the Schema for the verrazzanobackups API.  It backs up the state of the Verrazzano platform
+// components to a storage location.  A backup with a schedule creates a backup at each scheduled time.
+type VerrazzanoBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerrazzanoBackupSpec   `json:"spec,omitempty"`
+	Status VerrazzanoBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerrazzanoBackupList contains a list of VerrazzanoBackup
+type VerrazzanoBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerrazzanoBackup `json:"items"`
+}
+
+// VerrazzanoBackupSpec defines what is backed up, where it is stored and when
+type VerrazzanoBackupSpec struct {
+	// StorageLocation is where the backup is stored
+	StorageLocation BackupStorageLocation `json:"storageLocation"`
+	// Components are the names of the components that are backed up, all the components that support a backup are
+	// backed up if not specified: verrazzano, keycloak, rancher, opensearch and grafana
+	// +optional
+	Components []string `json:"components,omitempty"`
+	// Schedule is a cron expression, in UTC, at which a backup is created
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// Retention is how long the backups created by the schedule are kept
+	// +optional
+	Retention *BackupRetention `json:"retention,omitempty"`
+}
+
+// BackupStorageLocation is the location of a backup.  Exactly one of S3 or PersistentVolumeClaim must be specified.
+type BackupStorageLocation struct {
+	// S3 is an S3 compatible object store
+	// +optional
+	S3 *S3StorageLocation `json:"s3,omitempty"`
+	// PersistentVolumeClaim is a persistent volume claim in the namespace of the backup
+	// +optional
+	PersistentVolumeClaim *PersistentVolumeClaimStorageLocation `json:"persistentVolumeClaim,omitempty"`
+}
+
+// S3StorageLocation is a bucket of an S3 compatible object store
+type S3StorageLocation struct {
+	// Endpoint is the URL of the object store, for example https://minio.example.com:9000
+	Endpoint string `json:"endpoint"`
+	// Bucket is the name of the bucket
+	Bucket string `json:"bucket"`
+	// Prefix is the path in the bucket that the backups are stored under
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Region is the region of the bucket
+	// +optional
+	Region string `json:"region,omitempty"`
+	// CredentialSecretName is the name of the secret in the verrazzano-system namespace with the
+	// object_store_access_key and object_store_secret_key of the object store, the default is the verrazzano-backup
+	// secret that is also used by OpenSearch
+	// +optional
+	CredentialSecretName string `json:"credentialSecretName,omitempty"`
+}
+
+// PersistentVolumeClaimStorageLocation is a directory of a persistent volume claim
+type PersistentVolumeClaimStorageLocation struct {
+	// ClaimName is the name of the persistent volume claim
+	ClaimName string `json:"claimName"`
+	// Path is the directory in the volume that the backups are stored under
+	// +optional
+	Path string `json:"path,omitempty"`
+	// OpenSearchRepositoryPath is the location of the shared file system repository of the OpenSearch snapshots,
+	// which must be registered in the path.repo setting of the OpenSearch nodes
+	// +optional
+	OpenSearchRepositoryPath string `json:"openSearchRepositoryPath,omitempty"`
+}
+
+// BackupRetention defines which of the backups created by a schedule are deleted
+type BackupRetention struct {
+	// MaxBackups is the number of completed backups that are kept
+	// +optional
+	MaxBackups int `json:"maxBackups,omitempty"`
+	// TTL is how long a backup is kept after it was created
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// VerrazzanoBackupStatus defines the observed state of VerrazzanoBackup
+type VerrazzanoBackupStatus struct {
+	// Phase is the phase of the backup
+	Phase BackupPhase `json:"phase,omitempty"`
+	// Message is the reason the backup is pending or failed
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the backup started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the backup completed or failed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// LastScheduleTime is the last time a backup was created by the schedule
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Components are the phases of the components that are backed up, in backup order
+	Components []BackupComponentStatus `json:"components,omitempty"`
+}
+
+// BackupComponentStatus is the phase of the backup or restore of a component
+type BackupComponentStatus struct {
+	// Name is the name of the component
+	Name string `json:"name"`
+	// Phase is the phase of the backup or restore of the component
+	Phase BackupPhase `json:"phase"`
+	// Message is the reason the component was skipped or failed
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=verrazzanorestores
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=vzrestore;vzrestores
+// +kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backupName",description="The backup that is restored"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The phase of the restore"
+// +genclient
+
+// VerrazzanoRestore is the Schema for the verrazzanorestores API.  It restores the state of the Verrazzano platform
+// components from a VerrazzanoBackup.  The Verrazzano resource and its secrets are restored first, each other
+// component is restored once it is installed and ready.
+type VerrazzanoRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerrazzanoRestoreSpec   `json:"spec,omitempty"`
+	Status VerrazzanoRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerrazzanoRestoreList contains a list of VerrazzanoRestore
+type VerrazzanoRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerrazzanoRestore `json:"items"`
+}
+
+// VerrazzanoRestoreSpec defines the backup that is restored
+type VerrazzanoRestoreSpec struct {
+	// BackupName is the name of the completed VerrazzanoBackup, in the same namespace, that is restored
+	BackupName string `json:"backupName"`
+	// Components are the names of the components that are restored, all the components of the backup are restored
+	// if not specified
+	// +optional
+	Components []string `json:"components,omitempty"`
+}
+
+// VerrazzanoRestoreStatus defines the observed state of VerrazzanoRestore
+type VerrazzanoRestoreStatus struct {
+	// Phase is the phase of the restore
+	Phase BackupPhase `json:"phase,omitempty"`
+	// Message is the reason the restore is pending or failed
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the restore started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the restore completed or failed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Components are the phases of the components that are restored, in restore order
+	Components []BackupComponentStatus `json:"components,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerrazzanoBackup{}, &VerrazzanoBackupList{}, &VerrazzanoRestore{}, &VerrazzanoRestoreList{})
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=verrazzanoextensions
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=vzext;vzexts
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".spec.namespace",description="The namespace the chart is installed in"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message",description="The reason the extension cannot be installed"
+// +genclient
+
+// VerrazzanoExtension is the Schema for the verrazzanoextensions API.  It describes a Helm chart that is installed,
+// upgraded and uninstalled as a component of the Verrazzano resource in the same namespace.
+type VerrazzanoExtension struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerrazzanoExtensionSpec   `json:"spec,omitempty"`
+	Status VerrazzanoExtensionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerrazzanoExtensionList contains a list of VerrazzanoExtension
+type VerrazzanoExtensionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerrazzanoExtension `json:"items"`
+}
+
+// VerrazzanoExtensionSpec defines the Helm chart of the extension and how it is installed.  The name of the
+// extension is the name of the component and of the Helm release, an extension with the name of an extension created
+// earlier in another namespace cannot be installed.
+type VerrazzanoExtensionSpec struct {
+	// Chart is the Helm chart of the extension
+	Chart ExtensionChart `json:"chart"`
+	// Namespace is the namespace the chart is installed in
+	Namespace string `json:"namespace"`
+	// Enabled, the extension is uninstalled when it is disabled
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Dependencies are the names of the components that must be ready before the extension is installed
+	// +optional
+	Dependencies []string `json:"dependencies,omitempty"`
+	// ReadinessChecks are the workloads that must be ready for the extension to be ready
+	// +optional
+	ReadinessChecks ExtensionReadinessChecks `json:"readinessChecks,omitempty"`
+	// ImagePullSecretKey is the Helm value key for the global image pull secret
+	// +optional
+	ImagePullSecretKey string `json:"imagePullSecretKey,omitempty"`
+	HelmValueOverrides `json:",inline"`
+}
+
+// ExtensionChart specifies where the Helm chart of an extension comes from.  Exactly one of Path or OCIReference
+// must be specified.
+type ExtensionChart struct {
+	// Path is the path of a chart directory in the platform operator image
+	// +optional
+	Path string `json:"path,omitempty"`
+	// OCIReference is the reference of the chart in an OCI registry, for example oci://registry.local/charts/addon
+	// +optional
+	OCIReference string `json:"ociReference,omitempty"`
+	// Version of the chart in the OCI registry
+	// +optional
+	Version string `json:"version,omitempty"`
+	// InsecureSkipTLSVerify skips the verification of the OCI registry certificate
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// ExtensionReadinessChecks are the workloads, in the namespace of the extension, that must be ready
+type ExtensionReadinessChecks struct {
+	// Deployments are the names of the deployments that must be ready
+	// +optional
+	Deployments []string `json:"deployments,omitempty"`
+	// StatefulSets are the names of the statefulsets that must be ready
+	// +optional
+	StatefulSets []string `json:"statefulSets,omitempty"`
+}
+
+// VerrazzanoExtensionStatus defines the observed state of VerrazzanoExtension.  The state of the extension
+// component is reported in the status of the Verrazzano resource.
+type VerrazzanoExtensionStatus struct {
+	// ObservedGeneration is the generation of the VerrazzanoExtension that was last processed
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Message is the reason the extension cannot be installed
+	Message string `json:"message,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerrazzanoExtension{}, &VerrazzanoExtensionList{})
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PlanAction is the action that would be taken for a component
+type PlanAction string
+
+const (
+	// PlanActionInstall means the component would be installed
+	PlanActionInstall PlanAction = "Install"
+	// PlanActionUpgrade means the component would be upgraded
+	PlanActionUpgrade PlanAction = "Upgrade"
+	// PlanActionReconcile means the installed component would be updated with the new configuration
+	PlanActionReconcile PlanAction = "Reconcile"
+	// PlanActionUninstall means the installed component would be uninstalled because it is disabled
+	PlanActionUninstall PlanAction = "Uninstall"
+	// PlanActionSkip means nothing would be done for the component
+	PlanActionSkip PlanAction = "Skip"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=verrazzanoplans
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=vzplan;vzplans
+// +kubebuilder:printcolumn:name="Verrazzano",type="string",JSONPath=".spec.verrazzanoName",description="The Verrazzano resource the plan is computed for"
+// +kubebuilder:printcolumn:name="Generated",type="string",JSONPath=".status.plan.lastGeneratedTime",description="The time the plan was computed"
+// +genclient
+
+// VerrazzanoPlan is the Schema for the verrazzanoplans API.  It reports the changes that would be made to the
+// components if the Verrazzano resource was updated with the proposed spec, without changing anything.
+type VerrazzanoPlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerrazzanoPlanSpec   `json:"spec,omitempty"`
+	Status VerrazzanoPlanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerrazzanoPlanList contains a list of VerrazzanoPlan
+type VerrazzanoPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerrazzanoPlan `json:"items"`
+}
+
+// VerrazzanoPlanSpec defines the desired state of VerrazzanoPlan
+type VerrazzanoPlanSpec struct {
+	// VerrazzanoName is the name of the Verrazzano resource in the same namespace to compute the plan for
+	VerrazzanoName string `json:"verrazzanoName"`
+	// Verrazzano is the proposed Verrazzano spec.  If not specified, the plan is computed for the current spec.
+	// +optional
+	Verrazzano *VerrazzanoSpec `json:"verrazzano,omitempty"`
+}
+
+// VerrazzanoPlanStatus defines the observed state of VerrazzanoPlan
+type VerrazzanoPlanStatus struct {
+	// ObservedGeneration is the generation of the VerrazzanoPlan that the plan was computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Plan is the computed set of changes
+	Plan *Plan `json:"plan,omitempty"`
+	// Message is the reason the plan could not be computed
+	Message string `json:"message,omitempty"`
+}
+
+// Plan is the set of changes that would be made to the components of a Verrazzano installation
+type Plan struct {
+	// Generation is the generation of the Verrazzano resource that the plan was computed for
+	Generation int64 `json:"generation,omitempty"`
+	// LastGeneratedTime is the time the plan was computed
+	LastGeneratedTime string `json:"lastGeneratedTime,omitempty"`
+	// Components are the planned actions for each component, in install order
+	Components []ComponentPlan `json:"components,omitempty"`
+}
+
+// ComponentPlan is the planned action for a single component
+type ComponentPlan struct {
+	// Name of the component
+	Name string `json:"name"`
+	// Action that would be taken for the component
+	Action PlanAction `json:"action"`
+	// Message explains why the action would be taken
+	Message string `json:"message,omitempty"`
+	// ValuesDiff is the difference between the Helm values of the live release and the rendered Helm values
+	ValuesDiff []HelmValueChange `json:"valuesDiff,omitempty"`
+}
+
+// HelmValueChange is a change to a single Helm value.  Values are JSON encoded, and are empty when the value is
+// not set.  The values of sensitive keys are redacted.
+type HelmValueChange struct {
+	// Path is the dotted path of the Helm value
+	Path string `json:"path"`
+	// Current is the value of the live release
+	Current string `json:"current,omitempty"`
+	// Planned is the value that would be applied
+	Planned string `json:"planned,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerrazzanoPlan{}, &VerrazzanoPlanList{})
+}
@@ -8,6 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // ProfileType is the type of install profile.
@@ -21,6 +22,17 @@ const (
 	// ManagedCluster identifies the production managed-cluster install profile
 	ManagedCluster ProfileType = "managed-cluster"
 )
+
+// DriftPolicy specifies how changes made to the components outside of Verrazzano are handled
+type DriftPolicy string
+
+const (
+	// DriftPolicyReport reports the changes with the Drifted condition of the component.  This is the default value.
+	DriftPolicyReport DriftPolicy = "Report"
+	// DriftPolicyRemediate reports the changes and re-applies the Verrazzano configuration of the component
+	DriftPolicyRemediate DriftPolicy = "Remediate"
+)
+
 const (
 	// LoadBalancer is an ingress type of LoadBalancer.  This is the default value.
 	LoadBalancer IngressType = "LoadBalancer"
@@ -34,6 +46,7 @@ const (
 // +kubebuilder:resource:shortName=vz;vzs
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[-1:].type",description="The current status of the install/uninstall"
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="The current version of the Verrazzano installation"
+// +kubebuilder:printcolumn:name="Health",type="string",JSONPath=".status.health.summary",description="The summary of the health of the Verrazzano components"
 // +genclient
 
 // Verrazzano is the Schema for the verrazzanos API
@@ -84,9 +97,95 @@ type VerrazzanoSpec struct {
 	// +optional
 	// +patchStrategy=merge,retainKeys
 	VolumeClaimSpecTemplates []VolumeClaimSpecTemplate `json:"volumeClaimSpecTemplates,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
+
+	// UpgradePolicy specifies how an upgrade is handled
+	// +optional
+	UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// DriftPolicy specifies how changes made to the Helm releases of the components outside of Verrazzano are
+	// handled, either Report or Remediate.  Default is Report.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// CertificateExpiryThreshold is how long before they expire that the certificates are reported as expiring
+	// soon.  Default is 336h (14 days).
+	// +optional
+	CertificateExpiryThreshold *metav1.Duration `json:"certificateExpiryThreshold,omitempty"`
+
+	// ComponentTimeouts specifies how long the install and upgrade of a component can take before the component is
+	// failed.  The defaults are set by the profiles.
+	// +optional
+	ComponentTimeouts *ComponentTimeouts `json:"componentTimeouts,omitempty"`
+}
+
+// ComponentTimeouts specifies how long the install and upgrade of a component can take before the component and the
+// Verrazzano resource are set to Failed.  A failed install is retried with the verrazzano.io/install-retry-version
+// annotation, a failed upgrade with the verrazzano.io/upgrade-retry-version annotation.
+type ComponentTimeouts struct {
+	// Install is how long the pre-install and install of a component can take, once the components it depends on are
+	// ready.  No timeout if not set.
+	// +optional
+	Install *metav1.Duration `json:"install,omitempty"`
+	// Upgrade is how long the upgrade of a component can take.  No timeout if not set.
+	// +optional
+	Upgrade *metav1.Duration `json:"upgrade,omitempty"`
+	// Components overrides the timeouts of individual components
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge,retainKeys
+	Components []ComponentTimeout `json:"components,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
+}
+
+// ComponentTimeout overrides the install and upgrade timeouts of a component
+type ComponentTimeout struct {
+	// Name of the component, as it appears in the components section
+	Name string `json:"name"`
+	// Install is how long the pre-install and install of the component can take
+	// +optional
+	Install *metav1.Duration `json:"install,omitempty"`
+	// Upgrade is how long the upgrade of the component can take
+	// +optional
+	Upgrade *metav1.Duration `json:"upgrade,omitempty"`
+}
+
+// UpgradePolicy specifies how an upgrade is handled
+type UpgradePolicy struct {
+	// RollbackOnFailure rolls the upgraded components back to their previous release if a component fails to upgrade.
+	// Default is false, the failed upgrade is retried.
+	// +optional
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+	// Stages is the ordered list of stages the components are upgraded in.  The components of a stage are upgraded
+	// after the components of all the previous stages, the components that are not in a stage are upgraded last.
+	// Default is a single stage with all the components.
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge,retainKeys
+	Stages []UpgradeStage `json:"stages,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
 }
 
-// CommonKubernetesSpec - Kubernetes resources that are common to a subgroup of components
+// UpgradeStage is a group of components that are upgraded together.  Before a stage is started, the components
+// upgraded by the previous stages must be ready for the soak time, and the stage must be approved if approval is
+// required.
+type UpgradeStage struct {
+	// Name of the stage
+	Name string `json:"name"`
+	// Components are the names of the components upgraded in the stage, as they appear in the components section.
+	// The dependencies of a component must be upgraded in the same stage or in an earlier stage.
+	Components []string `json:"components"`
+	// RequireApproval pauses the upgrade before the stage is started, until the stage is approved with the
+	// verrazzano.io/upgrade-approved-stage annotation set to <version>/<stage name>, where version is the target version
+	// of the upgrade.  Default is false.
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+	// SoakTime is the time the components upgraded by the previous stages must stay ready before the stage is
+	// started.  Default is 0.
+	// +optional
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+}
+
+// CommonKubernetesSpec - Kubernetes resources that are common to the components.  The scheduling settings apply to
+// all the pods of a component, the replicas, resources and pod disruption budget apply to its main workload.  A
+// component rejects the settings its charts don't support.
 type CommonKubernetesSpec struct {
 	// Replicas specifies the number of pod instances to run
 	// +optional
@@ -94,6 +193,32 @@ type CommonKubernetesSpec struct {
 	// Affinity specifies the group of affinity scheduling rules
 	// +optional
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations specifies the taints the pods tolerate
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// NodeSelector specifies the labels of the nodes the pods are scheduled on
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// PriorityClassName specifies the priority class of the pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Resources specifies the compute resources of the containers
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// PodDisruptionBudget specifies the disruption budget of the pods
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+}
+
+// PodDisruptionBudgetSpec specifies the number of pods of a component that must stay available during voluntary
+// disruptions, such as node drains.  Only one of MinAvailable and MaxUnavailable can be specified.
+type PodDisruptionBudgetSpec struct {
+	// MinAvailable is the number or percentage of pods that must stay available
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the number or percentage of pods that can be unavailable
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // SecuritySpec defines the security configuration for Verrazzano
@@ -149,6 +274,207 @@ type VerrazzanoStatus struct {
 	State VzStateType `json:"state,omitempty"`
 	// States of the individual installed components
 	Components ComponentStatusMap `json:"components,omitempty"`
+	// Plan is the set of changes that would be made to the components, computed when plan mode is requested
+	// +optional
+	Plan *Plan `json:"plan,omitempty"`
+	// ProfileChain is the chain of profiles merged, in order, into the configuration being installed.  A custom
+	// profile follows the profiles it is layered on.
+	// +optional
+	ProfileChain []string `json:"profileChain,omitempty"`
+	// Certificates is the inventory of the TLS certificates of the Verrazzano installation
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+	// CARotation is the progress of the staged rotation of the CA of the Verrazzano ClusterIssuer
+	// +optional
+	CARotation *CARotationStatus `json:"caRotation,omitempty"`
+	// Preflight is the result of the checks of the target cluster made before the last install or upgrade
+	// +optional
+	Preflight *PreflightStatus `json:"preflight,omitempty"`
+	// Health is the rollup of the current health of the enabled components
+	// +optional
+	Health *HealthStatus `json:"health,omitempty"`
+}
+
+// CARotationStage is a stage of the rotation of the CA of the Verrazzano ClusterIssuer
+type CARotationStage string
+
+const (
+	// CARotationPublishingBundle means the CA bundle with the previous and the new CA is being published to the
+	// clients of the Verrazzano certificates
+	CARotationPublishingBundle CARotationStage = "PublishingBundle"
+	// CARotationReissuingCertificates means the certificates are being re-issued by the new CA
+	CARotationReissuingCertificates CARotationStage = "ReissuingCertificates"
+	// CARotationPropagatingBundle means the CA bundle is being propagated to the managed clusters
+	CARotationPropagatingBundle CARotationStage = "PropagatingBundle"
+	// CARotationRemovingPreviousCA means the previous CA is being removed from the CA bundle
+	CARotationRemovingPreviousCA CARotationStage = "RemovingPreviousCA"
+	// CARotationComplete means the rotation of the CA is complete
+	CARotationComplete CARotationStage = "Complete"
+)
+
+// CARotationStatus is the progress of the staged rotation of the CA of the Verrazzano ClusterIssuer
+type CARotationStatus struct {
+	// Stage of the rotation, one of PublishingBundle, ReissuingCertificates, PropagatingBundle, RemovingPreviousCA
+	// or Complete
+	Stage CARotationStage `json:"stage"`
+	// PreviousCA is the common name of the previous CA
+	// +optional
+	PreviousCA string `json:"previousCA,omitempty"`
+	// NewCA is the common name of the new CA
+	// +optional
+	NewCA string `json:"newCA,omitempty"`
+	// StartTime is the time the rotation started
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// StageTime is the time the rotation entered the current stage
+	// +optional
+	StageTime string `json:"stageTime,omitempty"`
+	// Message describes what the current stage is waiting for
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PreflightCheckResult is the result of a preflight check of the target cluster
+type PreflightCheckResult string
+
+const (
+	// PreflightCheckPassed means the cluster meets the requirement of the check
+	PreflightCheckPassed PreflightCheckResult = "Passed"
+	// PreflightCheckWarning means the cluster may not meet the requirement of the check, the install or upgrade is
+	// not blocked
+	PreflightCheckWarning PreflightCheckResult = "Warning"
+	// PreflightCheckFailed means the cluster does not meet the requirement of the check, the install or upgrade is
+	// blocked until it is fixed
+	PreflightCheckFailed PreflightCheckResult = "Failed"
+)
+
+// PreflightCheck is the result of a check of the target cluster made before an install or upgrade
+type PreflightCheck struct {
+	// Name of the check
+	Name string `json:"name"`
+	// Component is the name of the component that contributed the check, empty for the checks of the cluster
+	// +optional
+	Component string `json:"component,omitempty"`
+	// Result of the check, one of Passed, Warning or Failed
+	Result PreflightCheckResult `json:"result"`
+	// Message describes the result of the check, and how to fix a failure
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PreflightStatus is the result of the checks of the target cluster made before an install or upgrade
+type PreflightStatus struct {
+	// Operation is the operation the cluster was checked for, install or upgrade
+	Operation string `json:"operation"`
+	// Passed is true if none of the checks failed
+	Passed bool `json:"passed"`
+	// LastTransitionTime is the time the results of the checks last changed
+	// +optional
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+	// Checks are the results of the checks
+	// +optional
+	Checks []PreflightCheck `json:"checks,omitempty"`
+}
+
+// HealthSummary is the summary of the health of the enabled components
+type HealthSummary string
+
+const (
+	// HealthAvailable means all the enabled components are ready
+	HealthAvailable HealthSummary = "Available"
+	// HealthDegraded means all the enabled components are installed, and some of them are not ready
+	HealthDegraded HealthSummary = "Degraded"
+	// HealthUnavailable means some of the enabled components are not installed yet, or have failed
+	HealthUnavailable HealthSummary = "Unavailable"
+)
+
+// HealthStatus is the rollup of the current health of the enabled components
+type HealthStatus struct {
+	// Summary of the health, one of Available, Degraded or Unavailable
+	Summary HealthSummary `json:"summary"`
+	// Message describes the components that are not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the time the summary last changed
+	// +optional
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+	// Components is the health of each enabled component
+	// +optional
+	Components []ComponentHealth `json:"components,omitempty"`
+}
+
+// ComponentHealth is the current health of a component
+type ComponentHealth struct {
+	// Name of the component
+	Name string `json:"name"`
+	// State of the component
+	State CompStateType `json:"state"`
+	// UnreadyWorkloads describes the deployments, statefulsets and daemonsets of the component that are not ready
+	// +optional
+	UnreadyWorkloads []string `json:"unreadyWorkloads,omitempty"`
+	// LastError is the last error of an operation of the component, it is kept until the component is ready
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is the time of the last error
+	// +optional
+	LastErrorTime string `json:"lastErrorTime,omitempty"`
+	// LastTransitionTime is the time of the last condition of the component, the time the component entered its
+	// state
+	// +optional
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// CertificateSource identifies what a certificate is used by
+type CertificateSource string
+
+const (
+	// CertificateSourceComponent is a certificate of a Verrazzano component
+	CertificateSourceComponent CertificateSource = "Component"
+	// CertificateSourceIngressTrait is a certificate of the gateway of an IngressTrait
+	CertificateSourceIngressTrait CertificateSource = "IngressTrait"
+	// CertificateSourceWebhook is the serving certificate of the webhooks of a Verrazzano operator
+	CertificateSourceWebhook CertificateSource = "Webhook"
+)
+
+// CertificateRenewalState is the renewal state of a certificate
+type CertificateRenewalState string
+
+const (
+	// CertificateValid means the certificate is ready and is not expiring soon
+	CertificateValid CertificateRenewalState = "Valid"
+	// CertificateRenewing means cert-manager is issuing the certificate
+	CertificateRenewing CertificateRenewalState = "Renewing"
+	// CertificateNotReady means the certificate has not been issued, or the last attempt to issue it failed
+	CertificateNotReady CertificateRenewalState = "NotReady"
+	// CertificateExpiringSoon means the certificate expires within the certificate expiry threshold
+	CertificateExpiringSoon CertificateRenewalState = "ExpiringSoon"
+	// CertificateExpired means the certificate has expired
+	CertificateExpired CertificateRenewalState = "Expired"
+)
+
+// CertificateStatus is the status of a TLS certificate of the Verrazzano installation
+type CertificateStatus struct {
+	// Name of the cert-manager Certificate, or of the webhook configuration for a webhook certificate
+	Name string `json:"name"`
+	// Namespace of the cert-manager Certificate, empty for a webhook certificate
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Source is what the certificate is used by, one of Component, IngressTrait or Webhook
+	Source CertificateSource `json:"source"`
+	// Component is the name of the component of a component certificate
+	// +optional
+	Component string `json:"component,omitempty"`
+	// Issuer is the kind and name of the cert-manager issuer, or the common name of the CA of a webhook certificate
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+	// NotAfter is the time the certificate expires
+	// +optional
+	NotAfter string `json:"notAfter,omitempty"`
+	// RenewalTime is the time cert-manager renews the certificate
+	// +optional
+	RenewalTime string `json:"renewalTime,omitempty"`
+	// RenewalState of the certificate, one of Valid, Renewing, NotReady, ExpiringSoon or Expired
+	RenewalState CertificateRenewalState `json:"renewalState"`
 }
 
 type ComponentStatusMap map[string]*ComponentStatusDetails
@@ -167,6 +493,16 @@ type ComponentStatusDetails struct {
 	LastReconciledGeneration int64 `json:"lastReconciledGeneration,omitempty"`
 	// The generation of the VZ resource the Component is currently being reconciled against
 	ReconcilingGeneration int64 `json:"reconcilingGeneration,omitempty"`
+	// The release of the component before the upgrade in progress, a failed upgrade is rolled back to this release
+	PreviousRelease *ComponentRelease `json:"previousRelease,omitempty"`
+}
+
+// ComponentRelease identifies a release of a component
+type ComponentRelease struct {
+	// The chart version of the Helm release, empty if the component is not installed with Helm
+	Version string `json:"version,omitempty"`
+	// The revision of the Helm release, 0 if the component is not installed with Helm
+	HelmRevision int `json:"helmRevision,omitempty"`
 }
 
 // ConditionType identifies the condition of the install/uninstall/upgrade which can be checked with kubectl wait
@@ -205,6 +541,19 @@ const (
 
 	// CondUpgradeComplete means the upgrade has completed successfully
 	CondUpgradeComplete ConditionType = "UpgradeComplete"
+
+	// CondUpgradeRolledBack means the upgrade has failed and the upgraded components were rolled back.
+	CondUpgradeRolledBack ConditionType = "UpgradeRolledBack"
+
+	// CondDegraded means a component that was ready is no longer ready.
+	CondDegraded ConditionType = "Degraded"
+
+	// CondDrifted means the Helm release of a component has been changed outside of Verrazzano.
+	CondDrifted ConditionType = "Drifted"
+
+	// CondCertificatesExpiringSoon means certificates of the Verrazzano installation expire within the certificate
+	// expiry threshold, or have expired.
+	CondCertificatesExpiringSoon ConditionType = "CertificatesExpiringSoon"
 )
 
 // Condition describes current state of an install.
@@ -269,6 +618,9 @@ const (
 	// CompStateReady is the state when a Verrazzano resource can perform an uninstall or upgrade
 	CompStateReady CompStateType = "Ready"
 
+	// CompStateDegraded is the state when a component that was ready is no longer ready
+	CompStateDegraded CompStateType = "Degraded"
+
 	// CompStateFailed is the state when an install/uninstall/upgrade has failed
 	CompStateFailed CompStateType = "Failed"
 )
@@ -389,15 +741,26 @@ type ElasticsearchComponent struct {
 	ESInstallArgs []InstallArgs                 `json:"installArgs,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
 	Policies      []vmov1.IndexManagementPolicy `json:"policies,omitempty"`
 	Nodes         []OpenSearchNode              `json:"nodes,omitempty"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
-//OpenSearchNode specifies a node group in the OpenSearch cluster
+// OpenSearchNode specifies a node group in the OpenSearch cluster
 type OpenSearchNode struct {
 	Name      string                       `json:"name,omitempty"`
 	Replicas  int32                        `json:"replicas,omitempty"`
 	Roles     []vmov1.NodeRole             `json:"roles,omitempty"`
 	Storage   *OpenSearchNodeStorage       `json:"storage,omitempty"`
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// VolumeSource Defines the type of volume to be used for persistence of the node group, overrides the
+	// DefaultVolumeSource and can not be used with Storage; at present only EmptyDirVolumeSource or
+	// PersistentVolumeClaimVolumeSource are supported. If PersistentVolumeClaimVolumeSource is used, it must reference
+	// a VolumeClaimSpecTemplate in the VolumeClaimSpecTemplates section.
+	// +optional
+	// +patchStrategy=replace
+	VolumeSource *corev1.VolumeSource `json:"volumeSource,omitempty" patchStrategy:"replace"`
 }
 
 type OpenSearchNodeStorage struct {
@@ -408,36 +771,70 @@ type OpenSearchNodeStorage struct {
 type KibanaComponent struct {
 	// +optional
 	Enabled *bool `json:"enabled,omitempty"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // KubeStateMetricsComponent specifies the kube-state-metrics configuration.
 type KubeStateMetricsComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // GrafanaComponent specifies the Grafana configuration.
 type GrafanaComponent struct {
 	// +optional
 	Enabled *bool `json:"enabled,omitempty"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
+	// VolumeSource Defines the type of volume to be used for persistence, overrides the DefaultVolumeSource; at present only EmptyDirVolumeSource or
+	// PersistentVolumeClaimVolumeSource are supported. If PersistentVolumeClaimVolumeSource is used, it must reference
+	// a VolumeClaimSpecTemplate in the VolumeClaimSpecTemplates section.
+	// +optional
+	// +patchStrategy=replace
+	VolumeSource *corev1.VolumeSource `json:"volumeSource,omitempty" patchStrategy:"replace"`
 }
 
 // PrometheusComponent specifies the Prometheus configuration.
 type PrometheusComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
+	// VolumeSource Defines the type of volume to be used for persistence, overrides the DefaultVolumeSource; at present only EmptyDirVolumeSource or
+	// PersistentVolumeClaimVolumeSource are supported. If PersistentVolumeClaimVolumeSource is used, it must reference
+	// a VolumeClaimSpecTemplate in the VolumeClaimSpecTemplates section.
+	// +optional
+	// +patchStrategy=replace
+	VolumeSource *corev1.VolumeSource `json:"volumeSource,omitempty" patchStrategy:"replace"`
 }
 
 // PrometheusAdapterComponent specifies the Prometheus Adapter configuration.
 type PrometheusAdapterComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // PrometheusNodeExporterComponent specifies the Prometheus Node Exporter configuration.
 type PrometheusNodeExporterComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // PrometheusOperatorComponent specifies the Prometheus Operator configuration
@@ -445,12 +842,19 @@ type PrometheusOperatorComponent struct {
 	// +optional
 	Enabled            *bool `json:"enabled,omitempty"`
 	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // PrometheusPushgatewayComponent specifies the Prometheus Pushgateway configuration.
 type PrometheusPushgatewayComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // CertManagerComponent specifies the core CertManagerComponent config.
@@ -460,19 +864,31 @@ type CertManagerComponent struct {
 	// +patchStrategy=replace
 	Certificate Certificate `json:"certificate,omitempty" patchStrategy:"replace"`
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // CoherenceOperatorComponent specifies the Coherence Operator configuration
 type CoherenceOperatorComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // ApplicationOperatorComponent specifies the Application Operator configuration
 type ApplicationOperatorComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // AuthProxyKubernetesSection specifies the Kubernetes resources that can be customized for AuthProxy.
@@ -485,13 +901,18 @@ type AuthProxyComponent struct {
 	// +optional
 	Enabled *bool `json:"enabled,omitempty"`
 	// +optional
-	Kubernetes *AuthProxyKubernetesSection `json:"kubernetes,omitempty"`
+	Kubernetes         *AuthProxyKubernetesSection `json:"kubernetes,omitempty"`
+	HelmValueOverrides `json:",inline"`
 }
 
 // OAMComponent specifies the OAM configuration
 type OAMComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // VerrazzanoComponent specifies the Verrazzano configuration
@@ -503,19 +924,28 @@ type VerrazzanoComponent struct {
 	// +optional
 	// +patchMergeKey=name
 	// +patchStrategy=merge,retainKeys
-	InstallArgs []InstallArgs `json:"installArgs,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
+	InstallArgs        []InstallArgs `json:"installArgs,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
+	HelmValueOverrides `json:",inline"`
 }
 
 // KialiComponent specifies the Kiali configuration
 type KialiComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // ConsoleComponent specifies the Console UI configuration
 type ConsoleComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // DNSComponent specifies the DNS configuration
@@ -528,7 +958,11 @@ type DNSComponent struct {
 	OCI *OCI `json:"oci,omitempty"`
 	// DNS type of external. For example, OLCNE uses this type.
 	// +optional
-	External *External `json:"external,omitempty"`
+	External           *External `json:"external,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // IngressNginxComponent specifies the ingress-nginx configuration
@@ -545,7 +979,11 @@ type IngressNginxComponent struct {
 	// +optional
 	Ports []corev1.ServicePort `json:"ports,omitempty"`
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // IstioIngressSection specifies the specific config options available for the Istio Ingress Gateways.
@@ -586,6 +1024,9 @@ type IstioComponent struct {
 	Ingress *IstioIngressSection `json:"ingress,omitempty"`
 	// +optional
 	Egress *IstioEgressSection `json:"egress,omitempty"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // IsInjectionEnabled is istio sidecar injection enabled check
@@ -600,6 +1041,9 @@ func (c *IstioComponent) IsInjectionEnabled() bool {
 type JaegerOperatorComponent struct {
 	// +optional
 	Enabled *bool `json:"enabled,omitempty"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // KeycloakComponent specifies the Keycloak configuration
@@ -612,8 +1056,137 @@ type KeycloakComponent struct {
 	// MySQL contains the MySQL component configuration needed for Keycloak
 	// +optional
 	MySQL MySQLComponent `json:"mysql,omitempty"`
+	// Realm is the configuration of the verrazzano-system realm, in addition to the users, groups and clients
+	// that Verrazzano creates.  Groups, role bindings and providers removed from it are deleted from Keycloak.
+	// +optional
+	Realm *KeycloakRealm `json:"realm,omitempty"`
+	// +optional
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
+}
+
+// KeycloakRealm specifies the configuration of the verrazzano-system realm.  The configuration is applied every time
+// the Keycloak component is reconciled.  Groups, role bindings, identity providers and user federation providers that
+// were created for the configuration are deleted from Keycloak once they are removed from it.
+type KeycloakRealm struct {
+	// Groups to create in the realm
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge,retainKeys
+	Groups []KeycloakGroup `json:"groups,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
+	// RoleBindings grant realm roles to groups.  Roles that don't exist are created.
+	// +optional
+	RoleBindings []KeycloakRoleBinding `json:"roleBindings,omitempty"`
+	// IdentityProviders are the upstream OpenID Connect identity providers that users can log in with
+	// +optional
+	// +patchMergeKey=alias
+	// +patchStrategy=merge,retainKeys
+	IdentityProviders []KeycloakIdentityProvider `json:"identityProviders,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"alias"`
+	// UserFederation are the LDAP directories that users are looked up in
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge,retainKeys
+	UserFederation []KeycloakUserFederation `json:"userFederation,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"name"`
+	// PasswordPolicy of the realm, in the Keycloak password policy syntax.  Default is "length(8) and notUsername".
+	// +optional
+	PasswordPolicy string `json:"passwordPolicy,omitempty"`
+	// LoginTheme of the realm.  Default is "oracle".
+	// +optional
+	LoginTheme string `json:"loginTheme,omitempty"`
+}
+
+// KeycloakGroup specifies a group of the verrazzano-system realm
+type KeycloakGroup struct {
+	// Name of the group
+	Name string `json:"name"`
+	// ParentGroup is the name of the parent group, either a group of the realm configuration or a group that
+	// Verrazzano creates, such as verrazzano-users.  The group is a top level group if there is no parent group.
+	// +optional
+	ParentGroup string `json:"parentGroup,omitempty"`
+}
+
+// KeycloakRoleBinding grants realm roles to a group
+type KeycloakRoleBinding struct {
+	// Group is the name of the group
+	Group string `json:"group"`
+	// Roles are the names of the realm roles that are granted to the group
+	Roles []string `json:"roles"`
+}
+
+// KeycloakIdentityProvider specifies an upstream OpenID Connect identity provider
+type KeycloakIdentityProvider struct {
+	// Alias that identifies the identity provider
+	Alias string `json:"alias"`
+	// DisplayName of the identity provider on the login page
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
 	// +optional
 	Enabled *bool `json:"enabled,omitempty"`
+	// AuthorizationURL is the authorization endpoint of the identity provider
+	AuthorizationURL string `json:"authorizationURL"`
+	// TokenURL is the token endpoint of the identity provider
+	TokenURL string `json:"tokenURL"`
+	// UserInfoURL is the user info endpoint of the identity provider
+	// +optional
+	UserInfoURL string `json:"userInfoURL,omitempty"`
+	// JWKSURL is the endpoint of the keys that the identity provider signs tokens with.  The signatures of the
+	// tokens are validated when it is specified.
+	// +optional
+	JWKSURL string `json:"jwksURL,omitempty"`
+	// LogoutURL is the end session endpoint of the identity provider
+	// +optional
+	LogoutURL string `json:"logoutURL,omitempty"`
+	// Issuer is the issuer of the tokens of the identity provider
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+	// ClientID is the ID of the client that Keycloak is registered as with the identity provider
+	ClientID string `json:"clientID"`
+	// ClientSecret is the name of the secret in the verrazzano-install namespace with the client secret in the
+	// clientSecret field
+	ClientSecret string `json:"clientSecret"`
+	// DefaultScope are the scopes that are requested.  Default is openid.
+	// +optional
+	DefaultScope string `json:"defaultScope,omitempty"`
+}
+
+// KeycloakUserFederation specifies an LDAP user federation provider
+type KeycloakUserFederation struct {
+	// Name of the user federation provider
+	Name string `json:"name"`
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Vendor of the LDAP directory, one of ad, rhds, tivoli, edirectory or other.  Default is other.
+	// +optional
+	Vendor string `json:"vendor,omitempty"`
+	// ConnectionURL of the LDAP directory, for example ldaps://ldap.example.com
+	ConnectionURL string `json:"connectionURL"`
+	// UsersDN is the DN of the LDAP tree that the users are in
+	UsersDN string `json:"usersDN"`
+	// BindDN is the DN of the LDAP user that Keycloak binds as.  Keycloak binds anonymously when it isn't specified.
+	// +optional
+	BindDN string `json:"bindDN,omitempty"`
+	// BindCredential is the name of the secret in the verrazzano-install namespace with the password of the bind DN
+	// in the password field
+	// +optional
+	BindCredential string `json:"bindCredential,omitempty"`
+	// UsernameLDAPAttribute is the LDAP attribute that is mapped to the Keycloak username.  Default is uid.
+	// +optional
+	UsernameLDAPAttribute string `json:"usernameLDAPAttribute,omitempty"`
+	// RDNLDAPAttribute is the LDAP attribute that is the RDN of the users.  Default is uid.
+	// +optional
+	RDNLDAPAttribute string `json:"rdnLDAPAttribute,omitempty"`
+	// UUIDLDAPAttribute is the LDAP attribute that uniquely identifies the users.  Default is entryUUID.
+	// +optional
+	UUIDLDAPAttribute string `json:"uuidLDAPAttribute,omitempty"`
+	// UserObjectClasses are the object classes of the LDAP users.  Default is inetOrgPerson and organizationalPerson.
+	// +optional
+	UserObjectClasses []string `json:"userObjectClasses,omitempty"`
+	// EditMode is READ_ONLY, WRITABLE or UNSYNCED.  Default is READ_ONLY.
+	// +optional
+	EditMode string `json:"editMode,omitempty"`
 }
 
 // MySQLComponent specifies the MySQL configuration
@@ -628,13 +1201,27 @@ type MySQLComponent struct {
 	// is used, it must reference a VolumeClaimSpecTemplate in the VolumeClaimSpecTemplates section.
 	// +optional
 	// +patchStrategy=replace
-	VolumeSource *corev1.VolumeSource `json:"volumeSource,omitempty" patchStrategy:"replace"`
+	VolumeSource       *corev1.VolumeSource `json:"volumeSource,omitempty" patchStrategy:"replace"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // RancherComponent specifies the Rancher configuration
 type RancherComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
+	// VolumeSource Defines the type of volume to be used for persistence of the Rancher custom logos, which are only persisted when it is set; at present only EmptyDirVolumeSource or
+	// PersistentVolumeClaimVolumeSource are supported. If PersistentVolumeClaimVolumeSource is used, it must reference
+	// a VolumeClaimSpecTemplate in the VolumeClaimSpecTemplates section.
+	// +optional
+	// +patchStrategy=replace
+	VolumeSource *corev1.VolumeSource `json:"volumeSource,omitempty" patchStrategy:"replace"`
 }
 
 // FluentdComponent specifies the Fluentd DaemonSet configuration
@@ -652,13 +1239,21 @@ type FluentdComponent struct {
 
 	// Configuration for integration with OCI (Oracle Cloud Infrastructure) Logging Service
 	// +optional
-	OCI *OciLoggingConfiguration `json:"oci,omitempty"`
+	OCI                *OciLoggingConfiguration `json:"oci,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // WebLogicOperatorComponent specifies the WebLogic Operator configuration
 type WebLogicOperatorComponent struct {
 	// +optional
-	Enabled *bool `json:"enabled,omitempty"`
+	Enabled            *bool `json:"enabled,omitempty"`
+	HelmValueOverrides `json:",inline"`
+	// Kubernetes specifies the scheduling and resource settings of the pods
+	// +optional
+	Kubernetes *CommonKubernetesSpec `json:"kubernetes,omitempty"`
 }
 
 // InstallArgs identifies a name/value or name/value list needed for install.
@@ -48,6 +48,16 @@ func SetComponentValidator(v ComponentValidator) {
 	componentValidator = v
 }
 
+// ProfileMerger merges the requested profiles, including the custom profiles they are layered on, into an effective
+// CR and returns an error if they can not be resolved or merged
+type ProfileMerger func(requestedProfile ProfileType) error
+
+var profileMerger ProfileMerger = nil
+
+func SetProfileMerger(m ProfileMerger) {
+	profileMerger = m
+}
+
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (v *Verrazzano) ValidateCreate() error {
 	log := zap.S().With("source", "webhook", "operation", "create", "resource", fmt.Sprintf("%s:%s", v.Namespace, v.Name))
@@ -77,7 +87,15 @@ func (v *Verrazzano) ValidateCreate() error {
 		return err
 	}
 
-	if err := ValidateProfile(v.Spec.Profile); err != nil {
+	if err := ValidateProfile(client, v.Spec.Profile); err != nil {
+		return err
+	}
+
+	if err := ValidateDriftPolicy(v.Spec.DriftPolicy); err != nil {
+		return err
+	}
+
+	if err := ValidateComponentHelmValueOverrides(&v.Spec.Components); err != nil {
 		return err
 	}
 
@@ -123,6 +141,14 @@ func (v *Verrazzano) ValidateUpdate(old runtime.Object) error {
 		return fmt.Errorf("Profile change is not allowed oldResource %s to %s", oldResource.Spec.Profile, v.Spec.Profile)
 	}
 
+	if err := ValidateDriftPolicy(v.Spec.DriftPolicy); err != nil {
+		return err
+	}
+
+	if err := ValidateComponentHelmValueOverrides(&v.Spec.Components); err != nil {
+		return err
+	}
+
 	// Check to see if the update is an upgrade request, and if it is valid and allowable
 	err := ValidateUpgradeRequest(oldResource, v)
 	if err != nil {
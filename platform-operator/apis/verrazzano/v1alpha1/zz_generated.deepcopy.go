@@ -12,7 +12,9 @@ import (
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -38,6 +40,12 @@ func (in *ApplicationOperatorComponent) DeepCopyInto(out *ApplicationOperatorCom
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationOperatorComponent.
@@ -63,6 +71,7 @@ func (in *AuthProxyComponent) DeepCopyInto(out *AuthProxyComponent) {
 		*out = new(AuthProxyKubernetesSection)
 		(*in).DeepCopyInto(*out)
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthProxyComponent.
@@ -91,6 +100,66 @@ func (in *AuthProxyKubernetesSection) DeepCopy() *AuthProxyKubernetesSection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupComponentStatus) DeepCopyInto(out *BackupComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupComponentStatus.
+func (in *BackupComponentStatus) DeepCopy() *BackupComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRetention) DeepCopyInto(out *BackupRetention) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRetention.
+func (in *BackupRetention) DeepCopy() *BackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupStorageLocation) DeepCopyInto(out *BackupStorageLocation) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3StorageLocation)
+		**out = **in
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PersistentVolumeClaimStorageLocation)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorageLocation.
+func (in *BackupStorageLocation) DeepCopy() *BackupStorageLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupStorageLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CA) DeepCopyInto(out *CA) {
 	*out = *in
@@ -115,6 +184,12 @@ func (in *CertManagerComponent) DeepCopyInto(out *CertManagerComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerComponent.
@@ -144,6 +219,36 @@ func (in *Certificate) DeepCopy() *Certificate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARotationStatus) DeepCopyInto(out *CARotationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARotationStatus.
+func (in *CARotationStatus) DeepCopy() *CARotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CARotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CoherenceOperatorComponent) DeepCopyInto(out *CoherenceOperatorComponent) {
 	*out = *in
@@ -152,6 +257,12 @@ func (in *CoherenceOperatorComponent) DeepCopyInto(out *CoherenceOperatorCompone
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoherenceOperatorComponent.
@@ -172,6 +283,30 @@ func (in *CommonKubernetesSpec) DeepCopyInto(out *CommonKubernetesSpec) {
 		*out = new(v1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonKubernetesSpec.
@@ -184,6 +319,61 @@ func (in *CommonKubernetesSpec) DeepCopy() *CommonKubernetesSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentHealth) DeepCopyInto(out *ComponentHealth) {
+	*out = *in
+	if in.UnreadyWorkloads != nil {
+		in, out := &in.UnreadyWorkloads, &out.UnreadyWorkloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentHealth.
+func (in *ComponentHealth) DeepCopy() *ComponentHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentPlan) DeepCopyInto(out *ComponentPlan) {
+	*out = *in
+	if in.ValuesDiff != nil {
+		in, out := &in.ValuesDiff, &out.ValuesDiff
+		*out = make([]HelmValueChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPlan.
+func (in *ComponentPlan) DeepCopy() *ComponentPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentRelease) DeepCopyInto(out *ComponentRelease) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentRelease.
+func (in *ComponentRelease) DeepCopy() *ComponentRelease {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentRelease)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 	*out = *in
@@ -332,6 +522,11 @@ func (in *ComponentStatusDetails) DeepCopyInto(out *ComponentStatusDetails) {
 		*out = make([]Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.PreviousRelease != nil {
+		in, out := &in.PreviousRelease, &out.PreviousRelease
+		*out = new(ComponentRelease)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatusDetails.
@@ -373,6 +568,63 @@ func (in ComponentStatusMap) DeepCopy() ComponentStatusMap {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTimeout) DeepCopyInto(out *ComponentTimeout) {
+	*out = *in
+	if in.Install != nil {
+		in, out := &in.Install, &out.Install
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTimeout.
+func (in *ComponentTimeout) DeepCopy() *ComponentTimeout {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTimeout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTimeouts) DeepCopyInto(out *ComponentTimeouts) {
+	*out = *in
+	if in.Install != nil {
+		in, out := &in.Install, &out.Install
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentTimeout, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTimeouts.
+func (in *ComponentTimeouts) DeepCopy() *ComponentTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -396,6 +648,12 @@ func (in *ConsoleComponent) DeepCopyInto(out *ConsoleComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleComponent.
@@ -426,6 +684,12 @@ func (in *DNSComponent) DeepCopyInto(out *DNSComponent) {
 		*out = new(External)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSComponent.
@@ -467,6 +731,11 @@ func (in *ElasticsearchComponent) DeepCopyInto(out *ElasticsearchComponent) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchComponent.
@@ -479,6 +748,46 @@ func (in *ElasticsearchComponent) DeepCopy() *ElasticsearchComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionChart) DeepCopyInto(out *ExtensionChart) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionChart.
+func (in *ExtensionChart) DeepCopy() *ExtensionChart {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionChart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionReadinessChecks) DeepCopyInto(out *ExtensionReadinessChecks) {
+	*out = *in
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StatefulSets != nil {
+		in, out := &in.StatefulSets, &out.StatefulSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionReadinessChecks.
+func (in *ExtensionReadinessChecks) DeepCopy() *ExtensionReadinessChecks {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionReadinessChecks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *External) DeepCopyInto(out *External) {
 	*out = *in
@@ -514,6 +823,12 @@ func (in *FluentdComponent) DeepCopyInto(out *FluentdComponent) {
 		*out = new(OciLoggingConfiguration)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentdComponent.
@@ -534,6 +849,16 @@ func (in *GrafanaComponent) DeepCopyInto(out *GrafanaComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeSource != nil {
+		in, out := &in.VolumeSource, &out.VolumeSource
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaComponent.
@@ -546,6 +871,43 @@ func (in *GrafanaComponent) DeepCopy() *GrafanaComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthStatus) DeepCopyInto(out *HealthStatus) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthStatus.
+func (in *HealthStatus) DeepCopy() *HealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmValueChange) DeepCopyInto(out *HelmValueChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmValueChange.
+func (in *HelmValueChange) DeepCopy() *HelmValueChange {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmValueChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HelmValueOverrides) DeepCopyInto(out *HelmValueOverrides) {
 	*out = *in
@@ -595,6 +957,12 @@ func (in *IngressNginxComponent) DeepCopyInto(out *IngressNginxComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressNginxComponent.
@@ -712,6 +1080,11 @@ func (in *IstioComponent) DeepCopyInto(out *IstioComponent) {
 		*out = new(IstioEgressSection)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioComponent.
@@ -795,6 +1168,11 @@ func (in *JaegerOperatorComponent) DeepCopyInto(out *JaegerOperatorComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JaegerOperatorComponent.
@@ -818,11 +1196,22 @@ func (in *KeycloakComponent) DeepCopyInto(out *KeycloakComponent) {
 		}
 	}
 	in.MySQL.DeepCopyInto(&out.MySQL)
+	if in.Realm != nil {
+		in, out := &in.Realm, &out.Realm
+		*out = new(KeycloakRealm)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakComponent.
@@ -835,6 +1224,127 @@ func (in *KeycloakComponent) DeepCopy() *KeycloakComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakGroup) DeepCopyInto(out *KeycloakGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakGroup.
+func (in *KeycloakGroup) DeepCopy() *KeycloakGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakIdentityProvider) DeepCopyInto(out *KeycloakIdentityProvider) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakIdentityProvider.
+func (in *KeycloakIdentityProvider) DeepCopy() *KeycloakIdentityProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakIdentityProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRealm) DeepCopyInto(out *KeycloakRealm) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]KeycloakGroup, len(*in))
+		copy(*out, *in)
+	}
+	if in.RoleBindings != nil {
+		in, out := &in.RoleBindings, &out.RoleBindings
+		*out = make([]KeycloakRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IdentityProviders != nil {
+		in, out := &in.IdentityProviders, &out.IdentityProviders
+		*out = make([]KeycloakIdentityProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UserFederation != nil {
+		in, out := &in.UserFederation, &out.UserFederation
+		*out = make([]KeycloakUserFederation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRealm.
+func (in *KeycloakRealm) DeepCopy() *KeycloakRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakRoleBinding) DeepCopyInto(out *KeycloakRoleBinding) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakRoleBinding.
+func (in *KeycloakRoleBinding) DeepCopy() *KeycloakRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakUserFederation) DeepCopyInto(out *KeycloakUserFederation) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UserObjectClasses != nil {
+		in, out := &in.UserObjectClasses, &out.UserObjectClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakUserFederation.
+func (in *KeycloakUserFederation) DeepCopy() *KeycloakUserFederation {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakUserFederation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KialiComponent) DeepCopyInto(out *KialiComponent) {
 	*out = *in
@@ -843,6 +1353,12 @@ func (in *KialiComponent) DeepCopyInto(out *KialiComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KialiComponent.
@@ -863,6 +1379,11 @@ func (in *KibanaComponent) DeepCopyInto(out *KibanaComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaComponent.
@@ -883,6 +1404,12 @@ func (in *KubeStateMetricsComponent) DeepCopyInto(out *KubeStateMetricsComponent
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeStateMetricsComponent.
@@ -910,6 +1437,12 @@ func (in *MySQLComponent) DeepCopyInto(out *MySQLComponent) {
 		*out = new(v1.VolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLComponent.
@@ -930,6 +1463,12 @@ func (in *OAMComponent) DeepCopyInto(out *OAMComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAMComponent.
@@ -990,6 +1529,11 @@ func (in *OpenSearchNode) DeepCopyInto(out *OpenSearchNode) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VolumeSource != nil {
+		in, out := &in.VolumeSource, &out.VolumeSource
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchNode.
@@ -1042,6 +1586,103 @@ func (in *Overrides) DeepCopy() *Overrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimStorageLocation) DeepCopyInto(out *PersistentVolumeClaimStorageLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimStorageLocation.
+func (in *PersistentVolumeClaimStorageLocation) DeepCopy() *PersistentVolumeClaimStorageLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimStorageLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Plan) DeepCopyInto(out *Plan) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plan.
+func (in *Plan) DeepCopy() *Plan {
+	if in == nil {
+		return nil
+	}
+	out := new(Plan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
+func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightCheck) DeepCopyInto(out *PreflightCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightCheck.
+func (in *PreflightCheck) DeepCopy() *PreflightCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightStatus) DeepCopyInto(out *PreflightStatus) {
+	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]PreflightCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightStatus.
+func (in *PreflightStatus) DeepCopy() *PreflightStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusAdapterComponent) DeepCopyInto(out *PrometheusAdapterComponent) {
 	*out = *in
@@ -1050,6 +1691,12 @@ func (in *PrometheusAdapterComponent) DeepCopyInto(out *PrometheusAdapterCompone
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusAdapterComponent.
@@ -1070,6 +1717,17 @@ func (in *PrometheusComponent) DeepCopyInto(out *PrometheusComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeSource != nil {
+		in, out := &in.VolumeSource, &out.VolumeSource
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusComponent.
@@ -1090,6 +1748,12 @@ func (in *PrometheusNodeExporterComponent) DeepCopyInto(out *PrometheusNodeExpor
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusNodeExporterComponent.
@@ -1111,6 +1775,11 @@ func (in *PrometheusOperatorComponent) DeepCopyInto(out *PrometheusOperatorCompo
 		**out = **in
 	}
 	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusOperatorComponent.
@@ -1131,6 +1800,12 @@ func (in *PrometheusPushgatewayComponent) DeepCopyInto(out *PrometheusPushgatewa
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusPushgatewayComponent.
@@ -1151,6 +1826,17 @@ func (in *RancherComponent) DeepCopyInto(out *RancherComponent) {
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeSource != nil {
+		in, out := &in.VolumeSource, &out.VolumeSource
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RancherComponent.
@@ -1163,6 +1849,21 @@ func (in *RancherComponent) DeepCopy() *RancherComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3StorageLocation) DeepCopyInto(out *S3StorageLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3StorageLocation.
+func (in *S3StorageLocation) DeepCopy() *S3StorageLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(S3StorageLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
 	*out = *in
@@ -1189,15 +1890,62 @@ func (in *SecuritySpec) DeepCopy() *SecuritySpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Verrazzano) DeepCopyInto(out *Verrazzano) {
+func (in *UpgradePolicy) DeepCopyInto(out *UpgradePolicy) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Verrazzano.
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]UpgradeStage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePolicy.
+func (in *UpgradePolicy) DeepCopy() *UpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStage) DeepCopyInto(out *UpgradeStage) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoakTime != nil {
+		in, out := &in.SoakTime, &out.SoakTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStage.
+func (in *UpgradeStage) DeepCopy() *UpgradeStage {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Verrazzano) DeepCopyInto(out *Verrazzano) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Verrazzano.
 func (in *Verrazzano) DeepCopy() *Verrazzano {
 	if in == nil {
 		return nil
@@ -1215,6 +1963,123 @@ func (in *Verrazzano) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoBackup) DeepCopyInto(out *VerrazzanoBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoBackup.
+func (in *VerrazzanoBackup) DeepCopy() *VerrazzanoBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VerrazzanoBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoBackupList) DeepCopyInto(out *VerrazzanoBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VerrazzanoBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoBackupList.
+func (in *VerrazzanoBackupList) DeepCopy() *VerrazzanoBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VerrazzanoBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoBackupSpec) DeepCopyInto(out *VerrazzanoBackupSpec) {
+	*out = *in
+	in.StorageLocation.DeepCopyInto(&out.StorageLocation)
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(BackupRetention)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoBackupSpec.
+func (in *VerrazzanoBackupSpec) DeepCopy() *VerrazzanoBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoBackupStatus) DeepCopyInto(out *VerrazzanoBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]BackupComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoBackupStatus.
+func (in *VerrazzanoBackupStatus) DeepCopy() *VerrazzanoBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerrazzanoComponent) DeepCopyInto(out *VerrazzanoComponent) {
 	*out = *in
@@ -1230,6 +2095,7 @@ func (in *VerrazzanoComponent) DeepCopyInto(out *VerrazzanoComponent) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoComponent.
@@ -1242,6 +2108,108 @@ func (in *VerrazzanoComponent) DeepCopy() *VerrazzanoComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoExtension) DeepCopyInto(out *VerrazzanoExtension) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoExtension.
+func (in *VerrazzanoExtension) DeepCopy() *VerrazzanoExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VerrazzanoExtension) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoExtensionList) DeepCopyInto(out *VerrazzanoExtensionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VerrazzanoExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoExtensionList.
+func (in *VerrazzanoExtensionList) DeepCopy() *VerrazzanoExtensionList {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoExtensionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VerrazzanoExtensionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoExtensionSpec) DeepCopyInto(out *VerrazzanoExtensionSpec) {
+	*out = *in
+	out.Chart = in.Chart
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ReadinessChecks.DeepCopyInto(&out.ReadinessChecks)
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoExtensionSpec.
+func (in *VerrazzanoExtensionSpec) DeepCopy() *VerrazzanoExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoExtensionStatus) DeepCopyInto(out *VerrazzanoExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoExtensionStatus.
+func (in *VerrazzanoExtensionStatus) DeepCopy() *VerrazzanoExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerrazzanoList) DeepCopyInto(out *VerrazzanoList) {
 	*out = *in
@@ -1274,6 +2242,212 @@ func (in *VerrazzanoList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoPlan) DeepCopyInto(out *VerrazzanoPlan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoPlan.
+func (in *VerrazzanoPlan) DeepCopy() *VerrazzanoPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VerrazzanoPlan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoPlanList) DeepCopyInto(out *VerrazzanoPlanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VerrazzanoPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoPlanList.
+func (in *VerrazzanoPlanList) DeepCopy() *VerrazzanoPlanList {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoPlanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VerrazzanoPlanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoPlanSpec) DeepCopyInto(out *VerrazzanoPlanSpec) {
+	*out = *in
+	if in.Verrazzano != nil {
+		in, out := &in.Verrazzano, &out.Verrazzano
+		*out = new(VerrazzanoSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoPlanSpec.
+func (in *VerrazzanoPlanSpec) DeepCopy() *VerrazzanoPlanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoPlanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoPlanStatus) DeepCopyInto(out *VerrazzanoPlanStatus) {
+	*out = *in
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(Plan)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoPlanStatus.
+func (in *VerrazzanoPlanStatus) DeepCopy() *VerrazzanoPlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoPlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoRestore) DeepCopyInto(out *VerrazzanoRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoRestore.
+func (in *VerrazzanoRestore) DeepCopy() *VerrazzanoRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VerrazzanoRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoRestoreList) DeepCopyInto(out *VerrazzanoRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VerrazzanoRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoRestoreList.
+func (in *VerrazzanoRestoreList) DeepCopy() *VerrazzanoRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VerrazzanoRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoRestoreSpec) DeepCopyInto(out *VerrazzanoRestoreSpec) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoRestoreSpec.
+func (in *VerrazzanoRestoreSpec) DeepCopy() *VerrazzanoRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoRestoreStatus) DeepCopyInto(out *VerrazzanoRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]BackupComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoRestoreStatus.
+func (in *VerrazzanoRestoreStatus) DeepCopy() *VerrazzanoRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerrazzanoSpec) DeepCopyInto(out *VerrazzanoSpec) {
 	*out = *in
@@ -1291,6 +2465,21 @@ func (in *VerrazzanoSpec) DeepCopyInto(out *VerrazzanoSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(UpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateExpiryThreshold != nil {
+		in, out := &in.CertificateExpiryThreshold, &out.CertificateExpiryThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ComponentTimeouts != nil {
+		in, out := &in.ComponentTimeouts, &out.ComponentTimeouts
+		*out = new(ComponentTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoSpec.
@@ -1331,6 +2520,36 @@ func (in *VerrazzanoStatus) DeepCopyInto(out *VerrazzanoStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(Plan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProfileChain != nil {
+		in, out := &in.ProfileChain, &out.ProfileChain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(CARotationStatus)
+		**out = **in
+	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(PreflightStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(HealthStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoStatus.
@@ -1388,6 +2607,12 @@ func (in *WebLogicOperatorComponent) DeepCopyInto(out *WebLogicOperatorComponent
 		*out = new(bool)
 		**out = **in
 	}
+	in.HelmValueOverrides.DeepCopyInto(&out.HelmValueOverrides)
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(CommonKubernetesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebLogicOperatorComponent.
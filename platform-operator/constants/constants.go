@@ -131,6 +131,28 @@ const UpgradeRetryVersion = "verrazzano.io/upgrade-retry-version"
 // ObservedUpgradeRetryVersion is the previous restart version annotation field
 const ObservedUpgradeRetryVersion = "verrazzano.io/observed-upgrade-retry-version"
 
+// InstallRetryVersion is the annotation that retries an install that has failed, see ComponentTimeouts
+const InstallRetryVersion = "verrazzano.io/install-retry-version"
+
+// ObservedInstallRetryVersion is the previous install retry version annotation field
+const ObservedInstallRetryVersion = "verrazzano.io/observed-install-retry-version"
+
+// UpgradeApprovedStage is the annotation that approves the upgrade of a stage, and all of the stages before it, with the
+// value <version>/<stage name>, see UpgradeStage.RequireApproval
+const UpgradeApprovedStage = "verrazzano.io/upgrade-approved-stage"
+
+// PlanAnnotation is the annotation that requests the plan of the changes that would be made to the components,
+// instead of making the changes
+const PlanAnnotation = "verrazzano.io/plan"
+
+// RenewCertificatesAnnotation is the annotation that requests the renewal of the certificates of the Verrazzano
+// installation, either "all" or a comma separated list of namespace/name of cert-manager Certificates
+const RenewCertificatesAnnotation = "verrazzano.io/renew-certificates"
+
+// CAPropagationTimeoutAnnotation is the annotation that sets how long the rotation of the CA of the Verrazzano
+// ClusterIssuer waits for the managed clusters to get the new CA bundle, as a duration such as "24h"
+const CAPropagationTimeoutAnnotation = "verrazzano.io/ca-propagation-timeout"
+
 // NGINXControllerServiceName is the nginx ingress controller name
 const NGINXControllerServiceName = "ingress-controller-ingress-nginx-controller"
 
@@ -143,6 +165,12 @@ const UpgradeOperation = "upgrade"
 // InitializeOperation is the initialize string
 const InitializeOperation = "initialize"
 
+// UninstallOperation is the uninstall string
+const UninstallOperation = "uninstall"
+
+// PlanOperation is the plan string
+const PlanOperation = "plan"
+
 // ReconcileLoopRequeueInterval is the interval before reconcile gets called again.
 const ReconcileLoopRequeueInterval = 3 * time.Minute
 
@@ -167,8 +195,19 @@ const VerrazzanoIngressSecret = "verrazzano-tls" //nolint:gosec //#gosec G101
 // VerrazzanoLocalCABundleSecret is a secret containing the admin ca bundle
 const VerrazzanoLocalCABundleSecret = "verrazzano-local-ca-bundle" //nolint:gosec //#gosec G101
 
+// VerrazzanoCARotationBundleSecret is a secret in the cert-manager namespace containing the CA bundle of the previous
+// and the new CA of the Verrazzano ClusterIssuer, it only exists while the CA is being rotated
+const VerrazzanoCARotationBundleSecret = "verrazzano-ca-rotation-bundle" //nolint:gosec //#gosec G101
+
 //KubernetesAppLabel is a label key for kubernetes apps
 const KubernetesAppLabel = "app.kubernetes.io/component"
 
 //JaegerCollectorService is a label value for Jaeger collector
 const JaegerCollectorService = "service-collector"
+
+// VerrazzanoProfileLabel is the label, with the value "true", of a ConfigMap in the install namespace that has a
+// custom installation profile
+const VerrazzanoProfileLabel = "install.verrazzano.io/profile"
+
+// VerrazzanoProfileKey is the key of the profile YAML in a custom installation profile ConfigMap
+const VerrazzanoProfileKey = "profile.yaml"
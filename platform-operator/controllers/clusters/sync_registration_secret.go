@@ -11,6 +11,7 @@ import (
 	"github.com/verrazzano/verrazzano/pkg/mcconstants"
 	clusterapi "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/certificate"
 	corev1 "k8s.io/api/core/v1"
 	k8net "k8s.io/api/networking/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -195,7 +196,7 @@ func (r *VerrazzanoManagedClusterReconciler) getSecret(namespace string, secretN
 	return secret, nil
 }
 
-// Get the CA bundle used by verrazzano ingress and the optional rancher-ca-additional secret
+// Get the CA bundle used by verrazzano ingress, the optional rancher-ca-additional secret and the CA rotation bundle
 func (r *VerrazzanoManagedClusterReconciler) getAdminCaBundle() ([]byte, error) {
 	var caBundle []byte
 	secret, err := r.getSecret(constants.VerrazzanoSystemNamespace, "verrazzano-tls", true)
@@ -216,7 +217,13 @@ func (r *VerrazzanoManagedClusterReconciler) getAdminCaBundle() ([]byte, error)
 		caBundle = append(caBundle, optSecret.Data[constants.AdditionalTLSCAKey]...)
 	}
 
-	return caBundle, nil
+	// Append the previous and the new CA while the CA of the Verrazzano ClusterIssuer is being rotated, so that the
+	// managed clusters trust the certificates issued by either CA
+	rotationBundle, err := certificate.GetCARotationBundle(r.Client)
+	if err != nil {
+		return nil, err
+	}
+	return certificate.AppendCABundle(caBundle, rotationBundle), nil
 }
 
 // Get the keycloak URL
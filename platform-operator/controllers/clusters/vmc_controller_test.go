@@ -1427,6 +1427,11 @@ func expectSyncRegistration(t *testing.T, mock *mocks.MockClient, name string, e
 		Get(gomock.Any(), types.NamespacedName{Namespace: constants.RancherSystemNamespace, Name: constants.AdditionalTLS}, gomock.Not(gomock.Nil())).
 		Return(errors.NewNotFound(schema.GroupResource{Group: constants.RancherSystemNamespace, Resource: "Secret"}, constants.AdditionalTLS))
 
+	// Expect a call to get the CA rotation bundle secret, return the secret as not found
+	mock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: constants.CertManagerNamespace, Name: vpoconstants.VerrazzanoCARotationBundleSecret}, gomock.Not(gomock.Nil())).
+		Return(errors.NewNotFound(schema.GroupResource{Group: "", Resource: "Secret"}, vpoconstants.VerrazzanoCARotationBundleSecret))
+
 	// Expect a call to get the keycloak ingress and return the ingress.
 	mock.EXPECT().
 		Get(gomock.Any(), types.NamespacedName{Namespace: "keycloak", Name: "keycloak"}, gomock.Not(gomock.Nil())).
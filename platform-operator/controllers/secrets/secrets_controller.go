@@ -15,6 +15,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/certificate"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -26,7 +27,8 @@ import (
 // VerrazzanoSecretsReconciler reconciles secrets.
 // Currently the only secret reconciled is the verrazzano-tls secret. The controller
 // ensures that a copy of the ca.crt secret (admin CA bundle) is copied to a secret
-// in the verrazzano-mc namespace, so that managed clusters can fetch it.
+// in the verrazzano-mc namespace, so that managed clusters can fetch it.  While the CA
+// is being rotated the CA rotation bundle is added to the admin CA bundle.
 type VerrazzanoSecretsReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
@@ -44,6 +46,13 @@ func (r *VerrazzanoSecretsReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// We only care about the CA secret for the cluster - this can come from the verrazzano ingress
 	// tls secret (verrazzano-tls in verrazzano-system NS), OR from the tls-additional-ca in the
 	// cattle-system NS (used in the Let's Encrypt staging cert case)
+	// A change to the CA rotation bundle is reconciled as a change to the source of the CA secret
+	if req.NamespacedName == certificate.CARotationBundleSecretName {
+		req.NamespacedName = types.NamespacedName{Namespace: constants.VerrazzanoSystemNamespace, Name: constants.VerrazzanoIngressSecret}
+		if r.additionalTLSSecretExists() {
+			req.NamespacedName = types.NamespacedName{Namespace: vzconst.RancherSystemNamespace, Name: vzconst.AdditionalTLS}
+		}
+	}
 	isVzIngressSecret := isVerrazzanoIngressSecretName(req.NamespacedName)
 	isAddnlTLSSecret := isAdditionalTLSSecretName(req.NamespacedName)
 	if !isVzIngressSecret && !isAddnlTLSSecret {
@@ -109,12 +118,19 @@ func (r *VerrazzanoSecretsReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		mcCASecret.Namespace = constants.VerrazzanoMultiClusterNamespace
 	}
 
+	// While the CA of the Verrazzano ClusterIssuer is being rotated, the bundle includes the previous and the new CA
+	rotationBundle, err := certificate.GetCARotationBundle(r.Client)
+	if err != nil {
+		r.log.Errorf("Failed to fetch secret %s: %v", certificate.CARotationBundleSecretName, err)
+		return newRequeueWithDelay(), nil
+	}
+
 	result, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, &mcCASecret, func() error {
 		if mcCASecret.Data == nil {
 			mcCASecret.Data = make(map[string][]byte)
 		}
 		zap.S().Debugf("Updating MC CA secret with data from %s key of %s/%s secret ", caKey, caSecret.Namespace, caSecret.Name)
-		mcCASecret.Data["ca-bundle"] = caSecret.Data[caKey]
+		mcCASecret.Data["ca-bundle"] = certificate.AppendCABundle(caSecret.Data[caKey], rotationBundle)
 		return nil
 	})
 
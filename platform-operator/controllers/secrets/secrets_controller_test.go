@@ -5,12 +5,14 @@ package secrets
 
 import (
 	"context"
+	"encoding/pem"
 	"fmt"
 	"testing"
 
 	constants2 "github.com/verrazzano/verrazzano/pkg/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/golang/mock/gomock"
@@ -22,12 +24,14 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var mcNamespace = types.NamespacedName{Name: constants.VerrazzanoMultiClusterNamespace}
 var vzTLSSecret = types.NamespacedName{Name: constants.VerrazzanoIngressSecret, Namespace: constants.VerrazzanoSystemNamespace}
 var additionalTLSSecret = types.NamespacedName{Name: constants2.AdditionalTLS, Namespace: constants2.RancherSystemNamespace}
 var vzLocalCaBundleSecret = types.NamespacedName{Name: "verrazzano-local-ca-bundle", Namespace: constants.VerrazzanoMultiClusterNamespace}
+var caRotationBundleSecret = types.NamespacedName{Name: constants.VerrazzanoCARotationBundleSecret, Namespace: constants2.CertManagerNamespace}
 var unwatchedSecret = types.NamespacedName{Name: "any-secret", Namespace: "any-namespace"}
 
 const addnlTLSData = "YWRkaXRpb25hbCB0bHMgc2VjcmV0" // "additional tls secret"
@@ -99,6 +103,41 @@ func TestCreateLocalCABundle(t *testing.T) {
 	}
 }
 
+// TestCARotationBundle tests the Reconcile method for the following use case
+// GIVEN a request to reconcile the CA rotation bundle secret
+// WHEN the CA of the Verrazzano ClusterIssuer is being rotated
+// THEN the local-ca-bundle secret has the CA of the verrazzano-tls secret and the certificates of the CA rotation bundle
+func TestCARotationBundle(t *testing.T) {
+	asserts := assert.New(t)
+	oldCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("old-ca")})
+	newCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("new-ca")})
+	c := fake.NewClientBuilder().WithScheme(newScheme()).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: constants.VerrazzanoMultiClusterNamespace}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: vzTLSSecret.Name, Namespace: vzTLSSecret.Namespace},
+			Data:       map[string][]byte{"ca.crt": newCA},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: caRotationBundleSecret.Name, Namespace: caRotationBundleSecret.Namespace},
+			Data:       map[string][]byte{"ca-bundle": append(append([]byte{}, oldCA...), newCA...)},
+		},
+	).Build()
+
+	reconciler := newSecretsReconciler(c)
+	_, err := reconciler.Reconcile(context.TODO(), newRequest(caRotationBundleSecret.Namespace, caRotationBundleSecret.Name))
+	asserts.NoError(err)
+	secret := &corev1.Secret{}
+	asserts.NoError(c.Get(context.TODO(), vzLocalCaBundleSecret, secret))
+	asserts.Equal(append(append([]byte{}, newCA...), oldCA...), secret.Data["ca-bundle"])
+
+	// Once the rotation is complete the bundle only has the CA of the verrazzano-tls secret
+	asserts.NoError(c.Delete(context.TODO(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: caRotationBundleSecret.Name, Namespace: caRotationBundleSecret.Namespace}}))
+	_, err = reconciler.Reconcile(context.TODO(), newRequest(caRotationBundleSecret.Namespace, caRotationBundleSecret.Name))
+	asserts.NoError(err)
+	asserts.NoError(c.Get(context.TODO(), vzLocalCaBundleSecret, secret))
+	asserts.Equal(newCA, secret.Data["ca-bundle"])
+}
+
 // TestIgnoresOtherSecrets tests the Reconcile method for the following use case
 // GIVEN a request to reconcile a secret other than verrazzano TLS secret or additional TLS secret
 // WHEN any conditions
@@ -236,6 +275,12 @@ func expectGetCalls(t *testing.T, mock *mocks.MockClient, secretNS string, secre
 			return nil
 		}).MinTimes(1)
 
+	// Expect a call to get the CA rotation bundle secret, return the secret as not found
+	mock.EXPECT().
+		Get(gomock.Any(), caRotationBundleSecret, gomock.Not(gomock.Nil())).
+		Return(errors.NewNotFound(schema.GroupResource{Group: "", Resource: "Secret"}, caRotationBundleSecret.Name)).
+		MinTimes(1)
+
 	// Expect a call to get the local ca bundle secret
 	mock.EXPECT().
 		Get(gomock.Any(), vzLocalCaBundleSecret, gomock.Not(gomock.Nil())).
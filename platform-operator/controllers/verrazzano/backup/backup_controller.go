@@ -0,0 +1,442 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	vzctrl "github.com/verrazzano/verrazzano/pkg/controller"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzstring "github.com/verrazzano/verrazzano/pkg/string"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/transform"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// backupFinalizerName is the finalizer that keeps a VerrazzanoBackup until its data is deleted from the storage
+	// location
+	backupFinalizerName = "backup.install.verrazzano.io"
+
+	// scheduleLabel is the label of the backups created by a schedule, with the name of the schedule
+	scheduleLabel = "verrazzano.io/backup-schedule"
+)
+
+// VerrazzanoBackupReconciler backs up the state of the Verrazzano components, one component at a time, with a job
+// for each component.  A backup with a schedule creates a backup at each scheduled time, and deletes the backups that
+// are no longer retained.
+type VerrazzanoBackupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// SetupWithManager creates a new controller and adds it to the manager
+func (r *VerrazzanoBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vzapi.VerrazzanoBackup{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
+
+// Reconcile runs the jobs of the components of a backup in order, creates the backups of a schedule, and deletes the
+// data of a deleted backup
+func (r *VerrazzanoBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	backup := &vzapi.VerrazzanoBackup{}
+	if err := r.Get(ctx, req.NamespacedName, backup); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		zap.S().Errorf("Failed to fetch VerrazzanoBackup %s/%s: %v", req.Namespace, req.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+
+	// Get the resource logger needed to log message using 'progress' and 'once' methods
+	log, err := vzlog.EnsureResourceLogger(&vzlog.ResourceConfig{
+		Name:           backup.Name,
+		Namespace:      backup.Namespace,
+		ID:             string(backup.UID),
+		Generation:     backup.Generation,
+		ControllerName: "verrazzanobackup",
+	})
+	if err != nil {
+		zap.S().Errorf("Failed to create resource logger for VerrazzanoBackup controller: %v", err)
+		return newRequeueWithDelay(), nil
+	}
+
+	if !backup.DeletionTimestamp.IsZero() {
+		return r.procDeleteBackup(ctx, log, backup)
+	}
+	if len(backup.Spec.Schedule) > 0 {
+		return r.reconcileSchedule(ctx, log, backup)
+	}
+	return r.reconcileBackup(ctx, log, backup)
+}
+
+// reconcileBackup starts the backup once Verrazzano is ready, then runs the job of the next component until all the
+// components are backed up or one of them failed
+func (r *VerrazzanoBackupReconciler) reconcileBackup(ctx context.Context, log vzlog.VerrazzanoLogger, backup *vzapi.VerrazzanoBackup) (ctrl.Result, error) {
+	if backup.Status.Phase == vzapi.BackupPhaseCompleted || backup.Status.Phase == vzapi.BackupPhaseFailed {
+		return r.expireBackup(ctx, log, backup)
+	}
+	if message := validateBackup(backup); len(message) > 0 {
+		return r.finishBackup(ctx, log, backup, vzapi.BackupPhaseFailed, message)
+	}
+
+	vz, err := getVerrazzano(ctx, r.Client, backup.Namespace)
+	if err != nil {
+		log.Errorf("Failed to fetch the Verrazzano resource in namespace %s: %v", backup.Namespace, err)
+		return newRequeueWithDelay(), nil
+	}
+	if backup.Status.Phase != vzapi.BackupPhaseInProgress {
+		if vz == nil || vz.Status.State != vzapi.VzStateReady {
+			message := fmt.Sprintf("Waiting for the Verrazzano resource in namespace %s to be ready", backup.Namespace)
+			log.Progressf("VerrazzanoBackup %s/%s is waiting for the Verrazzano resource to be ready", backup.Namespace, backup.Name)
+			return r.setBackupPending(ctx, log, backup, message)
+		}
+		return r.startBackup(ctx, log, backup, vz)
+	}
+
+	i := getNextComponent(backup.Status.Components)
+	if i < 0 {
+		log.Oncef("VerrazzanoBackup %s/%s has completed", backup.Namespace, backup.Name)
+		return r.finishBackup(ctx, log, backup, vzapi.BackupPhaseCompleted, "")
+	}
+	component := &backup.Status.Components[i]
+	if vz == nil {
+		return r.finishBackup(ctx, log, backup, vzapi.BackupPhaseFailed, fmt.Sprintf("The Verrazzano resource in namespace %s was deleted", backup.Namespace))
+	}
+	comp, ok := findBackupComponent(component.Name)
+	if !ok {
+		return r.finishBackup(ctx, log, backup, vzapi.BackupPhaseFailed, fmt.Sprintf("The component %s does not support a backup", component.Name))
+	}
+	job, err := newBackupJob(backup, r.Scheme, comp)
+	if err != nil {
+		log.Errorf("Failed to create the backup job of component %s: %v", component.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	phase, message, err := runJob(ctx, r.Client, r.Scheme, backup, job, func() (map[string][]byte, error) {
+		return r.getBackupSecretData(ctx, log, backup, vz, comp)
+	})
+	if err != nil {
+		log.Errorf("Failed to run the backup job of component %s: %v", component.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	if phase == vzapi.BackupPhaseFailed {
+		component.Phase = phase
+		component.Message = message
+		return r.finishBackup(ctx, log, backup, phase, fmt.Sprintf("Failed to back up component %s", component.Name))
+	}
+	if component.Phase == phase {
+		log.Progressf("VerrazzanoBackup %s/%s is waiting for the backup of component %s", backup.Namespace, backup.Name, component.Name)
+		return ctrl.Result{}, nil
+	}
+	component.Phase = phase
+	return r.updateBackupStatus(ctx, log, backup, ctrl.Result{Requeue: phase == vzapi.BackupPhaseCompleted})
+}
+
+// startBackup adds the finalizer to the backup and initializes the phases of its components, the components that are
+// not enabled are skipped
+func (r *VerrazzanoBackupReconciler) startBackup(ctx context.Context, log vzlog.VerrazzanoLogger, backup *vzapi.VerrazzanoBackup, vz *vzapi.Verrazzano) (ctrl.Result, error) {
+	effectiveCR, err := transform.GetEffectiveCR(vz)
+	if err != nil {
+		log.Errorf("Failed to get the effective Verrazzano resource %s/%s: %v", vz.Namespace, vz.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	if !vzstring.SliceContainsString(backup.Finalizers, backupFinalizerName) {
+		backup.Finalizers = append(backup.Finalizers, backupFinalizerName)
+		if err := r.Update(ctx, backup); err != nil {
+			log.Errorf("Failed to add the finalizer to VerrazzanoBackup %s/%s: %v", backup.Namespace, backup.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+	}
+
+	requested := backup.Spec.Components
+	var components []vzapi.BackupComponentStatus
+	for _, comp := range getBackupComponents() {
+		name := comp.Name()
+		if len(requested) > 0 && !vzstring.SliceContainsString(requested, name) {
+			continue
+		}
+		status := vzapi.BackupComponentStatus{Name: name, Phase: vzapi.BackupPhasePending}
+		if !comp.IsEnabled(effectiveCR) {
+			status.Phase = vzapi.BackupPhaseSkipped
+			status.Message = "The component is not enabled"
+		}
+		components = append(components, status)
+	}
+	now := metav1.Now()
+	backup.Status.Phase = vzapi.BackupPhaseInProgress
+	backup.Status.Message = ""
+	backup.Status.StartTime = &now
+	backup.Status.Components = components
+	log.Oncef("VerrazzanoBackup %s/%s has started", backup.Namespace, backup.Name)
+	return r.updateBackupStatus(ctx, log, backup, ctrl.Result{Requeue: true})
+}
+
+// getBackupSecretData returns the data of the secret of the backup job of a component, with the credentials and the
+// state of the component
+func (r *VerrazzanoBackupReconciler) getBackupSecretData(ctx context.Context, log vzlog.VerrazzanoLogger, backup *vzapi.VerrazzanoBackup, vz *vzapi.Verrazzano, component backupComponent) (map[string][]byte, error) {
+	data, err := getJobSecretData(ctx, r.Client, backup.Spec.StorageLocation, component.spec)
+	if err != nil {
+		return nil, err
+	}
+	if component.spec.Method != spi.BackupMethodResources {
+		return data, nil
+	}
+	spiCtx, err := spi.NewContext(log, r.Client, vz, false)
+	if err != nil {
+		return nil, err
+	}
+	state, err := getComponentState(spiCtx.Init(component.Name()), r.Scheme, component)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range state {
+		data[key] = value
+	}
+	return data, nil
+}
+
+// reconcileSchedule creates a backup when the schedule is due, and deletes the completed backups of the schedule that
+// exceed the maximum number of backups.  Missed schedules are not caught up, only the last one creates a backup.
+func (r *VerrazzanoBackupReconciler) reconcileSchedule(ctx context.Context, log vzlog.VerrazzanoLogger, backup *vzapi.VerrazzanoBackup) (ctrl.Result, error) {
+	sched, err := parseSchedule(backup.Spec.Schedule)
+	if err != nil {
+		return r.finishBackup(ctx, log, backup, vzapi.BackupPhaseFailed, err.Error())
+	}
+	if message := validateBackup(backup); len(message) > 0 {
+		return r.finishBackup(ctx, log, backup, vzapi.BackupPhaseFailed, message)
+	}
+
+	now := time.Now()
+	last := backup.CreationTimestamp.Time
+	if backup.Status.LastScheduleTime != nil {
+		last = backup.Status.LastScheduleTime.Time
+	}
+	next := sched.next(last)
+	if next.IsZero() {
+		return r.finishBackup(ctx, log, backup, vzapi.BackupPhaseFailed, fmt.Sprintf("The schedule %q never runs", backup.Spec.Schedule))
+	}
+	changed := backup.Status.Phase != vzapi.BackupPhaseScheduled || len(backup.Status.Message) > 0
+	if !now.Before(next) {
+		scheduled := newScheduledBackup(backup, next)
+		if err := r.Create(ctx, scheduled); err != nil && !errors.IsAlreadyExists(err) {
+			log.Errorf("Failed to create the backup %s/%s of the schedule: %v", scheduled.Namespace, scheduled.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+		log.Infof("Created VerrazzanoBackup %s/%s of schedule %s", scheduled.Namespace, scheduled.Name, backup.Name)
+		backup.Status.LastScheduleTime = &metav1.Time{Time: now}
+		next = sched.next(now)
+		changed = true
+	}
+	if changed {
+		backup.Status.Phase = vzapi.BackupPhaseScheduled
+		backup.Status.Message = ""
+		if err := r.Status().Update(ctx, backup); err != nil {
+			log.Errorf("Failed to update the status of VerrazzanoBackup %s/%s: %v", backup.Namespace, backup.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+	}
+
+	if err := r.deleteExcessBackups(ctx, log, backup); err != nil {
+		log.Errorf("Failed to delete the backups of schedule %s/%s that are not retained: %v", backup.Namespace, backup.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+}
+
+// newScheduledBackup returns the backup created by a schedule at the scheduled time.  The backup keeps the time to
+// live of the schedule, and is kept when the schedule is deleted.
+func newScheduledBackup(schedule *vzapi.VerrazzanoBackup, scheduled time.Time) *vzapi.VerrazzanoBackup {
+	backup := &vzapi.VerrazzanoBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", schedule.Name, scheduled.UTC().Format("20060102150405")),
+			Namespace: schedule.Namespace,
+			Labels:    map[string]string{scheduleLabel: schedule.Name},
+		},
+		Spec: vzapi.VerrazzanoBackupSpec{
+			StorageLocation: *schedule.Spec.StorageLocation.DeepCopy(),
+			Components:      append([]string{}, schedule.Spec.Components...),
+		},
+	}
+	if schedule.Spec.Retention != nil && schedule.Spec.Retention.TTL != nil {
+		backup.Spec.Retention = &vzapi.BackupRetention{TTL: schedule.Spec.Retention.TTL.DeepCopy()}
+	}
+	return backup
+}
+
+// deleteExcessBackups deletes the oldest completed backups of a schedule that exceed the maximum number of backups
+func (r *VerrazzanoBackupReconciler) deleteExcessBackups(ctx context.Context, log vzlog.VerrazzanoLogger, schedule *vzapi.VerrazzanoBackup) error {
+	if schedule.Spec.Retention == nil || schedule.Spec.Retention.MaxBackups <= 0 {
+		return nil
+	}
+	backupList := &vzapi.VerrazzanoBackupList{}
+	if err := r.List(ctx, backupList, client.InNamespace(schedule.Namespace), client.MatchingLabels{scheduleLabel: schedule.Name}); err != nil {
+		return err
+	}
+	var completed []vzapi.VerrazzanoBackup
+	for _, backup := range backupList.Items {
+		if backup.Status.Phase == vzapi.BackupPhaseCompleted && backup.DeletionTimestamp.IsZero() {
+			completed = append(completed, backup)
+		}
+	}
+	// The names of the backups end with the scheduled time, the newest backups are first
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].Name > completed[j].Name
+	})
+	for i := schedule.Spec.Retention.MaxBackups; i < len(completed); i++ {
+		log.Infof("Deleting VerrazzanoBackup %s/%s, schedule %s keeps %d backups", completed[i].Namespace, completed[i].Name,
+			schedule.Name, schedule.Spec.Retention.MaxBackups)
+		if err := r.Delete(ctx, &completed[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// expireBackup deletes a finished backup once its time to live has passed
+func (r *VerrazzanoBackupReconciler) expireBackup(ctx context.Context, log vzlog.VerrazzanoLogger, backup *vzapi.VerrazzanoBackup) (ctrl.Result, error) {
+	if backup.Spec.Retention == nil || backup.Spec.Retention.TTL == nil {
+		return ctrl.Result{}, nil
+	}
+	expiry := backup.CreationTimestamp.Add(backup.Spec.Retention.TTL.Duration)
+	if remaining := time.Until(expiry); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	log.Infof("Deleting VerrazzanoBackup %s/%s, its time to live has passed", backup.Namespace, backup.Name)
+	if err := r.Delete(ctx, backup); err != nil && !errors.IsNotFound(err) {
+		log.Errorf("Failed to delete VerrazzanoBackup %s/%s: %v", backup.Namespace, backup.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// procDeleteBackup deletes the data of a deleted backup from the storage location, then removes the finalizer.  If the
+// data cannot be deleted the finalizer is removed anyway, so that the backup is not kept forever.
+func (r *VerrazzanoBackupReconciler) procDeleteBackup(ctx context.Context, log vzlog.VerrazzanoLogger, backup *vzapi.VerrazzanoBackup) (ctrl.Result, error) {
+	if !vzstring.SliceContainsString(backup.Finalizers, backupFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+	if hasCompletedComponent(backup.Status.Components) {
+		job, err := newDeleteJob(backup, r.Scheme)
+		if err != nil {
+			log.Errorf("Failed to create the job that deletes the data of VerrazzanoBackup %s/%s: %v", backup.Namespace, backup.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+		phase, message, err := runJob(ctx, r.Client, r.Scheme, backup, job, func() (map[string][]byte, error) {
+			return getJobSecretData(ctx, r.Client, backup.Spec.StorageLocation, spi.BackupSpec{})
+		})
+		if err != nil {
+			log.Errorf("Failed to run the job that deletes the data of VerrazzanoBackup %s/%s: %v", backup.Namespace, backup.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+		switch phase {
+		case vzapi.BackupPhaseInProgress:
+			log.Progressf("VerrazzanoBackup %s/%s is waiting for its data to be deleted", backup.Namespace, backup.Name)
+			return ctrl.Result{}, nil
+		case vzapi.BackupPhaseFailed:
+			log.Errorf("Failed to delete the data of VerrazzanoBackup %s/%s: %s", backup.Namespace, backup.Name, message)
+		}
+	}
+
+	backup.Finalizers = vzstring.RemoveStringFromSlice(backup.Finalizers, backupFinalizerName)
+	if err := r.Update(ctx, backup); err != nil {
+		log.Errorf("Failed to remove the finalizer from VerrazzanoBackup %s/%s: %v", backup.Namespace, backup.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// setBackupPending saves the reason the backup has not started, and requeues the reconcile
+func (r *VerrazzanoBackupReconciler) setBackupPending(ctx context.Context, log vzlog.VerrazzanoLogger, backup *vzapi.VerrazzanoBackup, message string) (ctrl.Result, error) {
+	if backup.Status.Phase == vzapi.BackupPhasePending && backup.Status.Message == message {
+		return newRequeueWithDelay(), nil
+	}
+	backup.Status.Phase = vzapi.BackupPhasePending
+	backup.Status.Message = message
+	return r.updateBackupStatus(ctx, log, backup, newRequeueWithDelay())
+}
+
+// finishBackup saves the final phase of the backup
+func (r *VerrazzanoBackupReconciler) finishBackup(ctx context.Context, log vzlog.VerrazzanoLogger, backup *vzapi.VerrazzanoBackup, phase vzapi.BackupPhase, message string) (ctrl.Result, error) {
+	if phase == vzapi.BackupPhaseFailed {
+		log.Errorf("VerrazzanoBackup %s/%s failed: %s", backup.Namespace, backup.Name, message)
+	}
+	now := metav1.Now()
+	backup.Status.Phase = phase
+	backup.Status.Message = message
+	backup.Status.CompletionTime = &now
+	return r.updateBackupStatus(ctx, log, backup, ctrl.Result{Requeue: phase == vzapi.BackupPhaseCompleted})
+}
+
+// updateBackupStatus saves the status of the backup and returns the result, the reconcile is requeued if the status
+// cannot be saved
+func (r *VerrazzanoBackupReconciler) updateBackupStatus(ctx context.Context, log vzlog.VerrazzanoLogger, backup *vzapi.VerrazzanoBackup, result ctrl.Result) (ctrl.Result, error) {
+	if err := r.Status().Update(ctx, backup); err != nil {
+		log.Errorf("Failed to update the status of VerrazzanoBackup %s/%s: %v", backup.Namespace, backup.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	return result, nil
+}
+
+// validateBackup returns the reason the backup is invalid, or an empty string if it is valid
+func validateBackup(backup *vzapi.VerrazzanoBackup) string {
+	if message := validateStorageLocation(backup.Spec.StorageLocation); len(message) > 0 {
+		return message
+	}
+	for _, component := range backup.Spec.Components {
+		if !isBackupComponent(component) {
+			return fmt.Sprintf("The component %s does not support a backup", component)
+		}
+	}
+	return ""
+}
+
+// getNextComponent returns the index of the first component that is not backed up or restored yet, or -1 if all the
+// components are done
+func getNextComponent(components []vzapi.BackupComponentStatus) int {
+	for i, component := range components {
+		if component.Phase == vzapi.BackupPhasePending || component.Phase == vzapi.BackupPhaseInProgress {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasCompletedComponent returns true if any component of the backup was backed up
+func hasCompletedComponent(components []vzapi.BackupComponentStatus) bool {
+	for _, component := range components {
+		if component.Phase == vzapi.BackupPhaseCompleted || component.Phase == vzapi.BackupPhaseInProgress {
+			return true
+		}
+	}
+	return false
+}
+
+// getVerrazzano returns the Verrazzano resource in the namespace, or nil if there isn't one
+func getVerrazzano(ctx context.Context, cli client.Client, namespace string) (*vzapi.Verrazzano, error) {
+	vzList := &vzapi.VerrazzanoList{}
+	if err := cli.List(ctx, vzList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	if len(vzList.Items) == 0 {
+		return nil, nil
+	}
+	return &vzList.Items[0], nil
+}
+
+// Create a new Result that will cause a reconcile requeue after a short delay
+func newRequeueWithDelay() ctrl.Result {
+	return vzctrl.NewRequeueWithDelay(3, 5, time.Second)
+}
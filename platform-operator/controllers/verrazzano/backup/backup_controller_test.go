@@ -0,0 +1,362 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testNamespace = "default"
+
+// TestReconcileBackup tests the VerrazzanoBackupReconciler Reconcile func
+// GIVEN a VerrazzanoBackup of the verrazzano and keycloak components and a ready Verrazzano resource
+// WHEN Reconcile is called as the jobs of the components complete
+// THEN ensure the components are backed up in order, and the backup is completed once all the jobs succeeded
+func TestReconcileBackup(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	backup := newTestBackup("backup", []string{"keycloak", "verrazzano"})
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(append(newTestSecrets(), newTestVerrazzano(), backup)...).Build()
+	reconciler := VerrazzanoBackupReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	// The backup starts, in the backup order of the components
+	result := reconcileBackup(t, reconciler, "backup")
+	asserts.True(result.Requeue)
+	actual := getTestBackup(t, c, "backup")
+	asserts.Equal(vzapi.BackupPhaseInProgress, actual.Status.Phase)
+	asserts.NotNil(actual.Status.StartTime)
+	asserts.Contains(actual.Finalizers, backupFinalizerName)
+	asserts.Equal([]vzapi.BackupComponentStatus{
+		{Name: "verrazzano", Phase: vzapi.BackupPhasePending},
+		{Name: "keycloak", Phase: vzapi.BackupPhasePending},
+	}, actual.Status.Components)
+
+	// The job of the verrazzano component is created, its secret has the state of the component
+	reconcileBackup(t, reconciler, "backup")
+	actual = getTestBackup(t, c, "backup")
+	asserts.Equal(vzapi.BackupPhaseInProgress, actual.Status.Components[0].Phase)
+	job := getTestJob(t, c, "backup-verrazzano-backup")
+	asserts.Equal("backup", job.Labels[backupLabel])
+	asserts.Equal(serviceAccountName, job.Spec.Template.Spec.ServiceAccountName)
+	secret := &corev1.Secret{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: job.Name}, secret))
+	asserts.Equal([]byte("access"), secret.Data[accessKey])
+	state := string(secret.Data["verrazzano.yaml"])
+	asserts.Contains(state, "kind: Verrazzano")
+	asserts.Contains(state, "name: mysql")
+	asserts.Contains(state, "name: keycloak")
+
+	// The job succeeds, its secret is deleted and the keycloak job is created
+	completeTestJob(t, c, job.Name, true)
+	result = reconcileBackup(t, reconciler, "backup")
+	asserts.True(result.Requeue)
+	asserts.True(errors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: job.Name}, &corev1.Secret{})))
+	reconcileBackup(t, reconciler, "backup")
+	job = getTestJob(t, c, "backup-keycloak-backup")
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: job.Name}, secret))
+	asserts.Equal([]byte("root"), secret.Data[mysqlPasswordKey])
+
+	completeTestJob(t, c, job.Name, true)
+	reconcileBackup(t, reconciler, "backup")
+	reconcileBackup(t, reconciler, "backup")
+	actual = getTestBackup(t, c, "backup")
+	asserts.Equal(vzapi.BackupPhaseCompleted, actual.Status.Phase)
+	asserts.NotNil(actual.Status.CompletionTime)
+}
+
+// TestReconcileBackupJobFailed tests the VerrazzanoBackupReconciler Reconcile func
+// GIVEN a VerrazzanoBackup whose job failed
+// WHEN Reconcile is called
+// THEN ensure the backup is failed and the remaining components are not backed up
+func TestReconcileBackupJobFailed(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	backup := newTestBackup("backup", []string{"verrazzano", "keycloak"})
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(append(newTestSecrets(), newTestVerrazzano(), backup)...).Build()
+	reconciler := VerrazzanoBackupReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	reconcileBackup(t, reconciler, "backup")
+	reconcileBackup(t, reconciler, "backup")
+	completeTestJob(t, c, "backup-verrazzano-backup", false)
+	reconcileBackup(t, reconciler, "backup")
+
+	actual := getTestBackup(t, c, "backup")
+	asserts.Equal(vzapi.BackupPhaseFailed, actual.Status.Phase)
+	asserts.Contains(actual.Status.Message, "verrazzano")
+	asserts.Equal(vzapi.BackupPhaseFailed, actual.Status.Components[0].Phase)
+	asserts.Equal(vzapi.BackupPhasePending, actual.Status.Components[1].Phase)
+	asserts.True(errors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "backup-keycloak-backup"}, &batchv1.Job{})))
+}
+
+// TestReconcileBackupPending tests the VerrazzanoBackupReconciler Reconcile func
+// GIVEN a VerrazzanoBackup and a Verrazzano resource that is not ready
+// WHEN Reconcile is called
+// THEN ensure the backup is pending and the reconcile is requeued
+func TestReconcileBackupPending(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	vz := newTestVerrazzano()
+	vz.Status.State = vzapi.VzStateInstalling
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz, newTestBackup("backup", nil)).Build()
+	reconciler := VerrazzanoBackupReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	result := reconcileBackup(t, reconciler, "backup")
+	asserts.True(result.Requeue)
+	actual := getTestBackup(t, c, "backup")
+	asserts.Equal(vzapi.BackupPhasePending, actual.Status.Phase)
+	asserts.NotEmpty(actual.Status.Message)
+	asserts.Empty(actual.Status.Components)
+}
+
+// TestReconcileBackupInvalid tests the VerrazzanoBackupReconciler Reconcile func
+// GIVEN VerrazzanoBackups with an invalid storage location or component
+// WHEN Reconcile is called
+// THEN ensure the backups are failed
+func TestReconcileBackupInvalid(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	noLocation := newTestBackup("no-location", nil)
+	noLocation.Spec.StorageLocation = vzapi.BackupStorageLocation{}
+	badComponent := newTestBackup("bad-component", []string{"istio"})
+	badSchedule := newTestBackup("bad-schedule", nil)
+	badSchedule.Spec.Schedule = "* * *"
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(newTestVerrazzano(), noLocation, badComponent, badSchedule).Build()
+	reconciler := VerrazzanoBackupReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	for _, name := range []string{"no-location", "bad-component", "bad-schedule"} {
+		reconcileBackup(t, reconciler, name)
+		actual := getTestBackup(t, c, name)
+		asserts.Equal(vzapi.BackupPhaseFailed, actual.Status.Phase, name)
+		asserts.NotEmpty(actual.Status.Message, name)
+	}
+}
+
+// TestReconcileSchedule tests the VerrazzanoBackupReconciler Reconcile func
+// GIVEN a VerrazzanoBackup with an hourly schedule that was created two hours ago
+// WHEN Reconcile is called
+// THEN ensure a backup of the schedule is created and the reconcile is requeued at the next scheduled time
+func TestReconcileSchedule(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	schedule := newTestBackup("schedule", []string{"keycloak"})
+	schedule.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	schedule.Spec.Schedule = "@hourly"
+	schedule.Spec.Retention = &vzapi.BackupRetention{TTL: &metav1.Duration{Duration: 24 * time.Hour}}
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(newTestVerrazzano(), schedule).Build()
+	reconciler := VerrazzanoBackupReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	result := reconcileBackup(t, reconciler, "schedule")
+	asserts.True(result.RequeueAfter > 0 && result.RequeueAfter <= time.Hour)
+	actual := getTestBackup(t, c, "schedule")
+	asserts.Equal(vzapi.BackupPhaseScheduled, actual.Status.Phase)
+	asserts.NotNil(actual.Status.LastScheduleTime)
+
+	backupList := &vzapi.VerrazzanoBackupList{}
+	asserts.NoError(c.List(context.TODO(), backupList, client.MatchingLabels{scheduleLabel: "schedule"}))
+	asserts.Len(backupList.Items, 1)
+	scheduled := backupList.Items[0]
+	asserts.Empty(scheduled.Spec.Schedule)
+	asserts.Equal([]string{"keycloak"}, scheduled.Spec.Components)
+	asserts.Equal(schedule.Spec.StorageLocation, scheduled.Spec.StorageLocation)
+	asserts.Equal(24*time.Hour, scheduled.Spec.Retention.TTL.Duration)
+
+	// The schedule is not due again
+	reconcileBackup(t, reconciler, "schedule")
+	asserts.NoError(c.List(context.TODO(), backupList, client.MatchingLabels{scheduleLabel: "schedule"}))
+	asserts.Len(backupList.Items, 1)
+}
+
+// TestReconcileScheduleMaxBackups tests the VerrazzanoBackupReconciler Reconcile func
+// GIVEN a VerrazzanoBackup with a schedule that keeps one backup, and two completed backups of the schedule
+// WHEN Reconcile is called
+// THEN ensure the oldest backup is deleted
+func TestReconcileScheduleMaxBackups(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	schedule := newTestBackup("schedule", nil)
+	schedule.Spec.Schedule = "@daily"
+	schedule.Spec.Retention = &vzapi.BackupRetention{MaxBackups: 1}
+	schedule.Status.LastScheduleTime = &metav1.Time{Time: time.Now()}
+	older := newTestBackup("schedule-20220101000000", nil)
+	newer := newTestBackup("schedule-20220102000000", nil)
+	inProgress := newTestBackup("schedule-20220103000000", nil)
+	for _, backup := range []*vzapi.VerrazzanoBackup{older, newer, inProgress} {
+		backup.Labels = map[string]string{scheduleLabel: "schedule"}
+		backup.Status.Phase = vzapi.BackupPhaseCompleted
+	}
+	inProgress.Status.Phase = vzapi.BackupPhaseInProgress
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(schedule, older, newer, inProgress).Build()
+	reconciler := VerrazzanoBackupReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	reconcileBackup(t, reconciler, "schedule")
+	asserts.True(errors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: older.Name}, &vzapi.VerrazzanoBackup{})))
+	getTestBackup(t, c, newer.Name)
+	getTestBackup(t, c, inProgress.Name)
+}
+
+// TestReconcileBackupTTL tests the VerrazzanoBackupReconciler Reconcile func
+// GIVEN completed VerrazzanoBackups whose time to live has passed or not
+// WHEN Reconcile is called
+// THEN ensure the expired backup is deleted, and the other one is requeued when it expires
+func TestReconcileBackupTTL(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	expired := newTestBackup("expired", nil)
+	expired.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	expired.Spec.Retention = &vzapi.BackupRetention{TTL: &metav1.Duration{Duration: time.Hour}}
+	expired.Status.Phase = vzapi.BackupPhaseCompleted
+	kept := expired.DeepCopy()
+	kept.Name = "kept"
+	kept.Spec.Retention.TTL.Duration = 3 * time.Hour
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(expired, kept).Build()
+	reconciler := VerrazzanoBackupReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	reconcileBackup(t, reconciler, "expired")
+	asserts.True(errors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "expired"}, &vzapi.VerrazzanoBackup{})))
+
+	result := reconcileBackup(t, reconciler, "kept")
+	asserts.True(result.RequeueAfter > 55*time.Minute && result.RequeueAfter <= time.Hour)
+	getTestBackup(t, c, "kept")
+}
+
+// TestReconcileDeleteBackup tests the VerrazzanoBackupReconciler Reconcile func
+// GIVEN a deleted VerrazzanoBackup with completed components
+// WHEN Reconcile is called
+// THEN ensure the job that deletes the data of the backup is run before the finalizer is removed
+func TestReconcileDeleteBackup(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	backup := newTestBackup("backup", nil)
+	backup.Finalizers = []string{backupFinalizerName}
+	backup.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	backup.Status.Phase = vzapi.BackupPhaseCompleted
+	backup.Status.Components = []vzapi.BackupComponentStatus{
+		{Name: "verrazzano", Phase: vzapi.BackupPhaseCompleted},
+		{Name: "opensearch", Phase: vzapi.BackupPhaseCompleted},
+	}
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(append(newTestSecrets(), backup)...).Build()
+	reconciler := VerrazzanoBackupReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	reconcileBackup(t, reconciler, "backup")
+	job := getTestJob(t, c, "backup-data-delete")
+	asserts.Len(job.Spec.Template.Spec.Containers, 2)
+	asserts.Equal([]string{"rm", "--recursive", "--force", "backup/bucket/prefix/backup/"}, job.Spec.Template.Spec.Containers[0].Args)
+	asserts.Contains(getTestBackup(t, c, "backup").Finalizers, backupFinalizerName)
+
+	completeTestJob(t, c, job.Name, true)
+	reconcileBackup(t, reconciler, "backup")
+	actual := &vzapi.VerrazzanoBackup{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "backup"}, actual)
+	asserts.True(errors.IsNotFound(err) || len(actual.Finalizers) == 0)
+}
+
+// setupTest registers the API types and overrides the images and the profiles of the tests
+func setupTest(t *testing.T) {
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	config.TestProfilesDir = "../../../manifests/profiles"
+	getJobImagesFunc = func() (*jobImages, error) {
+		return &jobImages{mc: "mc", mysql: "mysql", oraclelinux: "oraclelinux", kubectl: "kubectl"}, nil
+	}
+	t.Cleanup(func() {
+		config.TestProfilesDir = ""
+		getJobImagesFunc = getJobImages
+	})
+}
+
+// newTestVerrazzano returns a ready Verrazzano resource
+func newTestVerrazzano() *vzapi.Verrazzano {
+	return &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "verrazzano"},
+		Spec:       vzapi.VerrazzanoSpec{Profile: vzapi.Dev},
+		Status:     vzapi.VerrazzanoStatus{State: vzapi.VzStateReady},
+	}
+}
+
+// newTestBackup returns a backup of components to an S3 bucket
+func newTestBackup(name string, components []string) *vzapi.VerrazzanoBackup {
+	return &vzapi.VerrazzanoBackup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec: vzapi.VerrazzanoBackupSpec{
+			StorageLocation: vzapi.BackupStorageLocation{S3: &vzapi.S3StorageLocation{
+				Endpoint: "https://minio.example.com:9000",
+				Bucket:   "bucket",
+				Prefix:   "prefix",
+			}},
+			Components: components,
+		},
+	}
+}
+
+// newTestSecrets returns the secrets with the credentials of the storage location and the components
+func newTestSecrets() []client.Object {
+	return []client.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoSystemNamespace, Name: constants.VMIBackupSecretName},
+			Data: map[string][]byte{
+				constants.ObjectStoreAccessKey:       []byte("access"),
+				constants.ObjectStoreAccessSecretKey: []byte("secret"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: constants.KeycloakNamespace, Name: "mysql"},
+			Data:       map[string][]byte{mysqlRootPasswordKey: []byte("root")},
+		},
+	}
+}
+
+// reconcileBackup reconciles a backup and returns the result
+func reconcileBackup(t *testing.T, reconciler VerrazzanoBackupReconciler, name string) ctrl.Result {
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: name}})
+	assert.NoError(t, err)
+	return result
+}
+
+// getTestBackup returns a backup
+func getTestBackup(t *testing.T, c client.Client, name string) *vzapi.VerrazzanoBackup {
+	backup := &vzapi.VerrazzanoBackup{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: name}, backup))
+	return backup
+}
+
+// getTestJob returns a job
+func getTestJob(t *testing.T, c client.Client, name string) *batchv1.Job {
+	job := &batchv1.Job{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: name}, job))
+	return job
+}
+
+// completeTestJob sets the status of a job to succeeded or failed
+func completeTestJob(t *testing.T, c client.Client, name string, succeeded bool) {
+	job := getTestJob(t, c, name)
+	if succeeded {
+		job.Status.Succeeded = 1
+	} else {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+	}
+	assert.NoError(t, c.Status().Update(context.TODO(), job))
+}
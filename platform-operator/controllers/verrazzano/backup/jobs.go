@@ -0,0 +1,694 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/verrazzano/verrazzano/pkg/bom"
+	vzstring "github.com/verrazzano/verrazzano/pkg/string"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// serviceAccountName is the service account of the backup and restore jobs
+	serviceAccountName = "verrazzano-backup"
+
+	// backupLabel and restoreLabel are the labels of the jobs with the name of the backup or restore
+	backupLabel    = "verrazzano.io/backup"
+	restoreLabel   = "verrazzano.io/restore"
+	componentLabel = common.BackupComponentLabel
+
+	// The volumes of the jobs, the state of a component is written to the staging directory before it is copied to
+	// the storage location, and copied from the storage location to the staging directory before it is restored
+	stagingVolume = "staging"
+	stagingDir    = "/staging"
+	storageVolume = "storage"
+	storageDir    = "/backup"
+	stateVolume   = "state"
+	stateDir      = "/state"
+
+	// storageAlias is the alias of the S3 storage location for the MinIO client
+	storageAlias = "backup"
+
+	// The keys of the secret of a job
+	accessKey        = "access-key"
+	secretKey        = "secret-key"
+	mysqlPasswordKey = "mysql-password"
+	usernameKey      = "username"
+	passwordKey      = "password"
+
+	// The keys of the credential secrets of the MySQL and Grafana backup methods
+	mysqlRootPasswordKey = "mysql-root-password"
+
+	openSearchRepositoryName = "verrazzano-backup"
+
+	// maxJobNameLength is the maximum length of a job name, which is also a label value of its pods
+	maxJobNameLength = 63
+
+	// maxBindingNameLength is the maximum length of the name of a ClusterRoleBinding
+	maxBindingNameLength = 253
+)
+
+// The scripts of the jobs, they use the environment variables of the job containers
+const (
+	mysqlBackupScript = `mysqldump -h "$MYSQL_HOST" -u root --all-databases --single-transaction --routines --triggers > /staging/keycloak.sql`
+
+	mysqlRestoreScript = `mysql -h "$MYSQL_HOST" -u root < /staging/keycloak.sql`
+
+	copyStateScript = `cp -L /state/*.yaml /staging/`
+
+	grafanaBackupScript = `set -e
+for uid in $(curl -sf -u "$GRAFANA_USERNAME:$GRAFANA_PASSWORD" "$GRAFANA_URL/api/search?type=dash-db" | grep -o '"uid":"[^"]*"' | cut -d'"' -f4); do
+  curl -sf -u "$GRAFANA_USERNAME:$GRAFANA_PASSWORD" "$GRAFANA_URL/api/dashboards/uid/$uid" > "/staging/$uid.json"
+done`
+
+	grafanaRestoreScript = `set -e
+for dashboard in /staging/*.json; do
+  [ -e "$dashboard" ] || continue
+  sed -e 's/"id":[0-9]*/"id":null/' -e 's/^{/{"overwrite":true,/' "$dashboard" | curl -sf -u "$GRAFANA_USERNAME:$GRAFANA_PASSWORD" -XPOST -H 'Content-Type: application/json' "$GRAFANA_URL/api/dashboards/db" -d @-
+done`
+
+	openSearchBackupScript = `set -e
+curl -sf -XPUT -H 'Content-Type: application/json' "$OPENSEARCH_URL/_snapshot/$REPOSITORY_NAME" -d "$REPOSITORY"
+curl -sf -XPUT -H 'Content-Type: application/json' "$OPENSEARCH_URL/_snapshot/$REPOSITORY_NAME/$SNAPSHOT?wait_for_completion=true" -d '{"indices":"*,-.*","include_global_state":false}' | grep -q '"state":"SUCCESS"'`
+
+	openSearchRestoreScript = `set -e
+curl -sf -XPUT -H 'Content-Type: application/json' "$OPENSEARCH_URL/_snapshot/$REPOSITORY_NAME" -d "$REPOSITORY"
+curl -s -XPOST "$OPENSEARCH_URL/*,-.*/_close" || true
+curl -sf -XPOST -H 'Content-Type: application/json' "$OPENSEARCH_URL/_snapshot/$REPOSITORY_NAME/$SNAPSHOT/_restore?wait_for_completion=true" -d '{"indices":"*,-.*","include_global_state":false}'`
+
+	openSearchDeleteScript = `curl -s -XDELETE "$OPENSEARCH_URL/_snapshot/$REPOSITORY_NAME/$SNAPSHOT" || true`
+)
+
+// backupComponent is a component that supports a backup, with the description of its backup and restore
+type backupComponent struct {
+	spi.Component
+	spec spi.BackupSpec
+}
+
+// getBackupComponents returns the components that support a backup, in backup and restore order
+func getBackupComponents() []backupComponent {
+	var components []backupComponent
+	for _, comp := range registry.GetComponents() {
+		if provider, ok := comp.(spi.ComponentBackupProvider); ok {
+			components = append(components, backupComponent{Component: comp, spec: provider.GetBackupSpec()})
+		}
+	}
+	sort.SliceStable(components, func(i, j int) bool {
+		return components[i].spec.Order < components[j].spec.Order
+	})
+	return components
+}
+
+// findBackupComponent returns the component with the name, false if there is no such component or it doesn't support
+// a backup
+func findBackupComponent(name string) (backupComponent, bool) {
+	for _, comp := range getBackupComponents() {
+		if comp.Name() == name {
+			return comp, true
+		}
+	}
+	return backupComponent{}, false
+}
+
+// jobImages are the images used by the backup and restore jobs
+type jobImages struct {
+	mc          string
+	mysql       string
+	oraclelinux string
+	kubectl     string
+}
+
+// getJobImagesFunc is overridden by the unit tests
+var getJobImagesFunc = getJobImages
+
+// getJobImages returns the images of the jobs from the BOM
+func getJobImages() (*jobImages, error) {
+	bomFile, err := bom.NewBom(config.GetDefaultBOMFilePath())
+	if err != nil {
+		return nil, err
+	}
+	images := &jobImages{}
+	for _, image := range []struct {
+		subcomponent string
+		name         string
+		image        *string
+	}{
+		{subcomponent: "verrazzano-backup", name: "mc", image: &images.mc},
+		{subcomponent: "mysql", name: "mysql", image: &images.mysql},
+		{subcomponent: "oraclelinux", name: "oraclelinux", image: &images.oraclelinux},
+		{subcomponent: "additional-rancher", name: "kubectl", image: &images.kubectl},
+	} {
+		if *image.image, err = getBomImage(&bomFile, image.subcomponent, image.name); err != nil {
+			return nil, err
+		}
+	}
+	return images, nil
+}
+
+// getBomImage returns the full name of an image of a BOM subcomponent
+func getBomImage(bomFile *bom.Bom, subcomponent string, name string) (string, error) {
+	images, err := bomFile.GetSubcomponentImages(subcomponent)
+	if err != nil {
+		return "", err
+	}
+	fullNames, err := bomFile.GetImageNameList(subcomponent)
+	if err != nil {
+		return "", err
+	}
+	for i, image := range images {
+		if image.ImageName == name && i < len(fullNames) {
+			return fullNames[i], nil
+		}
+	}
+	return "", fmt.Errorf("Failed to find image %s in the BOM subcomponent %s", name, subcomponent)
+}
+
+// isBackupComponent returns true if the component supports a backup
+func isBackupComponent(name string) bool {
+	_, ok := findBackupComponent(name)
+	return ok
+}
+
+// validateStorageLocation returns the reason the storage location is invalid, or an empty string if it is valid
+func validateStorageLocation(location vzapi.BackupStorageLocation) string {
+	if (location.S3 == nil) == (location.PersistentVolumeClaim == nil) {
+		return "Exactly one of storageLocation.s3 and storageLocation.persistentVolumeClaim must be specified"
+	}
+	if location.S3 != nil {
+		if len(location.S3.Endpoint) == 0 || len(location.S3.Bucket) == 0 {
+			return "storageLocation.s3.endpoint and storageLocation.s3.bucket must be specified"
+		}
+		if !strings.Contains(location.S3.Endpoint, "://") {
+			return "storageLocation.s3.endpoint must be a URL, for example https://minio.example.com:9000"
+		}
+	}
+	if location.PersistentVolumeClaim != nil && len(location.PersistentVolumeClaim.ClaimName) == 0 {
+		return "storageLocation.persistentVolumeClaim.claimName must be specified"
+	}
+	return ""
+}
+
+// getJobName returns the name of the job of a component.  Names that are too long are shortened with a hash, so
+// that the job names of different backups stay unique.
+func getJobName(ownerName string, component string, operation string) string {
+	return shortenName(fmt.Sprintf("%s-%s-%s", ownerName, component, operation), maxJobNameLength)
+}
+
+// getBindingName returns the name of the ClusterRoleBinding of the jobs of a restore.  Names that are too long are
+// shortened with a hash, so that the binding names of different restores stay unique.
+func getBindingName(restore types.NamespacedName) string {
+	return shortenName(fmt.Sprintf("%s-%s-%s", serviceAccountName, restore.Namespace, restore.Name), maxBindingNameLength)
+}
+
+// shortenName returns the name shortened with a hash if it is longer than the maximum length
+func shortenName(name string, maxLength int) string {
+	if len(name) <= maxLength {
+		return name
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+	return strings.TrimRight(name[:maxLength-len(suffix)], "-") + suffix
+}
+
+// getStorageTarget returns the location of the state of a component of a backup, for the MinIO client
+func getStorageTarget(location vzapi.BackupStorageLocation, backupName string, component string) string {
+	if location.S3 != nil {
+		return path.Join(storageAlias, location.S3.Bucket, location.S3.Prefix, backupName, component) + "/"
+	}
+	return path.Join(storageDir, location.PersistentVolumeClaim.Path, backupName, component) + "/"
+}
+
+// getStorageBase returns the location of a backup, for the MinIO client
+func getStorageBase(location vzapi.BackupStorageLocation, backupName string) string {
+	if location.S3 != nil {
+		return path.Join(storageAlias, location.S3.Bucket, location.S3.Prefix, backupName) + "/"
+	}
+	return path.Join(storageDir, location.PersistentVolumeClaim.Path, backupName) + "/"
+}
+
+// getOpenSearchRepository returns the settings of the OpenSearch snapshot repository of the storage location.  The
+// credentials and the endpoint of the S3 repository are the settings of the default S3 client of OpenSearch, the
+// credentials are populated from the verrazzano-backup secret.
+func getOpenSearchRepository(location vzapi.BackupStorageLocation) string {
+	if location.S3 != nil {
+		return fmt.Sprintf(`{"type":"s3","settings":{"bucket":%q,"base_path":%q}}`,
+			location.S3.Bucket, path.Join(location.S3.Prefix, "opensearch"))
+	}
+	return fmt.Sprintf(`{"type":"fs","settings":{"location":%q}}`, location.PersistentVolumeClaim.OpenSearchRepositoryPath)
+}
+
+// newJob returns a job that runs the containers of a component of a backup or restore, the job is owned by the backup
+// or restore and runs in its namespace.  The secret of the job has the same name as the job.
+func newJob(owner client.Object, scheme *runtime.Scheme, name string, labels map[string]string, location vzapi.BackupStorageLocation, initContainers []corev1.Container, containers []corev1.Container, stateKeys []string) (*batchv1.Job, error) {
+	backoffLimit := int32(2)
+	volumes := []corev1.Volume{{
+		Name:         stagingVolume,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}}
+	if location.PersistentVolumeClaim != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: storageVolume,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: location.PersistentVolumeClaim.ClaimName,
+			}},
+		})
+	}
+	if len(stateKeys) > 0 {
+		var items []corev1.KeyToPath
+		for _, key := range stateKeys {
+			items = append(items, corev1.KeyToPath{Key: key, Path: key})
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name:         stateVolume,
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name, Items: items}},
+		})
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: owner.GetNamespace(),
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "false",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					InitContainers:     initContainers,
+					Containers:         containers,
+					Volumes:            volumes,
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(owner, job, scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// newBackupJob returns the job that backs up a component
+func newBackupJob(backup *vzapi.VerrazzanoBackup, scheme *runtime.Scheme, component backupComponent) (*batchv1.Job, error) {
+	images, err := getJobImagesFunc()
+	if err != nil {
+		return nil, err
+	}
+	name := getJobName(backup.Name, component.Name(), "backup")
+	labels := getJobLabels(backupLabel, backup.Name, component.Name())
+	location := backup.Spec.StorageLocation
+	endpoint := component.spec.Endpoint
+	var initContainers []corev1.Container
+	switch component.spec.Method {
+	case spi.BackupMethodOpenSearch:
+		return newJob(backup, scheme, name, labels, location, nil,
+			[]corev1.Container{newOpenSearchContainer(images, endpoint, location, backup.Name, openSearchBackupScript)}, nil)
+	case spi.BackupMethodMySQL:
+		initContainers = append(initContainers, newMySQLContainer(images, name, endpoint, mysqlBackupScript))
+	case spi.BackupMethodGrafana:
+		initContainers = append(initContainers, newGrafanaContainer(images, name, endpoint, grafanaBackupScript))
+	case spi.BackupMethodResources:
+		initContainers = append(initContainers, corev1.Container{
+			Name:         "copy-state",
+			Image:        images.oraclelinux,
+			Command:      []string{"sh", "-c", copyStateScript},
+			VolumeMounts: []corev1.VolumeMount{stagingMount(), {Name: stateVolume, MountPath: stateDir, ReadOnly: true}},
+		})
+	default:
+		return nil, fmt.Errorf("Failed, component %s has the unsupported backup method %s", component.Name(), component.spec.Method)
+	}
+	upload := newTransferContainer(images, "upload", name, location, stagingDir+"/", getStorageTarget(location, backup.Name, component.Name()))
+	return newJob(backup, scheme, name, labels, location, initContainers, []corev1.Container{upload}, getStateKeys(component))
+}
+
+// newRestoreJob returns the job that restores a component from a backup
+func newRestoreJob(restore *vzapi.VerrazzanoRestore, backup *vzapi.VerrazzanoBackup, scheme *runtime.Scheme, component backupComponent) (*batchv1.Job, error) {
+	images, err := getJobImagesFunc()
+	if err != nil {
+		return nil, err
+	}
+	name := getJobName(restore.Name, component.Name(), "restore")
+	location := backup.Spec.StorageLocation
+	labels := getJobLabels(restoreLabel, restore.Name, component.Name())
+	endpoint := component.spec.Endpoint
+	if component.spec.Method == spi.BackupMethodOpenSearch {
+		return newJob(restore, scheme, name, labels, location, nil,
+			[]corev1.Container{newOpenSearchContainer(images, endpoint, location, backup.Name, openSearchRestoreScript)}, nil)
+	}
+
+	download := newTransferContainer(images, "download", name, location, getStorageTarget(location, backup.Name, component.Name()), stagingDir+"/")
+	var container corev1.Container
+	switch component.spec.Method {
+	case spi.BackupMethodMySQL:
+		container = newMySQLContainer(images, name, endpoint, mysqlRestoreScript)
+	case spi.BackupMethodGrafana:
+		container = newGrafanaContainer(images, name, endpoint, grafanaRestoreScript)
+	case spi.BackupMethodResources:
+		// The resources of the state file are applied in the order the component returned them
+		container = corev1.Container{
+			Name:         "apply-state",
+			Image:        images.kubectl,
+			Args:         []string{"apply", "-f", stagingDir},
+			VolumeMounts: []corev1.VolumeMount{stagingMount()},
+		}
+	default:
+		return nil, fmt.Errorf("Failed, component %s has the unsupported backup method %s", component.Name(), component.spec.Method)
+	}
+	return newJob(restore, scheme, name, labels, location, []corev1.Container{download}, []corev1.Container{container}, nil)
+}
+
+// newDeleteJob returns the job that deletes the data of a backup from the storage location
+func newDeleteJob(backup *vzapi.VerrazzanoBackup, scheme *runtime.Scheme) (*batchv1.Job, error) {
+	images, err := getJobImagesFunc()
+	if err != nil {
+		return nil, err
+	}
+	name := getJobName(backup.Name, "data", "delete")
+	location := backup.Spec.StorageLocation
+	remove := newTransferContainer(images, "delete", name, location, "", "")
+	remove.Args = []string{"rm", "--recursive", "--force", getStorageBase(location, backup.Name)}
+	containers := []corev1.Container{remove}
+	for _, component := range getBackupComponents() {
+		if component.spec.Method == spi.BackupMethodOpenSearch && isComponentCompleted(backup.Status.Components, component.Name()) {
+			containers = append(containers, newOpenSearchContainer(images, component.spec.Endpoint, location, backup.Name, openSearchDeleteScript))
+		}
+	}
+	return newJob(backup, scheme, name, getJobLabels(backupLabel, backup.Name, "data"), location, nil, containers, nil)
+}
+
+// getJobLabels returns the labels of a job
+func getJobLabels(label string, ownerName string, component string) map[string]string {
+	return map[string]string{
+		label:          ownerName,
+		componentLabel: component,
+	}
+}
+
+// stagingMount returns the mount of the staging directory
+func stagingMount() corev1.VolumeMount {
+	return corev1.VolumeMount{Name: stagingVolume, MountPath: stagingDir}
+}
+
+// newTransferContainer returns a MinIO client container that copies the source to the target, one of which is the
+// storage location
+func newTransferContainer(images *jobImages, name string, secretName string, location vzapi.BackupStorageLocation, source string, target string) corev1.Container {
+	container := corev1.Container{
+		Name:         name,
+		Image:        images.mc,
+		Args:         []string{"cp", "--recursive", source, target},
+		VolumeMounts: []corev1.VolumeMount{stagingMount()},
+	}
+	if location.PersistentVolumeClaim != nil {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: storageVolume, MountPath: storageDir})
+		return container
+	}
+	scheme, host := splitEndpoint(location.S3.Endpoint)
+	container.Env = []corev1.EnvVar{
+		secretEnv("ACCESS_KEY", secretName, accessKey),
+		secretEnv("SECRET_KEY", secretName, secretKey),
+		{Name: "MC_HOST_" + storageAlias, Value: fmt.Sprintf("%s://$(ACCESS_KEY):$(SECRET_KEY)@%s", scheme, host)},
+	}
+	if len(location.S3.Region) > 0 {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "MC_REGION", Value: location.S3.Region})
+	}
+	return container
+}
+
+// newMySQLContainer returns a container that runs a script with the MySQL client
+func newMySQLContainer(images *jobImages, secretName string, host string, script string) corev1.Container {
+	return corev1.Container{
+		Name:    "mysql",
+		Image:   images.mysql,
+		Command: []string{"sh", "-c", script},
+		Env: []corev1.EnvVar{
+			{Name: "MYSQL_HOST", Value: host},
+			secretEnv("MYSQL_PWD", secretName, mysqlPasswordKey),
+		},
+		VolumeMounts: []corev1.VolumeMount{stagingMount()},
+	}
+}
+
+// newGrafanaContainer returns a container that runs a script with the Grafana API
+func newGrafanaContainer(images *jobImages, secretName string, url string, script string) corev1.Container {
+	return corev1.Container{
+		Name:    "grafana",
+		Image:   images.oraclelinux,
+		Command: []string{"sh", "-c", script},
+		Env: []corev1.EnvVar{
+			{Name: "GRAFANA_URL", Value: url},
+			secretEnv("GRAFANA_USERNAME", secretName, usernameKey),
+			secretEnv("GRAFANA_PASSWORD", secretName, passwordKey),
+		},
+		VolumeMounts: []corev1.VolumeMount{stagingMount()},
+	}
+}
+
+// newOpenSearchContainer returns a container that runs a script with the OpenSearch snapshot API.  The snapshot has
+// the name of the backup.
+func newOpenSearchContainer(images *jobImages, url string, location vzapi.BackupStorageLocation, backupName string, script string) corev1.Container {
+	return corev1.Container{
+		Name:    "opensearch",
+		Image:   images.oraclelinux,
+		Command: []string{"sh", "-c", script},
+		Env: []corev1.EnvVar{
+			{Name: "OPENSEARCH_URL", Value: url},
+			{Name: "REPOSITORY_NAME", Value: openSearchRepositoryName},
+			{Name: "REPOSITORY", Value: getOpenSearchRepository(location)},
+			{Name: "SNAPSHOT", Value: backupName},
+		},
+	}
+}
+
+// secretEnv returns an environment variable from a key of a secret
+func secretEnv(name string, secretName string, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+			Key:                  key,
+		}},
+	}
+}
+
+// splitEndpoint returns the scheme and the host of the endpoint of an S3 storage location
+func splitEndpoint(endpoint string) (string, string) {
+	parts := strings.SplitN(endpoint, "://", 2)
+	if len(parts) != 2 {
+		return "https", endpoint
+	}
+	return parts[0], strings.TrimSuffix(parts[1], "/")
+}
+
+// getJobSecretData returns the credentials used by the job of a component, the credentials are copied from the
+// secrets of the components since the job runs in the namespace of the backup or restore
+func getJobSecretData(ctx context.Context, cli client.Client, location vzapi.BackupStorageLocation, spec spi.BackupSpec) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	if location.S3 != nil {
+		name := location.S3.CredentialSecretName
+		if len(name) == 0 {
+			name = constants.VMIBackupSecretName
+		}
+		secret, err := getSecret(ctx, cli, types.NamespacedName{Namespace: constants.VerrazzanoSystemNamespace, Name: name})
+		if err != nil {
+			return nil, err
+		}
+		data[accessKey] = secret.Data[constants.ObjectStoreAccessKey]
+		data[secretKey] = secret.Data[constants.ObjectStoreAccessSecretKey]
+	}
+	switch spec.Method {
+	case spi.BackupMethodMySQL:
+		secret, err := getSecret(ctx, cli, spec.CredentialSecret)
+		if err != nil {
+			return nil, err
+		}
+		data[mysqlPasswordKey] = secret.Data[mysqlRootPasswordKey]
+	case spi.BackupMethodGrafana:
+		secret, err := getSecret(ctx, cli, spec.CredentialSecret)
+		if err != nil {
+			return nil, err
+		}
+		data[usernameKey] = secret.Data[usernameKey]
+		data[passwordKey] = secret.Data[passwordKey]
+	}
+	return data, nil
+}
+
+// getSecret returns a secret, the error explains which secret is missing
+func getSecret(ctx context.Context, cli client.Client, name types.NamespacedName) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, name, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("Failed, secret %s not found", name)
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// runJob creates the job and its secret if the job doesn't exist, and returns the phase of the job.  The data of the
+// secret is only read when the job is created.  The secret of the job is deleted once the job is done, since it has
+// credentials.
+func runJob(ctx context.Context, cli client.Client, scheme *runtime.Scheme, owner client.Object, job *batchv1.Job, getSecretData func() (map[string][]byte, error)) (vzapi.BackupPhase, string, error) {
+	existing := &batchv1.Job{}
+	err := cli.Get(ctx, client.ObjectKeyFromObject(job), existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", "", err
+	}
+	if errors.IsNotFound(err) {
+		if err := ensureServiceAccount(ctx, cli, job.Namespace); err != nil {
+			return "", "", err
+		}
+		secretData, err := getSecretData()
+		if err != nil {
+			return "", "", err
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: job.Name, Namespace: job.Namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, cli, secret, func() error {
+			secret.Data = secretData
+			return controllerutil.SetControllerReference(owner, secret, scheme)
+		}); err != nil {
+			return "", "", err
+		}
+		if err := cli.Create(ctx, job); err != nil {
+			return "", "", err
+		}
+		return vzapi.BackupPhaseInProgress, "", nil
+	}
+
+	phase, message := getJobPhase(existing)
+	if phase == vzapi.BackupPhaseInProgress {
+		return phase, message, nil
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: job.Name, Namespace: job.Namespace}}
+	if err := cli.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+		return "", "", err
+	}
+	return phase, message, nil
+}
+
+// getJobPhase returns the phase of a job, and the reason it failed
+func getJobPhase(job *batchv1.Job) (vzapi.BackupPhase, string) {
+	if job.Status.Succeeded > 0 {
+		return vzapi.BackupPhaseCompleted, ""
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return vzapi.BackupPhaseFailed, fmt.Sprintf("Job %s/%s failed: %s", job.Namespace, job.Name, condition.Message)
+		}
+	}
+	return vzapi.BackupPhaseInProgress, ""
+}
+
+// ensureServiceAccount creates the service account of the jobs in a namespace.  The service account has no
+// permissions, except for the restore jobs that apply the state of a component, see ensureRoleBinding.
+func ensureServiceAccount(ctx context.Context, cli client.Client, namespace string) error {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName, Namespace: namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, cli, sa, func() error { return nil })
+	return err
+}
+
+// getClusterRoleRules returns the rules of the ClusterRole of the restore jobs, which can only read and apply the
+// kinds of the state of the components
+func getClusterRoleRules() []rbacv1.PolicyRule {
+	resources := map[string][]string{}
+	var groups []string
+	for _, component := range getBackupComponents() {
+		for _, kind := range component.spec.Kinds {
+			plural, _ := meta.UnsafeGuessKindToResource(kind)
+			if _, ok := resources[kind.Group]; !ok {
+				groups = append(groups, kind.Group)
+			}
+			if !vzstring.SliceContainsString(resources[kind.Group], plural.Resource) {
+				resources[kind.Group] = append(resources[kind.Group], plural.Resource)
+			}
+		}
+	}
+	sort.Strings(groups)
+	var rules []rbacv1.PolicyRule
+	for _, group := range groups {
+		sort.Strings(resources[group])
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{group},
+			Resources: resources[group],
+			Verbs:     []string{"get", "list", "create", "patch"},
+		})
+	}
+	return rules
+}
+
+// ensureRoleBinding binds the service account of the jobs of a restore to the ClusterRole of the restore jobs, until
+// the restore finishes
+func ensureRoleBinding(ctx context.Context, cli client.Client, restore types.NamespacedName) error {
+	role := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, cli, role, func() error {
+		role.Rules = getClusterRoleRules()
+		return nil
+	}); err != nil {
+		return err
+	}
+	binding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: getBindingName(restore)}}
+	_, err := controllerutil.CreateOrUpdate(ctx, cli, binding, func() error {
+		binding.Labels = map[string]string{restoreLabel: restore.Name}
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     role.Name,
+		}
+		binding.Subjects = []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      serviceAccountName,
+			Namespace: restore.Namespace,
+		}}
+		return nil
+	})
+	return err
+}
+
+// deleteRoleBinding deletes the ClusterRoleBinding of the jobs of a restore
+func deleteRoleBinding(ctx context.Context, cli client.Client, restore types.NamespacedName) error {
+	binding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: getBindingName(restore)}}
+	if err := cli.Delete(ctx, binding); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isComponentCompleted returns true if a component of a backup or restore completed
+func isComponentCompleted(components []vzapi.BackupComponentStatus, name string) bool {
+	for _, component := range components {
+		if component.Name == name {
+			return component.Phase == vzapi.BackupPhaseCompleted
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzstring "github.com/verrazzano/verrazzano/pkg/string"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VerrazzanoRestoreReconciler restores the components of a completed backup, one component at a time.  The Verrazzano
+// resource and the generated secrets are restored first, which installs Verrazzano with the same secrets.  Each other
+// component is restored once it is ready.
+type VerrazzanoRestoreReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// SetupWithManager creates a new controller and adds it to the manager
+func (r *VerrazzanoRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vzapi.VerrazzanoRestore{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
+
+// Reconcile runs the jobs of the components of a restore in order, each job waits for its component to be ready
+func (r *VerrazzanoRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	restore := &vzapi.VerrazzanoRestore{}
+	if err := r.Get(ctx, req.NamespacedName, restore); err != nil {
+		if errors.IsNotFound(err) {
+			// The restore was deleted before it finished
+			if err := deleteRoleBinding(ctx, r.Client, req.NamespacedName); err != nil {
+				zap.S().Errorf("Failed to delete the ClusterRoleBinding of VerrazzanoRestore %s/%s: %v", req.Namespace, req.Name, err)
+				return newRequeueWithDelay(), nil
+			}
+			return ctrl.Result{}, nil
+		}
+		zap.S().Errorf("Failed to fetch VerrazzanoRestore %s/%s: %v", req.Namespace, req.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	if !restore.DeletionTimestamp.IsZero() {
+		if err := deleteRoleBinding(ctx, r.Client, req.NamespacedName); err != nil {
+			zap.S().Errorf("Failed to delete the ClusterRoleBinding of VerrazzanoRestore %s/%s: %v", req.Namespace, req.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+		return ctrl.Result{}, nil
+	}
+	if restore.Status.Phase == vzapi.BackupPhaseCompleted || restore.Status.Phase == vzapi.BackupPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	// Get the resource logger needed to log message using 'progress' and 'once' methods
+	log, err := vzlog.EnsureResourceLogger(&vzlog.ResourceConfig{
+		Name:           restore.Name,
+		Namespace:      restore.Namespace,
+		ID:             string(restore.UID),
+		Generation:     restore.Generation,
+		ControllerName: "verrazzanorestore",
+	})
+	if err != nil {
+		zap.S().Errorf("Failed to create resource logger for VerrazzanoRestore controller: %v", err)
+		return newRequeueWithDelay(), nil
+	}
+	return r.reconcileRestore(ctx, log, restore)
+}
+
+// reconcileRestore starts the restore once the backup is completed, then runs the job of the next component until all
+// the components are restored or one of them failed
+func (r *VerrazzanoRestoreReconciler) reconcileRestore(ctx context.Context, log vzlog.VerrazzanoLogger, restore *vzapi.VerrazzanoRestore) (ctrl.Result, error) {
+	backup := &vzapi.VerrazzanoBackup{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.BackupName}, backup); err != nil {
+		if errors.IsNotFound(err) {
+			return r.finishRestore(ctx, log, restore, vzapi.BackupPhaseFailed, fmt.Sprintf("The backup %s was not found", restore.Spec.BackupName))
+		}
+		log.Errorf("Failed to fetch VerrazzanoBackup %s/%s: %v", restore.Namespace, restore.Spec.BackupName, err)
+		return newRequeueWithDelay(), nil
+	}
+	if restore.Status.Phase != vzapi.BackupPhaseInProgress {
+		switch backup.Status.Phase {
+		case vzapi.BackupPhaseCompleted:
+			return r.startRestore(ctx, log, restore, backup)
+		case vzapi.BackupPhaseFailed, vzapi.BackupPhaseScheduled:
+			return r.finishRestore(ctx, log, restore, vzapi.BackupPhaseFailed, fmt.Sprintf("The backup %s is not a completed backup", backup.Name))
+		}
+		return r.setRestorePending(ctx, log, restore, fmt.Sprintf("Waiting for the backup %s to complete", backup.Name))
+	}
+
+	i := getNextComponent(restore.Status.Components)
+	if i < 0 {
+		log.Oncef("VerrazzanoRestore %s/%s has completed", restore.Namespace, restore.Name)
+		return r.finishRestore(ctx, log, restore, vzapi.BackupPhaseCompleted, "")
+	}
+	component := &restore.Status.Components[i]
+	comp, ok := findBackupComponent(component.Name)
+	if !ok {
+		return r.finishRestore(ctx, log, restore, vzapi.BackupPhaseFailed, fmt.Sprintf("The component %s does not support a backup", component.Name))
+	}
+	if !comp.spec.RestoreBeforeInstall {
+		ready, err := r.isComponentReady(ctx, restore.Namespace, component.Name)
+		if err != nil {
+			log.Errorf("Failed to fetch the Verrazzano resource in namespace %s: %v", restore.Namespace, err)
+			return newRequeueWithDelay(), nil
+		}
+		if !ready {
+			log.Progressf("VerrazzanoRestore %s/%s is waiting for component %s to be ready", restore.Namespace, restore.Name, component.Name)
+			return newRequeueWithDelay(), nil
+		}
+	}
+
+	job, err := newRestoreJob(restore, backup, r.Scheme, comp)
+	if err != nil {
+		log.Errorf("Failed to create the restore job of component %s: %v", component.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	if comp.spec.Method == spi.BackupMethodResources {
+		// Only the jobs that apply the state of a component use the Kubernetes API
+		if err := ensureRoleBinding(ctx, r.Client, client.ObjectKeyFromObject(restore)); err != nil {
+			log.Errorf("Failed to bind the service account of the restore job of component %s: %v", component.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+	}
+	phase, message, err := runJob(ctx, r.Client, r.Scheme, restore, job, func() (map[string][]byte, error) {
+		return getJobSecretData(ctx, r.Client, backup.Spec.StorageLocation, comp.spec)
+	})
+	if err != nil {
+		log.Errorf("Failed to run the restore job of component %s: %v", component.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	if phase == vzapi.BackupPhaseFailed {
+		component.Phase = phase
+		component.Message = message
+		return r.finishRestore(ctx, log, restore, phase, fmt.Sprintf("Failed to restore component %s", component.Name))
+	}
+	if component.Phase == phase {
+		log.Progressf("VerrazzanoRestore %s/%s is waiting for the restore of component %s", restore.Namespace, restore.Name, component.Name)
+		return ctrl.Result{}, nil
+	}
+	component.Phase = phase
+	return r.updateRestoreStatus(ctx, log, restore, ctrl.Result{Requeue: phase == vzapi.BackupPhaseCompleted})
+}
+
+// startRestore initializes the phases of the components that are restored, which are the completed components of the
+// backup
+func (r *VerrazzanoRestoreReconciler) startRestore(ctx context.Context, log vzlog.VerrazzanoLogger, restore *vzapi.VerrazzanoRestore, backup *vzapi.VerrazzanoBackup) (ctrl.Result, error) {
+	for _, name := range restore.Spec.Components {
+		if !isComponentCompleted(backup.Status.Components, name) {
+			return r.finishRestore(ctx, log, restore, vzapi.BackupPhaseFailed, fmt.Sprintf("The component %s is not in the backup %s", name, backup.Name))
+		}
+	}
+	var components []vzapi.BackupComponentStatus
+	for _, comp := range getBackupComponents() {
+		name := comp.Name()
+		if !isComponentCompleted(backup.Status.Components, name) {
+			continue
+		}
+		if len(restore.Spec.Components) > 0 && !vzstring.SliceContainsString(restore.Spec.Components, name) {
+			continue
+		}
+		components = append(components, vzapi.BackupComponentStatus{Name: name, Phase: vzapi.BackupPhasePending})
+	}
+	now := metav1.Now()
+	restore.Status.Phase = vzapi.BackupPhaseInProgress
+	restore.Status.Message = ""
+	restore.Status.StartTime = &now
+	restore.Status.Components = components
+	log.Oncef("VerrazzanoRestore %s/%s has started", restore.Namespace, restore.Name)
+	return r.updateRestoreStatus(ctx, log, restore, ctrl.Result{Requeue: true})
+}
+
+// isComponentReady returns true if the component is ready in the Verrazzano resource of the namespace
+func (r *VerrazzanoRestoreReconciler) isComponentReady(ctx context.Context, namespace string, name string) (bool, error) {
+	vz, err := getVerrazzano(ctx, r.Client, namespace)
+	if err != nil || vz == nil {
+		return false, err
+	}
+	status, ok := vz.Status.Components[name]
+	return ok && status.State == vzapi.CompStateReady, nil
+}
+
+// setRestorePending saves the reason the restore has not started, and requeues the reconcile
+func (r *VerrazzanoRestoreReconciler) setRestorePending(ctx context.Context, log vzlog.VerrazzanoLogger, restore *vzapi.VerrazzanoRestore, message string) (ctrl.Result, error) {
+	if restore.Status.Phase == vzapi.BackupPhasePending && restore.Status.Message == message {
+		return newRequeueWithDelay(), nil
+	}
+	restore.Status.Phase = vzapi.BackupPhasePending
+	restore.Status.Message = message
+	return r.updateRestoreStatus(ctx, log, restore, newRequeueWithDelay())
+}
+
+// finishRestore deletes the ClusterRoleBinding of the jobs and saves the final phase of the restore
+func (r *VerrazzanoRestoreReconciler) finishRestore(ctx context.Context, log vzlog.VerrazzanoLogger, restore *vzapi.VerrazzanoRestore, phase vzapi.BackupPhase, message string) (ctrl.Result, error) {
+	if err := deleteRoleBinding(ctx, r.Client, client.ObjectKeyFromObject(restore)); err != nil {
+		log.Errorf("Failed to delete the ClusterRoleBinding of VerrazzanoRestore %s/%s: %v", restore.Namespace, restore.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	if phase == vzapi.BackupPhaseFailed {
+		log.Errorf("VerrazzanoRestore %s/%s failed: %s", restore.Namespace, restore.Name, message)
+	}
+	now := metav1.Now()
+	restore.Status.Phase = phase
+	restore.Status.Message = message
+	restore.Status.CompletionTime = &now
+	return r.updateRestoreStatus(ctx, log, restore, ctrl.Result{})
+}
+
+// updateRestoreStatus saves the status of the restore and returns the result, the reconcile is requeued if the status
+// cannot be saved
+func (r *VerrazzanoRestoreReconciler) updateRestoreStatus(ctx context.Context, log vzlog.VerrazzanoLogger, restore *vzapi.VerrazzanoRestore, result ctrl.Result) (ctrl.Result, error) {
+	if err := r.Status().Update(ctx, restore); err != nil {
+		log.Errorf("Failed to update the status of VerrazzanoRestore %s/%s: %v", restore.Namespace, restore.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	return result, nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestReconcileRestore tests the VerrazzanoRestoreReconciler Reconcile func
+// GIVEN a VerrazzanoRestore of a completed backup of the verrazzano and keycloak components
+// WHEN Reconcile is called as the jobs complete and the components become ready
+// THEN ensure the verrazzano state is restored first, and keycloak is restored once it is ready
+func TestReconcileRestore(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	backup := newTestBackup("backup", nil)
+	backup.Status.Phase = vzapi.BackupPhaseCompleted
+	backup.Status.Components = []vzapi.BackupComponentStatus{
+		{Name: "verrazzano", Phase: vzapi.BackupPhaseCompleted},
+		{Name: "keycloak", Phase: vzapi.BackupPhaseCompleted},
+		{Name: "rancher", Phase: vzapi.BackupPhaseSkipped},
+	}
+	restore := newTestRestore("restore", "backup")
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(append(newTestSecrets(), backup, restore)...).Build()
+	reconciler := VerrazzanoRestoreReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	// The restore starts with the completed components of the backup
+	result := reconcileRestore(t, reconciler, "restore")
+	asserts.True(result.Requeue)
+	actual := getTestRestore(t, c, "restore")
+	asserts.Equal(vzapi.BackupPhaseInProgress, actual.Status.Phase)
+	asserts.Equal([]vzapi.BackupComponentStatus{
+		{Name: "verrazzano", Phase: vzapi.BackupPhasePending},
+		{Name: "keycloak", Phase: vzapi.BackupPhasePending},
+	}, actual.Status.Components)
+
+	// The verrazzano state is restored without waiting for a Verrazzano resource
+	reconcileRestore(t, reconciler, "restore")
+	job := getTestJob(t, c, "restore-verrazzano-restore")
+	asserts.Equal("restore", job.Labels[restoreLabel])
+	asserts.Equal([]string{"cp", "--recursive", "backup/bucket/prefix/backup/verrazzano/", "/staging/"}, job.Spec.Template.Spec.InitContainers[0].Args)
+	asserts.Equal([]string{"apply", "-f", "/staging"}, job.Spec.Template.Spec.Containers[0].Args)
+
+	// The job that applies the state is bound to the restricted ClusterRole while the restore runs
+	binding := &rbacv1.ClusterRoleBinding{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Name: getBindingName(client.ObjectKeyFromObject(restore))}, binding))
+	asserts.Equal(serviceAccountName, binding.RoleRef.Name)
+	asserts.Equal(testNamespace, binding.Subjects[0].Namespace)
+	role := &rbacv1.ClusterRole{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Name: serviceAccountName}, role))
+	asserts.Contains(role.Rules, rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"namespaces", "secrets"},
+		Verbs:     []string{"get", "list", "create", "patch"},
+	})
+	completeTestJob(t, c, job.Name, true)
+	reconcileRestore(t, reconciler, "restore")
+
+	// Keycloak is not restored until it is ready
+	vz := newTestVerrazzano()
+	vz.Status.Components = vzapi.ComponentStatusMap{"keycloak": {Name: "keycloak", State: vzapi.CompStateInstalling}}
+	asserts.NoError(c.Create(context.TODO(), vz))
+	result = reconcileRestore(t, reconciler, "restore")
+	asserts.True(result.Requeue)
+	asserts.True(errors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "restore-keycloak-restore"}, &batchv1.Job{})))
+
+	vz.Status.Components["keycloak"].State = vzapi.CompStateReady
+	asserts.NoError(c.Status().Update(context.TODO(), vz))
+	reconcileRestore(t, reconciler, "restore")
+	job = getTestJob(t, c, "restore-keycloak-restore")
+	completeTestJob(t, c, job.Name, true)
+	reconcileRestore(t, reconciler, "restore")
+	reconcileRestore(t, reconciler, "restore")
+	actual = getTestRestore(t, c, "restore")
+	asserts.Equal(vzapi.BackupPhaseCompleted, actual.Status.Phase)
+	asserts.NotNil(actual.Status.CompletionTime)
+
+	// The binding is deleted once the restore finishes
+	asserts.True(errors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Name: binding.Name}, &rbacv1.ClusterRoleBinding{})))
+}
+
+// TestReconcileDeletedRestore tests the VerrazzanoRestoreReconciler Reconcile func
+// GIVEN the ClusterRoleBinding of a VerrazzanoRestore that was deleted before it finished
+// WHEN Reconcile is called
+// THEN ensure the ClusterRoleBinding is deleted
+func TestReconcileDeletedRestore(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	name := types.NamespacedName{Namespace: testNamespace, Name: "restore"}
+	binding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: getBindingName(name)}}
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(binding).Build()
+	reconciler := VerrazzanoRestoreReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	reconcileRestore(t, reconciler, "restore")
+	asserts.True(errors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Name: binding.Name}, &rbacv1.ClusterRoleBinding{})))
+}
+
+// TestReconcileRestoreBackupNotCompleted tests the VerrazzanoRestoreReconciler Reconcile func
+// GIVEN VerrazzanoRestores of a backup in progress, a failed backup and a missing backup
+// WHEN Reconcile is called
+// THEN ensure the restore of the backup in progress is pending, and the other restores are failed
+func TestReconcileRestoreBackupNotCompleted(t *testing.T) {
+	asserts := assert.New(t)
+	setupTest(t)
+
+	inProgress := newTestBackup("in-progress", nil)
+	inProgress.Status.Phase = vzapi.BackupPhaseInProgress
+	failed := newTestBackup("failed", nil)
+	failed.Status.Phase = vzapi.BackupPhaseFailed
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(inProgress, failed,
+		newTestRestore("pending", "in-progress"), newTestRestore("failed", "failed"), newTestRestore("missing", "missing")).Build()
+	reconciler := VerrazzanoRestoreReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	result := reconcileRestore(t, reconciler, "pending")
+	asserts.True(result.Requeue)
+	asserts.Equal(vzapi.BackupPhasePending, getTestRestore(t, c, "pending").Status.Phase)
+	for _, name := range []string{"failed", "missing"} {
+		reconcileRestore(t, reconciler, name)
+		actual := getTestRestore(t, c, name)
+		asserts.Equal(vzapi.BackupPhaseFailed, actual.Status.Phase, name)
+		asserts.NotEmpty(actual.Status.Message, name)
+	}
+}
+
+// newTestRestore returns a restore of a backup
+func newTestRestore(name string, backupName string) *vzapi.VerrazzanoRestore {
+	return &vzapi.VerrazzanoRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec:       vzapi.VerrazzanoRestoreSpec{BackupName: backupName},
+	}
+}
+
+// reconcileRestore reconciles a restore and returns the result
+func reconcileRestore(t *testing.T, reconciler VerrazzanoRestoreReconciler, name string) ctrl.Result {
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: name}})
+	assert.NoError(t, err)
+	return result
+}
+
+// getTestRestore returns a restore
+func getTestRestore(t *testing.T, c client.Client, name string) *vzapi.VerrazzanoRestore {
+	restore := &vzapi.VerrazzanoRestore{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: name}, restore))
+	return restore
+}
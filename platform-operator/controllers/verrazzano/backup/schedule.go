@@ -0,0 +1,169 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package backup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleAliases are the predefined schedules that can be used instead of a cron expression
+var scheduleAliases = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// cronField is the range of the values of a field of a cron expression
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+// cronFields are the minute, hour, day of month, month and day of week fields of a cron expression
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 6},
+}
+
+// schedule is a parsed cron expression, with the values that match each field
+type schedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+	// anyDayOfMonth and anyDayOfWeek are true if the day of month or the day of week is not restricted
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// parseSchedule parses a standard five field cron expression, or one of the predefined schedules such as @daily.
+// Each field is a list of values, ranges or steps, such as 0,30 or 1-5 or */15.
+func parseSchedule(expr string) (*schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if alias, ok := scheduleAliases[expr]; ok {
+		expr = alias
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("Failed, the schedule %q must have %d fields", expr, len(cronFields))
+	}
+	var values []map[int]bool
+	for i, field := range fields {
+		matches, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, matches)
+	}
+	return &schedule{
+		minutes:       values[0],
+		hours:         values[1],
+		daysOfMonth:   values[2],
+		months:        values[3],
+		daysOfWeek:    values[4],
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}, nil
+}
+
+// parseCronField returns the values that match a field of a cron expression
+func parseCronField(field string, spec cronField) (map[int]bool, error) {
+	matches := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("Failed, invalid step in the %s field %q", spec.name, field)
+			}
+			part = part[:i]
+		}
+		low, high := spec.min, spec.max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("Failed, invalid value in the %s field %q", spec.name, field)
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("Failed, invalid range in the %s field %q", spec.name, field)
+				}
+			}
+		}
+		// Sunday is either 0 or 7 in the day of week field
+		sunday := spec.name == "day of week"
+		maxValue := spec.max
+		if sunday {
+			maxValue = 7
+		}
+		if low < spec.min || high > maxValue || low > high {
+			return nil, fmt.Errorf("Failed, the %s field %q is out of range", spec.name, field)
+		}
+		for v := low; v <= high; v += step {
+			if sunday && v == 7 {
+				matches[0] = true
+				continue
+			}
+			matches[v] = true
+		}
+	}
+	return matches, nil
+}
+
+// next returns the first time after the given time that matches the schedule, in UTC.  A zero time is returned if
+// the schedule never matches, for example on the 30th of February.
+func (s *schedule) next(after time.Time) time.Time {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	// Give up after five years, which covers the leap years
+	end := t.AddDate(5, 0, 0)
+	for t.Before(end) {
+		if !s.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchesDay returns true if the day matches the schedule.  Like cron, if both the day of month and the day of week
+// are restricted, the day matches if either of them matches.
+func (s *schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth[t.Day()]
+	dayOfWeek := s.daysOfWeek[int(t.Weekday())]
+	switch {
+	case s.anyDayOfMonth && s.anyDayOfWeek:
+		return true
+	case s.anyDayOfMonth:
+		return dayOfWeek
+	case s.anyDayOfWeek:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestScheduleNext tests the next func of a schedule
+// GIVEN cron expressions and predefined schedules
+// WHEN next is called
+// THEN ensure the first matching time after the given time is returned
+func TestScheduleNext(t *testing.T) {
+	// Saturday, the 14th of May 2022
+	after := time.Date(2022, time.May, 14, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{expr: "@hourly", expected: time.Date(2022, time.May, 14, 11, 0, 0, 0, time.UTC)},
+		{expr: "@daily", expected: time.Date(2022, time.May, 15, 0, 0, 0, 0, time.UTC)},
+		{expr: "@weekly", expected: time.Date(2022, time.May, 15, 0, 0, 0, 0, time.UTC)},
+		{expr: "@monthly", expected: time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", expected: time.Date(2022, time.May, 14, 10, 45, 0, 0, time.UTC)},
+		{expr: "0 2 * * 1-5", expected: time.Date(2022, time.May, 16, 2, 0, 0, 0, time.UTC)},
+		{expr: "30 4 1,15 * *", expected: time.Date(2022, time.May, 15, 4, 30, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", expected: time.Date(2022, time.May, 15, 0, 0, 0, 0, time.UTC)},
+		// Either the day of month or the day of week matches when both are restricted
+		{expr: "0 0 20 * 1", expected: time.Date(2022, time.May, 16, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", expected: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", expected: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			sched, err := parseSchedule(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, sched.next(after))
+		})
+	}
+}
+
+// TestParseScheduleInvalid tests the parseSchedule func
+// GIVEN invalid cron expressions
+// WHEN parseSchedule is called
+// THEN ensure an error is returned
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, expr := range []string{"", "@yearly", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := parseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package backup
+
+import (
+	"bytes"
+
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// getComponentState returns the state file of a component whose state is made of Kubernetes resources
+func getComponentState(ctx spi.ComponentContext, scheme *runtime.Scheme, component backupComponent) (map[string][]byte, error) {
+	resources, err := component.spec.GetResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	content, err := toYAML(scheme, resources)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{getStateKey(component): content}, nil
+}
+
+// toYAML returns the YAML documents of the objects, without their status and the metadata set by the API server,
+// so that they can be applied to another cluster
+func toYAML(scheme *runtime.Scheme, objects []client.Object) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, obj := range objects {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil {
+				return nil, err
+			}
+			u = &unstructured.Unstructured{Object: content}
+			gvks, _, err := scheme.ObjectKinds(obj)
+			if err != nil {
+				return nil, err
+			}
+			u.SetGroupVersionKind(gvks[0])
+		}
+		for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields", "ownerReferences", "finalizers", "selfLink"} {
+			unstructured.RemoveNestedField(u.Object, "metadata", field)
+		}
+		unstructured.RemoveNestedField(u.Object, "status")
+		content, err := yaml.Marshal(u.Object)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+		buf.Write(content)
+	}
+	return buf.Bytes(), nil
+}
+
+// getStateKey returns the name of the state file of a component
+func getStateKey(component backupComponent) string {
+	return component.Name() + ".yaml"
+}
+
+// getStateKeys returns the state files of a component whose state is made of Kubernetes resources
+func getStateKeys(component backupComponent) []string {
+	if component.spec.Method == spi.BackupMethodResources {
+		return []string{getStateKey(component)}
+	}
+	return nil
+}
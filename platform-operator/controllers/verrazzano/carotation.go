@@ -0,0 +1,293 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	"github.com/verrazzano/verrazzano/pkg/mcconstants"
+	vzstring "github.com/verrazzano/verrazzano/pkg/string"
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/clusters"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/certmanager"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/certificate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultCAPropagationTimeout is how long the CA rotation waits for the managed clusters to get the new CA bundle,
+// unless it is set with the CA propagation timeout annotation
+const defaultCAPropagationTimeout = 24 * time.Hour
+
+// The cert-manager functions used by the CA rotation, overridden by unit tests
+var (
+	getClusterIssuerCAFunc         = certmanager.GetClusterIssuerCA
+	reissueCertificatesFunc        = certmanager.ReissueCertificates
+	getCertificatesNotReissuedFunc = certmanager.GetCertificatesNotReissued
+)
+
+// checkCARotation advances the staged rotation of the CA of the Verrazzano ClusterIssuer.  The rotation is started by
+// the cert-manager component, which saves the previous CA in the CA rotation bundle when the CA changes.  The rotation
+// then goes through the stages:
+//   - PublishingBundle: the new CA is added to the CA rotation bundle, and the bundle is published in the local CA
+//     bundle and in the registration secrets of the managed clusters
+//   - ReissuingCertificates: the certificates are re-issued by the new CA
+//   - PropagatingBundle: the managed cluster agents connect to get the registration secret with the new CA, or the
+//     CA propagation timeout passes
+//   - RemovingPreviousCA: the CA rotation bundle is deleted, and the previous CA is removed from the published bundles
+//
+// The progress of the rotation is in the Verrazzano status.
+func (r *Reconciler) checkCARotation(vzctx vzcontext.VerrazzanoContext) error {
+	cr := vzctx.ActualCR
+	log := vzctx.Log
+	rotationBundle, err := certificate.GetCARotationBundle(r.Client)
+	if err != nil {
+		log.Errorf("Failed getting the CA rotation bundle: %v", err)
+		return err
+	}
+	if rotationBundle == nil && (cr.Status.CARotation == nil || cr.Status.CARotation.Stage == vzapi.CARotationComplete) {
+		return nil
+	}
+
+	var rotation vzapi.CARotationStatus
+	if cr.Status.CARotation != nil {
+		rotation = *cr.Status.CARotation
+	}
+	if rotationBundle != nil && (cr.Status.CARotation == nil || rotation.Stage == vzapi.CARotationComplete) {
+		log.Infof("Starting the staged rotation of the CA of the Verrazzano ClusterIssuer")
+		now := formatStatusTime(time.Now())
+		rotation = vzapi.CARotationStatus{
+			Stage:      vzapi.CARotationPublishingBundle,
+			PreviousCA: strings.Join(certificate.GetCACommonNames(rotationBundle), ", "),
+			StartTime:  now,
+			StageTime:  now,
+		}
+	}
+
+	if rotationBundle == nil && rotation.Stage != vzapi.CARotationRemovingPreviousCA {
+		// The CA rotation bundle has been deleted, only the removal of the previous CA from the published bundles remains
+		setCARotationStage(&rotation, vzapi.CARotationRemovingPreviousCA, "Waiting for the previous CA to be removed from the CA bundle")
+	}
+
+	spiCtx, err := spi.NewContext(log, r.Client, cr, r.DryRun)
+	if err != nil {
+		log.Errorf("Failed to create component context: %v", err)
+		return err
+	}
+	compContext := spiCtx.Init(certmanager.ComponentName)
+
+	switch rotation.Stage {
+	case vzapi.CARotationPublishingBundle:
+		err = r.publishCABundle(compContext, &rotation, rotationBundle)
+	case vzapi.CARotationReissuingCertificates:
+		err = r.waitForReissuedCertificates(compContext, &rotation)
+	case vzapi.CARotationPropagatingBundle:
+		err = r.propagateCABundle(compContext, &rotation)
+	case vzapi.CARotationRemovingPreviousCA:
+		err = r.removePreviousCA(compContext, &rotation)
+	}
+	if err != nil {
+		return err
+	}
+
+	if cr.Status.CARotation == nil || !reflect.DeepEqual(*cr.Status.CARotation, rotation) {
+		cr.Status.CARotation = &rotation
+		return r.updateVerrazzanoStatus(log, cr)
+	}
+	return nil
+}
+
+// publishCABundle adds the new CA to the CA rotation bundle, and waits until the bundle is published before the
+// certificates are re-issued by the new CA
+func (r *Reconciler) publishCABundle(compContext spi.ComponentContext, rotation *vzapi.CARotationStatus, rotationBundle []byte) error {
+	newCA, err := getClusterIssuerCAFunc(compContext)
+	if err != nil {
+		compContext.Log().Errorf("Failed getting the CA of the Verrazzano ClusterIssuer: %v", err)
+		return err
+	}
+	if len(newCA) == 0 {
+		rotation.Message = "Waiting for the new CA to be issued"
+		return nil
+	}
+	if names := certificate.GetCACommonNames(newCA); len(names) > 0 {
+		// The first certificate is the CA that issues the certificates, the others are its chain
+		rotation.NewCA = names[0]
+	}
+	if !certificate.ContainsCABundle(rotationBundle, newCA) {
+		rotationBundle = certificate.AppendCABundle(rotationBundle, newCA)
+		secret := &corev1.Secret{}
+		if err := r.Get(context.TODO(), certificate.CARotationBundleSecretName, secret); err != nil {
+			return err
+		}
+		secret.Data[certificate.CABundleKey] = rotationBundle
+		if err := r.Update(context.TODO(), secret); err != nil {
+			return err
+		}
+	}
+
+	pending, err := r.getCABundleConsumers(func(caBundle []byte) bool {
+		return !certificate.ContainsCABundle(caBundle, rotationBundle)
+	})
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		rotation.Message = fmt.Sprintf("Waiting for the CA bundle to be published to %s", strings.Join(pending, ", "))
+		return nil
+	}
+
+	compContext.Log().Infof("The CA bundle has been published, re-issuing the certificates with the new CA %s", rotation.NewCA)
+	if err := reissueCertificatesFunc(compContext); err != nil {
+		compContext.Log().Errorf("Failed requesting the re-issue of the certificates: %v", err)
+		return err
+	}
+	setCARotationStage(rotation, vzapi.CARotationReissuingCertificates, "Waiting for the certificates to be re-issued")
+	return nil
+}
+
+// waitForReissuedCertificates waits until all the certificates of the Verrazzano ClusterIssuer have been re-issued
+// by the new CA
+func (r *Reconciler) waitForReissuedCertificates(compContext spi.ComponentContext, rotation *vzapi.CARotationStatus) error {
+	pending, err := getCertificatesNotReissuedFunc(compContext, rotation.NewCA)
+	if err != nil {
+		compContext.Log().Errorf("Failed checking the re-issued certificates: %v", err)
+		return err
+	}
+	if len(pending) > 0 {
+		rotation.Message = fmt.Sprintf("Waiting for the certificates to be re-issued: %s", strings.Join(pending, ", "))
+		return nil
+	}
+	compContext.Log().Infof("The certificates have been re-issued by the new CA %s", rotation.NewCA)
+	setCARotationStage(rotation, vzapi.CARotationPropagatingBundle, "Waiting for the managed clusters to get the CA bundle")
+	return nil
+}
+
+// propagateCABundle waits until the agent of every managed cluster has connected since the certificates were
+// re-issued, so that the managed clusters have the registration secret with the new CA.  The previous CA is removed
+// without waiting for the managed clusters that have not connected when the CA propagation timeout passes.
+func (r *Reconciler) propagateCABundle(compContext spi.ComponentContext, rotation *vzapi.CARotationStatus) error {
+	stageTime, err := time.Parse(time.RFC3339, rotation.StageTime)
+	if err != nil {
+		stageTime = time.Now()
+	}
+	deadline := stageTime.Add(getCAPropagationTimeout(compContext.Log(), compContext.ActualCR()))
+	vmcList := &clustersv1alpha1.VerrazzanoManagedClusterList{}
+	if err := r.List(context.TODO(), vmcList, clipkg.InNamespace(constants.VerrazzanoMultiClusterNamespace)); err != nil && !errors.IsNotFound(err) {
+		compContext.Log().Errorf("Failed listing the managed clusters: %v", err)
+		return err
+	}
+	var pending []string
+	for _, vmc := range vmcList.Items {
+		if vmc.Status.LastAgentConnectTime == nil || vmc.Status.LastAgentConnectTime.Time.Before(stageTime) {
+			pending = append(pending, vmc.Name)
+		}
+	}
+	message := "Waiting for the previous CA to be removed from the CA bundle"
+	if len(pending) > 0 {
+		if time.Now().Before(deadline) {
+			rotation.Message = fmt.Sprintf("Waiting until %s for the agents of the managed clusters to connect: %s",
+				formatStatusTime(deadline), strings.Join(pending, ", "))
+			return nil
+		}
+		compContext.Log().Infof("The CA propagation timeout has passed before the agents of the managed clusters %s connected, removing the previous CA %s",
+			strings.Join(pending, ", "), rotation.PreviousCA)
+		message = fmt.Sprintf("%s, the agents of the managed clusters did not connect before the CA propagation timeout: %s",
+			message, strings.Join(pending, ", "))
+	} else {
+		compContext.Log().Infof("The managed clusters have the new CA, removing the previous CA %s", rotation.PreviousCA)
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: certificate.CARotationBundleSecretName.Name, Namespace: certificate.CARotationBundleSecretName.Namespace}}
+	if err := r.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	setCARotationStage(rotation, vzapi.CARotationRemovingPreviousCA, message)
+	return nil
+}
+
+// getCAPropagationTimeout returns how long the CA rotation waits for the managed clusters to get the new CA bundle
+func getCAPropagationTimeout(log vzlog.VerrazzanoLogger, cr *vzapi.Verrazzano) time.Duration {
+	value, ok := cr.Annotations[constants.CAPropagationTimeoutAnnotation]
+	if !ok {
+		return defaultCAPropagationTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		log.Errorf("Invalid value %s of the %s annotation, using the default CA propagation timeout %v", value,
+			constants.CAPropagationTimeoutAnnotation, defaultCAPropagationTimeout)
+		return defaultCAPropagationTimeout
+	}
+	return timeout
+}
+
+// removePreviousCA waits until the previous CA has been removed from the published CA bundles
+func (r *Reconciler) removePreviousCA(compContext spi.ComponentContext, rotation *vzapi.CARotationStatus) error {
+	previous := strings.Split(rotation.PreviousCA, ", ")
+	pending, err := r.getCABundleConsumers(func(caBundle []byte) bool {
+		for _, name := range certificate.GetCACommonNames(caBundle) {
+			if name != rotation.NewCA && vzstring.SliceContainsString(previous, name) {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		rotation.Message = fmt.Sprintf("Waiting for the previous CA to be removed from %s", strings.Join(pending, ", "))
+		return nil
+	}
+	compContext.Log().Infof("The rotation of the CA of the Verrazzano ClusterIssuer to %s is complete", rotation.NewCA)
+	setCARotationStage(rotation, vzapi.CARotationComplete, "")
+	return nil
+}
+
+// getCABundleConsumers returns the namespace/name of the published CA bundles, the local CA bundle and the
+// registration secrets of the managed clusters, that match the filter
+func (r *Reconciler) getCABundleConsumers(filter func(caBundle []byte) bool) ([]string, error) {
+	names := []types.NamespacedName{{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: constants.VerrazzanoLocalCABundleSecret}}
+	vmcList := &clustersv1alpha1.VerrazzanoManagedClusterList{}
+	if err := r.List(context.TODO(), vmcList, clipkg.InNamespace(constants.VerrazzanoMultiClusterNamespace)); err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	for _, vmc := range vmcList.Items {
+		names = append(names, types.NamespacedName{Namespace: vmc.Namespace, Name: clusters.GetRegistrationSecretName(vmc.Name)})
+	}
+
+	var matches []string
+	for _, name := range names {
+		secret := &corev1.Secret{}
+		if err := r.Get(context.TODO(), name, secret); err != nil {
+			if errors.IsNotFound(err) {
+				// The bundle isn't published in this secret yet, or multicluster isn't used
+				continue
+			}
+			return nil, err
+		}
+		if filter(secret.Data[mcconstants.AdminCaBundleKey]) {
+			matches = append(matches, fmt.Sprintf("%s/%s", name.Namespace, name.Name))
+		}
+	}
+	return matches, nil
+}
+
+// setCARotationStage moves the CA rotation to the next stage
+func setCARotationStage(rotation *vzapi.CARotationStatus, stage vzapi.CARotationStage, message string) {
+	rotation.Stage = stage
+	rotation.StageTime = formatStatusTime(time.Now())
+	rotation.Message = message
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/clusters"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/certificate"
+	"github.com/verrazzano/verrazzano/platform-operator/mocks"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// expectNoCARotation adds the expected call to get the CA rotation bundle to a mock client, there is no CA rotation
+// in progress
+func expectNoCARotation(mock *mocks.MockClient) {
+	mock.EXPECT().
+		Get(gomock.Any(), certificate.CARotationBundleSecretName, gomock.Not(gomock.Nil())).
+		Return(errors.NewNotFound(schema.GroupResource{Group: "", Resource: "Secret"}, certificate.CARotationBundleSecretName.Name)).
+		AnyTimes()
+}
+
+// newTestCA returns a PEM encoded self-signed CA certificate with the common name
+func newTestCA(t *testing.T, commonName string) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caBytes})
+}
+
+// TestCheckCARotation tests the checkCARotation method for the following use case
+// GIVEN a CA rotation bundle with the previous CA of the Verrazzano ClusterIssuer
+// WHEN checkCARotation is called as the bundle is published, the certificates are re-issued and the managed cluster
+// agent connects
+// THEN the rotation goes through each stage until the previous CA is removed and the rotation is complete
+func TestCheckCARotation(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	oldCA := newTestCA(t, "verrazzano-root-ca-old")
+	newCA := newTestCA(t, "verrazzano-root-ca-new")
+	bothCAs := certificate.AppendCABundle(oldCA, newCA)
+
+	origGetClusterIssuerCAFunc := getClusterIssuerCAFunc
+	origReissueCertificatesFunc := reissueCertificatesFunc
+	origGetCertificatesNotReissuedFunc := getCertificatesNotReissuedFunc
+	defer func() {
+		getClusterIssuerCAFunc = origGetClusterIssuerCAFunc
+		reissueCertificatesFunc = origReissueCertificatesFunc
+		getCertificatesNotReissuedFunc = origGetCertificatesNotReissuedFunc
+	}()
+	getClusterIssuerCAFunc = func(_ spi.ComponentContext) ([]byte, error) {
+		return newCA, nil
+	}
+	reissued := 0
+	reissueCertificatesFunc = func(_ spi.ComponentContext) error {
+		reissued++
+		return nil
+	}
+	var notReissued []string
+	getCertificatesNotReissuedFunc = func(_ spi.ComponentContext, caCommonName string) ([]string, error) {
+		asserts.Equal("verrazzano-root-ca-new", caCommonName)
+		return notReissued, nil
+	}
+
+	localBundle := types.NamespacedName{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: constants.VerrazzanoLocalCABundleSecret}
+	registration := types.NamespacedName{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: clusters.GetRegistrationSecretName("managed1")}
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-rotation"},
+		Status:     vzapi.VerrazzanoStatus{State: vzapi.VzStateReady},
+	}
+	vmc := &clustersv1alpha1.VerrazzanoManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: "managed1"},
+	}
+	scheme := newCertificateScheme()
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		vz,
+		vmc,
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: certificate.CARotationBundleSecretName.Namespace, Name: certificate.CARotationBundleSecretName.Name},
+			Data:       map[string][]byte{certificate.CABundleKey: oldCA},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: localBundle.Namespace, Name: localBundle.Name},
+			Data:       map[string][]byte{"ca-bundle": oldCA},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: registration.Namespace, Name: registration.Name},
+			Data:       map[string][]byte{"ca-bundle": oldCA},
+		},
+	).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	vzctx := vzcontext.VerrazzanoContext{Log: vzlog.DefaultLogger(), Client: c, ActualCR: vz}
+	setBundle := func(name types.NamespacedName, bundle []byte) {
+		secret := &corev1.Secret{}
+		asserts.NoError(c.Get(context.TODO(), name, secret))
+		secret.Data["ca-bundle"] = bundle
+		asserts.NoError(c.Update(context.TODO(), secret))
+	}
+
+	// The new CA is added to the rotation bundle, which hasn't been published yet
+	asserts.NoError(reconciler.checkCARotation(vzctx))
+	asserts.Equal(vzapi.CARotationPublishingBundle, vz.Status.CARotation.Stage)
+	asserts.Equal("verrazzano-root-ca-old", vz.Status.CARotation.PreviousCA)
+	asserts.Equal("verrazzano-root-ca-new", vz.Status.CARotation.NewCA)
+	asserts.Contains(vz.Status.CARotation.Message, localBundle.String())
+	asserts.Contains(vz.Status.CARotation.Message, registration.String())
+	bundle, err := certificate.GetCARotationBundle(c)
+	asserts.NoError(err)
+	asserts.Equal(bothCAs, bundle)
+	asserts.Equal(0, reissued)
+
+	// Once the bundle is published the certificates are re-issued
+	setBundle(localBundle, bothCAs)
+	setBundle(registration, bothCAs)
+	notReissued = []string{"verrazzano-system/verrazzano-tls"}
+	asserts.NoError(reconciler.checkCARotation(vzctx))
+	asserts.Equal(vzapi.CARotationReissuingCertificates, vz.Status.CARotation.Stage)
+	asserts.Equal(1, reissued)
+	asserts.NoError(reconciler.checkCARotation(vzctx))
+	asserts.Equal(vzapi.CARotationReissuingCertificates, vz.Status.CARotation.Stage)
+	asserts.Contains(vz.Status.CARotation.Message, "verrazzano-system/verrazzano-tls")
+
+	// Once the certificates are re-issued, wait for the managed cluster agent to connect
+	notReissued = nil
+	asserts.NoError(reconciler.checkCARotation(vzctx))
+	asserts.Equal(vzapi.CARotationPropagatingBundle, vz.Status.CARotation.Stage)
+	asserts.NoError(reconciler.checkCARotation(vzctx))
+	asserts.Equal(vzapi.CARotationPropagatingBundle, vz.Status.CARotation.Stage)
+	asserts.Contains(vz.Status.CARotation.Message, "managed1")
+
+	// Once the agent has connected the rotation bundle is deleted
+	vmc.Status.LastAgentConnectTime = &metav1.Time{Time: time.Now().Add(time.Minute)}
+	asserts.NoError(c.Status().Update(context.TODO(), vmc))
+	asserts.NoError(reconciler.checkCARotation(vzctx))
+	asserts.Equal(vzapi.CARotationRemovingPreviousCA, vz.Status.CARotation.Stage)
+	bundle, err = certificate.GetCARotationBundle(c)
+	asserts.NoError(err)
+	asserts.Nil(bundle)
+
+	// The rotation is complete once the previous CA is no longer published
+	asserts.NoError(reconciler.checkCARotation(vzctx))
+	asserts.Equal(vzapi.CARotationRemovingPreviousCA, vz.Status.CARotation.Stage)
+	setBundle(localBundle, newCA)
+	setBundle(registration, newCA)
+	asserts.NoError(reconciler.checkCARotation(vzctx))
+	asserts.Equal(vzapi.CARotationComplete, vz.Status.CARotation.Stage)
+
+	actual := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test-rotation"}, actual))
+	asserts.Equal(vzapi.CARotationComplete, actual.Status.CARotation.Stage)
+	asserts.Equal("verrazzano-root-ca-new", actual.Status.CARotation.NewCA)
+}
+
+// TestPropagateCABundleTimeout tests the propagateCABundle method for the following use case
+// GIVEN a CA rotation that is propagating the CA bundle to a managed cluster whose agent has not connected
+// WHEN the CA propagation timeout has not passed, and then has passed
+// THEN the rotation waits for the managed cluster, and then removes the previous CA without waiting
+func TestPropagateCABundleTimeout(t *testing.T) {
+	asserts := assert.New(t)
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "verrazzano",
+			Name:        "test-rotation",
+			Annotations: map[string]string{constants.CAPropagationTimeoutAnnotation: "3h"},
+		},
+	}
+	scheme := newCertificateScheme()
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		vz,
+		&clustersv1alpha1.VerrazzanoManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: "managed1"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: certificate.CARotationBundleSecretName.Namespace, Name: certificate.CARotationBundleSecretName.Name},
+			Data:       map[string][]byte{certificate.CABundleKey: newTestCA(t, "verrazzano-root-ca-old")},
+		},
+	).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	rotation := &vzapi.CARotationStatus{
+		Stage:      vzapi.CARotationPropagatingBundle,
+		PreviousCA: "verrazzano-root-ca-old",
+		StageTime:  formatStatusTime(time.Now().Add(-2 * time.Hour)),
+	}
+
+	// The timeout has not passed, the rotation waits for the managed cluster
+	asserts.NoError(reconciler.propagateCABundle(spi.NewFakeContext(c, vz, false), rotation))
+	asserts.Equal(vzapi.CARotationPropagatingBundle, rotation.Stage)
+	asserts.Contains(rotation.Message, "managed1")
+	bundle, err := certificate.GetCARotationBundle(c)
+	asserts.NoError(err)
+	asserts.NotNil(bundle)
+
+	// The timeout has passed, the previous CA is removed and the managed cluster is in the status message
+	vz.Annotations[constants.CAPropagationTimeoutAnnotation] = "1h"
+	asserts.NoError(reconciler.propagateCABundle(spi.NewFakeContext(c, vz, false), rotation))
+	asserts.Equal(vzapi.CARotationRemovingPreviousCA, rotation.Stage)
+	asserts.Contains(rotation.Message, "managed1")
+	bundle, err = certificate.GetCARotationBundle(c)
+	asserts.NoError(err)
+	asserts.Nil(bundle)
+}
+
+// TestGetCAPropagationTimeout tests the getCAPropagationTimeout method
+// GIVEN a Verrazzano resource with no CA propagation timeout annotation, a valid annotation, or an invalid annotation
+// WHEN getCAPropagationTimeout is called
+// THEN the timeout from the annotation is returned if it is valid, otherwise the default timeout is returned
+func TestGetCAPropagationTimeout(t *testing.T) {
+	vz := &vzapi.Verrazzano{}
+	assert.Equal(t, defaultCAPropagationTimeout, getCAPropagationTimeout(vzlog.DefaultLogger(), vz))
+
+	vz.Annotations = map[string]string{constants.CAPropagationTimeoutAnnotation: "30m"}
+	assert.Equal(t, 30*time.Minute, getCAPropagationTimeout(vzlog.DefaultLogger(), vz))
+
+	vz.Annotations[constants.CAPropagationTimeoutAnnotation] = "soon"
+	assert.Equal(t, defaultCAPropagationTimeout, getCAPropagationTimeout(vzlog.DefaultLogger(), vz))
+}
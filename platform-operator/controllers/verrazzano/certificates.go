@@ -0,0 +1,342 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	certapiv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/certmanager"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/certificate"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	adminv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// certificateCheckInterval is the minimum time between the checks of the certificates of the Verrazzano installation
+const certificateCheckInterval = 10 * time.Minute
+
+// defaultCertificateExpiryThreshold is how long before they expire that certificates are reported as expiring soon,
+// when the threshold isn't set in the Verrazzano resource
+const defaultCertificateExpiryThreshold = 14 * 24 * time.Hour
+
+// renewAllCertificates is the value of the renew certificates annotation that renews all the certificates
+const renewAllCertificates = "all"
+
+const (
+	verrazzanoClusterIssuerName = "verrazzano-cluster-issuer"
+	ingressTraitCertSuffix      = "-cert"
+	appOperatorWebhookName      = "verrazzano-application-ingresstrait-validator"
+)
+
+// webhookConfigurationNames are the ValidatingWebhookConfigurations of the Verrazzano operators, the webhook serving
+// certificates are generated by the operators when they start
+var webhookConfigurationNames = []string{certificate.OperatorName, appOperatorWebhookName}
+
+// lastCertificateCheckMap has the time of the last certificate check of each Verrazzano resource, keyed by the
+// resource namespace and name
+var lastCertificateCheckMap = map[string]time.Time{}
+
+// renewCertificateFunc requests a new certificate for a cert-manager Certificate, overridden by unit tests
+var renewCertificateFunc = certmanager.RenewCertificate
+
+// getCertificateExpiryThreshold returns how long before they expire that certificates are reported as expiring soon
+func getCertificateExpiryThreshold(cr *vzapi.Verrazzano) time.Duration {
+	if cr.Spec.CertificateExpiryThreshold != nil {
+		return cr.Spec.CertificateExpiryThreshold.Duration
+	}
+	return defaultCertificateExpiryThreshold
+}
+
+// checkCertificates periodically updates the inventory of the certificates of the Verrazzano installation in the
+// status.  The CertificatesExpiringSoon condition is set while certificates expire within the certificate expiry
+// threshold, and removed once they have been renewed.  The certificates requested by the renew certificates
+// annotation are renewed first, then the annotation is removed.
+func (r *Reconciler) checkCertificates(vzctx vzcontext.VerrazzanoContext) error {
+	cr := vzctx.ActualCR
+	key := getNSNKey(cr)
+	renew, renewRequested := cr.Annotations[vzconst.RenewCertificatesAnnotation]
+	if lastCheck, ok := lastCertificateCheckMap[key]; ok && !renewRequested && time.Since(lastCheck) < certificateCheckInterval {
+		return nil
+	}
+	spiCtx, err := spi.NewContext(vzctx.Log, r.Client, cr, r.DryRun)
+	if err != nil {
+		vzctx.Log.Errorf("Failed to create component context: %v", err)
+		return err
+	}
+
+	certs, err := r.getCertificateInventory(spiCtx)
+	if err == nil && renewRequested {
+		if err := r.renewCertificates(vzctx.Log, cr, certs, renew); err != nil {
+			return err
+		}
+		// Get the inventory again so that the renewed certificates are reported as renewing
+		certs, err = r.getCertificateInventory(spiCtx)
+	}
+	if err != nil {
+		// Try again at the next check, the certificate check doesn't block the reconcile
+		lastCertificateCheckMap[key] = time.Now()
+		return nil
+	}
+
+	statusChanged := !reflect.DeepEqual(cr.Status.Certificates, certs)
+	cr.Status.Certificates = certs
+	var expiring []string
+	for _, cert := range certs {
+		if cert.RenewalState == vzapi.CertificateExpiringSoon || cert.RenewalState == vzapi.CertificateExpired {
+			expiring = append(expiring, getCertificateKey(cert))
+		}
+	}
+	if len(expiring) > 0 {
+		msg := fmt.Sprintf("Certificates expire within %s: %s", getCertificateExpiryThreshold(cr), strings.Join(expiring, ", "))
+		if setVerrazzanoCondition(cr, vzapi.CondCertificatesExpiringSoon, msg) {
+			vzctx.Log.Progressf("%s", msg)
+			r.recordConditionEvent(cr, "", vzapi.CondCertificatesExpiringSoon, msg)
+			statusChanged = true
+		}
+	} else if hasCondition(cr.Status.Conditions, vzapi.CondCertificatesExpiringSoon) {
+		vzctx.Log.Infof("Certificates of the Verrazzano installation are no longer expiring soon")
+		cr.Status.Conditions = removeCondition(cr.Status.Conditions, vzapi.CondCertificatesExpiringSoon)
+		statusChanged = true
+	}
+	metrics.RecordCertificates(cr)
+
+	if statusChanged {
+		if err := r.updateVerrazzanoStatus(vzctx.Log, cr); err != nil {
+			return err
+		}
+	}
+	lastCertificateCheckMap[key] = time.Now()
+	return nil
+}
+
+// getCertificateInventory returns the certificates of the enabled components, of the IngressTrait gateways and of
+// the webhooks of the Verrazzano operators, sorted by source, namespace and name
+func (r *Reconciler) getCertificateInventory(spiCtx spi.ComponentContext) ([]vzapi.CertificateStatus, error) {
+	cr := spiCtx.ActualCR()
+	threshold := getCertificateExpiryThreshold(cr)
+	var certs []vzapi.CertificateStatus
+
+	if vzconfig.IsCertManagerEnabled(spiCtx.EffectiveCR()) {
+		componentCerts := map[types.NamespacedName]bool{}
+		for _, comp := range registry.GetComponents() {
+			if !comp.IsEnabled(spiCtx.EffectiveCR()) {
+				continue
+			}
+			compName := comp.Name()
+			for _, name := range comp.GetCertificateNames(spiCtx.Init(compName)) {
+				cert := &certapiv1.Certificate{}
+				if err := r.Get(context.TODO(), name, cert); err != nil {
+					if errors.IsNotFound(err) {
+						// The certificate hasn't been created yet
+						continue
+					}
+					spiCtx.Log().Errorf("Failed getting certificate %s: %v", name, err)
+					return nil, err
+				}
+				componentCerts[name] = true
+				certStatus := newCertificateStatus(cert, vzapi.CertificateSourceComponent, threshold)
+				certStatus.Component = compName
+				certs = append(certs, certStatus)
+			}
+		}
+
+		// The IngressTrait gateway certificates are issued by the Verrazzano ClusterIssuer in the istio-system namespace
+		certList := &certapiv1.CertificateList{}
+		if err := r.List(context.TODO(), certList, clipkg.InNamespace(vzconst.IstioSystemNamespace)); err != nil {
+			spiCtx.Log().Errorf("Failed listing certificates in namespace %s: %v", vzconst.IstioSystemNamespace, err)
+			return nil, err
+		}
+		for i := range certList.Items {
+			cert := &certList.Items[i]
+			if componentCerts[types.NamespacedName{Namespace: cert.Namespace, Name: cert.Name}] ||
+				cert.Spec.IssuerRef.Name != verrazzanoClusterIssuerName || !strings.HasSuffix(cert.Name, ingressTraitCertSuffix) {
+				continue
+			}
+			certs = append(certs, newCertificateStatus(cert, vzapi.CertificateSourceIngressTrait, threshold))
+		}
+	}
+
+	for _, name := range webhookConfigurationNames {
+		certStatus, err := r.getWebhookCertificateStatus(spiCtx.Log(), name, threshold)
+		if err != nil {
+			return nil, err
+		}
+		if certStatus != nil {
+			certs = append(certs, *certStatus)
+		}
+	}
+
+	sort.SliceStable(certs, func(i, j int) bool {
+		if certs[i].Source != certs[j].Source {
+			return certs[i].Source < certs[j].Source
+		}
+		return getCertificateKey(certs[i]) < getCertificateKey(certs[j])
+	})
+	return certs, nil
+}
+
+// newCertificateStatus returns the status of a cert-manager Certificate
+func newCertificateStatus(cert *certapiv1.Certificate, source vzapi.CertificateSource, threshold time.Duration) vzapi.CertificateStatus {
+	certStatus := vzapi.CertificateStatus{
+		Name:      cert.Name,
+		Namespace: cert.Namespace,
+		Source:    source,
+		Issuer:    fmt.Sprintf("%s/%s", cert.Spec.IssuerRef.Kind, cert.Spec.IssuerRef.Name),
+	}
+	var notAfter time.Time
+	if cert.Status.NotAfter != nil {
+		notAfter = cert.Status.NotAfter.Time
+		certStatus.NotAfter = formatStatusTime(notAfter)
+	}
+	if cert.Status.RenewalTime != nil {
+		certStatus.RenewalTime = formatStatusTime(cert.Status.RenewalTime.Time)
+	}
+
+	switch {
+	case hasCertificateCondition(cert, certapiv1.CertificateConditionIssuing):
+		certStatus.RenewalState = vzapi.CertificateRenewing
+	case !hasCertificateCondition(cert, certapiv1.CertificateConditionReady) || notAfter.IsZero():
+		certStatus.RenewalState = vzapi.CertificateNotReady
+	default:
+		certStatus.RenewalState = getExpiryState(notAfter, threshold)
+	}
+	return certStatus
+}
+
+// getWebhookCertificateStatus returns the status of the CA certificate of a webhook configuration, or nil if the
+// webhook configuration doesn't exist
+func (r *Reconciler) getWebhookCertificateStatus(log vzlog.VerrazzanoLogger, name string, threshold time.Duration) (*vzapi.CertificateStatus, error) {
+	webhook := &adminv1.ValidatingWebhookConfiguration{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: name}, webhook); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		log.Errorf("Failed getting ValidatingWebhookConfiguration %s: %v", name, err)
+		return nil, err
+	}
+	certStatus := &vzapi.CertificateStatus{
+		Name:         name,
+		Source:       vzapi.CertificateSourceWebhook,
+		RenewalState: vzapi.CertificateNotReady,
+	}
+	if len(webhook.Webhooks) == 0 {
+		return certStatus, nil
+	}
+	block, _ := pem.Decode(webhook.Webhooks[0].ClientConfig.CABundle)
+	if block == nil {
+		return certStatus, nil
+	}
+	caCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		log.Infof("Failed parsing the CA certificate of ValidatingWebhookConfiguration %s: %v", name, err)
+		return certStatus, nil
+	}
+	certStatus.Issuer = caCert.Issuer.CommonName
+	certStatus.NotAfter = formatStatusTime(caCert.NotAfter)
+	certStatus.RenewalState = getExpiryState(caCert.NotAfter, threshold)
+	return certStatus, nil
+}
+
+// renewCertificates renews the certificates requested by the renew certificates annotation, then removes the
+// annotation.  Webhook certificates can't be renewed this way, they are generated again when the operator restarts.
+func (r *Reconciler) renewCertificates(log vzlog.VerrazzanoLogger, cr *vzapi.Verrazzano, certs []vzapi.CertificateStatus, renew string) error {
+	requested := map[string]bool{}
+	for _, name := range strings.Split(renew, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			requested[name] = true
+		}
+	}
+	for _, cert := range certs {
+		key := getCertificateKey(cert)
+		if !requested[renewAllCertificates] && !requested[key] {
+			continue
+		}
+		delete(requested, key)
+		if cert.Source == vzapi.CertificateSourceWebhook {
+			log.Infof("Certificate of webhook %s is renewed by restarting the operator", cert.Name)
+			continue
+		}
+		log.Infof("Renewing certificate %s", key)
+		if err := renewCertificateFunc(log, types.NamespacedName{Namespace: cert.Namespace, Name: cert.Name}, "Renewal requested by the Verrazzano resource"); err != nil {
+			log.Errorf("Failed renewing certificate %s: %v", key, err)
+			return err
+		}
+	}
+	delete(requested, renewAllCertificates)
+	for name := range requested {
+		log.Infof("Certificate %s requested for renewal was not found", name)
+	}
+
+	delete(cr.Annotations, vzconst.RenewCertificatesAnnotation)
+	return r.Update(context.TODO(), cr)
+}
+
+// getExpiryState returns Expired, ExpiringSoon or Valid depending on when the certificate expires
+func getExpiryState(notAfter time.Time, threshold time.Duration) vzapi.CertificateRenewalState {
+	remaining := time.Until(notAfter)
+	if remaining <= 0 {
+		return vzapi.CertificateExpired
+	}
+	if remaining <= threshold {
+		return vzapi.CertificateExpiringSoon
+	}
+	return vzapi.CertificateValid
+}
+
+// hasCertificateCondition returns true if the cert-manager Certificate has a true condition of the given type
+func hasCertificateCondition(cert *certapiv1.Certificate, conditionType certapiv1.CertificateConditionType) bool {
+	for _, condition := range cert.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == cmmeta.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// getCertificateKey returns namespace/name of a certificate, or the name of a webhook certificate
+func getCertificateKey(cert vzapi.CertificateStatus) string {
+	if len(cert.Namespace) == 0 {
+		return cert.Name
+	}
+	return fmt.Sprintf("%s/%s", cert.Namespace, cert.Name)
+}
+
+// setVerrazzanoCondition adds the condition to the Verrazzano resource, or updates the message of the condition.
+// The state of the Verrazzano resource isn't changed.  Returns true if the condition changed.
+func setVerrazzanoCondition(cr *vzapi.Verrazzano, conditionType vzapi.ConditionType, message string) bool {
+	if hasCondition(cr.Status.Conditions, conditionType) {
+		return updateConditionMessage(cr.Status.Conditions, conditionType, message)
+	}
+	cr.Status.Conditions = append(cr.Status.Conditions, vzapi.Condition{
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		Message:            message,
+		LastTransitionTime: formatStatusTime(time.Now()),
+	})
+	return true
+}
+
+// deleteCertificateCheck forgets the time of the last certificate check of the Verrazzano resource
+func deleteCertificateCheck(cr *vzapi.Verrazzano) {
+	delete(lastCertificateCheckMap, getNSNKey(cr))
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	certapiv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/certificate"
+	"github.com/verrazzano/verrazzano/platform-operator/mocks"
+	adminv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// expectCertificateInventory adds the expected calls to get the certificate inventory to a mock client, there are no
+// IngressTrait certificates or webhook configurations
+func expectCertificateInventory(mock *mocks.MockClient) {
+	mock.EXPECT().
+		List(gomock.Any(), gomock.AssignableToTypeOf(&certapiv1.CertificateList{}), gomock.Any()).
+		Return(nil).AnyTimes()
+	mock.EXPECT().
+		Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&adminv1.ValidatingWebhookConfiguration{})).
+		DoAndReturn(func(ctx context.Context, name types.NamespacedName, webhook *adminv1.ValidatingWebhookConfiguration) error {
+			return errors.NewNotFound(schema.GroupResource{Group: "admissionregistration.k8s.io", Resource: "ValidatingWebhookConfiguration"}, name.Name)
+		}).AnyTimes()
+}
+
+// newCertificateScheme returns a scheme with the Verrazzano, Kubernetes and cert-manager types
+func newCertificateScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = k8scheme.AddToScheme(scheme)
+	_ = vzapi.AddToScheme(scheme)
+	_ = certapiv1.AddToScheme(scheme)
+	return scheme
+}
+
+// newTestCertificate returns a ready cert-manager Certificate that expires at the given time
+func newTestCertificate(namespace string, name string, notAfter time.Time) *certapiv1.Certificate {
+	return &certapiv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: certapiv1.CertificateSpec{
+			IssuerRef: cmmeta.ObjectReference{Kind: "ClusterIssuer", Name: verrazzanoClusterIssuerName},
+		},
+		Status: certapiv1.CertificateStatus{
+			NotAfter: &metav1.Time{Time: notAfter},
+			Conditions: []certapiv1.CertificateCondition{
+				{Type: certapiv1.CertificateConditionReady, Status: cmmeta.ConditionTrue},
+			},
+		},
+	}
+}
+
+// newTestWebhook returns a ValidatingWebhookConfiguration with a self-signed CA that expires at the given time
+func newTestWebhook(t *testing.T, name string, notAfter time.Time) *adminv1.ValidatingWebhookConfiguration {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "verrazzano-platform-operator.verrazzano-install.svc"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return &adminv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Webhooks: []adminv1.ValidatingWebhook{
+			{
+				Name:         name,
+				ClientConfig: adminv1.WebhookClientConfig{CABundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caBytes})},
+			},
+		},
+	}
+}
+
+// TestCheckCertificates tests the checkCertificates method for the following use case
+// GIVEN a component certificate that expires within the certificate expiry threshold, an IngressTrait certificate
+// and a webhook certificate that are valid
+// WHEN checkCertificates is called
+// THEN the certificates are reported in the status with the CertificatesExpiringSoon condition, and the condition is
+// removed once the certificate has been renewed
+func TestCheckCertificates(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	compCert := types.NamespacedName{Namespace: "verrazzano-system", Name: "comp-tls"}
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "a", Certificates: []types.NamespacedName{compCert}}},
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-certs"},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateReady,
+			Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}},
+		},
+	}
+	defer deleteCertificateCheck(vz)
+	now := time.Now()
+	c := fake.NewClientBuilder().WithScheme(newCertificateScheme()).WithObjects(
+		vz,
+		newTestCertificate(compCert.Namespace, compCert.Name, now.Add(48*time.Hour)),
+		newTestCertificate(vzconst.IstioSystemNamespace, "hello-hello-trait-cert", now.Add(60*24*time.Hour)),
+		newTestCertificate(vzconst.IstioSystemNamespace, "other-tls", now.Add(60*24*time.Hour)),
+		newTestWebhook(t, certificate.OperatorName, now.Add(365*24*time.Hour)),
+	).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	recorder := record.NewFakeRecorder(10)
+	reconciler.EventRecorder = recorder
+	vzctx := vzcontext.VerrazzanoContext{Log: vzlog.DefaultLogger(), Client: c, ActualCR: vz}
+
+	asserts.NoError(reconciler.checkCertificates(vzctx))
+	asserts.Len(vz.Status.Certificates, 3)
+	asserts.Equal(vzapi.CertificateSourceComponent, vz.Status.Certificates[0].Source)
+	asserts.Equal("a", vz.Status.Certificates[0].Component)
+	asserts.Equal("ClusterIssuer/verrazzano-cluster-issuer", vz.Status.Certificates[0].Issuer)
+	asserts.Equal(vzapi.CertificateExpiringSoon, vz.Status.Certificates[0].RenewalState)
+	asserts.Equal(vzapi.CertificateSourceIngressTrait, vz.Status.Certificates[1].Source)
+	asserts.Equal("hello-hello-trait-cert", vz.Status.Certificates[1].Name)
+	asserts.Equal(vzapi.CertificateValid, vz.Status.Certificates[1].RenewalState)
+	asserts.Equal(vzapi.CertificateSourceWebhook, vz.Status.Certificates[2].Source)
+	asserts.Equal(certificate.OperatorName, vz.Status.Certificates[2].Name)
+	asserts.Equal("verrazzano-platform-operator.verrazzano-install.svc", vz.Status.Certificates[2].Issuer)
+	asserts.Equal(vzapi.CertificateValid, vz.Status.Certificates[2].RenewalState)
+	asserts.True(hasCondition(vz.Status.Conditions, vzapi.CondCertificatesExpiringSoon))
+	asserts.Contains(<-recorder.Events, "Warning CertificatesExpiringSoon Certificates expire within 336h0m0s: verrazzano-system/comp-tls")
+
+	// The certificates are not checked again until the check interval has passed
+	renewed := newTestCertificate(compCert.Namespace, compCert.Name, now.Add(90*24*time.Hour))
+	cert := &certapiv1.Certificate{}
+	asserts.NoError(c.Get(context.TODO(), compCert, cert))
+	cert.Status = renewed.Status
+	asserts.NoError(c.Update(context.TODO(), cert))
+	asserts.NoError(reconciler.checkCertificates(vzctx))
+	asserts.Equal(vzapi.CertificateExpiringSoon, vz.Status.Certificates[0].RenewalState)
+
+	deleteCertificateCheck(vz)
+	asserts.NoError(reconciler.checkCertificates(vzctx))
+	asserts.Equal(vzapi.CertificateValid, vz.Status.Certificates[0].RenewalState)
+	asserts.False(hasCondition(vz.Status.Conditions, vzapi.CondCertificatesExpiringSoon))
+
+	actual := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test-certs"}, actual))
+	asserts.Len(actual.Status.Certificates, 3)
+	asserts.False(hasCondition(actual.Status.Conditions, vzapi.CondCertificatesExpiringSoon))
+}
+
+// TestRenewCertificates tests the checkCertificates method for the following use case
+// GIVEN a Verrazzano resource with the renew certificates annotation
+// WHEN checkCertificates is called
+// THEN the requested cert-manager certificates are renewed and the annotation is removed
+func TestRenewCertificates(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	var renewed []types.NamespacedName
+	origRenewCertificateFunc := renewCertificateFunc
+	defer func() { renewCertificateFunc = origRenewCertificateFunc }()
+	renewCertificateFunc = func(log vzlog.VerrazzanoLogger, name types.NamespacedName, message string) error {
+		renewed = append(renewed, name)
+		return nil
+	}
+
+	tests := []struct {
+		annotation string
+		expected   []types.NamespacedName
+	}{
+		{
+			annotation: "istio-system/a-trait-cert",
+			expected:   []types.NamespacedName{{Namespace: vzconst.IstioSystemNamespace, Name: "a-trait-cert"}},
+		},
+		{
+			annotation: "all",
+			expected: []types.NamespacedName{
+				{Namespace: vzconst.IstioSystemNamespace, Name: "a-trait-cert"},
+				{Namespace: vzconst.IstioSystemNamespace, Name: "b-trait-cert"},
+			},
+		},
+		{
+			annotation: certificate.OperatorName + ", istio-system/missing-cert",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.annotation, func(t *testing.T) {
+			renewed = nil
+			vz := &vzapi.Verrazzano{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "verrazzano",
+					Name:        "test-renew",
+					Annotations: map[string]string{vzconst.RenewCertificatesAnnotation: tt.annotation},
+				},
+			}
+			defer deleteCertificateCheck(vz)
+			now := time.Now()
+			c := fake.NewClientBuilder().WithScheme(newCertificateScheme()).WithObjects(
+				vz,
+				newTestCertificate(vzconst.IstioSystemNamespace, "a-trait-cert", now.Add(24*time.Hour)),
+				newTestCertificate(vzconst.IstioSystemNamespace, "b-trait-cert", now.Add(24*time.Hour)),
+				newTestWebhook(t, certificate.OperatorName, now.Add(365*24*time.Hour)),
+			).Build()
+			reconciler := newVerrazzanoReconciler(c)
+
+			asserts.NoError(reconciler.checkCertificates(vzcontext.VerrazzanoContext{Log: vzlog.DefaultLogger(), Client: c, ActualCR: vz}))
+			asserts.Equal(tt.expected, renewed)
+			actual := &vzapi.Verrazzano{}
+			asserts.NoError(c.Get(context.TODO(), client.ObjectKeyFromObject(vz), actual))
+			asserts.NotContains(actual.Annotations, vzconst.RenewCertificatesAnnotation)
+		})
+	}
+}
+
+// TestNewCertificateStatus tests the newCertificateStatus func
+// GIVEN cert-manager Certificates that are issuing, not ready, expired, expiring soon and valid
+// WHEN newCertificateStatus is called
+// THEN the renewal state of the certificates is returned
+func TestNewCertificateStatus(t *testing.T) {
+	now := time.Now()
+	issuing := newTestCertificate("ns", "issuing", now.Add(time.Hour))
+	issuing.Status.Conditions = append(issuing.Status.Conditions, certapiv1.CertificateCondition{Type: certapiv1.CertificateConditionIssuing, Status: cmmeta.ConditionTrue})
+	notReady := newTestCertificate("ns", "not-ready", now.Add(time.Hour))
+	notReady.Status.Conditions[0].Status = cmmeta.ConditionFalse
+	notIssued := newTestCertificate("ns", "not-issued", now)
+	notIssued.Status.NotAfter = nil
+
+	tests := []struct {
+		cert     *certapiv1.Certificate
+		expected vzapi.CertificateRenewalState
+	}{
+		{cert: issuing, expected: vzapi.CertificateRenewing},
+		{cert: notReady, expected: vzapi.CertificateNotReady},
+		{cert: notIssued, expected: vzapi.CertificateNotReady},
+		{cert: newTestCertificate("ns", "expired", now.Add(-time.Hour)), expected: vzapi.CertificateExpired},
+		{cert: newTestCertificate("ns", "expiring", now.Add(time.Hour)), expected: vzapi.CertificateExpiringSoon},
+		{cert: newTestCertificate("ns", "valid", now.Add(30*24*time.Hour)), expected: vzapi.CertificateValid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cert.Name, func(t *testing.T) {
+			certStatus := newCertificateStatus(tt.cert, vzapi.CertificateSourceIngressTrait, defaultCertificateExpiryThreshold)
+			assert.Equal(t, tt.expected, certStatus.RenewalState)
+		})
+	}
+}
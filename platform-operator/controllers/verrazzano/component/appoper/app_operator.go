@@ -9,16 +9,33 @@ import (
 	"os"
 
 	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	appconst "github.com/verrazzano/verrazzano/application-operator/constants"
 	"github.com/verrazzano/verrazzano/pkg/bom"
+	vmcv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/status"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// managedClusterSecrets are the secrets that register a managed cluster with the admin cluster
+var managedClusterSecrets = []string{"verrazzano-cluster-agent", "verrazzano-cluster-registration", "verrazzano-cluster-elasticsearch"}
+
+// multiclusterLists are the lists of the multicluster resources of the applications
+var multiclusterLists = []func() client.ObjectList{
+	func() client.ObjectList { return &clustersv1alpha1.MultiClusterApplicationConfigurationList{} },
+	func() client.ObjectList { return &clustersv1alpha1.MultiClusterComponentList{} },
+	func() client.ObjectList { return &clustersv1alpha1.MultiClusterConfigMapList{} },
+	func() client.ObjectList { return &clustersv1alpha1.MultiClusterSecretList{} },
+}
+
 const (
 	helmManagedByLabel             = "app.kubernetes.io/managed-by"
 	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
@@ -191,3 +208,40 @@ func labelAnnotateWorkloadDefinitions(c client.Client) error {
 	}
 	return nil
 }
+
+// deleteApplications deletes the resources of the applications.  On a managed cluster, the registration secrets are
+// deleted first so that the agent stops synchronizing the resources of the admin cluster.
+func deleteApplications(ctx spi.ComponentContext) error {
+	for _, name := range managedClusterSecrets {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoSystemNamespace, Name: name}}
+		if err := common.DeleteObject(ctx, secret); err != nil {
+			return err
+		}
+	}
+	mcNamespace := client.InNamespace(constants.VerrazzanoMultiClusterNamespace)
+	if err := common.DeleteResources(ctx, &vmcv1alpha1.VerrazzanoManagedClusterList{}, nil, mcNamespace); err != nil {
+		return err
+	}
+	if err := common.DeleteResources(ctx, &clustersv1alpha1.VerrazzanoProjectList{}, nil, mcNamespace); err != nil {
+		return err
+	}
+	for _, newList := range multiclusterLists {
+		if err := common.DeleteResources(ctx, newList(), nil); err != nil {
+			return err
+		}
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := ctx.Client().List(context.TODO(), namespaces, client.MatchingLabels{appconst.LabelVerrazzanoManaged: "true"}); err != nil {
+		return ctx.Log().ErrorfNewErr("Failed listing the namespaces of the applications: %v", err)
+	}
+	for _, ns := range namespaces.Items {
+		if err := common.DeleteResources(ctx, &oamv1alpha2.ApplicationConfigurationList{}, nil, client.InNamespace(ns.Name)); err != nil {
+			return err
+		}
+		if err := common.DeleteResources(ctx, &oamv1alpha2.ComponentList{}, nil, client.InNamespace(ns.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/oam"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	netv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -45,12 +47,39 @@ func NewComponent() spi.Component {
 			IgnoreNamespaceOverride: true,
 			SupportsOperatorInstall: true,
 			AppendOverridesFunc:     AppendApplicationOperatorOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
 			ImagePullSecretKeyname:  "global.imagePullSecrets[0]",
 			Dependencies:            []string{oam.ComponentName, istio.ComponentName},
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Affinity:          "affinity",
+					Tolerations:       "tolerations",
+					NodeSelector:      "nodeSelector",
+					PriorityClassName: "priorityClassName",
+					Resources:         "resources",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the Verrazzano Application Operator Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.ApplicationOperator; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the Verrazzano Application Operator component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.ApplicationOperator; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // IsReady component check
 func (c applicationOperatorComponent) IsReady(context spi.ComponentContext) bool {
 	if c.HelmComponent.IsReady(context) {
@@ -107,6 +136,16 @@ func (c applicationOperatorComponent) PostUpgrade(ctx spi.ComponentContext) erro
 
 }
 
+// PreUninstall deletes the applications and the multicluster resources while the operators that process them are
+// still installed
+func (c applicationOperatorComponent) PreUninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debug("application-operator PreUninstall dry run")
+		return nil
+	}
+	return deleteApplications(ctx)
+}
+
 // IsEnabled applicationOperator-specific enabled check for installation
 func (c applicationOperatorComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	comp := effectiveCR.Spec.Components.ApplicationOperator
@@ -118,9 +157,22 @@ func (c applicationOperatorComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) b
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (c applicationOperatorComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow any changes except to enable the component post-install
-	if c.IsEnabled(old) && !c.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	return nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the Verrazzano application operator, which reaches the workloads
+// of the applications, and the API server of the admin cluster from a managed cluster
+func (c applicationOperatorComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-verrazzano-application-operator", map[string]string{"app": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				// The webhooks are called by the API server
+				netpolicy.IngressFrom(nil, 9443),
+				netpolicy.PrometheusIngress(common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(common.ClusterPeers()),
+				netpolicy.EgressTo(nil, 443, 6443),
+			}),
+	}, nil
+}
@@ -8,16 +8,23 @@ import (
 	"fmt"
 	"testing"
 
+	oamcore "github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/stretchr/testify/assert"
+	appclustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	appconst "github.com/verrazzano/verrazzano/application-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
 	clustersv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -100,6 +107,48 @@ func TestAppOperatorPostUpgradeDeleteClusterRoleBinding(t *testing.T) {
 	assert.True(t, errors.IsNotFound(err))
 }
 
+// TestAppOperatorPreUninstall tests the PreUninstall function
+// GIVEN a call to PreUninstall
+// WHEN applications, multicluster resources and managed cluster registration secrets exist
+// THEN they are deleted, and the OAM resources of the namespaces that are not managed by Verrazzano are kept
+func TestAppOperatorPreUninstall(t *testing.T) {
+	scheme := newScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appclustersv1alpha1.AddToScheme(scheme)
+	_ = oamcore.AddToScheme(scheme)
+	managedLabels := map[string]string{appconst.LabelVerrazzanoManaged: "true"}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoSystemNamespace, Name: "verrazzano-cluster-registration"}},
+		&v1alpha1.VerrazzanoManagedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: "managed1"}},
+		&appclustersv1alpha1.VerrazzanoProject{ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: "project"}},
+		&appclustersv1alpha1.MultiClusterSecret{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "secret"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app", Labels: managedLabels}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		&oamv1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "appconf"}},
+		&oamv1alpha2.Component{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "comp"}},
+		&oamv1alpha2.Component{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "comp"}},
+	).Build()
+	err := NewComponent().PreUninstall(spi.NewFakeContext(fakeClient, &vzapi.Verrazzano{}, false))
+	assert.NoError(t, err)
+
+	deleted := []struct {
+		name types.NamespacedName
+		obj  client.Object
+	}{
+		{types.NamespacedName{Namespace: constants.VerrazzanoSystemNamespace, Name: "verrazzano-cluster-registration"}, &corev1.Secret{}},
+		{types.NamespacedName{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: "managed1"}, &v1alpha1.VerrazzanoManagedCluster{}},
+		{types.NamespacedName{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: "project"}, &appclustersv1alpha1.VerrazzanoProject{}},
+		{types.NamespacedName{Namespace: "app", Name: "secret"}, &appclustersv1alpha1.MultiClusterSecret{}},
+		{types.NamespacedName{Namespace: "app", Name: "appconf"}, &oamv1alpha2.ApplicationConfiguration{}},
+		{types.NamespacedName{Namespace: "app", Name: "comp"}, &oamv1alpha2.Component{}},
+	}
+	for _, d := range deleted {
+		err = fakeClient.Get(context.TODO(), d.name, d.obj)
+		assert.True(t, errors.IsNotFound(err), "expected %s to be deleted", d.name)
+	}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "other", Name: "comp"}, &oamv1alpha2.Component{}))
+}
+
 func newScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	_ = clustersv1alpha1.AddToScheme(scheme)
@@ -194,7 +243,7 @@ func Test_applicationOperatorComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name:    "no change",
@@ -220,6 +220,11 @@ func loadKubernetesSettings(ctx spi.ComponentContext, overrides *authProxyValues
 				}
 				overrides.Affinity = string(affinityYaml)
 			}
+			overrides.Tolerations = kubernetesSettings.Tolerations
+			overrides.NodeSelector = kubernetesSettings.NodeSelector
+			overrides.PriorityClassName = kubernetesSettings.PriorityClassName
+			overrides.Resources = kubernetesSettings.Resources
+			overrides.PodDisruptionBudget = kubernetesSettings.PodDisruptionBudget
 		}
 	}
 	return nil
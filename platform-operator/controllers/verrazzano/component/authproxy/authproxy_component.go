@@ -4,15 +4,18 @@
 package authproxy
 
 import (
-	"fmt"
 	"path/filepath"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -43,12 +46,21 @@ func NewComponent() spi.Component {
 			IgnoreNamespaceOverride: true,
 			SupportsOperatorInstall: true,
 			AppendOverridesFunc:     AppendOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
 			MinVerrazzanoVersion:    constants.VerrazzanoVersion1_3_0,
 			ImagePullSecretKeyname:  "global.imagePullSecrets[0]",
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the AuthProxy Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.AuthProxy; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
 // IsEnabled authProxyComponent-specific enabled check for installation
 func (c authProxyComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	comp := effectiveCR.Spec.Components.AuthProxy
@@ -60,13 +72,19 @@ func (c authProxyComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (c authProxyComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow any changes except to enable the component post-install
-	if c.IsEnabled(old) && !c.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	return nil
 }
 
+// ValidateKubernetesSettings checks the kubernetes section of the AuthProxy, which supports all the settings
+func (c authProxyComponent) ValidateKubernetesSettings(vz *vzapi.Verrazzano) error {
+	comp := vz.Spec.Components.AuthProxy
+	if comp == nil || comp.Kubernetes == nil {
+		return nil
+	}
+	return vzconfig.ValidateKubernetesSettings(ComponentJSONName, &comp.Kubernetes.CommonKubernetesSpec,
+		vzconfig.GetKubernetesSettings(&comp.Kubernetes.CommonKubernetesSpec)...)
+}
+
 // IsReady component check
 func (c authProxyComponent) IsReady(ctx spi.ComponentContext) bool {
 	if c.HelmComponent.IsReady(ctx) {
@@ -112,3 +130,19 @@ func (c authProxyComponent) PreInstall(ctx spi.ComponentContext) error {
 func (c authProxyComponent) PreUpgrade(ctx spi.ComponentContext) error {
 	return authproxyPreHelmOps(ctx)
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the Verrazzano authentication proxy, which is reached through the
+// ingress controller and forwards the requests to the consoles and APIs of the components
+func (c authProxyComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-verrazzano-authproxy", map[string]string{"app": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.IngressControllerPeers(), 8775),
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", map[string]string{"app": "fluentd"})}, 8775),
+				netpolicy.PrometheusIngress(common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(common.ClusterPeers()),
+			}),
+	}, nil
+}
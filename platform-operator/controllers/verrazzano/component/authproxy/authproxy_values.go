@@ -3,19 +3,29 @@
 
 package authproxy
 
+import (
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
 // authProxyValues struct representing the Helm chart values for this component
 type authProxyValues struct {
-	Name                 string        `json:"name,omitempty"`
-	ImageName            string        `json:"imageName,omitempty"`
-	ImageVersion         string        `json:"imageVersion,omitempty"`
-	PullPolicy           string        `json:"pullPolicy,omitempty"`
-	Replicas             uint32        `json:"replicas,omitempty"`
-	Port                 int           `json:"port,omitempty"`
-	ImpersonatorRoleName string        `json:"impersonatorRoleName,omitempty"`
-	Proxy                *proxyValues  `json:"proxy,omitempty"`
-	Config               *configValues `json:"config,omitempty"`
-	DNS                  *dnsValues    `json:"dns,omitempty"`
-	Affinity             string        `json:"affinity,omitempty"`
+	Name                 string                         `json:"name,omitempty"`
+	ImageName            string                         `json:"imageName,omitempty"`
+	ImageVersion         string                         `json:"imageVersion,omitempty"`
+	PullPolicy           string                         `json:"pullPolicy,omitempty"`
+	Replicas             uint32                         `json:"replicas,omitempty"`
+	Port                 int                            `json:"port,omitempty"`
+	ImpersonatorRoleName string                         `json:"impersonatorRoleName,omitempty"`
+	Proxy                *proxyValues                   `json:"proxy,omitempty"`
+	Config               *configValues                  `json:"config,omitempty"`
+	DNS                  *dnsValues                     `json:"dns,omitempty"`
+	Affinity             string                         `json:"affinity,omitempty"`
+	Tolerations          []corev1.Toleration            `json:"tolerations,omitempty"`
+	NodeSelector         map[string]string              `json:"nodeSelector,omitempty"`
+	PriorityClassName    string                         `json:"priorityClassName,omitempty"`
+	Resources            *corev1.ResourceRequirements   `json:"resources,omitempty"`
+	PodDisruptionBudget  *vzapi.PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 type proxyValues struct {
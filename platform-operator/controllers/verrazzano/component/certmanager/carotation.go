@@ -0,0 +1,152 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package certmanager
+
+import (
+	"context"
+	"fmt"
+
+	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	certmetav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/certificate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// startCARotation starts the staged rotation of the CA when the CA secret of the Verrazzano ClusterIssuer changes.
+// The previous CA is added to the CA rotation bundle, which is published to the clients of the Verrazzano
+// certificates with the new CA before the certificates are re-issued by the new CA.
+func startCARotation(compContext spi.ComponentContext, newSecretName string) error {
+	cli := compContext.Client()
+	clusterIssuer := certv1.ClusterIssuer{}
+	if err := cli.Get(context.TODO(), types.NamespacedName{Name: verrazzanoClusterIssuerName}, &clusterIssuer); err != nil {
+		if errors.IsNotFound(err) {
+			// Initial install, there is no previous CA
+			return nil
+		}
+		return err
+	}
+	if clusterIssuer.Spec.CA == nil || clusterIssuer.Spec.CA.SecretName == newSecretName {
+		return nil
+	}
+
+	namespace := compContext.EffectiveCR().Spec.Components.CertManager.Certificate.CA.ClusterResourceNamespace
+	previousSecret := v1.Secret{}
+	if err := cli.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: clusterIssuer.Spec.CA.SecretName}, &previousSecret); err != nil {
+		if errors.IsNotFound(err) {
+			compContext.Log().Infof("Previous CA secret %s/%s not found, the certificates are re-issued without a staged CA rotation",
+				namespace, clusterIssuer.Spec.CA.SecretName)
+			return nil
+		}
+		return err
+	}
+
+	compContext.Log().Infof("Starting the rotation of the CA of the Verrazzano ClusterIssuer from secret %s/%s to secret %s/%s",
+		namespace, clusterIssuer.Spec.CA.SecretName, namespace, newSecretName)
+	bundle := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      certificate.CARotationBundleSecretName.Name,
+			Namespace: certificate.CARotationBundleSecretName.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(context.TODO(), cli, &bundle, func() error {
+		if bundle.Data == nil {
+			bundle.Data = map[string][]byte{}
+		}
+		bundle.Data[certificate.CABundleKey] = certificate.AppendCABundle(bundle.Data[certificate.CABundleKey], previousSecret.Data[v1.TLSCertKey])
+		return nil
+	}); err != nil {
+		return compContext.Log().ErrorfNewErr("Failed to create or update the CA rotation bundle secret: %v", err)
+	}
+	return nil
+}
+
+// isCARotationInProgress returns true if the CA of the Verrazzano ClusterIssuer is being rotated, the certificates
+// are then re-issued by the staged rotation instead of all at once
+func isCARotationInProgress(compContext spi.ComponentContext) (bool, error) {
+	bundle, err := certificate.GetCARotationBundle(compContext.Client())
+	if err != nil {
+		return false, err
+	}
+	return bundle != nil, nil
+}
+
+// GetClusterIssuerCA returns the CA certificate of the Verrazzano ClusterIssuer, or nil if the ClusterIssuer is not a
+// CA issuer or the CA has not been issued yet
+func GetClusterIssuerCA(compContext spi.ComponentContext) ([]byte, error) {
+	cli := compContext.Client()
+	clusterIssuer := certv1.ClusterIssuer{}
+	if err := cli.Get(context.TODO(), types.NamespacedName{Name: verrazzanoClusterIssuerName}, &clusterIssuer); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if clusterIssuer.Spec.CA == nil {
+		return nil, nil
+	}
+	namespace := compContext.EffectiveCR().Spec.Components.CertManager.Certificate.CA.ClusterResourceNamespace
+	secret := v1.Secret{}
+	if err := cli.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: clusterIssuer.Spec.CA.SecretName}, &secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret.Data[v1.TLSCertKey], nil
+}
+
+// ReissueCertificates requests new certificates for the certificates of the Verrazzano ClusterIssuer that were not
+// issued by its current CA
+func ReissueCertificates(compContext spi.ComponentContext) error {
+	return checkRenewAllCertificates(compContext, true)
+}
+
+// GetCertificatesNotReissued returns the namespace/name of the certificates of the Verrazzano ClusterIssuer that are
+// not ready, or were not issued by the CA with the given common name
+func GetCertificatesNotReissued(compContext spi.ComponentContext, caCommonName string) ([]string, error) {
+	cli := compContext.Client()
+	certList := certv1.CertificateList{}
+	if err := cli.List(context.TODO(), &certList); err != nil {
+		return nil, err
+	}
+	var pending []string
+	for _, cert := range certList.Items {
+		if cert.Name == caCertificateName || cert.Spec.IssuerRef.Name != verrazzanoClusterIssuerName {
+			continue
+		}
+		name := fmt.Sprintf("%s/%s", cert.Namespace, cert.Name)
+		if !isCertificateConditionTrue(cert, certv1.CertificateConditionReady) || isCertificateConditionTrue(cert, certv1.CertificateConditionIssuing) {
+			pending = append(pending, name)
+			continue
+		}
+		secret := v1.Secret{}
+		if err := cli.Get(context.TODO(), types.NamespacedName{Namespace: cert.Namespace, Name: cert.Spec.SecretName}, &secret); err != nil {
+			if errors.IsNotFound(err) {
+				pending = append(pending, name)
+				continue
+			}
+			return nil, err
+		}
+		issuerCN, err := extractCommonNameFromCertSecret(&secret)
+		if err != nil || issuerCN != caCommonName {
+			pending = append(pending, name)
+		}
+	}
+	return pending, nil
+}
+
+// isCertificateConditionTrue returns true if the Certificate has a true condition of the given type
+func isCertificateConditionTrue(cert certv1.Certificate, conditionType certv1.CertificateConditionType) bool {
+	for _, condition := range cert.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == certmetav1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package certmanager
+
+import (
+	"testing"
+
+	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	certmetav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/certificate"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestStartCARotation tests the startCARotation function
+// GIVEN a Verrazzano ClusterIssuer with a CA secret
+// WHEN startCARotation is called with a different CA secret
+// THEN the previous CA is added to the CA rotation bundle
+func TestStartCARotation(t *testing.T) {
+	asserts := assert.New(t)
+	localvz := defaultVZConfig.DeepCopy()
+	localvz.Spec.Components.CertManager.Certificate.CA = ca
+
+	previousSecret, err := createCertSecretNoParent("previousSecret", testNamespace, "verrazzano-root-ca-old")
+	asserts.NoError(err)
+	clusterIssuer := &certv1.ClusterIssuer{
+		ObjectMeta: metav1.ObjectMeta{Name: verrazzanoClusterIssuerName},
+		Spec: certv1.IssuerSpec{
+			IssuerConfig: certv1.IssuerConfig{
+				CA: &certv1.CAIssuer{SecretName: previousSecret.Name},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(localvz, clusterIssuer, previousSecret).Build()
+	ctx := spi.NewFakeContext(client, localvz, false)
+
+	inProgress, err := isCARotationInProgress(ctx)
+	asserts.NoError(err)
+	asserts.False(inProgress)
+
+	// Same CA secret, no rotation
+	asserts.NoError(startCARotation(ctx, previousSecret.Name))
+	inProgress, err = isCARotationInProgress(ctx)
+	asserts.NoError(err)
+	asserts.False(inProgress)
+
+	// New CA secret, the previous CA is added to the rotation bundle
+	asserts.NoError(startCARotation(ctx, ca.SecretName))
+	inProgress, err = isCARotationInProgress(ctx)
+	asserts.NoError(err)
+	asserts.True(inProgress)
+	bundle, err := certificate.GetCARotationBundle(client)
+	asserts.NoError(err)
+	asserts.Equal(previousSecret.Data[v1.TLSCertKey], bundle)
+
+	// Starting the rotation again doesn't duplicate the previous CA
+	asserts.NoError(startCARotation(ctx, ca.SecretName))
+	bundle, err = certificate.GetCARotationBundle(client)
+	asserts.NoError(err)
+	asserts.Equal([]string{"verrazzano-root-ca-old"}, certificate.GetCACommonNames(bundle))
+}
+
+// TestStartCARotationPreviousSecretNotFound tests the startCARotation function
+// GIVEN a Verrazzano ClusterIssuer with a CA secret that doesn't exist
+// WHEN startCARotation is called with a different CA secret
+// THEN no CA rotation is started
+func TestStartCARotationPreviousSecretNotFound(t *testing.T) {
+	asserts := assert.New(t)
+	localvz := defaultVZConfig.DeepCopy()
+	localvz.Spec.Components.CertManager.Certificate.CA = ca
+
+	clusterIssuer := &certv1.ClusterIssuer{
+		ObjectMeta: metav1.ObjectMeta{Name: verrazzanoClusterIssuerName},
+		Spec: certv1.IssuerSpec{
+			IssuerConfig: certv1.IssuerConfig{
+				CA: &certv1.CAIssuer{SecretName: "previousSecret"},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(localvz, clusterIssuer).Build()
+	ctx := spi.NewFakeContext(client, localvz, false)
+
+	asserts.NoError(startCARotation(ctx, ca.SecretName))
+	inProgress, err := isCARotationInProgress(ctx)
+	asserts.NoError(err)
+	asserts.False(inProgress)
+}
+
+// TestGetCertificatesNotReissued tests the GetCertificatesNotReissued function
+// GIVEN certificates of the Verrazzano ClusterIssuer issued by the previous and the new CA
+// WHEN GetCertificatesNotReissued is called
+// THEN the certificates that are not ready or were not issued by the new CA are returned
+func TestGetCertificatesNotReissued(t *testing.T) {
+	asserts := assert.New(t)
+	localvz := defaultVZConfig.DeepCopy()
+	localvz.Spec.Components.CertManager.Certificate.CA = ca
+
+	newCABytes, err := createFakeCertBytes("leaf", createFakeCertificate("verrazzano-root-ca-new"))
+	asserts.NoError(err)
+	oldCABytes, err := createFakeCertBytes("leaf", createFakeCertificate("verrazzano-root-ca-old"))
+	asserts.NoError(err)
+	reissuedSecret, _ := createCertSecret("reissued-tls", testNamespace, newCABytes)
+	previousSecret, _ := createCertSecret("previous-tls", testNamespace, oldCABytes)
+
+	newCertificate := func(name string, issuer string, conditions ...certv1.CertificateConditionType) *certv1.Certificate {
+		cert := &certv1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+			Spec: certv1.CertificateSpec{
+				SecretName: name + "-tls",
+				IssuerRef:  certmetav1.ObjectReference{Name: issuer},
+			},
+		}
+		for _, condition := range conditions {
+			cert.Status.Conditions = append(cert.Status.Conditions, certv1.CertificateCondition{Type: condition, Status: certmetav1.ConditionTrue})
+		}
+		return cert
+	}
+	client := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		localvz,
+		reissuedSecret,
+		previousSecret,
+		newCertificate("reissued", verrazzanoClusterIssuerName, certv1.CertificateConditionReady),
+		newCertificate("previous", verrazzanoClusterIssuerName, certv1.CertificateConditionReady),
+		newCertificate("issuing", verrazzanoClusterIssuerName, certv1.CertificateConditionReady, certv1.CertificateConditionIssuing),
+		newCertificate("missing", verrazzanoClusterIssuerName, certv1.CertificateConditionReady),
+		newCertificate("other", "other-issuer"),
+	).Build()
+	ctx := spi.NewFakeContext(client, localvz, false)
+
+	pending, err := GetCertificatesNotReissued(ctx, "verrazzano-root-ca-new")
+	asserts.NoError(err)
+	asserts.ElementsMatch([]string{testNamespace + "/previous", testNamespace + "/issuing", testNamespace + "/missing"}, pending)
+}
@@ -245,7 +245,7 @@ func updateCerts(ctx context.Context, log vzlog.VerrazzanoLogger, cmClient certv
 		}
 		if !vzstring.SliceContainsString(issuerCNs, certIssuerCN) {
 			// If the issuerRef CN is not in the set of configured issuers, we need to renew the existing certs
-			if err := renewCertificate(ctx, cmClient, log, &certList.Items[index]); err != nil {
+			if err := renewCertificate(ctx, cmClient, log, &certList.Items[index], "Re-issue updated Verrazzano certificates from new ClusterIssuer"); err != nil {
 				return err
 			}
 		}
@@ -266,8 +266,22 @@ func getCertIssuerCommonName(currentCert certv1.Certificate) (string, error) {
 	return certIssuerCN, nil
 }
 
+// RenewCertificate requests a new certificate for the cert-manager Certificate with the name
+func RenewCertificate(log vzlog.VerrazzanoLogger, name types.NamespacedName, message string) error {
+	cmClient, err := getCMClientFunc()
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	cert, err := cmClient.Certificates(name.Namespace).Get(ctx, name.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	return renewCertificate(ctx, cmClient, log, cert, message)
+}
+
 //renewCertificate Requests a new certificate by updating the status of the Certificate object to "Issuing"
-func renewCertificate(ctx context.Context, cmclientv1 certv1client.CertmanagerV1Interface, log vzlog.VerrazzanoLogger, updateCert *certv1.Certificate) error {
+func renewCertificate(ctx context.Context, cmclientv1 certv1client.CertmanagerV1Interface, log vzlog.VerrazzanoLogger, updateCert *certv1.Certificate, message string) error {
 	// Update the certificate status to start a renewal; avoid using controllerruntime.CreateOrUpdate(), while
 	// it should only do an update we don't want to accidentally create a updateCert
 	log.Oncef("Updating certificate %s/%s", updateCert.Namespace, updateCert.Name)
@@ -279,7 +293,7 @@ func renewCertificate(ctx context.Context, cmclientv1 certv1client.CertmanagerV1
 
 	// Set the certificate Issuing condition type to True, per guidance by the CertManager team
 	cmutil.SetCertificateCondition(updateCert, updateCert.Generation, certv1.CertificateConditionIssuing, certmetav1.ConditionTrue,
-		"VerrazzanoUpdate", "Re-issue updated Verrazzano certificates from new ClusterIssuer")
+		"VerrazzanoUpdate", message)
 	// Updating the status field only works using the UpdateStatus call via the CertManager typed client interface
 	if _, err := cmclientv1.Certificates(updateCert.Namespace).UpdateStatus(ctx, updateCert, metav1.UpdateOptions{}); err != nil {
 		return err
@@ -727,6 +741,11 @@ func createOrUpdateCAResources(compContext spi.ComponentContext) (controllerutil
 		}
 	}
 
+	// Start a staged rotation if the CA of the cluster issuer changes
+	if err := startCARotation(compContext, vzCertCA.SecretName); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
 	// Create the cluster issuer resource for CA cert
 	compContext.Log().Debug("Applying ClusterIssuer")
 	clusterIssuer := certv1.ClusterIssuer{
@@ -5,7 +5,6 @@ package certmanager
 
 import (
 	"context"
-	"fmt"
 	"path/filepath"
 
 	vzconst "github.com/verrazzano/verrazzano/pkg/constants"
@@ -15,8 +14,11 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -27,6 +29,9 @@ const ComponentName = "cert-manager"
 // ComponentNamespace is the namespace of the component
 const ComponentNamespace = vzconst.CertManagerNamespace
 
+// leaderElectionConfigMaps are the ConfigMaps the cert-manager controllers use for their leader election
+var leaderElectionConfigMaps = []string{"cert-manager-controller", "cert-manager-cainjector-leader-election"}
+
 // ComponentJSONName is the josn name of the verrazzano component in CRD
 const ComponentJSONName = "certManager"
 
@@ -51,12 +56,55 @@ func NewComponent() spi.Component {
 			ImagePullSecretKeyname:  "global.imagePullSecrets[0].name",
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), "cert-manager-values.yaml"),
 			AppendOverridesFunc:     AppendOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
 			MinVerrazzanoVersion:    constants.VerrazzanoVersion1_0_0,
 			Dependencies:            []string{},
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:          "replicaCount",
+					Affinity:          "affinity",
+					Tolerations:       "tolerations",
+					NodeSelector:      "nodeSelector",
+					PriorityClassName: "global.priorityClassName",
+					Resources:         "resources",
+				},
+				{
+					Affinity:     "webhook.affinity",
+					Tolerations:  "webhook.tolerations",
+					NodeSelector: "webhook.nodeSelector",
+				},
+				{
+					Affinity:     "cainjector.affinity",
+					Tolerations:  "cainjector.tolerations",
+					NodeSelector: "cainjector.nodeSelector",
+				},
+				{
+					Affinity:     "startupapicheck.affinity",
+					Tolerations:  "startupapicheck.tolerations",
+					NodeSelector: "startupapicheck.nodeSelector",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the cert-manager Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.CertManager; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the cert-manager component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.CertManager; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // IsEnabled returns true if the cert-manager is enabled, which is the default
 func (c certManagerComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	return vzconfig.IsCertManagerEnabled(effectiveCR)
@@ -72,10 +120,6 @@ func (c certManagerComponent) IsReady(ctx spi.ComponentContext) bool {
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (c certManagerComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow any changes except to enable the component post-install
-	if c.IsEnabled(old) && !c.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	if _, err := validateConfiguration(new); err != nil {
 		return err
 	}
@@ -151,6 +195,24 @@ func (c certManagerComponent) PostUpgrade(compContext spi.ComponentContext) erro
 	return c.createOrUpdateClusterIssuer(compContext)
 }
 
+// PostUninstall deletes the cert-manager CRDs, the ConfigMaps of the leader elections and the cert-manager namespace
+func (c certManagerComponent) PostUninstall(compContext spi.ComponentContext) error {
+	// If it is a dry-run, do nothing
+	if compContext.IsDryRun() {
+		compContext.Log().Debug("cert-manager PostUninstall dry run")
+		return nil
+	}
+	if err := common.ForceDeleteResources(compContext, &apiextv1.CustomResourceDefinitionList{}, common.CRDGroupHasSuffix("cert-manager.io")); err != nil {
+		return err
+	}
+	for _, name := range leaderElectionConfigMaps {
+		if err := common.DeleteObject(compContext, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: vzconst.KubeSystem, Name: name}}); err != nil {
+			return err
+		}
+	}
+	return common.DeleteNamespaces(compContext, ComponentNamespace)
+}
+
 func (c certManagerComponent) createOrUpdateClusterIssuer(compContext spi.ComponentContext) error {
 	isCAValue, err := isCA(compContext)
 	if err != nil {
@@ -179,9 +241,38 @@ func (c certManagerComponent) createOrUpdateClusterIssuer(compContext spi.Compon
 	if err := cleanupUnusedResources(compContext, isCAValue); err != nil {
 		return err
 	}
+	if isCAValue {
+		rotating, err := isCARotationInProgress(compContext)
+		if err != nil {
+			return err
+		}
+		if rotating {
+			// The certificates are re-issued by the staged CA rotation, once the new CA has been published
+			compContext.Log().Oncef("The CA is being rotated, skipping the renewal of all certificates")
+			return nil
+		}
+	}
 	if err := checkRenewAllCertificates(compContext, isCAValue); err != nil {
 		compContext.Log().Errorf("Error requesting certificate renewal: %s", err.Error())
 		return err
 	}
 	return nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of cert-manager, which requests the certificates from the ACME servers
+// outside the cluster
+func (c certManagerComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-cert-manager", map[string]string{"app.kubernetes.io/instance": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				// The webhook is called by the API server
+				netpolicy.IngressFrom(nil, 10250),
+				netpolicy.PrometheusIngress(9402),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				// The ACME servers, and the self checks of the HTTP-01 and DNS-01 challenges
+				netpolicy.EgressTo(nil, 80, 443),
+				netpolicy.DNSEgress(nil),
+			}),
+	}, nil
+}
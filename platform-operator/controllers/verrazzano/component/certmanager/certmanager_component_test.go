@@ -16,10 +16,12 @@ import (
 	certv1fake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
 	certv1client "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/typed/certmanager/v1"
 	"github.com/stretchr/testify/assert"
+	vzconst "github.com/verrazzano/verrazzano/pkg/constants"
 	"github.com/verrazzano/verrazzano/pkg/k8sutil"
 	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -424,6 +426,34 @@ func TestClusterIssuerUpdated(t *testing.T) {
 	asserts.NotNil(otherReq)
 }
 
+// TestRenewCertificate tests the RenewCertificate function
+// GIVEN a call to RenewCertificate
+//  WHEN the certificate exists
+//  THEN the certificate has an Issuing condition with the message, and an error is returned if it doesn't exist
+func TestRenewCertificate(t *testing.T) {
+	asserts := assert.New(t)
+	certificate := &certv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cert", Namespace: "test-ns"},
+	}
+	cmClient := certv1fake.NewSimpleClientset(certificate)
+	defer func() { getCMClientFunc = GetCertManagerClientset }()
+	getCMClientFunc = func() (certv1client.CertmanagerV1Interface, error) {
+		return cmClient.CertmanagerV1(), nil
+	}
+
+	name := types.NamespacedName{Namespace: "test-ns", Name: "test-cert"}
+	asserts.NoError(RenewCertificate(vzlog.DefaultLogger(), name, "Renewal requested"))
+	updatedCert, err := cmClient.CertmanagerV1().Certificates(name.Namespace).Get(context.TODO(), name.Name, metav1.GetOptions{})
+	asserts.NoError(err)
+	condition := cmutil.GetCertificateCondition(updatedCert, certv1.CertificateConditionIssuing)
+	asserts.NotNil(condition)
+	asserts.Equal(cmmeta.ConditionTrue, condition.Status)
+	asserts.Equal("Renewal requested", condition.Message)
+
+	err = RenewCertificate(vzlog.DefaultLogger(), types.NamespacedName{Namespace: "test-ns", Name: "missing"}, "Renewal requested")
+	asserts.True(errors.IsNotFound(err))
+}
+
 // TestDryRun tests the behavior when DryRun is enabled, mainly for code coverage
 // GIVEN a call to PostInstall/PostUpgrade/PreInstall
 //  WHEN the ComponentContext has DryRun set to true
@@ -876,3 +906,35 @@ func getTestClient(tt validationTestStruct) func(log ...vzlog.VerrazzanoLogger)
 		return createFakeClient().CoreV1(), nil
 	}
 }
+
+// TestPostUninstall tests the PostUninstall function
+// GIVEN a call to PostUninstall
+//
+//	WHEN the cert-manager CRDs, leader election ConfigMaps and namespace exist
+//	THEN they are deleted, and the other CRDs are kept
+func TestPostUninstall(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = apiextv1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "certificates.cert-manager.io"},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Group: "cert-manager.io"},
+		},
+		&apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "other.example.io"},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Group: "example.io"},
+		},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: vzconst.KubeSystem, Name: "cert-manager-controller"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ComponentNamespace}},
+	).Build()
+	assert.NoError(t, fakeComponent.PostUninstall(spi.NewFakeContext(client, &vzapi.Verrazzano{}, false)))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Name: "certificates.cert-manager.io"}, &apiextv1.CustomResourceDefinition{})
+	assert.True(t, errors.IsNotFound(err))
+	err = client.Get(context.TODO(), types.NamespacedName{Namespace: vzconst.KubeSystem, Name: "cert-manager-controller"}, &corev1.ConfigMap{})
+	assert.True(t, errors.IsNotFound(err))
+	err = client.Get(context.TODO(), types.NamespacedName{Name: ComponentNamespace}, &corev1.Namespace{})
+	assert.True(t, errors.IsNotFound(err))
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "other.example.io"}, &apiextv1.CustomResourceDefinition{}))
+}
@@ -11,6 +11,13 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
+const (
+	// validatingWebhookName is the name of the validating webhook configuration the Coherence operator creates
+	validatingWebhookName = "coherence-operator-validating-webhook-configuration"
+	// mutatingWebhookName is the name of the mutating webhook configuration the Coherence operator creates
+	mutatingWebhookName = "coherence-operator-mutating-webhook-configuration"
+)
+
 // IsCoherenceOperatorReady checks if the COH operator deployment is ready
 func isCoherenceOperatorReady(ctx spi.ComponentContext) bool {
 	deployments := []types.NamespacedName{
@@ -3,15 +3,19 @@
 package coherence
 
 import (
-	"fmt"
 	"path/filepath"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ComponentName is the name of the component
@@ -39,10 +43,35 @@ func NewComponent() spi.Component {
 			ImagePullSecretKeyname:  secret.DefaultImagePullSecretKeyName,
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), "coherence-values.yaml"),
 			Dependencies:            []string{},
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Affinity:     "affinity",
+					Tolerations:  "tolerations",
+					NodeSelector: "nodeSelector",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the Coherence Operator Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.CoherenceOperator; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the Coherence Operator component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.CoherenceOperator; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // IsEnabled Coherence-specific enabled check for installation
 func (c coherenceComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	comp := effectiveCR.Spec.Components.CoherenceOperator
@@ -62,9 +91,32 @@ func (c coherenceComponent) IsReady(ctx spi.ComponentContext) bool {
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (c coherenceComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow any changes except to enable the component post-install
-	if c.IsEnabled(old) && !c.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	return nil
 }
+
+// PostUninstall deletes the webhook configurations the Coherence operator creates, they are not part of the chart
+func (c coherenceComponent) PostUninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debug("Coherence PostUninstall dry run")
+		return nil
+	}
+	if err := common.DeleteObject(ctx, &admv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: validatingWebhookName}}); err != nil {
+		return err
+	}
+	return common.DeleteObject(ctx, &admv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: mutatingWebhookName}})
+}
+
+// GetNetworkPolicies returns the NetworkPolicies of the Coherence Operator, which manages the Coherence clusters in the
+// application namespaces
+func (c coherenceComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-coherence-operator", map[string]string{"control-plane": "coherence"},
+			[]netv1.NetworkPolicyIngressRule{
+				// The webhook is called by the API server
+				netpolicy.IngressFrom(nil, 9443, 8000),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(common.ClusterPeers()),
+			}),
+	}, nil
+}
@@ -4,9 +4,18 @@
 package coherence
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_coherenceComponent_ValidateUpdate(t *testing.T) {
@@ -43,7 +52,7 @@ func Test_coherenceComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name:    "no change",
@@ -61,3 +70,20 @@ func Test_coherenceComponent_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+// TestPostUninstall tests the PostUninstall function
+// GIVEN a call to PostUninstall
+// WHEN the webhook configurations of the Coherence operator exist
+// THEN they are deleted
+func TestPostUninstall(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&admv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: validatingWebhookName}},
+		&admv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: mutatingWebhookName}},
+	).Build()
+	assert.NoError(t, NewComponent().PostUninstall(spi.NewFakeContext(client, &vzapi.Verrazzano{}, false)))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Name: validatingWebhookName}, &admv1.ValidatingWebhookConfiguration{})
+	assert.True(t, errors.IsNotFound(err))
+	err = client.Get(context.TODO(), types.NamespacedName{Name: mutatingWebhookName}, &admv1.MutatingWebhookConfiguration{})
+	assert.True(t, errors.IsNotFound(err))
+}
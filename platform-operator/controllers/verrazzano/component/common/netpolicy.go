@@ -0,0 +1,104 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package common
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ingressNGINXNamespace is the namespace of the NGINX ingress controller
+	ingressNGINXNamespace = "ingress-nginx"
+
+	// OpenSearchHTTPPort is the port of the OpenSearch REST API
+	OpenSearchHTTPPort = 9200
+
+	// OpenSearchTransportPort is the port the OpenSearch nodes communicate with each other on
+	OpenSearchTransportPort = 9300
+
+	// PrometheusPort is the port of the Prometheus API
+	PrometheusPort = 9090
+
+	// EnvoyStatsPort is the port Prometheus scrapes the statistics of the Istio proxy sidecars from
+	EnvoyStatsPort = 15090
+
+	// BackupComponentLabel is the label of the pods of the backup and restore jobs, with the name of the component
+	BackupComponentLabel = "verrazzano.io/backup-component"
+)
+
+// defaultPorts are the default ports of the schemes of the URLs of the endpoints outside the cluster
+var defaultPorts = map[string]int{"http": 80, "https": 443, "ldap": 389, "ldaps": 636}
+
+// AuthProxyPeers returns the peers for the pods of the Verrazzano authentication proxy, which forwards the requests
+// of the users to the consoles and APIs of the components
+func AuthProxyPeers() []netv1.NetworkPolicyPeer {
+	return []netv1.NetworkPolicyPeer{
+		netpolicy.PodPeer(vzconst.VerrazzanoSystemNamespace, map[string]string{"app": "verrazzano-authproxy"}),
+	}
+}
+
+// IngressControllerPeers returns the peers for the pods of the NGINX ingress controller
+func IngressControllerPeers() []netv1.NetworkPolicyPeer {
+	return []netv1.NetworkPolicyPeer{
+		netpolicy.PodPeer(ingressNGINXNamespace, map[string]string{"app.kubernetes.io/component": "controller"}),
+	}
+}
+
+// OpenSearchPeers returns the peers for the pods of the OpenSearch nodes of each role
+func OpenSearchPeers() []netv1.NetworkPolicyPeer {
+	return []netv1.NetworkPolicyPeer{
+		netpolicy.PodPeer(vzconst.VerrazzanoSystemNamespace, map[string]string{"opensearch.verrazzano.io/role-master": "true"}),
+		netpolicy.PodPeer(vzconst.VerrazzanoSystemNamespace, map[string]string{"opensearch.verrazzano.io/role-data": "true"}),
+		netpolicy.PodPeer(vzconst.VerrazzanoSystemNamespace, map[string]string{"opensearch.verrazzano.io/role-ingest": "true"}),
+	}
+}
+
+// ClusterPeers returns the peer for all the pods of the cluster
+func ClusterPeers() []netv1.NetworkPolicyPeer {
+	return []netv1.NetworkPolicyPeer{netpolicy.ClusterPeer()}
+}
+
+// BackupJobPeers returns the peer for the pods of the backup and restore jobs of a component, in any namespace
+func BackupJobPeers(component string) []netv1.NetworkPolicyPeer {
+	return []netv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{},
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{BackupComponentLabel: component}},
+	}}
+}
+
+// GetURLPort returns the port of a URL, the default port of its scheme if it has none, or 0 if the URL is invalid
+func GetURLPort(endpoint string) int {
+	u, err := url.Parse(endpoint)
+	if err != nil || len(u.Hostname()) == 0 {
+		return 0
+	}
+	if len(u.Port()) > 0 {
+		port, err := strconv.Atoi(u.Port())
+		if err != nil {
+			return 0
+		}
+		return port
+	}
+	return defaultPorts[strings.ToLower(u.Scheme)]
+}
+
+// AppendPort returns the ports with the port appended, unless it is 0 or already in the ports
+func AppendPort(ports []int, port int) []int {
+	if port == 0 {
+		return ports
+	}
+	for _, p := range ports {
+		if p == port {
+			return ports
+		}
+	}
+	return append(ports, port)
+}
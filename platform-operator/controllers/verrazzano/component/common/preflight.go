@@ -0,0 +1,75 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// LoadBalancerCheckName is the name of the preflight check of the LoadBalancer support of the cluster
+	LoadBalancerCheckName = "LoadBalancerSupport"
+
+	// loadBalancerPendingThreshold is how long a LoadBalancer service can wait for an address before the cluster is
+	// considered to have no LoadBalancer support
+	loadBalancerPendingThreshold = 10 * time.Minute
+
+	// kindProviderPrefix is the prefix of the provider ID of the nodes of a Kind cluster, which has no LoadBalancer
+	// support
+	kindProviderPrefix = "kind://"
+)
+
+// CheckLoadBalancerSupport returns the preflight check of the LoadBalancer support of the cluster, for a component
+// with an ingress of the LoadBalancer type.  There is no API to find out whether a cluster supports LoadBalancer
+// services, so the check passes if a LoadBalancer service has an address or a node is managed by a cloud provider,
+// and fails if a LoadBalancer service has waited a long time for an address.  Otherwise the check is a warning.
+func CheckLoadBalancerSupport(ctx spi.ComponentContext, componentName string) (vzapi.PreflightCheck, error) {
+	check := vzapi.PreflightCheck{Name: LoadBalancerCheckName, Component: componentName}
+	services := corev1.ServiceList{}
+	if err := ctx.Client().List(context.TODO(), &services); err != nil {
+		return check, err
+	}
+	var pending []string
+	for _, service := range services.Items {
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		if len(service.Status.LoadBalancer.Ingress) > 0 {
+			check.Result = vzapi.PreflightCheckPassed
+			check.Message = fmt.Sprintf("The LoadBalancer service %s/%s has an address", service.Namespace, service.Name)
+			return check, nil
+		}
+		if time.Since(service.CreationTimestamp.Time) > loadBalancerPendingThreshold {
+			pending = append(pending, fmt.Sprintf("%s/%s", service.Namespace, service.Name))
+		}
+	}
+	if len(pending) > 0 {
+		check.Result = vzapi.PreflightCheckFailed
+		check.Message = fmt.Sprintf("The LoadBalancer services %s have waited more than %v for an address, install a LoadBalancer implementation in the cluster or use the NodePort ingress type",
+			strings.Join(pending, ", "), loadBalancerPendingThreshold)
+		return check, nil
+	}
+
+	nodes := corev1.NodeList{}
+	if err := ctx.Client().List(context.TODO(), &nodes); err != nil {
+		return check, err
+	}
+	for _, node := range nodes.Items {
+		if len(node.Spec.ProviderID) > 0 && !strings.HasPrefix(node.Spec.ProviderID, kindProviderPrefix) {
+			check.Result = vzapi.PreflightCheckPassed
+			check.Message = fmt.Sprintf("The node %s is managed by a cloud provider", node.Name)
+			return check, nil
+		}
+	}
+	check.Result = vzapi.PreflightCheckWarning
+	check.Message = "No LoadBalancer support was found in the cluster, the LoadBalancer services wait for an address unless a LoadBalancer implementation is installed"
+	return check, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	scheme2 "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestCheckLoadBalancerSupport tests the CheckLoadBalancerSupport function
+// GIVEN clusters with and without LoadBalancer services that have an address, and nodes managed by a cloud provider
+// WHEN CheckLoadBalancerSupport is called
+// THEN the check passes if the cluster supports LoadBalancer services, fails if a LoadBalancer service has waited
+// a long time for an address, and warns otherwise
+func TestCheckLoadBalancerSupport(t *testing.T) {
+	assigned := newTestLoadBalancer("assigned", time.Now())
+	assigned.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	cloudNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}, Spec: corev1.NodeSpec{ProviderID: "oci://ocid1.instance.oc1"}}
+	kindNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}, Spec: corev1.NodeSpec{ProviderID: "kind://docker/kind/kind-control-plane"}}
+
+	tests := []struct {
+		name     string
+		objects  []client.Object
+		expected vzapi.PreflightCheckResult
+	}{
+		{name: "assigned", objects: []client.Object{assigned, kindNode}, expected: vzapi.PreflightCheckPassed},
+		{name: "cloud provider", objects: []client.Object{newTestLoadBalancer("new", time.Now()), cloudNode}, expected: vzapi.PreflightCheckPassed},
+		{name: "pending", objects: []client.Object{newTestLoadBalancer("pending", time.Now().Add(-time.Hour)), cloudNode}, expected: vzapi.PreflightCheckFailed},
+		{name: "unknown", objects: []client.Object{kindNode}, expected: vzapi.PreflightCheckWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme2.Scheme).WithObjects(tt.objects...).Build()
+			check, err := CheckLoadBalancerSupport(spi.NewFakeContext(c, &vzapi.Verrazzano{}, false), "test")
+			assert.NoError(t, err)
+			assert.Equal(t, LoadBalancerCheckName, check.Name)
+			assert.Equal(t, "test", check.Component)
+			assert.Equal(t, tt.expected, check.Result, check.Message)
+		})
+	}
+}
+
+// newTestLoadBalancer returns a LoadBalancer service created at the given time
+func newTestLoadBalancer(name string, created time.Time) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}
+}
@@ -48,6 +48,8 @@ const (
   "type": "token",
   "description": "automation"
 }`
+	// LocalClusterPath Path of the local cluster, the cluster Rancher runs in, as in DELETE during PreUninstall
+	LocalClusterPath = "/v3/clusters/local"
 	// RancherServerURLPath Path to update server URL, as in PUT during PostInstall
 	RancherServerURLPath = "/v3/settings/server-url"
 	// Template body to PUT a new server url
@@ -241,6 +243,27 @@ func (r *RESTClient) PutServerURL() error {
 	return nil
 }
 
+// DeleteLocalCluster deletes the local cluster, so that Rancher removes the resources it deployed in the cluster.  A
+// local cluster that doesn't exist is ignored.
+func (r *RESTClient) DeleteLocalCluster() error {
+	url := fmt.Sprintf("https://%s%s", r.hostname, LocalClusterPath)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(contentTypeHeader, applicationJSON)
+	req.Header.Set(authorizationHeader, fmt.Sprintf("Bearer %s", r.accessToken))
+	resp, err := r.do(r.client, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("Failed to delete the local cluster: %s", resp.Status)
+	}
+	return nil
+}
+
 func parseTokenResponse(resp *http.Response) (string, error) {
 	defer resp.Body.Close()
 	tokenResponse := &TokenResponse{}
@@ -361,3 +361,62 @@ func TestPutServerURL(t *testing.T) {
 		})
 	}
 }
+
+// TestDeleteLocalCluster verifies how the Rancher client deletes the local cluster
+func TestDeleteLocalCluster(t *testing.T) {
+	var tests = []struct {
+		testName string
+		rest     *RESTClient
+		isErr    bool
+	}{
+		{
+			// GIVEN Rancher is running
+			//  WHEN DeleteLocalCluster is called
+			//  THEN DeleteLocalCluster should delete the local cluster
+			"should be able to delete the local cluster",
+			testClient(okResponse),
+			false,
+		},
+		{
+			// GIVEN The local cluster has already been deleted
+			//  WHEN DeleteLocalCluster is called
+			//  THEN DeleteLocalCluster should succeed
+			"should ignore a local cluster that doesn't exist",
+			testClient(func(h *http.Client, request *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 404,
+					Body:       io.NopCloser(strings.NewReader(`{"type":"error"}`)),
+				}, nil
+			}),
+			false,
+		},
+		{
+			// GIVEN Rancher is not running
+			//  WHEN DeleteLocalCluster is called
+			//  THEN DeleteLocalCluster should fail to delete the local cluster
+			"should fail to delete the local cluster if the request fails",
+			testClient(errorResponse),
+			true,
+		},
+		{
+			// GIVEN The access token is invalid
+			//  WHEN DeleteLocalCluster is called
+			//  THEN DeleteLocalCluster should fail to delete the local cluster
+			"should fail to delete the local cluster if the status is not expected",
+			testClient(unauthorizedResponse),
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			tt.rest.accessToken = dummyToken
+			err := tt.rest.DeleteLocalCluster()
+			if tt.isErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
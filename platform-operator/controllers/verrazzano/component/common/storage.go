@@ -0,0 +1,190 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/verrazzano/verrazzano/pkg/bom"
+	"github.com/verrazzano/verrazzano/pkg/k8sutil"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultStorageClassAnnotation is the annotation of the default StorageClass of the cluster
+	DefaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+	// BetaDefaultStorageClassAnnotation is the beta annotation of the default StorageClass, which is still used by
+	// some providers
+	BetaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// VolumeDefaults are the persistence defaults of a component, which apply when the component has no volume source or
+// the volume claim template has no storage request
+type VolumeDefaults struct {
+	// Persistent is true when the component has a persistent volume without a volume source
+	Persistent bool
+	// Size is the size of the persistent volume when the volume claim template has no storage request
+	Size string
+}
+
+// volumeSettings are the persistence settings of a component that are compared on an update
+type volumeSettings struct {
+	persistent   bool
+	storageClass string
+	size         *resource.Quantity
+}
+
+// getVolumeSettings returns the persistence settings of a component for a volume source
+func getVolumeSettings(vz *vzapi.Verrazzano, source *corev1.VolumeSource, defaults VolumeDefaults) (volumeSettings, error) {
+	settings := volumeSettings{}
+	if source != nil && source.EmptyDir != nil {
+		return settings, nil
+	}
+	claimSpec, err := vzconfig.FindVolumeClaimSpec(vz, source)
+	if err != nil {
+		return settings, err
+	}
+	settings.persistent = claimSpec != nil || defaults.Persistent
+	if !settings.persistent {
+		return settings, nil
+	}
+	if claimSpec != nil {
+		if claimSpec.StorageClassName != nil {
+			settings.storageClass = *claimSpec.StorageClassName
+		}
+		if storage, ok := claimSpec.Resources.Requests[corev1.ResourceStorage]; ok && !storage.IsZero() {
+			settings.size = &storage
+			return settings, nil
+		}
+	}
+	if len(defaults.Size) > 0 {
+		size, err := resource.ParseQuantity(defaults.Size)
+		if err != nil {
+			return settings, err
+		}
+		settings.size = &size
+	}
+	return settings, nil
+}
+
+// CompareVolumeSources compares the old and new volume sources of a component.  The persistence and the StorageClass
+// of the volumes can not be changed, and the volumes can only grow when their StorageClass allows volume expansion.
+func CompareVolumeSources(old *vzapi.Verrazzano, new *vzapi.Verrazzano, oldSource *corev1.VolumeSource, newSource *corev1.VolumeSource, defaults VolumeDefaults, jsonName string) error {
+	oldSettings, err := getVolumeSettings(old, oldSource, defaults)
+	if err != nil {
+		return err
+	}
+	newSettings, err := getVolumeSettings(new, newSource, defaults)
+	if err != nil {
+		return err
+	}
+	if oldSettings.persistent != newSettings.persistent {
+		return fmt.Errorf("Can not change the persistence of the volumes of %s", jsonName)
+	}
+	if !newSettings.persistent {
+		return nil
+	}
+	if oldSettings.storageClass != newSettings.storageClass {
+		return fmt.Errorf("Can not change the StorageClass of the volumes of %s", jsonName)
+	}
+	if oldSettings.size == nil || newSettings.size == nil {
+		if oldSettings.size != newSettings.size {
+			return fmt.Errorf("Can not change volume settings for %s", jsonName)
+		}
+		return nil
+	}
+	switch newSettings.size.Cmp(*oldSettings.size) {
+	case -1:
+		return fmt.Errorf("Can not reduce the size of the volumes of %s", jsonName)
+	case 1:
+		return checkVolumeExpansion(newSettings.storageClass, jsonName)
+	}
+	return nil
+}
+
+// checkVolumeExpansion checks that a StorageClass allows the expansion of volumes, the default StorageClass of the
+// cluster is checked when no StorageClass is given
+func checkVolumeExpansion(storageClass string, jsonName string) error {
+	client, err := k8sutil.GetGoClient()
+	if err != nil {
+		return err
+	}
+	if len(storageClass) == 0 {
+		classes, err := client.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for i := range classes.Items {
+			class := &classes.Items[i]
+			if class.Annotations[DefaultStorageClassAnnotation] == "true" || class.Annotations[BetaDefaultStorageClassAnnotation] == "true" {
+				if class.AllowVolumeExpansion == nil || !*class.AllowVolumeExpansion {
+					return fmt.Errorf("Can not resize the volumes of %s, the default StorageClass %s does not allow volume expansion", jsonName, class.Name)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("Can not resize the volumes of %s, there is no default StorageClass", jsonName)
+	}
+	class, err := client.StorageV1().StorageClasses().Get(context.TODO(), storageClass, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Can not resize the volumes of %s, failed getting the StorageClass %s: %v", jsonName, storageClass, err)
+	}
+	if class.AllowVolumeExpansion == nil || !*class.AllowVolumeExpansion {
+		return fmt.Errorf("Can not resize the volumes of %s, the StorageClass %s does not allow volume expansion", jsonName, storageClass)
+	}
+	return nil
+}
+
+// AppendPersistenceOverrides appends the Helm overrides of the persistence settings of a chart found under a prefix,
+// an emptyDir disables the persistence and a volume claim template sets the StorageClass, size and access mode
+func AppendPersistenceOverrides(vz *vzapi.Verrazzano, source *corev1.VolumeSource, prefix string, kvs []bom.KeyValue) ([]bom.KeyValue, error) {
+	if source == nil {
+		return kvs, nil
+	}
+	if source.EmptyDir != nil {
+		return append(kvs, bom.KeyValue{
+			Key:   prefix + ".enabled",
+			Value: "false",
+		}), nil
+	}
+	storageSpec, err := vzconfig.FindVolumeClaimSpec(vz, source)
+	if err != nil {
+		return kvs, err
+	}
+	storageClass := storageSpec.StorageClassName
+	if storageClass != nil && len(*storageClass) > 0 {
+		kvs = append(kvs, bom.KeyValue{
+			Key:       prefix + ".storageClass",
+			Value:     *storageClass,
+			SetString: true,
+		})
+	}
+	storage := storageSpec.Resources.Requests.Storage()
+	if storageSpec.Resources.Requests != nil && !storage.IsZero() {
+		kvs = append(kvs, bom.KeyValue{
+			Key:       prefix + ".size",
+			Value:     storage.String(),
+			SetString: true,
+		})
+	}
+	accessModes := storageSpec.AccessModes
+	if len(accessModes) > 0 {
+		// The charts only allow a single AccessMode value, so just choose the first
+		kvs = append(kvs, bom.KeyValue{
+			Key:       prefix + ".accessMode",
+			Value:     string(accessModes[0]),
+			SetString: true,
+		})
+	}
+	return append(kvs, bom.KeyValue{
+		Key:   prefix + ".enabled",
+		Value: "true",
+	}), nil
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/bom"
+	"github.com/verrazzano/verrazzano/pkg/k8sutil"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// newStorageVZ returns a Verrazzano CR with a volume claim template of the given size and StorageClass
+func newStorageVZ(size string, storageClass string) *vzapi.Verrazzano {
+	spec := corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+		},
+	}
+	if len(storageClass) > 0 {
+		spec.StorageClassName = &storageClass
+	}
+	return &vzapi.Verrazzano{
+		Spec: vzapi.VerrazzanoSpec{
+			VolumeClaimSpecTemplates: []vzapi.VolumeClaimSpecTemplate{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}, Spec: spec},
+			},
+		},
+	}
+}
+
+// newStorageClass returns a StorageClass that may allow volume expansion and be the default of the cluster
+func newStorageClass(name string, expansion bool, isDefault bool) *storagev1.StorageClass {
+	class := &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+		AllowVolumeExpansion: &expansion,
+	}
+	if isDefault {
+		class.Annotations[DefaultStorageClassAnnotation] = "true"
+	}
+	return class
+}
+
+// TestCompareVolumeSources tests the CompareVolumeSources function
+// GIVEN the old and new volume sources of a component
+// WHEN CompareVolumeSources is called
+// THEN changes of the persistence and StorageClass are rejected, and the volumes can only grow when the StorageClass
+// allows volume expansion
+func TestCompareVolumeSources(t *testing.T) {
+	k8sutil.SetFakeClient(k8sfake.NewSimpleClientset(
+		newStorageClass("expandable", true, true),
+		newStorageClass("fixed", false, false)))
+	defer k8sutil.ClearFakeClient()
+
+	emptyDir := &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	claim := &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}
+	persistent := VolumeDefaults{Persistent: true, Size: "50Gi"}
+	tests := []struct {
+		name        string
+		old         *vzapi.Verrazzano
+		new         *vzapi.Verrazzano
+		oldSource   *corev1.VolumeSource
+		newSource   *corev1.VolumeSource
+		defaults    VolumeDefaults
+		expectedErr string
+	}{
+		{
+			name:      "NoChange",
+			old:       newStorageVZ("1Gi", "fixed"),
+			new:       newStorageVZ("1Gi", "fixed"),
+			oldSource: claim,
+			newSource: claim,
+		},
+		{
+			name:      "EmptyDirToEmptyDir",
+			old:       &vzapi.Verrazzano{},
+			new:       &vzapi.Verrazzano{},
+			oldSource: emptyDir,
+			newSource: emptyDir,
+		},
+		{
+			name:        "EmptyDirToPVC",
+			old:         newStorageVZ("1Gi", ""),
+			new:         newStorageVZ("1Gi", ""),
+			oldSource:   emptyDir,
+			newSource:   claim,
+			expectedErr: "Can not change the persistence of the volumes of test",
+		},
+		{
+			name:        "NoVolumeSourceToPVC",
+			old:         newStorageVZ("1Gi", ""),
+			new:         newStorageVZ("1Gi", ""),
+			newSource:   claim,
+			expectedErr: "Can not change the persistence of the volumes of test",
+		},
+		{
+			name:      "DefaultVolumeToPVCOfTheSameSize",
+			old:       newStorageVZ("50Gi", ""),
+			new:       newStorageVZ("50Gi", ""),
+			newSource: claim,
+			defaults:  persistent,
+		},
+		{
+			name:      "DefaultVolumeToLargerPVC",
+			old:       newStorageVZ("100Gi", ""),
+			new:       newStorageVZ("100Gi", ""),
+			newSource: claim,
+			defaults:  persistent,
+		},
+		{
+			name:        "ChangeStorageClass",
+			old:         newStorageVZ("1Gi", "fixed"),
+			new:         newStorageVZ("1Gi", "expandable"),
+			oldSource:   claim,
+			newSource:   claim,
+			expectedErr: "Can not change the StorageClass of the volumes of test",
+		},
+		{
+			name:        "ReduceSize",
+			old:         newStorageVZ("2Gi", "expandable"),
+			new:         newStorageVZ("1Gi", "expandable"),
+			oldSource:   claim,
+			newSource:   claim,
+			expectedErr: "Can not reduce the size of the volumes of test",
+		},
+		{
+			name:      "GrowWithExpandableStorageClass",
+			old:       newStorageVZ("1Gi", "expandable"),
+			new:       newStorageVZ("2Gi", "expandable"),
+			oldSource: claim,
+			newSource: claim,
+		},
+		{
+			name:      "GrowWithExpandableDefaultStorageClass",
+			old:       newStorageVZ("1Gi", ""),
+			new:       newStorageVZ("2Gi", ""),
+			oldSource: claim,
+			newSource: claim,
+		},
+		{
+			name:        "GrowWithFixedStorageClass",
+			old:         newStorageVZ("1Gi", "fixed"),
+			new:         newStorageVZ("2Gi", "fixed"),
+			oldSource:   claim,
+			newSource:   claim,
+			expectedErr: "the StorageClass fixed does not allow volume expansion",
+		},
+		{
+			name:        "GrowWithMissingStorageClass",
+			old:         newStorageVZ("1Gi", "missing"),
+			new:         newStorageVZ("2Gi", "missing"),
+			oldSource:   claim,
+			newSource:   claim,
+			expectedErr: "failed getting the StorageClass missing",
+		},
+		{
+			name:        "MissingVolumeClaimTemplate",
+			old:         newStorageVZ("1Gi", ""),
+			new:         &vzapi.Verrazzano{},
+			oldSource:   claim,
+			newSource:   claim,
+			expectedErr: "did not find matching storage volume template for claim data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CompareVolumeSources(tt.old, tt.new, tt.oldSource, tt.newSource, tt.defaults, "test")
+			if len(tt.expectedErr) > 0 {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCheckVolumeExpansionWithoutDefaultStorageClass tests the checkVolumeExpansion function
+// GIVEN a cluster without a default StorageClass
+// WHEN checkVolumeExpansion is called without a StorageClass
+// THEN an error is returned
+func TestCheckVolumeExpansionWithoutDefaultStorageClass(t *testing.T) {
+	k8sutil.SetFakeClient(k8sfake.NewSimpleClientset(newStorageClass("expandable", true, false)))
+	defer k8sutil.ClearFakeClient()
+
+	err := checkVolumeExpansion("", "test")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "there is no default StorageClass")
+	assert.NoError(t, checkVolumeExpansion("expandable", "test"))
+}
+
+// TestAppendPersistenceOverrides tests the AppendPersistenceOverrides function
+// GIVEN a volume source
+// WHEN AppendPersistenceOverrides is called
+// THEN the persistence overrides of the chart are appended under the prefix
+func TestAppendPersistenceOverrides(t *testing.T) {
+	vz := newStorageVZ("2Gi", "fast")
+
+	kvs, err := AppendPersistenceOverrides(vz, nil, "persistence", []bom.KeyValue{})
+	assert.NoError(t, err)
+	assert.Empty(t, kvs)
+
+	kvs, err = AppendPersistenceOverrides(vz, &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}, "persistence", []bom.KeyValue{})
+	assert.NoError(t, err)
+	assert.Equal(t, []bom.KeyValue{{Key: "persistence.enabled", Value: "false"}}, kvs)
+
+	claim := &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}
+	kvs, err = AppendPersistenceOverrides(vz, claim, "customLogos", []bom.KeyValue{})
+	assert.NoError(t, err)
+	assert.Equal(t, []bom.KeyValue{
+		{Key: "customLogos.storageClass", Value: "fast", SetString: true},
+		{Key: "customLogos.size", Value: "2Gi", SetString: true},
+		{Key: "customLogos.accessMode", Value: "ReadWriteOnce", SetString: true},
+		{Key: "customLogos.enabled", Value: "true"},
+	}, kvs)
+
+	_, err = AppendPersistenceOverrides(&vzapi.Verrazzano{}, claim, "persistence", []bom.KeyValue{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package common
+
+import (
+	"context"
+	"strings"
+
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResourceMatchFunc selects the resources deleted by DeleteResources and ForceDeleteResources
+type ResourceMatchFunc func(obj clipkg.Object) bool
+
+// NameContains returns a function that selects the resources whose name contains one of the strings
+func NameContains(strs ...string) ResourceMatchFunc {
+	return func(obj clipkg.Object) bool {
+		for _, str := range strs {
+			if strings.Contains(obj.GetName(), str) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CRDGroupHasSuffix returns a function that selects the CustomResourceDefinitions whose group ends with one of the
+// suffixes
+func CRDGroupHasSuffix(suffixes ...string) ResourceMatchFunc {
+	return func(obj clipkg.Object) bool {
+		crd, ok := obj.(*apiextv1.CustomResourceDefinition)
+		if !ok {
+			return false
+		}
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(crd.Spec.Group, suffix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DeleteResources lists the resources and deletes the ones selected by the match function, a nil function selects
+// all the resources.  Nothing is deleted if the CRD of the resources is not installed.
+func DeleteResources(ctx spi.ComponentContext, list clipkg.ObjectList, match ResourceMatchFunc, opts ...clipkg.ListOption) error {
+	return deleteResources(ctx, list, match, false, opts...)
+}
+
+// ForceDeleteResources is DeleteResources for resources whose finalizers are not processed once the component is
+// uninstalled, the finalizers are removed before the resources are deleted
+func ForceDeleteResources(ctx spi.ComponentContext, list clipkg.ObjectList, match ResourceMatchFunc, opts ...clipkg.ListOption) error {
+	return deleteResources(ctx, list, match, true, opts...)
+}
+
+// DeleteNamespaces removes the finalizers of the namespaces and deletes them, the namespaces that don't exist are
+// skipped
+func DeleteNamespaces(ctx spi.ComponentContext, names ...string) error {
+	for _, name := range names {
+		ns := &corev1.Namespace{}
+		if err := ctx.Client().Get(context.TODO(), types.NamespacedName{Name: name}, ns); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return ctx.Log().ErrorfNewErr("Failed getting namespace %s: %v", name, err)
+		}
+		if err := deleteObject(ctx, ns, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteObject deletes the object, it is skipped if it doesn't exist
+func DeleteObject(ctx spi.ComponentContext, obj clipkg.Object) error {
+	return deleteObject(ctx, obj, false)
+}
+
+// deleteResources deletes the selected resources of the list, after removing their finalizers if force is true
+func deleteResources(ctx spi.ComponentContext, list clipkg.ObjectList, match ResourceMatchFunc, force bool, opts ...clipkg.ListOption) error {
+	if err := ctx.Client().List(context.TODO(), list, opts...); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return ctx.Log().ErrorfNewErr("Failed listing the resources to delete: %v", err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return ctx.Log().ErrorfNewErr("Failed listing the resources to delete: %v", err)
+	}
+	for _, item := range items {
+		obj, ok := item.(clipkg.Object)
+		if !ok || (match != nil && !match(obj)) {
+			continue
+		}
+		if err := deleteObject(ctx, obj, force); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteObject deletes the object, after removing its finalizers if force is true
+func deleteObject(ctx spi.ComponentContext, obj clipkg.Object, force bool) error {
+	if force && len(obj.GetFinalizers()) > 0 {
+		patch := clipkg.MergeFrom(obj.DeepCopyObject().(clipkg.Object))
+		obj.SetFinalizers(nil)
+		if err := ctx.Client().Patch(context.TODO(), obj, patch); err != nil && !errors.IsNotFound(err) {
+			return ctx.Log().ErrorfNewErr("Failed removing the finalizers of %s: %v", getObjectName(obj), err)
+		}
+	}
+	if err := ctx.Client().Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+		return ctx.Log().ErrorfNewErr("Failed deleting %s: %v", getObjectName(obj), err)
+	}
+	return nil
+}
+
+// getObjectName returns the namespace and name of the object, or its name if it is cluster scoped
+func getObjectName(obj clipkg.Object) string {
+	if len(obj.GetNamespace()) == 0 {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestDeleteResources tests the DeleteResources function
+// GIVEN cluster roles with and without a matching name
+// WHEN DeleteResources is called
+// THEN only the cluster roles with a matching name are deleted
+func TestDeleteResources(t *testing.T) {
+	a := assert.New(t)
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "istio-reader-istio-system"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "istiod-istio-system"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"}},
+	).Build()
+
+	a.NoError(DeleteResources(spi.NewFakeContext(client, nil, false), &rbacv1.ClusterRoleList{}, NameContains("istio-reader", "istiod")))
+
+	roles := &rbacv1.ClusterRoleList{}
+	a.NoError(client.List(context.TODO(), roles))
+	a.Len(roles.Items, 1)
+	a.Equal("cluster-admin", roles.Items[0].Name)
+}
+
+// TestDeleteResourcesInNamespace tests the DeleteResources function
+// GIVEN secrets in two namespaces
+// WHEN DeleteResources is called for a namespace without a match function
+// THEN all the secrets of the namespace are deleted
+func TestDeleteResourcesInNamespace(t *testing.T) {
+	a := assert.New(t)
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "s1"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "s2"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "s1"}},
+	).Build()
+
+	a.NoError(DeleteResources(spi.NewFakeContext(client, nil, false), &corev1.SecretList{}, nil, clipkg.InNamespace("ns1")))
+
+	secrets := &corev1.SecretList{}
+	a.NoError(client.List(context.TODO(), secrets))
+	a.Len(secrets.Items, 1)
+	a.Equal("ns2", secrets.Items[0].Namespace)
+}
+
+// TestForceDeleteResources tests the ForceDeleteResources function
+// GIVEN CRDs with finalizers
+// WHEN ForceDeleteResources is called with a CRD group suffix
+// THEN the CRDs of the group are deleted and the other CRDs are kept
+func TestForceDeleteResources(t *testing.T) {
+	a := assert.New(t)
+	scheme := runtime.NewScheme()
+	_ = apiextv1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "certificates.cert-manager.io", Finalizers: []string{"test"}},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Group: "cert-manager.io"},
+		},
+		&apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "challenges.acme.cert-manager.io"},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Group: "acme.cert-manager.io"},
+		},
+		&apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "gateways.networking.istio.io", Finalizers: []string{"test"}},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Group: "networking.istio.io"},
+		},
+	).Build()
+
+	a.NoError(ForceDeleteResources(spi.NewFakeContext(client, nil, false), &apiextv1.CustomResourceDefinitionList{}, CRDGroupHasSuffix("cert-manager.io")))
+
+	crds := &apiextv1.CustomResourceDefinitionList{}
+	a.NoError(client.List(context.TODO(), crds))
+	a.Len(crds.Items, 1)
+	a.Equal("gateways.networking.istio.io", crds.Items[0].Name)
+	a.Equal([]string{"test"}, crds.Items[0].Finalizers)
+}
+
+// TestDeleteResourcesNotRegistered tests the DeleteResources function
+// GIVEN a client that can't list the resources
+// WHEN DeleteResources is called
+// THEN an error is returned
+func TestDeleteResourcesNotRegistered(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+	assert.Error(t, DeleteResources(spi.NewFakeContext(client, nil, false), &apiextv1.CustomResourceDefinitionList{}, nil))
+}
+
+// TestDeleteNamespaces tests the DeleteNamespaces function
+// GIVEN a namespace with a finalizer
+// WHEN DeleteNamespaces is called for the namespace and for a namespace that doesn't exist
+// THEN the namespace is deleted and no error is returned
+func TestDeleteNamespaces(t *testing.T) {
+	a := assert.New(t)
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Finalizers: []string{"test"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}},
+	).Build()
+
+	a.NoError(DeleteNamespaces(spi.NewFakeContext(client, nil, false), "ns1", "missing"))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Name: "ns1"}, &corev1.Namespace{})
+	a.True(errors.IsNotFound(err))
+	a.NoError(client.Get(context.TODO(), types.NamespacedName{Name: "ns2"}, &corev1.Namespace{}))
+}
+
+// TestDeleteObject tests the DeleteObject function
+// GIVEN a secret
+// WHEN DeleteObject is called for the secret and for a secret that doesn't exist
+// THEN the secret is deleted and no error is returned
+func TestDeleteObject(t *testing.T) {
+	a := assert.New(t)
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "s1"}},
+	).Build()
+	ctx := spi.NewFakeContext(client, nil, false)
+
+	a.NoError(DeleteObject(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "s1"}}))
+	a.NoError(DeleteObject(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "missing"}}))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "s1"}, &corev1.Secret{})
+	a.True(errors.IsNotFound(err))
+}
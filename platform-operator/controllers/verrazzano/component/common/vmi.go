@@ -6,7 +6,6 @@ package common
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	globalconst "github.com/verrazzano/verrazzano/pkg/constants"
@@ -47,6 +46,9 @@ type ResourceRequestValues struct {
 // VMIMutateFunc is the function used to populate the components in VMI
 type VMIMutateFunc func(ctx spi.ComponentContext, storage *ResourceRequestValues, vmi *vmov1.VerrazzanoMonitoringInstance, existingVMI *vmov1.VerrazzanoMonitoringInstance) error
 
+// VMIDisableFunc is the function used to disable a component in the VMI
+type VMIDisableFunc func(vmi *vmov1.VerrazzanoMonitoringInstance)
+
 // NewVMI creates a new VerrazzanoMonitoringInstance object with default values
 func NewVMI() *vmov1.VerrazzanoMonitoringInstance {
 	return &vmov1.VerrazzanoMonitoringInstance{
@@ -99,6 +101,14 @@ func CreateOrUpdateVMI(ctx spi.ComponentContext, updateFunc VMIMutateFunc) error
 		vmi.Spec.AutoSecret = true
 		vmi.Spec.SecretsName = constants.VMISecret
 		vmi.Spec.CascadingDelete = true
+		if existingVMI == nil {
+			// The StorageClass of the PVCs created by the VMO can not be changed afterwards
+			storageClass, err := GetVMIStorageClass(effectiveCR)
+			if err != nil {
+				return err
+			}
+			vmi.Spec.StorageClass = storageClass
+		}
 		return updateFunc(ctx, storage, vmi, existingVMI)
 	})
 	if err != nil {
@@ -107,6 +117,34 @@ func CreateOrUpdateVMI(ctx spi.ComponentContext, updateFunc VMIMutateFunc) error
 	return nil
 }
 
+// DisableVMIComponent disables a component in the VMI resource.  The VMI is deleted once none of the
+// components it manages are enabled.
+func DisableVMIComponent(ctx spi.ComponentContext, disableFunc VMIDisableFunc) error {
+	if ctx.IsDryRun() {
+		return nil
+	}
+	vmi := NewVMI()
+	err := ctx.Client().Get(context.TODO(), types.NamespacedName{Name: vmi.Name, Namespace: vmi.Namespace}, vmi)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return ctx.Log().ErrorfNewErr("Failed getting VMI: %v", err)
+	}
+	disableFunc(vmi)
+	if !vmi.Spec.Grafana.Enabled && !vmi.Spec.Kibana.Enabled && !vmi.Spec.Elasticsearch.Enabled && !vmi.Spec.Prometheus.Enabled {
+		ctx.Log().Oncef("Deleting VMI %s/%s, no enabled components remain", vmi.Namespace, vmi.Name)
+		if err := ctx.Client().Delete(context.TODO(), vmi); err != nil && !errors.IsNotFound(err) {
+			return ctx.Log().ErrorfNewErr("Failed deleting VMI: %v", err)
+		}
+		return nil
+	}
+	if err := ctx.Client().Update(context.TODO(), vmi); err != nil {
+		return ctx.Log().ErrorfNewErr("Failed updating VMI: %v", err)
+	}
+	return nil
+}
+
 // EnsureVMISecret creates or updates the VMI secret
 func EnsureVMISecret(cli client.Client) error {
 	secret := &corev1.Secret{
@@ -185,27 +223,81 @@ func EnsureBackupSecret(cli client.Client) error {
 
 // FindStorageOverride finds and returns the correct storage override from the effective CR
 func FindStorageOverride(effectiveCR *vzapi.Verrazzano) (*ResourceRequestValues, error) {
-	if effectiveCR == nil || effectiveCR.Spec.DefaultVolumeSource == nil {
+	return FindComponentStorageOverride(effectiveCR, nil)
+}
+
+// FindComponentStorageOverride finds and returns the storage override of a VMI component from its volume source, the
+// DefaultVolumeSource of the effective CR is used when the component has none
+func FindComponentStorageOverride(effectiveCR *vzapi.Verrazzano, componentSource *corev1.VolumeSource) (*ResourceRequestValues, error) {
+	volumeSource := vzconfig.GetVolumeSource(effectiveCR, componentSource)
+	if volumeSource == nil {
 		return nil, nil
 	}
-	defaultVolumeSource := effectiveCR.Spec.DefaultVolumeSource
-	if defaultVolumeSource.EmptyDir != nil {
+	if volumeSource.EmptyDir != nil {
 		return &ResourceRequestValues{
 			Storage: "",
 		}, nil
 	}
-	if defaultVolumeSource.PersistentVolumeClaim != nil {
-		pvcClaim := defaultVolumeSource.PersistentVolumeClaim
-		storageSpec, found := vzconfig.FindVolumeTemplate(pvcClaim.ClaimName, effectiveCR.Spec.VolumeClaimSpecTemplates)
-		if !found {
-			return nil, fmt.Errorf("Failed, did not find matching storage volume template for claim %s", pvcClaim.ClaimName)
+	storageSpec, err := vzconfig.FindVolumeClaimSpec(effectiveCR, volumeSource)
+	if err != nil {
+		return nil, err
+	}
+	storageString := storageSpec.Resources.Requests.Storage().String()
+	return &ResourceRequestValues{
+		Storage: storageString,
+	}, nil
+}
+
+// GetComponentStorageOverride returns the storage override of a VMI component, which is the given storage override of
+// the DefaultVolumeSource when the component has no volume source
+func GetComponentStorageOverride(effectiveCR *vzapi.Verrazzano, componentSource *corev1.VolumeSource, storage *ResourceRequestValues) (*ResourceRequestValues, error) {
+	if componentSource == nil {
+		return storage, nil
+	}
+	return FindComponentStorageOverride(effectiveCR, componentSource)
+}
+
+// GetVMIStorageClass returns the StorageClass of the volume claim templates used by the VMI components, which must
+// all use the same StorageClass since it is set for the whole VMI.  Nil is returned when no template names one.
+func GetVMIStorageClass(effectiveCR *vzapi.Verrazzano) (*string, error) {
+	sources := []*corev1.VolumeSource{effectiveCR.Spec.DefaultVolumeSource}
+	if prometheus := effectiveCR.Spec.Components.Prometheus; prometheus != nil {
+		sources = append(sources, prometheus.VolumeSource)
+	}
+	if grafana := effectiveCR.Spec.Components.Grafana; grafana != nil {
+		sources = append(sources, grafana.VolumeSource)
+	}
+	if opensearch := effectiveCR.Spec.Components.Elasticsearch; opensearch != nil {
+		for _, node := range opensearch.Nodes {
+			sources = append(sources, node.VolumeSource)
 		}
-		storageString := storageSpec.Resources.Requests.Storage().String()
-		return &ResourceRequestValues{
-			Storage: storageString,
-		}, nil
 	}
-	return nil, fmt.Errorf("Failed, unsupported volume source: %v", defaultVolumeSource)
+	var storageClass *string
+	for _, source := range sources {
+		storageSpec, err := vzconfig.FindVolumeClaimSpec(effectiveCR, source)
+		if err != nil {
+			return nil, err
+		}
+		if storageSpec == nil || storageSpec.StorageClassName == nil || len(*storageSpec.StorageClassName) == 0 {
+			continue
+		}
+		if storageClass != nil && *storageClass != *storageSpec.StorageClassName {
+			return nil, fmt.Errorf("The volume claim templates of the VMI components must use the same StorageClass, found %s and %s",
+				*storageClass, *storageSpec.StorageClassName)
+		}
+		storageClass = storageSpec.StorageClassName
+	}
+	return storageClass, nil
+}
+
+// ValidateVMIVolumeSource validates the volume source of a VMI component, and checks that the volume claim templates
+// of the VMI components use the same StorageClass
+func ValidateVMIVolumeSource(vz *vzapi.Verrazzano, source *corev1.VolumeSource, jsonName string) error {
+	if err := vzconfig.ValidateVolumeSource(vz, source, jsonName); err != nil {
+		return err
+	}
+	_, err := GetVMIStorageClass(vz)
+	return err
 }
 
 // IsVMISecretReady returns true if the VMI secret is present in the system namespace
@@ -237,19 +329,14 @@ func CreateAndLabelVMINamespaces(ctx spi.ComponentContext) error {
 
 // CompareStorageOverrides compares storage override settings for the VMI components
 func CompareStorageOverrides(old *vzapi.Verrazzano, new *vzapi.Verrazzano, jsonName string) error {
-	// compare the storage overrides and reject if the type or size is different
-	oldSetting, err := FindStorageOverride(old)
-	if err != nil {
-		return err
-	}
-	newSetting, err := FindStorageOverride(new)
-	if err != nil {
-		return err
-	}
-	if !reflect.DeepEqual(oldSetting, newSetting) {
-		return fmt.Errorf("Can not change volume settings for %s", jsonName)
-	}
-	return nil
+	return CompareVMIVolumeSources(old, new, nil, nil, jsonName)
+}
+
+// CompareVMIVolumeSources compares the old and new volume sources of a VMI component, the DefaultVolumeSource is
+// compared when the component has none
+func CompareVMIVolumeSources(old *vzapi.Verrazzano, new *vzapi.Verrazzano, oldSource *corev1.VolumeSource, newSource *corev1.VolumeSource, jsonName string) error {
+	return CompareVolumeSources(old, new, vzconfig.GetVolumeSource(old, oldSource), vzconfig.GetVolumeSource(new, newSource),
+		VolumeDefaults{Persistent: true, Size: defaultStorageSize}, jsonName)
 }
 
 // CheckIngressesAndCerts checks the Ingress and Certs for the VMI components in the Post- function
@@ -344,6 +431,26 @@ func SetStorageSize(storage *ResourceRequestValues, storageObject *vmov1.Storage
 	}
 }
 
+// SetResources copies the requests and limits of the kubernetes section of a component to the VMI resources, the
+// quantities that are not set keep their default value
+func SetResources(spec *vzapi.CommonKubernetesSpec, resources *vmov1.Resources) {
+	if spec == nil || spec.Resources == nil {
+		return
+	}
+	if cpu, ok := spec.Resources.Requests[corev1.ResourceCPU]; ok {
+		resources.RequestCPU = cpu.String()
+	}
+	if memory, ok := spec.Resources.Requests[corev1.ResourceMemory]; ok {
+		resources.RequestMemory = memory.String()
+	}
+	if cpu, ok := spec.Resources.Limits[corev1.ResourceCPU]; ok {
+		resources.LimitCPU = cpu.String()
+	}
+	if memory, ok := spec.Resources.Limits[corev1.ResourceMemory]; ok {
+		resources.LimitMemory = memory.String()
+	}
+}
+
 // ExportVMOHelmChart adds necessary annotations to verrazzano-monitoring-operator objects which allows them to be
 // managed by the verrazzano-monitoring-operator helm chart.  This is needed for the case when VMO was
 // previously installed by the verrazzano helm charrt.
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -314,3 +315,123 @@ func Test_SetStorageSize(t *testing.T) {
 	SetStorageSize(storageRequest, storageObject)
 	assert.Equal(t, storageSize, storageObject.Size)
 }
+
+// Test_GetComponentStorageOverride tests the GetComponentStorageOverride function
+// GIVEN a Verrazzano CR with a volume claim template
+// WHEN GetComponentStorageOverride is called with or without the volume source of a VMI component
+// THEN the storage override of the component takes precedence over the one of the DefaultVolumeSource
+func Test_GetComponentStorageOverride(t *testing.T) {
+	vz := newStorageVZ("100Gi", "")
+	defaultOverride := &ResourceRequestValues{Storage: "50Gi"}
+
+	storage, err := GetComponentStorageOverride(vz, nil, defaultOverride)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultOverride, storage)
+
+	storage, err = GetComponentStorageOverride(vz, &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}, defaultOverride)
+	assert.NoError(t, err)
+	assert.Equal(t, "", storage.Storage)
+
+	claim := &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}
+	storage, err = GetComponentStorageOverride(vz, claim, defaultOverride)
+	assert.NoError(t, err)
+	assert.Equal(t, "100Gi", storage.Storage)
+
+	_, err = GetComponentStorageOverride(&vzapi.Verrazzano{}, claim, defaultOverride)
+	assert.Error(t, err)
+}
+
+// Test_GetVMIStorageClass tests the GetVMIStorageClass function
+// GIVEN a Verrazzano CR whose VMI components reference volume claim templates
+// WHEN GetVMIStorageClass is called
+// THEN the StorageClass of the templates is returned, or an error when the components use different StorageClasses
+func Test_GetVMIStorageClass(t *testing.T) {
+	claim := func(name string) *corev1.VolumeSource {
+		return &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: name}}
+	}
+	fast := "fast"
+	slow := "slow"
+	vz := &vzapi.Verrazzano{
+		Spec: vzapi.VerrazzanoSpec{
+			DefaultVolumeSource: claim("default"),
+			VolumeClaimSpecTemplates: []vzapi.VolumeClaimSpecTemplate{
+				{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "fast"}, Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &fast}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "slow"}, Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &slow}},
+			},
+		},
+	}
+
+	storageClass, err := GetVMIStorageClass(vz)
+	assert.NoError(t, err)
+	assert.Nil(t, storageClass)
+
+	vz.Spec.Components.Prometheus = &vzapi.PrometheusComponent{VolumeSource: claim("fast")}
+	vz.Spec.Components.Elasticsearch = &vzapi.ElasticsearchComponent{
+		Nodes: []vzapi.OpenSearchNode{{Name: "data", VolumeSource: claim("fast")}},
+	}
+	storageClass, err = GetVMIStorageClass(vz)
+	assert.NoError(t, err)
+	assert.Equal(t, "fast", *storageClass)
+
+	vz.Spec.Components.Grafana = &vzapi.GrafanaComponent{VolumeSource: claim("slow")}
+	_, err = GetVMIStorageClass(vz)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must use the same StorageClass, found fast and slow")
+	assert.Error(t, ValidateVMIVolumeSource(vz, nil, "test"))
+}
+
+// Test_SetResources tests the SetResources function
+func Test_SetResources(t *testing.T) {
+	// GIVEN no kubernetes section
+	// WHEN the resources are set
+	// THEN we expect the default resources to be unchanged
+	resources := &vmov1.Resources{RequestMemory: "48Mi"}
+	SetResources(nil, resources)
+	assert.Equal(t, vmov1.Resources{RequestMemory: "48Mi"}, *resources)
+
+	// GIVEN a kubernetes section with a CPU request and a memory limit
+	// WHEN the resources are set
+	// THEN we expect those quantities to be set, and the default memory request to be kept
+	SetResources(&vzapi.CommonKubernetesSpec{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+	}, resources)
+	assert.Equal(t, vmov1.Resources{RequestMemory: "48Mi", RequestCPU: "250m", LimitMemory: "1Gi"}, *resources)
+}
+
+// TestDisableVMIComponent tests the DisableVMIComponent function
+// GIVEN a VMI with Grafana and OpenSearch enabled
+//  WHEN I call DisableVMIComponent for each of the enabled components
+//  THEN the VMI is updated, then deleted once no components remain enabled
+func TestDisableVMIComponent(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = vmov1.AddToScheme(scheme)
+	vmi := NewVMI()
+	vmi.Spec.Grafana.Enabled = true
+	vmi.Spec.Elasticsearch.Enabled = true
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmi).Build()
+	ctx := spi.NewFakeContext(fakeClient, &vzapi.Verrazzano{}, false)
+
+	err := DisableVMIComponent(ctx, func(vmi *vmov1.VerrazzanoMonitoringInstance) {
+		vmi.Spec.Grafana.Enabled = false
+	})
+	assert.NoError(t, err)
+	actual := NewVMI()
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Name: actual.Name, Namespace: actual.Namespace}, actual)
+	assert.NoError(t, err)
+	assert.False(t, actual.Spec.Grafana.Enabled)
+	assert.True(t, actual.Spec.Elasticsearch.Enabled)
+
+	err = DisableVMIComponent(ctx, func(vmi *vmov1.VerrazzanoMonitoringInstance) {
+		vmi.Spec.Elasticsearch.Enabled = false
+	})
+	assert.NoError(t, err)
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Name: actual.Name, Namespace: actual.Namespace}, NewVMI())
+	assert.True(t, errors.IsNotFound(err))
+
+	// No VMI, nothing to do
+	assert.NoError(t, DisableVMIComponent(ctx, func(vmi *vmov1.VerrazzanoMonitoringInstance) {}))
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package extension
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+
+	helmcli "github.com/verrazzano/verrazzano/pkg/helm"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// chartsDirName is the name of the directory, in the temporary directory, that OCI charts are pulled into
+const chartsDirName = "verrazzano-extensions"
+
+// pullFuncSig is a function needed for unit test override
+type pullFuncSig func(log vzlog.VerrazzanoLogger, chartRef string, version string, destDir string, insecureSkipTLSVerify bool) (stdout []byte, stderr []byte, err error)
+
+// pullFunc is the function used to pull OCI charts
+var pullFunc pullFuncSig = helmcli.Pull
+
+// extensionComponent is a component that installs the Helm chart of a VerrazzanoExtension
+type extensionComponent struct {
+	helm.HelmComponent
+
+	// extension is the VerrazzanoExtension the component was created from
+	extension *vzapi.VerrazzanoExtension
+}
+
+var _ spi.Component = extensionComponent{}
+
+// NewComponent returns the component of a VerrazzanoExtension.  The name of the extension is the name of the
+// component and of the Helm release.
+func NewComponent(ext *vzapi.VerrazzanoExtension) spi.Component {
+	ext = ext.DeepCopy()
+	imagePullSecretKey := ext.Spec.ImagePullSecretKey
+	if len(imagePullSecretKey) == 0 {
+		imagePullSecretKey = secret.DefaultImagePullSecretKeyName
+	}
+	return extensionComponent{
+		HelmComponent: helm.HelmComponent{
+			ReleaseName:             ext.Name,
+			JSONName:                ext.Name,
+			ChartDir:                GetChartDir(ext),
+			ChartNamespace:          ext.Spec.Namespace,
+			IgnoreNamespaceOverride: true,
+			IgnoreImageOverrides:    true,
+			SupportsOperatorInstall: true,
+			ImagePullSecretKeyname:  imagePullSecretKey,
+			Dependencies:            ext.Spec.Dependencies,
+			GetHelmValueOverrides: func(_ spi.ComponentContext) []vzapi.HelmValueOverrides {
+				return []vzapi.HelmValueOverrides{ext.Spec.HelmValueOverrides}
+			},
+		},
+		extension: ext,
+	}
+}
+
+// GetChartDir returns the chart directory of the extension, which is the directory an OCI chart is pulled into
+func GetChartDir(ext *vzapi.VerrazzanoExtension) string {
+	if len(ext.Spec.Chart.OCIReference) == 0 {
+		return ext.Spec.Chart.Path
+	}
+	return filepath.Join(getPullDir(ext), path.Base(ext.Spec.Chart.OCIReference))
+}
+
+// getPullDir returns the directory the OCI chart of the extension is extracted into
+func getPullDir(ext *vzapi.VerrazzanoExtension) string {
+	return filepath.Join(os.TempDir(), chartsDirName, ext.Namespace, ext.Name, ext.Spec.Chart.Version)
+}
+
+// EnsureChart pulls the OCI chart of the extension, unless it has already been pulled
+func EnsureChart(log vzlog.VerrazzanoLogger, ext *vzapi.VerrazzanoExtension) error {
+	if len(ext.Spec.Chart.OCIReference) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(GetChartDir(ext)); err == nil {
+		return nil
+	}
+	pullDir := getPullDir(ext)
+	if err := os.MkdirAll(pullDir, 0700); err != nil {
+		return log.ErrorfNewErr("Failed to create the chart directory %s of extension %s: %v", pullDir, ext.Name, err)
+	}
+	if _, _, err := pullFunc(log, ext.Spec.Chart.OCIReference, ext.Spec.Chart.Version, pullDir, ext.Spec.Chart.InsecureSkipTLSVerify); err != nil {
+		os.RemoveAll(pullDir)
+		return log.ErrorfNewErr("Failed to pull the chart %s of extension %s: %v", ext.Spec.Chart.OCIReference, ext.Name, err)
+	}
+	return nil
+}
+
+// IsEnabled returns true if the extension is in the namespace of the Verrazzano resource, is enabled and is not
+// being deleted
+func (c extensionComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
+	if effectiveCR.Namespace != c.extension.Namespace || c.extension.DeletionTimestamp != nil {
+		return false
+	}
+	return c.extension.Spec.Enabled == nil || *c.extension.Spec.Enabled
+}
+
+// PreInstall creates the namespace of the extension and pulls the OCI chart if needed
+func (c extensionComponent) PreInstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debugf("Extension %s PreInstall dry run", c.Name())
+		return nil
+	}
+	if err := EnsureChart(ctx.Log(), c.extension); err != nil {
+		return err
+	}
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: c.ChartNamespace}}
+	if _, err := controllerutil.CreateOrUpdate(context.TODO(), ctx.Client(), &ns, func() error {
+		return nil
+	}); err != nil {
+		return ctx.Log().ErrorfNewErr("Failed to create or update the %s namespace of extension %s: %v", c.ChartNamespace, c.Name(), err)
+	}
+	return c.HelmComponent.PreInstall(ctx)
+}
+
+// PreUpgrade pulls the OCI chart if needed
+func (c extensionComponent) PreUpgrade(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		return nil
+	}
+	if err := EnsureChart(ctx.Log(), c.extension); err != nil {
+		return err
+	}
+	return c.HelmComponent.PreUpgrade(ctx)
+}
+
+// IsReady returns true if the Helm release is deployed and the workloads in the readiness checks are ready
+func (c extensionComponent) IsReady(ctx spi.ComponentContext) bool {
+	if !c.HelmComponent.IsReady(ctx) {
+		return false
+	}
+	if ctx.IsDryRun() {
+		return true
+	}
+	return c.areWorkloadsReady(ctx)
+}
+
+// areWorkloadsReady returns true if the deployments and statefulsets in the readiness checks are ready
+func (c extensionComponent) areWorkloadsReady(ctx spi.ComponentContext) bool {
+	prefix := "Extension " + c.Name()
+	checks := c.extension.Spec.ReadinessChecks
+	if len(checks.Deployments) > 0 &&
+		!status.DeploymentsAreReady(ctx.Log(), ctx.Client(), c.getNamespacedNames(checks.Deployments), 1, prefix) {
+		return false
+	}
+	if len(checks.StatefulSets) > 0 &&
+		!status.StatefulSetsAreReady(ctx.Log(), ctx.Client(), c.getNamespacedNames(checks.StatefulSets), 1, prefix) {
+		return false
+	}
+	return true
+}
+
+// getNamespacedNames returns the names of the workloads in the namespace of the extension
+func (c extensionComponent) getNamespacedNames(names []string) []types.NamespacedName {
+	nsns := make([]types.NamespacedName, 0, len(names))
+	for _, name := range names {
+		nsns = append(nsns, types.NamespacedName{Namespace: c.ChartNamespace, Name: name})
+	}
+	return nsns
+}
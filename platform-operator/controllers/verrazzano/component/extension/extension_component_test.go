@@ -0,0 +1,169 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package extension
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	helmcli "github.com/verrazzano/verrazzano/pkg/helm"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newExtension returns a VerrazzanoExtension for the tests
+func newExtension(chart vzapi.ExtensionChart) *vzapi.VerrazzanoExtension {
+	return &vzapi.VerrazzanoExtension{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "addon"},
+		Spec: vzapi.VerrazzanoExtensionSpec{
+			Chart:        chart,
+			Namespace:    "addon-system",
+			Dependencies: []string{"istio"},
+		},
+	}
+}
+
+// TestNewComponent tests NewComponent
+// GIVEN a VerrazzanoExtension
+// WHEN the component is created
+// THEN the Helm release, chart, namespace and dependencies come from the extension
+func TestNewComponent(t *testing.T) {
+	asserts := assert.New(t)
+	comp := NewComponent(newExtension(vzapi.ExtensionChart{Path: "/charts/addon"})).(extensionComponent)
+	asserts.Equal("addon", comp.Name())
+	asserts.Equal("addon", comp.GetJSONName())
+	asserts.Equal("/charts/addon", comp.ChartDir)
+	asserts.Equal("addon-system", comp.ChartNamespace)
+	asserts.Equal([]string{"istio"}, comp.GetDependencies())
+	asserts.Equal(secret.DefaultImagePullSecretKeyName, comp.ImagePullSecretKeyname)
+
+	ext := newExtension(vzapi.ExtensionChart{OCIReference: "oci://registry.local/charts/addon", Version: "1.2.0"})
+	ext.Spec.ImagePullSecretKey = "imagePullSecrets[0].name"
+	comp = NewComponent(ext).(extensionComponent)
+	asserts.Equal(filepath.Join(os.TempDir(), chartsDirName, "default", "addon", "1.2.0", "addon"), comp.ChartDir)
+	asserts.Equal("imagePullSecrets[0].name", comp.ImagePullSecretKeyname)
+}
+
+// TestIsEnabled tests the IsEnabled function
+// GIVEN a VerrazzanoExtension
+// WHEN IsEnabled is called
+// THEN true is returned only for an enabled extension, in the namespace of the Verrazzano resource, that isn't being deleted
+func TestIsEnabled(t *testing.T) {
+	asserts := assert.New(t)
+	vz := &vzapi.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vz"}}
+	ext := newExtension(vzapi.ExtensionChart{Path: "/charts/addon"})
+	asserts.True(NewComponent(ext).IsEnabled(vz))
+
+	disabled := false
+	ext.Spec.Enabled = &disabled
+	asserts.False(NewComponent(ext).IsEnabled(vz))
+
+	ext.Spec.Enabled = nil
+	now := metav1.Now()
+	ext.DeletionTimestamp = &now
+	asserts.False(NewComponent(ext).IsEnabled(vz))
+
+	ext.DeletionTimestamp = nil
+	asserts.False(NewComponent(ext).IsEnabled(&vzapi.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "vz"}}))
+}
+
+// TestEnsureChart tests the EnsureChart function
+// GIVEN a VerrazzanoExtension with an OCI chart
+// WHEN EnsureChart is called
+// THEN the chart is pulled once into the chart directory, and an error is returned if the pull fails
+func TestEnsureChart(t *testing.T) {
+	asserts := assert.New(t)
+	defer func() { pullFunc = helmcli.Pull }()
+
+	ext := newExtension(vzapi.ExtensionChart{OCIReference: "oci://registry.local/charts/addon", Version: "1.2.0", InsecureSkipTLSVerify: true})
+	ext.Name = "addon-ensure-chart"
+	defer os.RemoveAll(filepath.Join(os.TempDir(), chartsDirName, "default", ext.Name))
+
+	pullFunc = func(_ vzlog.VerrazzanoLogger, _ string, _ string, _ string, _ bool) ([]byte, []byte, error) {
+		return nil, []byte("not found"), errors.New("not found")
+	}
+	asserts.Error(EnsureChart(vzlog.DefaultLogger(), ext))
+
+	pulls := 0
+	pullFunc = func(_ vzlog.VerrazzanoLogger, chartRef string, version string, destDir string, insecureSkipTLSVerify bool) ([]byte, []byte, error) {
+		pulls++
+		asserts.Equal("oci://registry.local/charts/addon", chartRef)
+		asserts.Equal("1.2.0", version)
+		asserts.True(insecureSkipTLSVerify)
+		return nil, nil, os.Mkdir(filepath.Join(destDir, "addon"), 0700)
+	}
+	asserts.NoError(EnsureChart(vzlog.DefaultLogger(), ext))
+	asserts.NoError(EnsureChart(vzlog.DefaultLogger(), ext))
+	asserts.Equal(1, pulls)
+	asserts.DirExists(GetChartDir(ext))
+
+	// Nothing is pulled for a chart in the operator image
+	asserts.NoError(EnsureChart(vzlog.DefaultLogger(), newExtension(vzapi.ExtensionChart{Path: "/charts/addon"})))
+	asserts.Equal(1, pulls)
+}
+
+// TestPreInstall tests the PreInstall function
+// GIVEN a VerrazzanoExtension
+// WHEN PreInstall is called
+// THEN the namespace of the extension is created
+func TestPreInstall(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+	vz := &vzapi.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vz"}}
+	comp := NewComponent(newExtension(vzapi.ExtensionChart{Path: "/charts/addon"}))
+	assert.NoError(t, comp.PreInstall(spi.NewFakeContext(c, vz, false)))
+
+	ns := &corev1.Namespace{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "addon-system"}, ns))
+}
+
+// TestAreWorkloadsReady tests the areWorkloadsReady function
+// GIVEN a VerrazzanoExtension with readiness checks
+// WHEN areWorkloadsReady is called
+// THEN true is returned only if the workloads in the readiness checks are ready
+func TestAreWorkloadsReady(t *testing.T) {
+	asserts := assert.New(t)
+	vz := &vzapi.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vz"}}
+	ext := newExtension(vzapi.ExtensionChart{Path: "/charts/addon"})
+
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+	asserts.True(NewComponent(ext).(extensionComponent).areWorkloadsReady(spi.NewFakeContext(c, vz, false)))
+
+	ext.Spec.ReadinessChecks.StatefulSets = []string{"addon-db"}
+	asserts.False(NewComponent(ext).(extensionComponent).areWorkloadsReady(spi.NewFakeContext(c, vz, false)))
+
+	c = fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "addon-system", Name: "addon-db"},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "addon-db"}},
+			},
+			Status: appsv1.StatefulSetStatus{ReadyReplicas: 1, UpdatedReplicas: 1, UpdateRevision: "addon-db-1"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "addon-system",
+				Name:      "addon-db-0",
+				Labels:    map[string]string{"app": "addon-db", "controller-revision-hash": "addon-db-1"},
+			},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Ready: true}}},
+		},
+		&appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "addon-system", Name: "addon-db-1"},
+			Revision:   1,
+		},
+	).Build()
+	asserts.True(NewComponent(ext).(extensionComponent).areWorkloadsReady(spi.NewFakeContext(c, vz, false)))
+}
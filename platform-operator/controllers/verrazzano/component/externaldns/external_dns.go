@@ -14,11 +14,13 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/status"
 	"hash/fnv"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"strconv"
+	"strings"
 )
 
 // ComponentName is the name of the component
@@ -84,6 +86,32 @@ func preInstall(compContext spi.ComponentContext) error {
 	return nil
 }
 
+// checkOCIDNSSecret returns the preflight check of the OCI DNS secret, which must exist in the verrazzano-install
+// namespace and be created from one file
+func checkOCIDNSSecret(compContext spi.ComponentContext) (vzapi.PreflightCheck, error) {
+	secretName := compContext.EffectiveCR().Spec.Components.DNS.OCI.OCIConfigSecret
+	check := vzapi.PreflightCheck{Name: "OCIDNSSecret", Component: ComponentName}
+	dnsSecret := v1.Secret{}
+	if err := compContext.Client().Get(context.TODO(), client.ObjectKey{Name: secretName, Namespace: constants.VerrazzanoInstallNamespace}, &dnsSecret); err != nil {
+		if !errors.IsNotFound(err) {
+			return check, err
+		}
+		check.Result = vzapi.PreflightCheckFailed
+		check.Message = fmt.Sprintf("The OCI DNS secret %s does not exist in the %s namespace, create it from the OCI configuration file of the OCI DNS zone",
+			secretName, constants.VerrazzanoInstallNamespace)
+		return check, nil
+	}
+	if len(dnsSecret.Data) != 1 {
+		check.Result = vzapi.PreflightCheckFailed
+		check.Message = fmt.Sprintf("The OCI DNS secret %s in the %s namespace has %d keys, it should be created from one file",
+			secretName, constants.VerrazzanoInstallNamespace, len(dnsSecret.Data))
+		return check, nil
+	}
+	check.Result = vzapi.PreflightCheckPassed
+	check.Message = fmt.Sprintf("The OCI DNS secret %s exists in the %s namespace", secretName, constants.VerrazzanoInstallNamespace)
+	return check, nil
+}
+
 func isExternalDNSReady(compContext spi.ComponentContext) bool {
 	deployments := []types.NamespacedName{
 		{
@@ -170,3 +198,13 @@ func buildOwnerString(uid types.UID) (string, error) {
 	sum := hash.Sum32()
 	return fmt.Sprintf("v8o-%s", strconv.FormatUint(uint64(sum), 16)), nil
 }
+
+// isExternalDNSIngress returns true if the ingress has an ExternalDNS annotation
+func isExternalDNSIngress(obj client.Object) bool {
+	for key := range obj.GetAnnotations() {
+		if strings.Contains(key, ComponentName) {
+			return true
+		}
+	}
+	return false
+}
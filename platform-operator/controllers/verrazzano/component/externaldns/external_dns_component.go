@@ -6,12 +6,17 @@ package externaldns
 import (
 	"fmt"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"path/filepath"
 
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	netv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ComponentName is the name of the component
@@ -38,12 +43,40 @@ func NewComponent() spi.Component {
 			ImagePullSecretKeyname:  imagePullSecretHelmKey,
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), "external-dns-values.yaml"),
 			AppendOverridesFunc:     AppendOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
 			MinVerrazzanoVersion:    constants.VerrazzanoVersion1_0_0,
 			Dependencies:            []string{},
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:          "replicas",
+					Affinity:          "affinity",
+					Tolerations:       "tolerations",
+					NodeSelector:      "nodeSelector",
+					PriorityClassName: "priorityClassName",
+					Resources:         "resources",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the ExternalDNS Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.DNS; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the ExternalDNS component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.DNS; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 func (e externalDNSComponent) PreInstall(compContext spi.ComponentContext) error {
 	return preInstall(compContext)
 }
@@ -63,6 +96,36 @@ func (e externalDNSComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	return false
 }
 
+// RunPreflightChecks checks that the OCI DNS secret exists
+func (e externalDNSComponent) RunPreflightChecks(compContext spi.ComponentContext) ([]vzapi.PreflightCheck, error) {
+	check, err := checkOCIDNSSecret(compContext)
+	if err != nil {
+		return nil, err
+	}
+	return []vzapi.PreflightCheck{check}, nil
+}
+
+// PreUninstall deletes the ingresses managed by ExternalDNS, so that ExternalDNS removes their DNS records
+func (e externalDNSComponent) PreUninstall(compContext spi.ComponentContext) error {
+	if compContext.IsDryRun() {
+		compContext.Log().Debug("ExternalDNS PreUninstall dry run")
+		return nil
+	}
+	return common.DeleteResources(compContext, &netv1.IngressList{}, isExternalDNSIngress)
+}
+
+// PostUninstall deletes the cluster role and binding of ExternalDNS
+func (e externalDNSComponent) PostUninstall(compContext spi.ComponentContext) error {
+	if compContext.IsDryRun() {
+		compContext.Log().Debug("ExternalDNS PostUninstall dry run")
+		return nil
+	}
+	if err := common.DeleteObject(compContext, &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: ComponentName}}); err != nil {
+		return err
+	}
+	return common.DeleteObject(compContext, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: ComponentName}})
+}
+
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (e externalDNSComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
 	// Do not allow any changes except to enable the component post-install
@@ -71,3 +134,17 @@ func (e externalDNSComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.V
 	}
 	return nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of external DNS, which updates the records of the DNS provider
+// outside the cluster
+func (e externalDNSComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-external-dns", map[string]string{"app.kubernetes.io/instance": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.PrometheusIngress(7979),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(nil, 443),
+			}),
+	}, nil
+}
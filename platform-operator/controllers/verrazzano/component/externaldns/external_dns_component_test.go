@@ -4,9 +4,19 @@
 package externaldns
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	netv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_externalDNSComponent_ValidateUpdate(t *testing.T) {
@@ -162,3 +172,37 @@ func Test_externalDNSComponent_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+// TestPreUninstall tests the PreUninstall function
+// GIVEN a call to PreUninstall
+// WHEN ingresses with and without an ExternalDNS annotation exist
+// THEN the ingresses with an ExternalDNS annotation are deleted
+func TestPreUninstall(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dns",
+			Annotations: map[string]string{"external-dns.alpha.kubernetes.io/target": "verrazzano-ingress.example.com"}}},
+		&netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other"}},
+	).Build()
+	assert.NoError(t, NewComponent().PreUninstall(spi.NewFakeContext(client, &vzapi.Verrazzano{}, false)))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Namespace: "ns", Name: "dns"}, &netv1.Ingress{})
+	assert.True(t, errors.IsNotFound(err))
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Namespace: "ns", Name: "other"}, &netv1.Ingress{}))
+}
+
+// TestPostUninstall tests the PostUninstall function
+// GIVEN a call to PostUninstall
+// WHEN the ExternalDNS cluster role and binding exist
+// THEN they are deleted
+func TestPostUninstall(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: ComponentName}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: ComponentName}},
+	).Build()
+	assert.NoError(t, NewComponent().PostUninstall(spi.NewFakeContext(client, &vzapi.Verrazzano{}, false)))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Name: ComponentName}, &rbacv1.ClusterRole{})
+	assert.True(t, errors.IsNotFound(err))
+	err = client.Get(context.TODO(), types.NamespacedName{Name: ComponentName}, &rbacv1.ClusterRoleBinding{})
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -228,6 +228,37 @@ func TestExternalDNSPreInstall3InvalidScope(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// TestRunPreflightChecks tests the RunPreflightChecks fn
+// GIVEN a call to RunPreflightChecks
+// WHEN the OCI DNS secret is missing, has more than one key, or exists
+// THEN the OCI DNS secret check fails for a missing secret or a secret with more than one key
+func TestRunPreflightChecks(t *testing.T) {
+	localvz := vz.DeepCopy()
+	localvz.Spec.Components.DNS.OCI = oci
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "oci", Namespace: constants.VerrazzanoInstallNamespace},
+		Data:       map[string][]byte{"oci.yaml": []byte("fake data"), "key": []byte("fake key")},
+	}
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+	checks, err := fakeComponent.RunPreflightChecks(spi.NewFakeContext(client, localvz, false))
+	assert.NoError(t, err)
+	assert.Len(t, checks, 1)
+	assert.Equal(t, vzapi.PreflightCheckFailed, checks[0].Result)
+	assert.Contains(t, checks[0].Message, "does not exist")
+
+	client = fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(secret.DeepCopy()).Build()
+	checks, err = fakeComponent.RunPreflightChecks(spi.NewFakeContext(client, localvz, false))
+	assert.NoError(t, err)
+	assert.Equal(t, vzapi.PreflightCheckFailed, checks[0].Result)
+	assert.Contains(t, checks[0].Message, "one file")
+
+	delete(secret.Data, "key")
+	client = fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(secret).Build()
+	checks, err = fakeComponent.RunPreflightChecks(spi.NewFakeContext(client, localvz, false))
+	assert.NoError(t, err)
+	assert.Equal(t, vzapi.PreflightCheckPassed, checks[0].Result)
+}
+
 // TestOwnerIDTextPrefix_HelmValueExists tests the getOrBuildIDs and getOrBuildTXTRecordPrefix functions
 // GIVEN calls to getOrBuildIDs and getOrBuildTXTRecordPrefix
 //  WHEN a valid helm release and namespace are deployed and the txtOwnerId and txtPrefix values exist in the release values
@@ -0,0 +1,23 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package grafana
+
+import (
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// backupEndpoint is the URL of the Grafana service, whose API exports and imports the dashboards
+const backupEndpoint = "http://vmi-system-grafana.verrazzano-system.svc.cluster.local:3000"
+
+// GetBackupSpec returns the backup of the Grafana dashboards, which are exported with the credentials of the VMI
+func (g grafanaComponent) GetBackupSpec() spi.BackupSpec {
+	return spi.BackupSpec{
+		Order:            4,
+		Method:           spi.BackupMethodGrafana,
+		Endpoint:         backupEndpoint,
+		CredentialSecret: types.NamespacedName{Namespace: ComponentNamespace, Name: constants.VMISecret},
+	}
+}
@@ -4,14 +4,14 @@
 package grafana
 
 import (
-	"fmt"
-
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/vmo"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -106,8 +106,17 @@ func (g grafanaComponent) IsReady(ctx spi.ComponentContext) bool {
 }
 
 // ValidateInstall checks if the specified Verrazzano CR is valid for this component to be installed
-func (g grafanaComponent) ValidateInstall(_ *vzapi.Verrazzano) error {
-	return nil
+func (g grafanaComponent) ValidateInstall(vz *vzapi.Verrazzano) error {
+	return common.ValidateVMIVolumeSource(vz, getVolumeSource(vz), ComponentJSONName)
+}
+
+// ValidateKubernetesSettings checks the kubernetes section of Grafana, the VMI only supports the resources
+func (g grafanaComponent) ValidateKubernetesSettings(vz *vzapi.Verrazzano) error {
+	if vz.Spec.Components.Grafana == nil {
+		return nil
+	}
+	return vzconfig.ValidateKubernetesSettings(ComponentJSONName, vz.Spec.Components.Grafana.Kubernetes,
+		vzconfig.KubernetesResources)
 }
 
 // PreInstall ensures that preconditions are met before installing the Grafana component
@@ -160,16 +169,55 @@ func (g grafanaComponent) PostUpgrade(ctx spi.ComponentContext) error {
 	return common.CheckIngressesAndCerts(ctx, g)
 }
 
+// PreUninstall performs Grafana pre-uninstall processing
+func (g grafanaComponent) PreUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+// Uninstall performs Grafana uninstall processing by disabling Grafana in the VMI
+func (g grafanaComponent) Uninstall(ctx spi.ComponentContext) error {
+	return common.DisableVMIComponent(ctx, disableFunc)
+}
+
+// PostUninstall performs Grafana post-uninstall processing
+func (g grafanaComponent) PostUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+// IsUninstalled returns true if the Grafana deployment has been removed
+func (g grafanaComponent) IsUninstalled(ctx spi.ComponentContext) (bool, error) {
+	return !isGrafanaInstalled(ctx), nil
+}
+
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (g grafanaComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// do not allow disabling active components
-	if vzconfig.IsGrafanaEnabled(old) && !vzconfig.IsGrafanaEnabled(new) {
-		return fmt.Errorf("Disabling component Grafana not allowed")
+	// Reject changes of the persistence or StorageClass of the volume, it can only grow when the StorageClass allows
+	// volume expansion
+	if err := common.CompareVMIVolumeSources(old, new, getVolumeSource(old), getVolumeSource(new), ComponentJSONName); err != nil {
+		return err
 	}
-	return nil
+	return common.ValidateVMIVolumeSource(new, getVolumeSource(new), ComponentJSONName)
 }
 
 // Reconcile reconciles the Grafana component
 func (g grafanaComponent) Reconcile(ctx spi.ComponentContext) error {
 	return nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of Grafana, which queries Prometheus and is reached by its backup and
+// restore jobs
+func (g grafanaComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-grafana", map[string]string{"app": "system-grafana"},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.AuthProxyPeers(), 3000),
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", map[string]string{"k8s-app": "verrazzano-monitoring-operator"})}, 3000),
+				netpolicy.IngressFrom(common.BackupJobPeers(ComponentName), 3000),
+				netpolicy.PrometheusIngress(common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(netpolicy.PrometheusPeers(), common.PrometheusPort),
+				netpolicy.IstiodEgress(),
+			}),
+	}, nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -297,7 +298,7 @@ func testInstallOrUpgrade(t *testing.T, installOrUpgradeFunc func(spi.ComponentC
 func TestValidateUpdate(t *testing.T) {
 	// GIVEN an old VZ with Grafana enabled and a new VZ with Grafana disabled
 	// WHEN we call the ValidateUpdate function
-	// THEN the function returns an error
+	// THEN the function does not return an error
 	oldVz := &vzapi.Verrazzano{
 		Spec: vzapi.VerrazzanoSpec{
 			Components: vzapi.ComponentSpec{
@@ -318,7 +319,7 @@ func TestValidateUpdate(t *testing.T) {
 		},
 	}
 
-	assert.Error(t, NewComponent().ValidateUpdate(oldVz, newVz))
+	assert.NoError(t, NewComponent().ValidateUpdate(oldVz, newVz))
 
 	// GIVEN an old VZ with Grafana enabled and a new VZ with Grafana enabled
 	// WHEN we call the ValidateUpdate function
@@ -326,3 +327,36 @@ func TestValidateUpdate(t *testing.T) {
 	newVz.Spec.Components.Grafana.Enabled = &trueValue
 	assert.NoError(t, NewComponent().ValidateUpdate(oldVz, newVz))
 }
+
+// TestValidateVolumeSource tests the ValidateInstall and ValidateUpdate functions for the volume source of Grafana
+func TestValidateVolumeSource(t *testing.T) {
+	// GIVEN a VZ where Grafana references a volume claim template that does not exist
+	// WHEN we call the ValidateInstall function
+	// THEN the function returns an error
+	vz := &vzapi.Verrazzano{
+		Spec: vzapi.VerrazzanoSpec{
+			Components: vzapi.ComponentSpec{
+				Grafana: &vzapi.GrafanaComponent{
+					Enabled: &trueValue,
+					VolumeSource: &v1.VolumeSource{
+						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "grafana"},
+					},
+				},
+			},
+		},
+	}
+	assert.Error(t, NewComponent().ValidateInstall(vz))
+
+	// GIVEN a VZ where Grafana references a volume claim template that exists
+	// WHEN we call the ValidateInstall function
+	// THEN the function does not return an error
+	vz.Spec.VolumeClaimSpecTemplates = []vzapi.VolumeClaimSpecTemplate{{ObjectMeta: metav1.ObjectMeta{Name: "grafana"}}}
+	assert.NoError(t, NewComponent().ValidateInstall(vz))
+
+	// GIVEN an old VZ with a persistent Grafana volume and a new VZ where it is an emptyDir
+	// WHEN we call the ValidateUpdate function
+	// THEN the function returns an error
+	newVz := vz.DeepCopy()
+	newVz.Spec.Components.Grafana.VolumeSource = &v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}
+	assert.Error(t, NewComponent().ValidateUpdate(vz, newVz))
+}
@@ -8,14 +8,33 @@ import (
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // updateFunc mutates the VMI struct and ensures the Grafana component is configured properly
 func updateFunc(ctx spi.ComponentContext, storage *common.ResourceRequestValues, vmi *vmov1.VerrazzanoMonitoringInstance, existingVMI *vmov1.VerrazzanoMonitoringInstance) error {
-	vmi.Spec.Grafana = newGrafana(ctx.EffectiveCR(), storage, existingVMI)
+	cr := ctx.EffectiveCR()
+	storage, err := common.GetComponentStorageOverride(cr, getVolumeSource(cr), storage)
+	if err != nil {
+		return err
+	}
+	vmi.Spec.Grafana = newGrafana(cr, storage, existingVMI)
 	return nil
 }
 
+// getVolumeSource returns the volume source of Grafana
+func getVolumeSource(vz *vzapi.Verrazzano) *corev1.VolumeSource {
+	if vz.Spec.Components.Grafana == nil {
+		return nil
+	}
+	return vz.Spec.Components.Grafana.VolumeSource
+}
+
+// disableFunc mutates the VMI struct to disable the Grafana component
+func disableFunc(vmi *vmov1.VerrazzanoMonitoringInstance) {
+	vmi.Spec.Grafana.Enabled = false
+}
+
 // newGrafana creates a Grafana struct and populates it using existing config or default values
 func newGrafana(cr *vzapi.Verrazzano, storage *common.ResourceRequestValues, existingVMI *vmov1.VerrazzanoMonitoringInstance) vmov1.Grafana {
 	grafanaSpec := cr.Spec.Components.Grafana
@@ -31,6 +50,7 @@ func newGrafana(cr *vzapi.Verrazzano, storage *common.ResourceRequestValues, exi
 		},
 		Storage: vmov1.Storage{},
 	}
+	common.SetResources(grafanaSpec.Kubernetes, &grafana.Resources)
 	common.SetStorageSize(storage, &grafana.Storage)
 	if existingVMI != nil {
 		// preserve PVC names since these are set by the VMO
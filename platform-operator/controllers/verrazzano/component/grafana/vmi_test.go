@@ -11,6 +11,9 @@ import (
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var enabled = true
@@ -70,3 +73,33 @@ func TestNewGrafanaWithExistingVMI(t *testing.T) {
 	assert.Equal(t, "50Gi", vmi.Spec.Grafana.Storage.Size)
 	assert.Equal(t, []string{"my-pvc"}, vmi.Spec.Grafana.Storage.PvcNames)
 }
+
+// TestNewGrafanaWithVolumeSource tests that the volume source of Grafana takes precedence over the DefaultVolumeSource
+// GIVEN a Verrazzano CR where Grafana references a volume claim template
+// WHEN I create a new Grafana resource
+// THEN the storage size is the one of the template
+func TestNewGrafanaWithVolumeSource(t *testing.T) {
+	cr := grafanaEnabledCR.DeepCopy()
+	cr.Spec.VolumeClaimSpecTemplates = []vzapi.VolumeClaimSpecTemplate{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "grafana"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			},
+		},
+	}
+	cr.Spec.Components.Grafana.VolumeSource = &corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "grafana"},
+	}
+
+	vmi := vmov1.VerrazzanoMonitoringInstance{}
+	ctx := spi.NewFakeContext(nil, cr, false)
+	err := updateFunc(ctx, &common.ResourceRequestValues{Storage: "50Gi"}, &vmi, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "10Gi", vmi.Spec.Grafana.Storage.Size)
+
+	cr.Spec.Components.Grafana.VolumeSource.PersistentVolumeClaim.ClaimName = "missing"
+	assert.Error(t, updateFunc(ctx, nil, &vmi, nil))
+}
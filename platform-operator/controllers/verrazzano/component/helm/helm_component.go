@@ -7,6 +7,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/verrazzano/verrazzano/pkg/bom"
 	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
@@ -64,7 +67,8 @@ type HelmComponent struct {
 	// AppendOverridesFunc is an optional function get additional override values
 	AppendOverridesFunc appendOverridesSig
 
-	// GetHelmValueOverrides is an optional function that returns the helm override values
+	// GetHelmValueOverrides is an optional function that returns the Helm value overrides of the component from the
+	// Verrazzano resource
 	GetHelmValueOverrides getHelmValueOverridesSig
 
 	// ResolveNamespaceFunc is an optional function to process the namespace name
@@ -94,6 +98,13 @@ type HelmComponent struct {
 
 	// Certificates associated with the component
 	Certificates []types.NamespacedName
+
+	// GetKubernetesSpec is an optional function that returns the kubernetes section of the component from the
+	// Verrazzano resource
+	GetKubernetesSpec getKubernetesSpecSig
+
+	// KubernetesValueKeys are the Helm value keys of the workloads of the chart for the kubernetes section
+	KubernetesValueKeys []KubernetesValueKeys
 }
 
 // Verify that HelmComponent implements Component
@@ -112,7 +123,7 @@ type preUpgradeFuncSig func(log vzlog.VerrazzanoLogger, client clipkg.Client, re
 type appendOverridesSig func(context spi.ComponentContext, releaseName string, namespace string, chartDir string, kvs []bom.KeyValue) ([]bom.KeyValue, error)
 
 // getHelmValueOverridesSig is the signature for providing the list of Helm value overrides.
-type getHelmValueOverridesSig func(context spi.ComponentContext) []vzapi.Overrides
+type getHelmValueOverridesSig func(context spi.ComponentContext) []vzapi.HelmValueOverrides
 
 // resolveNamespaceSig is an optional function called for special namespace processing
 type resolveNamespaceSig func(ns string) string
@@ -131,6 +142,34 @@ func SetDefaultUpgradeFunc() {
 	upgradeFunc = helm.Upgrade
 }
 
+// uninstallFuncSig is a function needed for unit test override
+type uninstallFuncSig func(log vzlog.VerrazzanoLogger, releaseName string, namespace string, dryRun bool) (stdout []byte, stderr []byte, err error)
+
+// uninstallFunc is the default uninstall function
+var uninstallFunc uninstallFuncSig = helm.Uninstall
+
+func SetUninstallFunc(f uninstallFuncSig) {
+	uninstallFunc = f
+}
+
+func SetDefaultUninstallFunc() {
+	uninstallFunc = helm.Uninstall
+}
+
+// rollbackFuncSig is a function needed for unit test override
+type rollbackFuncSig func(log vzlog.VerrazzanoLogger, releaseName string, namespace string, revision int, wait bool, dryRun bool) (stdout []byte, stderr []byte, err error)
+
+// rollbackFunc is the default rollback function
+var rollbackFunc rollbackFuncSig = helm.Rollback
+
+func SetRollbackFunc(f rollbackFuncSig) {
+	rollbackFunc = f
+}
+
+func SetDefaultRollbackFunc() {
+	rollbackFunc = helm.Rollback
+}
+
 // UpgradePrehooksEnabled is needed so that higher level units tests can disable as needed
 var UpgradePrehooksEnabled = true
 
@@ -159,6 +198,11 @@ func (h HelmComponent) GetCertificateNames(_ spi.ComponentContext) []types.Names
 	return h.Certificates
 }
 
+// GetNamespace returns the namespace the Helm release of this component is installed in
+func (h HelmComponent) GetNamespace(context spi.ComponentContext) string {
+	return h.resolveNamespace(context.EffectiveCR().Namespace)
+}
+
 // GetMinVerrazzanoVersion returns the minimum Verrazzano version required by this component
 func (h HelmComponent) GetMinVerrazzanoVersion() string {
 	if len(h.MinVerrazzanoVersion) == 0 {
@@ -205,6 +249,31 @@ func (h HelmComponent) IsReady(context spi.ComponentContext) bool {
 	return true
 }
 
+// GetUnreadyWorkloads returns a description of each deployment, statefulset and daemonset of the Helm release that
+// is not ready
+func (h HelmComponent) GetUnreadyWorkloads(context spi.ComponentContext) ([]string, error) {
+	if context.IsDryRun() {
+		context.Log().Debugf("GetUnreadyWorkloads() dry run for %s", h.ReleaseName)
+		return nil, nil
+	}
+	return status.GetUnreadyReleaseWorkloads(context.Client(), h.ReleaseName, h.resolveNamespace(context.EffectiveCR().Namespace))
+}
+
+// GetMonitoredOverrides returns the Helm value overrides of the component that have changes to their ConfigMaps and
+// Secrets monitored
+func (h HelmComponent) GetMonitoredOverrides(context spi.ComponentContext) []vzapi.Overrides {
+	if h.GetHelmValueOverrides == nil {
+		return nil
+	}
+	var monitored []vzapi.Overrides
+	for _, helmValueOverrides := range h.GetHelmValueOverrides(context) {
+		if helmValueOverrides.MonitorChanges != nil && *helmValueOverrides.MonitorChanges {
+			monitored = append(monitored, helmValueOverrides.ValueOverrides...)
+		}
+	}
+	return monitored
+}
+
 // IsEnabled Indicates whether a component is enabled for installation
 func (h HelmComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	return true
@@ -235,7 +304,7 @@ func (h HelmComponent) Install(context spi.ComponentContext) error {
 
 	// vz-specific chart overrides file
 	overrides, err := h.buildCustomHelmOverrides(context, resolvedNamespace, kvs...)
-	defer vzos.RemoveTempFiles(context.Log().GetZapLogger(), `\w*`)
+	defer func() { removeOverrideFiles(context.Log(), overrides) }()
 	if err != nil {
 		return err
 	}
@@ -245,6 +314,93 @@ func (h HelmComponent) Install(context spi.ComponentContext) error {
 	return err
 }
 
+// GetOverrideValues returns the Helm values that the component would be installed with, merged the same way Helm
+// merges them.  The values of the live release are not included.  Nothing is changed in the cluster.
+func (h HelmComponent) GetOverrideValues(context spi.ComponentContext) (map[string]interface{}, error) {
+	resolvedNamespace := h.resolveNamespace(context.EffectiveCR().Namespace)
+
+	// The global image pull secret is only copied to the component namespace during install, just check that it exists
+	var kvs []bom.KeyValue
+	secretExists, err := secret.GlobalImagePullSecretExists(context.Client())
+	if err != nil {
+		return nil, err
+	}
+	if secretExists {
+		kvs = append(kvs, bom.KeyValue{Key: h.ImagePullSecretKeyname, Value: constants.GlobalImagePullSecName})
+	}
+
+	overrides, err := h.buildCustomHelmOverrides(context, resolvedNamespace, kvs...)
+	defer func() { removeOverrideFiles(context.Log(), overrides) }()
+	if err != nil {
+		return nil, err
+	}
+	return helm.MergeOverrides(overrides)
+}
+
+// GetDeployedValues returns the user supplied Helm values of the live release.  False is returned if the release
+// is not installed.
+func (h HelmComponent) GetDeployedValues(context spi.ComponentContext) (map[string]interface{}, bool, error) {
+	resolvedNamespace := h.resolveNamespace(context.EffectiveCR().Namespace)
+	found, err := helm.IsReleaseInstalled(h.ReleaseName, resolvedNamespace)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	values, err := helm.GetValuesMap(context.Log(), h.ReleaseName, resolvedNamespace)
+	if err != nil {
+		return nil, true, err
+	}
+	return values, true, nil
+}
+
+// GetDrift returns the differences between the live Helm release and the release Verrazzano would install.  The chart
+// of the release must have the chart version in the BOM, and each value set by Verrazzano must have the same value in
+// the release.  Values that are only in the release are ignored since an upgrade retains the values of the
+// previous release.
+func (h HelmComponent) GetDrift(context spi.ComponentContext) ([]string, error) {
+	if context.IsDryRun() {
+		context.Log().Debugf("GetDrift() dry run for %s", h.ReleaseName)
+		return nil, nil
+	}
+	deployedValues, found, err := h.GetDeployedValues(context)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var drift []string
+	chartVersion, err := getChartVersion(h.ReleaseName)
+	if err != nil {
+		return nil, err
+	}
+	if len(chartVersion) > 0 {
+		releaseChart, err := helmcli.GetReleaseChart(h.ReleaseName, h.resolveNamespace(context.EffectiveCR().Namespace))
+		if err != nil {
+			return nil, err
+		}
+		// The release chart is the chart name followed by the chart version
+		if !strings.HasSuffix(releaseChart, "-"+chartVersion) {
+			drift = append(drift, fmt.Sprintf("chart %s is installed instead of version %s", releaseChart, chartVersion))
+		}
+	}
+
+	overrideValues, err := h.GetOverrideValues(context)
+	if err != nil {
+		return nil, err
+	}
+	deployed := helm.FlattenValues(deployedValues)
+	var changedPaths []string
+	for path, value := range helm.FlattenValues(overrideValues) {
+		if deployed[path] != value {
+			changedPaths = append(changedPaths, path)
+		}
+	}
+	if len(changedPaths) > 0 {
+		// Only the paths are reported, the values may be sensitive
+		sort.Strings(changedPaths)
+		drift = append(drift, fmt.Sprintf("values %s have been changed", strings.Join(changedPaths, ", ")))
+	}
+	return drift, nil
+}
+
 func (h HelmComponent) PreInstall(context spi.ComponentContext) error {
 	if h.PreInstallFunc != nil {
 		err := h.PreInstallFunc(context, h.ReleaseName, h.resolveNamespace(context.EffectiveCR().Namespace), h.ChartDir)
@@ -322,7 +478,7 @@ func (h HelmComponent) Upgrade(context spi.ComponentContext) error {
 	}
 
 	overrides, err := h.buildCustomHelmOverrides(context, resolvedNamespace, kvs...)
-	defer vzos.RemoveTempFiles(context.Log().GetZapLogger(), `\w*`)
+	defer func() { removeOverrideFiles(context.Log(), overrides) }()
 	if err != nil {
 		return err
 	}
@@ -344,6 +500,31 @@ func (h HelmComponent) Upgrade(context spi.ComponentContext) error {
 	return err
 }
 
+// GetReleaseRevision returns the revision of the installed Helm release, 0 if the release is not installed
+func (h HelmComponent) GetReleaseRevision(context spi.ComponentContext) (int, error) {
+	if context.IsDryRun() {
+		context.Log().Debugf("GetReleaseRevision() dry run for %s", h.ReleaseName)
+		return 0, nil
+	}
+	return helm.GetReleaseRevision(h.ReleaseName, h.resolveNamespace(context.EffectiveCR().Namespace))
+}
+
+// GetReleaseVersion returns the chart version of the installed Helm release, an empty string if the release is not
+// installed
+func (h HelmComponent) GetReleaseVersion(context spi.ComponentContext) (string, error) {
+	if context.IsDryRun() {
+		context.Log().Debugf("GetReleaseVersion() dry run for %s", h.ReleaseName)
+		return "", nil
+	}
+	return helm.GetReleaseChartVersion(h.ReleaseName, h.resolveNamespace(context.EffectiveCR().Namespace))
+}
+
+// Rollback rolls the Helm release back to the revision
+func (h HelmComponent) Rollback(context spi.ComponentContext, revision int) error {
+	_, _, err := rollbackFunc(context.Log(), h.ReleaseName, h.resolveNamespace(context.EffectiveCR().Namespace), revision, true, context.IsDryRun())
+	return err
+}
+
 func (h HelmComponent) PreUpgrade(_ spi.ComponentContext) error {
 	return nil
 }
@@ -356,6 +537,45 @@ func (h HelmComponent) Reconcile(_ spi.ComponentContext) error {
 	return nil
 }
 
+func (h HelmComponent) PreUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+// Uninstall removes the component by uninstalling the Helm release
+func (h HelmComponent) Uninstall(context spi.ComponentContext) error {
+	resolvedNamespace := h.resolveNamespace(context.EffectiveCR().Namespace)
+
+	// Check if the component is installed before trying to uninstall
+	found, err := helm.IsReleaseInstalled(h.ReleaseName, resolvedNamespace)
+	if err != nil {
+		return err
+	}
+	if !found {
+		context.Log().Infof("Skipping uninstall of component %s since it is not installed", h.ReleaseName)
+		return nil
+	}
+
+	_, _, err = uninstallFunc(context.Log(), h.ReleaseName, resolvedNamespace, context.IsDryRun())
+	return err
+}
+
+func (h HelmComponent) PostUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+// IsUninstalled Indicates whether or not the Helm release for the component has been removed
+func (h HelmComponent) IsUninstalled(context spi.ComponentContext) (bool, error) {
+	if context.IsDryRun() {
+		context.Log().Debugf("IsUninstalled() dry run for %s", h.ReleaseName)
+		return true, nil
+	}
+	installed, err := helm.IsReleaseInstalled(h.ReleaseName, h.resolveNamespace(context.EffectiveCR().Namespace))
+	if err != nil {
+		return false, err
+	}
+	return !installed, nil
+}
+
 // buildCustomHelmOverrides Builds the helm overrides for a release, including image and file, and custom overrides
 // - returns an error and a HelmOverride struct with the field populated
 func (h HelmComponent) buildCustomHelmOverrides(context spi.ComponentContext, namespace string, additionalValues ...bom.KeyValue) ([]helm.HelmOverrides, error) {
@@ -369,14 +589,25 @@ func (h HelmComponent) buildCustomHelmOverrides(context spi.ComponentContext, na
 	// Sort the kvs list by priority (0th term has the highest priority)
 	// Getting user defined Helm overrides as the highest priority
 	if h.GetHelmValueOverrides != nil {
-		kvs, err = h.retrieveHelmOverrideResources(context, h.GetHelmValueOverrides(context))
+		var valueOverrides []vzapi.Overrides
+		for _, helmValueOverrides := range h.GetHelmValueOverrides(context) {
+			valueOverrides = append(valueOverrides, helmValueOverrides.ValueOverrides...)
+		}
+		kvs, err = h.retrieveHelmOverrideResources(context, valueOverrides)
 		if err != nil {
 			return overrides, err
 		}
 	}
 
+	// Create a file from the kubernetes section, which has precedence over the Verrazzano Helm values
+	newKvs, err := h.fileFromKubernetesSpec(context)
+	if err != nil {
+		return overrides, err
+	}
+	kvs = append(kvs, newKvs...)
+
 	// Create files from the Verrazzano Helm values
-	newKvs, err := h.filesFromVerrazzanoHelm(context, namespace, additionalValues)
+	newKvs, err = h.filesFromVerrazzanoHelm(context, namespace, additionalValues)
 	if err != nil {
 		return overrides, err
 	}
@@ -458,6 +689,19 @@ func (h HelmComponent) filesFromVerrazzanoHelm(context spi.ComponentContext, nam
 	return newKvs, nil
 }
 
+// removeOverrideFiles removes the temporary files in the Helm overrides.  Other components may be installed or
+// upgraded at the same time, so only the files created for this component are removed.
+func removeOverrideFiles(log vzlog.VerrazzanoLogger, overrides []helm.HelmOverrides) {
+	for _, override := range overrides {
+		if len(override.FileOverride) == 0 || filepath.Dir(override.FileOverride) != filepath.Clean(os.TempDir()) {
+			continue
+		}
+		if err := os.Remove(override.FileOverride); err != nil && !os.IsNotExist(err) {
+			log.Errorf("Error deleting temp file %s: %v", override.FileOverride, err)
+		}
+	}
+}
+
 // organizeHelmOverrides creates a list of Helm overrides from key value pairs in reverse precedence (0th value has the lowest precedence)
 // Each key value pair gets its own override object to keep strict precedence
 func (h HelmComponent) organizeHelmOverrides(kvs []bom.KeyValue) []helm.HelmOverrides {
@@ -600,6 +844,21 @@ func getImageOverrides(subcomponentName string) ([]bom.KeyValue, error) {
 	return kvs, nil
 }
 
+// Get the chart version of the subcomponent from the BOM, an empty string is returned if the subcomponent is not in
+// the BOM
+func getChartVersion(subcomponentName string) (string, error) {
+	bomFile, err := bom.NewBom(config.GetDefaultBOMFilePath())
+	if err != nil {
+		return "", err
+	}
+	sc, err := bomFile.GetSubcomponent(subcomponentName)
+	if err != nil {
+		// The release is not a subcomponent of the BOM, for example an extension
+		return "", nil
+	}
+	return sc.Version, nil
+}
+
 func (h HelmComponent) GetSkipUpgrade() bool {
 	return h.SkipUpgrade
 }
@@ -449,6 +449,98 @@ func TestIsInstalled(t *testing.T) {
 	a.False(comp.IsInstalled(spi.NewFakeContext(client, &v1alpha1.Verrazzano{ObjectMeta: v1.ObjectMeta{Namespace: "foo"}}, false)))
 }
 
+// TestUninstall tests the component uninstall
+// GIVEN a component
+//  WHEN I call Uninstall
+//  THEN the helm release is uninstalled only if it is installed
+func TestUninstall(t *testing.T) {
+	a := assert.New(t)
+
+	comp := HelmComponent{
+		ReleaseName:             "istio-operator",
+		ChartNamespace:          "istio-system",
+		IgnoreNamespaceOverride: true,
+	}
+
+	var uninstalled bool
+	SetUninstallFunc(func(_ vzlog.VerrazzanoLogger, releaseName string, namespace string, dryRun bool) (stdout []byte, stderr []byte, err error) {
+		a.Equal(comp.ReleaseName, releaseName)
+		a.Equal(comp.ChartNamespace, namespace)
+		uninstalled = true
+		return []byte{}, []byte{}, nil
+	})
+	defer SetDefaultUninstallFunc()
+	defer helm.SetDefaultRunner()
+
+	// Release is installed, expect the uninstall to be called
+	helm.SetCmdRunner(genericHelmTestRunner{})
+	a.NoError(comp.Uninstall(spi.NewFakeContext(nil, &v1alpha1.Verrazzano{}, false)))
+	a.True(uninstalled)
+
+	// Release is not installed, expect the uninstall to be skipped
+	uninstalled = false
+	helm.SetCmdRunner(genericHelmTestRunner{stdErr: []byte("Error: release: not found"), err: fmt.Errorf("Not installed")})
+	a.NoError(comp.Uninstall(spi.NewFakeContext(nil, &v1alpha1.Verrazzano{}, false)))
+	a.False(uninstalled)
+}
+
+// TestRollback tests GetReleaseRevision, GetReleaseVersion and Rollback
+// GIVEN a component
+//  WHEN I call Rollback with the revision returned by GetReleaseRevision
+//  THEN the chart version of the release is returned and the Helm release of the component is rolled back to the revision
+func TestRollback(t *testing.T) {
+	a := assert.New(t)
+
+	comp := HelmComponent{
+		ReleaseName:             "istio-operator",
+		ChartNamespace:          "istio-system",
+		IgnoreNamespaceOverride: true,
+	}
+
+	var rolledBackRevision int
+	SetRollbackFunc(func(_ vzlog.VerrazzanoLogger, releaseName string, namespace string, revision int, wait bool, dryRun bool) (stdout []byte, stderr []byte, err error) {
+		a.Equal(comp.ReleaseName, releaseName)
+		a.Equal(comp.ChartNamespace, namespace)
+		a.True(wait)
+		rolledBackRevision = revision
+		return []byte{}, []byte{}, nil
+	})
+	defer SetDefaultRollbackFunc()
+	defer helm.SetDefaultRunner()
+
+	helm.SetCmdRunner(genericHelmTestRunner{stdOut: []byte(`{"info": {"status": "deployed"}, "chart": {"metadata": {"version": "1.13.2"}}, "version": 2}`)})
+	ctx := spi.NewFakeContext(nil, &v1alpha1.Verrazzano{}, false)
+	revision, err := comp.GetReleaseRevision(ctx)
+	a.NoError(err)
+	a.Equal(2, revision)
+	version, err := comp.GetReleaseVersion(ctx)
+	a.NoError(err)
+	a.Equal("1.13.2", version)
+	a.NoError(comp.Rollback(ctx, revision))
+	a.Equal(2, rolledBackRevision)
+}
+
+// TestIsUninstalled tests IsUninstalled
+// GIVEN a component
+//  WHEN I call IsUninstalled
+//  THEN true is returned if the helm release is not installed, false otherwise
+func TestIsUninstalled(t *testing.T) {
+	a := assert.New(t)
+
+	comp := HelmComponent{}
+	defer helm.SetDefaultRunner()
+
+	helm.SetCmdRunner(genericHelmTestRunner{})
+	uninstalled, err := comp.IsUninstalled(spi.NewFakeContext(nil, &v1alpha1.Verrazzano{}, false))
+	a.NoError(err)
+	a.False(uninstalled)
+
+	helm.SetCmdRunner(genericHelmTestRunner{stdErr: []byte("Error: release: not found"), err: fmt.Errorf("Not installed")})
+	uninstalled, err = comp.IsUninstalled(spi.NewFakeContext(nil, &v1alpha1.Verrazzano{}, false))
+	a.NoError(err)
+	a.True(uninstalled)
+}
+
 // TestReady tests IsReady
 // GIVEN a component
 //  WHEN I call IsReady
@@ -973,3 +1065,172 @@ func fakePreUpgrade(log vzlog.VerrazzanoLogger, client clipkg.Client, release st
 
 	return nil
 }
+
+// TestRemoveOverrideFiles tests the removeOverrideFiles func
+// GIVEN Helm overrides that contain temporary files
+// WHEN removeOverrideFiles is called
+// THEN only the temporary files in the overrides are removed
+func TestRemoveOverrideFiles(t *testing.T) {
+	a := assert.New(t)
+	log := vzlog.DefaultLogger()
+
+	overrideFile, err := os.CreateTemp(os.TempDir(), "helm-overrides-*.yaml")
+	a.NoError(err)
+	a.NoError(overrideFile.Close())
+	otherFile, err := os.CreateTemp(os.TempDir(), "helm-overrides-*.yaml")
+	a.NoError(err)
+	a.NoError(otherFile.Close())
+	defer os.Remove(otherFile.Name())
+
+	removeOverrideFiles(log, []helm.HelmOverrides{
+		{FileOverride: overrideFile.Name()},
+		{FileOverride: testBomFilePath},
+		{SetOverrides: "key=value"},
+	})
+	_, err = os.Stat(overrideFile.Name())
+	a.True(os.IsNotExist(err))
+	_, err = os.Stat(otherFile.Name())
+	a.NoError(err)
+	_, err = os.Stat(testBomFilePath)
+	a.NoError(err)
+}
+
+// TestGetOverrideValues tests the GetOverrideValues func
+// GIVEN a component with additional overrides and a global image pull secret
+// WHEN GetOverrideValues is called
+// THEN the merged Helm values are returned and the image pull secret is not copied to the component namespace
+func TestGetOverrideValues(t *testing.T) {
+	a := assert.New(t)
+
+	comp := HelmComponent{
+		ReleaseName:            "rancher",
+		ChartDir:               "ChartDir",
+		ChartNamespace:         "chartNS",
+		IgnoreImageOverrides:   true,
+		ImagePullSecretKeyname: "imagePullSecret",
+		AppendOverridesFunc: func(_ spi.ComponentContext, _ string, _ string, _ string, kvs []bom.KeyValue) ([]bom.KeyValue, error) {
+			return append(kvs, bom.KeyValue{Key: "image.tag", Value: "1.0", SetString: true}), nil
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: constants.GlobalImagePullSecName, Namespace: "default"}},
+	).Build()
+
+	values, err := comp.GetOverrideValues(spi.NewFakeContext(client, &v1alpha1.Verrazzano{ObjectMeta: v1.ObjectMeta{Namespace: "foo"}}, true))
+	a.NoError(err)
+	a.Equal(map[string]interface{}{
+		"image":           map[string]interface{}{"tag": "1.0"},
+		"imagePullSecret": constants.GlobalImagePullSecName,
+	}, values)
+
+	err = client.Get(context.TODO(), types.NamespacedName{Namespace: "chartNS", Name: constants.GlobalImagePullSecName}, &corev1.Secret{})
+	a.Error(err)
+}
+
+// TestGetDrift tests the GetDrift func
+// GIVEN a component with a Helm release
+// WHEN the release values or chart have been changed outside of Verrazzano
+// THEN the changed values and the installed chart are reported when its version is not the version in the BOM, the
+// values only in the release are ignored
+func TestGetDrift(t *testing.T) {
+	a := assert.New(t)
+
+	comp := HelmComponent{
+		ReleaseName:          "rancher",
+		ChartDir:             "ChartDir",
+		ChartNamespace:       "chartNS",
+		IgnoreImageOverrides: true,
+		AppendOverridesFunc: func(_ spi.ComponentContext, _ string, _ string, _ string, kvs []bom.KeyValue) ([]bom.KeyValue, error) {
+			return append(kvs, bom.KeyValue{Key: "image.tag", Value: "1.0", SetString: true}), nil
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+	ctx := spi.NewFakeContext(client, &v1alpha1.Verrazzano{ObjectMeta: v1.ObjectMeta{Namespace: "foo"}}, false)
+
+	config.SetDefaultBomFilePath(testBomFilePath)
+	defer config.SetDefaultBomFilePath("")
+	releaseChart := "rancher-2.6.4"
+	helm.SetReleaseChartFunction(func(releaseName string, namespace string) (string, error) {
+		return releaseChart, nil
+	})
+	defer helm.SetDefaultReleaseChartFunction()
+	defer helm.SetDefaultRunner()
+
+	// The release matches the Verrazzano configuration
+	helm.SetCmdRunner(genericHelmTestRunner{stdOut: []byte(`{"image": {"tag": "1.0"}, "replicas": 2}`)})
+	drift, err := comp.GetDrift(ctx)
+	a.NoError(err)
+	a.Empty(drift)
+
+	// The release has been changed
+	releaseChart = "rancher-2.6.3"
+	helm.SetCmdRunner(genericHelmTestRunner{stdOut: []byte(`{"image": {"tag": "2.0"}}`)})
+	drift, err = comp.GetDrift(ctx)
+	a.NoError(err)
+	a.Equal([]string{
+		"chart rancher-2.6.3 is installed instead of version 2.6.4",
+		"values image.tag have been changed",
+	}, drift)
+
+	// The chart version of a release that is not in the BOM is not checked
+	comp.ReleaseName = "my-extension"
+	helm.SetCmdRunner(genericHelmTestRunner{stdOut: []byte(`{"image": {"tag": "1.0"}}`)})
+	drift, err = comp.GetDrift(ctx)
+	a.NoError(err)
+	a.Empty(drift)
+
+	// The release is not installed
+	helm.SetCmdRunner(genericHelmTestRunner{stdErr: []byte("Error: release: not found"), err: errors.New("not found")})
+	drift, err = comp.GetDrift(ctx)
+	a.NoError(err)
+	a.Empty(drift)
+}
+
+// TestHelmValueOverrides tests the Helm value overrides of a component
+// GIVEN a component with Helm value overrides in a ConfigMap and a Secret, of which only the ConfigMap is monitored
+// WHEN GetOverrideValues and GetMonitoredOverrides are called
+// THEN the values from the ConfigMap and the Secret are overridden, and only the ConfigMap override is monitored
+func TestHelmValueOverrides(t *testing.T) {
+	a := assert.New(t)
+	config.SetDefaultBomFilePath(testBomFilePath)
+	defer config.SetDefaultBomFilePath("")
+	monitorChanges := true
+	configMapOverrides := v1alpha1.HelmValueOverrides{
+		MonitorChanges: &monitorChanges,
+		ValueOverrides: []v1alpha1.Overrides{{
+			ConfigMapRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}, Key: "values.yaml"},
+		}},
+	}
+	secretOverrides := v1alpha1.HelmValueOverrides{
+		ValueOverrides: []v1alpha1.Overrides{{
+			SecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "sec"}, Key: "values.yaml"},
+		}},
+	}
+	comp := HelmComponent{
+		ReleaseName:    "rancher",
+		ChartDir:       "ChartDir",
+		ChartNamespace: "chartNS",
+		GetHelmValueOverrides: func(_ spi.ComponentContext) []v1alpha1.HelmValueOverrides {
+			return []v1alpha1.HelmValueOverrides{configMapOverrides, secretOverrides}
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{Namespace: "foo", Name: "cm"},
+			Data:       map[string]string{"values.yaml": "replicas: 3"},
+		},
+		&corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Namespace: "foo", Name: "sec"},
+			Data:       map[string][]byte{"values.yaml": []byte("image:\n  tag: \"2.0\"")},
+		},
+	).Build()
+	ctx := spi.NewFakeContext(client, &v1alpha1.Verrazzano{ObjectMeta: v1.ObjectMeta{Namespace: "foo"}}, false)
+
+	values, err := comp.GetOverrideValues(ctx)
+	a.NoError(err)
+	flattened := helm.FlattenValues(values)
+	a.Equal("3", flattened["replicas"])
+	a.Equal(`"2.0"`, flattened["image.tag"])
+
+	a.Equal(configMapOverrides.ValueOverrides, comp.GetMonitoredOverrides(ctx))
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package helm
+
+import (
+	"strings"
+
+	"github.com/verrazzano/verrazzano/pkg/bom"
+	vzos "github.com/verrazzano/verrazzano/pkg/os"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	"sigs.k8s.io/yaml"
+)
+
+// KubernetesValueKeys are the Helm value keys of a workload of a chart for the settings of the kubernetes section of
+// the component.  A setting is only applied to the workloads that have a key for it, so the replicas, resources and
+// pod disruption budget keys are usually only set for the main workload of the component.
+type KubernetesValueKeys struct {
+	Replicas          string
+	Affinity          string
+	Tolerations       string
+	NodeSelector      string
+	PriorityClassName string
+	Resources         string
+
+	// AffinityAsString is true for charts that render the affinity from a YAML string
+	AffinityAsString bool
+
+	// PodDisruptionBudget is the key of the map with the minAvailable and maxUnavailable values
+	PodDisruptionBudget string
+
+	// PodDisruptionBudgetEnabled is the key of the flag that enables the pod disruption budget, for the charts that
+	// have one
+	PodDisruptionBudgetEnabled string
+}
+
+// getKubernetesSpecSig is the signature for providing the kubernetes section of the component
+type getKubernetesSpecSig func(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec
+
+// ValidateKubernetesSettings checks that the kubernetes section of the component only has the settings that the
+// component has Helm value keys for
+func (h HelmComponent) ValidateKubernetesSettings(vz *vzapi.Verrazzano) error {
+	if h.GetKubernetesSpec == nil {
+		return nil
+	}
+	return vzconfig.ValidateKubernetesSettings(h.JSONName, h.GetKubernetesSpec(vz), h.getSupportedKubernetesSettings()...)
+}
+
+// getSupportedKubernetesSettings returns the JSON names of the settings that at least one workload has a key for
+func (h HelmComponent) getSupportedKubernetesSettings() []string {
+	var supported []string
+	add := func(key string, setting string) {
+		if len(key) > 0 {
+			supported = append(supported, setting)
+		}
+	}
+	for _, keys := range h.KubernetesValueKeys {
+		add(keys.Replicas, vzconfig.KubernetesReplicas)
+		add(keys.Affinity, vzconfig.KubernetesAffinity)
+		add(keys.Tolerations, vzconfig.KubernetesTolerations)
+		add(keys.NodeSelector, vzconfig.KubernetesNodeSelector)
+		add(keys.PriorityClassName, vzconfig.KubernetesPriorityClassName)
+		add(keys.Resources, vzconfig.KubernetesResources)
+		add(keys.PodDisruptionBudget, vzconfig.KubernetesPodDisruptionBudget)
+	}
+	return supported
+}
+
+// fileFromKubernetesSpec creates a Helm values file with the settings of the kubernetes section of the component
+func (h HelmComponent) fileFromKubernetesSpec(context spi.ComponentContext) ([]bom.KeyValue, error) {
+	if h.GetKubernetesSpec == nil {
+		return nil, nil
+	}
+	values, err := buildKubernetesValues(h.GetKubernetesSpec(context.EffectiveCR()), h.KubernetesValueKeys)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, context.Log().ErrorfNewErr("Could not create YAML file from the kubernetes settings: %v", err)
+	}
+	file, err := vzos.CreateTempFile(context.Log(), "helm-overrides-*.yaml", data)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	return []bom.KeyValue{{Value: file.Name(), IsFile: true}}, nil
+}
+
+// buildKubernetesValues returns the Helm values of the settings of the kubernetes section for the keys of each
+// workload
+func buildKubernetesValues(spec *vzapi.CommonKubernetesSpec, workloads []KubernetesValueKeys) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if spec == nil {
+		return values, nil
+	}
+	for _, keys := range workloads {
+		if spec.Replicas > 0 {
+			setValue(values, keys.Replicas, spec.Replicas)
+		}
+		if spec.Affinity != nil {
+			if keys.AffinityAsString {
+				affinity, err := yaml.Marshal(spec.Affinity)
+				if err != nil {
+					return nil, err
+				}
+				setValue(values, keys.Affinity, string(affinity))
+			} else {
+				setValue(values, keys.Affinity, spec.Affinity)
+			}
+		}
+		if len(spec.Tolerations) > 0 {
+			setValue(values, keys.Tolerations, spec.Tolerations)
+		}
+		if len(spec.NodeSelector) > 0 {
+			setValue(values, keys.NodeSelector, spec.NodeSelector)
+		}
+		if len(spec.PriorityClassName) > 0 {
+			setValue(values, keys.PriorityClassName, spec.PriorityClassName)
+		}
+		if spec.Resources != nil {
+			setValue(values, keys.Resources, spec.Resources)
+		}
+		if spec.PodDisruptionBudget != nil {
+			// Both values are set, so that a null value removes the default value of the chart
+			setValue(values, keys.PodDisruptionBudget, map[string]interface{}{
+				"minAvailable":   spec.PodDisruptionBudget.MinAvailable,
+				"maxUnavailable": spec.PodDisruptionBudget.MaxUnavailable,
+			})
+			setValue(values, keys.PodDisruptionBudgetEnabled, true)
+		}
+	}
+	return values, nil
+}
+
+// setValue sets the value of a dotted Helm value key in the nested values, nothing is set for an empty key
+func setValue(values map[string]interface{}, key string, value interface{}) {
+	if len(key) == 0 {
+		return
+	}
+	segments := strings.Split(key, ".")
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := values[segment].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			values[segment] = next
+		}
+		values = next
+	}
+	values[segments[len(segments)-1]] = value
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package helm
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+var testKubernetesValueKeys = []KubernetesValueKeys{
+	{
+		Replicas:            "replicaCount",
+		Affinity:            "affinity",
+		Tolerations:         "tolerations",
+		NodeSelector:        "nodeSelector",
+		PriorityClassName:   "priorityClassName",
+		Resources:           "resources",
+		PodDisruptionBudget: "podDisruptionBudget",
+	},
+	{
+		Affinity:     "webhook.affinity",
+		Tolerations:  "webhook.tolerations",
+		NodeSelector: "webhook.nodeSelector",
+	},
+}
+
+// TestBuildKubernetesValues tests the buildKubernetesValues function
+// GIVEN a kubernetes section and the value keys of the workloads of a chart
+// WHEN buildKubernetesValues is called
+// THEN the settings are set for the workloads that have a key for them
+func TestBuildKubernetesValues(t *testing.T) {
+	values, err := buildKubernetesValues(nil, testKubernetesValueKeys)
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+
+	maxUnavailable := intstr.FromInt(1)
+	spec := &v1alpha1.CommonKubernetesSpec{
+		Replicas:          3,
+		Tolerations:       []corev1.Toleration{{Key: "key", Operator: corev1.TolerationOpExists}},
+		NodeSelector:      map[string]string{"disk": "ssd"},
+		PriorityClassName: "high",
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+		PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable},
+	}
+	values, err = buildKubernetesValues(spec, testKubernetesValueKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), values["replicaCount"])
+	assert.Equal(t, spec.Tolerations, values["tolerations"])
+	assert.Equal(t, spec.NodeSelector, values["nodeSelector"])
+	assert.Equal(t, "high", values["priorityClassName"])
+	assert.Equal(t, spec.Resources, values["resources"])
+	assert.NotContains(t, values, "affinity")
+
+	// Both values of the pod disruption budget are set, so that the default of the chart is removed
+	pdb := values["podDisruptionBudget"].(map[string]interface{})
+	assert.Nil(t, pdb["minAvailable"])
+	assert.Equal(t, &maxUnavailable, pdb["maxUnavailable"])
+
+	// The second workload only gets the scheduling settings
+	webhook := values["webhook"].(map[string]interface{})
+	assert.Len(t, webhook, 2)
+	assert.Equal(t, spec.Tolerations, webhook["tolerations"])
+	assert.Equal(t, spec.NodeSelector, webhook["nodeSelector"])
+}
+
+// TestBuildKubernetesValuesAffinityAsString tests the buildKubernetesValues function
+// GIVEN a kubernetes section with an affinity
+// WHEN buildKubernetesValues is called for a chart that renders the affinity from a string
+// THEN the affinity is set as a YAML string
+func TestBuildKubernetesValuesAffinityAsString(t *testing.T) {
+	spec := &v1alpha1.CommonKubernetesSpec{
+		Affinity: &corev1.Affinity{
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					{Weight: 100, PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+				},
+			},
+		},
+	}
+	values, err := buildKubernetesValues(spec, []KubernetesValueKeys{{Affinity: "affinity", AffinityAsString: true}})
+	assert.NoError(t, err)
+	affinity, ok := values["affinity"].(string)
+	assert.True(t, ok)
+
+	parsed := &corev1.Affinity{}
+	assert.NoError(t, yaml.Unmarshal([]byte(affinity), parsed))
+	assert.Equal(t, spec.Affinity, parsed)
+}
+
+// TestValidateKubernetesSettings tests the ValidateKubernetesSettings function
+// GIVEN a Helm component with value keys for some of the settings
+// WHEN ValidateKubernetesSettings is called
+// THEN an error is returned for the settings the chart has no key for
+func TestValidateKubernetesSettings(t *testing.T) {
+	spec := &v1alpha1.CommonKubernetesSpec{}
+	comp := HelmComponent{
+		JSONName: "test",
+		GetKubernetesSpec: func(_ *v1alpha1.Verrazzano) *v1alpha1.CommonKubernetesSpec {
+			return spec
+		},
+		KubernetesValueKeys: []KubernetesValueKeys{{Replicas: "replicaCount", Resources: "resources"}},
+	}
+	vz := &v1alpha1.Verrazzano{}
+
+	assert.NoError(t, HelmComponent{}.ValidateKubernetesSettings(vz))
+	assert.NoError(t, comp.ValidateKubernetesSettings(vz))
+
+	spec.Replicas = 2
+	assert.NoError(t, comp.ValidateKubernetesSettings(vz))
+
+	spec.NodeSelector = map[string]string{"disk": "ssd"}
+	err := comp.ValidateKubernetesSettings(vz)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nodeSelector")
+}
+
+// TestFileFromKubernetesSpec tests the fileFromKubernetesSpec function
+// GIVEN a Helm component with a kubernetes section
+// WHEN fileFromKubernetesSpec is called
+// THEN a values file is created only when there are settings
+func TestFileFromKubernetesSpec(t *testing.T) {
+	spec := &v1alpha1.CommonKubernetesSpec{}
+	comp := HelmComponent{
+		GetKubernetesSpec: func(_ *v1alpha1.Verrazzano) *v1alpha1.CommonKubernetesSpec {
+			return spec
+		},
+		KubernetesValueKeys: []KubernetesValueKeys{{Replicas: "deployment.replicas"}},
+	}
+	ctx := spi.NewFakeContext(fake.NewClientBuilder().Build(), &v1alpha1.Verrazzano{}, false)
+
+	kvs, err := comp.fileFromKubernetesSpec(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, kvs)
+
+	spec.Replicas = 2
+	kvs, err = comp.fileFromKubernetesSpec(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, kvs, 1)
+	assert.True(t, kvs[0].IsFile)
+	defer os.Remove(kvs[0].Value)
+
+	data, err := os.ReadFile(kvs[0].Value)
+	assert.NoError(t, err)
+	assert.Equal(t, "deployment:\n  replicas: 2\n", string(data))
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 
@@ -21,11 +22,13 @@ import (
 	"github.com/verrazzano/verrazzano/pkg/istio"
 	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/status"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -139,12 +142,32 @@ func (i istioComponent) ValidateInstall(vz *vzapi.Verrazzano) error {
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (i istioComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	if i.IsEnabled(old) && !i.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	return i.validateForExternalIPSWithNodePort(&new.Spec)
 }
 
+// ValidateKubernetesSettings checks the kubernetes sections of istiod and the gateways, which support all the settings
+func (i istioComponent) ValidateKubernetesSettings(vz *vzapi.Verrazzano) error {
+	istio := vz.Spec.Components.Istio
+	if istio == nil {
+		return nil
+	}
+	validate := func(name string, spec *vzapi.CommonKubernetesSpec) error {
+		return vzconfig.ValidateKubernetesSettings(name, spec, vzconfig.GetKubernetesSettings(spec)...)
+	}
+	if err := validate(ComponentJSONName, istio.Kubernetes); err != nil {
+		return err
+	}
+	if istio.Ingress != nil && istio.Ingress.Kubernetes != nil {
+		if err := validate(ComponentJSONName+".ingress", &istio.Ingress.Kubernetes.CommonKubernetesSpec); err != nil {
+			return err
+		}
+	}
+	if istio.Egress != nil && istio.Egress.Kubernetes != nil {
+		return validate(ComponentJSONName+".egress", &istio.Egress.Kubernetes.CommonKubernetesSpec)
+	}
+	return nil
+}
+
 // validateForExternalIPSWithNodePort checks that externalIPs are set when Type=NodePort
 func (i istioComponent) validateForExternalIPSWithNodePort(vz *vzapi.VerrazzanoSpec) error {
 	// good if istio or istio.ingress is not set
@@ -165,6 +188,19 @@ func (i istioComponent) validateForExternalIPSWithNodePort(vz *vzapi.VerrazzanoS
 	return nil
 }
 
+// RunPreflightChecks checks that the cluster supports LoadBalancer services when the ingress gateway is of the
+// LoadBalancer type
+func (i istioComponent) RunPreflightChecks(ctx spi.ComponentContext) ([]vzapi.PreflightCheck, error) {
+	if comp := ctx.EffectiveCR().Spec.Components.Istio; comp != nil && comp.Ingress != nil && comp.Ingress.Type == vzapi.NodePort {
+		return nil, nil
+	}
+	check, err := common.CheckLoadBalancerSupport(ctx, ComponentName)
+	if err != nil {
+		return nil, err
+	}
+	return []vzapi.PreflightCheck{check}, nil
+}
+
 func (i istioComponent) Upgrade(context spi.ComponentContext) error {
 	log := context.Log()
 
@@ -384,3 +420,21 @@ func getImageOverrides() ([]bom.KeyValue, error) {
 	}
 	return kvs, nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of Istio.  The gateways are reached from outside the cluster and
+// route to the services of the applications, and istiod is reached by the proxy sidecars of all the pods of the mesh.
+func (i istioComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(IstioNamespace, "allow-istio", nil,
+			[]netv1.NetworkPolicyIngressRule{
+				// The gateways and their health checks from anywhere, and the webhooks from the API server
+				netpolicy.IngressFrom(nil, 8443, 15021, 15017),
+				netpolicy.IngressFrom(common.ClusterPeers(), 15012),
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", nil)}),
+				netpolicy.PrometheusIngress(15014, common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(nil),
+			}),
+	}, nil
+}
@@ -64,6 +64,25 @@ spec:
           replicaCount: {{.EgressReplicaCount}}
           affinity:
 {{ multiLineIndent 12 .EgressAffinity }}
+          {{- if .EgressTolerations }}
+          tolerations:
+{{ multiLineIndent 12 .EgressTolerations }}
+          {{- end}}
+          {{- if .EgressNodeSelector }}
+          nodeSelector:
+{{ multiLineIndent 12 .EgressNodeSelector }}
+          {{- end}}
+          {{- if .EgressPriorityClassName }}
+          priorityClassName: {{.EgressPriorityClassName}}
+          {{- end}}
+          {{- if .EgressResources }}
+          resources:
+{{ multiLineIndent 12 .EgressResources }}
+          {{- end}}
+          {{- if .EgressPodDisruptionBudget }}
+          podDisruptionBudget:
+{{ multiLineIndent 12 .EgressPodDisruptionBudget }}
+          {{- end}}
     ingressGateways:
       - name: istio-ingressgateway
         enabled: true
@@ -81,16 +100,45 @@ spec:
           {{- end}}
           affinity:
 {{ multiLineIndent 12 .IngressAffinity }}
+          {{- if .IngressTolerations }}
+          tolerations:
+{{ multiLineIndent 12 .IngressTolerations }}
+          {{- end}}
+          {{- if .IngressNodeSelector }}
+          nodeSelector:
+{{ multiLineIndent 12 .IngressNodeSelector }}
+          {{- end}}
+          {{- if .IngressPriorityClassName }}
+          priorityClassName: {{.IngressPriorityClassName}}
+          {{- end}}
+          {{- if .IngressResources }}
+          resources:
+{{ multiLineIndent 12 .IngressResources }}
+          {{- end}}
+          {{- if .IngressPodDisruptionBudget }}
+          podDisruptionBudget:
+{{ multiLineIndent 12 .IngressPodDisruptionBudget }}
+          {{- end}}
 `
 
 type ReplicaData struct {
-	IngressReplicaCount uint32
-	EgressReplicaCount  uint32
-	IngressAffinity     string
-	EgressAffinity      string
-	IngressServiceType  string
-	IngressServicePorts string
-	ExternalIps         string
+	IngressReplicaCount        uint32
+	EgressReplicaCount         uint32
+	IngressAffinity            string
+	EgressAffinity             string
+	IngressServiceType         string
+	IngressServicePorts        string
+	ExternalIps                string
+	IngressTolerations         string
+	EgressTolerations          string
+	IngressNodeSelector        string
+	EgressNodeSelector         string
+	IngressPriorityClassName   string
+	EgressPriorityClassName    string
+	IngressResources           string
+	EgressResources            string
+	IngressPodDisruptionBudget string
+	EgressPodDisruptionBudget  string
 }
 
 // BuildIstioOperatorYaml builds the IstioOperator CR YAML that will be passed as an override to istioctl
@@ -142,6 +190,13 @@ func BuildIstioOperatorYaml(ctx spi.ComponentContext, comp *vzapi.IstioComponent
 		return "", err
 	}
 	expandedYamls = append(expandedYamls, gatewayYaml)
+	pilotYaml, err := configurePilot(comp)
+	if err != nil {
+		return "", err
+	}
+	if len(pilotYaml) > 0 {
+		expandedYamls = append(expandedYamls, pilotYaml)
+	}
 	// Merge all of the expanded YAMLs into a single YAML,
 	// second has precedence over first, third over second, and so forth.
 	merged, err := vzyaml.ReplacementMerge(expandedYamls...)
@@ -201,6 +256,21 @@ func configureGateways(istioComponent *vzapi.IstioComponent, externalIP string)
 		data.EgressAffinity = string(yml)
 	}
 
+	var err error
+	data.IngressTolerations, data.IngressNodeSelector, data.IngressResources, data.IngressPodDisruptionBudget, err =
+		marshalKubernetesSettings(istioComponent.Ingress.Kubernetes.CommonKubernetesSpec)
+	if err != nil {
+		return "", err
+	}
+	data.IngressPriorityClassName = istioComponent.Ingress.Kubernetes.PriorityClassName
+
+	data.EgressTolerations, data.EgressNodeSelector, data.EgressResources, data.EgressPodDisruptionBudget, err =
+		marshalKubernetesSettings(istioComponent.Egress.Kubernetes.CommonKubernetesSpec)
+	if err != nil {
+		return "", err
+	}
+	data.EgressPriorityClassName = istioComponent.Egress.Kubernetes.PriorityClassName
+
 	data.IngressServiceType = string(vzapi.LoadBalancer)
 	if istioComponent.Ingress.Type == vzapi.NodePort {
 		data.IngressServiceType = string(vzapi.NodePort)
@@ -248,3 +318,68 @@ func configureGateways(istioComponent *vzapi.IstioComponent, externalIP string)
 
 	return b.String(), nil
 }
+
+// marshalKubernetesSettings returns the YAML of the tolerations, node selector, resources and pod disruption budget of
+// a gateway, an empty string is returned for the settings that are not set
+func marshalKubernetesSettings(spec vzapi.CommonKubernetesSpec) (tolerations, nodeSelector, resources, pdb string, err error) {
+	marshal := func(value interface{}, isSet bool) string {
+		if !isSet || err != nil {
+			return ""
+		}
+		var yml []byte
+		yml, err = yaml.Marshal(value)
+		return string(yml)
+	}
+	tolerations = marshal(spec.Tolerations, len(spec.Tolerations) > 0)
+	nodeSelector = marshal(spec.NodeSelector, len(spec.NodeSelector) > 0)
+	resources = marshal(spec.Resources, spec.Resources != nil)
+	pdb = marshal(spec.PodDisruptionBudget, spec.PodDisruptionBudget != nil)
+	return tolerations, nodeSelector, resources, pdb, err
+}
+
+// configurePilot creates the istiod YAML from the kubernetes section of the Istio component, an empty string is
+// returned when there are no settings
+func configurePilot(istioComponent *vzapi.IstioComponent) (string, error) {
+	spec := istioComponent.Kubernetes
+	if spec == nil {
+		return "", nil
+	}
+	k8s := map[string]interface{}{}
+	if spec.Replicas > 0 {
+		k8s["replicaCount"] = spec.Replicas
+	}
+	if spec.Affinity != nil {
+		k8s["affinity"] = spec.Affinity
+	}
+	if len(spec.Tolerations) > 0 {
+		k8s["tolerations"] = spec.Tolerations
+	}
+	if len(spec.NodeSelector) > 0 {
+		k8s["nodeSelector"] = spec.NodeSelector
+	}
+	if len(spec.PriorityClassName) > 0 {
+		k8s["priorityClassName"] = spec.PriorityClassName
+	}
+	if spec.Resources != nil {
+		k8s["resources"] = spec.Resources
+	}
+	if spec.PodDisruptionBudget != nil {
+		k8s["podDisruptionBudget"] = spec.PodDisruptionBudget
+	}
+	if len(k8s) == 0 {
+		return "", nil
+	}
+	yml, err := yaml.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"components": map[string]interface{}{
+				"pilot": map[string]interface{}{
+					"k8s": k8s,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(yml), nil
+}
@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/stretchr/testify/assert"
@@ -586,6 +587,86 @@ spec:
       enableTracing: true
 `
 
+var pdbMinAvailable = intstr.FromInt(1)
+
+var cr5 = &vzapi.IstioComponent{
+	Enabled: &enabled,
+	Ingress: &vzapi.IstioIngressSection{
+		Kubernetes: &vzapi.IstioKubernetesSection{
+			CommonKubernetesSpec: vzapi.CommonKubernetesSpec{
+				Replicas:          2,
+				Tolerations:       []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "ingress", Effect: corev1.TaintEffectNoSchedule}},
+				NodeSelector:      map[string]string{"node-role": "ingress"},
+				PriorityClassName: "system-cluster-critical",
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+				PodDisruptionBudget: &vzapi.PodDisruptionBudgetSpec{MinAvailable: &pdbMinAvailable},
+			},
+		},
+	},
+	Egress: &vzapi.IstioEgressSection{
+		Kubernetes: &vzapi.IstioKubernetesSection{
+			CommonKubernetesSpec: vzapi.CommonKubernetesSpec{
+				Replicas:     1,
+				NodeSelector: map[string]string{"node-role": "egress"},
+			},
+		},
+	},
+	Kubernetes: &vzapi.CommonKubernetesSpec{
+		Replicas: 2,
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		},
+	},
+}
+
+// Resulting YAML with the scheduling and resource settings of the gateways and istiod
+const cr5Yaml = `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec:
+  components:
+    egressGateways:
+    - enabled: true
+      k8s:
+        affinity: null
+        nodeSelector:
+          node-role: egress
+        replicaCount: 1
+      name: istio-egressgateway
+    ingressGateways:
+    - enabled: true
+      k8s:
+        affinity: null
+        nodeSelector:
+          node-role: ingress
+        podDisruptionBudget:
+          minAvailable: 1
+        priorityClassName: system-cluster-critical
+        replicaCount: 2
+        resources:
+          requests:
+            memory: 256Mi
+        service:
+          type: LoadBalancer
+        tolerations:
+        - effect: NoSchedule
+          key: dedicated
+          operator: Equal
+          value: ingress
+      name: istio-ingressgateway
+    pilot:
+      k8s:
+        replicaCount: 2
+        resources:
+          limits:
+            cpu: "1"
+  values:
+    meshConfig:
+      enableTracing: false
+`
+
 // TestBuildIstioOperatorYaml tests the BuildIstioOperatorYaml function
 // GIVEN an Verrazzano CR Istio component
 // WHEN BuildIstioOperatorYaml is called
@@ -634,6 +715,12 @@ func TestBuildIstioOperatorYaml(t *testing.T) {
 			expected: cr4Yaml,
 			ctx:      spi.NewFakeContext(clientForJaeger, jaegerEnabledCR, false),
 		},
+		{
+			testName: "Override scheduling and resources of the gateways and istiod",
+			value:    cr5,
+			expected: cr5Yaml,
+			ctx:      fakeCtx,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.testName, func(t *testing.T) {
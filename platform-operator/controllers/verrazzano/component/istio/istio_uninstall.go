@@ -0,0 +1,125 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package istio
+
+import (
+	"strings"
+
+	vzconst "github.com/verrazzano/verrazzano/pkg/constants"
+	"github.com/verrazzano/verrazzano/pkg/istio"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	istioSidecarInjectorWebhook = "istio-sidecar-injector"
+	istiodValidatingWebhook     = "istiod-istio-system"
+	istioDefaultSecret          = "istio.default"
+	istioRootCertConfigMap      = "istio-ca-root-cert"
+)
+
+type uninstallFuncSig func(log vzlog.VerrazzanoLogger) (stdout []byte, stderr []byte, err error)
+
+// uninstallFunc is the default uninstall function
+var uninstallFunc uninstallFuncSig = istio.Uninstall
+
+func SetIstioUninstallFunction(fn uninstallFuncSig) {
+	uninstallFunc = fn
+}
+
+func SetDefaultIstioUninstallFunction() {
+	uninstallFunc = istio.Uninstall
+}
+
+// PreUninstall performs any Istio pre-uninstall processing
+func (i istioComponent) PreUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+// Uninstall removes the Istio control plane using istioctl
+func (i istioComponent) Uninstall(compContext spi.ComponentContext) error {
+	if compContext.IsDryRun() {
+		compContext.Log().Debug("Istio Uninstall dry run")
+		return nil
+	}
+	installed, err := i.IsInstalled(compContext)
+	if err != nil {
+		return err
+	}
+	if !installed {
+		compContext.Log().Infof("Skipping uninstall of component %s since it is not installed", ComponentName)
+		return nil
+	}
+	if _, _, err := uninstallFunc(compContext.Log()); err != nil {
+		return compContext.Log().ErrorfNewErr("Failed uninstalling Istio: %v", err)
+	}
+	return nil
+}
+
+// PostUninstall removes the Istio resources that istioctl leaves behind: the webhook configurations, the cluster
+// roles, the CRDs, the secrets and the root CA ConfigMaps of the namespaces, and the Istio namespace
+func (i istioComponent) PostUninstall(compContext spi.ComponentContext) error {
+	if compContext.IsDryRun() {
+		compContext.Log().Debug("Istio PostUninstall dry run")
+		return nil
+	}
+	if err := common.DeleteObject(compContext, &admv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: istioSidecarInjectorWebhook}}); err != nil {
+		return err
+	}
+	if err := common.DeleteObject(compContext, &admv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: istiodValidatingWebhook}}); err != nil {
+		return err
+	}
+	if err := common.DeleteResources(compContext, &rbacv1.ClusterRoleBindingList{}, common.NameContains("istio-system", "istio-multi")); err != nil {
+		return err
+	}
+	if err := common.DeleteResources(compContext, &rbacv1.ClusterRoleList{}, common.NameContains("istio-system", "istio-reader", "istiocoredns")); err != nil {
+		return err
+	}
+	if err := common.ForceDeleteResources(compContext, &apiextv1.CustomResourceDefinitionList{}, common.CRDGroupHasSuffix("istio.io")); err != nil {
+		return err
+	}
+	for _, namespace := range []string{"default", "kube-public", "kube-node-lease"} {
+		if err := common.DeleteObject(compContext, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: istioDefaultSecret}}); err != nil {
+			return err
+		}
+	}
+	if err := common.DeleteResources(compContext, &corev1.SecretList{}, isIstioSecret, clipkg.InNamespace(vzconst.KubeSystem)); err != nil {
+		return err
+	}
+	if err := common.DeleteResources(compContext, &corev1.ConfigMapList{}, isRootCertConfigMap); err != nil {
+		return err
+	}
+	return common.DeleteNamespaces(compContext, IstioNamespace)
+}
+
+// isIstioSecret returns true if the name or an annotation of the secret refers to Istio
+func isIstioSecret(obj clipkg.Object) bool {
+	if strings.Contains(obj.GetName(), "istio.") {
+		return true
+	}
+	for key, value := range obj.GetAnnotations() {
+		if strings.Contains(key, "istio.") || strings.Contains(value, "istio.") {
+			return true
+		}
+	}
+	return false
+}
+
+// isRootCertConfigMap returns true if the ConfigMap is the Istio root CA certificate of a namespace
+func isRootCertConfigMap(obj clipkg.Object) bool {
+	return obj.GetName() == istioRootCertConfigMap
+}
+
+// IsUninstalled returns true if the Istio control plane deployment has been removed
+func (i istioComponent) IsUninstalled(compContext spi.ComponentContext) (bool, error) {
+	installed, err := i.IsInstalled(compContext)
+	return !installed, err
+}
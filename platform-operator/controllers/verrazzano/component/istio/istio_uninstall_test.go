@@ -0,0 +1,128 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package istio
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzconst "github.com/verrazzano/verrazzano/pkg/constants"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestUninstall tests the Istio Uninstall call
+// GIVEN an Istio component
+//  WHEN I call Uninstall with Istio installed
+//  THEN istioctl uninstall is called
+func TestUninstall(t *testing.T) {
+	a := assert.New(t)
+
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: IstiodDeployment, Namespace: IstioNamespace}},
+	).Build()
+
+	var called bool
+	SetIstioUninstallFunction(func(log vzlog.VerrazzanoLogger) (stdout []byte, stderr []byte, err error) {
+		called = true
+		return []byte{}, []byte{}, nil
+	})
+	defer SetDefaultIstioUninstallFunction()
+
+	comp := istioComponent{}
+	a.NoError(comp.Uninstall(spi.NewFakeContext(client, &installv1alpha1.Verrazzano{}, false)))
+	a.True(called)
+}
+
+// TestUninstallNotInstalled tests the Istio Uninstall call
+// GIVEN an Istio component
+//  WHEN I call Uninstall with Istio not installed
+//  THEN istioctl uninstall is not called and IsUninstalled returns true
+func TestUninstallNotInstalled(t *testing.T) {
+	a := assert.New(t)
+
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+
+	SetIstioUninstallFunction(func(log vzlog.VerrazzanoLogger) (stdout []byte, stderr []byte, err error) {
+		a.Fail("istioctl uninstall should not be called")
+		return []byte{}, []byte{}, nil
+	})
+	defer SetDefaultIstioUninstallFunction()
+
+	comp := istioComponent{}
+	ctx := spi.NewFakeContext(client, &installv1alpha1.Verrazzano{}, false)
+	a.NoError(comp.Uninstall(ctx))
+	uninstalled, err := comp.IsUninstalled(ctx)
+	a.NoError(err)
+	a.True(uninstalled)
+}
+
+// TestPostUninstall tests the Istio PostUninstall call
+// GIVEN an Istio component
+// WHEN I call PostUninstall
+// THEN the Istio webhook configurations, cluster roles, CRDs, secrets, ConfigMaps and namespace are deleted, and the other resources are kept
+func TestPostUninstall(t *testing.T) {
+	a := assert.New(t)
+
+	scheme := runtime.NewScheme()
+	_ = k8scheme.AddToScheme(scheme)
+	_ = apiextv1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&admv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: istioSidecarInjectorWebhook}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "istio-reader-istio-system"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "istiod-istio-system"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		&apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "gateways.networking.istio.io", Finalizers: []string{"test"}},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Group: "networking.istio.io"},
+		},
+		&apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "other.example.io"},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Group: "example.io"},
+		},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: istioDefaultSecret, Namespace: "default"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "istio.istio-reader", Namespace: vzconst.KubeSystem}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: vzconst.KubeSystem}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: istioRootCertConfigMap, Namespace: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: IstioNamespace, Finalizers: []string{"test"}}},
+	).Build()
+
+	comp := istioComponent{}
+	a.NoError(comp.PostUninstall(spi.NewFakeContext(client, &installv1alpha1.Verrazzano{}, false)))
+
+	deleted := []struct {
+		name types.NamespacedName
+		obj  clipkg.Object
+	}{
+		{types.NamespacedName{Name: istioSidecarInjectorWebhook}, &admv1.MutatingWebhookConfiguration{}},
+		{types.NamespacedName{Name: "istio-reader-istio-system"}, &rbacv1.ClusterRoleBinding{}},
+		{types.NamespacedName{Name: "istiod-istio-system"}, &rbacv1.ClusterRole{}},
+		{types.NamespacedName{Name: "gateways.networking.istio.io"}, &apiextv1.CustomResourceDefinition{}},
+		{types.NamespacedName{Name: istioDefaultSecret, Namespace: "default"}, &corev1.Secret{}},
+		{types.NamespacedName{Name: "istio.istio-reader", Namespace: vzconst.KubeSystem}, &corev1.Secret{}},
+		{types.NamespacedName{Name: istioRootCertConfigMap, Namespace: "default"}, &corev1.ConfigMap{}},
+		{types.NamespacedName{Name: IstioNamespace}, &corev1.Namespace{}},
+	}
+	for _, d := range deleted {
+		err := client.Get(context.TODO(), d.name, d.obj)
+		a.True(errors.IsNotFound(err), "expected %s to be deleted", d.name)
+	}
+	a.NoError(client.Get(context.TODO(), types.NamespacedName{Name: "other"}, &rbacv1.ClusterRole{}))
+	a.NoError(client.Get(context.TODO(), types.NamespacedName{Name: "other.example.io"}, &apiextv1.CustomResourceDefinition{}))
+	a.NoError(client.Get(context.TODO(), types.NamespacedName{Name: "other", Namespace: vzconst.KubeSystem}, &corev1.Secret{}))
+}
@@ -5,9 +5,11 @@ package operator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/verrazzano/verrazzano/pkg/bom"
 	"github.com/verrazzano/verrazzano/pkg/k8sutil"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"path"
@@ -34,7 +36,7 @@ var subcomponentNames = []string{
 }
 
 func componentInstall(ctx spi.ComponentContext) error {
-	args, err := buildInstallArgs()
+	args, err := buildInstallArgs(ctx.EffectiveCR())
 	if err != nil {
 		return err
 	}
@@ -47,7 +49,21 @@ func componentInstall(ctx spi.ComponentContext) error {
 	return nil
 }
 
-func buildInstallArgs() (map[string]interface{}, error) {
+func componentUninstall(ctx spi.ComponentContext) error {
+	args, err := buildInstallArgs(ctx.EffectiveCR())
+	if err != nil {
+		return err
+	}
+
+	// Delete the Jaeger Operator resources
+	yamlApplier := k8sutil.NewYAMLApplier(ctx.Client(), "")
+	if err := yamlApplier.DeleteFT(path.Join(config.GetThirdPartyManifestsDir(), templateFile), args); err != nil {
+		return ctx.Log().ErrorfNewErr("Failed to uninstall Jaeger Operator: %v", err)
+	}
+	return nil
+}
+
+func buildInstallArgs(effectiveCR *vzapi.Verrazzano) (map[string]interface{}, error) {
 	args := map[string]interface{}{
 		"namespace": constants.VerrazzanoMonitoringNamespace,
 	}
@@ -60,9 +76,50 @@ func buildInstallArgs() (map[string]interface{}, error) {
 			return args, err
 		}
 	}
+	var spec *vzapi.CommonKubernetesSpec
+	if effectiveCR.Spec.Components.JaegerOperator != nil {
+		spec = effectiveCR.Spec.Components.JaegerOperator.Kubernetes
+	}
+	if err := setKubernetesArgs(args, spec); err != nil {
+		return args, err
+	}
 	return args, nil
 }
 
+// setKubernetesArgs sets the template args of the kubernetes section of the component, the settings are rendered as
+// JSON, which the YAML template accepts as a flow value, and an empty string keeps the default of the template
+func setKubernetesArgs(args map[string]interface{}, spec *vzapi.CommonKubernetesSpec) error {
+	if spec == nil {
+		spec = &vzapi.CommonKubernetesSpec{}
+	}
+	args["replicas"] = uint32(1)
+	if spec.Replicas > 0 {
+		args["replicas"] = spec.Replicas
+	}
+	args["priorityClassName"] = spec.PriorityClassName
+	settings := map[string]struct {
+		value interface{}
+		isSet bool
+	}{
+		"affinity":     {spec.Affinity, spec.Affinity != nil},
+		"tolerations":  {spec.Tolerations, len(spec.Tolerations) > 0},
+		"nodeSelector": {spec.NodeSelector, len(spec.NodeSelector) > 0},
+		"resources":    {spec.Resources, spec.Resources != nil},
+	}
+	for name, setting := range settings {
+		args[name] = ""
+		if !setting.isSet {
+			continue
+		}
+		data, err := json.Marshal(setting.value)
+		if err != nil {
+			return err
+		}
+		args[name] = string(data)
+	}
+	return nil
+}
+
 func setImageOverride(args map[string]interface{}, bomFile bom.Bom, subcomponent string) error {
 	images, err := bomFile.GetImageNameList(subcomponent)
 	if err != nil {
@@ -8,9 +8,13 @@ import (
 	"fmt"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/status"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
 	appsv1 "k8s.io/api/apps/v1"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -54,6 +58,18 @@ func (c jaegerOperatorComponent) Install(ctx spi.ComponentContext) error {
 	return componentInstall(ctx)
 }
 
+func (c jaegerOperatorComponent) Uninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		return nil
+	}
+	return componentUninstall(ctx)
+}
+
+func (c jaegerOperatorComponent) IsUninstalled(ctx spi.ComponentContext) (bool, error) {
+	installed, err := c.IsInstalled(ctx)
+	return !installed, err
+}
+
 func (c jaegerOperatorComponent) Reconcile(ctx spi.ComponentContext) error {
 	return nil
 }
@@ -111,6 +127,14 @@ func (c jaegerOperatorComponent) PostInstall(_ spi.ComponentContext) error {
 	return nil
 }
 
+func (c jaegerOperatorComponent) PreUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+func (c jaegerOperatorComponent) PostUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
 func (c jaegerOperatorComponent) PreUpgrade(_ spi.ComponentContext) error {
 	return nil
 }
@@ -134,3 +158,37 @@ func (c jaegerOperatorComponent) ValidateInstall(_ *vzapi.Verrazzano) error {
 func (c jaegerOperatorComponent) ValidateUpdate(_, _ *vzapi.Verrazzano) error {
 	return nil
 }
+
+// ValidateKubernetesSettings checks the kubernetes section of the Jaeger Operator, the manifest has no pod disruption
+// budget
+func (c jaegerOperatorComponent) ValidateKubernetesSettings(vz *vzapi.Verrazzano) error {
+	if vz.Spec.Components.JaegerOperator == nil {
+		return nil
+	}
+	return vzconfig.ValidateKubernetesSettings(ComponentJSONName, vz.Spec.Components.JaegerOperator.Kubernetes,
+		vzconfig.KubernetesReplicas, vzconfig.KubernetesAffinity, vzconfig.KubernetesTolerations,
+		vzconfig.KubernetesNodeSelector, vzconfig.KubernetesPriorityClassName, vzconfig.KubernetesResources)
+}
+
+// GetNetworkPolicies returns the NetworkPolicies of the Jaeger Operator, and of the Jaeger instances it manages, which
+// receive the traces of the applications and store them in OpenSearch
+func (c jaegerOperatorComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-jaeger-operator", map[string]string{"name": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				// The webhook is called by the API server
+				netpolicy.IngressFrom(nil, 9443),
+				netpolicy.PrometheusIngress(8443),
+			},
+			nil),
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-jaeger", map[string]string{"app.kubernetes.io/part-of": "jaeger"},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.ClusterPeers(), 4317, 4318, 9411, 14250, 14267, 14268),
+				netpolicy.IngressFrom(common.AuthProxyPeers(), 16686),
+				netpolicy.PrometheusIngress(14269),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(nil, common.OpenSearchHTTPPort, 443),
+			}),
+	}, nil
+}
@@ -59,7 +59,7 @@ func TestBuildInstallArgs(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			config.SetDefaultBomFilePath(tt.bomFile)
-			args, err := buildInstallArgs()
+			args, err := buildInstallArgs(&vzapi.Verrazzano{})
 			if tt.hasError {
 				assert.Error(t, err)
 			} else {
@@ -74,6 +74,30 @@ func TestBuildInstallArgs(t *testing.T) {
 	}
 }
 
+// TestSetKubernetesArgs tests the setKubernetesArgs function
+// GIVEN a kubernetes section of the Jaeger Operator
+// WHEN setKubernetesArgs is called
+// THEN the template args have the settings as JSON, and defaults for the settings that are not set
+func TestSetKubernetesArgs(t *testing.T) {
+	args := map[string]interface{}{}
+	assert.NoError(t, setKubernetesArgs(args, nil))
+	assert.Equal(t, uint32(1), args["replicas"])
+	for _, name := range []string{"affinity", "tolerations", "nodeSelector", "priorityClassName", "resources"} {
+		assert.Equal(t, "", args[name])
+	}
+
+	args = map[string]interface{}{}
+	assert.NoError(t, setKubernetesArgs(args, &vzapi.CommonKubernetesSpec{
+		Replicas:          2,
+		NodeSelector:      map[string]string{"disk": "ssd"},
+		PriorityClassName: "high",
+	}))
+	assert.Equal(t, uint32(2), args["replicas"])
+	assert.Equal(t, `{"disk":"ssd"}`, args["nodeSelector"])
+	assert.Equal(t, "high", args["priorityClassName"])
+	assert.Equal(t, "", args["affinity"])
+}
+
 // TestIsJaegerOperatorReady tests the isJaegerOperatorReady function for the Jaeger Operator
 func TestIsJaegerOperatorReady(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,26 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"fmt"
+
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/mysql"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// mysqlSecretName is the secret with the root password of the MySQL server of Keycloak
+const mysqlSecretName = "mysql"
+
+// GetBackupSpec returns the backup of the Keycloak realms, users and clients, which is a dump of the databases of the
+// MySQL server of Keycloak
+func (c KeycloakComponent) GetBackupSpec() spi.BackupSpec {
+	return spi.BackupSpec{
+		Order:            1,
+		Method:           spi.BackupMethodMySQL,
+		Endpoint:         fmt.Sprintf("%s.%s.svc.cluster.local", mysql.ComponentName, mysql.ComponentNamespace),
+		CredentialSecret: types.NamespacedName{Namespace: mysql.ComponentNamespace, Name: mysqlSecretName},
+	}
+}
@@ -7,30 +7,25 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"os/exec"
-	"path/filepath"
-	"strings"
+	"reflect"
 	"text/template"
 
 	"github.com/verrazzano/verrazzano/pkg/bom"
 	"github.com/verrazzano/verrazzano/pkg/k8sutil"
-	vzos "github.com/verrazzano/verrazzano/pkg/os"
 	vzpassword "github.com/verrazzano/verrazzano/pkg/security/password"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/status"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/keycloak"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	networkv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/kubernetes"
-	restclient "k8s.io/client-go/rest"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -51,6 +46,13 @@ const (
 	vzInternalPromUser      = "verrazzano-prom-internal"
 	vzInternalEsUser        = "verrazzano-es-internal"
 	keycloakPodName         = "keycloak-0"
+	keycloakHTTPPort        = 8080
+	keycloakAdminUser       = "keycloakadmin"
+	keycloakAdminSecret     = "keycloak-http"
+	pkceClientID            = "verrazzano-pkce"
+	pgClientID              = "verrazzano-pg"
+	passwordPolicy          = "length(8) and notUsername"
+	loginTheme              = "oracle"
 )
 
 // Define the keycloak Key:Value pair for init container.
@@ -184,7 +186,6 @@ const pgClient = `
       "rootUrl" : "",
       "adminUrl" : "",
       "surrogateAuthRequired" : false,
-      "directAccessGrantsEnabled" : "true",
       "clientAuthenticatorType" : "client-secret",
       "secret" : "de05ccdc-67df-47f3-81f6-37e61d195aba",
       "redirectUris" : [ ],
@@ -274,81 +275,18 @@ const pgClient = `
 }
 `
 
-// KeycloakClients represents an array of clients currently configured in Keycloak
-type KeycloakClients []struct {
-	ID       string `json:"id"`
-	ClientID string `json:"clientId"`
-}
-
-// SubGroup represents the subgroups that Keycloak groups may contain
-type SubGroup struct {
-	ID        string        `json:"id"`
-	Name      string        `json:"name"`
-	Path      string        `json:"path"`
-	SubGroups []interface{} `json:"subGroups"`
-}
-
-// KeycloakGroups is an array of groups configured in Keycloak
-type KeycloakGroups []struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Path      string `json:"path"`
-	SubGroups []SubGroup
-}
-
-// KeycloakRoles is an array of roles configured in Keycloak
-type KeycloakRoles []struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Composite   bool   `json:"composite"`
-	ClientRole  bool   `json:"clientRole"`
-	ContainerID string `json:"containerId"`
-}
-
-// KeycloakUsers is an array of users configured in Keycloak
-type KeycloakUsers []struct {
-	ID                         string        `json:"id"`
-	CreatedTimestamp           int64         `json:"createdTimestamp"`
-	Username                   string        `json:"username"`
-	Enabled                    bool          `json:"enabled"`
-	Totp                       bool          `json:"totp"`
-	EmailVerified              bool          `json:"emailVerified"`
-	DisableableCredentialTypes []interface{} `json:"disableableCredentialTypes"`
-	RequiredActions            []interface{} `json:"requiredActions"`
-	NotBefore                  int           `json:"notBefore"`
-	Access                     struct {
-		ManageGroupMembership bool `json:"manageGroupMembership"`
-		View                  bool `json:"view"`
-		MapRoles              bool `json:"mapRoles"`
-		Impersonate           bool `json:"impersonate"`
-		Manage                bool `json:"manage"`
-	} `json:"access"`
-}
-
 type templateData struct {
 	DNSSubDomain string
 }
 
-// Unit testing support
-type bashFuncSig func(inArgs ...string) (string, string, error)
-
-var bashFunc bashFuncSig = vzos.RunBash
-
-func setBashFunc(f bashFuncSig) {
-	bashFunc = f
-}
-
-var execCommand = exec.Command
+// newAdminClientFunc is needed for unit test override
+var newAdminClientFunc = newAdminClient
 
 // imageData needed for template rendering
 type imageData struct {
 	Image string
 }
 
-// maskPw will mask passwords in strings with '******'
-var maskPw = vzpassword.MaskFunction("password ")
-
 // AppendKeycloakOverrides appends the Keycloak theme for the Key keycloak.extraInitContainers.
 // A go template is used to replace the image in the init container spec.
 func AppendKeycloakOverrides(compContext spi.ComponentContext, _ string, _ string, _ string, kvs []bom.KeyValue) ([]bom.KeyValue, error) {
@@ -455,49 +393,48 @@ func updateKeycloakIngress(ctx spi.ComponentContext) error {
 	return err
 }
 
-// updateKeycloakUris calls a bash script to update the Keycloak rewrite and weborigin uris
-func updateKeycloakUris(ctx spi.ComponentContext) error {
-
+// newAdminClient forwards a local port to the Keycloak pod and returns a client of the Keycloak Admin REST API that
+// connects through it, with a function that stops the port forwarding.  The operator is outside the Istio mesh,
+// so it can't connect to the Keycloak service, which requires mutual TLS.
+func newAdminClient(ctx spi.ComponentContext) (*keycloak.AdminClient, func(), error) {
+	password, err := getSecretPassword(ctx, ComponentNamespace, keycloakAdminSecret)
+	if err != nil {
+		return nil, nil, err
+	}
 	cfg, cli, err := k8sutil.ClientConfig()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-
-	err = loginKeycloak(ctx, cfg, cli)
+	localPort, stop, err := k8sutil.PortForwardPod(cli, cfg, keycloakPod(), keycloakHTTPPort)
 	if err != nil {
-		return err
+		ctx.Log().Errorf("Component Keycloak failed forwarding a port to pod %s: %v", keycloakPodName, err)
+		return nil, nil, err
 	}
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d/auth", localPort)
+	return keycloak.NewAdminClient(baseURL, keycloakAdminUser, password, nil), stop, nil
+}
 
-	// Get the Client ID JSON array
-	keycloakClients, err := getKeycloakClients(ctx)
+// updateKeycloakUris updates the redirect URIs and web origins of the verrazzano-pkce client, when they don't match
+// the DNS domain of the Verrazzano installation
+func updateKeycloakUris(ctx spi.ComponentContext, kc *keycloak.AdminClient, pkceClient *keycloak.Client) error {
+	desired, err := getPkceClient(ctx)
 	if err != nil {
 		return err
 	}
-
-	// Get the client ID for verrazzano-pkce
-	id := getClientID(keycloakClients, "verrazzano-pkce")
-	if id == "" {
-		err := errors.New("Component Keycloak failed retrieving ID for Keycloak user, zero length")
-		ctx.Log().Error(err)
-		return err
+	if reflect.DeepEqual(desired.RedirectURIs, pkceClient.RedirectURIs) && reflect.DeepEqual(desired.WebOrigins, pkceClient.WebOrigins) {
+		return nil
 	}
-	ctx.Log().Debug("Keycloak Post Upgrade: Successfully retrieved clientID")
-
-	// Get DNS Domain Configuration
-	dnsSubDomain, err := vzconfig.BuildDNSDomain(ctx.Client(), ctx.EffectiveCR())
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed retrieving DNS sub domain: %v", err)
-		return err
+	update := keycloak.Client{
+		ID:           pkceClient.ID,
+		ClientID:     pkceClient.ClientID,
+		RedirectURIs: desired.RedirectURIs,
+		WebOrigins:   desired.WebOrigins,
 	}
-	ctx.Log().Debugf("Keycloak Post Upgrade: DNSDomain returned %s", dnsSubDomain)
-
-	// Call the Script and Update the URIs
-	scriptName := filepath.Join(config.GetInstallDir(), "update-kiali-redirect-uris.sh")
-	if _, stderr, err := bashFunc(scriptName, id, dnsSubDomain); err != nil {
-		ctx.Log().Errorf("Component Keycloak failed updating KeyCloak URIs %v: %s", err, stderr)
+	if err := kc.UpdateClient(vzSysRealm, update); err != nil {
+		ctx.Log().Errorf("Component Keycloak failed updating the Keycloak URIs: %v", err)
 		return err
 	}
-	ctx.Log().Debug("Component Keycloak successfully updated Keycloak URIs")
+	ctx.Log().Once("Component Keycloak successfully updated Keycloak URIs")
 	return nil
 }
 
@@ -515,152 +452,117 @@ func configureKeycloakRealms(ctx spi.ComponentContext) error {
 		return fmt.Errorf("Waiting for pod %s to be ready", pod.Name)
 	}
 
-	cfg, cli, err := k8sutil.ClientConfig()
+	kc, stop, err := newAdminClientFunc(ctx)
 	if err != nil {
 		return err
 	}
+	defer stop()
 
-	// If ephemeral storage is configured, additional steps may be required to
-	// rebuild the configuration lost due to MySQL pod getting restarted.
-	if (ctx.EffectiveCR().Spec.Components.Keycloak != nil) && (ctx.EffectiveCR().Spec.Components.Keycloak.MySQL.VolumeSource == nil) {
+	// Login to Keycloak
+	err = kc.Login()
+	if err != nil {
+		ctx.Log().Errorf("Component Keycloak failed logging into Keycloak: %v", err)
+		// If ephemeral storage is configured, additional steps may be required to
+		// rebuild the configuration lost due to MySQL pod getting restarted.
 		// When the MySQL pod restarts and using ephemeral storage, the
 		// login to Keycloak will fail.  Need to recycle the Keycloak pod
 		// to resolve the condition.
-		err = loginKeycloak(ctx, cfg, cli)
-		if err != nil {
+		if (ctx.EffectiveCR().Spec.Components.Keycloak != nil) && (ctx.EffectiveCR().Spec.Components.Keycloak.MySQL.VolumeSource == nil) {
 			err2 := ctx.Client().Delete(context.TODO(), pod)
 			if err2 != nil {
 				ctx.Log().Errorf("Component Keycloak failed to recycle pod %s: %v", pod.Name, err2)
 			}
-			return err
 		}
-	}
-
-	// Login to Keycloak
-	err = loginKeycloak(ctx, cfg, cli)
-	if err != nil {
 		return err
 	}
+	ctx.Log().Once("Component Keycloak successfully logged into Keycloak")
 
 	// Create VerrazzanoSystem Realm
-	err = createVerrazzanoSystemRealm(ctx, cfg, cli)
+	err = createVerrazzanoSystemRealm(ctx, kc)
 	if err != nil {
 		return err
 	}
 
 	// Create Verrazzano Users Group
-	userGroupID, err := createVerrazzanoUsersGroup(ctx)
+	userGroupID, err := createVerrazzanoGroup(ctx, kc, vzUsersGroup, "", nil)
 	if err != nil {
 		return err
 	}
-	if userGroupID == "" {
-		err := errors.New("Component Keycloak failed; user Group ID from Keycloak is zero length")
-		ctx.Log().Error(err)
-		return err
-	}
 
 	// Create Verrazzano Admin Group
-	adminGroupID, err := createVerrazzanoAdminGroup(ctx, userGroupID)
+	_, err = createVerrazzanoGroup(ctx, kc, vzAdminGroup, userGroupID, nil)
 	if err != nil {
 		return err
 	}
-	if adminGroupID == "" {
-		err := errors.New("Component Keycloak failed; admin group ID from Keycloak is zero length")
-		ctx.Log().Error(err)
-		return err
-	}
 
 	// Create Verrazzano Project Monitors Group
-	monitorGroupID, err := createVerrazzanoMonitorsGroup(ctx, userGroupID)
+	_, err = createVerrazzanoGroup(ctx, kc, vzMonitorGroup, userGroupID, nil)
 	if err != nil {
 		return err
 	}
-	if monitorGroupID == "" {
-		err = errors.New("Component Keycloak failed; monitor group ID from Keycloak is zero length")
-		ctx.Log().Error(err)
-		return err
-	}
 
 	// Create Verrazzano System Group
-	err = createVerrazzanoSystemGroup(ctx, cfg, cli, userGroupID)
+	_, err = createVerrazzanoGroup(ctx, kc, vzSystemGroup, userGroupID, nil)
 	if err != nil {
 		return err
 	}
 
 	// Create Verrazzano API Access Role
-	err = createVerrazzanoRole(ctx, cfg, cli, vzAPIAccessRole)
+	err = createVerrazzanoRole(ctx, kc, vzAPIAccessRole)
 	if err != nil {
 		return err
 	}
 
 	// Granting Roles to Groups
-	err = grantRolesToGroups(ctx, cfg, cli, userGroupID, adminGroupID, monitorGroupID)
+	err = grantRolesToGroups(ctx, kc, userGroupID)
 	if err != nil {
 		return err
 	}
 
 	// Creating Verrazzano User
-	err = createUser(ctx, cfg, cli, vzUserName, "verrazzano", vzAdminGroup)
+	err = createUser(ctx, kc, vzUserName, "verrazzano", vzAdminGroup)
 	if err != nil {
 		return err
 	}
 
 	// Creating Verrazzano Internal Prometheus User
-	err = createUser(ctx, cfg, cli, vzInternalPromUser, "verrazzano-prom-internal", vzSystemGroup)
+	err = createUser(ctx, kc, vzInternalPromUser, "verrazzano-prom-internal", vzSystemGroup)
 	if err != nil {
 		return err
 	}
 
 	// Creating Verrazzano Internal ES User
-	err = createUser(ctx, cfg, cli, vzInternalEsUser, "verrazzano-es-internal", vzSystemGroup)
+	err = createUser(ctx, kc, vzInternalEsUser, "verrazzano-es-internal", vzSystemGroup)
 	if err != nil {
 		return err
 	}
 
 	// Create verrazzano-pkce client
-	err = createOrUpdateVerrazzanoPkceClient(ctx, cfg, cli)
+	err = createOrUpdateVerrazzanoPkceClient(ctx, kc)
 	if err != nil {
 		return err
 	}
 
 	// Creating verrazzano-pg client
-	err = createVerrazzanoPgClient(ctx, cfg, cli)
+	err = createVerrazzanoPgClient(ctx, kc)
 	if err != nil {
 		return err
 	}
 
-	// Setting password policy for master
-	err = setPasswordPolicyForRealm(ctx, cfg, cli, "master", "passwordPolicy=length(8) and notUsername")
+	// Applying the realm configuration of the Verrazzano CR
+	err = configureRealm(ctx, kc)
 	if err != nil {
 		return err
 	}
 
-	// Setting password policy for Verrazzano realm
-	err = setPasswordPolicyForRealm(ctx, cfg, cli, "verrazzano-system", "passwordPolicy=length(8) and notUsername")
+	// Setting password policy, login theme and enabling the realms
+	err = updateRealm(ctx, kc, keycloak.Realm{Realm: keycloak.MasterRealm, PasswordPolicy: passwordPolicy, LoginTheme: loginTheme})
 	if err != nil {
 		return err
 	}
-
-	// Configuring login theme for master
-	err = configureLoginThemeForRealm(ctx, cfg, cli, "master", "oracle")
-	if err != nil {
-		return err
-	}
-
-	// Configuring login theme for verrazzano-system
-	err = configureLoginThemeForRealm(ctx, cfg, cli, "verrazzano-system", "oracle")
-	if err != nil {
-		return err
-	}
-
-	// Enabling vzSysRealm realm
-	err = enableVerrazzanoSystemRealm(ctx, cfg, cli)
-	if err != nil {
-		return err
-	}
-
-	// Removing login config file
-	err = removeLoginConfigFile(ctx, cfg, cli)
+	enabled := true
+	realmPolicy, realmTheme := getRealmPolicyAndTheme(ctx.EffectiveCR())
+	err = updateRealm(ctx, kc, keycloak.Realm{Realm: vzSysRealm, PasswordPolicy: realmPolicy, LoginTheme: realmTheme, Enabled: &enabled})
 	if err != nil {
 		return err
 	}
@@ -669,49 +571,6 @@ func configureKeycloakRealms(ctx spi.ComponentContext) error {
 	return nil
 }
 
-// loginKeycloak logs into Keycloak so kcadm API calls can be made
-func loginKeycloak(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface) error {
-	// Get the Keycloak admin password
-	secret := &corev1.Secret{}
-	err := ctx.Client().Get(context.TODO(), client.ObjectKey{
-		Namespace: "keycloak",
-		Name:      "keycloak-http",
-	}, secret)
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed retrieving Keycloak password: %s", err)
-		return err
-	}
-	pw := secret.Data["password"]
-	keycloakpw := string(pw)
-	if keycloakpw == "" {
-		err = errors.New("Component Keycloak failed; Keycloak password is an empty string")
-		ctx.Log().Error(err)
-		return err
-	}
-	ctx.Log().Debug("loginKeycloak: Successfully retrieved Keycloak password")
-
-	// Login to Keycloak
-	kcPod := keycloakPod()
-	loginCmd := "/opt/jboss/keycloak/bin/kcadm.sh config credentials --server http://localhost:8080/auth --realm master --user keycloakadmin --password " + keycloakpw
-	ctx.Log().Debugf("loginKeycloak: Login Cmd = %s", maskPw(loginCmd))
-	stdOut, stdErr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(loginCmd))
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed logging into Keycloak: stdout = %s: stderr = %s", stdOut, stdErr)
-		return fmt.Errorf("error: %s", maskPw(err.Error()))
-	}
-	ctx.Log().Once("Component Keycloak successfully logged into Keycloak")
-
-	return nil
-}
-
-func bashCMD(command string) []string {
-	return []string{
-		"bash",
-		"-c",
-		command,
-	}
-}
-
 func keycloakPod() *v1.Pod {
 	return &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -757,6 +616,11 @@ func createAuthSecret(ctx spi.ComponentContext, namespace string, secretname str
 
 // getSecretPassword retrieves the password associated with a secret
 func getSecretPassword(ctx spi.ComponentContext, namespace string, secretname string) (string, error) {
+	return getSecretField(ctx, namespace, secretname, "password")
+}
+
+// getSecretField retrieves a field of a secret, which must not be empty
+func getSecretField(ctx spi.ComponentContext, namespace string, secretname string, field string) (string, error) {
 	secret := &corev1.Secret{}
 	err := ctx.Client().Get(context.TODO(), client.ObjectKey{
 		Namespace: namespace,
@@ -766,14 +630,13 @@ func getSecretPassword(ctx spi.ComponentContext, namespace string, secretname st
 		ctx.Log().Errorf("Component Keycloak failed retrieving secret %s/%s: %v", namespace, secretname, err)
 		return "", err
 	}
-	pw := secret.Data["password"]
-	stringpw := string(pw)
-	if stringpw == "" {
-		err := fmt.Errorf("Component Keycloak failed, password field empty in secret %s/%s", namespace, secretname)
+	value := string(secret.Data[field])
+	if value == "" {
+		err := fmt.Errorf("Component Keycloak failed, %s field empty in secret %s/%s", field, namespace, secretname)
 		ctx.Log().Error(err)
 		return "", err
 	}
-	return stringpw, nil
+	return value, nil
 }
 
 // getDNSDomain returns the DNS Domain
@@ -786,491 +649,244 @@ func getDNSDomain(c client.Client, vz *vzapi.Verrazzano) (string, error) {
 	return dnsDomain, nil
 }
 
-func createVerrazzanoSystemRealm(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface) error {
-	kcPod := keycloakPod()
-	realm := "realm=" + vzSysRealm
-	checkRealmExistsCmd := "/opt/jboss/keycloak/bin/kcadm.sh get realms/" + vzSysRealm
-	ctx.Log().Debugf("createVerrazzanoSystemRealm: Check Verrazzano System Realm Exists Cmd = %s", checkRealmExistsCmd)
-	_, _, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(checkRealmExistsCmd))
+// createVerrazzanoSystemRealm creates the Verrazzano system realm, disabled until it is configured, if it doesn't exist
+func createVerrazzanoSystemRealm(ctx spi.ComponentContext, kc *keycloak.AdminClient) error {
+	realm, err := kc.GetRealm(vzSysRealm)
 	if err != nil {
-		ctx.Log().Debug("createVerrazzanoSystemRealm: Verrazzano System Realm doesn't exist: Creating it")
-		createRealmCmd := "/opt/jboss/keycloak/bin/kcadm.sh create realms -s " + realm + " -s enabled=false"
-		ctx.Log().Debugf("createVerrazzanoSystemRealm: Create Verrazzano System Realm Cmd = %s", createRealmCmd)
-		stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(createRealmCmd))
-		if err != nil {
-			ctx.Log().Errorf("Component Keycloak failed creating Verrazzano System Realm: stdout = %s, stderr = %s", stdout, stderr)
-			return err
-		}
-		ctx.Log().Once("Component Keycloak successfully created the Verrazzano system realm")
+		ctx.Log().Errorf("Component Keycloak failed retrieving the Verrazzano System Realm: %v", err)
+		return err
+	}
+	if realm != nil {
+		return nil
 	}
+	ctx.Log().Debug("createVerrazzanoSystemRealm: Verrazzano System Realm doesn't exist: Creating it")
+	enabled := false
+	if err := kc.CreateRealm(keycloak.Realm{Realm: vzSysRealm, Enabled: &enabled}); err != nil && !keycloak.IsConflict(err) {
+		ctx.Log().Errorf("Component Keycloak failed creating Verrazzano System Realm: %v", err)
+		return err
+	}
+	ctx.Log().Once("Component Keycloak successfully created the Verrazzano system realm")
 	return nil
 }
 
-func createVerrazzanoUsersGroup(ctx spi.ComponentContext) (string, error) {
-	keycloakGroups, err := getKeycloakGroups(ctx)
-	if err == nil && groupExists(keycloakGroups, vzUsersGroup) {
-		// Group already exists
-		return getGroupID(keycloakGroups, vzUsersGroup), nil
-	}
-
-	userGroup := "name=" + vzUsersGroup
-	cmd := execCommand("kubectl", "exec", keycloakPodName, "-n", ComponentNamespace, "-c", "keycloak", "--", "/opt/jboss/keycloak/bin/kcadm.sh", "create", "groups", "-r", vzSysRealm, "-s", userGroup)
-	ctx.Log().Debugf("createVerrazzanoUsersGroup: Create Verrazzano Users Group Cmd = %s", cmd.String())
-	out, err := cmd.CombinedOutput()
+// createVerrazzanoGroup creates a group with the attributes in the Verrazzano system realm, as a subgroup of the parent
+// group unless the parent group ID is empty, if it doesn't exist.  The ID of the group is returned.
+func createVerrazzanoGroup(ctx spi.ComponentContext, kc *keycloak.AdminClient, groupName string, parentID string, attributes map[string][]string) (string, error) {
+	groups, err := kc.GetGroups(vzSysRealm)
 	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed creating Verrazzano Users Group: command output = %s", out)
-		return "", err
-	}
-	ctx.Log().Debugf("createVerrazzanoUsersGroup: Create Verrazzano Users Group Output = %s", out)
-	if len(string(out)) == 0 {
-		err = errors.New("Component Keycloak failed; user group ID from Keycloak is zero length")
-		ctx.Log().Error(err)
+		ctx.Log().Errorf("Component Keycloak failed retrieving Groups: %v", err)
 		return "", err
 	}
-	arr := strings.Split(string(out), "'")
-	if len(arr) != 3 {
-		return "", fmt.Errorf("Component Keycloak failed parsing output returned from Users Group create stdout returned = %s", out)
+	if group := keycloak.FindGroup(groups, groupName); group != nil {
+		return group.ID, nil
 	}
-	ctx.Log().Debugf("createVerrazzanoUsersGroup: User Group ID = %s", arr[1])
-	ctx.Log().Once("Component Keycloak successfully created the Verrazzano user group")
-	return arr[1], nil
-}
 
-func createVerrazzanoAdminGroup(ctx spi.ComponentContext, userGroupID string) (string, error) {
-	keycloakGroups, err := getKeycloakGroups(ctx)
-	if err == nil && groupExists(keycloakGroups, vzAdminGroup) {
-		// Group already exists
-		return getGroupID(keycloakGroups, vzAdminGroup), nil
-	}
-	adminGroup := "groups/" + userGroupID + "/children"
-	adminGroupName := "name=" + vzAdminGroup
-	cmd := execCommand("kubectl", "exec", keycloakPodName, "-n", ComponentNamespace, "-c", "keycloak", "--", "/opt/jboss/keycloak/bin/kcadm.sh", "create", adminGroup, "-r", vzSysRealm, "-s", adminGroupName)
-	ctx.Log().Debugf("createVerrazzanoAdminGroup: Create Verrazzano Admin Group Cmd = %s", cmd.String())
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed creating Verrazzano Admin Group: command output = %s", out)
-		return "", err
+	var id string
+	if len(parentID) == 0 {
+		id, err = kc.CreateGroup(vzSysRealm, keycloak.Group{Name: groupName, Attributes: attributes})
+	} else {
+		id, err = kc.CreateChildGroup(vzSysRealm, parentID, keycloak.Group{Name: groupName, Attributes: attributes})
 	}
-	ctx.Log().Debugf("createVerrazzanoAdminGroup: Create Verrazzano Admin Group Output = %s", out)
-	if len(string(out)) == 0 {
-		err = errors.New("Component Keycloak failed; admin group ID from Keycloak is zero length")
-		ctx.Log().Error(err)
+	if err != nil {
+		ctx.Log().Errorf("Component Keycloak failed creating group %s: %v", groupName, err)
 		return "", err
 	}
-	arr := strings.Split(string(out), "'")
-	if len(arr) != 3 {
-		return "", fmt.Errorf("Component Keycloak failed parsing output returned from Admin Group create stdout returned = %s", out)
-	}
-	ctx.Log().Debugf("createVerrazzanoAdminGroup: Admin Group ID = %s", arr[1])
-	ctx.Log().Once("Component Keycloak successfully created the Verrazzano admin group")
-	return arr[1], nil
+	ctx.Log().Debugf("createVerrazzanoGroup: Group %s ID = %s", groupName, id)
+	ctx.Log().Oncef("Component Keycloak successfully created the group %s", groupName)
+	return id, nil
 }
 
-func createVerrazzanoMonitorsGroup(ctx spi.ComponentContext, userGroupID string) (string, error) {
-	keycloakGroups, err := getKeycloakGroups(ctx)
-	if err == nil && groupExists(keycloakGroups, vzMonitorGroup) {
-		// Group already exists
-		return getGroupID(keycloakGroups, vzMonitorGroup), nil
-	}
-	monitorGroup := "groups/" + userGroupID + "/children"
-	monitorGroupName := "name=" + vzMonitorGroup
-	cmd := execCommand("kubectl", "exec", keycloakPodName, "-n", ComponentNamespace, "-c", "keycloak", "--", "/opt/jboss/keycloak/bin/kcadm.sh", "create", monitorGroup, "-r", vzSysRealm, "-s", monitorGroupName)
-	ctx.Log().Debugf("createVerrazzanoProjectMonitorsGroup: Create Verrazzano Monitors Group Cmd = %s", cmd.String())
-	out, err := cmd.CombinedOutput()
+// createVerrazzanoRole creates a realm role in the Verrazzano system realm if it doesn't exist
+func createVerrazzanoRole(ctx spi.ComponentContext, kc *keycloak.AdminClient, roleName string) error {
+	role, err := kc.GetRole(vzSysRealm, roleName)
 	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed creating Verrazzano Monitor Group: command output = %s", out)
-		return "", err
+		ctx.Log().Errorf("Component Keycloak failed retrieving role %s: %v", roleName, err)
+		return err
 	}
-	ctx.Log().Debugf("createVerrazzanoProjectMonitorsGroup: Create Verrazzano Project Monitors Group Output = %s", out)
-	if len(string(out)) == 0 {
-		err = errors.New("Component Keycloak failed; monitor group ID from Keycloak is zero length")
-		ctx.Log().Error(err)
-		return "", err
+	if role != nil {
+		return nil
 	}
-	arr := strings.Split(string(out), "'")
-	if len(arr) != 3 {
-		return "", fmt.Errorf("Component Keycloak failed parsing output returned from Monitor Group create stdout returned = %s", out)
+	if err := kc.CreateRole(vzSysRealm, keycloak.Role{Name: roleName}); err != nil && !keycloak.IsConflict(err) {
+		ctx.Log().Errorf("Component Keycloak failed creating role %s: %v", roleName, err)
+		return err
 	}
-	ctx.Log().Debugf("createVerrazzanoProjectMonitorsGroup: Monitor Group ID = %s", arr[1])
-	ctx.Log().Once("Component Keycloak successfully created the Verrazzano monitors group")
-
-	return arr[1], nil
+	ctx.Log().Oncef("Component Keycloak successfully created the role %s", roleName)
+	return nil
 }
 
-func createVerrazzanoSystemGroup(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface, userGroupID string) error {
-
-	keycloakGroups, err := getKeycloakGroups(ctx)
-	if err == nil && groupExists(keycloakGroups, vzSystemGroup) {
-		return nil
-	}
+// grantRolesToGroups grants the Verrazzano API access role to the Verrazzano users group
+func grantRolesToGroups(ctx spi.ComponentContext, kc *keycloak.AdminClient, userGroupID string) error {
+	return grantRealmRoles(ctx, kc, vzUsersGroup, userGroupID, vzGroupRoles[vzUsersGroup])
+}
 
-	kcPod := keycloakPod()
-	systemGroup := "groups/" + userGroupID + "/children"
-	systemGroupName := "name=" + vzSystemGroup
-	createVzSystemGroupCmd := "/opt/jboss/keycloak/bin/kcadm.sh create " + systemGroup + " -r " + vzSysRealm + " -s " + systemGroupName
-	ctx.Log().Debugf("createVerrazzanoSystemGroup: Create Verrazzano System Group Cmd = %s", createVzSystemGroupCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(createVzSystemGroupCmd))
+// grantRealmRoles grants the realm roles, which must exist, to a group of the Verrazzano system realm unless the
+// group has them
+func grantRealmRoles(ctx spi.ComponentContext, kc *keycloak.AdminClient, groupName string, groupID string, roleNames []string) error {
+	granted, err := kc.GetGroupRealmRoles(vzSysRealm, groupID)
 	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed creating Verrazzano System Group: stdout = %s, stderr = %s", stdout, stderr)
+		ctx.Log().Errorf("Component Keycloak failed retrieving the roles of group %s: %v", groupName, err)
 		return err
 	}
-	ctx.Log().Once("Component Keycloak successfully created the Verrazzano system group")
-	return nil
-}
-
-func createVerrazzanoRole(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface, roleName string) error {
-	keycloakRoles, err := getKeycloakRoles(ctx)
-	if err == nil && roleExists(keycloakRoles, roleName) {
+	var roles []keycloak.Role
+	for _, roleName := range roleNames {
+		if hasRole(granted, roleName) || hasRole(roles, roleName) {
+			continue
+		}
+		role, err := kc.GetRole(vzSysRealm, roleName)
+		if err != nil || role == nil {
+			ctx.Log().Errorf("Component Keycloak failed retrieving role %s: %v", roleName, err)
+			return fmt.Errorf("Failed retrieving role %s: %v", roleName, err)
+		}
+		roles = append(roles, *role)
+	}
+	if len(roles) == 0 {
 		return nil
 	}
-	kcPod := keycloakPod()
-	role := "name=" + roleName
-	createRoleCmd := "/opt/jboss/keycloak/bin/kcadm.sh create roles -r " + vzSysRealm + " -s " + role
-	ctx.Log().Debugf("createVerrazzanoRole: Create Verrazzano API Access Role Cmd = %s", createRoleCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(createRoleCmd))
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed creating Verrazzano API Access Role: stdout = %s, stderr = %s", stdout, stderr)
+	if err := kc.AddGroupRealmRoles(vzSysRealm, groupID, roles); err != nil {
+		ctx.Log().Errorf("Component Keycloak failed granting roles to group %s: %v", groupName, err)
 		return err
 	}
-	ctx.Log().Once("Component Keycloak successfully created the Verrazzano API access role")
+	ctx.Log().Oncef("Component Keycloak successfully granted roles %v to group %s", roleNames, groupName)
 	return nil
 }
 
-func grantRolesToGroups(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface, userGroupID string, adminGroupID string, monitorGroupID string) error {
-	// Keycloak API does not fail if Role already exists as of 15.0.3
-	kcPod := keycloakPod()
-	// Granting vz_api_access role to verrazzano users group
-	grantAPIAccessToVzUserGroupCmd := "/opt/jboss/keycloak/bin/kcadm.sh add-roles -r " + vzSysRealm + " --gid " + userGroupID + " --rolename " + vzAPIAccessRole
-	ctx.Log().Debugf("grantRolesToGroups: Grant API Access to VZ Users Cmd = %s", grantAPIAccessToVzUserGroupCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(grantAPIAccessToVzUserGroupCmd))
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed granting api access role to Verrazzano users group: stdout = %s, stderr = %s", stdout, stderr)
-		return err
+// hasRole returns true if there is a role with the name
+func hasRole(roles []keycloak.Role, roleName string) bool {
+	for _, role := range roles {
+		if role.Name == roleName {
+			return true
+		}
 	}
-	ctx.Log().Once("Component Keycloak successfully granted the access role to the Verrazzano user group")
-
-	return nil
+	return false
 }
 
-func createUser(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface, userName string, secretName string, groupName string) error {
-	keycloakUsers, err := getKeycloakUsers(ctx)
-	if err == nil && userExists(keycloakUsers, userName) {
-		return nil
-	}
-	kcPod := keycloakPod()
-	vzUser := "username=" + userName
-	vzUserGroup := "groups[0]=/" + vzUsersGroup + "/" + groupName
-	createVzUserCmd := "/opt/jboss/keycloak/bin/kcadm.sh create users -r " + vzSysRealm + " -s " + vzUser + " -s " + vzUserGroup + " -s enabled=true"
-	ctx.Log().Debugf("createUser: Create Verrazzano User Cmd = %s", createVzUserCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(createVzUserCmd))
+// createUser creates a user in a subgroup of the Verrazzano users group, with the password in the secret, if the
+// user doesn't exist
+func createUser(ctx spi.ComponentContext, kc *keycloak.AdminClient, userName string, secretName string, groupName string) error {
+	user, err := kc.FindUser(vzSysRealm, userName)
 	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed creating Verrazzano user: stdout = %s, stderr = %s", stdout, stderr)
+		ctx.Log().Errorf("Component Keycloak failed retrieving user %s: %v", userName, err)
 		return err
 	}
-	ctx.Log().Debugf("createUser: Successfully Created VZ User %s", userName)
+	if user != nil {
+		return nil
+	}
 
-	vzpw, err := getSecretPassword(ctx, "verrazzano-system", secretName)
+	vzpw, err := getSecretPassword(ctx, constants.VerrazzanoSystemNamespace, secretName)
 	if err != nil {
 		return err
 	}
-	setVZUserPwCmd := "/opt/jboss/keycloak/bin/kcadm.sh set-password -r " + vzSysRealm + " --username " + userName + " --new-password " + vzpw
-	ctx.Log().Debugf("createUser: Set Verrazzano User PW Cmd = %s", maskPw(setVZUserPwCmd))
-	stdout, stderr, err = k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(setVZUserPwCmd))
+	enabled := true
+	userID, err := kc.CreateUser(vzSysRealm, keycloak.User{
+		Username: userName,
+		Enabled:  &enabled,
+		Groups:   []string{"/" + vzUsersGroup + "/" + groupName},
+	})
 	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed setting Verrazzano user password: stdout = %s, stderr = %s", stdout, stderr)
-		return fmt.Errorf("error: %s", maskPw(err.Error()))
+		ctx.Log().Errorf("Component Keycloak failed creating Verrazzano user %s: %v", userName, err)
+		return err
 	}
-	ctx.Log().Debugf("createUser: Created VZ User %s PW", userName)
-	ctx.Log().Oncef("Component Keycloak successfully created user %s", userName)
-
-	return nil
-}
-
-func createOrUpdateVerrazzanoPkceClient(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface) error {
-	data := templateData{}
+	ctx.Log().Debugf("createUser: Successfully Created VZ User %s", userName)
 
-	keycloakClients, err := getKeycloakClients(ctx)
-	if err != nil {
+	if err := kc.SetPassword(vzSysRealm, userID, vzpw); err != nil {
+		ctx.Log().Errorf("Component Keycloak failed setting the password of Verrazzano user %s: %v", userName, err)
 		return err
 	}
-	if clientExists(keycloakClients, "verrazzano-pkce") {
-		if err := updateKeycloakUris(ctx); err != nil {
-			return err
-		}
-		return nil
-	}
+	ctx.Log().Oncef("Component Keycloak successfully created user %s", userName)
+	return nil
+}
 
-	kcPod := keycloakPod()
+// getPkceClient returns the verrazzano-pkce client for the DNS domain of the Verrazzano installation
+func getPkceClient(ctx spi.ComponentContext) (*keycloak.Client, error) {
 	// Get DNS Domain Configuration
 	dnsSubDomain, err := getDNSDomain(ctx.Client(), ctx.EffectiveCR())
 	if err != nil {
 		ctx.Log().Errorf("Component Keycloak failed retrieving DNS sub domain: %v", err)
-		return err
+		return nil, err
 	}
-	ctx.Log().Debugf("createOrUpdateVerrazzanoPkceClient: DNSDomain returned %s", dnsSubDomain)
-
-	data.DNSSubDomain = dnsSubDomain
+	ctx.Log().Debugf("getPkceClient: DNSDomain returned %s", dnsSubDomain)
 
 	// use template to get populate template with data
 	var b bytes.Buffer
 	t, err := template.New("verrazzanoPkceClient").Parse(pkceTmpl)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = t.Execute(&b, &data)
+	err = t.Execute(&b, &templateData{DNSSubDomain: dnsSubDomain})
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	// Create verrazzano-pkce client
-	vzPkceCreateCmd := "/opt/jboss/keycloak/bin/kcadm.sh create clients -r " + vzSysRealm + " -f - <<\\END" +
-		b.String() +
-		"END"
-
-	ctx.Log().Debugf("createOrUpdateVerrazzanoPkceClient: Create verrazzano-pkce client Cmd = %s", vzPkceCreateCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(vzPkceCreateCmd))
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed creating verrazzano-pkce client: stdout = %s, stderr = %s", stdout, stderr)
-		return err
+	pkceClient := &keycloak.Client{}
+	if err := json.Unmarshal(b.Bytes(), pkceClient); err != nil {
+		return nil, err
 	}
-	ctx.Log().Debug("createOrUpdateVerrazzanoPkceClient: Created verrazzano-pkce client")
-	return nil
+	return pkceClient, nil
 }
 
-func createVerrazzanoPgClient(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface) error {
-	keycloakClients, err := getKeycloakClients(ctx)
-	if err == nil && clientExists(keycloakClients, "verrazzano-pg") {
-		return nil
-	}
-
-	kcPod := keycloakPod()
-	vzPgCreateCmd := "/opt/jboss/keycloak/bin/kcadm.sh create clients -r " + vzSysRealm + " -f - <<\\END" +
-		pgClient +
-		"END"
-	ctx.Log().Debugf("createVerrazzanoPgClient: Create verrazzano-pg client Cmd = %s", vzPgCreateCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(vzPgCreateCmd))
+// createOrUpdateVerrazzanoPkceClient creates the verrazzano-pkce client, or updates its URIs if it exists
+func createOrUpdateVerrazzanoPkceClient(ctx spi.ComponentContext, kc *keycloak.AdminClient) error {
+	existing, err := kc.FindClient(vzSysRealm, pkceClientID)
 	if err != nil {
-		ctx.Log().Errorf("createVerrazzanoPgClient: Error creating verrazzano-pg client: stdout = %s, stderr = %s", stdout, stderr)
+		ctx.Log().Errorf("Component Keycloak failed retrieving clients: %v", err)
 		return err
 	}
-	ctx.Log().Debug("createVerrazzanoPgClient: Created verrazzano-pg client")
-	return nil
-}
-
-func setPasswordPolicyForRealm(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface, realmName string, policy string) error {
-	kcPod := keycloakPod()
-	setPolicyCmd := "/opt/jboss/keycloak/bin/kcadm.sh update realms/" + realmName + " -s \"" + policy + "\""
-	ctx.Log().Debugf("setPasswordPolicyForRealm: Setting password policy for realm %s Cmd = %s", realmName, setPolicyCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(setPolicyCmd))
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed setting password policy for master: stdout = %s, stderr = %s", stdout, stderr)
-		return err
+	if existing != nil {
+		return updateKeycloakUris(ctx, kc, existing)
 	}
-	ctx.Log().Debugf("setPasswordPolicyForRealm: Set password policy for realm %s", realmName)
-	ctx.Log().Oncef("Component Keycloak successfully set the password policy for realm %s", realmName)
-	return nil
-}
 
-func configureLoginThemeForRealm(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface, realmName string, loginTheme string) error {
-	kcPod := keycloakPod()
-	setLoginThemeCmd := "/opt/jboss/keycloak/bin/kcadm.sh update realms/" + realmName + " -s loginTheme=" + loginTheme
-	ctx.Log().Debugf("configureLoginThemeForRealm: Configuring login theme Cmd = %s", setLoginThemeCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(setLoginThemeCmd))
+	pkceClient, err := getPkceClient(ctx)
 	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed configuring login theme for master: stdout = %s, stderr = %s", stdout, stderr)
 		return err
 	}
-	ctx.Log().Debug("configureLoginThemeForRealm: Configured login theme for master Cmd")
-	ctx.Log().Oncef("Component Keycloak successfully set the login theme for realm %s", realmName)
-	return nil
-}
-
-func enableVerrazzanoSystemRealm(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface) error {
-	kcPod := keycloakPod()
-	setVzEnableRealmCmd := "/opt/jboss/keycloak/bin/kcadm.sh update realms/" + vzSysRealm + " -s enabled=true"
-	ctx.Log().Debugf("enableVerrazzanoSystemRealm: Enabling vzSysRealm realm Cmd = %s", setVzEnableRealmCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(setVzEnableRealmCmd))
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed enabling vzSysRealm realm: stdout = %s, stderr = %s", stdout, stderr)
+	if _, err := kc.CreateClient(vzSysRealm, *pkceClient); err != nil {
+		ctx.Log().Errorf("Component Keycloak failed creating verrazzano-pkce client: %v", err)
 		return err
 	}
-	ctx.Log().Debug("enableVerrazzanoSystemRealm: Enabled vzSysRealm realm")
-	ctx.Log().Once("Component Keycloak successfully enabled the vzSysRealm realm")
-
+	ctx.Log().Debug("createOrUpdateVerrazzanoPkceClient: Created verrazzano-pkce client")
 	return nil
 }
 
-func removeLoginConfigFile(ctx spi.ComponentContext, cfg *restclient.Config, cli kubernetes.Interface) error {
-	kcPod := keycloakPod()
-	removeLoginConfigFileCmd := "rm /root/.keycloak/kcadm.config"
-	ctx.Log().Debugf("removeLoginConfigFile: Removing login config file Cmd = %s", removeLoginConfigFileCmd)
-	stdout, stderr, err := k8sutil.ExecPod(cli, cfg, kcPod, ComponentName, bashCMD(removeLoginConfigFileCmd))
+// createVerrazzanoPgClient creates the verrazzano-pg client if it doesn't exist
+func createVerrazzanoPgClient(ctx spi.ComponentContext, kc *keycloak.AdminClient) error {
+	existing, err := kc.FindClient(vzSysRealm, pgClientID)
 	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed removing login config file: stdout = %s, stderr = %s", stdout, stderr)
+		ctx.Log().Errorf("Component Keycloak failed retrieving clients: %v", err)
 		return err
 	}
-	ctx.Log().Debug("removeLoginConfigFile: Removed login config file")
-	return nil
-}
-
-// getKeycloakGroups returns a structure of Groups in Realm verrazzano-system
-func getKeycloakGroups(ctx spi.ComponentContext) (KeycloakGroups, error) {
-	var keycloakGroups KeycloakGroups
-	// Get the Client ID JSON array
-	cmd := execCommand("kubectl", "exec", keycloakPodName, "-n", ComponentNamespace, "-c", "keycloak", "--", "/opt/jboss/keycloak/bin/kcadm.sh", "get", "groups", "-r", vzSysRealm)
-	out, err := cmd.Output()
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed retrieving Groups: %s", err)
-		return nil, err
-	}
-	if len(string(out)) == 0 {
-		err = errors.New("Component Keycloak failed; groups JSON from Keycloak is zero length")
-		ctx.Log().Error(err)
-		return nil, err
-	}
-	err = json.Unmarshal(out, &keycloakGroups)
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed ummarshalling groups json: %v", err)
-		return nil, err
-	}
-
-	return keycloakGroups, nil
-}
-
-func groupExists(keycloakGroups KeycloakGroups, groupName string) bool {
-	for _, keycloakGroup := range keycloakGroups {
-		if keycloakGroup.Name == groupName {
-			return true
-		}
-		for _, subGroup := range keycloakGroup.SubGroups {
-			if subGroup.Name == groupName {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-func getGroupID(keycloakGroups KeycloakGroups, groupName string) string {
-	for _, keycloakGroup := range keycloakGroups {
-		if keycloakGroup.Name == groupName {
-			return keycloakGroup.ID
-		}
-		for _, subGroup := range keycloakGroup.SubGroups {
-			if subGroup.Name == groupName {
-				return subGroup.ID
-			}
-		}
-	}
-	return ""
-}
-
-// getKeycloakRoless returns a structure of Groups in Realm verrazzano-system
-func getKeycloakRoles(ctx spi.ComponentContext) (KeycloakRoles, error) {
-	var keycloakRoles KeycloakRoles
-	// Get the Client ID JSON array
-	cmd := execCommand("kubectl", "exec", keycloakPodName, "-n", ComponentNamespace, "-c", "keycloak", "--", "/opt/jboss/keycloak/bin/kcadm.sh", "get-roles", "-r", vzSysRealm)
-	out, err := cmd.Output()
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed retrieving Roles: %s", err)
-		return nil, err
-	}
-	if len(string(out)) == 0 {
-		err = errors.New("Component Keycloak failed; roles JSON from Keycloak is zero length")
-		ctx.Log().Error(err)
-		return nil, err
-	}
-	err = json.Unmarshal(out, &keycloakRoles)
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed ummarshalling groups json: %v", err)
-		return nil, err
-	}
-
-	return keycloakRoles, nil
-}
-
-func roleExists(keycloakRoles KeycloakRoles, roleName string) bool {
-	for _, keycloakRole := range keycloakRoles {
-		if keycloakRole.Name == roleName {
-			return true
-		}
+	if existing != nil {
+		return nil
 	}
-	return false
-}
 
-// getKeycloakUsers returns a structure of Users in Realm verrazzano-system
-func getKeycloakUsers(ctx spi.ComponentContext) (KeycloakUsers, error) {
-	var keycloakUsers KeycloakUsers
-	// Get the Client ID JSON array
-	cmd := execCommand("kubectl", "exec", keycloakPodName, "-n", ComponentNamespace, "-c", "keycloak", "--", "/opt/jboss/keycloak/bin/kcadm.sh", "get", "users", "-r", vzSysRealm)
-	out, err := cmd.Output()
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed retrieving Users: %s", err)
-		return nil, err
-	}
-	if len(string(out)) == 0 {
-		err := errors.New("Component Keycloak failed; users JSON from Keycloak is zero length")
-		ctx.Log().Error(err)
-		return nil, err
-	}
-	err = json.Unmarshal(out, &keycloakUsers)
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed ummarshalling users json: %v", err)
-		return nil, err
+	client := keycloak.Client{}
+	if err := json.Unmarshal([]byte(pgClient), &client); err != nil {
+		return err
 	}
-	return keycloakUsers, nil
-}
-
-func userExists(keycloakUsers KeycloakUsers, userName string) bool {
-	for _, keycloakUser := range keycloakUsers {
-		if keycloakUser.Username == userName {
-			return true
-		}
+	if _, err := kc.CreateClient(vzSysRealm, client); err != nil {
+		ctx.Log().Errorf("Component Keycloak failed creating verrazzano-pg client: %v", err)
+		return err
 	}
-	return false
+	ctx.Log().Debug("createVerrazzanoPgClient: Created verrazzano-pg client")
+	return nil
 }
 
-// getKeycloakClients returns a structure of Users in Realm verrazzano-system
-func getKeycloakClients(ctx spi.ComponentContext) (KeycloakClients, error) {
-	var keycloakClients KeycloakClients
-	// Get the Client ID JSON array
-	cmd := execCommand("kubectl", "exec", keycloakPodName, "-n", ComponentNamespace, "-c", "keycloak", "--", "/opt/jboss/keycloak/bin/kcadm.sh", "get", "clients", "-r", "verrazzano-system", "--fields", "id,clientId")
-	out, err := cmd.Output()
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed retrieving clients: %s", err)
-		return nil, err
-	}
-	if len(string(out)) == 0 {
-		err := errors.New("Component Keycloak failed; clients JSON from Keycloak is zero length")
-		ctx.Log().Error(err)
-		return nil, err
+// updateRealm updates the fields of the realm that are set, unless they already have the values
+func updateRealm(ctx spi.ComponentContext, kc *keycloak.AdminClient, update keycloak.Realm) error {
+	realm, err := kc.GetRealm(update.Realm)
+	if err != nil || realm == nil {
+		ctx.Log().Errorf("Component Keycloak failed retrieving realm %s: %v", update.Realm, err)
+		return fmt.Errorf("Failed retrieving realm %s: %v", update.Realm, err)
 	}
-	err = json.Unmarshal(out, &keycloakClients)
-	if err != nil {
-		ctx.Log().Errorf("Component Keycloak failed ummarshalling client json: %v", err)
-		return nil, err
-	}
-	return keycloakClients, nil
-}
-
-func clientExists(keycloakClients KeycloakClients, clientName string) bool {
-
-	for _, keycloakClient := range keycloakClients {
-		if keycloakClient.ClientID == clientName {
-			return true
-		}
+	if (len(update.PasswordPolicy) == 0 || update.PasswordPolicy == realm.PasswordPolicy) &&
+		(len(update.LoginTheme) == 0 || update.LoginTheme == realm.LoginTheme) &&
+		(update.Enabled == nil || (realm.Enabled != nil && *update.Enabled == *realm.Enabled)) {
+		return nil
 	}
-	return false
-}
-
-func getClientID(keycloakClients KeycloakClients, clientName string) string {
-
-	for _, keycloakClient := range keycloakClients {
-		if keycloakClient.ClientID == clientName {
-			return keycloakClient.ID
-		}
+	if err := kc.UpdateRealm(update); err != nil {
+		ctx.Log().Errorf("Component Keycloak failed updating realm %s: %v", update.Realm, err)
+		return err
 	}
-	return ""
+	ctx.Log().Oncef("Component Keycloak successfully updated realm %s", update.Realm)
+	return nil
 }
 
 func isKeycloakReady(ctx spi.ComponentContext) bool {
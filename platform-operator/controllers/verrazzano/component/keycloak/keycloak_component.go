@@ -18,7 +18,9 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"path/filepath"
@@ -60,6 +62,8 @@ func NewComponent() spi.Component {
 			Dependencies:            []string{istio.ComponentName, nginx.ComponentName, certmanager.ComponentName},
 			SupportsOperatorInstall: true,
 			AppendOverridesFunc:     AppendKeycloakOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
 			Certificates:            certificates,
 			IngressNames: []types.NamespacedName{
 				{
@@ -67,12 +71,40 @@ func NewComponent() spi.Component {
 					Name:      constants.KeycloakIngress,
 				},
 			},
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:            "replicas",
+					Affinity:            "affinity",
+					AffinityAsString:    true,
+					Tolerations:         "tolerations",
+					NodeSelector:        "nodeSelector",
+					PriorityClassName:   "priorityClassName",
+					Resources:           "resources",
+					PodDisruptionBudget: "podDisruptionBudget",
+				},
+			},
 		},
 	}
 }
 
-// Reconcile - the only condition currently being handled by this function is to restore
-// the Keycloak configuration when the MySQL pod gets restarted and ephemeral storage is being used.
+// GetHelmOverrides returns the Helm value overrides for the Keycloak Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.Keycloak; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the Keycloak component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.Keycloak; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
+// Reconcile - restores the Keycloak configuration when the MySQL pod gets restarted and ephemeral storage is being
+// used, and applies the realm configuration of the Verrazzano CR.
 func (c KeycloakComponent) Reconcile(ctx spi.ComponentContext) error {
 	// If the Keycloak component is ready, confirm the configuration is working.
 	// If ephemeral storage is being used, the Keycloak configuration will be rebuilt if needed.
@@ -116,7 +148,7 @@ func (c KeycloakComponent) PreInstall(ctx spi.ComponentContext) error {
 	}
 
 	// Create secret for the keycloakadmin user if it doesn't exist
-	err = createAuthSecret(ctx, ComponentNamespace, "keycloak-http", "keycloakadmin")
+	err = createAuthSecret(ctx, ComponentNamespace, keycloakAdminSecret, keycloakAdminUser)
 	if err != nil {
 		return err
 	}
@@ -179,15 +211,16 @@ func (c KeycloakComponent) IsReady(ctx spi.ComponentContext) bool {
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (c KeycloakComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow any changes except to enable the component post-install
-	if c.IsEnabled(old) && !c.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	// Reject any other edits for now
 	if err := common.CompareInstallArgs(c.getInstallArgs(old), c.getInstallArgs(new)); err != nil {
 		return fmt.Errorf("Updates to istioInstallArgs not allowed for %s", ComponentJSONName)
 	}
-	return nil
+	return validateRealm(new)
+}
+
+// ValidateInstall checks if the specified Verrazzano CR is valid for this component to be installed
+func (c KeycloakComponent) ValidateInstall(vz *vzapi.Verrazzano) error {
+	return validateRealm(vz)
 }
 
 func (c KeycloakComponent) getInstallArgs(vz *vzapi.Verrazzano) []vzapi.InstallArgs {
@@ -196,3 +229,29 @@ func (c KeycloakComponent) getInstallArgs(vz *vzapi.Verrazzano) []vzapi.InstallA
 	}
 	return nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of Keycloak, which is reached through the ingress controller and by
+// the Verrazzano system components, and reaches the identity providers and user federation providers of the realm
+// configuration
+func (c KeycloakComponent) GetNetworkPolicies(ctx spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	keycloakLabels := map[string]string{"app.kubernetes.io/name": ComponentName}
+	egress := []netv1.NetworkPolicyEgressRule{
+		netpolicy.EgressTo([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", map[string]string{"app": "mysql"})}, 3306),
+		netpolicy.EgressTo([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", keycloakLabels)}),
+		netpolicy.IstiodEgress(),
+	}
+	if ports := getRealmEgressPorts(ctx.EffectiveCR()); len(ports) > 0 {
+		egress = append(egress, netpolicy.EgressTo(nil, ports...))
+	}
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-keycloak", keycloakLabels,
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.IngressControllerPeers(), 8080),
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer(constants.VerrazzanoSystemNamespace, nil)}, 8080),
+				// The Keycloak replicas form a cluster
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", keycloakLabels)}),
+				netpolicy.PrometheusIngress(common.EnvoyStatsPort),
+			},
+			egress),
+	}, nil
+}
@@ -172,7 +172,7 @@ func TestKeycloakComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "disable",
@@ -228,3 +228,36 @@ func TestKeycloakComponent_GetCertificateNames(t *testing.T) {
 	assert.Len(t, names, 1)
 	assert.Equal(t, types.NamespacedName{Name: keycloakCertificateName, Namespace: ComponentNamespace}, names[0])
 }
+
+// TestKeycloakComponent_GetNetworkPolicies tests the Keycloak GetNetworkPolicies call
+// GIVEN a Keycloak component with identity providers and user federation providers
+// WHEN I call GetNetworkPolicies
+// THEN the egress to the ports of their endpoints is allowed, and no other egress is allowed without them
+func TestKeycloakComponent_GetNetworkPolicies(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+	policies, err := NewComponent().(KeycloakComponent).GetNetworkPolicies(spi.NewFakeContext(c, &vzapi.Verrazzano{}, false))
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+	assert.Len(t, policies[0].Spec.Egress, 3)
+
+	realm := &vzapi.KeycloakRealm{
+		IdentityProviders: []vzapi.KeycloakIdentityProvider{
+			testRealm.IdentityProviders[0],
+			{Alias: "other", TokenURL: "https://idp.example.com:8443/token", UserInfoURL: "https://idp.example.com:8443/userinfo"},
+		},
+		UserFederation: []vzapi.KeycloakUserFederation{
+			testRealm.UserFederation[0],
+			{Name: "plain", ConnectionURL: "ldap://a.example.com ldap://b.example.com:1389"},
+		},
+	}
+	policies, err = NewComponent().(KeycloakComponent).GetNetworkPolicies(spi.NewFakeContext(c, newRealmVZ(realm), false))
+	assert.NoError(t, err)
+	egress := policies[0].Spec.Egress
+	assert.Len(t, egress, 4)
+	assert.Empty(t, egress[3].To)
+	var ports []int
+	for _, port := range egress[3].Ports {
+		ports = append(ports, port.Port.IntValue())
+	}
+	assert.Equal(t, []int{389, 443, 636, 1389, 8443}, ports)
+}
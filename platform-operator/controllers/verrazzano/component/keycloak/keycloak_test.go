@@ -4,10 +4,11 @@
 package keycloak
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"os"
-	"os/exec"
+	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 
 	certmanager "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -15,15 +16,19 @@ import (
 	"github.com/verrazzano/verrazzano/pkg/bom"
 	"github.com/verrazzano/verrazzano/pkg/k8sutil"
 	k8sutilfake "github.com/verrazzano/verrazzano/pkg/k8sutil/fake"
-	vzos "github.com/verrazzano/verrazzano/pkg/os"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/keycloak"
+	kcfake "github.com/verrazzano/verrazzano/platform-operator/internal/keycloak/fake"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	networkv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/httpstream"
 	"k8s.io/client-go/kubernetes"
 	k8scheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -37,8 +42,11 @@ const (
 	profilesRelativePath    = "../../../../manifests/profiles"
 )
 
-var keycloakClientIds = "[ {\n  \"id\" : \"a732-249893586af2\",\n  \"clientId\" : \"account\"\n}, {\n  \"id\" : \"4256-a350-e46eb48e8606\",\n  \"clientId\" : \"account-console\"\n}, {\n  \"id\" : \"4c1d-8d1b-68635e005567\",\n  \"clientId\" : \"admin-cli\"\n}, {\n  \"id\" : \"4350-ab70-17c37dd995b9\",\n  \"clientId\" : \"broker\"\n}, {\n  \"id\" : \"4f6d-a495-0e9e3849608e\",\n  \"clientId\" : \"realm-management\"\n}, {\n  \"id\" : \"4d92-9d64-f201698d2b79\",\n  \"clientId\" : \"security-admin-console\"\n}, {\n  \"id\" : \"4160-8593-32697ebf2c11\",\n  \"clientId\" : \"verrazzano-oauth-client\"\n}, {\n  \"id\" : \"bde9-9374bd6a38fd\",\n  \"clientId\" : \"verrazzano-pg\"\n}, {\n  \"id\" : \"8327-13cdbfe3b000\",\n  \"clientId\" : \"verrazzano-pkce\"\n\n}, {\n  \"id\" : \"494a-b7ec-b05681cafc73\",\n  \"clientId\" : \"webui\"\n} ]"
-var keycloakErrorClientIds = "[ {\n  \"id\" : \"a732-249893586af2\",\n  \"clientId\" : \"account\"\n}, {\n  \"id\" : \"4256-a350-e46eb48e8606\",\n  \"clientId\" : \"account-console\"\n}, {\n  \"id\" : \"4c1d-8d1b-68635e005567\",\n  \"clientId\" : \"admin-cli\"\n}, {\n  \"id\" : \"4f6d-a495-0e9e3849608e\",\n  \"clientId\" : \"realm-management\"\n}, {\n  \"id\" : \"4d92-9d64-f201698d2b79\",\n  \"clientId\" : \"security-admin-console\"\n}, {\n  \"id\" : \"4160-8593-32697ebf2c11\",\n  \"clientId\" : \"verrazzano-oauth-client\"\n}, {\n  \"id\" : \"bde9-9374bd6a38fd\",\n  \"clientId\" : \"verrazzano-pg\"\n}, {\n  \"id\" : \"494a-b7ec-b05681cafc73\",\n  \"clientId\" : \"webui\"\n} ]"
+var (
+	origClientConfig      = k8sutil.ClientConfig
+	origPortForwardDialer = k8sutil.NewPortForwardDialer
+)
+
 var testVZ = &vzapi.Verrazzano{
 	Spec: vzapi.VerrazzanoSpec{
 		Profile: "dev",
@@ -59,16 +67,6 @@ var crEnabled = vzapi.Verrazzano{
 	},
 }
 
-// fakeBash mocks a successful script run
-func fakeBash(_ ...string) (string, string, error) {
-	return "success", "", nil
-}
-
-// fakeBashFail mocks a failed script run
-func fakeBashFail(_ ...string) (string, string, error) {
-	return "fail", "Script Failed", errors.New("script Failed")
-}
-
 func fakeRESTConfig() (*rest.Config, kubernetes.Interface, error) {
 	cfg, cli := k8sutilfake.NewClientsetConfig()
 	return cfg, cli, nil
@@ -104,473 +102,263 @@ func createTestNginxService() *v1.Service {
 	}
 }
 
-func fakeExecCommand(command string, args ...string) *exec.Cmd {
-	cs := []string{"-test.run=TestHelperProcess", "--", command}
-	cs = append(cs, args...)
-	firstArg := os.Args[0]
-	cmd := exec.Command(firstArg, cs...)
-	cmd.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
-	return cmd
-}
-
-func TestHelperProcess(t *testing.T) {
-	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
-		return
-	}
-
-	_, _ = fmt.Fprintf(os.Stdout, keycloakClientIds)
-	os.Exit(0)
-}
-
-func fakeFailExecCommand(command string, args ...string) *exec.Cmd {
-	cs := []string{"-test.run=TestFailHelperProcess", "--", command}
-	cs = append(cs, args...)
-	firstArg := os.Args[0]
-	cmd := exec.Command(firstArg, cs...)
-	cmd.Env = []string{"GO_WANT_FAIL_HELPER_PROCESS=1"}
-	return cmd
-}
-
-// fakeConfigureRealmCommands Implements the default responses for TestConfigureKeycloakRealms
-// - these tests launch actual go test commands to spit out canned output and return codes
-// - this is to control how the test respond to multiple different commands in the flow
-// - because it's function-based, we can't have different responses based on the call ordering, unless we want to
-//   get funky with how we manage state, and implement the canned calls as a stack or something
-func fakeConfigureRealmCommands(command string, args ...string) *exec.Cmd {
-	kccommand := fmt.Sprintf("%s-%s", args[8], args[9])
-	switch kccommand {
-	case "get-clients":
-		return fakeExecCommand(command, args...)
-	default:
-		return fakeCreateUserGroupCommand(command, args...)
-	}
-}
-
-func TestFailHelperProcess(t *testing.T) {
-	if os.Getenv("GO_WANT_FAIL_HELPER_PROCESS") != "1" {
-		return
-	}
-
-	_, _ = fmt.Fprintf(os.Stdout, keycloakClientIds)
-	os.Exit(1)
-}
-
-func fakeFailExecCommandNoClients(command string, args ...string) *exec.Cmd {
-	cs := []string{"-test.run=TestFailNoClientsHelperProcess", "--", command}
-	cs = append(cs, args...)
-	firstArg := os.Args[0]
-	cmd := exec.Command(firstArg, cs...)
-	cmd.Env = []string{"GO_WANT_FAIL_NO_CLIENTS_HELPER_PROCESS=1"}
-	return cmd
-}
-
-func TestFailNoClientsHelperProcess(t *testing.T) {
-	if os.Getenv("GO_WANT_FAIL_NO_CLIENTS_HELPER_PROCESS") != "1" {
-		return
-	}
-
-	_, _ = fmt.Fprintf(os.Stdout, "")
-	os.Exit(0)
-}
-
-func fakeFailExecCommandNoUser(command string, args ...string) *exec.Cmd {
-	cs := []string{"-test.run=TestFailNoUserHelperProcess", "--", command}
-	cs = append(cs, args...)
-	firstArg := os.Args[0]
-	cmd := exec.Command(firstArg, cs...)
-	cmd.Env = []string{"GO_WANT_FAIL_NO_USER_HELPER_PROCESS=1"}
-	return cmd
-}
-
-func TestFailNoUserHelperProcess(t *testing.T) {
-	if os.Getenv("GO_WANT_FAIL_NO_USER_HELPER_PROCESS") != "1" {
-		return
+// newVerrazzanoSecret returns a secret with the password of a Verrazzano user
+func newVerrazzanoSecret(name string, password string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: constants.VerrazzanoSystemNamespace,
+		},
+		Data: map[string][]byte{"password": []byte(password)},
 	}
-
-	_, _ = fmt.Fprintf(os.Stdout, keycloakErrorClientIds)
-	os.Exit(0)
-}
-
-func fakeCreateUserGroupCommand(command string, args ...string) *exec.Cmd {
-	cs := []string{"-test.run=TestFakeCreateUserGroup", "--", command}
-	cs = append(cs, args...)
-	firstArg := os.Args[0]
-	cmd := exec.Command(firstArg, cs...)
-	cmd.Env = []string{"GO_WANT_TEST_CREATE_USER_GROUP=1"}
-	return cmd
 }
 
-func TestFakeCreateUserGroup(t *testing.T) {
-	if os.Getenv("GO_WANT_TEST_CREATE_USER_GROUP") != "1" {
-		return
+// newReadyKeycloakPod returns a Keycloak pod that is ready
+func newReadyKeycloakPod() *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      keycloakPodName,
+			Namespace: ComponentNamespace,
+		},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{
+				{
+					Type:   v1.PodReady,
+					Status: v1.ConditionTrue,
+				},
+			},
+		},
 	}
-
-	_, _ = fmt.Fprintf(os.Stdout, "Created new group with id '6653a73b-f292-4dfe-91cb-956ead33ea67'")
-	os.Exit(0)
 }
 
-func fakeCreateUserGroupCommandFail(command string, args ...string) *exec.Cmd {
-	cs := []string{"-test.run=TestFakeCreateUserGroupFail", "--", command}
-	cs = append(cs, args...)
-	firstArg := os.Args[0]
-	cmd := exec.Command(firstArg, cs...)
-	cmd.Env = []string{"GO_WANT_TEST_CREATE_USER_GROUP_FAIL=1"}
-	return cmd
-}
-
-func TestFakeCreateUserGroupFail(t *testing.T) {
-	if os.Getenv("GO_WANT_TEST_CREATE_USER_GROUP_FAIL") != "1" {
-		return
+// newRealmTestObjects returns the objects needed to configure the Keycloak realms
+func newRealmTestObjects() []client.Object {
+	return []client.Object{
+		createTestLoginSecret(),
+		createTestNginxService(),
+		newReadyKeycloakPod(),
+		newVerrazzanoSecret("verrazzano", "verrazzano-pw"),
+		newVerrazzanoSecret("verrazzano-prom-internal", "prom-pw"),
+		newVerrazzanoSecret("verrazzano-es-internal", "es-pw"),
 	}
-
-	_, _ = fmt.Fprintf(os.Stdout, "")
-	os.Exit(0)
-}
-
-func fakeCreateUserGroupParseCommandFail(command string, args ...string) *exec.Cmd {
-	cs := []string{"-test.run=TestFakeCreateUserGroupParseFail", "--", command}
-	cs = append(cs, args...)
-	firstArg := os.Args[0]
-	cmd := exec.Command(firstArg, cs...)
-	cmd.Env = []string{"GO_WANT_TEST_CREATE_USER_GROUP_PARSE_FAIL=1"}
-	return cmd
 }
 
-func TestFakeCreateUserParseGroupFail(t *testing.T) {
-	if os.Getenv("GO_WANT_TEST_CREATE_USER_GROUP_PARSE_FAIL") != "1" {
-		return
+// useTestServer makes the component connect to the fake Keycloak instead of forwarding a port to the Keycloak pod,
+// and returns a function that restores the default
+func useTestServer(server *kcfake.Server) func() {
+	newAdminClientFunc = func(ctx spi.ComponentContext) (*keycloak.AdminClient, func(), error) {
+		password, err := getSecretPassword(ctx, ComponentNamespace, keycloakAdminSecret)
+		if err != nil {
+			return nil, nil, err
+		}
+		return keycloak.NewAdminClient(server.BaseURL(), keycloakAdminUser, password, nil), func() {}, nil
 	}
-
-	_, _ = fmt.Fprintf(os.Stdout, "Created new group with id 6653a73b-f292-4dfe-91cb-956ead33ea67")
-	os.Exit(0)
+	return func() { newAdminClientFunc = newAdminClient }
 }
 
-func TestUpdateKeycloakURIs(t *testing.T) {
-	k8sutil.ClientConfig = fakeRESTConfig
-	k8sutil.NewPodExecutor = k8sutilfake.NewPodExecutor
-	tests := []struct {
-		name    string
-		args    spi.ComponentContext
-		wantErr bool
-	}{
-		{
-			name: "testUpdateKeycloakURIs",
-			args: spi.NewFakeContext(
-				fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestLoginSecret(), createTestNginxService()).Build(),
-				testVZ,
-				false),
-			wantErr: false,
-		},
-		{
-			name: "testFailForNoKeycloakSecret",
-			args: spi.NewFakeContext(
-				fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestNginxService()).Build(),
-				testVZ,
-				false),
-			wantErr: true,
-		},
-		{
-			name: "testFailForKeycloakSecretPasswordEmpty",
-			args: spi.NewFakeContext(
-				fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestNginxService(), &v1.Secret{
-					TypeMeta: metav1.TypeMeta{},
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "keycloak-http",
-						Namespace: "keycloak",
-					},
-					Data: map[string][]byte{"password": []byte("")},
-				}).Build(),
-				testVZ,
-				false),
-			wantErr: true,
-		},
-		{
-			name: "testFailForAuthenticationToKeycloak",
-			args: spi.NewFakeContext(
-				fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestLoginSecret(), createTestNginxService()).Build(),
-				testVZ,
-				false),
-			wantErr: true,
-		},
-		{
-			name: "testFailForNoKeycloakClientsReturned",
-			args: spi.NewFakeContext(
-				fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestLoginSecret(), createTestNginxService()).Build(),
-				testVZ,
-				false),
-			wantErr: true,
-		},
-		{
-			name: "testFailForKeycloakUserNotFound",
-			args: spi.NewFakeContext(
-				fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestLoginSecret(), createTestNginxService()).Build(),
-				testVZ,
-				false),
-			wantErr: true,
-		},
-		{
-			name: "testFailForNoIngress",
-			args: spi.NewFakeContext(
-				fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestLoginSecret()).Build(),
-				testVZ,
-				false),
-			wantErr: true,
-		},
-		{
-			name: "testScriptFailure",
-			args: spi.NewFakeContext(
-				fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestLoginSecret(), createTestNginxService()).Build(),
-				testVZ,
-				false),
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			execCommand = fakeExecCommand
-			if tt.wantErr && tt.name == "testFailForAuthenticationToKeycloak" {
-				execCommand = fakeFailExecCommand
-			}
-			if tt.wantErr && tt.name == "testFailForNoKeycloakClientsReturned" {
-				execCommand = fakeFailExecCommandNoClients
-			}
-			if tt.wantErr && tt.name == "testFailForKeycloakUserNotFound" {
-				execCommand = fakeFailExecCommandNoUser
-			}
-			setBashFunc(fakeBash)
-			if tt.wantErr && tt.name == "testScriptFailure" {
-				setBashFunc(fakeBashFail)
-			}
-			defer func() { execCommand = exec.Command }()
-			if err := updateKeycloakUris(tt.args); (err != nil) != tt.wantErr {
-				t.Errorf("updateKeycloakUris() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
+// getWrites returns the requests that create or update Keycloak resources
+func getWrites(requests []string) []string {
+	var writes []string
+	for _, request := range requests {
+		if !strings.HasPrefix(request, http.MethodGet) {
+			writes = append(writes, request)
+		}
 	}
+	return writes
 }
 
 // TestConfigureKeycloakRealms tests configuration of the Keycloak realms
-// GIVEN a client, and a k8s environment
+// GIVEN a Keycloak and the Verrazzano secrets
 // WHEN I call configureKeycloakRealms
-// THEN configure the Keycloak realms, otherwise returning an error if the environment is invalid
+// THEN the Verrazzano system realm is configured, and nothing is changed when it is called again
 func TestConfigureKeycloakRealms(t *testing.T) {
-	loginSecret := createTestLoginSecret()
-	nginxService := createTestNginxService()
-	k8sutil.ClientConfig = fakeRESTConfig
-	k8sutil.NewPodExecutor = k8sutilfake.NewPodExecutor
-
-	defaultBashFunc := func(inArgs ...string) (string, string, error) {
-		return "", "", nil
-	}
-
-	keycloakPod := &v1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      keycloakPodName,
-			Namespace: ComponentNamespace,
-		},
-		Status: v1.PodStatus{
-			Conditions: []v1.PodCondition{
-				{
-					Type:   v1.PodReady,
-					Status: v1.ConditionTrue,
-				},
-			},
-		},
-	}
+	asserts := assert.New(t)
+	server := kcfake.NewServer(keycloakAdminUser, "password")
+	defer server.Close()
+	defer useTestServer(server)()
 
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(newRealmTestObjects()...).Build()
+	ctx := spi.NewFakeContext(c, testVZ, false)
+	asserts.NoError(configureKeycloakRealms(ctx))
+
+	realm := server.Realms[vzSysRealm]
+	asserts.NotNil(realm)
+	asserts.True(*realm.Realm.Enabled)
+	for _, state := range []*kcfake.RealmState{realm, server.Realms[keycloak.MasterRealm]} {
+		asserts.Equal(passwordPolicy, state.Realm.PasswordPolicy)
+		asserts.Equal(loginTheme, state.Realm.LoginTheme)
+	}
+
+	usersGroup := keycloak.FindGroup(realm.Groups, vzUsersGroup)
+	asserts.Equal("/"+vzUsersGroup, usersGroup.Path)
+	for _, group := range []string{vzAdminGroup, vzMonitorGroup, vzSystemGroup} {
+		asserts.NotNil(keycloak.FindGroup(usersGroup.SubGroups, group), group)
+	}
+	asserts.Equal([]string{vzAPIAccessRole}, realm.GroupRoles[usersGroup.ID])
+
+	asserts.Len(realm.Users, 3)
+	asserts.Equal([]string{"/verrazzano-users/verrazzano-admins"}, realm.Users[0].Groups)
+	asserts.Equal("verrazzano-pw", realm.Passwords[vzUserName])
+	asserts.Equal("prom-pw", realm.Passwords[vzInternalPromUser])
+	asserts.Equal("es-pw", realm.Passwords[vzInternalEsUser])
+
+	dnsDomain, err := getDNSDomain(c, ctx.EffectiveCR())
+	asserts.NoError(err)
+	asserts.Len(realm.Clients, 2)
+	asserts.Equal(pkceClientID, realm.Clients[0].ClientID)
+	asserts.Contains(realm.Clients[0].RedirectURIs, "https://verrazzano."+dnsDomain+"/verrazzano/authcallback")
+	asserts.Equal("S256", realm.Clients[0].Attributes["pkce.code.challenge.method"])
+	asserts.Equal(pgClientID, realm.Clients[1].ClientID)
+	asserts.True(*realm.Clients[1].DirectAccessGrantsEnabled)
+
+	// The configuration is only read when it is already done
+	server.Requests = nil
+	asserts.NoError(configureKeycloakRealms(ctx))
+	asserts.Empty(getWrites(server.Requests))
+}
+
+// TestConfigureKeycloakRealmsFail tests configuration of the Keycloak realms
+// GIVEN a Keycloak and an invalid k8s environment, or a Keycloak request that fails
+// WHEN I call configureKeycloakRealms
+// THEN an error is returned
+func TestConfigureKeycloakRealmsFail(t *testing.T) {
 	var tests = []struct {
 		name        string
-		c           client.Client
-		stdout      string
-		isErr       bool
+		objects     []client.Object
+		failure     string
 		errContains string
-		execFunc    func(command string, args ...string) *exec.Cmd
-		bashFunc    bashFuncSig
 	}{
 		{
-			"should fail when login fails",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(keycloakPod).Build(),
-			"blahblah",
-			true,
-			"secrets \"keycloak-http\" not found",
-			fakeCreateUserGroupCommand,
-			defaultBashFunc,
+			name:        "should fail when the Keycloak pod is not ready",
+			objects:     []client.Object{createTestLoginSecret(), &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: keycloakPodName, Namespace: ComponentNamespace}}},
+			errContains: "Waiting for pod keycloak-0 to be ready",
 		},
 		{
-			"should fail to retrieve user group ID from Keycloak when stdout is empty",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(loginSecret, keycloakPod).Build(),
-			"",
-			true,
-			"Component Keycloak failed; user group ID from Keycloak is zero length",
-			fakeCreateUserGroupCommandFail,
-			defaultBashFunc,
+			name:        "should fail when the Keycloak admin secret is not present",
+			objects:     []client.Object{newReadyKeycloakPod()},
+			errContains: "secrets \"keycloak-http\" not found",
 		},
 		{
-			"should fail to retrieve user group ID from Keycloak when stdout is incorrect",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(loginSecret, keycloakPod).Build(),
-			"",
-			true,
-			"failed parsing output returned from Users Group",
-			fakeCreateUserGroupParseCommandFail,
-			defaultBashFunc,
+			name:        "should fail when the Verrazzano secret is not present",
+			objects:     []client.Object{createTestLoginSecret(), createTestNginxService(), newReadyKeycloakPod()},
+			errContains: "secrets \"verrazzano\" not found",
 		},
 		{
-			"should fail when Verrazzano secret is not present",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(loginSecret, keycloakPod).Build(),
-			"blahblah'id",
-			true,
-			"secrets \"verrazzano\" not found",
-			fakeCreateUserGroupCommand,
-			defaultBashFunc,
+			name: "should fail when the Verrazzano secret has no password",
+			objects: []client.Object{createTestLoginSecret(), createTestNginxService(), newReadyKeycloakPod(),
+				newVerrazzanoSecret("verrazzano", "")},
+			errContains: "password field empty in secret",
 		},
 		{
-			"should fail when Verrazzano secret has no password",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(loginSecret, nginxService, keycloakPod,
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte(""),
-					},
-				}).Build(),
-			"blahblah'id",
-			true,
-			"password field empty in secret",
-			fakeCreateUserGroupCommand,
-			defaultBashFunc,
-		},
-		{
-			"should fail when nginx service is not present",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(loginSecret, keycloakPod,
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte("blah di blah"),
-					},
-				},
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano-prom-internal",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte("blah di blah"),
-					},
-				},
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano-es-internal",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte("blah di blah"),
-					},
-				}).Build(),
-			"blahblah'id",
-			true,
-			"services \"ingress-controller-ingress-nginx-controller\" not found",
-			fakeConfigureRealmCommands,
-			defaultBashFunc,
-		},
-		{
-			"bashFunc fails during updateKeycloakURIs",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(loginSecret, nginxService, keycloakPod,
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte("blah di blah"),
-					},
-				},
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano-prom-internal",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte("blah di blah"),
-					},
-				},
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano-es-internal",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte("blah di blah"),
-					},
-				}).Build(),
-			"blahblah'id",
-			true,
-			"updateKeycloakURIs failed",
-			fakeConfigureRealmCommands,
-			func(inArgs ...string) (string, string, error) {
-				return "", "Command failed", fmt.Errorf("updateKeycloakURIs failed")
-			},
+			name: "should fail when the nginx service is not present",
+			objects: []client.Object{createTestLoginSecret(), newReadyKeycloakPod(),
+				newVerrazzanoSecret("verrazzano", "verrazzano-pw"),
+				newVerrazzanoSecret("verrazzano-prom-internal", "prom-pw"),
+				newVerrazzanoSecret("verrazzano-es-internal", "es-pw")},
+			errContains: "services \"ingress-controller-ingress-nginx-controller\" not found",
 		},
 		{
-			"should pass when able to successfully exec commands on the keycloak pod and all k8s objects are present",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(loginSecret, nginxService, keycloakPod,
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte("blah di blah"),
-					},
-				},
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano-prom-internal",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte("blah di blah"),
-					},
-				},
-				&v1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "verrazzano-es-internal",
-						Namespace: "verrazzano-system",
-					},
-					Data: map[string][]byte{
-						"password": []byte("blah di blah"),
-					},
-				}).Build(),
-			"blahblah'id",
-			false,
-			"",
-			fakeConfigureRealmCommands,
-			defaultBashFunc,
+			name:        "should fail when a group can't be created",
+			objects:     newRealmTestObjects(),
+			failure:     "POST /auth/admin/realms/verrazzano-system/groups",
+			errContains: "injected failure",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx := spi.NewFakeContext(tt.c, testVZ, false)
-			execCommand = tt.execFunc
-			bashFunc = tt.bashFunc
-			defer func() { bashFunc = vzos.RunBash }()
-			k8sutilfake.PodSTDOUT = tt.stdout
-			err := configureKeycloakRealms(ctx)
-			if tt.isErr {
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), tt.errContains)
-			} else {
-				assert.NoError(t, err)
+			server := kcfake.NewServer(keycloakAdminUser, "password")
+			defer server.Close()
+			defer useTestServer(server)()
+			if len(tt.failure) > 0 {
+				server.Failures[tt.failure] = http.StatusInternalServerError
 			}
+			c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(tt.objects...).Build()
+			err := configureKeycloakRealms(spi.NewFakeContext(c, testVZ, false))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
 		})
 	}
 }
 
+// TestConfigureKeycloakRealmsLoginFail tests configuration of the Keycloak realms
+// GIVEN a Keycloak that rejects the admin password and MySQL with ephemeral storage
+// WHEN I call configureKeycloakRealms
+// THEN an error is returned and the Keycloak pod is recycled
+func TestConfigureKeycloakRealmsLoginFail(t *testing.T) {
+	server := kcfake.NewServer(keycloakAdminUser, "other-password")
+	defer server.Close()
+	defer useTestServer(server)()
+
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(newRealmTestObjects()...).Build()
+	err := configureKeycloakRealms(spi.NewFakeContext(c, testVZ, false))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid user credentials")
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: ComponentNamespace, Name: keycloakPodName}, &v1.Pod{})
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+// TestUpdateKeycloakURIs tests updating the URIs of the verrazzano-pkce client
+// GIVEN a verrazzano-pkce client with URIs of another DNS domain
+// WHEN I call createOrUpdateVerrazzanoPkceClient
+// THEN the URIs are updated, and aren't updated again when they match the DNS domain
+func TestUpdateKeycloakURIs(t *testing.T) {
+	asserts := assert.New(t)
+	server := kcfake.NewServer(keycloakAdminUser, "password")
+	defer server.Close()
+	kc := keycloak.NewAdminClient(server.BaseURL(), keycloakAdminUser, "password", nil)
+	asserts.NoError(kc.CreateRealm(keycloak.Realm{Realm: vzSysRealm}))
+	_, err := kc.CreateClient(vzSysRealm, keycloak.Client{
+		ClientID:     pkceClientID,
+		RedirectURIs: []string{"https://verrazzano.old.nip.io/*"},
+		WebOrigins:   []string{"https://verrazzano.old.nip.io"},
+	})
+	asserts.NoError(err)
+
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestNginxService()).Build()
+	ctx := spi.NewFakeContext(c, testVZ, false)
+	asserts.NoError(createOrUpdateVerrazzanoPkceClient(ctx, kc))
+	dnsDomain, err := getDNSDomain(c, ctx.EffectiveCR())
+	asserts.NoError(err)
+	pkceClient := server.Realms[vzSysRealm].Clients[0]
+	asserts.Len(pkceClient.RedirectURIs, 12)
+	asserts.Contains(pkceClient.RedirectURIs, "https://kiali.vmi.system."+dnsDomain+"/_authentication_callback")
+	asserts.Contains(pkceClient.WebOrigins, "https://verrazzano."+dnsDomain)
+
+	server.Requests = nil
+	asserts.NoError(createOrUpdateVerrazzanoPkceClient(ctx, kc))
+	asserts.Empty(getWrites(server.Requests))
+
+	// The DNS domain can't be determined without the nginx service
+	_, err = kc.CreateClient(vzSysRealm, keycloak.Client{ClientID: "other"})
+	asserts.NoError(err)
+	pkceClient.RedirectURIs = nil
+	ctx = spi.NewFakeContext(fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build(), testVZ, false)
+	asserts.Error(updateKeycloakUris(ctx, kc, &pkceClient))
+}
+
+// TestNewAdminClient tests creating the client of the Keycloak Admin REST API
+// GIVEN a Keycloak pod that a port can't be forwarded to
+// WHEN I call newAdminClient
+// THEN an error is returned
+func TestNewAdminClient(t *testing.T) {
+	defer func() { k8sutil.ClientConfig = origClientConfig }()
+	defer func() { k8sutil.NewPortForwardDialer = origPortForwardDialer }()
+	k8sutil.ClientConfig = fakeRESTConfig
+	k8sutil.NewPortForwardDialer = func(_ *rest.Config, _ string, _ *url.URL) (httpstream.Dialer, error) {
+		return nil, errors.New("unable to upgrade connection")
+	}
+
+	_, _, err := newAdminClient(spi.NewFakeContext(fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build(), testVZ, false))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "secrets \"keycloak-http\" not found")
+
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(createTestLoginSecret()).Build()
+	_, _, err = newAdminClient(spi.NewFakeContext(c, testVZ, false))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to upgrade connection")
+}
+
 // TestAppendKeycloakOverrides tests that the Keycloak overrides are generated correctly.
 // GIVEN a Verrazzano BOM
 // WHEN I call AppendKeycloakOverrides
@@ -658,52 +446,6 @@ func TestGetEnvironmentName(t *testing.T) {
 	}
 }
 
-// TestLoginKeycloak tests the login to keycloak interacts with k8s resources as expected
-// GIVEN a client
-// WHEN I call loginKeycloak
-// THEN throw an error if the k8s environment is invalid (bad secret)
-func TestLoginKeycloak(t *testing.T) {
-	httpSecret := createTestLoginSecret()
-	httpSecretEmptyPassword := createTestLoginSecret()
-	httpSecretEmptyPassword.Data["password"] = []byte("")
-	cfg, restclient, _ := fakeRESTConfig()
-	k8sutil.NewPodExecutor = k8sutilfake.NewPodExecutor
-
-	var tests = []struct {
-		name  string
-		c     client.Client
-		isErr bool
-	}{
-		{
-			"should fail when secret does not exist",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build(),
-			true,
-		},
-		{
-			"should fail to find the keycloak password if it is empty",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(httpSecretEmptyPassword).Build(),
-			true,
-		},
-		{
-			"should log into keycloak when the password is present",
-			fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(httpSecret).Build(),
-			false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			k8sutilfake.PodSTDOUT = "blah"
-			err := loginKeycloak(spi.NewFakeContext(tt.c, testVZ, false), cfg, restclient)
-			if tt.isErr {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
-		})
-	}
-}
-
 // TestCreateOrUpdateAuthSecret tests creation of the auth secret
 // GIVEN a client
 // WHEN I call createOrUpdateAuthSecret
@@ -800,369 +542,6 @@ func getBoolPtr(b bool) *bool {
 	return &b
 }
 
-// TestClientExists tests that the function returns false/true whether client exists
-// GIVEN an array of keycloak Clients
-// WHEN I call clientExists
-// THEN return true/false whether the client exists in the array of clients
-func TestClientExists(t *testing.T) {
-	var tests = []struct {
-		name string
-		in   KeycloakClients
-		out  bool
-	}{
-		{"testEmptyClients",
-			KeycloakClients{},
-			false,
-		},
-		{"testClientNotFound",
-			KeycloakClients{
-				{
-					"973973",
-					"thisClient",
-				},
-				{
-					"973974",
-					"thatClient",
-				},
-			},
-			false,
-		},
-		{"testClientFound",
-			KeycloakClients{
-				{
-					"973973",
-					"thisClient",
-				},
-				{
-					"973974",
-					"thatClient",
-				},
-				{
-					"973974",
-					"someClient",
-				},
-			},
-			true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.out, clientExists(tt.in, "someClient"))
-		})
-	}
-}
-
-// TestUserExists tests that the function returns false/true whether user exists
-// GIVEN an array of keycloak Users
-// WHEN I call userExists
-// THEN return true/false whether the user exists in the array of users
-func TestUserExists(t *testing.T) {
-	var tests = []struct {
-		name string
-		in   KeycloakUsers
-		out  bool
-	}{
-		{"testEmptyUsers",
-			KeycloakUsers{},
-			false,
-		},
-		{"testUserNotFound",
-			KeycloakUsers{
-				{
-					ID:       "955995",
-					Username: "thisUser",
-				},
-				{
-					ID:       "955996",
-					Username: "thatUser",
-				},
-			},
-			false,
-		},
-		{"testUserFound",
-			KeycloakUsers{
-				{
-					ID:       "955995",
-					Username: "thisUser",
-				},
-				{
-					ID:       "955996",
-					Username: "thatUser",
-				},
-				{
-					ID:       "955997",
-					Username: "someUser",
-				},
-			},
-			true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.out, userExists(tt.in, "someUser"))
-		})
-	}
-}
-
-// TestRoleExists tests that the function returns false/true whether role exists
-// GIVEN an array of keycloak Roles
-// WHEN I call roleExists
-// THEN return true/false whether the role exists in the array of roles
-func TestRoleExists(t *testing.T) {
-	var tests = []struct {
-		name string
-		in   KeycloakRoles
-		out  bool
-	}{
-		{"testEmptyRoles",
-			KeycloakRoles{},
-			false,
-		},
-		{"testRoleNotFound",
-			KeycloakRoles{
-				{
-					ID:   "955995",
-					Name: "thisRole",
-				},
-				{
-					ID:   "955996",
-					Name: "thatRole",
-				},
-			},
-			false,
-		},
-		{"testRoleFound",
-			KeycloakRoles{
-				{
-					ID:   "955995",
-					Name: "thisRole",
-				},
-				{
-					ID:   "955996",
-					Name: "thatRole",
-				},
-				{
-					ID:   "955997",
-					Name: "someRole",
-				},
-			},
-			true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.out, roleExists(tt.in, "someRole"))
-		})
-	}
-}
-
-// TestGroupExists tests that the function returns false/true whether group exists
-// GIVEN an array of keycloak Groups
-// WHEN I call groupExists
-// THEN return true/false whether the group exists in the Keycloak Group structure
-func TestGroupExists(t *testing.T) {
-
-	var (
-		tests = []struct {
-			name string
-			in   KeycloakGroups
-			out  bool
-		}{
-			{"testEmptyGroups",
-				KeycloakGroups{},
-				false,
-			},
-			{"testGroupNotFound",
-				KeycloakGroups{
-					{
-						ID:        "955995",
-						Name:      "thisGroup",
-						SubGroups: nil,
-					},
-					{
-						ID:   "",
-						Name: "",
-						SubGroups: []SubGroup{
-							{
-								ID:   "333333",
-								Name: "subGroup1",
-							},
-							{
-								ID:   "444444",
-								Name: "subGroup2",
-							},
-						},
-					},
-				},
-				false,
-			},
-			{"testGroupFound",
-				KeycloakGroups{
-					{
-						ID:        "955995",
-						Name:      "foundGroup",
-						SubGroups: nil,
-					},
-					{
-						ID:   "",
-						Name: "",
-						SubGroups: []SubGroup{
-							{
-								ID:   "333333",
-								Name: "subGroup1",
-							},
-							{
-								ID:   "444444",
-								Name: "subGroup2",
-							},
-						},
-					},
-				},
-				true,
-			},
-			{"testSubGroupFound",
-				KeycloakGroups{
-					{
-						ID:        "955995",
-						Name:      "someGroup",
-						SubGroups: nil,
-					},
-					{
-						ID:   "",
-						Name: "",
-						SubGroups: []SubGroup{
-							{
-								ID:   "333333",
-								Name: "subGroup1",
-							},
-							{
-								ID:   "444444",
-								Name: "subGroup2",
-							},
-							{
-								ID:   "555555",
-								Name: "foundGroup",
-							},
-						},
-					},
-				},
-				true,
-			},
-		}
-	)
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.out, groupExists(tt.in, "foundGroup"))
-		})
-	}
-}
-
-// TestGetGroupID tests that the function returns the correct groupID for the group name
-// GIVEN an array of keycloak Groups
-// WHEN I call getGroupID
-// THEN return the groupID for the GroupName passed in, empty string for not found
-func TestGetGroupID(t *testing.T) {
-
-	var (
-		tests = []struct {
-			name string
-			in   KeycloakGroups
-			out  string
-		}{
-			{"testEmptyGroups",
-				KeycloakGroups{},
-				"",
-			},
-			{"testGroupIDNotFound",
-				KeycloakGroups{
-					{
-						ID:        "955995",
-						Name:      "thisGroup",
-						SubGroups: nil,
-					},
-					{
-						ID:   "",
-						Name: "",
-						SubGroups: []SubGroup{
-							{
-								ID:   "333333",
-								Name: "subGroup1",
-							},
-							{
-								ID:   "444444",
-								Name: "subGroup2",
-							},
-						},
-					},
-				},
-				"",
-			},
-			{"testGroupIDFound",
-				KeycloakGroups{
-					{
-						ID:        "999999",
-						Name:      "foundGroup",
-						SubGroups: nil,
-					},
-					{
-						ID:   "",
-						Name: "",
-						SubGroups: []SubGroup{
-							{
-								ID:   "333333",
-								Name: "subGroup1",
-							},
-							{
-								ID:   "444444",
-								Name: "subGroup2",
-							},
-						},
-					},
-				},
-				"999999",
-			},
-			{"testSubGroupIDFound",
-				KeycloakGroups{
-					{
-						ID:        "955995",
-						Name:      "someGroup",
-						SubGroups: nil,
-					},
-					{
-						ID:   "",
-						Name: "",
-						SubGroups: []SubGroup{
-							{
-								ID:   "333333",
-								Name: "subGroup1",
-							},
-							{
-								ID:   "444444",
-								Name: "subGroup2",
-							},
-							{
-								ID:   "999999",
-								Name: "foundGroup",
-							},
-						},
-					},
-				},
-				"999999",
-			},
-		}
-	)
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.out, getGroupID(tt.in, "foundGroup"))
-		})
-	}
-}
-
 func TestUpdateKeycloakIngress(t *testing.T) {
 	ingress := &networkv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{Name: "keycloak", Namespace: "keycloak"},
@@ -0,0 +1,679 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/keycloak"
+)
+
+const (
+	oidcProviderID      = "oidc"
+	ldapProviderID      = "ldap"
+	defaultOIDCScope    = "openid"
+	defaultLDAPVendor   = "other"
+	defaultLDAPEditMode = "READ_ONLY"
+	clientSecretField   = "clientSecret"
+
+	// secretChecksumKey is the config key of the checksum of the secret of an identity provider or user federation
+	// provider.  Keycloak doesn't return the secrets, so the checksum tells if the secret has changed.
+	secretChecksumKey = "verrazzanoSecretChecksum"
+
+	// managedKey is the attribute of the groups, and the config key of the identity providers and user federation
+	// providers, that are created for the realm configuration.  They are deleted once they are removed from it.
+	managedKey = "verrazzanoManaged"
+
+	// grantedRolesKey is the attribute of a group with the realm roles granted to it by the role bindings of the realm
+	// configuration.  The roles are revoked once they are removed from it.
+	grantedRolesKey = "verrazzanoGrantedRoles"
+)
+
+// vzGroups are the groups that Verrazzano creates in the Verrazzano system realm
+var vzGroups = []string{vzUsersGroup, vzAdminGroup, vzMonitorGroup, vzSystemGroup}
+
+// vzGroupRoles are the realm roles that Verrazzano grants to its groups, by group name.  They are not revoked when
+// they are removed from a role binding.
+var vzGroupRoles = map[string][]string{vzUsersGroup: {vzAPIAccessRole}}
+
+// ldapVendors are the LDAP vendors that Keycloak supports
+var ldapVendors = []string{"ad", "rhds", "tivoli", "edirectory", defaultLDAPVendor}
+
+// ldapEditModes are the edit modes of an LDAP user federation provider
+var ldapEditModes = []string{defaultLDAPEditMode, "WRITABLE", "UNSYNCED"}
+
+// getRealmConfig returns the realm configuration of the Keycloak component, or nil if there is none
+func getRealmConfig(vz *vzapi.Verrazzano) *vzapi.KeycloakRealm {
+	if vz == nil || vz.Spec.Components.Keycloak == nil {
+		return nil
+	}
+	return vz.Spec.Components.Keycloak.Realm
+}
+
+// getRealmPolicyAndTheme returns the password policy and login theme of the Verrazzano system realm
+func getRealmPolicyAndTheme(vz *vzapi.Verrazzano) (string, string) {
+	policy, theme := passwordPolicy, loginTheme
+	if realm := getRealmConfig(vz); realm != nil {
+		if len(realm.PasswordPolicy) > 0 {
+			policy = realm.PasswordPolicy
+		}
+		if len(realm.LoginTheme) > 0 {
+			theme = realm.LoginTheme
+		}
+	}
+	return policy, theme
+}
+
+// getRealmEgressPorts returns the ports of the endpoints of the identity providers and user federation providers of
+// the realm configuration that Keycloak connects to.  The NetworkPolicy of Keycloak allows the egress to these ports,
+// since the addresses of the endpoints are host names.
+func getRealmEgressPorts(vz *vzapi.Verrazzano) []int {
+	realm := getRealmConfig(vz)
+	if realm == nil {
+		return nil
+	}
+	var urls []string
+	for _, idp := range realm.IdentityProviders {
+		// The authorization and logout endpoints are only reached by the browser of the user
+		urls = append(urls, idp.TokenURL, idp.UserInfoURL, idp.JWKSURL)
+	}
+	for _, uf := range realm.UserFederation {
+		// The connection URL can have several space separated URLs
+		urls = append(urls, strings.Fields(uf.ConnectionURL)...)
+	}
+	var ports []int
+	for _, endpoint := range urls {
+		ports = common.AppendPort(ports, common.GetURLPort(endpoint))
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+// validateRealm checks the realm configuration of the Keycloak component
+func validateRealm(vz *vzapi.Verrazzano) error {
+	realm := getRealmConfig(vz)
+	if realm == nil {
+		return nil
+	}
+
+	// Group names must be unique in the realm, and the parents of the groups must exist without a cycle
+	parents := map[string]string{}
+	for _, group := range realm.Groups {
+		if len(group.Name) == 0 {
+			return fmt.Errorf("A Keycloak realm group must have a name")
+		}
+		if _, ok := parents[group.Name]; ok || contains(vzGroups, group.Name) {
+			return fmt.Errorf("Keycloak realm group %s is not unique", group.Name)
+		}
+		parents[group.Name] = group.ParentGroup
+	}
+	for _, group := range realm.Groups {
+		seen := map[string]bool{group.Name: true}
+		for parent := parents[group.Name]; len(parent) > 0; parent = parents[parent] {
+			if _, ok := parents[parent]; !ok && !contains(vzGroups, parent) {
+				return fmt.Errorf("Keycloak realm group %s has parent group %s, which does not exist", group.Name, parent)
+			}
+			if seen[parent] {
+				return fmt.Errorf("Keycloak realm group %s is its own ancestor", group.Name)
+			}
+			seen[parent] = true
+		}
+	}
+
+	for _, binding := range realm.RoleBindings {
+		if _, ok := parents[binding.Group]; !ok && !contains(vzGroups, binding.Group) {
+			return fmt.Errorf("Keycloak realm role binding is for group %s, which does not exist", binding.Group)
+		}
+		if len(binding.Roles) == 0 {
+			return fmt.Errorf("Keycloak realm role binding for group %s has no roles", binding.Group)
+		}
+	}
+
+	aliases := map[string]bool{}
+	for _, idp := range realm.IdentityProviders {
+		if len(idp.Alias) == 0 || len(idp.AuthorizationURL) == 0 || len(idp.TokenURL) == 0 || len(idp.ClientID) == 0 || len(idp.ClientSecret) == 0 {
+			return fmt.Errorf("Keycloak realm identity provider %s must have an alias, authorizationURL, tokenURL, clientID and clientSecret", idp.Alias)
+		}
+		if aliases[idp.Alias] {
+			return fmt.Errorf("Keycloak realm identity provider %s is not unique", idp.Alias)
+		}
+		aliases[idp.Alias] = true
+	}
+
+	names := map[string]bool{}
+	for _, uf := range realm.UserFederation {
+		if len(uf.Name) == 0 || len(uf.ConnectionURL) == 0 || len(uf.UsersDN) == 0 {
+			return fmt.Errorf("Keycloak realm user federation provider %s must have a name, connectionURL and usersDN", uf.Name)
+		}
+		if names[uf.Name] {
+			return fmt.Errorf("Keycloak realm user federation provider %s is not unique", uf.Name)
+		}
+		names[uf.Name] = true
+		if len(uf.Vendor) > 0 && !contains(ldapVendors, uf.Vendor) {
+			return fmt.Errorf("Keycloak realm user federation provider %s has vendor %s, which is not one of %v", uf.Name, uf.Vendor, ldapVendors)
+		}
+		if len(uf.EditMode) > 0 && !contains(ldapEditModes, uf.EditMode) {
+			return fmt.Errorf("Keycloak realm user federation provider %s has edit mode %s, which is not one of %v", uf.Name, uf.EditMode, ldapEditModes)
+		}
+	}
+	return nil
+}
+
+// configureRealm applies the realm configuration of the Keycloak component to the Verrazzano system realm, and
+// deletes the groups, role bindings, identity providers and user federation providers that were created for the
+// configuration and have been removed from it
+func configureRealm(ctx spi.ComponentContext, kc *keycloak.AdminClient) error {
+	realm := getRealmConfig(ctx.EffectiveCR())
+	if realm == nil {
+		// Everything that was created for a previous configuration is deleted
+		realm = &vzapi.KeycloakRealm{}
+	}
+	if err := createRealmGroups(ctx, kc, realm.Groups); err != nil {
+		return err
+	}
+	for _, binding := range realm.RoleBindings {
+		if err := createRoleBinding(ctx, kc, binding); err != nil {
+			return err
+		}
+	}
+	if err := deleteRemovedGroups(ctx, kc, realm.Groups); err != nil {
+		return err
+	}
+	if err := revokeRemovedRoles(ctx, kc, realm.RoleBindings); err != nil {
+		return err
+	}
+
+	checksumKey := ""
+	if len(realm.IdentityProviders) > 0 || len(realm.UserFederation) > 0 {
+		var err error
+		if checksumKey, err = getSecretPassword(ctx, ComponentNamespace, keycloakAdminSecret); err != nil {
+			return err
+		}
+	}
+	for _, idp := range realm.IdentityProviders {
+		if err := createOrUpdateIdentityProvider(ctx, kc, idp, checksumKey); err != nil {
+			return err
+		}
+	}
+	if err := deleteRemovedIdentityProviders(ctx, kc, realm.IdentityProviders); err != nil {
+		return err
+	}
+	if len(realm.UserFederation) > 0 {
+		sysRealm, err := kc.GetRealm(vzSysRealm)
+		if err != nil || sysRealm == nil {
+			ctx.Log().Errorf("Component Keycloak failed retrieving realm %s: %v", vzSysRealm, err)
+			return fmt.Errorf("Failed retrieving realm %s: %v", vzSysRealm, err)
+		}
+		for _, uf := range realm.UserFederation {
+			if err := createOrUpdateUserFederation(ctx, kc, sysRealm.ID, uf, checksumKey); err != nil {
+				return err
+			}
+		}
+	}
+	return deleteRemovedUserFederation(ctx, kc, realm.UserFederation)
+}
+
+// createRealmGroups creates the groups of the realm configuration, parents before their subgroups
+func createRealmGroups(ctx spi.ComponentContext, kc *keycloak.AdminClient, groups []vzapi.KeycloakGroup) error {
+	ids := map[string]string{}
+	for len(ids) < len(groups) {
+		created := false
+		for _, group := range groups {
+			if _, ok := ids[group.Name]; ok {
+				continue
+			}
+			parentID, err := getParentGroupID(kc, group.ParentGroup, ids)
+			if err != nil {
+				return err
+			}
+			if len(group.ParentGroup) > 0 && len(parentID) == 0 {
+				// The parent group is created first
+				continue
+			}
+			id, err := createVerrazzanoGroup(ctx, kc, group.Name, parentID, map[string][]string{managedKey: {"true"}})
+			if err != nil {
+				return err
+			}
+			ids[group.Name] = id
+			created = true
+		}
+		if !created {
+			return fmt.Errorf("Failed creating the Keycloak realm groups, the parent groups do not exist")
+		}
+	}
+	return nil
+}
+
+// getParentGroupID returns the ID of the parent group, or an empty string if there is no parent group or it
+// doesn't exist yet
+func getParentGroupID(kc *keycloak.AdminClient, parentGroup string, ids map[string]string) (string, error) {
+	if len(parentGroup) == 0 {
+		return "", nil
+	}
+	if id, ok := ids[parentGroup]; ok {
+		return id, nil
+	}
+	if !contains(vzGroups, parentGroup) {
+		return "", nil
+	}
+	groups, err := kc.GetGroups(vzSysRealm)
+	if err != nil {
+		return "", err
+	}
+	if group := keycloak.FindGroup(groups, parentGroup); group != nil {
+		return group.ID, nil
+	}
+	return "", nil
+}
+
+// createRoleBinding creates the realm roles of a role binding that don't exist, and grants them to the group
+func createRoleBinding(ctx spi.ComponentContext, kc *keycloak.AdminClient, binding vzapi.KeycloakRoleBinding) error {
+	groups, err := kc.GetGroups(vzSysRealm)
+	if err != nil {
+		ctx.Log().Errorf("Component Keycloak failed retrieving Groups: %v", err)
+		return err
+	}
+	group := keycloak.FindGroup(groups, binding.Group)
+	if group == nil {
+		return fmt.Errorf("Failed granting roles to Keycloak group %s, the group does not exist", binding.Group)
+	}
+	for _, role := range binding.Roles {
+		if err := createVerrazzanoRole(ctx, kc, role); err != nil {
+			return err
+		}
+	}
+	return grantRealmRoles(ctx, kc, group.Name, group.ID, binding.Roles)
+}
+
+// deleteRemovedGroups deletes the groups that were created for the realm configuration and have been removed from it,
+// with their subgroups
+func deleteRemovedGroups(ctx spi.ComponentContext, kc *keycloak.AdminClient, declared []vzapi.KeycloakGroup) error {
+	groups, err := kc.GetGroups(vzSysRealm)
+	if err != nil {
+		ctx.Log().Errorf("Component Keycloak failed retrieving Groups: %v", err)
+		return err
+	}
+	names := map[string]bool{}
+	for _, group := range declared {
+		names[group.Name] = true
+	}
+	return deleteUndeclaredGroups(ctx, kc, groups, names)
+}
+
+// deleteUndeclaredGroups deletes the groups, and the subgroups, that were created for the realm configuration and
+// whose name is not declared
+func deleteUndeclaredGroups(ctx spi.ComponentContext, kc *keycloak.AdminClient, groups []keycloak.Group, names map[string]bool) error {
+	for _, group := range groups {
+		if !contains(group.Attributes[managedKey], "true") || names[group.Name] {
+			if err := deleteUndeclaredGroups(ctx, kc, group.SubGroups, names); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := kc.DeleteGroup(vzSysRealm, group.ID); err != nil && !keycloak.IsNotFound(err) {
+			ctx.Log().Errorf("Component Keycloak failed deleting group %s: %v", group.Name, err)
+			return err
+		}
+		ctx.Log().Oncef("Component Keycloak successfully deleted the group %s", group.Name)
+	}
+	return nil
+}
+
+// revokeRemovedRoles revokes the realm roles that were granted by the role bindings of the realm configuration and
+// have been removed from them.  The roles granted by the role bindings are tracked by an attribute of the groups.
+func revokeRemovedRoles(ctx spi.ComponentContext, kc *keycloak.AdminClient, bindings []vzapi.KeycloakRoleBinding) error {
+	declared := map[string][]string{}
+	for _, binding := range bindings {
+		for _, role := range binding.Roles {
+			if !contains(declared[binding.Group], role) {
+				declared[binding.Group] = append(declared[binding.Group], role)
+			}
+		}
+	}
+	groups, err := kc.GetGroups(vzSysRealm)
+	if err != nil {
+		ctx.Log().Errorf("Component Keycloak failed retrieving Groups: %v", err)
+		return err
+	}
+	for _, group := range flattenGroups(groups) {
+		roleNames := declared[group.Name]
+		granted := group.Attributes[grantedRolesKey]
+		if sameValues(granted, roleNames) {
+			continue
+		}
+		var revoked []keycloak.Role
+		for _, roleName := range granted {
+			if contains(roleNames, roleName) || contains(vzGroupRoles[group.Name], roleName) {
+				continue
+			}
+			role, err := kc.GetRole(vzSysRealm, roleName)
+			if err != nil {
+				ctx.Log().Errorf("Component Keycloak failed retrieving role %s: %v", roleName, err)
+				return err
+			}
+			if role != nil {
+				revoked = append(revoked, *role)
+			}
+		}
+		if len(revoked) > 0 {
+			if err := kc.DeleteGroupRealmRoles(vzSysRealm, group.ID, revoked); err != nil {
+				ctx.Log().Errorf("Component Keycloak failed revoking roles from group %s: %v", group.Name, err)
+				return err
+			}
+			ctx.Log().Oncef("Component Keycloak successfully revoked roles from group %s", group.Name)
+		}
+
+		// An empty value removes the attribute
+		if group.Attributes == nil {
+			group.Attributes = map[string][]string{}
+		}
+		group.Attributes[grantedRolesKey] = append([]string{}, roleNames...)
+		if err := kc.UpdateGroup(vzSysRealm, group); err != nil {
+			ctx.Log().Errorf("Component Keycloak failed updating group %s: %v", group.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// createOrUpdateIdentityProvider creates an OpenID Connect identity provider, or updates it if it differs from
+// the configuration
+func createOrUpdateIdentityProvider(ctx spi.ComponentContext, kc *keycloak.AdminClient, idp vzapi.KeycloakIdentityProvider, checksumKey string) error {
+	clientSecret, err := getSecretField(ctx, constants.VerrazzanoInstallNamespace, idp.ClientSecret, clientSecretField)
+	if err != nil {
+		return err
+	}
+	enabled := idp.Enabled == nil || *idp.Enabled
+	scope := idp.DefaultScope
+	if len(scope) == 0 {
+		scope = defaultOIDCScope
+	}
+	config := map[string]string{
+		"authorizationUrl": idp.AuthorizationURL,
+		"tokenUrl":         idp.TokenURL,
+		"clientId":         idp.ClientID,
+		"clientSecret":     clientSecret,
+		"clientAuthMethod": "client_secret_post",
+		"defaultScope":     scope,
+		"syncMode":         "IMPORT",
+		secretChecksumKey:  getChecksum(checksumKey, clientSecret),
+		managedKey:         "true",
+	}
+	setIfNotEmpty(config, "userInfoUrl", idp.UserInfoURL)
+	setIfNotEmpty(config, "logoutUrl", idp.LogoutURL)
+	setIfNotEmpty(config, "issuer", idp.Issuer)
+	if len(idp.JWKSURL) > 0 {
+		config["jwksUrl"] = idp.JWKSURL
+		config["useJwksUrl"] = "true"
+		config["validateSignature"] = "true"
+	}
+
+	existing, err := kc.GetIdentityProvider(vzSysRealm, idp.Alias)
+	if err != nil {
+		ctx.Log().Errorf("Component Keycloak failed retrieving identity provider %s: %v", idp.Alias, err)
+		return err
+	}
+	if existing == nil {
+		err := kc.CreateIdentityProvider(vzSysRealm, keycloak.IdentityProvider{
+			Alias:       idp.Alias,
+			DisplayName: idp.DisplayName,
+			ProviderID:  oidcProviderID,
+			Enabled:     &enabled,
+			Config:      config,
+		})
+		if err != nil {
+			ctx.Log().Errorf("Component Keycloak failed creating identity provider %s: %v", idp.Alias, err)
+			return err
+		}
+		ctx.Log().Oncef("Component Keycloak successfully created identity provider %s", idp.Alias)
+		return nil
+	}
+
+	// The secret is compared by its checksum, Keycloak returns the mask instead
+	config["clientSecret"] = keycloak.SecretMask
+	if existing.DisplayName == idp.DisplayName && existing.Enabled != nil && *existing.Enabled == enabled &&
+		configHasValues(existing.Config, config) {
+		return nil
+	}
+	config["clientSecret"] = clientSecret
+	existing.DisplayName = idp.DisplayName
+	existing.Enabled = &enabled
+	if existing.Config == nil {
+		existing.Config = map[string]string{}
+	}
+	for k, v := range config {
+		existing.Config[k] = v
+	}
+	if err := kc.UpdateIdentityProvider(vzSysRealm, *existing); err != nil {
+		ctx.Log().Errorf("Component Keycloak failed updating identity provider %s: %v", idp.Alias, err)
+		return err
+	}
+	ctx.Log().Oncef("Component Keycloak successfully updated identity provider %s", idp.Alias)
+	return nil
+}
+
+// deleteRemovedIdentityProviders deletes the identity providers that were created for the realm configuration and
+// have been removed from it
+func deleteRemovedIdentityProviders(ctx spi.ComponentContext, kc *keycloak.AdminClient, declared []vzapi.KeycloakIdentityProvider) error {
+	idps, err := kc.GetIdentityProviders(vzSysRealm)
+	if err != nil {
+		ctx.Log().Errorf("Component Keycloak failed retrieving identity providers: %v", err)
+		return err
+	}
+	for _, idp := range idps {
+		if idp.Config[managedKey] != "true" || hasIdentityProvider(declared, idp.Alias) {
+			continue
+		}
+		if err := kc.DeleteIdentityProvider(vzSysRealm, idp.Alias); err != nil && !keycloak.IsNotFound(err) {
+			ctx.Log().Errorf("Component Keycloak failed deleting identity provider %s: %v", idp.Alias, err)
+			return err
+		}
+		ctx.Log().Oncef("Component Keycloak successfully deleted identity provider %s", idp.Alias)
+	}
+	return nil
+}
+
+// createOrUpdateUserFederation creates an LDAP user federation provider, or updates it if it differs from the
+// configuration
+func createOrUpdateUserFederation(ctx spi.ComponentContext, kc *keycloak.AdminClient, realmID string, uf vzapi.KeycloakUserFederation, checksumKey string) error {
+	config := map[string][]string{
+		"enabled":               {fmt.Sprint(uf.Enabled == nil || *uf.Enabled)},
+		"vendor":                {valueOrDefault(uf.Vendor, defaultLDAPVendor)},
+		"connectionUrl":         {uf.ConnectionURL},
+		"usersDn":               {uf.UsersDN},
+		"authType":              {"none"},
+		"editMode":              {valueOrDefault(uf.EditMode, defaultLDAPEditMode)},
+		"usernameLDAPAttribute": {valueOrDefault(uf.UsernameLDAPAttribute, "uid")},
+		"rdnLDAPAttribute":      {valueOrDefault(uf.RDNLDAPAttribute, "uid")},
+		"uuidLDAPAttribute":     {valueOrDefault(uf.UUIDLDAPAttribute, "entryUUID")},
+		"userObjectClasses":     {"inetOrgPerson, organizationalPerson"},
+		"searchScope":           {"1"},
+		"importEnabled":         {"true"},
+		"syncRegistrations":     {"false"},
+		managedKey:              {"true"},
+	}
+	if len(uf.UserObjectClasses) > 0 {
+		config["userObjectClasses"] = []string{strings.Join(uf.UserObjectClasses, ", ")}
+	}
+	bindCredential := ""
+	if len(uf.BindDN) > 0 {
+		config["authType"] = []string{"simple"}
+		config["bindDn"] = []string{uf.BindDN}
+		if len(uf.BindCredential) > 0 {
+			var err error
+			if bindCredential, err = getSecretPassword(ctx, constants.VerrazzanoInstallNamespace, uf.BindCredential); err != nil {
+				return err
+			}
+			config["bindCredential"] = []string{bindCredential}
+			config[secretChecksumKey] = []string{getChecksum(checksumKey, bindCredential)}
+		}
+	}
+
+	existing, err := kc.FindComponent(vzSysRealm, keycloak.UserStorageProviderType, uf.Name)
+	if err != nil {
+		ctx.Log().Errorf("Component Keycloak failed retrieving user federation provider %s: %v", uf.Name, err)
+		return err
+	}
+	if existing == nil {
+		_, err := kc.CreateComponent(vzSysRealm, keycloak.Component{
+			Name:         uf.Name,
+			ProviderID:   ldapProviderID,
+			ProviderType: keycloak.UserStorageProviderType,
+			ParentID:     realmID,
+			Config:       config,
+		})
+		if err != nil {
+			ctx.Log().Errorf("Component Keycloak failed creating user federation provider %s: %v", uf.Name, err)
+			return err
+		}
+		ctx.Log().Oncef("Component Keycloak successfully created user federation provider %s", uf.Name)
+		return nil
+	}
+
+	// The secret is compared by its checksum, Keycloak returns the mask instead
+	if len(bindCredential) > 0 {
+		config["bindCredential"] = []string{keycloak.SecretMask}
+	}
+	if existing.Config == nil {
+		existing.Config = map[string][]string{}
+	}
+	upToDate := true
+	for k, v := range config {
+		if !reflect.DeepEqual(existing.Config[k], v) {
+			upToDate = false
+		}
+	}
+	if upToDate {
+		return nil
+	}
+	if len(bindCredential) > 0 {
+		config["bindCredential"] = []string{bindCredential}
+	}
+	for k, v := range config {
+		existing.Config[k] = v
+	}
+	if err := kc.UpdateComponent(vzSysRealm, *existing); err != nil {
+		ctx.Log().Errorf("Component Keycloak failed updating user federation provider %s: %v", uf.Name, err)
+		return err
+	}
+	ctx.Log().Oncef("Component Keycloak successfully updated user federation provider %s", uf.Name)
+	return nil
+}
+
+// deleteRemovedUserFederation deletes the user federation providers that were created for the realm configuration
+// and have been removed from it
+func deleteRemovedUserFederation(ctx spi.ComponentContext, kc *keycloak.AdminClient, declared []vzapi.KeycloakUserFederation) error {
+	components, err := kc.GetComponents(vzSysRealm, keycloak.UserStorageProviderType)
+	if err != nil {
+		ctx.Log().Errorf("Component Keycloak failed retrieving user federation providers: %v", err)
+		return err
+	}
+	for _, component := range components {
+		if !contains(component.Config[managedKey], "true") || hasUserFederation(declared, component.Name) {
+			continue
+		}
+		if err := kc.DeleteComponent(vzSysRealm, component.ID); err != nil && !keycloak.IsNotFound(err) {
+			ctx.Log().Errorf("Component Keycloak failed deleting user federation provider %s: %v", component.Name, err)
+			return err
+		}
+		ctx.Log().Oncef("Component Keycloak successfully deleted user federation provider %s", component.Name)
+	}
+	return nil
+}
+
+// configHasValues returns true if the config has the values
+func configHasValues(config map[string]string, values map[string]string) bool {
+	for k, v := range values {
+		if config[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// setIfNotEmpty sets the config value unless it is empty
+func setIfNotEmpty(config map[string]string, key string, value string) {
+	if len(value) > 0 {
+		config[key] = value
+	}
+}
+
+// valueOrDefault returns the value, or the default value if the value is empty
+func valueOrDefault(value string, defaultValue string) string {
+	if len(value) > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getChecksum returns the HMAC-SHA256 checksum of a secret.  Keycloak admins can read the checksum in the
+// configuration of the provider, the key keeps it from being used to guess the secret.  The key is the password of
+// the Keycloak admin user, the providers are updated once when it changes.
+func getChecksum(key string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(secret))
+	return fmt.Sprintf("%x", mac.Sum(nil))
+}
+
+// flattenGroups returns the groups and their subgroups
+func flattenGroups(groups []keycloak.Group) []keycloak.Group {
+	var all []keycloak.Group
+	for _, group := range groups {
+		all = append(all, group)
+		all = append(all, flattenGroups(group.SubGroups)...)
+	}
+	return all
+}
+
+// sameValues returns true if the slices have the same values, in any order
+func sameValues(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range a {
+		if !contains(b, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasIdentityProvider returns true if there is an identity provider with the alias
+func hasIdentityProvider(idps []vzapi.KeycloakIdentityProvider, alias string) bool {
+	for _, idp := range idps {
+		if idp.Alias == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUserFederation returns true if there is a user federation provider with the name
+func hasUserFederation(providers []vzapi.KeycloakUserFederation, name string) bool {
+	for _, uf := range providers {
+		if uf.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns true if the string is in the slice
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
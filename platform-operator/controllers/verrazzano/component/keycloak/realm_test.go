@@ -0,0 +1,306 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/keycloak"
+	kcfake "github.com/verrazzano/verrazzano/platform-operator/internal/keycloak/fake"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newRealmVZ returns a Verrazzano CR with the realm configuration of the Keycloak component
+func newRealmVZ(realm *vzapi.KeycloakRealm) *vzapi.Verrazzano {
+	vz := testVZ.DeepCopy()
+	vz.Spec.Components.Keycloak.Realm = realm
+	return vz
+}
+
+// newInstallSecret returns a secret in the verrazzano-install namespace
+func newInstallSecret(name string, field string, value string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: constants.VerrazzanoInstallNamespace},
+		Data:       map[string][]byte{field: []byte(value)},
+	}
+}
+
+// testRealm is a realm configuration with all the kinds of resources
+var testRealm = &vzapi.KeycloakRealm{
+	Groups: []vzapi.KeycloakGroup{
+		{Name: "team-a-viewers", ParentGroup: "team-a"},
+		{Name: "team-a", ParentGroup: vzUsersGroup},
+		{Name: "auditors"},
+	},
+	RoleBindings: []vzapi.KeycloakRoleBinding{
+		{Group: "team-a", Roles: []string{"team_a_admin", vzAPIAccessRole}},
+		{Group: vzMonitorGroup, Roles: []string{"audit"}},
+	},
+	IdentityProviders: []vzapi.KeycloakIdentityProvider{
+		{
+			Alias:            "corp",
+			DisplayName:      "Corporate SSO",
+			AuthorizationURL: "https://sso.example.com/authorize",
+			TokenURL:         "https://sso.example.com/token",
+			JWKSURL:          "https://sso.example.com/keys",
+			ClientID:         "verrazzano",
+			ClientSecret:     "corp-sso",
+		},
+	},
+	UserFederation: []vzapi.KeycloakUserFederation{
+		{
+			Name:           "corp-ldap",
+			ConnectionURL:  "ldaps://ldap.example.com",
+			UsersDN:        "ou=people,dc=example,dc=com",
+			BindDN:         "cn=keycloak,dc=example,dc=com",
+			BindCredential: "corp-ldap",
+		},
+	},
+	PasswordPolicy: "length(12)",
+	LoginTheme:     "custom",
+}
+
+// TestConfigureRealm tests applying the realm configuration of the Verrazzano CR
+// GIVEN a Verrazzano CR with groups, role bindings, an identity provider, user federation, a password policy and
+// a login theme
+// WHEN I call configureKeycloakRealms
+// THEN the configuration is created in the Verrazzano system realm, nothing is changed when it is applied again,
+// and the identity provider is updated when its secret changes
+func TestConfigureRealm(t *testing.T) {
+	asserts := assert.New(t)
+	server := kcfake.NewServer(keycloakAdminUser, "password")
+	defer server.Close()
+	defer useTestServer(server)()
+
+	objects := append(newRealmTestObjects(),
+		newInstallSecret("corp-sso", clientSecretField, "sso-secret"),
+		newInstallSecret("corp-ldap", "password", "ldap-secret"))
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(objects...).Build()
+	ctx := spi.NewFakeContext(c, newRealmVZ(testRealm), false)
+	asserts.NoError(configureKeycloakRealms(ctx))
+
+	realm := server.Realms[vzSysRealm]
+	asserts.Equal("length(12)", realm.Realm.PasswordPolicy)
+	asserts.Equal("custom", realm.Realm.LoginTheme)
+	asserts.Equal(passwordPolicy, server.Realms[keycloak.MasterRealm].Realm.PasswordPolicy)
+
+	asserts.Equal("/verrazzano-users/team-a/team-a-viewers", keycloak.FindGroup(realm.Groups, "team-a-viewers").Path)
+	asserts.Equal("/auditors", keycloak.FindGroup(realm.Groups, "auditors").Path)
+	teamA := keycloak.FindGroup(realm.Groups, "team-a")
+	asserts.Equal([]string{"team_a_admin", vzAPIAccessRole}, realm.GroupRoles[teamA.ID])
+	asserts.Equal([]string{"audit"}, realm.GroupRoles[keycloak.FindGroup(realm.Groups, vzMonitorGroup).ID])
+
+	asserts.Len(realm.IdentityProviders, 1)
+	idp := realm.IdentityProviders[0]
+	asserts.Equal("Corporate SSO", idp.DisplayName)
+	asserts.True(*idp.Enabled)
+	asserts.Equal("sso-secret", idp.Config["clientSecret"])
+	asserts.Equal(getChecksum("password", "sso-secret"), idp.Config[secretChecksumKey])
+	asserts.Equal("openid", idp.Config["defaultScope"])
+	asserts.Equal("true", idp.Config["validateSignature"])
+
+	asserts.Len(realm.Components, 1)
+	ldap := realm.Components[0]
+	asserts.Equal(vzSysRealm, ldap.ParentID)
+	asserts.Equal([]string{"ldap-secret"}, ldap.Config["bindCredential"])
+	asserts.Equal([]string{"simple"}, ldap.Config["authType"])
+	asserts.Equal([]string{"READ_ONLY"}, ldap.Config["editMode"])
+
+	// The configuration is only read when it is already applied
+	server.Requests = nil
+	asserts.NoError(configureKeycloakRealms(ctx))
+	asserts.Empty(getWrites(server.Requests))
+
+	// The identity provider is updated when its secret changes
+	secret := newInstallSecret("corp-sso", clientSecretField, "new-secret")
+	asserts.NoError(c.Update(context.TODO(), secret))
+	server.Requests = nil
+	asserts.NoError(configureKeycloakRealms(ctx))
+	asserts.Equal([]string{"PUT /auth/admin/realms/verrazzano-system/identity-provider/instances/corp"}, getWrites(server.Requests))
+	asserts.Equal("new-secret", realm.IdentityProviders[0].Config["clientSecret"])
+	asserts.Equal("Corporate SSO", realm.IdentityProviders[0].DisplayName)
+}
+
+// TestConfigureRealmRemoved tests applying the realm configuration of the Verrazzano CR
+// GIVEN a realm configured for the Verrazzano CR, and groups and providers that were not created for it
+// WHEN I call configureKeycloakRealms once groups, role bindings and providers are removed from the configuration,
+// and once the configuration is removed
+// THEN the groups, roles and providers that were created for the configuration are deleted, and the others are kept
+func TestConfigureRealmRemoved(t *testing.T) {
+	asserts := assert.New(t)
+	server := kcfake.NewServer(keycloakAdminUser, "password")
+	defer server.Close()
+	defer useTestServer(server)()
+
+	objects := append(newRealmTestObjects(),
+		newInstallSecret("corp-sso", clientSecretField, "sso-secret"),
+		newInstallSecret("corp-ldap", "password", "ldap-secret"))
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(objects...).Build()
+	asserts.NoError(configureKeycloakRealms(spi.NewFakeContext(c, newRealmVZ(testRealm), false)))
+
+	realm := server.Realms[vzSysRealm]
+	realm.Groups = append(realm.Groups, keycloak.Group{ID: "manual", Name: "manual", Path: "/manual"})
+	realm.IdentityProviders = append(realm.IdentityProviders, keycloak.IdentityProvider{Alias: "manual", ProviderID: oidcProviderID})
+	realm.Components = append(realm.Components, keycloak.Component{ID: "manual", Name: "manual", ProviderID: ldapProviderID,
+		ProviderType: keycloak.UserStorageProviderType, ParentID: vzSysRealm})
+
+	asserts.NoError(configureKeycloakRealms(spi.NewFakeContext(c, newRealmVZ(&vzapi.KeycloakRealm{
+		Groups:       []vzapi.KeycloakGroup{{Name: "auditors"}},
+		RoleBindings: []vzapi.KeycloakRoleBinding{{Group: "auditors", Roles: []string{"audit"}}},
+	}), false)))
+	asserts.Nil(keycloak.FindGroup(realm.Groups, "team-a"))
+	asserts.Nil(keycloak.FindGroup(realm.Groups, "team-a-viewers"))
+	asserts.NotNil(keycloak.FindGroup(realm.Groups, "manual"))
+	asserts.Empty(realm.GroupRoles[keycloak.FindGroup(realm.Groups, vzMonitorGroup).ID])
+	asserts.Equal([]string{"audit"}, realm.GroupRoles[keycloak.FindGroup(realm.Groups, "auditors").ID])
+	asserts.Equal([]string{vzAPIAccessRole}, realm.GroupRoles[keycloak.FindGroup(realm.Groups, vzUsersGroup).ID])
+	asserts.Len(realm.IdentityProviders, 1)
+	asserts.Equal("manual", realm.IdentityProviders[0].Alias)
+	asserts.Len(realm.Components, 1)
+	asserts.Equal("manual", realm.Components[0].Name)
+
+	asserts.NoError(configureKeycloakRealms(spi.NewFakeContext(c, newRealmVZ(nil), false)))
+	asserts.Nil(keycloak.FindGroup(realm.Groups, "auditors"))
+	asserts.NotNil(keycloak.FindGroup(realm.Groups, vzMonitorGroup))
+	asserts.NotNil(keycloak.FindGroup(realm.Groups, "manual"))
+}
+
+// TestConfigureRealmFail tests applying the realm configuration of the Verrazzano CR
+// GIVEN a Verrazzano CR with an identity provider or user federation provider whose secret does not exist
+// WHEN I call configureKeycloakRealms
+// THEN an error is returned
+func TestConfigureRealmFail(t *testing.T) {
+	var tests = []struct {
+		name        string
+		realm       *vzapi.KeycloakRealm
+		errContains string
+	}{
+		{
+			name:        "should fail when the client secret of an identity provider does not exist",
+			realm:       &vzapi.KeycloakRealm{IdentityProviders: testRealm.IdentityProviders},
+			errContains: "secrets \"corp-sso\" not found",
+		},
+		{
+			name:        "should fail when the bind credential of a user federation provider does not exist",
+			realm:       &vzapi.KeycloakRealm{UserFederation: testRealm.UserFederation},
+			errContains: "secrets \"corp-ldap\" not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := kcfake.NewServer(keycloakAdminUser, "password")
+			defer server.Close()
+			defer useTestServer(server)()
+			c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(newRealmTestObjects()...).Build()
+			err := configureKeycloakRealms(spi.NewFakeContext(c, newRealmVZ(tt.realm), false))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+// TestValidateRealm tests validating the realm configuration of the Verrazzano CR
+// GIVEN a Verrazzano CR with a realm configuration
+// WHEN I call ValidateInstall
+// THEN an error is returned if the configuration is invalid
+func TestValidateRealm(t *testing.T) {
+	var tests = []struct {
+		name        string
+		realm       *vzapi.KeycloakRealm
+		errContains string
+	}{
+		{
+			name:  "should allow no realm configuration",
+			realm: nil,
+		},
+		{
+			name:  "should allow a valid realm configuration",
+			realm: testRealm,
+		},
+		{
+			name:        "should reject a group without a name",
+			realm:       &vzapi.KeycloakRealm{Groups: []vzapi.KeycloakGroup{{ParentGroup: vzUsersGroup}}},
+			errContains: "must have a name",
+		},
+		{
+			name:        "should reject a group with the name of a Verrazzano group",
+			realm:       &vzapi.KeycloakRealm{Groups: []vzapi.KeycloakGroup{{Name: vzAdminGroup}}},
+			errContains: "is not unique",
+		},
+		{
+			name:        "should reject a group with a parent group that does not exist",
+			realm:       &vzapi.KeycloakRealm{Groups: []vzapi.KeycloakGroup{{Name: "a", ParentGroup: "missing"}}},
+			errContains: "parent group missing, which does not exist",
+		},
+		{
+			name:        "should reject groups that are their own ancestor",
+			realm:       &vzapi.KeycloakRealm{Groups: []vzapi.KeycloakGroup{{Name: "a", ParentGroup: "b"}, {Name: "b", ParentGroup: "a"}}},
+			errContains: "is its own ancestor",
+		},
+		{
+			name:        "should reject a role binding for a group that does not exist",
+			realm:       &vzapi.KeycloakRealm{RoleBindings: []vzapi.KeycloakRoleBinding{{Group: "missing", Roles: []string{"role"}}}},
+			errContains: "group missing, which does not exist",
+		},
+		{
+			name:        "should reject a role binding without roles",
+			realm:       &vzapi.KeycloakRealm{RoleBindings: []vzapi.KeycloakRoleBinding{{Group: vzUsersGroup}}},
+			errContains: "has no roles",
+		},
+		{
+			name:        "should reject an identity provider without a client secret",
+			realm:       &vzapi.KeycloakRealm{IdentityProviders: []vzapi.KeycloakIdentityProvider{{Alias: "a", AuthorizationURL: "u", TokenURL: "u", ClientID: "c"}}},
+			errContains: "must have an alias, authorizationURL, tokenURL, clientID and clientSecret",
+		},
+		{
+			name: "should reject identity providers with the same alias",
+			realm: &vzapi.KeycloakRealm{IdentityProviders: []vzapi.KeycloakIdentityProvider{
+				testRealm.IdentityProviders[0], testRealm.IdentityProviders[0]}},
+			errContains: "identity provider corp is not unique",
+		},
+		{
+			name:        "should reject a user federation provider without a users DN",
+			realm:       &vzapi.KeycloakRealm{UserFederation: []vzapi.KeycloakUserFederation{{Name: "a", ConnectionURL: "ldap://a"}}},
+			errContains: "must have a name, connectionURL and usersDN",
+		},
+		{
+			name: "should reject user federation providers with the same name",
+			realm: &vzapi.KeycloakRealm{UserFederation: []vzapi.KeycloakUserFederation{
+				testRealm.UserFederation[0], testRealm.UserFederation[0]}},
+			errContains: "user federation provider corp-ldap is not unique",
+		},
+		{
+			name:        "should reject a user federation provider with an unknown vendor",
+			realm:       &vzapi.KeycloakRealm{UserFederation: []vzapi.KeycloakUserFederation{{Name: "a", ConnectionURL: "ldap://a", UsersDN: "dc=a", Vendor: "x"}}},
+			errContains: "has vendor x",
+		},
+		{
+			name:        "should reject a user federation provider with an unknown edit mode",
+			realm:       &vzapi.KeycloakRealm{UserFederation: []vzapi.KeycloakUserFederation{{Name: "a", ConnectionURL: "ldap://a", UsersDN: "dc=a", EditMode: "x"}}},
+			errContains: "has edit mode x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewComponent().ValidateInstall(newRealmVZ(tt.realm))
+			if len(tt.errContains) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+			assert.Error(t, NewComponent().ValidateUpdate(testVZ, newRealmVZ(tt.realm)))
+		})
+	}
+}
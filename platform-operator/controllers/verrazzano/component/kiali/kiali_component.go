@@ -5,7 +5,6 @@ package kiali
 
 import (
 	"context"
-	"fmt"
 	"path/filepath"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
@@ -18,10 +17,13 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
 
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -59,6 +61,8 @@ func NewComponent() spi.Component {
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), kialiOverridesFile),
 			Dependencies:            []string{istio.ComponentName, nginx.ComponentName, certmanager.ComponentName},
 			AppendOverridesFunc:     AppendOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
 			MinVerrazzanoVersion:    constants.VerrazzanoVersion1_1_0,
 			Certificates:            certificates,
 			IngressNames: []types.NamespacedName{
@@ -67,10 +71,35 @@ func NewComponent() spi.Component {
 					Name:      constants.KialiIngress,
 				},
 			},
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:          "deployment.replicas",
+					Tolerations:       "deployment.tolerations",
+					NodeSelector:      "deployment.node_selector",
+					PriorityClassName: "deployment.priority_class_name",
+					Resources:         "deployment.resources",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the Kiali Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.Kiali; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the Kiali component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.Kiali; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // PostInstall Kiali-post-install processing, create or update the Kiali ingress
 func (c kialiComponent) PostInstall(ctx spi.ComponentContext) error {
 	ctx.Log().Debugf("Kiali post-install")
@@ -130,6 +159,15 @@ func (c kialiComponent) PostUpgrade(ctx spi.ComponentContext) error {
 	return c.createOrUpdateKialiResources(ctx)
 }
 
+// PostUninstall deletes the Kiali CRDs, which are not deleted with the chart
+func (c kialiComponent) PostUninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debug("Kiali PostUninstall dry run")
+		return nil
+	}
+	return common.ForceDeleteResources(ctx, &apiextv1.CustomResourceDefinitionList{}, common.CRDGroupHasSuffix("kiali.io"))
+}
+
 // IsReady Kiali-specific ready-check
 func (c kialiComponent) IsReady(context spi.ComponentContext) bool {
 	if c.HelmComponent.IsReady(context) {
@@ -162,9 +200,19 @@ func (c kialiComponent) createOrUpdateKialiResources(ctx spi.ComponentContext) e
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (c kialiComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow any changes except to enable the component post-install
-	if c.IsEnabled(old) && !c.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	return nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of Kiali, which queries Prometheus, istiod and Jaeger
+func (c kialiComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-kiali", map[string]string{"app": "kiali"},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.AuthProxyPeers(), 20001),
+				netpolicy.PrometheusIngress(9090, common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(common.ClusterPeers()),
+			}),
+	}, nil
+}
@@ -19,6 +19,7 @@ import (
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -50,6 +51,7 @@ func init() {
 	_ = istioclinet.AddToScheme(testScheme)
 	_ = istioclisec.AddToScheme(testScheme)
 	_ = certapiv1.AddToScheme(testScheme)
+	_ = apiextv1.AddToScheme(testScheme)
 	// +kubebuilder:scaffold:testScheme
 }
 
@@ -370,7 +372,7 @@ func Test_kialiComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name:    "no change",
@@ -388,3 +390,20 @@ func Test_kialiComponent_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+// TestPostUninstall tests the PostUninstall function
+// GIVEN a call to PostUninstall
+// WHEN the Kiali CRDs exist
+// THEN they are deleted
+func TestPostUninstall(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		&apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitoringdashboards.monitoring.kiali.io"},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Group: "monitoring.kiali.io"},
+		},
+	).Build()
+	assert.NoError(t, NewComponent().PostUninstall(spi.NewFakeContext(client, &vzapi.Verrazzano{}, false)))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Name: "monitoringdashboards.monitoring.kiali.io"}, &apiextv1.CustomResourceDefinition{})
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -15,6 +15,7 @@ import (
 	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
@@ -187,64 +188,16 @@ func generateVolumeSourceOverrides(compContext spi.ComponentContext, kvs []bom.K
 
 // doGenerateVolumeSourceOverrides generates the appropriate persistence overrides given the effective CR
 func doGenerateVolumeSourceOverrides(effectiveCR *vzapi.Verrazzano, kvs []bom.KeyValue) ([]bom.KeyValue, error) {
+	return common.AppendPersistenceOverrides(effectiveCR, getVolumeSource(effectiveCR), "persistence", kvs)
+}
+
+// getVolumeSource returns the volume source of MySQL, which is the DefaultVolumeSource when MySQL has none
+func getVolumeSource(effectiveCR *vzapi.Verrazzano) *v1.VolumeSource {
 	var mySQLVolumeSource *v1.VolumeSource
 	if effectiveCR.Spec.Components.Keycloak != nil {
 		mySQLVolumeSource = effectiveCR.Spec.Components.Keycloak.MySQL.VolumeSource
 	}
-	if mySQLVolumeSource == nil {
-		mySQLVolumeSource = effectiveCR.Spec.DefaultVolumeSource
-	}
-
-	// No volumes to process, return what we have
-	if mySQLVolumeSource == nil {
-		return kvs, nil
-	}
-
-	if mySQLVolumeSource.EmptyDir != nil {
-		// EmptyDir, disable persistence
-		kvs = append(kvs, bom.KeyValue{
-			Key:   "persistence.enabled",
-			Value: "false",
-		})
-	} else if mySQLVolumeSource.PersistentVolumeClaim != nil {
-		// Configured for persistence, adapt the PVC Spec template to the appropriate Helm args
-		pvcs := mySQLVolumeSource.PersistentVolumeClaim
-		storageSpec, found := vzconfig.FindVolumeTemplate(pvcs.ClaimName, effectiveCR.Spec.VolumeClaimSpecTemplates)
-		if !found {
-			return kvs, fmt.Errorf("Failed, No VolumeClaimTemplate found for %s", pvcs.ClaimName)
-		}
-		storageClass := storageSpec.StorageClassName
-		if storageClass != nil && len(*storageClass) > 0 {
-			kvs = append(kvs, bom.KeyValue{
-				Key:       "persistence.storageClass",
-				Value:     *storageClass,
-				SetString: true,
-			})
-		}
-		storage := storageSpec.Resources.Requests.Storage()
-		if storageSpec.Resources.Requests != nil && !storage.IsZero() {
-			kvs = append(kvs, bom.KeyValue{
-				Key:       "persistence.size",
-				Value:     storage.String(),
-				SetString: true,
-			})
-		}
-		accessModes := storageSpec.AccessModes
-		if len(accessModes) > 0 {
-			// MySQL only allows a single AccessMode value, so just choose the first
-			kvs = append(kvs, bom.KeyValue{
-				Key:       "persistence.accessMode",
-				Value:     string(accessModes[0]),
-				SetString: true,
-			})
-		}
-		// Enable MySQL persistence
-		kvs = append(kvs, bom.KeyValue{
-			Key:   "persistence.enabled",
-			Value: "true",
-		})
-	}
-	return kvs, nil
+	return vzconfig.GetVolumeSource(effectiveCR, mySQLVolumeSource)
 }
 
 //appendCustomImageOverrides - Append the custom overrides for the busybox initContainer
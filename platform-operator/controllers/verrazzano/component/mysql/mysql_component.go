@@ -5,9 +5,9 @@ package mysql
 
 import (
 	"fmt"
-	"github.com/verrazzano/verrazzano/pkg/bom"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/istio"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"path/filepath"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
@@ -17,6 +17,8 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	netv1 "k8s.io/api/networking/v1"
 )
 
 // ComponentName is the name of the component
@@ -28,6 +30,9 @@ const ComponentNamespace = vzconst.KeycloakNamespace
 // ComponentJSONName is the josn name of the verrazzano component in CRD
 const ComponentJSONName = "keycloak.mysql"
 
+// mysqlVolumeDefaults are the defaults of the persistence of the MySQL chart
+var mysqlVolumeDefaults = common.VolumeDefaults{Persistent: true, Size: "8Gi"}
+
 // mysqlComponent represents an MySQL component
 type mysqlComponent struct {
 	helm.HelmComponent
@@ -49,11 +54,45 @@ func NewComponent() spi.Component {
 			ImagePullSecretKeyname:  secret.DefaultImagePullSecretKeyName,
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), "mysql-values.yaml"),
 			AppendOverridesFunc:     appendMySQLOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
 			Dependencies:            []string{istio.ComponentName},
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Affinity:          "affinity",
+					Tolerations:       "tolerations",
+					NodeSelector:      "nodeSelector",
+					PriorityClassName: "priorityClassName",
+					Resources:         "resources",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the MySQL Helm chart, they are part of the Keycloak
+// configuration
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.Keycloak; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.MySQL.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the MySQL component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.Keycloak; comp != nil {
+		return comp.MySQL.Kubernetes
+	}
+	return nil
+}
+
+// GetMonitoredOverrides returns no overrides, changes to the MySQL configuration are not supported yet so the
+// component isn't installed again when its Helm value overrides change
+func (c mysqlComponent) GetMonitoredOverrides(_ spi.ComponentContext) []vzapi.Overrides {
+	return nil
+}
+
 // IsReady calls MySQL isMySQLReady function
 func (c mysqlComponent) IsReady(context spi.ComponentContext) bool {
 	if c.HelmComponent.IsReady(context) {
@@ -82,29 +121,26 @@ func (c mysqlComponent) PostInstall(ctx spi.ComponentContext) error {
 	return postInstall(ctx)
 }
 
+// PostUninstall deletes the keycloak namespace, which is shared with Keycloak and is uninstalled first
+func (c mysqlComponent) PostUninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debug("MySQL PostUninstall dry run")
+		return nil
+	}
+	return common.DeleteNamespaces(ctx, ComponentNamespace)
+}
+
+// ValidateInstall checks if the specified Verrazzano CR is valid for this component to be installed
+func (c mysqlComponent) ValidateInstall(vz *vzapi.Verrazzano) error {
+	return vzconfig.ValidateVolumeSource(vz, getVolumeSource(vz), ComponentJSONName)
+}
+
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (c mysqlComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Block all changes for now, particularly around storage changes
-
-	// compare the VolumeSourceOverrides and reject if the type or size or storage class is different
-	oldSetting, err := doGenerateVolumeSourceOverrides(old, []bom.KeyValue{})
-	if err != nil {
-		return err
-	}
-	newSetting, err := doGenerateVolumeSourceOverrides(new, []bom.KeyValue{})
-	if err != nil {
+	// Reject changes of the persistence or StorageClass of the volume, it can only grow when the StorageClass allows it
+	if err := common.CompareVolumeSources(old, new, getVolumeSource(old), getVolumeSource(new), mysqlVolumeDefaults, ComponentJSONName); err != nil {
 		return err
 	}
-	// Reject any persistence-specific changes via the mysqlInstallArgs settings
-	if bom.FindKV(oldSetting, "persistence.enabled") != bom.FindKV(newSetting, "persistence.enabled") {
-		return fmt.Errorf("Can not change persistence enabled setting in component: %s", ComponentJSONName)
-	}
-	if bom.FindKV(oldSetting, "persistence.size") != bom.FindKV(newSetting, "persistence.size") {
-		return fmt.Errorf("Can not change persistence volume size in component: %s", ComponentJSONName)
-	}
-	if bom.FindKV(oldSetting, "persistence.storageClass") != bom.FindKV(newSetting, "persistence.storageClass") {
-		return fmt.Errorf("Can not change persistence storage class in component: %s", ComponentJSONName)
-	}
 	// Reject any installArgs changes for now
 	if err := common.CompareInstallArgs(c.getInstallArgs(old), c.getInstallArgs(new)); err != nil {
 		return fmt.Errorf("Updates to mysqlInstallArgs not allowed for %s", ComponentJSONName)
@@ -118,3 +154,19 @@ func (c mysqlComponent) getInstallArgs(vz *vzapi.Verrazzano) []vzapi.InstallArgs
 	}
 	return nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the MySQL database of Keycloak, which is also reached by the
+// backup and restore jobs of Keycloak
+func (c mysqlComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-mysql", map[string]string{"app": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", map[string]string{"app.kubernetes.io/name": "keycloak"})}, 3306),
+				netpolicy.IngressFrom(common.BackupJobPeers("keycloak"), 3306),
+				netpolicy.PrometheusIngress(common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.IstiodEgress(),
+			}),
+	}, nil
+}
@@ -4,17 +4,33 @@
 package mysql
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/k8sutil"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 )
 
 func Test_mysqlComponent_ValidateUpdate(t *testing.T) {
+	// The resized volumes have the default StorageClass, which does not allow volume expansion
+	k8sutil.SetFakeClient(k8sfake.NewSimpleClientset())
+	defer k8sutil.ClearFakeClient()
 	var pvc1Gi, _ = resource.ParseQuantity("1Gi")
 	var pvc2Gi, _ = resource.ParseQuantity("2Gi")
 	var storageClass1 = "class1"
@@ -341,3 +357,89 @@ func Test_mysqlComponent_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+// Test_mysqlComponent_ValidateUpdateResize tests the ValidateUpdate function
+// GIVEN a MySQL volume claim template whose StorageClass allows volume expansion
+// WHEN the size of the template is changed
+// THEN the volume can grow but not shrink
+func Test_mysqlComponent_ValidateUpdateResize(t *testing.T) {
+	expansion := true
+	k8sutil.SetFakeClient(k8sfake.NewSimpleClientset(&storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "expandable"},
+		AllowVolumeExpansion: &expansion,
+	}))
+	defer k8sutil.ClearFakeClient()
+
+	newVZ := func(size string) *vzapi.Verrazzano {
+		storageClass := "expandable"
+		return &vzapi.Verrazzano{
+			Spec: vzapi.VerrazzanoSpec{
+				Components: vzapi.ComponentSpec{
+					Keycloak: &vzapi.KeycloakComponent{
+						MySQL: vzapi.MySQLComponent{
+							VolumeSource: &corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "mysql"},
+							},
+						},
+					},
+				},
+				VolumeClaimSpecTemplates: []vzapi.VolumeClaimSpecTemplate{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "mysql"},
+						Spec: corev1.PersistentVolumeClaimSpec{
+							StorageClassName: &storageClass,
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{"storage": resource.MustParse(size)},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	c := NewComponent()
+	assert.NoError(t, c.ValidateUpdate(newVZ("1Gi"), newVZ("2Gi")))
+	assert.Error(t, c.ValidateUpdate(newVZ("2Gi"), newVZ("1Gi")))
+}
+
+// Test_mysqlComponent_ValidateInstall tests the ValidateInstall function
+// GIVEN a MySQL volume source
+// WHEN ValidateInstall is called
+// THEN an error is returned when the volume claim template does not exist
+func Test_mysqlComponent_ValidateInstall(t *testing.T) {
+	vz := &vzapi.Verrazzano{
+		Spec: vzapi.VerrazzanoSpec{
+			DefaultVolumeSource: &corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "missing"},
+			},
+		},
+	}
+	assert.Error(t, NewComponent().ValidateInstall(vz))
+	vz.Spec.DefaultVolumeSource = &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	assert.NoError(t, NewComponent().ValidateInstall(vz))
+}
+
+// Test_mysqlComponent_GetNetworkPolicies tests the GetNetworkPolicies function
+// GIVEN the MySQL component
+// WHEN GetNetworkPolicies is called
+// THEN the backup and restore jobs of Keycloak in any namespace can reach MySQL
+func Test_mysqlComponent_GetNetworkPolicies(t *testing.T) {
+	policies, err := NewComponent().(mysqlComponent).GetNetworkPolicies(nil)
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+	assert.Contains(t, policies[0].Spec.Ingress, netpolicy.IngressFrom(common.BackupJobPeers("keycloak"), 3306))
+}
+
+// Test_mysqlComponent_PostUninstall tests the PostUninstall function
+// GIVEN a call to PostUninstall
+// WHEN the keycloak namespace exists
+// THEN it is deleted
+func Test_mysqlComponent_PostUninstall(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ComponentNamespace}},
+	).Build()
+	assert.NoError(t, NewComponent().PostUninstall(spi.NewFakeContext(client, &vzapi.Verrazzano{}, false)))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Name: ComponentNamespace}, &corev1.Namespace{})
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -27,6 +27,9 @@ const (
 
 	ControllerName = vpoconst.NGINXControllerServiceName
 	backendName    = "ingress-controller-ingress-nginx-defaultbackend"
+
+	// clusterRoleName is the name of the cluster role and binding of the ingress controller
+	clusterRoleName = "ingress-controller-ingress-nginx"
 )
 
 func isNginxReady(context spi.ComponentContext) bool {
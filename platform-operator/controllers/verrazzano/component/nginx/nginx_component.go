@@ -4,18 +4,22 @@
 package nginx
 
 import (
-	"fmt"
 	"path/filepath"
 
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/istio"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	netv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ComponentName is the name of the component
@@ -52,12 +56,46 @@ func NewComponent() spi.Component {
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), ValuesFileOverride),
 			PreInstallFunc:          PreInstall,
 			AppendOverridesFunc:     AppendOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
 			PostInstallFunc:         PostInstall,
 			Dependencies:            []string{istio.ComponentName},
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:          "controller.replicaCount",
+					Affinity:          "controller.affinity",
+					Tolerations:       "controller.tolerations",
+					NodeSelector:      "controller.nodeSelector",
+					PriorityClassName: "controller.priorityClassName",
+					Resources:         "controller.resources",
+				},
+				{
+					Affinity:          "defaultBackend.affinity",
+					Tolerations:       "defaultBackend.tolerations",
+					NodeSelector:      "defaultBackend.nodeSelector",
+					PriorityClassName: "defaultBackend.priorityClassName",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the ingress-nginx Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.Ingress; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the ingress-nginx component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.Ingress; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // IsEnabled nginx-specific enabled check for installation
 func (c nginxComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	comp := effectiveCR.Spec.Components.Ingress
@@ -77,9 +115,6 @@ func (c nginxComponent) IsReady(ctx spi.ComponentContext) bool {
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (c nginxComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	if c.IsEnabled(old) && !c.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	return c.validateForExternalIPSWithNodePort(&new.Spec)
 }
 
@@ -107,3 +142,49 @@ func (c nginxComponent) validateForExternalIPSWithNodePort(vz *vzapi.VerrazzanoS
 
 	return nil
 }
+
+// RunPreflightChecks checks that the cluster supports LoadBalancer services when the ingress is of the LoadBalancer type
+func (c nginxComponent) RunPreflightChecks(ctx spi.ComponentContext) ([]vzapi.PreflightCheck, error) {
+	if ingress := ctx.EffectiveCR().Spec.Components.Ingress; ingress != nil && ingress.Type == vzapi.NodePort {
+		return nil, nil
+	}
+	check, err := common.CheckLoadBalancerSupport(ctx, ComponentName)
+	if err != nil {
+		return nil, err
+	}
+	return []vzapi.PreflightCheck{check}, nil
+}
+
+// PostUninstall deletes the cluster role and binding of the ingress controller and the ingress-nginx namespace
+func (c nginxComponent) PostUninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debug("NGINX PostUninstall dry run")
+		return nil
+	}
+	if err := common.DeleteObject(ctx, &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName}}); err != nil {
+		return err
+	}
+	if err := common.DeleteObject(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName}}); err != nil {
+		return err
+	}
+	return common.DeleteNamespaces(ctx, ComponentNamespace)
+}
+
+// GetNetworkPolicies returns the NetworkPolicies of the NGINX ingress controller, which routes the requests from outside
+// the cluster to the services of the cluster
+func (c nginxComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-ingress-nginx", nil,
+			[]netv1.NetworkPolicyIngressRule{
+				// HTTPS from anywhere, and the admission webhook from the API server
+				netpolicy.IngressFrom(nil, 443, 8443),
+				netpolicy.IngressFrom(common.ClusterPeers(), 80),
+				// The controller calls the default backend
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", nil)}, 8080),
+				netpolicy.PrometheusIngress(10254, common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(common.ClusterPeers()),
+			}),
+	}, nil
+}
@@ -4,9 +4,18 @@
 package nginx
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/verrazzano/verrazzano/pkg/test/ip"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	v1 "k8s.io/api/core/v1"
 
@@ -49,7 +58,7 @@ func Test_nginxComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "change-type-to-nodeport-without-externalIPs",
@@ -274,3 +283,23 @@ func Test_nginxComponent_ValidateInstall(t *testing.T) {
 		})
 	}
 }
+
+// TestPostUninstall tests the PostUninstall function
+// GIVEN a call to PostUninstall
+// WHEN the cluster role and binding of the ingress controller and the ingress-nginx namespace exist
+// THEN they are deleted
+func TestPostUninstall(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ComponentNamespace}},
+	).Build()
+	assert.NoError(t, NewComponent().PostUninstall(spi.NewFakeContext(client, &vzapi.Verrazzano{}, false)))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Name: clusterRoleName}, &rbacv1.ClusterRole{})
+	assert.True(t, errors.IsNotFound(err))
+	err = client.Get(context.TODO(), types.NamespacedName{Name: clusterRoleName}, &rbacv1.ClusterRoleBinding{})
+	assert.True(t, errors.IsNotFound(err))
+	err = client.Get(context.TODO(), types.NamespacedName{Name: ComponentNamespace}, &v1.Namespace{})
+	assert.True(t, errors.IsNotFound(err))
+}
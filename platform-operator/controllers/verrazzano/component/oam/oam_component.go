@@ -4,7 +4,6 @@
 package oam
 
 import (
-	"fmt"
 	"path/filepath"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
@@ -14,6 +13,10 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	netv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ComponentName is the name of the component
@@ -41,10 +44,37 @@ func NewComponent() spi.Component {
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), "oam-kubernetes-runtime-values.yaml"),
 			ImagePullSecretKeyname:  secret.DefaultImagePullSecretKeyName,
 			Dependencies:            []string{},
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:     "replicaCount",
+					Affinity:     "affinity",
+					Tolerations:  "tolerations",
+					NodeSelector: "nodeSelector",
+					Resources:    "resources",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the OAM Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.OAM; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the OAM component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.OAM; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // IsEnabled OAM-specific enabled check for installation
 func (c oamComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	comp := effectiveCR.Spec.Components.OAM
@@ -64,10 +94,6 @@ func (c oamComponent) IsReady(ctx spi.ComponentContext) bool {
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (c oamComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Block all changes for now, particularly around storage changes
-	if c.IsEnabled(old) && !c.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	return nil
 }
 
@@ -91,3 +117,25 @@ func (c oamComponent) PostUpgrade(ctx spi.ComponentContext) error {
 	}
 	return c.HelmComponent.PostUpgrade(ctx)
 }
+
+// PostUninstall deletes the cluster role the OAM component added for the persistent volume claim workloads
+func (c oamComponent) PostUninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debug("OAM PostUninstall dry run")
+		return nil
+	}
+	return common.DeleteObject(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: pvcClusterRoleName}})
+}
+
+// GetNetworkPolicies returns the NetworkPolicies of the OAM Kubernetes runtime, which only reaches the API server
+func (c oamComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-oam-kubernetes-runtime", map[string]string{"app.kubernetes.io/name": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.PrometheusIngress(common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.IstiodEgress(),
+			}),
+	}, nil
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -57,7 +59,7 @@ func Test_oamComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name:    "no change",
@@ -115,3 +117,17 @@ func TestPostUpgrade(t *testing.T) {
 	err = client.Get(context.TODO(), types.NamespacedName{Name: pvcClusterRoleName}, &clusterRole)
 	assert.NoError(t, err)
 }
+
+// TestPostUninstall tests the PostUninstall function
+// GIVEN a call to PostUninstall
+// WHEN the persistent volume claim cluster role exists
+// THEN it is deleted
+func TestPostUninstall(t *testing.T) {
+	client := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: pvcClusterRoleName}},
+	).Build()
+	assert.NoError(t, NewComponent().PostUninstall(spi.NewFakeContext(client, &vzapi.Verrazzano{}, false)))
+
+	err := client.Get(context.TODO(), types.NamespacedName{Name: pvcClusterRoleName}, &rbacv1.ClusterRole{})
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"context"
+	"sort"
+
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+)
+
+const (
+	// backupEndpoint is the URL of the OpenSearch master service, which takes and restores the snapshots
+	backupEndpoint = "http://vmi-system-es-master-http.verrazzano-system.svc.cluster.local:9200"
+
+	// defaultStoragePort is the port of the default object store of the snapshots
+	defaultStoragePort = 443
+)
+
+// GetBackupSpec returns the backup of the OpenSearch indices, which is a snapshot in the storage location of the backup
+func (o opensearchComponent) GetBackupSpec() spi.BackupSpec {
+	return spi.BackupSpec{
+		Order:    3,
+		Method:   spi.BackupMethodOpenSearch,
+		Endpoint: backupEndpoint,
+	}
+}
+
+// getSnapshotStoragePorts returns the ports of the object stores the OpenSearch nodes store the snapshots in, which are
+// the default object store and the S3 storage locations of the backups
+func getSnapshotStoragePorts(ctx spi.ComponentContext) ([]int, error) {
+	backups := vzapi.VerrazzanoBackupList{}
+	if err := ctx.Client().List(context.TODO(), &backups); err != nil {
+		return nil, err
+	}
+	ports := []int{defaultStoragePort}
+	for _, backup := range backups.Items {
+		if backup.Spec.StorageLocation.S3 != nil {
+			ports = common.AppendPort(ports, common.GetURLPort(backup.Spec.StorageLocation.S3.Endpoint))
+		}
+	}
+	sort.Ints(ports)
+	return ports, nil
+}
@@ -4,14 +4,17 @@
 package opensearch
 
 import (
-	"fmt"
+	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/vmo"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"strings"
 )
 
 const (
@@ -76,6 +79,15 @@ func (o opensearchComponent) PreInstall(ctx spi.ComponentContext) error {
 	if err := common.CreateAndLabelVMINamespaces(ctx); err != nil {
 		return ctx.Log().ErrorfNewErr("Failed creating/labeling namespace %s for OpenSearch : %v", ComponentNamespace, err)
 	}
+	// The data nodes that are removed are drained before the VMI is updated, so that no shards are lost
+	progress, err := drainLeavingDataNodes(ctx)
+	if err != nil {
+		return err
+	}
+	if len(progress) > 0 {
+		ctx.Log().Progressf("Component %s: %s", ComponentName, progress)
+		return ctrlerrors.RetryableError{Source: ComponentName, Operation: "Drain the data nodes that are removed", Progress: progress}
+	}
 	return nil
 }
 
@@ -103,7 +115,10 @@ func (o opensearchComponent) IsReady(ctx spi.ComponentContext) bool {
 // PostInstall OpenSearch post-install processing
 func (o opensearchComponent) PostInstall(ctx spi.ComponentContext) error {
 	ctx.Log().Debugf("OpenSearch component post-upgrade")
-	return common.CheckIngressesAndCerts(ctx, o)
+	if err := common.CheckIngressesAndCerts(ctx, o); err != nil {
+		return err
+	}
+	return clearDataNodeExclusion(ctx)
 }
 
 // PostUpgrade OpenSearch post-upgrade processing
@@ -123,6 +138,26 @@ func (o opensearchComponent) updateElasticsearchResources(ctx spi.ComponentConte
 	return nil
 }
 
+// PreUninstall OpenSearch component pre-uninstall processing
+func (o opensearchComponent) PreUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+// Uninstall OpenSearch component uninstall processing, disables OpenSearch in the VMI
+func (o opensearchComponent) Uninstall(ctx spi.ComponentContext) error {
+	return common.DisableVMIComponent(ctx, disableFunc)
+}
+
+// PostUninstall OpenSearch component post-uninstall processing
+func (o opensearchComponent) PostUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+// IsUninstalled returns true if the OpenSearch master statefulset has been removed
+func (o opensearchComponent) IsUninstalled(ctx spi.ComponentContext) (bool, error) {
+	return !doesOSExist(ctx), nil
+}
+
 // IsEnabled opensearch-specific enabled check for installation
 func (o opensearchComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	comp := effectiveCR.Spec.Components.Elasticsearch
@@ -134,22 +169,37 @@ func (o opensearchComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (o opensearchComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow disabling active components
-	if err := o.isOpenSearchEnabled(old, new); err != nil {
+	// Reject changes of the persistence or StorageClass of the volumes, they can only grow when the StorageClass
+	// allows volume expansion
+	if err := common.CompareStorageOverrides(old, new, ComponentJSONName); err != nil {
 		return err
 	}
-	// Reject any other edits except InstallArgs
-	// Do not allow any updates to storage settings via the volumeClaimSpecTemplates/defaultVolumeSource
-	if err := common.CompareStorageOverrides(old, new, ComponentJSONName); err != nil {
+	if err := validateNodeVolumeUpdates(old, new); err != nil {
 		return err
 	}
 	// Reject edits that duplicate names of install args or node groups
-	return validateNoDuplicatedConfiguration(new)
+	if err := validateNoDuplicatedConfiguration(new); err != nil {
+		return err
+	}
+	return validateNodeVolumeSources(new)
 }
 
 // ValidateInstall checks if the specified Verrazzano CR is valid for this component to be installed
 func (o opensearchComponent) ValidateInstall(vz *vzapi.Verrazzano) error {
-	return validateNoDuplicatedConfiguration(vz)
+	if err := validateNoDuplicatedConfiguration(vz); err != nil {
+		return err
+	}
+	return validateNodeVolumeSources(vz)
+}
+
+// ValidateKubernetesSettings checks the kubernetes section of OpenSearch, which only supports the resources that
+// are used by the node groups without their own resources
+func (o opensearchComponent) ValidateKubernetesSettings(vz *vzapi.Verrazzano) error {
+	if vz.Spec.Components.Elasticsearch == nil {
+		return nil
+	}
+	return vzconfig.ValidateKubernetesSettings(ComponentJSONName, vz.Spec.Components.Elasticsearch.Kubernetes,
+		vzconfig.KubernetesResources)
 }
 
 // Name returns the component name
@@ -157,14 +207,6 @@ func (o opensearchComponent) Name() string {
 	return ComponentName
 }
 
-func (o opensearchComponent) isOpenSearchEnabled(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow disabling of any component post-install for now
-	if vzconfig.IsElasticsearchEnabled(old) && !vzconfig.IsElasticsearchEnabled(new) {
-		return fmt.Errorf("Disabling component OpenSearch not allowed")
-	}
-	return nil
-}
-
 // GetIngressNames - gets the names of the ingresses associated with this component
 func (o opensearchComponent) GetIngressNames(ctx spi.ComponentContext) []types.NamespacedName {
 	var ingressNames []types.NamespacedName
@@ -188,3 +230,31 @@ func (o opensearchComponent) GetCertificateNames(_ spi.ComponentContext) []types
 		},
 	}
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the OpenSearch nodes of each role.  The REST API is reached by the
+// components that send logs and traces to OpenSearch, and by the backup and restore jobs.
+func (o opensearchComponent) GetNetworkPolicies(ctx spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	storagePorts, err := getSnapshotStoragePorts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var policies []netv1.NetworkPolicy
+	for _, peer := range common.OpenSearchPeers() {
+		for key := range peer.PodSelector.MatchLabels {
+			role := strings.TrimPrefix(key, "opensearch.verrazzano.io/role-")
+			policies = append(policies, netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-opensearch-"+role, peer.PodSelector.MatchLabels,
+				[]netv1.NetworkPolicyIngressRule{
+					netpolicy.IngressFrom(common.OpenSearchPeers(), common.OpenSearchHTTPPort, common.OpenSearchTransportPort),
+					netpolicy.IngressFrom(common.ClusterPeers(), common.OpenSearchHTTPPort),
+					netpolicy.PrometheusIngress(common.EnvoyStatsPort),
+				},
+				[]netv1.NetworkPolicyEgressRule{
+					netpolicy.EgressTo(common.OpenSearchPeers(), common.OpenSearchHTTPPort, common.OpenSearchTransportPort),
+					// The snapshots are stored in an object store outside the cluster
+					netpolicy.EgressTo(nil, storagePorts...),
+					netpolicy.IstiodEgress(),
+				}))
+		}
+	}
+	return policies, nil
+}
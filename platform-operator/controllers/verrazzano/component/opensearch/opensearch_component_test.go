@@ -13,11 +13,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	spi2 "github.com/verrazzano/verrazzano/pkg/controller/errors"
+	"github.com/verrazzano/verrazzano/pkg/k8sutil"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -309,6 +311,9 @@ func getBoolPtr(b bool) *bool {
 }
 
 func Test_opensearchComponent_ValidateUpdate(t *testing.T) {
+	// The resized volumes have the default StorageClass, which does not allow volume expansion
+	k8sutil.SetFakeClient(k8sfake.NewSimpleClientset())
+	defer k8sutil.ClearFakeClient()
 	disabled := false
 	var pvc1Gi, _ = resource.ParseQuantity("1Gi")
 	var pvc2Gi, _ = resource.ParseQuantity("2Gi")
@@ -344,7 +349,7 @@ func Test_opensearchComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "change-installargs",
@@ -486,7 +491,7 @@ func Test_opensearchComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			// Change to OS installargs allowed, persistence changes are supported
@@ -514,3 +519,32 @@ func Test_opensearchComponent_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+// TestGetNetworkPolicies tests the GetNetworkPolicies call
+// GIVEN backups with S3 storage locations on the default port and on another port
+// WHEN GetNetworkPolicies is called
+// THEN the OpenSearch nodes can reach the ports of the storage locations
+func TestGetNetworkPolicies(t *testing.T) {
+	newBackup := func(name string, endpoint string) *vzapi.VerrazzanoBackup {
+		return &vzapi.VerrazzanoBackup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano-install", Name: name},
+			Spec: vzapi.VerrazzanoBackupSpec{StorageLocation: vzapi.BackupStorageLocation{
+				S3: &vzapi.S3StorageLocation{Endpoint: endpoint, Bucket: "bucket"},
+			}},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		newBackup("minio", "http://minio.example.com:9000"), newBackup("s3", "https://s3.example.com")).Build()
+	policies, err := NewComponent().(opensearchComponent).GetNetworkPolicies(spi.NewFakeContext(c, &vzapi.Verrazzano{}, false))
+	assert.NoError(t, err)
+	assert.Len(t, policies, 3)
+	for _, policy := range policies {
+		storage := policy.Spec.Egress[1]
+		assert.Empty(t, storage.To)
+		var ports []int
+		for _, port := range storage.Ports {
+			ports = append(ports, port.Port.IntValue())
+		}
+		assert.Equal(t, []int{443, 9000}, ports)
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"k8s.io/apimachinery/pkg/api/errors"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// allocationExcludeSetting is the cluster setting that moves the shards off the OpenSearch nodes that match it
+	allocationExcludeSetting = "cluster.routing.allocation.exclude._name"
+
+	// vmiNodePrefix is the prefix of the names of the OpenSearch nodes of the VMI, which are named after their pods
+	vmiNodePrefix = "vmi-system-"
+
+	// legacyDataNodeName is the name of the data nodes configured by the nodes.data install args
+	legacyDataNodeName = "es-data"
+
+	// excludedNodesAnnotation is the annotation of the VMI with the data nodes excluded from shard allocation by the
+	// operator, the exclusion is removed once they have left the cluster
+	excludedNodesAnnotation = "verrazzano.io/opensearch-excluded-nodes"
+)
+
+// openSearchRequestFunc sends a request to the OpenSearch cluster, for unit test purposes
+var openSearchRequestFunc = doOpenSearchRequest
+
+// dataNodeGroup is a group of OpenSearch nodes with the data role.  Data nodes that are not master nodes are
+// deployments, one for each replica, master nodes are the pods of a statefulset.
+type dataNodeGroup struct {
+	replicas    int32
+	statefulSet bool
+}
+
+// clusterHealth is the part of the OpenSearch cluster health used to wait for the relocation of the shards
+type clusterHealth struct {
+	Status           string `json:"status"`
+	RelocatingShards int    `json:"relocating_shards"`
+}
+
+// shardAllocation is the node a shard is allocated to, from the cat shards API
+type shardAllocation struct {
+	Index string `json:"index"`
+	Node  string `json:"node"`
+}
+
+// nodeName is the name of a node, from the cat nodes API
+type nodeName struct {
+	Name string `json:"name"`
+}
+
+// drainLeavingDataNodes excludes the data nodes that are removed by the update of the VMI from shard allocation, so
+// that their shards are relocated to the remaining data nodes before the VMI is updated.  It returns what the drain
+// is waiting for, or an empty string once the shards are relocated and the cluster health is green.
+func drainLeavingDataNodes(ctx spi.ComponentContext) (string, error) {
+	vmi, err := getVMI(ctx)
+	if err != nil || vmi == nil {
+		return "", err
+	}
+	leaving, err := getLeavingDataNodes(ctx, vmi)
+	if err != nil || len(leaving) == 0 {
+		return "", err
+	}
+	exclusion := strings.Join(leaving, ",")
+	if vmi.Annotations[excludedNodesAnnotation] != exclusion {
+		if vmi.Annotations == nil {
+			vmi.Annotations = map[string]string{}
+		}
+		vmi.Annotations[excludedNodesAnnotation] = exclusion
+		if err := ctx.Client().Update(context.TODO(), vmi); err != nil {
+			return "", err
+		}
+	}
+	current, err := getAllocationExclusion(ctx)
+	if err != nil {
+		return "", err
+	}
+	if current != exclusion {
+		ctx.Log().Infof("Excluding the OpenSearch data nodes %s from shard allocation before they are removed", exclusion)
+		if err := setAllocationExclusion(ctx, exclusion); err != nil {
+			return "", err
+		}
+	}
+
+	var shards []shardAllocation
+	if err := getOpenSearchJSON(ctx, "/_cat/shards?format=json&h=index,node", &shards); err != nil {
+		return "", err
+	}
+	remaining := 0
+	for _, shard := range shards {
+		if matchesAnyNode(shard.Node, leaving) {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		return fmt.Sprintf("Waiting for %d shards to be relocated from the OpenSearch data nodes %s that are removed", remaining, exclusion), nil
+	}
+
+	health := clusterHealth{}
+	if err := getOpenSearchJSON(ctx, "/_cluster/health", &health); err != nil {
+		return "", err
+	}
+	if health.Status != "green" || health.RelocatingShards > 0 {
+		return fmt.Sprintf("Waiting for the OpenSearch cluster health to be green before the data nodes %s are removed, the health is %s with %d relocating shards",
+			exclusion, health.Status, health.RelocatingShards), nil
+	}
+	return "", nil
+}
+
+// clearDataNodeExclusion removes the data nodes excluded by drainLeavingDataNodes from the shard allocation settings
+// once they have left the cluster.  The exclusion is left unchanged if it was changed by someone else.
+func clearDataNodeExclusion(ctx spi.ComponentContext) error {
+	vmi, err := getVMI(ctx)
+	if err != nil || vmi == nil || len(vmi.Annotations[excludedNodesAnnotation]) == 0 {
+		return err
+	}
+	exclusion := vmi.Annotations[excludedNodesAnnotation]
+	excluded := strings.Split(exclusion, ",")
+	var nodes []nodeName
+	if err := getOpenSearchJSON(ctx, "/_cat/nodes?format=json&h=name", &nodes); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if matchesAnyNode(node.Name, excluded) {
+			ctx.Log().Progressf("Waiting for the OpenSearch data node %s to leave the cluster", node.Name)
+			return ctrlerrors.RetryableError{Source: ComponentName, Operation: "Wait for the removed data nodes to leave the cluster"}
+		}
+	}
+	current, err := getAllocationExclusion(ctx)
+	if err != nil {
+		return err
+	}
+	if current == exclusion {
+		ctx.Log().Infof("Removing the OpenSearch data nodes %s from the shard allocation exclusion", exclusion)
+		if err := setAllocationExclusion(ctx, ""); err != nil {
+			return err
+		}
+	}
+	delete(vmi.Annotations, excludedNodesAnnotation)
+	return ctx.Client().Update(context.TODO(), vmi)
+}
+
+// getVMI returns the VMI, or nil if it doesn't exist
+func getVMI(ctx spi.ComponentContext) (*vmov1.VerrazzanoMonitoringInstance, error) {
+	vmi := common.NewVMI()
+	if err := ctx.Client().Get(context.TODO(), clipkg.ObjectKeyFromObject(vmi), vmi); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return vmi, nil
+}
+
+// getLeavingDataNodes returns the name patterns of the data nodes of the existing VMI that are removed by the update
+// of the VMI, because their node group is scaled down or removed, or no longer has the data role.  No nodes are
+// returned if no data node would remain, since there would be nowhere to relocate the shards.
+func getLeavingDataNodes(ctx spi.ComponentContext, existing *vmov1.VerrazzanoMonitoringInstance) ([]string, error) {
+	if !existing.Spec.Elasticsearch.Enabled {
+		return nil, nil
+	}
+	storage, err := common.FindStorageOverride(ctx.EffectiveCR())
+	if err != nil {
+		return nil, err
+	}
+	desired := existing.DeepCopy()
+	if err := updateFunc(ctx, storage, desired, existing); err != nil {
+		return nil, err
+	}
+
+	desiredGroups := getDataNodeGroups(desired)
+	var remaining int32
+	for _, group := range desiredGroups {
+		remaining += group.replicas
+	}
+	var leaving []string
+	for name, group := range getDataNodeGroups(existing) {
+		for i := desiredGroups[name].replicas; i < group.replicas; i++ {
+			if group.statefulSet {
+				leaving = append(leaving, fmt.Sprintf("%s%s-%d", vmiNodePrefix, name, i))
+			} else {
+				// The pods of the deployment of a data node have generated suffixes
+				leaving = append(leaving, fmt.Sprintf("%s%s-%d-*", vmiNodePrefix, name, i))
+			}
+		}
+	}
+	if len(leaving) > 0 && remaining == 0 {
+		ctx.Log().Oncef("Component %s is removing all the data nodes, the shards cannot be relocated", ComponentName)
+		return nil, nil
+	}
+	sort.Strings(leaving)
+	return leaving, nil
+}
+
+// getDataNodeGroups returns the node groups of the VMI with the data role, by name
+func getDataNodeGroups(vmi *vmov1.VerrazzanoMonitoringInstance) map[string]dataNodeGroup {
+	groups := map[string]dataNodeGroup{}
+	if vmi.Spec.Elasticsearch.DataNode.Replicas > 0 {
+		groups[legacyDataNodeName] = dataNodeGroup{replicas: vmi.Spec.Elasticsearch.DataNode.Replicas}
+	}
+	for _, node := range vmi.Spec.Elasticsearch.Nodes {
+		group := dataNodeGroup{replicas: node.Replicas}
+		isData := false
+		for _, role := range node.Roles {
+			switch role {
+			case vmov1.DataRole:
+				isData = true
+			case vmov1.MasterRole:
+				group.statefulSet = true
+			}
+		}
+		if isData && node.Replicas > 0 {
+			groups[node.Name] = group
+		}
+	}
+	return groups
+}
+
+// matchesAnyNode returns true if the node name matches one of the name patterns
+func matchesAnyNode(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// getAllocationExclusion returns the data nodes that are excluded from shard allocation
+func getAllocationExclusion(ctx spi.ComponentContext) (string, error) {
+	settings := map[string]map[string]interface{}{}
+	if err := getOpenSearchJSON(ctx, "/_cluster/settings?flat_settings=true", &settings); err != nil {
+		return "", err
+	}
+	exclusion, _ := settings["persistent"][allocationExcludeSetting].(string)
+	return exclusion, nil
+}
+
+// setAllocationExclusion sets the data nodes that are excluded from shard allocation, the setting is removed if the
+// exclusion is empty
+func setAllocationExclusion(ctx spi.ComponentContext, exclusion string) error {
+	value := "null"
+	if len(exclusion) > 0 {
+		value = fmt.Sprintf("%q", exclusion)
+	}
+	body := fmt.Sprintf(`{"persistent":{"%s":%s}}`, allocationExcludeSetting, value)
+	_, err := openSearchRequestFunc(ctx, "PUT", "/_cluster/settings", body)
+	return err
+}
+
+// getOpenSearchJSON sends a GET request to the OpenSearch cluster and decodes the JSON response
+func getOpenSearchJSON(ctx spi.ComponentContext, requestPath string, result interface{}) error {
+	output, err := openSearchRequestFunc(ctx, "GET", requestPath, "")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(output, result); err != nil {
+		return ctx.Log().ErrorfNewErr("Failed to parse the OpenSearch response of %s: %v", requestPath, err)
+	}
+	return nil
+}
+
+// doOpenSearchRequest sends a request to the OpenSearch cluster from a ready master node
+func doOpenSearchRequest(ctx spi.ComponentContext, method string, requestPath string, body string) ([]byte, error) {
+	pods, err := getPodsWithReadyContainer(ctx.Client(), containerName, clipkg.MatchingLabels{"app": workloadName}, clipkg.InNamespace(ComponentNamespace))
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, ctrlerrors.RetryableError{Source: ComponentName, Operation: "Wait for a ready OpenSearch master node"}
+	}
+	pod := pods[0]
+	httpPort, err := getNamedContainerPortOfContainer(pod, containerName, portName)
+	if err != nil {
+		return nil, err
+	}
+	curl := fmt.Sprintf("curl -s -k --fail -X%s http://localhost:%d%s", method, httpPort, requestPath)
+	if len(body) > 0 {
+		curl = fmt.Sprintf("%s --header 'Content-Type: application/json' -d '%s'", curl, body)
+	}
+	cmd := execCommand("kubectl", "exec", pod.Name, "-n", ComponentNamespace, "-c", containerName, "--", "sh", "-c", curl)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, ctx.Log().ErrorfNewErr("Failed the OpenSearch request %s %s: %v", method, requestPath, err)
+	}
+	return output, nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeOpenSearch records the requests sent to the OpenSearch cluster and returns the responses by request path
+type fakeOpenSearch struct {
+	responses map[string]string
+	requests  []string
+}
+
+func (f *fakeOpenSearch) request(_ spi.ComponentContext, method string, requestPath string, body string) ([]byte, error) {
+	f.requests = append(f.requests, method+" "+requestPath+" "+body)
+	return []byte(f.responses[requestPath]), nil
+}
+
+// TestGetLeavingDataNodes tests the getLeavingDataNodes func
+// GIVEN a VMI with data node groups, and a Verrazzano CR that scales down or removes them
+// WHEN getLeavingDataNodes is called
+// THEN ensure the name patterns of the data nodes that are removed are returned
+func TestGetLeavingDataNodes(t *testing.T) {
+	vmi := newScaleDownTestVMI()
+	ctx := spi.NewFakeContext(fake.NewClientBuilder().WithScheme(testScheme).Build(), newScaleDownTestCR("1", 2), false)
+
+	leaving, err := getLeavingDataNodes(ctx, vmi)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"vmi-system-data-group-0-*",
+		"vmi-system-data-group-1-*",
+		"vmi-system-es-data-1-*",
+		"vmi-system-es-data-2-*",
+		"vmi-system-master-data-2",
+	}, leaving)
+
+	// No data node is removed
+	ctx = spi.NewFakeContext(fake.NewClientBuilder().WithScheme(testScheme).Build(), newScaleDownTestCR("3", 3), false)
+	cr := ctx.EffectiveCR()
+	cr.Spec.Components.Elasticsearch.Nodes = append(cr.Spec.Components.Elasticsearch.Nodes, vzapi.OpenSearchNode{
+		Name: "data-group", Replicas: 2, Roles: []vmov1.NodeRole{vmov1.DataRole},
+	})
+	leaving, err = getLeavingDataNodes(ctx, vmi)
+	assert.NoError(t, err)
+	assert.Empty(t, leaving)
+
+	// All the data nodes are removed, there is nowhere to relocate the shards
+	ctx = spi.NewFakeContext(fake.NewClientBuilder().WithScheme(testScheme).Build(), newScaleDownTestCR("0", 0), false)
+	leaving, err = getLeavingDataNodes(ctx, vmi)
+	assert.NoError(t, err)
+	assert.Empty(t, leaving)
+}
+
+// TestDrainLeavingDataNodes tests the drainLeavingDataNodes func
+// GIVEN a VMI whose data nodes are scaled down
+// WHEN drainLeavingDataNodes is called while the shards are relocated
+// THEN ensure the leaving nodes are excluded from shard allocation, and the drain waits for the relocation of the
+// shards and a green cluster health
+func TestDrainLeavingDataNodes(t *testing.T) {
+	fakeOS := &fakeOpenSearch{responses: map[string]string{
+		"/_cluster/settings?flat_settings=true": `{"persistent":{},"transient":{}}`,
+		"/_cat/shards?format=json&h=index,node": `[{"index":"verrazzano-system","node":"vmi-system-es-data-0-6d8f9c7b5-x2x4z"},{"index":"verrazzano-system","node":"vmi-system-es-data-2-5c6d7f8b9-k8k4p"}]`,
+		"/_cluster/health":                      `{"status":"green","relocating_shards":0}`,
+	}}
+	openSearchRequestFunc = fakeOS.request
+	defer func() { openSearchRequestFunc = doOpenSearchRequest }()
+
+	vmi := newScaleDownTestVMI()
+	vmi.Spec.Elasticsearch.Nodes = nil
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(vmi).Build()
+	ctx := spi.NewFakeContext(c, newScaleDownTestCR("2", 0), false)
+
+	progress, err := drainLeavingDataNodes(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, progress, "Waiting for 1 shards to be relocated")
+	assert.Contains(t, fakeOS.requests, `PUT /_cluster/settings {"persistent":{"cluster.routing.allocation.exclude._name":"vmi-system-es-data-2-*"}}`)
+	actual := &vmov1.VerrazzanoMonitoringInstance{}
+	assert.NoError(t, c.Get(context.TODO(), clipkg.ObjectKeyFromObject(vmi), actual))
+	assert.Equal(t, "vmi-system-es-data-2-*", actual.Annotations[excludedNodesAnnotation])
+
+	// The shards are relocated, the cluster health is not green yet
+	fakeOS.responses["/_cluster/settings?flat_settings=true"] = `{"persistent":{"cluster.routing.allocation.exclude._name":"vmi-system-es-data-2-*"},"transient":{}}`
+	fakeOS.responses["/_cat/shards?format=json&h=index,node"] = `[{"index":"verrazzano-system","node":"vmi-system-es-data-0-6d8f9c7b5-x2x4z"}]`
+	fakeOS.responses["/_cluster/health"] = `{"status":"yellow","relocating_shards":1}`
+	fakeOS.requests = nil
+	progress, err = drainLeavingDataNodes(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, progress, "cluster health to be green")
+	for _, request := range fakeOS.requests {
+		assert.NotContains(t, request, "PUT")
+	}
+
+	fakeOS.responses["/_cluster/health"] = `{"status":"green","relocating_shards":0}`
+	progress, err = drainLeavingDataNodes(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, progress)
+
+	// PreInstall waits until the data nodes are drained
+	fakeOS.responses["/_cat/shards?format=json&h=index,node"] = `[{"index":"verrazzano-system","node":"vmi-system-es-data-2-5c6d7f8b9-k8k4p"}]`
+	err = NewComponent().PreInstall(ctx)
+	assert.IsType(t, ctrlerrors.RetryableError{}, err)
+	assert.NotEmpty(t, err.(ctrlerrors.RetryableError).Progress)
+}
+
+// TestClearDataNodeExclusion tests the clearDataNodeExclusion func
+// GIVEN a VMI with data nodes excluded from shard allocation by the operator
+// WHEN clearDataNodeExclusion is called
+// THEN ensure the exclusion is removed once the excluded nodes have left the cluster
+func TestClearDataNodeExclusion(t *testing.T) {
+	fakeOS := &fakeOpenSearch{responses: map[string]string{
+		"/_cluster/settings?flat_settings=true": `{"persistent":{"cluster.routing.allocation.exclude._name":"vmi-system-es-data-2-*"},"transient":{}}`,
+		"/_cat/nodes?format=json&h=name":        `[{"name":"vmi-system-es-data-0-6d8f9c7b5-x2x4z"},{"name":"vmi-system-es-data-2-5c6d7f8b9-k8k4p"}]`,
+	}}
+	openSearchRequestFunc = fakeOS.request
+	defer func() { openSearchRequestFunc = doOpenSearchRequest }()
+
+	vmi := newScaleDownTestVMI()
+	vmi.Annotations = map[string]string{excludedNodesAnnotation: "vmi-system-es-data-2-*"}
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(vmi).Build()
+	ctx := spi.NewFakeContext(c, &vzapi.Verrazzano{}, false)
+
+	err := clearDataNodeExclusion(ctx)
+	assert.IsType(t, ctrlerrors.RetryableError{}, err)
+
+	fakeOS.responses["/_cat/nodes?format=json&h=name"] = `[{"name":"vmi-system-es-data-0-6d8f9c7b5-x2x4z"}]`
+	err = clearDataNodeExclusion(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, fakeOS.requests, `PUT /_cluster/settings {"persistent":{"cluster.routing.allocation.exclude._name":null}}`)
+	actual := &vmov1.VerrazzanoMonitoringInstance{}
+	assert.NoError(t, c.Get(context.TODO(), clipkg.ObjectKeyFromObject(vmi), actual))
+	assert.NotContains(t, actual.Annotations, excludedNodesAnnotation)
+
+	// Nothing is sent to OpenSearch once the exclusion is removed
+	fakeOS.requests = nil
+	assert.NoError(t, clearDataNodeExclusion(ctx))
+	assert.Empty(t, fakeOS.requests)
+}
+
+// newScaleDownTestVMI returns a VMI with 3 legacy data nodes, a data node group and a master node group with the data
+// role
+func newScaleDownTestVMI() *vmov1.VerrazzanoMonitoringInstance {
+	vmi := common.NewVMI()
+	vmi.Spec.Elasticsearch = vmov1.Elasticsearch{
+		Enabled:    true,
+		MasterNode: vmov1.ElasticsearchNode{Replicas: 1},
+		DataNode:   vmov1.ElasticsearchNode{Replicas: 3},
+		Nodes: []vmov1.ElasticsearchNode{
+			{Name: "data-group", Replicas: 2, Roles: []vmov1.NodeRole{vmov1.DataRole}},
+			{Name: "master-data", Replicas: 3, Roles: []vmov1.NodeRole{vmov1.MasterRole, vmov1.DataRole}},
+		},
+	}
+	return vmi
+}
+
+// newScaleDownTestCR returns a Verrazzano CR with legacy data node replicas and master node group replicas
+func newScaleDownTestCR(dataReplicas string, masterDataReplicas int32) *vzapi.Verrazzano {
+	cr := &vzapi.Verrazzano{
+		Spec: vzapi.VerrazzanoSpec{
+			Components: vzapi.ComponentSpec{
+				Elasticsearch: &vzapi.ElasticsearchComponent{
+					Enabled: getBoolPtr(true),
+					ESInstallArgs: []vzapi.InstallArgs{
+						{Name: "nodes.master.replicas", Value: "1"},
+						{Name: "nodes.data.replicas", Value: dataReplicas},
+					},
+				},
+			},
+		},
+	}
+	if masterDataReplicas > 0 {
+		cr.Spec.Components.Elasticsearch.Nodes = []vzapi.OpenSearchNode{
+			{Name: "master-data", Replicas: masterDataReplicas, Roles: []vmov1.NodeRole{vmov1.MasterRole, vmov1.DataRole}},
+		}
+	}
+	return cr
+}
@@ -6,6 +6,7 @@ package opensearch
 import (
 	"fmt"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 )
 
 //entryTracker is a Set like construct to track if a value was seen already
@@ -68,3 +69,46 @@ func validateNoDuplicateNodeGroups(opensearch *vzapi.ElasticsearchComponent) err
 	}
 	return nil
 }
+
+//validateNodeVolumeSources validates the volume sources of the node groups, which can not be used with a storage size
+func validateNodeVolumeSources(vz *vzapi.Verrazzano) error {
+	if vz.Spec.Components.Elasticsearch == nil {
+		return nil
+	}
+	for _, node := range vz.Spec.Components.Elasticsearch.Nodes {
+		if node.VolumeSource == nil {
+			continue
+		}
+		name := fmt.Sprintf("%s node group %s", ComponentJSONName, node.Name)
+		if node.Storage != nil {
+			return fmt.Errorf("The %s can not have both a storage size and a volume source", name)
+		}
+		if err := common.ValidateVMIVolumeSource(vz, node.VolumeSource, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//validateNodeVolumeUpdates compares the volume sources of the node groups that are kept by an update, the node groups
+// with a storage size are left to the VMO
+func validateNodeVolumeUpdates(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
+	if old.Spec.Components.Elasticsearch == nil || new.Spec.Components.Elasticsearch == nil {
+		return nil
+	}
+	oldNodes := map[string]vzapi.OpenSearchNode{}
+	for _, node := range old.Spec.Components.Elasticsearch.Nodes {
+		oldNodes[node.Name] = node
+	}
+	for _, node := range new.Spec.Components.Elasticsearch.Nodes {
+		oldNode, ok := oldNodes[node.Name]
+		if !ok || oldNode.Storage != nil || node.Storage != nil {
+			continue
+		}
+		name := fmt.Sprintf("%s node group %s", ComponentJSONName, node.Name)
+		if err := common.CompareVMIVolumeSources(old, new, oldNode.VolumeSource, node.VolumeSource, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,7 +6,14 @@ package opensearch
 import (
 	"fmt"
 	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano/pkg/k8sutil"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"testing"
 )
 
@@ -122,3 +129,116 @@ func TestValidateNoDuplicatedConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func createNGWithVolume(name string, claimName string) vzapi.OpenSearchNode {
+	node := createNG(name, 3, []vmov1.NodeRole{vmov1.DataRole})
+	node.VolumeSource = &corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+	}
+	return node
+}
+
+func createVZWithTemplate(size string, nodes ...vzapi.OpenSearchNode) *vzapi.Verrazzano {
+	vz := createVZ(&vzapi.ElasticsearchComponent{Nodes: nodes})
+	vz.Spec.VolumeClaimSpecTemplates = []vzapi.VolumeClaimSpecTemplate{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "data"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		},
+	}
+	return vz
+}
+
+func TestValidateNodeVolumeSources(t *testing.T) {
+	withStorage := createNGWithVolume("data", "data")
+	withStorage.Storage = &vzapi.OpenSearchNodeStorage{Size: "100Gi"}
+	var tests = []struct {
+		name     string
+		vz       *vzapi.Verrazzano
+		hasError bool
+	}{
+		{
+			"no error when component has no node groups",
+			emptyComponent,
+			false,
+		},
+		{
+			"no error when node group references a volume claim template",
+			createVZWithTemplate("100Gi", createNGWithVolume("data", "data")),
+			false,
+		},
+		{
+			"error when node group references a missing volume claim template",
+			createVZWithTemplate("100Gi", createNGWithVolume("data", "missing")),
+			true,
+		},
+		{
+			"error when node group has both a storage size and a volume source",
+			createVZWithTemplate("100Gi", withStorage),
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateNodeVolumeSources(tt.vz); (err != nil) != tt.hasError {
+				t.Errorf("validateNodeVolumeSources() error = %v, hasError: %v", err, tt.hasError)
+			}
+		})
+	}
+}
+
+func TestValidateNodeVolumeUpdates(t *testing.T) {
+	expansion := true
+	k8sutil.SetFakeClient(k8sfake.NewSimpleClientset(&storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "standard", Annotations: map[string]string{common.DefaultStorageClassAnnotation: "true"}},
+		AllowVolumeExpansion: &expansion,
+	}))
+	defer k8sutil.ClearFakeClient()
+
+	emptyDirNode := createNG("data", 3, []vmov1.NodeRole{vmov1.DataRole})
+	emptyDirNode.VolumeSource = &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	var tests = []struct {
+		name     string
+		old      *vzapi.Verrazzano
+		new      *vzapi.Verrazzano
+		hasError bool
+	}{
+		{
+			"no error when the volume of a node group grows",
+			createVZWithTemplate("100Gi", createNGWithVolume("data", "data")),
+			createVZWithTemplate("200Gi", createNGWithVolume("data", "data")),
+			false,
+		},
+		{
+			"error when the volume of a node group shrinks",
+			createVZWithTemplate("200Gi", createNGWithVolume("data", "data")),
+			createVZWithTemplate("100Gi", createNGWithVolume("data", "data")),
+			true,
+		},
+		{
+			"error when the volume of a node group becomes an emptyDir",
+			createVZWithTemplate("100Gi", createNGWithVolume("data", "data")),
+			createVZWithTemplate("100Gi", emptyDirNode),
+			true,
+		},
+		{
+			"no error when a node group with a smaller volume is added",
+			createVZWithTemplate("200Gi", createNGWithVolume("data", "data")),
+			createVZWithTemplate("100Gi", createNGWithVolume("data2", "data")),
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateNodeVolumeUpdates(tt.old, tt.new); (err != nil) != tt.hasError {
+				t.Errorf("validateNodeVolumeUpdates() error = %v, hasError: %v", err, tt.hasError)
+			}
+		})
+	}
+}
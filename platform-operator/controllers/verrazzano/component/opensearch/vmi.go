@@ -29,6 +29,11 @@ func updateFunc(ctx spi.ComponentContext, storage *common.ResourceRequestValues,
 	return nil
 }
 
+// disableFunc mutates the VMI struct to disable the OpenSearch component
+func disableFunc(vmi *vmov1.VerrazzanoMonitoringInstance) {
+	vmi.Spec.Elasticsearch.Enabled = false
+}
+
 func hasNodeStorageOverride(cr *vzapi.Verrazzano, override string) bool {
 	openSearch := cr.Spec.Components.Elasticsearch
 	if openSearch == nil {
@@ -54,6 +59,11 @@ func newOpenSearch(cr *vzapi.Verrazzano, storage *common.ResourceRequestValues,
 		return &vmov1.Elasticsearch{}, nil
 	}
 	opensearchComponent := cr.Spec.Components.Elasticsearch
+	// adapt the VPO node list to VMI node list
+	nodes, err := nodeAdapter(cr, vmi, opensearchComponent.Nodes, storage)
+	if err != nil {
+		return nil, err
+	}
 	opensearch := &vmov1.Elasticsearch{
 		Enabled: opensearchComponent.Enabled != nil && *opensearchComponent.Enabled,
 		Storage: vmov1.Storage{},
@@ -70,8 +80,14 @@ func newOpenSearch(cr *vzapi.Verrazzano, storage *common.ResourceRequestValues,
 				RequestMemory: "2.5Gi",
 			},
 		},
-		// adapt the VPO node list to VMI node list
-		Nodes: nodeAdapter(vmi, cr.Spec.Components.Elasticsearch.Nodes, storage),
+		Nodes: nodes,
+	}
+
+	// Node groups without their own resources use the resources of the kubernetes section
+	for i, node := range opensearchComponent.Nodes {
+		if node.Resources == nil {
+			common.SetResources(opensearchComponent.Kubernetes, &opensearch.Nodes[i].Resources)
+		}
 	}
 
 	// Proxy any ISM policies to the VMI
@@ -170,7 +186,7 @@ func populateOpenSearchFromInstallArgs(opensearch *vmov1.Elasticsearch, opensear
 	return nil
 }
 
-func nodeAdapter(vmi *vmov1.VerrazzanoMonitoringInstance, nodes []vzapi.OpenSearchNode, storage *common.ResourceRequestValues) []vmov1.ElasticsearchNode {
+func nodeAdapter(cr *vzapi.Verrazzano, vmi *vmov1.VerrazzanoMonitoringInstance, nodes []vzapi.OpenSearchNode, storage *common.ResourceRequestValues) ([]vmov1.ElasticsearchNode, error) {
 	getQuantity := func(q *resource.Quantity) string {
 		if q == nil || q.String() == "0" {
 			return ""
@@ -179,9 +195,14 @@ func nodeAdapter(vmi *vmov1.VerrazzanoMonitoringInstance, nodes []vzapi.OpenSear
 	}
 	var vmoNodes []vmov1.ElasticsearchNode
 	for _, node := range nodes {
+		// the volume source of the node group takes precedence over the DefaultVolumeSource
+		nodeStorage, err := common.GetComponentStorageOverride(cr, node.VolumeSource, storage)
+		if err != nil {
+			return nil, err
+		}
 		var storageSize string
-		if storage != nil && storage.Storage != "" {
-			storageSize = storage.Storage
+		if nodeStorage != nil && nodeStorage.Storage != "" {
+			storageSize = nodeStorage.Storage
 		}
 		if node.Storage != nil {
 			storageSize = node.Storage.Size
@@ -207,7 +228,7 @@ func nodeAdapter(vmi *vmov1.VerrazzanoMonitoringInstance, nodes []vzapi.OpenSear
 		setPVCNames(vmi, &vmoNode)
 		vmoNodes = append(vmoNodes, vmoNode)
 	}
-	return vmoNodes
+	return vmoNodes, nil
 }
 
 //setPVCNames persists any PVC names from an existing VMI
@@ -13,6 +13,7 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
@@ -187,6 +188,47 @@ func TestCreateOrUpdateVMI(t *testing.T) {
 	assert.EqualValues(t, 3, vmi.Spec.Elasticsearch.DataNode.Replicas)
 }
 
+// TestCreateOrUpdateVMIWithNodeVolumeSource tests a new VMI resources is created in K8s with the volume source of a
+// node group
+// GIVEN a Verrazzano CR with a node group that references a volume claim template
+// WHEN I create a new VMI resource
+// THEN the node group has the size of the template, and the VMI has its StorageClass
+func TestCreateOrUpdateVMIWithNodeVolumeSource(t *testing.T) {
+	fast := "fast"
+	cr := vmiEnabledCR.DeepCopy()
+	cr.Spec.VolumeClaimSpecTemplates = []vzapi.VolumeClaimSpecTemplate{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "opensearch"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &fast,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("200Gi")},
+				},
+			},
+		},
+	}
+	cr.Spec.Components.Elasticsearch.Nodes = []vzapi.OpenSearchNode{
+		{
+			Name:     "data",
+			Replicas: 3,
+			Roles:    []vmov1.NodeRole{vmov1.DataRole},
+			VolumeSource: &corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "opensearch"},
+			},
+		},
+	}
+	ctx := spi.NewFakeContext(fake.NewClientBuilder().WithScheme(testScheme).Build(), cr, false)
+	err := common.CreateOrUpdateVMI(ctx, updateFunc)
+	assert.NoError(t, err)
+	vmi := &vmov1.VerrazzanoMonitoringInstance{}
+	namespacedName := types.NamespacedName{Name: system, Namespace: globalconst.VerrazzanoSystemNamespace}
+	err = ctx.Client().Get(context.TODO(), namespacedName, vmi)
+	assert.NoError(t, err)
+	assert.Equal(t, "fast", *vmi.Spec.StorageClass)
+	assert.Len(t, vmi.Spec.Elasticsearch.Nodes, 1)
+	assert.Equal(t, "200Gi", vmi.Spec.Elasticsearch.Nodes[0].Storage.Size)
+}
+
 // TestCreateOrUpdateVMINoNGINX tests a new VMI resources is created in K8s according to the CR
 // GIVEN a Verrazzano CR
 // WHEN I create a new VMI resource and NGINX is not enabled
@@ -321,7 +363,8 @@ func TestNodeAdapter(t *testing.T) {
 		},
 	}
 
-	adaptedNodes := nodeAdapter(vmi, nodes, &common.ResourceRequestValues{Storage: vmiStorage})
+	adaptedNodes, err := nodeAdapter(&vzapi.Verrazzano{}, vmi, nodes, &common.ResourceRequestValues{Storage: vmiStorage})
+	assert.NoError(t, err)
 	compareNodes := func(n1, n2 *vmov1.ElasticsearchNode) {
 		assert.Equal(t, n1.Name, n2.Name)
 		assert.Equal(t, n1.Replicas, n2.Replicas)
@@ -4,13 +4,14 @@
 package opensearchdashboards
 
 import (
-	"fmt"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/vmo"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -118,6 +119,26 @@ func (d opensearchDashboardsComponent) PostUpgrade(ctx spi.ComponentContext) err
 
 }
 
+// PreUninstall OpenSearch-Dashboards pre-uninstall processing
+func (d opensearchDashboardsComponent) PreUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+// Uninstall OpenSearch-Dashboards uninstall processing, disables OpenSearch-Dashboards in the VMI
+func (d opensearchDashboardsComponent) Uninstall(ctx spi.ComponentContext) error {
+	return common.DisableVMIComponent(ctx, disableFunc)
+}
+
+// PostUninstall OpenSearch-Dashboards post-uninstall processing
+func (d opensearchDashboardsComponent) PostUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+// IsUninstalled returns true if the OpenSearch-Dashboards deployment has been removed
+func (d opensearchDashboardsComponent) IsUninstalled(ctx spi.ComponentContext) (bool, error) {
+	return !doesOSDExist(ctx), nil
+}
+
 // IsEnabled OpenSearch-Dashboards specific enabled check for installation
 func (d opensearchDashboardsComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	comp := effectiveCR.Spec.Components.Kibana
@@ -129,12 +150,8 @@ func (d opensearchDashboardsComponent) IsEnabled(effectiveCR *vzapi.Verrazzano)
 
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (d opensearchDashboardsComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow disabling active components
-	if err := d.isOpenSearchDashboardEnabled(old, new); err != nil {
-		return err
-	}
-	// Reject any other edits except InstallArgs
-	// Do not allow any updates to storage settings via the volumeClaimSpecTemplates/defaultVolumeSource
+	// Reject changes of the persistence or StorageClass of the VMI volumes, they can only grow when the StorageClass
+	// allows volume expansion
 	if err := common.CompareStorageOverrides(old, new, ComponentJSONName); err != nil {
 		return err
 	}
@@ -146,19 +163,21 @@ func (d opensearchDashboardsComponent) ValidateInstall(_ *vzapi.Verrazzano) erro
 	return nil
 }
 
+// ValidateKubernetesSettings checks the kubernetes section of OpenSearch-Dashboards, the VMI only supports the
+// replicas and resources
+func (d opensearchDashboardsComponent) ValidateKubernetesSettings(vz *vzapi.Verrazzano) error {
+	if vz.Spec.Components.Kibana == nil {
+		return nil
+	}
+	return vzconfig.ValidateKubernetesSettings(ComponentJSONName, vz.Spec.Components.Kibana.Kubernetes,
+		vzconfig.KubernetesReplicas, vzconfig.KubernetesResources)
+}
+
 // Name returns the component name
 func (d opensearchDashboardsComponent) Name() string {
 	return ComponentName
 }
 
-func (d opensearchDashboardsComponent) isOpenSearchDashboardEnabled(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow disabling of any component post-install for now
-	if vzconfig.IsKibanaEnabled(old) && !vzconfig.IsKibanaEnabled(new) {
-		return fmt.Errorf("Disabling component OpenSearch-Dashboards not allowed")
-	}
-	return nil
-}
-
 // GetIngressNames - gets the names of the ingresses associated with this component
 func (d opensearchDashboardsComponent) GetIngressNames(ctx spi.ComponentContext) []types.NamespacedName {
 	var ingressNames []types.NamespacedName
@@ -182,3 +201,19 @@ func (d opensearchDashboardsComponent) GetCertificateNames(_ spi.ComponentContex
 		},
 	}
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of OpenSearch Dashboards, which queries OpenSearch
+func (d opensearchDashboardsComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-opensearch-dashboards", map[string]string{"app": "system-kibana"},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.AuthProxyPeers(), 5601),
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", map[string]string{"k8s-app": "verrazzano-monitoring-operator"})}, 5601),
+				netpolicy.PrometheusIngress(common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(common.OpenSearchPeers(), common.OpenSearchHTTPPort),
+				netpolicy.IstiodEgress(),
+			}),
+	}, nil
+}
@@ -13,11 +13,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	spi2 "github.com/verrazzano/verrazzano/pkg/controller/errors"
+	"github.com/verrazzano/verrazzano/pkg/k8sutil"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -303,6 +305,9 @@ func getBoolPtr(b bool) *bool {
 }
 
 func Test_opensearchdashboardComponent_ValidateUpdate(t *testing.T) {
+	// The resized volumes have the default StorageClass, which does not allow volume expansion
+	k8sutil.SetFakeClient(k8sfake.NewSimpleClientset())
+	defer k8sutil.ClearFakeClient()
 	disabled := false
 	var pvc1Gi, _ = resource.ParseQuantity("1Gi")
 	var pvc2Gi, _ = resource.ParseQuantity("2Gi")
@@ -338,7 +343,7 @@ func Test_opensearchdashboardComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "change-installargs",
@@ -482,7 +487,7 @@ func Test_opensearchdashboardComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			// Change to OS installargs allowed, persistence changes are supported
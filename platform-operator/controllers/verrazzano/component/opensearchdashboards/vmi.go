@@ -17,6 +17,11 @@ func updateFunc(ctx spi.ComponentContext, storage *common.ResourceRequestValues,
 	return nil
 }
 
+// disableFunc mutates the VMI struct to disable the OpenSearch-Dashboards component
+func disableFunc(vmi *vmov1.VerrazzanoMonitoringInstance) {
+	vmi.Spec.Kibana.Enabled = false
+}
+
 func newOpenSearchDashboards(cr *vzapi.Verrazzano) vmov1.Kibana {
 	if cr.Spec.Components.Kibana == nil {
 		return vmov1.Kibana{}
@@ -28,5 +33,9 @@ func newOpenSearchDashboards(cr *vzapi.Verrazzano) vmov1.Kibana {
 			RequestMemory: "192Mi",
 		},
 	}
+	if k8s := kibanaValues.Kubernetes; k8s != nil && k8s.Replicas > 0 {
+		opensearchDashboards.Replicas = int32(k8s.Replicas)
+	}
+	common.SetResources(kibanaValues.Kubernetes, &opensearchDashboards.Resources)
 	return opensearchDashboards
 }
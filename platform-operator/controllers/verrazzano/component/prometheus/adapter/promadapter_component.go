@@ -8,9 +8,12 @@ import (
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	netv1 "k8s.io/api/networking/v1"
 )
 
 // ComponentName is the name of the component
@@ -41,10 +44,40 @@ func NewComponent() spi.Component {
 			ImagePullSecretKeyname:  "image.pullSecrets[0]",
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), "prometheus-adapter-values.yaml"),
 			Dependencies:            []string{},
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:                   "replicas",
+					Affinity:                   "affinity",
+					Tolerations:                "tolerations",
+					NodeSelector:               "nodeSelector",
+					PriorityClassName:          "priorityClassName",
+					Resources:                  "resources",
+					PodDisruptionBudget:        "podDisruptionBudget",
+					PodDisruptionBudgetEnabled: "podDisruptionBudget.enabled",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the Prometheus Adapter Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.PrometheusAdapter; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the Prometheus Adapter component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.PrometheusAdapter; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // IsEnabled returns true if the Prometheus Adapter is enabled or if the component is not specified
 // in the Verrazzano CR.
 func (c prometheusAdapterComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
@@ -67,3 +100,17 @@ func (c prometheusAdapterComponent) IsReady(ctx spi.ComponentContext) bool {
 func (c prometheusAdapterComponent) PreInstall(ctx spi.ComponentContext) error {
 	return preInstall(ctx)
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the Prometheus Adapter, which serves the metrics API of the API
+// server from Prometheus
+func (c prometheusAdapterComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-prometheus-adapter", map[string]string{"app.kubernetes.io/instance": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(nil, 6443),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(netpolicy.PrometheusPeers(), common.PrometheusPort),
+			}),
+	}, nil
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	netv1 "k8s.io/api/networking/v1"
 )
 
 // ComponentName is the name of the component
@@ -41,10 +43,39 @@ func NewComponent() spi.Component {
 			ImagePullSecretKeyname:  "imagePullSecrets[0].name",
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), "kube-state-metrics-values.yaml"),
 			Dependencies:            []string{},
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:            "replicas",
+					Affinity:            "affinity",
+					Tolerations:         "tolerations",
+					NodeSelector:        "nodeSelector",
+					PriorityClassName:   "priorityClassName",
+					Resources:           "resources",
+					PodDisruptionBudget: "podDisruptionBudget",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the kube-state-metrics Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.KubeStateMetrics; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the kube-state-metrics component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.KubeStateMetrics; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // IsEnabled returns true if kube-state-metrics is enabled or if the component is not specified
 // in the Verrazzano CR.
 func (c kubeStateMetricsComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
@@ -67,3 +98,14 @@ func (c kubeStateMetricsComponent) IsReady(ctx spi.ComponentContext) bool {
 func (c kubeStateMetricsComponent) PreInstall(ctx spi.ComponentContext) error {
 	return preInstall(ctx)
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of kube-state-metrics, which is scraped by Prometheus
+func (c kubeStateMetricsComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-kube-state-metrics", map[string]string{"app.kubernetes.io/instance": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.PrometheusIngress(8080, 8081),
+			},
+			nil),
+	}, nil
+}
@@ -13,7 +13,9 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	netv1 "k8s.io/api/networking/v1"
 )
 
 // ComponentName is the name of the component
@@ -47,10 +49,37 @@ func NewComponent() spi.Component {
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), valuesFile),
 			Dependencies:            []string{},
 			AppendOverridesFunc:     AppendOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Affinity:          "affinity",
+					Tolerations:       "tolerations",
+					NodeSelector:      "nodeSelector",
+					PriorityClassName: "priorityClassName",
+					Resources:         "resources",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the Prometheus Node Exporter Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.PrometheusNodeExporter; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the Prometheus Node Exporter component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.PrometheusNodeExporter; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // IsEnabled returns true if the Prometheus Node-Exporter is enabled or if the component is not specified
 // in the Verrazzano CR.
 func (c prometheusNodeExporterComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
@@ -85,3 +114,14 @@ func AppendOverrides(ctx spi.ComponentContext, _ string, _ string, _ string, kvs
 	}
 	return kvs, nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the Prometheus Node Exporter, which is scraped by Prometheus
+func (c prometheusNodeExporterComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-prometheus-node-exporter", map[string]string{"release": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.PrometheusIngress(9100),
+			},
+			nil),
+	}, nil
+}
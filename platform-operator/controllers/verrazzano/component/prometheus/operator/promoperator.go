@@ -84,9 +84,20 @@ func AppendOverrides(ctx spi.ComponentContext, _ string, _ string, _ string, kvs
 	return kvs, nil
 }
 
-// GetHelmOverrides appends Helm value overrides for the Prometheus Operator Helm chart
-func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.Overrides {
-	return ctx.EffectiveCR().Spec.Components.PrometheusOperator.ValueOverrides
+// GetHelmOverrides returns the Helm value overrides for the Prometheus Operator Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.PrometheusOperator; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the Prometheus Operator component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.PrometheusOperator; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
 }
 
 // appendCustomImageOverrides takes a list of subcomponent image names and appends it to the given Helm overrides
@@ -8,9 +8,12 @@ import (
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	netv1 "k8s.io/api/networking/v1"
 )
 
 // ComponentName is the name of the component
@@ -43,6 +46,34 @@ func NewComponent() spi.Component {
 			Dependencies:            []string{},
 			AppendOverridesFunc:     AppendOverrides,
 			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Affinity:          "prometheusOperator.affinity",
+					Tolerations:       "prometheusOperator.tolerations",
+					NodeSelector:      "prometheusOperator.nodeSelector",
+					PriorityClassName: "prometheusOperator.priorityClassName",
+					Resources:         "prometheusOperator.resources",
+				},
+				{
+					Affinity:          "prometheusOperator.admissionWebhooks.patch.affinity",
+					Tolerations:       "prometheusOperator.admissionWebhooks.patch.tolerations",
+					NodeSelector:      "prometheusOperator.admissionWebhooks.patch.nodeSelector",
+					PriorityClassName: "prometheusOperator.admissionWebhooks.patch.priorityClassName",
+				},
+				{
+					Affinity:          "prometheus.prometheusSpec.affinity",
+					Tolerations:       "prometheus.prometheusSpec.tolerations",
+					NodeSelector:      "prometheus.prometheusSpec.nodeSelector",
+					PriorityClassName: "prometheus.prometheusSpec.priorityClassName",
+				},
+				{
+					Affinity:          "alertmanager.alertmanagerSpec.affinity",
+					Tolerations:       "alertmanager.alertmanagerSpec.tolerations",
+					NodeSelector:      "alertmanager.alertmanagerSpec.nodeSelector",
+					PriorityClassName: "alertmanager.alertmanagerSpec.priorityClassName",
+				},
+			},
 		},
 	}
 }
@@ -70,6 +101,15 @@ func (c prometheusComponent) PreInstall(ctx spi.ComponentContext) error {
 	return preInstall(ctx)
 }
 
+// PostUninstall deletes the verrazzano-monitoring namespace, the other monitoring components are uninstalled first
+func (c prometheusComponent) PostUninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debug("Prometheus Operator PostUninstall dry run")
+		return nil
+	}
+	return common.DeleteNamespaces(ctx, ComponentNamespace)
+}
+
 // ValidateInstall verifies the installation of the Verrazzano object
 func (c prometheusComponent) ValidateInstall(effectiveCR *vzapi.Verrazzano) error {
 	if effectiveCR.Spec.Components.PrometheusOperator != nil {
@@ -85,3 +125,33 @@ func (c prometheusComponent) ValidateUpgrade(effectiveCR *vzapi.Verrazzano) erro
 	}
 	return nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the Prometheus Operator, and of the Prometheus and Alertmanager
+// instances it manages.  Prometheus scrapes the pods and nodes of the cluster.
+func (c prometheusComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-prometheus-operator", map[string]string{"app.kubernetes.io/instance": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				// The webhook is called by the API server
+				netpolicy.IngressFrom(nil, 10250),
+				netpolicy.PrometheusIngress(8080),
+			},
+			nil),
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-prometheus", map[string]string{"app.kubernetes.io/name": "prometheus"},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.ClusterPeers(), common.PrometheusPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(nil),
+			}),
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-alertmanager", map[string]string{"app.kubernetes.io/name": "alertmanager"},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(netpolicy.PrometheusPeers(), 9093),
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", map[string]string{"app.kubernetes.io/name": "alertmanager"})}, 9094),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				// The receivers of the alerts
+				netpolicy.EgressTo(nil),
+			}),
+	}, nil
+}
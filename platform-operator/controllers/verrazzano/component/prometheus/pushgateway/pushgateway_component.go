@@ -4,7 +4,9 @@
 package pushgateway
 
 import (
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"path/filepath"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
@@ -12,6 +14,7 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	netv1 "k8s.io/api/networking/v1"
 )
 
 // ComponentName is the name of the component
@@ -42,10 +45,39 @@ func NewComponent() spi.Component {
 			ImagePullSecretKeyname:  secret.DefaultImagePullSecretKeyName,
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), "prometheus-pushgateway-values.yaml"),
 			Dependencies:            []string{},
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:            "replicaCount",
+					Affinity:            "affinity",
+					Tolerations:         "tolerations",
+					NodeSelector:        "nodeSelector",
+					PriorityClassName:   "priorityClassName",
+					Resources:           "resources",
+					PodDisruptionBudget: "podDisruptionBudget",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the Prometheus Pushgateway Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.PrometheusPushgateway; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the Prometheus Pushgateway component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.PrometheusPushgateway; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
 // IsEnabled returns true if the Prometheus PrometheusPushgateway is enabled or if the component is not specified
 // in the Verrazzano CR.
 func (c prometheusPushgatewayComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
@@ -68,3 +100,15 @@ func (c prometheusPushgatewayComponent) IsReady(ctx spi.ComponentContext) bool {
 func (c prometheusPushgatewayComponent) PreInstall(ctx spi.ComponentContext) error {
 	return preInstall(ctx)
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the Prometheus Pushgateway, which receives the metrics of the batch
+// jobs of the cluster
+func (c prometheusPushgatewayComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-prometheus-pushgateway", map[string]string{"release": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.ClusterPeers(), 9091),
+			},
+			nil),
+	}, nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package rancher
+
+import (
+	"context"
+
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// backupKinds are the Rancher resources with the users and their permissions
+var backupKinds = []schema.GroupVersionKind{
+	{Group: "management.cattle.io", Version: "v3", Kind: "User"},
+	{Group: "management.cattle.io", Version: "v3", Kind: "GlobalRoleBinding"},
+	{Group: "management.cattle.io", Version: "v3", Kind: "ClusterRoleTemplateBinding"},
+	{Group: "management.cattle.io", Version: "v3", Kind: "ProjectRoleTemplateBinding"},
+	{Group: "management.cattle.io", Version: "v3", Kind: "AuthConfig"},
+}
+
+// GetBackupSpec returns the backup of the Rancher users and their permissions
+func (r rancherComponent) GetBackupSpec() spi.BackupSpec {
+	return spi.BackupSpec{
+		Order:        2,
+		Method:       spi.BackupMethodResources,
+		Kinds:        backupKinds,
+		GetResources: getBackupResources,
+	}
+}
+
+// getBackupResources returns the Rancher users and their permissions.  Kinds that are not installed are skipped.
+func getBackupResources(ctx spi.ComponentContext) ([]clipkg.Object, error) {
+	var resources []clipkg.Object
+	for _, gvk := range backupKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := ctx.Client().List(context.TODO(), list); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+		for i := range list.Items {
+			resources = append(resources, &list.Items[i])
+		}
+	}
+	return resources, nil
+}
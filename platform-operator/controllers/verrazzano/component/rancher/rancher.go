@@ -46,6 +46,9 @@ const (
 	letsEncryptIngressClassKey = "letsEncrypt.ingress.class"
 	letsEncryptEmailKey        = "letsEncrypt.email"
 	letsEncryptEnvironmentKey  = "letsEncrypt.environment"
+
+	// Custom logos Keys
+	customLogosKey = "customLogos"
 )
 
 const (
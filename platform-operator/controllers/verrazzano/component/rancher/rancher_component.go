@@ -20,6 +20,10 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/secret"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -32,6 +36,10 @@ const ComponentNamespace = common.CattleSystem
 // ComponentJSONName is the josn name of the verrazzano component in CRD
 const ComponentJSONName = "rancher"
 
+// customLogosVolumeDefaults are the defaults of the volume of the custom logos, which is only persisted when Rancher
+// has a volume source
+var customLogosVolumeDefaults = common.VolumeDefaults{Size: "1Gi"}
+
 type rancherComponent struct {
 	helm.HelmComponent
 }
@@ -52,6 +60,8 @@ func NewComponent() spi.Component {
 			ImagePullSecretKeyname:  secret.DefaultImagePullSecretKeyName,
 			ValuesFile:              filepath.Join(config.GetHelmOverridesDir(), "rancher-values.yaml"),
 			AppendOverridesFunc:     AppendOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
+			GetKubernetesSpec:       GetKubernetesSpec,
 			Certificates:            certificates,
 			Dependencies:            []string{nginx.ComponentName, certmanager.ComponentName},
 			IngressNames: []types.NamespacedName{
@@ -60,10 +70,41 @@ func NewComponent() spi.Component {
 					Name:      constants.RancherIngress,
 				},
 			},
+			KubernetesValueKeys: []helm.KubernetesValueKeys{
+				{
+					Replicas:  "replicas",
+					Resources: "resources",
+				},
+			},
 		},
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the Rancher Helm chart
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	if comp := ctx.EffectiveCR().Spec.Components.Rancher; comp != nil {
+		return []vzapi.HelmValueOverrides{comp.HelmValueOverrides}
+	}
+	return nil
+}
+
+// GetKubernetesSpec returns the kubernetes section of the Rancher component
+func GetKubernetesSpec(effectiveCR *vzapi.Verrazzano) *vzapi.CommonKubernetesSpec {
+	if comp := effectiveCR.Spec.Components.Rancher; comp != nil {
+		return comp.Kubernetes
+	}
+	return nil
+}
+
+// getVolumeSource returns the volume source of the custom logos of Rancher, the DefaultVolumeSource does not apply
+// since the custom logos are optional
+func getVolumeSource(vz *vzapi.Verrazzano) *corev1.VolumeSource {
+	if comp := vz.Spec.Components.Rancher; comp != nil {
+		return comp.VolumeSource
+	}
+	return nil
+}
+
 //AppendOverrides set the Rancher overrides for Helm
 func AppendOverrides(ctx spi.ComponentContext, _ string, _ string, _ string, kvs []bom.KeyValue) ([]bom.KeyValue, error) {
 	rancherHostName, err := getRancherHostname(ctx.Client(), ctx.EffectiveCR())
@@ -80,6 +121,10 @@ func AppendOverrides(ctx spi.ComponentContext, _ string, _ string, _ string, kvs
 		Value: useBundledSystemChartValue,
 	})
 	kvs = appendRegistryOverrides(kvs)
+	kvs, err = common.AppendPersistenceOverrides(ctx.EffectiveCR(), getVolumeSource(ctx.EffectiveCR()), customLogosKey, kvs)
+	if err != nil {
+		return kvs, err
+	}
 	return appendCAOverrides(kvs, ctx)
 }
 
@@ -155,13 +200,19 @@ func (r rancherComponent) IsEnabled(effectiveCR *vzapi.Verrazzano) bool {
 	return *comp.Enabled
 }
 
+// ValidateInstall checks if the specified Verrazzano CR is valid for this component to be installed
+func (r rancherComponent) ValidateInstall(vz *vzapi.Verrazzano) error {
+	return vzconfig.ValidateVolumeSource(vz, getVolumeSource(vz), ComponentJSONName)
+}
+
 // ValidateUpdate checks if the specified new Verrazzano CR is valid for this component to be updated
 func (r rancherComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Block all changes for now, particularly around storage changes
-	if r.IsEnabled(old) && !r.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
+	// Reject changes of the persistence or StorageClass of the custom logos, the volume can only grow when the
+	// StorageClass allows volume expansion
+	if err := common.CompareVolumeSources(old, new, getVolumeSource(old), getVolumeSource(new), customLogosVolumeDefaults, ComponentJSONName); err != nil {
+		return err
 	}
-	return nil
+	return vzconfig.ValidateVolumeSource(new, getVolumeSource(new), ComponentJSONName)
 }
 
 // PreInstall
@@ -258,3 +309,21 @@ func (r rancherComponent) PostInstall(ctx spi.ComponentContext) error {
 	}
 	return r.HelmComponent.PostInstall(ctx)
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of Rancher.  The policy selects all the pods of the namespace, since
+// Rancher creates pods in the namespace to manage the clusters.
+func (r rancherComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-rancher", nil,
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.IngressControllerPeers(), 80, 443),
+				netpolicy.IngressFrom([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", nil)}),
+				// The Rancher webhook is called by the API server
+				netpolicy.IngressFrom(nil, 9443),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				// Rancher reaches the managed clusters, and the repositories of the charts it installs
+				netpolicy.EgressTo(nil),
+			}),
+	}, nil
+}
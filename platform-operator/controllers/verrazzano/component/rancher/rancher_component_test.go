@@ -23,7 +23,9 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -376,7 +378,7 @@ func Test_rancherComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name:    "no change",
@@ -384,6 +386,24 @@ func Test_rancherComponent_ValidateUpdate(t *testing.T) {
 			new:     &vzapi.Verrazzano{},
 			wantErr: false,
 		},
+		{
+			name:    "persist-custom-logos",
+			old:     &vzapi.Verrazzano{},
+			new:     newCustomLogosVZ("logos"),
+			wantErr: true,
+		},
+		{
+			name:    "no change to custom logos",
+			old:     newCustomLogosVZ("logos"),
+			new:     newCustomLogosVZ("logos"),
+			wantErr: false,
+		},
+		{
+			name:    "missing-custom-logos-template",
+			old:     newCustomLogosVZ("logos"),
+			new:     newCustomLogosVZ("missing"),
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -394,3 +414,51 @@ func Test_rancherComponent_ValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+// newCustomLogosVZ returns a Verrazzano CR where the custom logos of Rancher reference a volume claim template
+func newCustomLogosVZ(claimName string) *vzapi.Verrazzano {
+	return &vzapi.Verrazzano{
+		Spec: vzapi.VerrazzanoSpec{
+			Components: vzapi.ComponentSpec{
+				Rancher: &vzapi.RancherComponent{
+					VolumeSource: &corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+					},
+				},
+			},
+			VolumeClaimSpecTemplates: []vzapi.VolumeClaimSpecTemplate{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "logos"},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestAppendCustomLogosOverrides verifies that the custom logos are persisted when Rancher has a volume source
+// GIVEN a Verrazzano CR where Rancher references a volume claim template
+// WHEN AppendOverrides is called
+// THEN the custom logos are enabled with the size of the template
+func TestAppendCustomLogosOverrides(t *testing.T) {
+	vz := vzAcmeDev.DeepCopy()
+	vz.Spec.VolumeClaimSpecTemplates = newCustomLogosVZ("logos").Spec.VolumeClaimSpecTemplates
+	vz.Spec.Components.Rancher = newCustomLogosVZ("logos").Spec.Components.Rancher
+	ctx := spi.NewFakeContext(fake.NewClientBuilder().WithScheme(getScheme()).Build(), vz, false)
+	kvs, err := AppendOverrides(ctx, "", "", "", []bom.KeyValue{})
+	assert.NoError(t, err)
+	v, ok := getValue(kvs, "customLogos.enabled")
+	assert.True(t, ok)
+	assert.Equal(t, "true", v)
+	v, ok = getValue(kvs, "customLogos.size")
+	assert.True(t, ok)
+	assert.Equal(t, "2Gi", v)
+
+	assert.NoError(t, NewComponent().ValidateInstall(vz))
+	vz.Spec.Components.Rancher.VolumeSource.PersistentVolumeClaim.ClaimName = "missing"
+	assert.Error(t, NewComponent().ValidateInstall(vz))
+}
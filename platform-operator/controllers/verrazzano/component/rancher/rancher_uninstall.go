@@ -0,0 +1,256 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package rancher
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	vzconst "github.com/verrazzano/verrazzano/pkg/constants"
+	"github.com/verrazzano/verrazzano/pkg/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// managementGroup is the API group of the resources Rancher uses to manage the clusters
+	managementGroup = "management.cattle.io"
+	// projectIDAnnotation is the annotation Rancher adds to the secrets of the projects
+	projectIDAnnotation = "field.cattle.io/projectId"
+	// rancherFinalizer is the finalizer Rancher adds to the namespaces
+	rancherFinalizer = "controller.cattle.io"
+)
+
+// rancherReleases are the Helm releases Rancher installs in the cluster
+var rancherReleases = []types.NamespacedName{
+	{Namespace: "cattle-fleet-system", Name: "fleet"},
+	{Namespace: "cattle-fleet-system", Name: "fleet-crd"},
+	{Namespace: "cattle-fleet-local-system", Name: "fleet-agent-local"},
+	{Namespace: "fleet-system", Name: "fleet"},
+	{Namespace: "fleet-system", Name: "fleet-crd"},
+	{Namespace: ComponentNamespace, Name: "rancher-webhook"},
+}
+
+// rancherConfigMaps are the ConfigMaps of the Rancher controllers
+var rancherConfigMaps = []types.NamespacedName{
+	{Namespace: vzconst.KubeSystem, Name: "cattle-controllers"},
+	{Namespace: vzconst.KubeSystem, Name: "rancher-controller-lock"},
+}
+
+// defaultNamespaces are the namespaces of the cluster that Rancher adds role bindings and annotations to
+var defaultNamespaces = []string{"default", "kube-node-lease", "kube-public", vzconst.KubeSystem}
+
+// rancherNamespacePattern matches the namespaces Rancher creates, including the namespaces of the projects and users
+var rancherNamespacePattern = regexp.MustCompile(`^(cattle-.+|fleet-.+|local|p-[a-z0-9]{5}|user-[a-z0-9]{5})$`)
+
+// PreUninstall deletes the Rancher local cluster, so that Rancher removes the resources it deployed in the cluster.  A
+// failure is not an error, since PostUninstall removes the resources that are left.
+func (r rancherComponent) PreUninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debug("Rancher PreUninstall dry run")
+		return nil
+	}
+	if err := deleteLocalCluster(ctx); err != nil {
+		ctx.Log().Infof("Failed deleting the Rancher local cluster, its resources are removed once Rancher is uninstalled: %v", err)
+	}
+	return nil
+}
+
+/*
+PostUninstall removes the resources that are left once the Rancher chart is uninstalled
+- Uninstall the charts Rancher installed
+- Delete the Rancher webhooks, custom resources, CRDs, cluster roles and role bindings
+- Delete the Rancher ConfigMaps and annotations in the namespaces of the cluster
+- Delete the Rancher namespaces, and remove the Rancher finalizers from the other namespaces
+*/
+func (r rancherComponent) PostUninstall(ctx spi.ComponentContext) error {
+	if ctx.IsDryRun() {
+		ctx.Log().Debug("Rancher PostUninstall dry run")
+		return nil
+	}
+	for _, release := range rancherReleases {
+		if err := uninstallRelease(ctx, release); err != nil {
+			return err
+		}
+	}
+	if err := common.DeleteResources(ctx, &admv1.MutatingWebhookConfigurationList{}, isRancherResource("cattle.io")); err != nil {
+		return err
+	}
+	if err := common.DeleteResources(ctx, &admv1.ValidatingWebhookConfigurationList{}, isRancherResource("cattle.io")); err != nil {
+		return err
+	}
+	if err := deleteManagementResources(ctx); err != nil {
+		return err
+	}
+	if err := common.ForceDeleteResources(ctx, &apiextv1.CustomResourceDefinitionList{}, common.CRDGroupHasSuffix("cattle.io")); err != nil {
+		return err
+	}
+	if err := common.DeleteResources(ctx, &rbacv1.ClusterRoleBindingList{}, isRancherResource("cattle.io", "rancher-webhook",
+		"fleetworkspace-", "fleet-", "gitjob", "cattle-admin", "proxy-role-binding-kubernetes-master")); err != nil {
+		return err
+	}
+	if err := common.DeleteResources(ctx, &rbacv1.ClusterRoleList{}, isRancherResource("cattle.io", "fleetworkspace-",
+		"fleet-", "gitjob", "cattle-admin", "local-cluster", "proxy-clusterrole-kubeapiserver")); err != nil {
+		return err
+	}
+	for _, namespace := range defaultNamespaces {
+		if err := common.DeleteResources(ctx, &rbacv1.RoleBindingList{}, isRancherRoleBinding, clipkg.InNamespace(namespace)); err != nil {
+			return err
+		}
+		if err := removeProjectAnnotations(ctx, namespace); err != nil {
+			return err
+		}
+	}
+	for _, name := range rancherConfigMaps {
+		if err := common.DeleteObject(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: name.Namespace, Name: name.Name}}); err != nil {
+			return err
+		}
+	}
+	return deleteRancherNamespaces(ctx)
+}
+
+// deleteLocalCluster deletes the local cluster using the Rancher API
+func deleteLocalCluster(ctx spi.ComponentContext) error {
+	c := ctx.Client()
+	password, err := common.GetAdminSecret(c)
+	if err != nil {
+		return err
+	}
+	rancherHostName, err := getRancherHostname(c, ctx.EffectiveCR())
+	if err != nil {
+		return err
+	}
+	rest, err := common.NewClient(c, rancherHostName, password)
+	if err != nil {
+		return err
+	}
+	if err := rest.SetAccessToken(); err != nil {
+		return err
+	}
+	return rest.DeleteLocalCluster()
+}
+
+// uninstallRelease uninstalls a chart Rancher installed, if it is installed
+func uninstallRelease(ctx spi.ComponentContext, release types.NamespacedName) error {
+	installed, err := helm.IsReleaseInstalled(release.Name, release.Namespace)
+	if err != nil {
+		return ctx.Log().ErrorfNewErr("Failed checking if Helm release %s/%s is installed: %v", release.Namespace, release.Name, err)
+	}
+	if !installed {
+		return nil
+	}
+	if _, _, err := helm.Uninstall(ctx.Log(), release.Name, release.Namespace, false); err != nil {
+		return ctx.Log().ErrorfNewErr("Failed uninstalling Helm release %s/%s: %v", release.Namespace, release.Name, err)
+	}
+	return nil
+}
+
+// isRancherResource returns a function that selects the resources whose name contains one of the strings, or that
+// have a Rancher label
+func isRancherResource(strs ...string) common.ResourceMatchFunc {
+	nameContains := common.NameContains(strs...)
+	return func(obj clipkg.Object) bool {
+		if nameContains(obj) || obj.GetLabels()["app"] == ComponentName {
+			return true
+		}
+		for key := range obj.GetLabels() {
+			if strings.Contains(key, "cattle.io") {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isRancherRoleBinding returns true if the role binding was created by Rancher
+func isRancherRoleBinding(obj clipkg.Object) bool {
+	return strings.Contains(obj.GetName(), "clusterrolebinding-") || strings.HasPrefix(obj.GetName(), "rb-")
+}
+
+// deleteManagementResources removes the finalizers of the management resources and deletes them, their finalizers
+// are not processed once Rancher is uninstalled
+func deleteManagementResources(ctx spi.ComponentContext) error {
+	crds := &apiextv1.CustomResourceDefinitionList{}
+	if err := ctx.Client().List(context.TODO(), crds); err != nil {
+		return ctx.Log().ErrorfNewErr("Failed listing the CRDs: %v", err)
+	}
+	for _, crd := range crds.Items {
+		if crd.Spec.Group != managementGroup {
+			continue
+		}
+		for _, version := range crd.Spec.Versions {
+			if !version.Storage {
+				continue
+			}
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.ListKind})
+			if err := common.ForceDeleteResources(ctx, list, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// removeProjectAnnotations removes the Rancher project annotation from the secrets of the namespace
+func removeProjectAnnotations(ctx spi.ComponentContext, namespace string) error {
+	secrets := &corev1.SecretList{}
+	if err := ctx.Client().List(context.TODO(), secrets, clipkg.InNamespace(namespace)); err != nil {
+		return ctx.Log().ErrorfNewErr("Failed listing the secrets in namespace %s: %v", namespace, err)
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if _, ok := secret.Annotations[projectIDAnnotation]; !ok {
+			continue
+		}
+		patch := clipkg.MergeFrom(secret.DeepCopy())
+		delete(secret.Annotations, projectIDAnnotation)
+		if err := ctx.Client().Patch(context.TODO(), secret, patch); err != nil {
+			return ctx.Log().ErrorfNewErr("Failed removing the Rancher annotation from secret %s/%s: %v", namespace, secret.Name, err)
+		}
+	}
+	return nil
+}
+
+// deleteRancherNamespaces deletes the namespaces Rancher created, and removes the Rancher finalizers from the other
+// namespaces
+func deleteRancherNamespaces(ctx spi.ComponentContext) error {
+	namespaces := &corev1.NamespaceList{}
+	if err := ctx.Client().List(context.TODO(), namespaces); err != nil {
+		return ctx.Log().ErrorfNewErr("Failed listing the namespaces: %v", err)
+	}
+	var rancherNamespaces []string
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if rancherNamespacePattern.MatchString(ns.Name) {
+			rancherNamespaces = append(rancherNamespaces, ns.Name)
+			continue
+		}
+		var finalizers []string
+		for _, finalizer := range ns.Finalizers {
+			if !strings.Contains(finalizer, rancherFinalizer) {
+				finalizers = append(finalizers, finalizer)
+			}
+		}
+		if len(finalizers) == len(ns.Finalizers) {
+			continue
+		}
+		patch := clipkg.MergeFrom(ns.DeepCopy())
+		ns.Finalizers = finalizers
+		if err := ctx.Client().Patch(context.TODO(), ns, patch); err != nil {
+			return ctx.Log().ErrorfNewErr("Failed removing the Rancher finalizers from namespace %s: %v", ns.Name, err)
+		}
+	}
+	return common.DeleteNamespaces(ctx, rancherNamespaces...)
+}
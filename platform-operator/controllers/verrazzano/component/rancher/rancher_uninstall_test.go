@@ -0,0 +1,131 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package rancher
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzconst "github.com/verrazzano/verrazzano/pkg/constants"
+	"github.com/verrazzano/verrazzano/pkg/helm"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// notFoundRunner returns that the Helm releases are not found
+type notFoundRunner struct{}
+
+// Run notFoundRunner executor
+func (r notFoundRunner) Run(_ *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	return []byte{}, []byte("Error: release: not found"), errors.New("release not found")
+}
+
+// TestPreUninstall tests the Rancher PreUninstall call
+// GIVEN a Rancher component
+// WHEN PreUninstall is called and the Rancher local cluster can't be deleted
+// THEN no error is returned
+func TestPreUninstall(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(getScheme()).Build()
+	assert.NoError(t, NewComponent().PreUninstall(spi.NewFakeContext(c, &vzapi.Verrazzano{}, false)))
+}
+
+// TestPostUninstall tests the Rancher PostUninstall call
+// GIVEN a cluster with the resources Rancher leaves once it is uninstalled
+// WHEN PostUninstall is called
+// THEN the Rancher resources are deleted, the Rancher annotations and finalizers are removed, and the other resources are kept
+func TestPostUninstall(t *testing.T) {
+	a := assert.New(t)
+	helm.SetCmdRunner(notFoundRunner{})
+	defer helm.SetDefaultRunner()
+
+	clusterGVK := schema.GroupVersionKind{Group: managementGroup, Version: "v3", Kind: "Cluster"}
+	scheme := getScheme()
+	_ = admv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = apiextv1.AddToScheme(scheme)
+	scheme.AddKnownTypeWithName(clusterGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(clusterGVK.GroupVersion().WithKind("ClusterList"), &unstructured.UnstructuredList{})
+
+	localCluster := &unstructured.Unstructured{}
+	localCluster.SetGroupVersionKind(clusterGVK)
+	localCluster.SetName("local")
+	localCluster.SetFinalizers([]string{rancherFinalizer})
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&admv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "rancher.cattle.io"}},
+		&apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "clusters.management.cattle.io"},
+			Spec: apiextv1.CustomResourceDefinitionSpec{
+				Group:    managementGroup,
+				Names:    apiextv1.CustomResourceDefinitionNames{Kind: "Cluster", ListKind: "ClusterList"},
+				Versions: []apiextv1.CustomResourceDefinitionVersion{{Name: "v3", Storage: true}},
+			},
+		},
+		localCluster,
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "cattle-admin-binding"}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "rb-abcde"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: vzconst.KubeSystem, Name: "secret",
+			Annotations: map[string]string{projectIDAnnotation: "local:p-abcde", "other": "value"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: vzconst.KubeSystem, Name: "cattle-controllers"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ComponentNamespace}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "p-abcde"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Finalizers: []string{rancherFinalizer}}},
+	).Build()
+
+	a.NoError(NewComponent().PostUninstall(spi.NewFakeContext(c, &vzapi.Verrazzano{}, false)))
+
+	deleted := []struct {
+		name types.NamespacedName
+		obj  clipkg.Object
+	}{
+		{types.NamespacedName{Name: "rancher.cattle.io"}, &admv1.ValidatingWebhookConfiguration{}},
+		{types.NamespacedName{Name: "clusters.management.cattle.io"}, &apiextv1.CustomResourceDefinition{}},
+		{types.NamespacedName{Name: "local"}, localCluster.DeepCopy()},
+		{types.NamespacedName{Name: "cattle-admin-binding"}, &rbacv1.ClusterRoleBinding{}},
+		{types.NamespacedName{Namespace: "default", Name: "rb-abcde"}, &rbacv1.RoleBinding{}},
+		{types.NamespacedName{Namespace: vzconst.KubeSystem, Name: "cattle-controllers"}, &corev1.ConfigMap{}},
+		{types.NamespacedName{Name: ComponentNamespace}, &corev1.Namespace{}},
+		{types.NamespacedName{Name: "p-abcde"}, &corev1.Namespace{}},
+	}
+	for _, d := range deleted {
+		err := c.Get(context.TODO(), d.name, d.obj)
+		a.True(k8serrors.IsNotFound(err), "expected %s to be deleted", d.name)
+	}
+	a.NoError(c.Get(context.TODO(), types.NamespacedName{Name: "other"}, &rbacv1.ClusterRoleBinding{}))
+
+	secret := &corev1.Secret{}
+	a.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vzconst.KubeSystem, Name: "secret"}, secret))
+	a.Equal(map[string]string{"other": "value"}, secret.Annotations)
+
+	ns := &corev1.Namespace{}
+	a.NoError(c.Get(context.TODO(), types.NamespacedName{Name: "default"}, ns))
+	a.Empty(ns.Finalizers)
+}
+
+// TestPostUninstallDryRun tests the Rancher PostUninstall call
+// GIVEN a Rancher component
+// WHEN PostUninstall is called in dry run mode
+// THEN nothing is deleted
+func TestPostUninstallDryRun(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(getScheme()).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ComponentNamespace}},
+	).Build()
+	assert.NoError(t, NewComponent().PostUninstall(spi.NewFakeContext(c, &vzapi.Verrazzano{}, true)))
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: ComponentNamespace}, &corev1.Namespace{}))
+}
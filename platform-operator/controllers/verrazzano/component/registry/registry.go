@@ -4,6 +4,10 @@
 package registry
 
 import (
+	"fmt"
+	"sync"
+
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/appoper"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/authproxy"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/certmanager"
@@ -37,6 +41,12 @@ var getComponentsFn = getComponents
 
 var componentsRegistry []spi.Component
 
+// extensionComponents are the components of the VerrazzanoExtension resources, which follow the built-in components
+var extensionComponents []spi.Component
+
+// extensionMutex guards extensionComponents, which are replaced each time the extensions are reconciled
+var extensionMutex sync.RWMutex
+
 // OverrideGetComponentsFn Allows overriding the set of registry components for testing purposes
 func OverrideGetComponentsFn(fnType GetCompoentsFnType) {
 	getComponentsFn = fnType
@@ -56,6 +66,19 @@ func GetComponents() []spi.Component {
 
 // getComponents is the internal impl function for GetComponents, to allow overriding it for testing purposes
 func getComponents() []spi.Component {
+	builtIn := getBuiltInComponents()
+	extensionMutex.RLock()
+	defer extensionMutex.RUnlock()
+	if len(extensionComponents) == 0 {
+		return builtIn
+	}
+	comps := make([]spi.Component, 0, len(builtIn)+len(extensionComponents))
+	comps = append(comps, builtIn...)
+	return append(comps, extensionComponents...)
+}
+
+// getBuiltInComponents returns the components that are part of Verrazzano
+func getBuiltInComponents() []spi.Component {
 	if len(componentsRegistry) == 0 {
 		componentsRegistry = []spi.Component{
 			oam.NewComponent(),
@@ -87,6 +110,24 @@ func getComponents() []spi.Component {
 	return componentsRegistry
 }
 
+// SetExtensionComponents replaces the components of the VerrazzanoExtension resources that are returned by
+// GetComponents after the built-in components
+func SetExtensionComponents(comps []spi.Component) {
+	extensionMutex.Lock()
+	defer extensionMutex.Unlock()
+	extensionComponents = comps
+}
+
+// IsBuiltInComponent returns true if the name is the name of a built-in component
+func IsBuiltInComponent(name string) bool {
+	for _, comp := range getBuiltInComponents() {
+		if comp.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
 func FindComponent(releaseName string) (bool, spi.Component) {
 	for _, comp := range GetComponents() {
 		if comp.Name() == releaseName {
@@ -96,6 +137,78 @@ func FindComponent(releaseName string) (bool, spi.Component) {
 	return false, nil
 }
 
+// ValidateDependencies checks the dependency graph of the registry components.  An error is returned if a dependency
+// cycle is found or a component declares a dependency that does not exist.
+func ValidateDependencies() error {
+	_, err := sortByDependencies(GetComponents())
+	return err
+}
+
+// GetComponentsInInstallOrder returns the registry components sorted so that each component follows all of the
+// components it depends on.
+func GetComponentsInInstallOrder() ([]spi.Component, error) {
+	return sortByDependencies(GetComponents())
+}
+
+// GetComponentsInUninstallOrder returns the registry components in the order they should be uninstalled.  This is
+// the reverse of the dependency order, so a component is always uninstalled before any of the components it depends on.
+func GetComponentsInUninstallOrder() ([]spi.Component, error) {
+	ordered, err := GetComponentsInInstallOrder()
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(ordered)-1; i < j; i, j = i+1, j-1 {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	}
+	return ordered, nil
+}
+
+// sortByDependencies returns the components sorted such that each component follows all of its dependencies.  The
+// relative order of the input list is otherwise preserved.  An error is returned if a dependency cycle is found or
+// a declared dependency does not exist.
+func sortByDependencies(comps []spi.Component) ([]spi.Component, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	byName := make(map[string]spi.Component, len(comps))
+	for _, comp := range comps {
+		byName[comp.Name()] = comp
+	}
+	state := make(map[string]int, len(comps))
+	sorted := make([]spi.Component, 0, len(comps))
+
+	var visit func(comp spi.Component) error
+	visit = func(comp spi.Component) error {
+		switch state[comp.Name()] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("Failed, illegal state, dependency cycle found for %s", comp.Name())
+		}
+		state[comp.Name()] = visiting
+		for _, dependencyName := range comp.GetDependencies() {
+			dependency, ok := byName[dependencyName]
+			if !ok {
+				return fmt.Errorf("Failed, illegal state, declared dependency not found for %s: %s", comp.Name(), dependencyName)
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		state[comp.Name()] = visited
+		sorted = append(sorted, comp)
+		return nil
+	}
+
+	for _, comp := range comps {
+		if err := visit(comp); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
 // ComponentDependenciesMet Checks if the declared dependencies for the component are ready and available
 func ComponentDependenciesMet(c spi.Component, context spi.ComponentContext) bool {
 	log := context.Log()
@@ -117,6 +230,25 @@ func ComponentDependenciesMet(c spi.Component, context spi.ComponentContext) boo
 	return true
 }
 
+// ComponentDependentsDisabled Checks that none of the enabled components in the effective CR declare a dependency on
+// the component, returning the names of any enabled dependents.  A component can only be disabled and removed once
+// no other enabled component depends on it.
+func ComponentDependentsDisabled(c spi.Component, effectiveCR *vzapi.Verrazzano) (bool, []string) {
+	var dependents []string
+	for _, comp := range GetComponents() {
+		if comp.Name() == c.Name() || !comp.IsEnabled(effectiveCR) {
+			continue
+		}
+		for _, dependencyName := range comp.GetDependencies() {
+			if dependencyName == c.Name() {
+				dependents = append(dependents, comp.Name())
+				break
+			}
+		}
+	}
+	return len(dependents) == 0, dependents
+}
+
 // checkDependencies Check the ready state of any dependencies and check for cycles
 func checkDependencies(c spi.Component, context spi.ComponentContext, visited map[string]bool, stateMap map[string]bool) (map[string]bool, error) {
 	compName := c.Name()
@@ -424,6 +424,31 @@ func TestComponentDependenciesChainNoCycle(t *testing.T) {
 	assert.True(t, ready)
 }
 
+// TestComponentDependentsDisabled tests ComponentDependentsDisabled
+// GIVEN a component
+//  WHEN I call ComponentDependentsDisabled for it
+//  THEN it returns false and the names of the enabled components that depend on it
+func TestComponentDependentsDisabled(t *testing.T) {
+	base := fakeComponent{name: "fake1", enabled: true}
+	OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			base,
+			fakeComponent{name: "fake2", dependencies: []string{"fake1"}, enabled: true},
+			fakeComponent{name: "fake3", dependencies: []string{"fake1"}},
+			fakeComponent{name: "fake4", dependencies: []string{"fake2"}, enabled: true},
+		}
+	})
+	defer ResetGetComponentsFn()
+
+	disabled, dependents := ComponentDependentsDisabled(base, &v1alpha1.Verrazzano{})
+	assert.False(t, disabled)
+	assert.Equal(t, []string{"fake2"}, dependents)
+
+	disabled, dependents = ComponentDependentsDisabled(fakeComponent{name: "fake4"}, &v1alpha1.Verrazzano{})
+	assert.True(t, disabled)
+	assert.Empty(t, dependents)
+}
+
 // TestRegistryDependencies tests the default Registry components for cycles
 // GIVEN a component
 //  WHEN I call checkDependencies for it
@@ -524,6 +549,22 @@ func (f fakeComponent) PostInstall(_ spi.ComponentContext) error {
 	return nil
 }
 
+func (f fakeComponent) PreUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+func (f fakeComponent) Uninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+func (f fakeComponent) PostUninstall(_ spi.ComponentContext) error {
+	return nil
+}
+
+func (f fakeComponent) IsUninstalled(_ spi.ComponentContext) (bool, error) {
+	return true, nil
+}
+
 func (f fakeComponent) PreUpgrade(_ spi.ComponentContext) error {
 	return nil
 }
@@ -555,3 +596,147 @@ func (f fakeComponent) ValidateUpdate(old *v1alpha1.Verrazzano, new *v1alpha1.Ve
 func (f fakeComponent) GetCertificateNames(_ spi.ComponentContext) []types.NamespacedName {
 	return []types.NamespacedName{}
 }
+
+// TestGetComponentsInUninstallOrder tests GetComponentsInUninstallOrder
+// GIVEN a registry of components with dependencies
+//  WHEN I call GetComponentsInUninstallOrder
+//  THEN every component is returned before the components it depends on
+func TestGetComponentsInUninstallOrder(t *testing.T) {
+	OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeComponent{name: "fake3", dependencies: []string{"fake2"}},
+			fakeComponent{name: "fake1"},
+			fakeComponent{name: "fake2", dependencies: []string{"fake1"}},
+			fakeComponent{name: "fake4"},
+		}
+	})
+	defer ResetGetComponentsFn()
+
+	comps, err := GetComponentsInUninstallOrder()
+	assert.NoError(t, err)
+	var names []string
+	for _, comp := range comps {
+		names = append(names, comp.Name())
+	}
+	assert.Equal(t, []string{"fake4", "fake3", "fake2", "fake1"}, names)
+}
+
+// TestGetComponentsInUninstallOrderCycle tests GetComponentsInUninstallOrder
+// GIVEN a registry of components with a dependency cycle or a missing dependency
+//  WHEN I call GetComponentsInUninstallOrder
+//  THEN an error is returned
+func TestGetComponentsInUninstallOrderCycle(t *testing.T) {
+	OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeComponent{name: "fake1", dependencies: []string{"fake2"}},
+			fakeComponent{name: "fake2", dependencies: []string{"fake1"}},
+		}
+	})
+	_, err := GetComponentsInUninstallOrder()
+	assert.Error(t, err)
+
+	OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeComponent{name: "fake1", dependencies: []string{"missing"}},
+		}
+	})
+	defer ResetGetComponentsFn()
+	_, err = GetComponentsInUninstallOrder()
+	assert.Error(t, err)
+}
+
+// TestGetComponentsInInstallOrder tests GetComponentsInInstallOrder
+// GIVEN a registry of components with dependencies
+//  WHEN I call GetComponentsInInstallOrder
+//  THEN every component is returned after the components it depends on
+func TestGetComponentsInInstallOrder(t *testing.T) {
+	OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeComponent{name: "fake3", dependencies: []string{"fake2"}},
+			fakeComponent{name: "fake1"},
+			fakeComponent{name: "fake2", dependencies: []string{"fake1"}},
+			fakeComponent{name: "fake4"},
+		}
+	})
+	defer ResetGetComponentsFn()
+
+	comps, err := GetComponentsInInstallOrder()
+	assert.NoError(t, err)
+	var names []string
+	for _, comp := range comps {
+		names = append(names, comp.Name())
+	}
+	assert.Equal(t, []string{"fake1", "fake2", "fake3", "fake4"}, names)
+}
+
+// TestValidateDependencies tests ValidateDependencies
+// GIVEN the default registry and a registry with a dependency cycle
+//  WHEN I call ValidateDependencies
+//  THEN no error is returned for the default registry and an error is returned for the cycle
+func TestValidateDependencies(t *testing.T) {
+	assert.NoError(t, ValidateDependencies())
+
+	OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeComponent{name: "fake1", dependencies: []string{"fake3"}},
+			fakeComponent{name: "fake2", dependencies: []string{"fake1"}},
+			fakeComponent{name: "fake3", dependencies: []string{"fake2"}},
+		}
+	})
+	defer ResetGetComponentsFn()
+	assert.Error(t, ValidateDependencies())
+}
+
+// TestSetExtensionComponents tests SetExtensionComponents
+// GIVEN the components of VerrazzanoExtension resources
+//  WHEN I call GetComponents
+//  THEN the extension components follow the built-in components and can depend on them
+func TestSetExtensionComponents(t *testing.T) {
+	a := assert.New(t)
+	SetExtensionComponents([]spi.Component{
+		fakeComponent{name: "addon", dependencies: []string{istio.ComponentName}},
+	})
+	defer SetExtensionComponents(nil)
+
+	comps := GetComponents()
+	a.Len(comps, 25)
+	a.Equal("addon", comps[24].Name())
+	a.Len(getBuiltInComponents(), 24)
+	a.NoError(ValidateDependencies())
+	a.True(IsBuiltInComponent(istio.ComponentName))
+	a.False(IsBuiltInComponent("addon"))
+
+	SetExtensionComponents(nil)
+	a.Len(GetComponents(), 24)
+}
+
+// TestNetworkPolicyProviders tests the NetworkPolicies of the built-in components
+// GIVEN the default registry
+// WHEN I call GetNetworkPolicies for each component
+// THEN each component provides policies with unique names that select the pods of a namespace
+func TestNetworkPolicyProviders(t *testing.T) {
+	a := assert.New(t)
+	// OpenSearch lists the backups for the ports of their storage locations
+	_ = v1alpha1.AddToScheme(k8scheme.Scheme)
+	client := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+	ctx := spi.NewFakeContext(client, &v1alpha1.Verrazzano{}, false, profileDir)
+
+	names := map[types.NamespacedName]string{}
+	for _, comp := range getBuiltInComponents() {
+		provider, ok := comp.(spi.ComponentNetworkPolicyProvider)
+		a.True(ok, "component %s does not provide NetworkPolicies", comp.Name())
+		if !ok {
+			continue
+		}
+		policies, err := provider.GetNetworkPolicies(ctx)
+		a.NoError(err)
+		a.NotEmpty(policies, comp.Name())
+		for _, policy := range policies {
+			a.NotEmpty(policy.Namespace, comp.Name())
+			a.NotEmpty(policy.Name, comp.Name())
+			nsn := types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}
+			a.NotContains(names, nsn, "NetworkPolicy %s of component %s is also provided by %s", nsn, comp.Name(), names[nsn])
+			names[nsn] = comp.Name()
+		}
+	}
+}
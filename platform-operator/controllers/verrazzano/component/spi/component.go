@@ -6,6 +6,8 @@ package spi
 import (
 	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -67,6 +69,14 @@ type ComponentInstaller interface {
 	Install(context ComponentContext) error
 	// PostInstall allows components to perform any post-processing required after initial install
 	PostInstall(context ComponentContext) error
+	// PreUninstall allows components to perform any pre-processing required prior to uninstall
+	PreUninstall(context ComponentContext) error
+	// Uninstall removes the component from the cluster
+	Uninstall(context ComponentContext) error
+	// PostUninstall allows components to perform any post-processing required after uninstall
+	PostUninstall(context ComponentContext) error
+	// IsUninstalled Indicates whether or not the component has been completely removed
+	IsUninstalled(context ComponentContext) (bool, error)
 }
 
 // ComponentUpgrader interface defines upgrade operations for components that support it
@@ -79,6 +89,109 @@ type ComponentUpgrader interface {
 	PostUpgrade(context ComponentContext) error
 }
 
+// ComponentRollbacker interface defines the rollback of a failed upgrade for components that support it
+type ComponentRollbacker interface {
+	// GetReleaseRevision returns the revision of the installed release, 0 if the release can't be rolled back
+	GetReleaseRevision(context ComponentContext) (int, error)
+	// GetReleaseVersion returns the chart version of the installed release, an empty string if the release is not installed
+	GetReleaseVersion(context ComponentContext) (string, error)
+	// Rollback rolls the component back to the release revision
+	Rollback(context ComponentContext, revision int) error
+}
+
+// ComponentHealthReporter interface defines the reporting of unhealthy resources for components that support it
+type ComponentHealthReporter interface {
+	// GetUnreadyWorkloads returns a description of each deployment, statefulset and daemonset of the component that is not ready
+	GetUnreadyWorkloads(context ComponentContext) ([]string, error)
+}
+
+// ComponentDriftDetector interface defines the detection of changes made outside of Verrazzano for components that support it
+type ComponentDriftDetector interface {
+	// GetDrift returns a description of each difference between the installed component and its Verrazzano configuration
+	GetDrift(context ComponentContext) ([]string, error)
+}
+
+// ComponentPreflightChecker interface defines the checks of the target cluster made before the install or upgrade, for
+// components that support it
+type ComponentPreflightChecker interface {
+	// RunPreflightChecks returns the results of the checks of the target cluster made before the component is installed
+	// or upgraded.  A failed check blocks the install or upgrade until it is fixed.
+	RunPreflightChecks(context ComponentContext) ([]vzapi.PreflightCheck, error)
+}
+
+// ComponentOverridesMonitor interface defines the Helm value overrides of a component whose ConfigMaps and Secrets are
+// monitored, the component is installed again when they change
+type ComponentOverridesMonitor interface {
+	// GetMonitoredOverrides returns the Helm value overrides that have changes to their ConfigMaps and Secrets monitored
+	GetMonitoredOverrides(context ComponentContext) []vzapi.Overrides
+}
+
+// ComponentNamespaceProvider interface defines the namespace a component is installed in, for components that support it
+type ComponentNamespaceProvider interface {
+	// GetNamespace returns the namespace the component is installed in
+	GetNamespace(context ComponentContext) string
+}
+
+// ComponentNetworkPolicyProvider interface defines the network flows of the pods of a component, for components that
+// support it
+type ComponentNetworkPolicyProvider interface {
+	// GetNetworkPolicies returns the NetworkPolicies that allow the ingress and egress traffic the pods of the component
+	// need.  Once all the enabled components of a namespace provide their policies, the other traffic of the pods of the
+	// namespace is denied.
+	GetNetworkPolicies(context ComponentContext) ([]netv1.NetworkPolicy, error)
+}
+
+// BackupMethod is how the state of a component is backed up and restored
+type BackupMethod string
+
+const (
+	// BackupMethodResources saves the Kubernetes resources of the state of the component, the restore applies them
+	BackupMethodResources BackupMethod = "Resources"
+	// BackupMethodMySQL dumps the databases of a MySQL server, the restore loads the dump
+	BackupMethodMySQL BackupMethod = "MySQL"
+	// BackupMethodOpenSearch takes a snapshot of the OpenSearch indices, the restore restores the snapshot
+	BackupMethodOpenSearch BackupMethod = "OpenSearch"
+	// BackupMethodGrafana exports the Grafana dashboards, the restore imports them
+	BackupMethodGrafana BackupMethod = "Grafana"
+)
+
+// BackupSpec describes how the state of a component is backed up and restored
+type BackupSpec struct {
+	// Order is the position of the component in the backup and restore, the components with a lower order are
+	// restored first
+	Order int
+	// Method is how the state of the component is backed up and restored
+	Method BackupMethod
+	// RestoreBeforeInstall is true if the state of the component is restored before Verrazzano is installed, the
+	// state of the other components is restored once they are ready
+	RestoreBeforeInstall bool
+	// Endpoint is the address of the service the backup and restore jobs connect to, for the MySQL, OpenSearch and
+	// Grafana methods
+	Endpoint string
+	// CredentialSecret is the secret with the credentials of the endpoint, for the MySQL and Grafana methods
+	CredentialSecret types.NamespacedName
+	// Kinds are the kinds of the Kubernetes resources in the state of the component, for the Resources method
+	Kinds []schema.GroupVersionKind
+	// GetResources returns the Kubernetes resources in the state of the component, in the order they are applied by
+	// the restore, for the Resources method
+	GetResources func(context ComponentContext) ([]clipkg.Object, error)
+}
+
+// ComponentBackupProvider interface defines the backup and restore of the state of a component, for components whose
+// state is not recreated by an install
+type ComponentBackupProvider interface {
+	// GetBackupSpec returns how the state of the component is backed up and restored
+	GetBackupSpec() BackupSpec
+}
+
+// ComponentKubernetesSettingsValidator interface defines the validation of the kubernetes section of a component, for
+// components that support it
+type ComponentKubernetesSettingsValidator interface {
+	// ValidateKubernetesSettings checks that the kubernetes section of the component only has the settings the
+	// component supports
+	ValidateKubernetesSettings(vz *vzapi.Verrazzano) error
+}
+
 // ComponentValidator interface defines validation operations for components that support it
 type ComponentValidator interface {
 	// ValidateInstall checks if the specified Verrazzano CR is valid for this component to be installed
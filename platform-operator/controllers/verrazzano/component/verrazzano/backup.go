@@ -0,0 +1,85 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"sort"
+
+	globalconst "github.com/verrazzano/verrazzano/pkg/constants"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// generatedSecrets are the secrets generated by the installation of Verrazzano, the components use the same
+// passwords and CA once they are restored
+var generatedSecrets = []types.NamespacedName{
+	{Namespace: globalconst.CertManagerNamespace, Name: globalconst.DefaultVerrazzanoCASecretName},
+	{Namespace: constants.VerrazzanoSystemNamespace, Name: constants.VMISecret},
+	{Namespace: constants.VerrazzanoSystemNamespace, Name: constants.VMIBackupSecretName},
+	{Namespace: constants.KeycloakNamespace, Name: "mysql"},
+	{Namespace: constants.KeycloakNamespace, Name: "keycloak-http"},
+	{Namespace: common.CattleSystem, Name: common.RancherAdminSecret},
+}
+
+// GetBackupSpec returns the backup of the Verrazzano resource and of the secrets generated by the install.  They are
+// restored before Verrazzano is installed, so that the other components are installed with the same secrets.
+func (c verrazzanoComponent) GetBackupSpec() spi.BackupSpec {
+	return spi.BackupSpec{
+		Order:                0,
+		Method:               spi.BackupMethodResources,
+		RestoreBeforeInstall: true,
+		Kinds: []schema.GroupVersionKind{
+			corev1.SchemeGroupVersion.WithKind("Namespace"),
+			corev1.SchemeGroupVersion.WithKind("Secret"),
+			vzapi.SchemeGroupVersion.WithKind("Verrazzano"),
+		},
+		GetResources: getBackupResources,
+	}
+}
+
+// getBackupResources returns the namespaces of the generated secrets, the generated secrets and the Verrazzano
+// resource, in the order they are restored
+func getBackupResources(ctx spi.ComponentContext) ([]clipkg.Object, error) {
+	vz := ctx.ActualCR()
+	secretNames := append([]types.NamespacedName{}, generatedSecrets...)
+	if certManager := ctx.EffectiveCR().Spec.Components.CertManager; certManager != nil && len(certManager.Certificate.CA.SecretName) > 0 {
+		ca := certManager.Certificate.CA
+		secretNames = append(secretNames, types.NamespacedName{Namespace: ca.ClusterResourceNamespace, Name: ca.SecretName})
+	}
+
+	namespaces := map[string]bool{vz.Namespace: true}
+	var secrets []clipkg.Object
+	for _, name := range secretNames {
+		secret := &corev1.Secret{}
+		if err := ctx.Client().Get(context.TODO(), name, secret); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		namespaces[secret.Namespace] = true
+		secrets = append(secrets, secret)
+	}
+
+	var namespaceNames []string
+	for name := range namespaces {
+		namespaceNames = append(namespaceNames, name)
+	}
+	sort.Strings(namespaceNames)
+	var resources []clipkg.Object
+	for _, name := range namespaceNames {
+		resources = append(resources, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	resources = append(resources, secrets...)
+	return append(resources, vz.DeepCopy()), nil
+}
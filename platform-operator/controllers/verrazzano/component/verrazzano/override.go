@@ -15,6 +15,7 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
 
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/yaml"
 )
 
@@ -103,6 +104,14 @@ func appendVerrazzanoValues(ctx spi.ComponentContext, overrides *verrazzanoValue
 	overrides.Keycloak = &keycloakValues{Enabled: vzconfig.IsKeycloakEnabled(effectiveCR)}
 	overrides.Rancher = &rancherValues{Enabled: vzconfig.IsRancherEnabled(effectiveCR)}
 	overrides.Console = &consoleValues{Enabled: vzconfig.IsConsoleEnabled(effectiveCR)}
+	if console := effectiveCR.Spec.Components.Console; console != nil && console.Kubernetes != nil {
+		overrides.Console.Replicas = console.Kubernetes.Replicas
+		overrides.Console.Affinity = console.Kubernetes.Affinity
+		overrides.Console.Tolerations = console.Kubernetes.Tolerations
+		overrides.Console.NodeSelector = console.Kubernetes.NodeSelector
+		overrides.Console.PriorityClassName = console.Kubernetes.PriorityClassName
+		overrides.Console.Resources = console.Kubernetes.Resources
+	}
 	overrides.NodeExporter = &nodeExporterValues{Enabled: vzconfig.IsVMOEnabled(effectiveCR)}
 	overrides.PrometheusOperator = &prometheusOperatorValues{Enabled: vzconfig.IsPrometheusOperatorEnabled(effectiveCR)}
 	overrides.PrometheusAdapter = &prometheusAdapterValues{Enabled: vzconfig.IsPrometheusAdapterEnabled(effectiveCR)}
@@ -184,14 +193,27 @@ func appendVMIOverrides(effectiveCR *vzapi.Verrazzano, overrides *verrazzanoValu
 	}
 	overrides.ElasticSearch.MultiNodeCluster = multiNodeCluster
 
+	prometheusStorage, err := common.GetComponentStorageOverride(effectiveCR, getPrometheusVolumeSource(effectiveCR), storageOverrides)
+	if err != nil {
+		return kvs, err
+	}
+	var grafanaVolumeSource *corev1.VolumeSource
+	if effectiveCR.Spec.Components.Grafana != nil {
+		grafanaVolumeSource = effectiveCR.Spec.Components.Grafana.VolumeSource
+	}
+	grafanaStorage, err := common.GetComponentStorageOverride(effectiveCR, grafanaVolumeSource, storageOverrides)
+	if err != nil {
+		return kvs, err
+	}
+
 	overrides.Prometheus = &prometheusValues{
 		Enabled:  vzconfig.IsPrometheusEnabled(effectiveCR),
-		Requests: storageOverrides,
+		Requests: prometheusStorage,
 	}
 
 	overrides.Grafana = &grafanaValues{
 		Enabled:  vzconfig.IsGrafanaEnabled(effectiveCR),
-		Requests: storageOverrides,
+		Requests: grafanaStorage,
 	}
 	return kvs, nil
 }
@@ -232,6 +254,14 @@ func appendFluentdOverrides(effectiveCR *vzapi.Verrazzano, overrides *verrazzano
 				APISecret:       fluentd.OCI.APISecret,
 			}
 		}
+		// Overrides for the scheduling and resources of the DaemonSet
+		if fluentd.Kubernetes != nil {
+			overrides.Fluentd.Affinity = fluentd.Kubernetes.Affinity
+			overrides.Fluentd.Tolerations = fluentd.Kubernetes.Tolerations
+			overrides.Fluentd.NodeSelector = fluentd.Kubernetes.NodeSelector
+			overrides.Fluentd.PriorityClassName = fluentd.Kubernetes.PriorityClassName
+			overrides.Fluentd.Resources = fluentd.Kubernetes.Resources
+		}
 	}
 
 	// Force the override to be the internal ES secret if the legacy ES secret is being used.
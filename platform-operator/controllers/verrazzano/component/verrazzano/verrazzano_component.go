@@ -19,8 +19,10 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/nginx"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -68,6 +70,7 @@ func NewComponent() spi.Component {
 			IgnoreNamespaceOverride: true,
 			ResolveNamespaceFunc:    resolveVerrazzanoNamespace,
 			AppendOverridesFunc:     appendVerrazzanoOverrides,
+			GetHelmValueOverrides:   GetHelmOverrides,
 			ImagePullSecretKeyname:  vzImagePullSecretKeyName,
 			SupportsOperatorInstall: true,
 			Dependencies:            []string{istio.ComponentName, nginx.ComponentName, certmanager.ComponentName, authproxy.ComponentName},
@@ -75,6 +78,26 @@ func NewComponent() spi.Component {
 	}
 }
 
+// GetHelmOverrides returns the Helm value overrides for the Verrazzano Helm chart.  The Console, Fluentd and
+// Prometheus are installed by the Verrazzano Helm chart, so their overrides are also values of the chart.
+func GetHelmOverrides(ctx spi.ComponentContext) []vzapi.HelmValueOverrides {
+	var overrides []vzapi.HelmValueOverrides
+	components := ctx.EffectiveCR().Spec.Components
+	if components.Verrazzano != nil {
+		overrides = append(overrides, components.Verrazzano.HelmValueOverrides)
+	}
+	if components.Console != nil {
+		overrides = append(overrides, components.Console.HelmValueOverrides)
+	}
+	if components.Fluentd != nil {
+		overrides = append(overrides, components.Fluentd.HelmValueOverrides)
+	}
+	if components.Prometheus != nil {
+		overrides = append(overrides, components.Prometheus.HelmValueOverrides)
+	}
+	return overrides
+}
+
 // PreInstall Verrazzano component pre-install processing; create and label required namespaces, copy any
 // required secrets
 func (c verrazzanoComponent) PreInstall(ctx spi.ComponentContext) error {
@@ -187,9 +210,12 @@ func (c verrazzanoComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Ve
 	if err := c.checkEnabled(old, new); err != nil {
 		return err
 	}
-	// Reject any other edits except InstallArgs
-	// Do not allow any updates to storage settings via the volumeClaimSpecTemplates/defaultVolumeSource
-	if err := common.CompareStorageOverrides(old, new, ComponentJSONName); err != nil {
+	// Reject changes of the persistence or StorageClass of the Prometheus volume, it can only grow when the
+	// StorageClass allows volume expansion
+	if err := common.CompareVMIVolumeSources(old, new, getPrometheusVolumeSource(old), getPrometheusVolumeSource(new), "prometheus"); err != nil {
+		return err
+	}
+	if err := common.ValidateVMIVolumeSource(new, getPrometheusVolumeSource(new), "prometheus"); err != nil {
 		return err
 	}
 	if err := validateFluentd(new); err != nil {
@@ -200,12 +226,48 @@ func (c verrazzanoComponent) ValidateUpdate(old *vzapi.Verrazzano, new *vzapi.Ve
 
 // ValidateInstall checks if the specified Verrazzano CR is valid for this component to be installed
 func (c verrazzanoComponent) ValidateInstall(vz *vzapi.Verrazzano) error {
+	if err := common.ValidateVMIVolumeSource(vz, getPrometheusVolumeSource(vz), "prometheus"); err != nil {
+		return err
+	}
 	if err := validateFluentd(vz); err != nil {
 		return err
 	}
 	return nil
 }
 
+// getPrometheusVolumeSource returns the volume source of the VMI Prometheus
+func getPrometheusVolumeSource(vz *vzapi.Verrazzano) *corev1.VolumeSource {
+	if vz.Spec.Components.Prometheus == nil {
+		return nil
+	}
+	return vz.Spec.Components.Prometheus.VolumeSource
+}
+
+// ValidateKubernetesSettings checks the kubernetes sections of the console, Fluentd and the VMI Prometheus, which are
+// installed by this component
+func (c verrazzanoComponent) ValidateKubernetesSettings(vz *vzapi.Verrazzano) error {
+	scheduling := []string{vzconfig.KubernetesAffinity, vzconfig.KubernetesTolerations, vzconfig.KubernetesNodeSelector,
+		vzconfig.KubernetesPriorityClassName, vzconfig.KubernetesResources}
+	if console := vz.Spec.Components.Console; console != nil {
+		if err := vzconfig.ValidateKubernetesSettings("console", console.Kubernetes,
+			append(scheduling, vzconfig.KubernetesReplicas)...); err != nil {
+			return err
+		}
+	}
+	if fluentd := vz.Spec.Components.Fluentd; fluentd != nil {
+		if err := vzconfig.ValidateKubernetesSettings("fluentd", fluentd.Kubernetes, scheduling...); err != nil {
+			return err
+		}
+	}
+	if prometheus := vz.Spec.Components.Prometheus; prometheus != nil {
+		if err := vzconfig.ValidateKubernetesSettings("prometheus", prometheus.Kubernetes,
+			vzconfig.KubernetesReplicas, vzconfig.KubernetesResources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // existing Fluentd mount paths can be found at platform-operator/helm_config/charts/verrazzano/templates/verrazzano-logging.yaml
 var existingFluentdMountPaths = [7]string{
 	"/fluentd/cacerts", "/fluentd/secret", "/fluentd/etc",
@@ -226,10 +288,6 @@ func validateFluentd(vz *vzapi.Verrazzano) error {
 }
 
 func (c verrazzanoComponent) checkEnabled(old *vzapi.Verrazzano, new *vzapi.Verrazzano) error {
-	// Do not allow disabling of any component post-install for now
-	if c.IsEnabled(old) && !c.IsEnabled(new) {
-		return fmt.Errorf("Disabling component %s is not allowed", ComponentJSONName)
-	}
 	if vzconfig.IsConsoleEnabled(old) && !vzconfig.IsConsoleEnabled(new) {
 		return fmt.Errorf("Disabling component console not allowed")
 	}
@@ -353,3 +411,25 @@ func validateEntryExist(secret *corev1.Secret, entry string) error {
 	}
 	return nil
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the Verrazzano console and of Fluentd, which sends the logs to
+// OpenSearch in the cluster or to the OpenSearch of the admin cluster
+func (c verrazzanoComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-verrazzano-console", map[string]string{"app": "verrazzano-console"},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.IngressFrom(common.AuthProxyPeers(), 8000),
+				netpolicy.PrometheusIngress(common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.IstiodEgress(),
+			}),
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-fluentd", map[string]string{"app": "fluentd"},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.PrometheusIngress(24231),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo(nil),
+			}),
+	}, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	spi2 "github.com/verrazzano/verrazzano/pkg/controller/errors"
 	helmcli "github.com/verrazzano/verrazzano/pkg/helm"
+	"github.com/verrazzano/verrazzano/pkg/k8sutil"
 	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
@@ -22,6 +23,7 @@ import (
 	v1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -216,6 +218,25 @@ func createPreInstallTestClient(extraObjs ...client.Object) client.Client {
 	return c
 }
 
+// TestGetHelmOverrides tests the GetHelmOverrides function
+// GIVEN a call to GetHelmOverrides
+//  WHEN the Verrazzano, Console and Prometheus components have Helm value overrides
+//  THEN the overrides of all the components installed by the Verrazzano Helm chart are returned
+func TestGetHelmOverrides(t *testing.T) {
+	overrides := func(name string) vzapi.HelmValueOverrides {
+		return vzapi.HelmValueOverrides{ValueOverrides: []vzapi.Overrides{{
+			ConfigMapRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: "values.yaml"},
+		}}}
+	}
+	cr := &vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{Components: vzapi.ComponentSpec{
+		Verrazzano: &vzapi.VerrazzanoComponent{HelmValueOverrides: overrides("verrazzano")},
+		Console:    &vzapi.ConsoleComponent{HelmValueOverrides: overrides("console")},
+		Prometheus: &vzapi.PrometheusComponent{HelmValueOverrides: overrides("prometheus")},
+	}}}
+	assert.Equal(t, []vzapi.HelmValueOverrides{overrides("verrazzano"), overrides("console"), overrides("prometheus")},
+		GetHelmOverrides(spi.NewFakeContext(nil, cr, false)))
+}
+
 // TestIsEnabledNilVerrazzano tests the IsEnabled function
 // GIVEN a call to IsEnabled
 //  WHEN The Verrazzano component is nil
@@ -269,6 +290,9 @@ func getBoolPtr(b bool) *bool {
 }
 
 func Test_verrazzanoComponent_ValidateUpdate(t *testing.T) {
+	// The resized volumes have the default StorageClass, which does not allow volume expansion
+	k8sutil.SetFakeClient(k8sfake.NewSimpleClientset())
+	defer k8sutil.ClearFakeClient()
 	disabled := false
 	var pvc1Gi, _ = resource.ParseQuantity("1Gi")
 	var pvc2Gi, _ = resource.ParseQuantity("2Gi")
@@ -306,7 +330,7 @@ func Test_verrazzanoComponent_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "change-installargs",
@@ -5,6 +5,7 @@ package verrazzano
 
 import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // verrazzanoValues Struct representing the Verrazzano Helm chart values
@@ -119,14 +120,25 @@ type loggingValues struct {
 }
 
 type fluentdValues struct {
-	Enabled           bool                `json:"enabled"` // Always write
-	ExtraVolumeMounts []volumeMount       `json:"extraVolumeMounts,omitempty"`
-	OCI               *ociLoggingSettings `json:"oci,omitempty"`
+	Enabled           bool                         `json:"enabled"` // Always write
+	ExtraVolumeMounts []volumeMount                `json:"extraVolumeMounts,omitempty"`
+	OCI               *ociLoggingSettings          `json:"oci,omitempty"`
+	Affinity          *corev1.Affinity             `json:"affinity,omitempty"`
+	Tolerations       []corev1.Toleration          `json:"tolerations,omitempty"`
+	NodeSelector      map[string]string            `json:"nodeSelector,omitempty"`
+	PriorityClassName string                       `json:"priorityClassName,omitempty"`
+	Resources         *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 type consoleValues struct {
-	Enabled bool   `json:"enabled"` // Always write
-	Name    string `json:"name,omitempty"`
+	Enabled           bool                         `json:"enabled"` // Always write
+	Name              string                       `json:"name,omitempty"`
+	Replicas          uint32                       `json:"replicas,omitempty"`
+	Affinity          *corev1.Affinity             `json:"affinity,omitempty"`
+	Tolerations       []corev1.Toleration          `json:"tolerations,omitempty"`
+	NodeSelector      map[string]string            `json:"nodeSelector,omitempty"`
+	PriorityClassName string                       `json:"priorityClassName,omitempty"`
+	Resources         *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 type apiValues struct {
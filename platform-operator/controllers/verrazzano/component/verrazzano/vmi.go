@@ -16,7 +16,12 @@ const (
 
 // updateFunc is passed into CreateOrUpdateVMI to create the necessary VMI resources
 func updateFunc(ctx spi.ComponentContext, storage *common.ResourceRequestValues, vmi *vmov1.VerrazzanoMonitoringInstance, existingVMI *vmov1.VerrazzanoMonitoringInstance) error {
-	vmi.Spec.Prometheus = newPrometheus(ctx.EffectiveCR(), storage, existingVMI)
+	cr := ctx.EffectiveCR()
+	storage, err := common.GetComponentStorageOverride(cr, getPrometheusVolumeSource(cr), storage)
+	if err != nil {
+		return err
+	}
+	vmi.Spec.Prometheus = newPrometheus(cr, storage, existingVMI)
 	return nil
 }
 
@@ -32,9 +37,16 @@ func newPrometheus(cr *vzapi.Verrazzano, storage *common.ResourceRequestValues,
 		},
 		Storage: vmov1.Storage{},
 	}
+	if k8s := prometheusValues.Kubernetes; k8s != nil && k8s.Replicas > 0 {
+		prometheus.Replicas = int32(k8s.Replicas)
+	}
+	common.SetResources(prometheusValues.Kubernetes, &prometheus.Resources)
 	common.SetStorageSize(storage, &prometheus.Storage)
 	if vmi != nil {
-		prometheus.Storage = vmi.Spec.Prometheus.Storage
+		// preserve the PVC names and availability domain since these are set by the VMO, the VMO resizes the PVCs
+		// when the size grows
+		prometheus.Storage.PvcNames = vmi.Spec.Prometheus.Storage.PvcNames
+		prometheus.Storage.AvailabilityDomain = vmi.Spec.Prometheus.Storage.AvailabilityDomain
 	}
 
 	return prometheus
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
 )
@@ -60,3 +61,20 @@ func TestPrometheusWithStorageOverride(t *testing.T) {
 	prometheus := newPrometheus(&vmiEnabledCR, &common.ResourceRequestValues{Storage: "100Gi"}, nil)
 	assert.Equal(t, "100Gi", prometheus.Storage.Size)
 }
+
+// TestNewPrometheusWithExistingVMI tests that the storage size of an existing VMI is updated
+// GIVEN a Verrazzano CR with a storage override of 100Gi and an existing VMI with 50Gi of storage
+// WHEN I create a new Prometheus resource
+// THEN the storage is 100Gi, and the PVC names of the existing VMI are preserved so that the VMO resizes the PVC
+func TestNewPrometheusWithExistingVMI(t *testing.T) {
+	existingVMI := &vmov1.VerrazzanoMonitoringInstance{
+		Spec: vmov1.VerrazzanoMonitoringInstanceSpec{
+			Prometheus: vmov1.Prometheus{
+				Storage: vmov1.Storage{Size: "50Gi", PvcNames: []string{"vmi-system-prometheus"}},
+			},
+		},
+	}
+	prometheus := newPrometheus(&vmiEnabledCR, &common.ResourceRequestValues{Storage: "100Gi"}, existingVMI)
+	assert.Equal(t, "100Gi", prometheus.Storage.Size)
+	assert.Equal(t, []string{"vmi-system-prometheus"}, prometheus.Storage.PvcNames)
+}
@@ -12,8 +12,10 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/nginx"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
 	appsv1 "k8s.io/api/apps/v1"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"path/filepath"
@@ -89,3 +91,18 @@ func (c vmoComponent) PreUpgrade(context spi.ComponentContext) error {
 func (c vmoComponent) Upgrade(context spi.ComponentContext) error {
 	return c.HelmComponent.Install(context)
 }
+
+// GetNetworkPolicies returns the NetworkPolicies of the Verrazzano Monitoring Operator, which configures OpenSearch,
+// OpenSearch Dashboards and Grafana
+func (c vmoComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(ComponentNamespace, "allow-verrazzano-monitoring-operator", map[string]string{"k8s-app": ComponentName},
+			[]netv1.NetworkPolicyIngressRule{
+				netpolicy.PrometheusIngress(8080, 8090, common.EnvoyStatsPort),
+			},
+			[]netv1.NetworkPolicyEgressRule{
+				netpolicy.EgressTo([]netv1.NetworkPolicyPeer{netpolicy.PodPeer("", nil)}),
+				netpolicy.IstiodEgress(),
+			}),
+	}, nil
+}
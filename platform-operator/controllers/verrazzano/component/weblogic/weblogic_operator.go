@@ -16,12 +16,15 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// serviceAccountName is the name of the service account of the WebLogic operator
+const serviceAccountName = "weblogic-operator-sa"
+
 // AppendWeblogicOperatorOverrides appends the WKO-specific helm Value overrides.
 func AppendWeblogicOperatorOverrides(_ spi.ComponentContext, _ string, _ string, _ string, kvs []bom.KeyValue) ([]bom.KeyValue, error) {
 	keyValueOverrides := []bom.KeyValue{
 		{
 			Key:   "serviceAccount",
-			Value: "weblogic-operator-sa",
+			Value: serviceAccountName,
 		},
 		{
 			Key:   "domainNamespaceSelectionStrategy",
@@ -48,16 +51,15 @@ func AppendWeblogicOperatorOverrides(_ spi.ComponentContext, _ string, _ string,
 
 func WeblogicOperatorPreInstall(ctx spi.ComponentContext, _ string, namespace string, _ string) error {
 	var serviceAccount corev1.ServiceAccount
-	const accountName = "weblogic-operator-sa"
 	c := ctx.Client()
-	if err := c.Get(context.TODO(), types.NamespacedName{Name: accountName, Namespace: namespace}, &serviceAccount); err != nil {
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: serviceAccountName, Namespace: namespace}, &serviceAccount); err != nil {
 		if !errors.IsNotFound(err) {
 			return err
 		}
 	}
 	serviceAccount = corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      accountName,
+			Name:      serviceAccountName,
 			Namespace: namespace,
 		},
 	}
@@ -7,7 +7,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	vzctrl "github.com/verrazzano/verrazzano/pkg/controller"
@@ -22,9 +24,9 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/rbac"
-	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/uninstalljob"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/transform"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/vzinstance"
-	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
 	"go.uber.org/zap"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -33,6 +35,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -46,9 +49,10 @@ import (
 // Reconciler reconciles a Verrazzano object
 type Reconciler struct {
 	client.Client
-	Scheme     *runtime.Scheme
-	Controller controller.Controller
-	DryRun     bool
+	Scheme        *runtime.Scheme
+	Controller    controller.Controller
+	DryRun        bool
+	EventRecorder record.EventRecorder
 }
 
 // Name of finalizer
@@ -80,6 +84,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// If the resource is not found, that means all of the finalizers have been removed,
 		// and the Verrazzano resource has been deleted, so there is nothing left to do.
 		if errors.IsNotFound(err) {
+			metrics.DeleteVerrazzano(req.Namespace, req.Name)
 			return reconcile.Result{}, nil
 		}
 		zap.S().Errorf("Failed to fetch Verrazzano resource: %v", err)
@@ -100,6 +105,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	log.Oncef("Reconciling Verrazzano resource %v, generation %v, version %s", req.NamespacedName, vz.Generation, vz.Status.Version)
 	res, err := r.doReconcile(ctx, log, vz)
+	metrics.RecordVerrazzanoStatus(vz)
 	if vzctrl.ShouldRequeue(res) {
 		return res, nil
 	}
@@ -189,13 +195,33 @@ func (r *Reconciler) ProcReadyState(vzctx vzcontext.VerrazzanoContext) (ctrl.Res
 		return result, nil
 	}
 
+	// In plan mode only report the changes that would be made, the components are left unchanged
+	if isPlanRequested(actualCR) {
+		return r.procPlan(log, actualCR)
+	}
+	if actualCR.Status.Plan != nil {
+		actualCR.Status.Plan = nil
+		if err := r.updateVerrazzanoStatus(log, actualCR); err != nil {
+			return newRequeueWithDelay(), err
+		}
+	}
+
 	// If Verrazzano is installed see if upgrade is needed
 	if isInstalled(actualCR.Status) {
 		if len(actualCR.Spec.Version) > 0 && actualCR.Spec.Version != actualCR.Status.Version {
+			// Check the target cluster before the upgrade is started, the upgrade is blocked until the failed checks
+			// are fixed
+			if passed, err := r.runPreflightChecks(vzctx, vzconst.UpgradeOperation); err != nil || !passed {
+				return newRequeueWithDelay(), err
+			}
 			// Transition to upgrade state
 			r.updateVzState(log, actualCR, installv1alpha1.VzStateUpgrading)
 			return newRequeueWithDelay(), err
 		}
+		// Keep the chain of profiles in the status current with the Verrazzano resource
+		if err := r.refreshProfileChain(log, actualCR); err != nil {
+			return newRequeueWithDelay(), err
+		}
 		// Keep retrying to reconcile components until it completes
 		if result, err := r.reconcileComponents(vzctx); err != nil {
 			return newRequeueWithDelay(), err
@@ -203,15 +229,28 @@ func (r *Reconciler) ProcReadyState(vzctx vzcontext.VerrazzanoContext) (ctrl.Res
 			return result, nil
 		}
 
-		return ctrl.Result{}, nil
-	}
+		// Periodically check that the installed components have not been changed outside of Verrazzano
+		if err := r.checkComponentDrift(vzctx); err != nil {
+			return newRequeueWithDelay(), err
+		}
 
-	// if an OCI DNS installation, make sure the secret required exists before proceeding
-	if actualCR.Spec.Components.DNS != nil && actualCR.Spec.Components.DNS.OCI != nil {
-		err := r.doesOCIDNSConfigSecretExist(actualCR)
-		if err != nil {
+		// Advance the staged rotation of the CA of the Verrazzano ClusterIssuer
+		if err := r.checkCARotation(vzctx); err != nil {
+			return newRequeueWithDelay(), err
+		}
+
+		// Periodically update the inventory of the certificates, and renew the certificates that are requested
+		if err := r.checkCertificates(vzctx); err != nil {
 			return newRequeueWithDelay(), err
 		}
+
+		// Periodically check that the installed components are still ready
+		return r.checkComponentHealth(vzctx)
+	}
+
+	// Check the target cluster before the install is started, the install is blocked until the failed checks are fixed
+	if passed, err := r.runPreflightChecks(vzctx, vzconst.InstallOperation); err != nil || !passed {
+		return newRequeueWithDelay(), err
 	}
 
 	// Pre-create the Verrazzano System namespace if it doesn't already exist, before kicking off the install job,
@@ -236,7 +275,8 @@ func (r *Reconciler) ProcReadyState(vzctx vzcontext.VerrazzanoContext) (ctrl.Res
 		return ctrl.Result{}, nil
 	}
 
-	// Delete leftover uninstall job if we find one.
+	// Delete the uninstall job left over by an operator from an earlier version, which uninstalled Verrazzano with
+	// the uninstall scripts
 	err = r.cleanupUninstallJob(buildUninstallJobName(actualCR.Name), getInstallNamespace(), log)
 	if err != nil {
 		return newRequeueWithDelay(), err
@@ -347,6 +387,17 @@ func (r *Reconciler) ProcPausedUpgradeState(vzctx vzcontext.VerrazzanoContext) (
 		return r.procDelete(context.TODO(), log, vz)
 	}
 
+	// A staged upgrade stays paused until the stage is approved
+	if stage, approved := getPausedUpgradeStage(vz); stage != nil {
+		if !approved {
+			log.Progressf("Upgrade stage %s is waiting for approval with the %s annotation set to %s", stage.name, vzconst.UpgradeApprovedStage, getStageApproval(vz, stage.name))
+			return newRequeueWithDelay(), nil
+		}
+		err := r.updateStatus(log, vz, fmt.Sprintf("Verrazzano upgrade to version %s in progress, upgrade stage %s has been approved", vz.Spec.Version, stage.name),
+			installv1alpha1.CondUpgradeStarted)
+		return newRequeueWithDelay(), err
+	}
+
 	// check if the VPO and VZ versions are the same and the upgrade can proceed
 	if isOperatorSameVersionAsCR(vz.Spec.Version) {
 		// upgrade can proceed from paused state
@@ -371,8 +422,22 @@ func (r *Reconciler) ProcFailedState(vzctx vzcontext.VerrazzanoContext) (ctrl.Re
 		return r.procDelete(ctx, log, vz)
 	}
 
+	// Determine if the user specified to retry a failed install
+	retry, err := r.retryInstall(ctx, vz)
+	if err != nil {
+		log.Errorf("Failed to update the annotations: %v", err)
+		return newRequeueWithDelay(), err
+	}
+	if retry {
+		// The failed components are installed again, with new install timeouts
+		log.Infof("Install retry version annotation has changed, retrying install")
+		deleteInstallStartTimes(vz)
+		err = r.updateVzState(log, vz, installv1alpha1.VzStateReady)
+		return ctrl.Result{Requeue: true, RequeueAfter: 1}, err
+	}
+
 	// Determine if the user specified to retry upgrade
-	retry, err := r.retryUpgrade(ctx, vz)
+	retry, err = r.retryUpgrade(ctx, vz)
 	if err != nil {
 		log.Errorf("Failed to update the annotations: %v", err)
 		return newRequeueWithDelay(), err
@@ -399,17 +464,6 @@ func (r *Reconciler) ProcFailedState(vzctx vzcontext.VerrazzanoContext) (ctrl.Re
 	return ctrl.Result{}, nil
 }
 
-// doesOCIDNSConfigSecretExist returns true if the DNS secret exists
-func (r *Reconciler) doesOCIDNSConfigSecretExist(vz *installv1alpha1.Verrazzano) error {
-	// ensure the secret exists before proceeding
-	secret := &corev1.Secret{}
-	err := r.Get(context.TODO(), types.NamespacedName{Name: vz.Spec.Components.DNS.OCI.OCIConfigSecret, Namespace: vzconst.VerrazzanoInstallNamespace}, secret)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 // createServiceAccount creates a required service account
 func (r *Reconciler) createServiceAccount(ctx context.Context, log vzlog.VerrazzanoLogger, vz *installv1alpha1.Verrazzano) error {
 	// Define a new service account resource
@@ -512,7 +566,8 @@ func (r *Reconciler) checkInstallComplete(vzctx vzcontext.VerrazzanoContext) (bo
 	return true, r.updateStatus(log, actualCR, message, installv1alpha1.CondInstallComplete)
 }
 
-// cleanupUninstallJob checks for the existence of a stale uninstall job and deletes the job if one is found
+// cleanupUninstallJob checks for the existence of a stale uninstall job and deletes the job if one is found.  The
+// uninstall job was created by an operator from an earlier version, it is cleaned up once the operator is upgraded.
 func (r *Reconciler) cleanupUninstallJob(jobName string, namespace string, log vzlog.VerrazzanoLogger) error {
 	// Check if the job for running the uninstall scripts exist
 	jobFound := &batchv1.Job{}
@@ -555,46 +610,8 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return err
 }
 
-func (r *Reconciler) createUninstallJob(log vzlog.VerrazzanoLogger, vz *installv1alpha1.Verrazzano) error {
-	// Define a new uninstall job resource
-	job := uninstalljob.NewJob(
-		&uninstalljob.JobConfig{
-			JobConfigCommon: k8s.JobConfigCommon{
-				JobName:            buildUninstallJobName(vz.Name),
-				Namespace:          getInstallNamespace(),
-				Labels:             vz.Labels,
-				ServiceAccountName: buildServiceAccountName(vz.Name),
-				JobImage:           os.Getenv("VZ_INSTALL_IMAGE"),
-				DryRun:             r.DryRun,
-			},
-		},
-	)
-
-	// Check if the job for running the uninstall scripts exist
-	jobFound := &batchv1.Job{}
-	log.Debugf("Checking if uninstall job %s exist", buildUninstallJobName(vz.Name))
-	err := r.Get(context.TODO(), types.NamespacedName{Name: buildUninstallJobName(vz.Name), Namespace: getInstallNamespace()}, jobFound)
-	if err != nil && errors.IsNotFound(err) {
-		log.Infof("Creating uninstall job %s, dry-run=%v", buildUninstallJobName(vz.Name), r.DryRun)
-		err = r.Create(context.TODO(), job)
-		if err != nil {
-			return err
-		}
-	} else if err != nil {
-		return err
-	}
-
-	log.Progressf("Uninstall job %s is running", buildUninstallJobName(vz.Name))
-
-	err = r.setUninstallCondition(log, jobFound, vz)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// buildUninstallJobName returns the name of an uninstall job based on Verrazzano resource name.
+// buildUninstallJobName returns the name of an uninstall job based on Verrazzano resource name.  The uninstall job is
+// only created by an operator from an earlier version, the name is used to clean it up.
 func buildUninstallJobName(name string) string {
 	return fmt.Sprintf("verrazzano-uninstall-%s", name)
 }
@@ -644,14 +661,11 @@ func getVzAndOperatorVersions(vzVersion string) (*semver.SemVersion, *semver.Sem
 
 // updateStatus updates the status in the Verrazzano CR
 func (r *Reconciler) updateStatus(log vzlog.VerrazzanoLogger, cr *installv1alpha1.Verrazzano, message string, conditionType installv1alpha1.ConditionType) error {
-	t := time.Now().UTC()
 	condition := installv1alpha1.Condition{
-		Type:    conditionType,
-		Status:  corev1.ConditionTrue,
-		Message: message,
-		LastTransitionTime: fmt.Sprintf("%d-%02d-%02dT%02d:%02d:%02dZ",
-			t.Year(), t.Month(), t.Day(),
-			t.Hour(), t.Minute(), t.Second()),
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		Message:            message,
+		LastTransitionTime: formatStatusTime(time.Now()),
 	}
 	cr.Status.Conditions = append(cr.Status.Conditions, condition)
 
@@ -660,7 +674,11 @@ func (r *Reconciler) updateStatus(log vzlog.VerrazzanoLogger, cr *installv1alpha
 	log.Debugf("Setting Verrazzano resource condition and state: %v/%v", condition.Type, cr.Status.State)
 
 	// Update the status
-	return r.updateVerrazzanoStatus(log, cr)
+	if err := r.updateVerrazzanoStatus(log, cr); err != nil {
+		return err
+	}
+	r.recordConditionEvent(cr, "", conditionType, message)
+	return nil
 }
 
 // updateVzState updates the status state in the Verrazzano CR
@@ -674,14 +692,11 @@ func (r *Reconciler) updateVzState(log vzlog.VerrazzanoLogger, cr *installv1alph
 }
 
 func (r *Reconciler) updateComponentStatus(compContext spi.ComponentContext, message string, conditionType installv1alpha1.ConditionType) error {
-	t := time.Now().UTC()
 	condition := installv1alpha1.Condition{
-		Type:    conditionType,
-		Status:  corev1.ConditionTrue,
-		Message: message,
-		LastTransitionTime: fmt.Sprintf("%d-%02d-%02dT%02d:%02d:%02dZ",
-			t.Year(), t.Month(), t.Day(),
-			t.Hour(), t.Minute(), t.Second()),
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		Message:            message,
+		LastTransitionTime: formatStatusTime(time.Now()),
 	}
 
 	componentName := compContext.GetComponent()
@@ -711,13 +726,22 @@ func (r *Reconciler) updateComponentStatus(compContext spi.ComponentContext, mes
 			componentStatus.ReconcilingGeneration = cr.Generation
 		}
 	}
+	oldState := componentStatus.State
+	oldConditionCount := len(componentStatus.Conditions)
 	componentStatus.Conditions = appendConditionIfNecessary(log, componentStatus, condition)
 
 	// Set the state of resource
 	componentStatus.State = checkCondtitionType(conditionType)
 
 	// Update the status
-	return r.updateVerrazzanoStatus(log, cr)
+	if err := r.updateVerrazzanoStatus(log, cr); err != nil {
+		return err
+	}
+	// Only record the transitions, the same condition is set again while a component stays in a state
+	if componentStatus.State != oldState || len(componentStatus.Conditions) != oldConditionCount {
+		r.recordConditionEvent(cr, componentName, conditionType, message)
+	}
+	return nil
 }
 
 func appendConditionIfNecessary(log vzlog.VerrazzanoLogger, compStatus *installv1alpha1.ComponentStatusDetails, newCondition installv1alpha1.Condition) []installv1alpha1.Condition {
@@ -743,9 +767,11 @@ func checkCondtitionType(currentCondition installv1alpha1.ConditionType) install
 	case installv1alpha1.CondUpgradePaused:
 		return installv1alpha1.CompStateUpgrading
 	case installv1alpha1.CondUninstallComplete:
-		return installv1alpha1.CompStateReady
+		return installv1alpha1.CompStateDisabled
 	case installv1alpha1.CondInstallFailed, installv1alpha1.CondUpgradeFailed, installv1alpha1.CondUninstallFailed:
 		return installv1alpha1.CompStateFailed
+	case installv1alpha1.CondDegraded:
+		return installv1alpha1.CompStateDegraded
 	}
 	// Return ready for installv1alpha1.CondInstallComplete, installv1alpha1.CondUpgradeComplete
 	return installv1alpha1.CompStateReady
@@ -764,7 +790,9 @@ func conditionToVzState(currentCondition installv1alpha1.ConditionType) installv
 		return installv1alpha1.VzStatePaused
 	case installv1alpha1.CondUninstallComplete:
 		return installv1alpha1.VzStateReady
-	case installv1alpha1.CondInstallFailed, installv1alpha1.CondUpgradeFailed, installv1alpha1.CondUninstallFailed:
+	case installv1alpha1.CondInstallFailed, installv1alpha1.CondUpgradeFailed, installv1alpha1.CondUninstallFailed,
+		installv1alpha1.CondUpgradeRolledBack:
+		// A rolled back upgrade is retried the same way as a failed upgrade, see ProcFailedState
 		return installv1alpha1.VzStateFailed
 	}
 	// Return ready for installv1alpha1.CondInstallComplete, installv1alpha1.CondUpgradeComplete
@@ -780,9 +808,95 @@ func (r *Reconciler) setInstallingState(log vzlog.VerrazzanoLogger, vz *installv
 	}
 
 	vz.Status.Version = bomSemVer.ToString()
+
+	// Set the chain of profiles that the components are installed from
+	profileChain, err := transform.GetProfileChain(vz)
+	if err != nil {
+		return err
+	}
+	vz.Status.ProfileChain = profileChain
 	return r.updateStatus(log, vz, "Verrazzano install in progress", installv1alpha1.CondInstallStarted)
 }
 
+// profileChainGenerationMap has the generation of each Verrazzano resource when the chain of profiles in its status was
+// last refreshed, keyed by the resource
+var profileChainGenerationMap = map[string]int64{}
+
+// profileChainMutex guards profileChainGenerationMap, which is updated by the profile watch and the reconcile
+var profileChainMutex sync.Mutex
+
+// refreshProfileChain updates the chain of profiles in the status when the generation of the Verrazzano resource
+// changes, or when a custom profile ConfigMap changes, the custom profiles in the chain may have been changed since
+// the install
+func (r *Reconciler) refreshProfileChain(log vzlog.VerrazzanoLogger, vz *installv1alpha1.Verrazzano) error {
+	key := getNSNKey(vz)
+	profileChainMutex.Lock()
+	generation, ok := profileChainGenerationMap[key]
+	profileChainMutex.Unlock()
+	if ok && generation == vz.Generation {
+		return nil
+	}
+	profileChain, err := transform.GetProfileChain(vz)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(vz.Status.ProfileChain, profileChain) {
+		vz.Status.ProfileChain = profileChain
+		if err := r.updateVerrazzanoStatus(log, vz); err != nil {
+			return err
+		}
+	}
+	profileChainMutex.Lock()
+	defer profileChainMutex.Unlock()
+	profileChainGenerationMap[key] = vz.Generation
+	return nil
+}
+
+// deleteProfileChainGeneration forgets the generation of the last refresh of the chain of profiles, the chain is
+// refreshed by the next reconcile
+func deleteProfileChainGeneration(cr *installv1alpha1.Verrazzano) {
+	profileChainMutex.Lock()
+	defer profileChainMutex.Unlock()
+	delete(profileChainGenerationMap, getNSNKey(cr))
+}
+
+// watchProfiles watches the ConfigMaps of the custom profiles in the install namespace.  A change to a custom profile
+// triggers a reconcile that refreshes the chain of profiles in the status of the Verrazzano resource.
+func (r *Reconciler) watchProfiles(namespace string, name string, log vzlog.VerrazzanoLogger) error {
+	p := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isProfileConfigMap(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return (isProfileConfigMap(e.ObjectOld) || isProfileConfigMap(e.ObjectNew)) && isProfileConfigMapChanged(e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isProfileConfigMap(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+	eventHandler := handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		log.Debugf("Custom profile ConfigMap %s/%s has changed, refreshing the chain of profiles", obj.GetNamespace(), obj.GetName())
+		deleteProfileChainGeneration(&installv1alpha1.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}})
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+	})
+
+	log.Debugf("Watching for custom profile ConfigMaps for Verrazzano CR %s/%s", namespace, name)
+	return r.Controller.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, eventHandler, p)
+}
+
+// isProfileConfigMap returns true if the object is a labelled custom profile ConfigMap in the install namespace
+func isProfileConfigMap(obj client.Object) bool {
+	return obj.GetNamespace() == vzconst.VerrazzanoInstallNamespace && obj.GetLabels()[vzconst.VerrazzanoProfileLabel] == "true"
+}
+
+// isProfileConfigMapChanged returns true if the profile label or the data of a custom profile ConfigMap has changed
+func isProfileConfigMapChanged(oldObj client.Object, newObj client.Object) bool {
+	return isProfileConfigMap(oldObj) != isProfileConfigMap(newObj) || isOverrideDataChanged(oldObj, newObj)
+}
+
 // checkComponentReadyState returns true if all component-level status' are "CompStateReady" for enabled components
 func (r *Reconciler) checkComponentReadyState(vzctx vzcontext.VerrazzanoContext) (bool, error) {
 	cr := vzctx.ActualCR
@@ -795,13 +909,18 @@ func (r *Reconciler) checkComponentReadyState(vzctx vzcontext.VerrazzanoContext)
 		return true, nil
 	}
 
+	spiCtx, err := spi.NewContext(vzctx.Log, r.Client, vzctx.ActualCR, r.DryRun)
+	if err != nil {
+		vzctx.Log.Errorf("Failed to create component context: %v", err)
+		return false, err
+	}
+	// Keep the health rollup current while the components are installed
+	if err := r.updateHealth(spiCtx, nil); err != nil {
+		return false, err
+	}
+
 	// Return false if any enabled component is not ready
 	for _, comp := range registry.GetComponents() {
-		spiCtx, err := spi.NewContext(vzctx.Log, r.Client, vzctx.ActualCR, r.DryRun)
-		if err != nil {
-			spiCtx.Log().Errorf("Failed to create component context: %v", err)
-			return false, err
-		}
 		if comp.IsEnabled(spiCtx.EffectiveCR()) && cr.Status.Components[comp.Name()].State != installv1alpha1.CompStateReady {
 			return false, nil
 		}
@@ -862,50 +981,6 @@ func (r *Reconciler) initializeComponentStatus(log vzlog.VerrazzanoLogger, cr *i
 	return ctrl.Result{}, nil
 }
 
-// setUninstallCondition sets the Verrazzano resource condition in status for uninstall
-func (r *Reconciler) setUninstallCondition(log vzlog.VerrazzanoLogger, job *batchv1.Job, vz *installv1alpha1.Verrazzano) (err error) {
-	// If the job has succeeded or failed add the appropriate condition
-	if job.Status.Succeeded != 0 || job.Status.Failed != 0 {
-		for _, condition := range vz.Status.Conditions {
-			if condition.Type == installv1alpha1.CondUninstallComplete || condition.Type == installv1alpha1.CondUninstallFailed {
-				return nil
-			}
-		}
-
-		// Remove the owner reference so that the install job is not deleted when the Verrazzano resource is deleted
-		job.SetOwnerReferences([]metav1.OwnerReference{})
-
-		// Update the job
-		err := r.Status().Update(context.TODO(), job)
-		if err != nil {
-			log.Errorf("Failed to update uninstall job owner references: %v", err)
-			return err
-		}
-
-		var message string
-		var conditionType installv1alpha1.ConditionType
-		if job.Status.Succeeded == 1 {
-			message = "Successfullly uninstalled Verrazzano"
-			conditionType = installv1alpha1.CondUninstallComplete
-			log.Info(message)
-		} else {
-			message = "Failed to uninstall Verrazzano"
-			conditionType = installv1alpha1.CondUninstallFailed
-			log.Error(message)
-		}
-		return r.updateStatus(log, vz, message, conditionType)
-	}
-
-	// Add the uninstall started condition if not already added
-	for _, condition := range vz.Status.Conditions {
-		if condition.Type == installv1alpha1.CondUninstallStarted {
-			return nil
-		}
-	}
-
-	return r.updateStatus(log, vz, "CompStateInstalling Verrazzano", installv1alpha1.CondUninstallStarted)
-}
-
 // getInternalConfigMap Convenience method for getting the saved install ConfigMap
 func (r *Reconciler) getInternalConfigMap(ctx context.Context, vz *installv1alpha1.Verrazzano) (installConfig *corev1.ConfigMap, err error) {
 	key := client.ObjectKey{
@@ -1047,19 +1122,30 @@ func getInstallNamespace() string {
 }
 
 func (r *Reconciler) retryUpgrade(ctx context.Context, vz *installv1alpha1.Verrazzano) (bool, error) {
+	return r.isRetryRequested(ctx, vz, vzconst.UpgradeRetryVersion, vzconst.ObservedUpgradeRetryVersion)
+}
+
+// retryInstall returns true if the install retry version annotation has changed, see ComponentTimeouts
+func (r *Reconciler) retryInstall(ctx context.Context, vz *installv1alpha1.Verrazzano) (bool, error) {
+	return r.isRetryRequested(ctx, vz, vzconst.InstallRetryVersion, vzconst.ObservedInstallRetryVersion)
+}
+
+// isRetryRequested returns true if the retry version annotation is different from the observed retry version
+// annotation, and updates the observed retry version annotation
+func (r *Reconciler) isRetryRequested(ctx context.Context, vz *installv1alpha1.Verrazzano, retryAnnotation string, observedAnnotation string) (bool, error) {
 	// get the user-specified restart version - if it's missing then there's nothing to do here
-	restartVersion, ok := vz.Annotations[vzconst.UpgradeRetryVersion]
+	restartVersion, ok := vz.Annotations[retryAnnotation]
 	if !ok {
 		return false, nil
 	}
 
 	// get the annotation with the previous restart version - if it's missing or the versions do not
 	// match, then return true
-	prevRestartVersion, ok := vz.Annotations[vzconst.ObservedUpgradeRetryVersion]
+	prevRestartVersion, ok := vz.Annotations[observedAnnotation]
 	if !ok || restartVersion != prevRestartVersion {
 
 		// add/update the previous restart version annotation to the CR
-		vz.Annotations[vzconst.ObservedUpgradeRetryVersion] = restartVersion
+		vz.Annotations[observedAnnotation] = restartVersion
 		err := r.Client.Update(ctx, vz)
 		return true, err
 	}
@@ -1074,14 +1160,12 @@ func (r *Reconciler) procDelete(ctx context.Context, log vzlog.VerrazzanoLogger,
 	}
 	log.Once("Deleting Verrazzano installation")
 
-	// Create the uninstall job if it doesn't exist
-	if err := r.createUninstallJob(log, vz); err != nil {
-		if errors.IsConflict(err) {
-			log.Debug("Resource conflict creating the uninstall job, requeuing")
-		} else {
-			log.Errorf("Failed creating the uninstall job: %v", err)
+	// Uninstall the components if the uninstall has not finished
+	if !isUninstallDone(vz.Status) {
+		res, err := r.reconcileUninstall(log, vz)
+		if err != nil || vzctrl.ShouldRequeue(res) {
+			return res, err
 		}
-		return newRequeueWithDelay(), err
 	}
 
 	// Remove the finalizer and update the Verrazzano resource if the uninstall has finished.
@@ -1108,6 +1192,12 @@ func (r *Reconciler) procDelete(ctx context.Context, log vzlog.VerrazzanoLogger,
 			}
 
 			delete(initializedSet, vz.Name)
+			deleteDriftCheck(vz)
+			deleteCertificateCheck(vz)
+			deleteOverridesChanged(vz)
+			deleteComponentErrors(vz)
+			deleteInstallStartTimes(vz)
+			deleteProfileChainGeneration(vz)
 			// Uninstall is done, all cleanup is finished, and finalizer removed.
 			return ctrl.Result{}, nil
 		}
@@ -1123,12 +1213,24 @@ func (r *Reconciler) cleanup(ctx context.Context, log vzlog.VerrazzanoLogger, vz
 		return err
 	}
 
+	// Delete the NetworkPolicies of the components
+	err = r.deleteNetworkPolicies(log)
+	if err != nil {
+		return err
+	}
+
 	// Delete install service account
 	err = r.deleteServiceAccount(ctx, log, vz, getInstallNamespace())
 	if err != nil {
 		return err
 	}
 
+	// Delete the Verrazzano CRDs, RBAC resources and namespaces that are not owned by a component
+	err = r.deleteVerrazzanoResources(log, vz)
+	if err != nil {
+		return err
+	}
+
 	// Delete the verrazzano-system namespace
 	err = r.deleteNamespace(ctx, log, vzconst.VerrazzanoSystemNamespace)
 	if err != nil {
@@ -1297,6 +1399,24 @@ func (r *Reconciler) initForVzResource(vz *installv1alpha1.Verrazzano, log vzlog
 		return newRequeueWithDelay(), err
 	}
 
+	// Watch the ConfigMaps and Secrets with Helm value overrides of the components that monitor them
+	if err := r.watchHelmOverrides(vz.Namespace, vz.Name, log); err != nil {
+		log.Errorf("Failed to set ConfigMap and Secret watch for Verrazzano CR %s: %v", vz.Name, err)
+		return newRequeueWithDelay(), err
+	}
+
+	// Watch the VerrazzanoExtensions so that their components are reconciled once they are registered
+	if err := r.watchExtensions(vz.Namespace, vz.Name, log); err != nil {
+		log.Errorf("Failed to set VerrazzanoExtension watch for Verrazzano CR %s: %v", vz.Name, err)
+		return newRequeueWithDelay(), err
+	}
+
+	// Watch the custom profile ConfigMaps so that the chain of profiles in the status is kept current
+	if err := r.watchProfiles(vz.Namespace, vz.Name, log); err != nil {
+		log.Errorf("Failed to set custom profile ConfigMap watch for Verrazzano CR %s: %v", vz.Name, err)
+		return newRequeueWithDelay(), err
+	}
+
 	// Update the map indicating the resource is being watched
 	initializedSet[vz.Name] = true
 	return ctrl.Result{Requeue: true}, nil
@@ -1334,3 +1454,11 @@ func (r *Reconciler) updateVerrazzanoStatus(log vzlog.VerrazzanoLogger, vz *inst
 	// Return error so that reconcile gets called again
 	return err
 }
+
+// formatStatusTime returns the time in the format of the times in the Verrazzano status
+func formatStatusTime(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("%d-%02d-%02dT%02d:%02d:%02dZ",
+		t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second())
+}
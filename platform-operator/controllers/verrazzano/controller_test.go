@@ -10,20 +10,19 @@ import (
 	"time"
 
 	"github.com/verrazzano/verrazzano/pkg/helm"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
 	constants2 "github.com/verrazzano/verrazzano/pkg/mcconstants"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/rbac"
-	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/uninstalljob"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/transform"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
-	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s"
 	"github.com/verrazzano/verrazzano/platform-operator/mocks"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
-	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -33,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 // For unit testing
@@ -152,6 +152,10 @@ func TestInstall(t *testing.T) {
 				mock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 			}
 
+			// Expect the calls of the preflight checks, and the status update with their results
+			expectPreflightChecks(mock)
+			expectPreflightStatusUpdate(mockStatus, asserts)
+
 			// Expect a call to update the status of the Verrazzano resource
 			mockStatus.EXPECT().
 				Update(gomock.Any(), gomock.Any()).
@@ -431,6 +435,9 @@ func TestCreateVerrazzanoWithOCIDNS(t *testing.T) {
 			return nil
 		})
 
+	// Expect the calls of the preflight checks
+	expectPreflightChecks(mock)
+
 	// Expect a call to get the DNS config secret and return it
 	mock.EXPECT().
 		Get(gomock.Any(), types.NamespacedName{Namespace: constants.VerrazzanoInstallNamespace, Name: "test-oci-config-secret"}, gomock.Not(gomock.Nil())).
@@ -455,6 +462,9 @@ func TestCreateVerrazzanoWithOCIDNS(t *testing.T) {
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
+	// Expect the status update with the results of the preflight checks
+	expectPreflightStatusUpdate(mockStatus, asserts)
+
 	// Expect a call to update the status of the Verrazzano resource
 	mockStatus.EXPECT().
 		Update(gomock.Any(), gomock.Any()).
@@ -480,7 +490,7 @@ func TestCreateVerrazzanoWithOCIDNS(t *testing.T) {
 
 // TestUninstallComplete tests the Reconcile method for the following use case
 // GIVEN a request to reconcile an Verrazzano resource
-// WHEN a Verrazzano resource has been deleted
+// WHEN a Verrazzano resource has been deleted and the component uninstall has completed
 // THEN ensure all the objects are deleted
 func TestUninstallComplete(t *testing.T) {
 	unitTesting = true
@@ -532,46 +542,14 @@ func TestUninstallComplete(t *testing.T) {
 	// Expect a call to get the ClusterRoleBinding
 	expectClusterRoleBindingExists(mock, verrazzanoToUse, namespace, name)
 
-	// Expect a call to get the uninstall Job - return that it exists
-	mock.EXPECT().
-		Get(gomock.Any(), types.NamespacedName{Namespace: getInstallNamespace(), Name: buildUninstallJobName(name)}, gomock.Not(gomock.Nil())).
-		DoAndReturn(func(ctx context.Context, name types.NamespacedName, job *batchv1.Job) error {
-			newJob := uninstalljob.NewJob(&uninstalljob.JobConfig{
-				JobConfigCommon: k8s.JobConfigCommon{
-					JobName:            name.Name,
-					Namespace:          name.Namespace,
-					Labels:             labels,
-					ServiceAccountName: buildServiceAccountName(name.Name),
-					JobImage:           "image",
-					DryRun:             false,
-				},
-			})
-			job.ObjectMeta = newJob.ObjectMeta
-			job.Spec = newJob.Spec
-			return nil
-		})
-
 	// Expect a call to update the finalizers - return success
 	mock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
-	// Expect a call to get the status writer and return a mock.
-	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
-
-	// Expect a call to update the status of the Verrazzano resource
-	mockStatus.EXPECT().
-		Update(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
-			asserts.Len(verrazzano.Status.Conditions, 2)
-			return nil
-		})
-
 	expectDeleteClusterRoleBinding(mock, getInstallNamespace(), name)
 	expectDeleteServiceAccount(mock, getInstallNamespace(), name)
+	expectDeleteVerrazzanoResources(mock)
 	expectDeleteNamespace(mock)
 
-	config.TestProfilesDir = "../../manifests/profiles"
-	defer func() { config.TestProfilesDir = "" }()
-
 	// Create and make the request
 	request := newRequest(namespace, name)
 	reconciler := newVerrazzanoReconciler(mock)
@@ -587,7 +565,7 @@ func TestUninstallComplete(t *testing.T) {
 // TestUninstallStarted tests the Reconcile method for the following use case
 // GIVEN a request to reconcile an Verrazzano resource
 // WHEN a Verrazzano resource has been deleted
-// THEN ensure an unisntall job is started
+// THEN ensure the uninstall started condition is added
 func TestUninstallStarted(t *testing.T) {
 	unitTesting = true
 	namespace := "verrazzano"
@@ -607,6 +585,9 @@ func TestUninstallStarted(t *testing.T) {
 	mockStatus := mocks.NewMockStatusWriter(mocker)
 	asserts.NotNil(mockStatus)
 
+	uninstallTrackerMap = make(map[string]*uninstallTracker)
+	defer func() { uninstallTrackerMap = make(map[string]*uninstallTracker) }()
+
 	// Expect a call to get the Verrazzano resource.  Return resource with deleted timestamp.
 	mock.EXPECT().
 		Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: name}, gomock.Not(gomock.Nil())).
@@ -622,11 +603,6 @@ func TestUninstallStarted(t *testing.T) {
 				Finalizers:        []string{finalizerName}}
 			verrazzano.Status = vzapi.VerrazzanoStatus{
 				State: vzapi.VzStateReady,
-				Conditions: []vzapi.Condition{
-					{
-						Type: vzapi.CondUninstallStarted,
-					},
-				},
 			}
 			return nil
 		})
@@ -637,27 +613,114 @@ func TestUninstallStarted(t *testing.T) {
 	// Expect a call to get the ClusterRoleBinding
 	expectClusterRoleBindingExists(mock, verrazzanoToUse, namespace, name)
 
-	// Expect a call to get the uninstall Job - return that it does not exist
-	mock.EXPECT().
-		Get(gomock.Any(), types.NamespacedName{Namespace: getInstallNamespace(), Name: buildUninstallJobName(name)}, gomock.Not(gomock.Nil())).
-		Return(errors.NewNotFound(schema.GroupResource{Group: namespace, Resource: "Job"}, buildUninstallJobName(name)))
+	// Expect a call to get the status writer and return a mock.
+	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
-	// Expect a call to create the uninstall Job - return success
+	// Expect a call to update the status of the Verrazzano resource with the uninstall started condition
+	mockStatus.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
+			asserts.Len(verrazzano.Status.Conditions, 1)
+			asserts.Equal(vzapi.CondUninstallStarted, verrazzano.Status.Conditions[0].Type)
+			asserts.Equal(vzapi.VzStateUninstalling, verrazzano.Status.State)
+			return nil
+		})
+
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	// Create and make the request
+	request := newRequest(namespace, name)
+	reconciler := newVerrazzanoReconciler(mock)
+	result, err := reconciler.Reconcile(nil, request)
+
+	// Validate the results
+	mocker.Finish()
+	asserts.NoError(err)
+	asserts.Equal(true, result.Requeue)
+	asserts.NotEqual(time.Duration(0), result.RequeueAfter)
+}
+
+// TestUninstallComponentInProgress tests the Reconcile method for the following use case
+// GIVEN a request to reconcile an Verrazzano resource that is being uninstalled
+// WHEN a component has not finished uninstalling
+// THEN ensure the component uninstall methods are called and the request is requeued
+func TestUninstallComponentInProgress(t *testing.T) {
+	unitTesting = true
+	namespace := "verrazzano"
+	name := "test"
+	labels := map[string]string{"label1": "test"}
+	var verrazzanoToUse vzapi.Verrazzano
+
+	deleteTime := metav1.Time{
+		Time: time.Now(),
+	}
+
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	mock := mocks.NewMockClient(mocker)
+	mockStatus := mocks.NewMockStatusWriter(mocker)
+	asserts.NotNil(mockStatus)
+
+	uninstallTrackerMap = make(map[string]*uninstallTracker)
+	defer func() { uninstallTrackerMap = make(map[string]*uninstallTracker) }()
+
+	mockComp := mocks.NewMockComponent(mocker)
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			mockComp,
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	// Set mock component expectations
+	mockComp.EXPECT().Name().Return("fake").AnyTimes()
+	mockComp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
+	mockComp.EXPECT().IsInstalled(gomock.Any()).Return(true, nil).Times(1)
+	mockComp.EXPECT().PreUninstall(gomock.Any()).Return(nil).Times(1)
+	mockComp.EXPECT().Uninstall(gomock.Any()).Return(nil).Times(1)
+	mockComp.EXPECT().IsUninstalled(gomock.Any()).Return(false, nil).Times(1)
+
+	// Expect a call to get the Verrazzano resource.  Return resource with deleted timestamp.
 	mock.EXPECT().
-		Create(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(ctx context.Context, job *batchv1.Job, opts ...client.CreateOption) error {
-			asserts.Equalf(getInstallNamespace(), job.Namespace, "Job namespace did not match")
-			asserts.Equalf(buildUninstallJobName(name), job.Name, "Job name did not match")
-			asserts.Equalf(labels, job.Labels, "Job labels did not match")
+		Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: name}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, name types.NamespacedName, verrazzano *vzapi.Verrazzano) error {
+			verrazzano.TypeMeta = metav1.TypeMeta{
+				APIVersion: "install.verrazzano.io/v1alpha1",
+				Kind:       "Verrazzano"}
+			verrazzano.ObjectMeta = metav1.ObjectMeta{
+				Namespace:         name.Namespace,
+				Name:              name.Name,
+				DeletionTimestamp: &deleteTime,
+				Finalizers:        []string{finalizerName}}
+			verrazzano.Status = vzapi.VerrazzanoStatus{
+				State: vzapi.VzStateUninstalling,
+				Conditions: []vzapi.Condition{
+					{
+						Type: vzapi.CondUninstallStarted,
+					},
+				},
+			}
 			return nil
 		})
 
-	// Expect a call to update the job - return success
-	mock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	// Expect a call to get the service account
+	expectGetServiceAccountExists(mock, name, labels)
+
+	// Expect a call to get the ClusterRoleBinding
+	expectClusterRoleBindingExists(mock, verrazzanoToUse, namespace, name)
 
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
+	// Expect a call to update the component status to uninstalling
+	mockStatus.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
+			asserts.Equal(vzapi.CompStateUninstalling, verrazzano.Status.Components["fake"].State)
+			return nil
+		})
+
 	config.TestProfilesDir = "../../manifests/profiles"
 	defer func() { config.TestProfilesDir = "" }()
 
@@ -674,9 +737,9 @@ func TestUninstallStarted(t *testing.T) {
 }
 
 // TestUninstallFailed tests the Reconcile method for the following use case
-// GIVEN an uninstall job has failed
+// GIVEN the uninstall has failed
 // WHEN a Verrazzano resource has been deleted
-// THEN ensure the error is handled
+// THEN ensure the error is handled and the finalizer is removed
 func TestUninstallFailed(t *testing.T) {
 	unitTesting = true
 	namespace := "verrazzano"
@@ -707,7 +770,13 @@ func TestUninstallFailed(t *testing.T) {
 				DeletionTimestamp: &deleteTime,
 				Finalizers:        []string{finalizerName}}
 			verrazzano.Status = vzapi.VerrazzanoStatus{
-				State: vzapi.VzStateReady}
+				State: vzapi.VzStateFailed,
+				Conditions: []vzapi.Condition{
+					{
+						Type: vzapi.CondUninstallFailed,
+					},
+				},
+			}
 			return nil
 		})
 
@@ -717,46 +786,12 @@ func TestUninstallFailed(t *testing.T) {
 	// Expect a call to get the ClusterRoleBinding
 	expectClusterRoleBindingExists(mock, verrazzanoToUse, namespace, name)
 
-	// Expect a call to get the uninstall Job - return that it exists and the job failed
-	mock.EXPECT().
-		Get(gomock.Any(), types.NamespacedName{Namespace: getInstallNamespace(), Name: buildUninstallJobName(name)}, gomock.Not(gomock.Nil())).
-		DoAndReturn(func(ctx context.Context, name types.NamespacedName, job *batchv1.Job) error {
-			newJob := uninstalljob.NewJob(&uninstalljob.JobConfig{
-				JobConfigCommon: k8s.JobConfigCommon{
-					JobName:            name.Name,
-					Namespace:          name.Namespace,
-					Labels:             labels,
-					ServiceAccountName: buildServiceAccountName(name.Name),
-					JobImage:           "image",
-					DryRun:             false,
-				},
-			})
-			job.ObjectMeta = newJob.ObjectMeta
-			job.Spec = newJob.Spec
-			job.Status = batchv1.JobStatus{
-				Failed: 1,
-			}
-			return nil
-		})
-
-	// Expect a status update on the job
-	mockStatus.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
-
 	// Expect a call to update the finalizers - return success
 	mock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
-	// Expect a call to get the status writer and return a mock.
-	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
-
-	// Expect a call to update the status of the Verrazzano resource
-	mockStatus.EXPECT().
-		Update(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
-			return nil
-		})
-
 	expectDeleteClusterRoleBinding(mock, getInstallNamespace(), name)
 	expectDeleteServiceAccount(mock, getInstallNamespace(), name)
+	expectDeleteVerrazzanoResources(mock)
 	expectDeleteNamespace(mock)
 
 	config.TestProfilesDir = "../../manifests/profiles"
@@ -775,9 +810,9 @@ func TestUninstallFailed(t *testing.T) {
 }
 
 // TestUninstallSucceeded tests the Reconcile method for the following use case
-// GIVEN an uninstall job has succeeded
-// WHEN a Verrazzano resource has been deleted
-// THEN ensure all the objects are deleted
+// GIVEN a request to reconcile an Verrazzano resource that is being uninstalled
+// WHEN all of the components uninstall successfully
+// THEN ensure the components are disabled and all the objects are deleted
 func TestUninstallSucceeded(t *testing.T) {
 	unitTesting = true
 	namespace := "verrazzano"
@@ -795,6 +830,36 @@ func TestUninstallSucceeded(t *testing.T) {
 	mockStatus := mocks.NewMockStatusWriter(mocker)
 	asserts.NotNil(mockStatus)
 
+	uninstallTrackerMap = make(map[string]*uninstallTracker)
+	defer func() { uninstallTrackerMap = make(map[string]*uninstallTracker) }()
+
+	// The dependent component must be uninstalled before the component it depends on
+	var uninstalled []string
+	newMockComp := func(compName string, dependencies []string, installed bool) *mocks.MockComponent {
+		mockComp := mocks.NewMockComponent(mocker)
+		mockComp.EXPECT().Name().Return(compName).AnyTimes()
+		mockComp.EXPECT().GetDependencies().Return(dependencies).AnyTimes()
+		mockComp.EXPECT().IsInstalled(gomock.Any()).Return(installed, nil).Times(1)
+		if installed {
+			mockComp.EXPECT().PreUninstall(gomock.Any()).Return(nil).Times(1)
+			mockComp.EXPECT().Uninstall(gomock.Any()).DoAndReturn(func(ctx spi.ComponentContext) error {
+				uninstalled = append(uninstalled, compName)
+				return nil
+			}).Times(1)
+			mockComp.EXPECT().IsUninstalled(gomock.Any()).Return(true, nil).Times(1)
+			mockComp.EXPECT().PostUninstall(gomock.Any()).Return(nil).Times(1)
+		}
+		return mockComp
+	}
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			newMockComp("fake1", []string{}, true),
+			newMockComp("fake2", []string{"fake1"}, true),
+			newMockComp("fake3", []string{}, false),
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
 	// Expect a call to get the Verrazzano resource.  Return resource with deleted timestamp.
 	mock.EXPECT().
 		Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: name}, gomock.Not(gomock.Nil())).
@@ -808,7 +873,13 @@ func TestUninstallSucceeded(t *testing.T) {
 				DeletionTimestamp: &deleteTime,
 				Finalizers:        []string{finalizerName}}
 			verrazzano.Status = vzapi.VerrazzanoStatus{
-				State: vzapi.VzStateReady}
+				State: vzapi.VzStateUninstalling,
+				Conditions: []vzapi.Condition{
+					{
+						Type: vzapi.CondUninstallStarted,
+					},
+				},
+			}
 			return nil
 		})
 
@@ -818,46 +889,24 @@ func TestUninstallSucceeded(t *testing.T) {
 	// Expect a call to get the ClusterRoleBinding
 	expectClusterRoleBindingExists(mock, verrazzanoToUse, namespace, name)
 
-	// Expect a call to get the uninstall Job - return that it exists and the job succeeded
-	mock.EXPECT().
-		Get(gomock.Any(), types.NamespacedName{Namespace: getInstallNamespace(), Name: buildUninstallJobName(name)}, gomock.Not(gomock.Nil())).
-		DoAndReturn(func(ctx context.Context, name types.NamespacedName, job *batchv1.Job) error {
-			newJob := uninstalljob.NewJob(&uninstalljob.JobConfig{
-				JobConfigCommon: k8s.JobConfigCommon{
-					JobName:            name.Name,
-					Namespace:          name.Namespace,
-					Labels:             labels,
-					ServiceAccountName: buildServiceAccountName(name.Name),
-					JobImage:           "image",
-					DryRun:             false,
-				},
-			})
-			job.ObjectMeta = newJob.ObjectMeta
-			job.Spec = newJob.Spec
-			job.Status = batchv1.JobStatus{
-				Succeeded: 1,
-			}
-			return nil
-		})
-
-	// Expect a status update on the job
-	mockStatus.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
-
 	// Expect a call to update the finalizers - return success
 	mock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
-	// Expect a call to update the status of the Verrazzano resource
+	// Expect calls to update the status of the Verrazzano resource
+	var lastStatus vzapi.VerrazzanoStatus
 	mockStatus.EXPECT().
 		Update(gomock.Any(), gomock.Any()).
 		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
+			lastStatus = verrazzano.Status
 			return nil
-		})
+		}).AnyTimes()
 
 	expectDeleteClusterRoleBinding(mock, getInstallNamespace(), name)
 	expectDeleteServiceAccount(mock, getInstallNamespace(), name)
+	expectDeleteVerrazzanoResources(mock)
 	expectDeleteNamespace(mock)
 
 	config.TestProfilesDir = "../../manifests/profiles"
@@ -873,6 +922,11 @@ func TestUninstallSucceeded(t *testing.T) {
 	asserts.NoError(err)
 	asserts.Equal(false, result.Requeue)
 	asserts.Equal(time.Duration(0), result.RequeueAfter)
+	asserts.Equal([]string{"fake2", "fake1"}, uninstalled)
+	asserts.Equal(vzapi.CompStateDisabled, lastStatus.Components["fake1"].State)
+	asserts.Equal(vzapi.CompStateDisabled, lastStatus.Components["fake2"].State)
+	asserts.NotContains(lastStatus.Components, "fake3")
+	asserts.True(isUninstallDone(lastStatus))
 }
 
 // TestVerrazzanoNotFound tests the Reconcile method for the following use case
@@ -1178,6 +1232,11 @@ func TestVZSystemNamespaceGetError(t *testing.T) {
 	// Expect a call to get the ClusterRoleBinding
 	expectClusterRoleBindingExists(mock, verrazzanoToUse, namespace, name)
 
+	// Expect the calls of the preflight checks, and the status update with their results
+	expectPreflightChecks(mock)
+	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
+	expectPreflightStatusUpdate(mockStatus, asserts)
+
 	errMsg := "get vz system namespace error"
 	// Expect a call to get the Verrazzano system namespace - return a failure error
 	mock.EXPECT().
@@ -1236,6 +1295,11 @@ func TestVZSystemNamespaceCreateError(t *testing.T) {
 	// Expect a call to get the ClusterRoleBinding
 	expectClusterRoleBindingExists(mock, verrazzanoToUse, namespace, name)
 
+	// Expect the calls of the preflight checks, and the status update with their results
+	expectPreflightChecks(mock)
+	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
+	expectPreflightStatusUpdate(mockStatus, asserts)
+
 	errMsg := "create vz system namespace error"
 	// Expect a call to get the Verrazzano system namespace - return an IsNotFound
 	mock.EXPECT().
@@ -1307,6 +1371,9 @@ func TestGetOCIConfigSecretError(t *testing.T) {
 	// Expect a call to get the ClusterRoleBinding
 	expectClusterRoleBindingExists(mock, verrazzanoToUse, namespace, name)
 
+	// Expect the calls of the preflight checks
+	expectPreflightChecks(mock)
+
 	// Expect a call to get the DNS config secret but return a not found error
 	mock.EXPECT().
 		Get(gomock.Any(), types.NamespacedName{Namespace: constants.VerrazzanoInstallNamespace, Name: "test-oci-config-secret"}, gomock.Not(gomock.Nil())).
@@ -1327,6 +1394,97 @@ func TestGetOCIConfigSecretError(t *testing.T) {
 	asserts.NotZero(result.RequeueAfter)
 }
 
+// TestOCIConfigSecretNotFound tests the Reconcile method for the following use case
+// GIVEN a request to reconcile an Verrazzano resource
+// WHEN the OCI DNS secret does not exist
+// THEN the failed preflight check is stored in the status and the install is not started
+func TestOCIConfigSecretNotFound(t *testing.T) {
+	unitTesting = true
+	namespace := "verrazzano"
+	name := "test"
+	labels := map[string]string{"label1": "test"}
+	var verrazzanoToUse vzapi.Verrazzano
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	mock := mocks.NewMockClient(mocker)
+	mockStatus := mocks.NewMockStatusWriter(mocker)
+
+	verrazzanoToUse.TypeMeta = metav1.TypeMeta{
+		APIVersion: "install.verrazzano.io/v1alpha1",
+		Kind:       "Verrazzano"}
+	verrazzanoToUse.ObjectMeta = metav1.ObjectMeta{
+		Namespace:  namespace,
+		Name:       name,
+		Labels:     labels,
+		Finalizers: []string{finalizerName}}
+	verrazzanoToUse.Spec.Components.DNS = &vzapi.DNSComponent{
+		OCI: &vzapi.OCI{
+			OCIConfigSecret:        "test-oci-config-secret",
+			DNSZoneCompartmentOCID: "test-dns-zone-ocid",
+			DNSZoneOCID:            "test-dns-zone-ocid",
+			DNSZoneName:            "test-dns-zone-name",
+		},
+	}
+	verrazzanoToUse.Status = vzapi.VerrazzanoStatus{
+		State: vzapi.VzStateReady}
+	verrazzanoToUse.Status.Components = makeVerrazzanoComponentStatusMap()
+
+	// Expect a call to get the Verrazzano resource.
+	expectGetVerrazzanoExists(mock, verrazzanoToUse, namespace, name, labels)
+
+	// Expect a call to get the service account
+	expectGetServiceAccountExists(mock, name, labels)
+
+	// Expect a call to get the ClusterRoleBinding
+	expectClusterRoleBindingExists(mock, verrazzanoToUse, namespace, name)
+
+	// Expect the calls of the preflight checks
+	expectPreflightChecks(mock)
+
+	// Expect a call to get the DNS config secret and return that it does not exist
+	mock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: constants.VerrazzanoInstallNamespace, Name: "test-oci-config-secret"}, gomock.Not(gomock.Nil())).
+		Return(errors.NewNotFound(schema.GroupResource{Resource: "Secret"}, "test-oci-config-secret"))
+
+	// Expect the status update with the failed preflight check, the state is not changed to Installing and the
+	// Verrazzano system namespace is not created
+	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
+	mockStatus.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
+			asserts.Equal(vzapi.VzStateReady, verrazzano.Status.State)
+			asserts.NotNil(verrazzano.Status.Preflight)
+			asserts.False(verrazzano.Status.Preflight.Passed)
+			asserts.Equal(constants.InstallOperation, verrazzano.Status.Preflight.Operation)
+			return nil
+		}).Times(1)
+
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	// Create and make the request
+	request := newRequest(namespace, name)
+	reconciler := newVerrazzanoReconciler(mock)
+	result, err := reconciler.Reconcile(nil, request)
+
+	// Validate the results
+	mocker.Finish()
+	asserts.NoError(err)
+	asserts.Equal(true, result.Requeue)
+	asserts.NotZero(result.RequeueAfter)
+}
+
+// expectPreflightStatusUpdate expects the status update that stores the results of the preflight checks, which passed
+func expectPreflightStatusUpdate(mockStatus *mocks.MockStatusWriter, asserts *assert.Assertions) {
+	mockStatus.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
+			asserts.NotNil(verrazzano.Status.Preflight)
+			asserts.True(verrazzano.Status.Preflight.Passed)
+			return nil
+		})
+}
+
 // newScheme creates a new scheme that includes this package's object to use for testing
 func newScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
@@ -1429,6 +1587,14 @@ func expectDeleteNamespace(mock *mocks.MockClient) {
 	mock.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 }
 
+// expectDeleteVerrazzanoResources expects the calls to list the Verrazzano CRDs, RBAC resources and namespaces left
+// once the components are uninstalled, and returns that there are none
+func expectDeleteVerrazzanoResources(mock *mocks.MockClient) {
+	mock.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mock.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: constants.VerrazzanoMultiClusterNamespace}, gomock.Not(gomock.Nil())).
+		Return(errors.NewNotFound(schema.GroupResource{Resource: "Namespace"}, constants.VerrazzanoMultiClusterNamespace))
+}
+
 // expectDeleteClusterRoleBinding expects a call to delete the ClusterRoleBinding for the Verrazzano with the given
 // namespace and name, and returns that it exists
 func expectDeleteClusterRoleBinding(mock *mocks.MockClient, namespace string, name string) {
@@ -1821,3 +1987,85 @@ func equalStringSet(x, y []string) bool {
 	}
 	return true
 }
+
+// TestRefreshProfileChain tests the refreshProfileChain function
+// GIVEN an installed Verrazzano resource with a stale chain of profiles in its status
+// WHEN refreshProfileChain is called
+// THEN the chain of profiles is only refreshed when the generation of the resource has changed
+func TestRefreshProfileChain(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-profile-chain", Generation: 2},
+		Spec:       vzapi.VerrazzanoSpec{Profile: vzapi.Dev},
+		Status:     vzapi.VerrazzanoStatus{ProfileChain: []string{"base", "prod"}},
+	}
+	defer deleteProfileChainGeneration(vz)
+	c := fake.NewClientBuilder().WithScheme(newScheme()).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	nsn := types.NamespacedName{Namespace: "verrazzano", Name: "test-profile-chain"}
+
+	asserts.NoError(reconciler.refreshProfileChain(vzlog.DefaultLogger(), vz))
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	asserts.Equal([]string{"base", "dev"}, vz.Status.ProfileChain)
+
+	// The chain is not refreshed again for the same generation
+	vz.Status.ProfileChain = []string{"base", "prod"}
+	asserts.NoError(reconciler.refreshProfileChain(vzlog.DefaultLogger(), vz))
+	asserts.Equal([]string{"base", "prod"}, vz.Status.ProfileChain)
+
+	// The chain is refreshed once the generation changes
+	vz.Generation = 3
+	asserts.NoError(reconciler.refreshProfileChain(vzlog.DefaultLogger(), vz))
+	asserts.Equal([]string{"base", "dev"}, vz.Status.ProfileChain)
+}
+
+// TestRefreshProfileChainCustomProfile tests the refreshProfileChain function
+// GIVEN an installed Verrazzano resource with a custom profile
+// WHEN the custom profile ConfigMap is changed to be layered on another profile
+// THEN the change is detected by the profile watch predicate and the chain of profiles is refreshed for the same
+// generation of the resource
+func TestRefreshProfileChainCustomProfile(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	oldProfile := newCustomProfileConfigMap("staging", "spec:\n  profile: dev\n")
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-custom-profile-chain", Generation: 1},
+		Spec:       vzapi.VerrazzanoSpec{Profile: "staging"},
+	}
+	defer deleteProfileChainGeneration(vz)
+	scheme := newScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vz, oldProfile).Build()
+	transform.SetProfileReader(c)
+	defer transform.SetProfileReader(nil)
+	reconciler := newVerrazzanoReconciler(c)
+
+	asserts.NoError(reconciler.refreshProfileChain(vzlog.DefaultLogger(), vz))
+	asserts.Equal([]string{"base", "dev", "staging"}, vz.Status.ProfileChain)
+
+	// The custom profile is layered on the prod profile instead
+	newProfile := oldProfile.DeepCopy()
+	newProfile.Data[constants.VerrazzanoProfileKey] = "spec:\n  profile: prod\n"
+	asserts.NoError(c.Update(context.TODO(), newProfile))
+	asserts.True(isProfileConfigMap(newProfile))
+	asserts.True(isProfileConfigMapChanged(oldProfile, newProfile))
+
+	// The chain is refreshed once the watch has seen the change
+	asserts.NoError(reconciler.refreshProfileChain(vzlog.DefaultLogger(), vz))
+	asserts.Equal([]string{"base", "dev", "staging"}, vz.Status.ProfileChain)
+	deleteProfileChainGeneration(vz)
+	asserts.NoError(reconciler.refreshProfileChain(vzlog.DefaultLogger(), vz))
+	asserts.Equal([]string{"base", "prod", "staging"}, vz.Status.ProfileChain)
+
+	// Removing the profile label is a change, an unrelated ConfigMap is not
+	unlabelled := newProfile.DeepCopy()
+	unlabelled.Labels = nil
+	asserts.True(isProfileConfigMapChanged(newProfile, unlabelled))
+	asserts.False(isProfileConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoInstallNamespace, Name: "other"}}))
+	asserts.False(isProfileConfigMapChanged(newProfile, newProfile.DeepCopy()))
+}
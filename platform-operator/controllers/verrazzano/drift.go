@@ -0,0 +1,130 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// driftCheckInterval is the minimum time between the checks for changes made to the components outside of Verrazzano
+const driftCheckInterval = 5 * time.Minute
+
+// lastDriftCheckMap has the time of the last drift check of each Verrazzano resource, keyed by the resource
+// namespace and name
+var lastDriftCheckMap = map[string]time.Time{}
+
+// isRemediateDrift returns true if the components that have drifted are re-applied
+func isRemediateDrift(cr *vzapi.Verrazzano) bool {
+	return cr.Spec.DriftPolicy == vzapi.DriftPolicyRemediate
+}
+
+// checkComponentDrift periodically checks whether the components that are Ready have been changed outside of
+// Verrazzano.  The Drifted condition is set on a component that has drifted, and removed once the component matches
+// its configuration again.  When the drift policy is Remediate the component is installed again with its
+// configuration.
+func (r *Reconciler) checkComponentDrift(vzctx vzcontext.VerrazzanoContext) error {
+	cr := vzctx.ActualCR
+	key := getNSNKey(cr)
+	if lastCheck, ok := lastDriftCheckMap[key]; ok && time.Since(lastCheck) < driftCheckInterval {
+		return nil
+	}
+	spiCtx, err := spi.NewContext(vzctx.Log, r.Client, cr, r.DryRun)
+	if err != nil {
+		vzctx.Log.Errorf("Failed to create component context: %v", err)
+		return err
+	}
+
+	statusChanged := false
+	for _, comp := range registry.GetComponents() {
+		compName := comp.Name()
+		detector, ok := comp.(spi.ComponentDriftDetector)
+		if !ok || !comp.IsOperatorInstallSupported() || !comp.IsEnabled(spiCtx.EffectiveCR()) {
+			continue
+		}
+		compStatus, ok := cr.Status.Components[compName]
+		if !ok || compStatus.State != vzapi.CompStateReady {
+			continue
+		}
+		compContext := spiCtx.Init(compName).Operation(vzconst.InstallOperation)
+		drift, err := detector.GetDrift(compContext)
+		if err != nil {
+			// Try again at the next check, the drift check doesn't block the reconcile
+			compContext.Log().Errorf("Failed checking component %s for drift: %v", compName, err)
+			continue
+		}
+		if len(drift) == 0 {
+			if hasCondition(compStatus.Conditions, vzapi.CondDrifted) {
+				compContext.Log().Infof("Component %s matches the Verrazzano configuration", compName)
+				compStatus.Conditions = removeCondition(compStatus.Conditions, vzapi.CondDrifted)
+				statusChanged = true
+			}
+			continue
+		}
+
+		msg := fmt.Sprintf("Component has been changed outside of Verrazzano: %s", strings.Join(drift, "; "))
+		if isRemediateDrift(cr) {
+			compContext.Log().Infof("Component %s has drifted and will be installed again: %s", compName, strings.Join(drift, "; "))
+			if err := metrics.TimeOperation(compName, metrics.RemediateOperation, func() error { return comp.Install(compContext) }); err != nil {
+				r.recordOperationError(cr, compName, metrics.RemediateOperation, err)
+				msg = fmt.Sprintf("%s, failed installing the component again: %v", msg, err)
+			} else {
+				msg = fmt.Sprintf("%s, the component has been installed again", msg)
+			}
+		} else {
+			compContext.Log().Progressf("Component %s has drifted: %s", compName, strings.Join(drift, "; "))
+		}
+		if setDriftedCondition(compStatus, msg) {
+			r.recordConditionEvent(cr, compName, vzapi.CondDrifted, msg)
+			statusChanged = true
+		}
+	}
+
+	if statusChanged {
+		if err := r.updateVerrazzanoStatus(vzctx.Log, cr); err != nil {
+			return err
+		}
+	}
+	lastDriftCheckMap[key] = time.Now()
+	return nil
+}
+
+// setDriftedCondition adds the Drifted condition to the component, or updates the message of the condition.  The
+// state of the component isn't changed.  Returns true if the condition changed.
+func setDriftedCondition(compStatus *vzapi.ComponentStatusDetails, message string) bool {
+	if hasCondition(compStatus.Conditions, vzapi.CondDrifted) {
+		return updateConditionMessage(compStatus.Conditions, vzapi.CondDrifted, message)
+	}
+	compStatus.Conditions = append(compStatus.Conditions, vzapi.Condition{
+		Type:               vzapi.CondDrifted,
+		Status:             corev1.ConditionTrue,
+		Message:            message,
+		LastTransitionTime: formatStatusTime(time.Now()),
+	})
+	return true
+}
+
+// hasCondition returns true if there is a condition of the given type
+func hasCondition(conditions []vzapi.Condition, conditionType vzapi.ConditionType) bool {
+	for _, condition := range conditions {
+		if condition.Type == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteDriftCheck forgets the time of the last drift check of the Verrazzano resource
+func deleteDriftCheck(cr *vzapi.Verrazzano) {
+	delete(lastDriftCheckMap, getNSNKey(cr))
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeDriftComponent is a component that reports the drift it is given, and clears the drift when it is installed
+type fakeDriftComponent struct {
+	fakeComponent
+	drift     *[]string
+	installed *int
+}
+
+func (f fakeDriftComponent) GetDrift(_ spi.ComponentContext) ([]string, error) {
+	return *f.drift, nil
+}
+
+func (f fakeDriftComponent) Install(_ spi.ComponentContext) error {
+	*f.installed++
+	*f.drift = nil
+	return nil
+}
+
+// TestCheckComponentDrift tests the checkComponentDrift method for the following use case
+// GIVEN a Ready component that has been changed outside of Verrazzano
+// WHEN the drift policy is Report or Remediate
+// THEN the Drifted condition is set on the component, and the component is installed again for Remediate
+func TestCheckComponentDrift(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	var drift []string
+	installed := 0
+	components := []spi.Component{
+		fakeDriftComponent{
+			fakeComponent: fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "a", SupportsOperatorInstall: true}},
+			drift:         &drift,
+			installed:     &installed,
+		},
+	}
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return components
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-drift"},
+		Status: vzapi.VerrazzanoStatus{
+			State: vzapi.VzStateReady,
+			Components: vzapi.ComponentStatusMap{
+				"a": {Name: "a", State: vzapi.CompStateReady, Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}}},
+			},
+		},
+	}
+	defer deleteDriftCheck(vz)
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	nsn := types.NamespacedName{Namespace: "verrazzano", Name: "test-drift"}
+	checkDrift := func() *vzapi.ComponentStatusDetails {
+		deleteDriftCheck(vz)
+		asserts.NoError(reconciler.checkComponentDrift(vzcontext.VerrazzanoContext{Log: vzlog.DefaultLogger(), Client: c, ActualCR: vz}))
+		asserts.NoError(c.Get(context.TODO(), nsn, vz))
+		return vz.Status.Components["a"]
+	}
+
+	// The drift is reported
+	drift = []string{"values image.tag have been changed"}
+	compStatus := checkDrift()
+	asserts.Equal(vzapi.CompStateReady, compStatus.State)
+	asserts.True(hasCondition(compStatus.Conditions, vzapi.CondDrifted))
+	asserts.Equal("Component has been changed outside of Verrazzano: values image.tag have been changed",
+		compStatus.Conditions[len(compStatus.Conditions)-1].Message)
+	asserts.Equal(0, installed)
+
+	// The drift is not checked again until the interval has passed
+	drift = nil
+	asserts.NoError(reconciler.checkComponentDrift(vzcontext.VerrazzanoContext{Log: vzlog.DefaultLogger(), Client: c, ActualCR: vz}))
+	asserts.True(hasCondition(vz.Status.Components["a"].Conditions, vzapi.CondDrifted))
+
+	// The condition is removed once the component matches its configuration
+	compStatus = checkDrift()
+	asserts.False(hasCondition(compStatus.Conditions, vzapi.CondDrifted))
+
+	// The component is installed again for the Remediate policy
+	vz.Spec.DriftPolicy = vzapi.DriftPolicyRemediate
+	drift = []string{"chart a-0.9.0 is installed instead of a-1.0.0"}
+	compStatus = checkDrift()
+	asserts.Equal(1, installed)
+	asserts.Contains(compStatus.Conditions[len(compStatus.Conditions)-1].Message, "the component has been installed again")
+
+	compStatus = checkDrift()
+	asserts.Equal(1, installed)
+	asserts.False(hasCondition(compStatus.Conditions, vzapi.CondDrifted))
+}
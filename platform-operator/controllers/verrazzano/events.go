@@ -0,0 +1,49 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"fmt"
+
+	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// recordConditionEvent records an Event on the Verrazzano resource for a condition transition.  Failed, rolled back,
+// degraded, drifted and certificates expiring soon conditions are recorded as Warning Events.  The component name is
+// empty for conditions of the Verrazzano resource.
+func (r *Reconciler) recordConditionEvent(cr *installv1alpha1.Verrazzano, componentName string, conditionType installv1alpha1.ConditionType, message string) {
+	if r.EventRecorder == nil {
+		return
+	}
+	eventType := corev1.EventTypeNormal
+	switch conditionType {
+	case installv1alpha1.CondInstallFailed, installv1alpha1.CondUpgradeFailed, installv1alpha1.CondUninstallFailed,
+		installv1alpha1.CondUpgradeRolledBack, installv1alpha1.CondDegraded, installv1alpha1.CondDrifted,
+		installv1alpha1.CondCertificatesExpiringSoon:
+		eventType = corev1.EventTypeWarning
+	}
+	if len(componentName) > 0 {
+		message = fmt.Sprintf("Component %s: %s", componentName, message)
+	}
+	r.EventRecorder.Event(cr, eventType, string(conditionType), message)
+}
+
+// recordOperationError records a Warning Event on the Verrazzano resource for a component operation that failed, and
+// keeps the error as the last error of the component in the health rollup.  Retryable errors, such as waiting for a
+// resource to be ready, are not recorded.
+func (r *Reconciler) recordOperationError(cr *installv1alpha1.Verrazzano, componentName string, operation string, err error) {
+	if err == nil {
+		return
+	}
+	if _, ok := err.(ctrlerrors.RetryableError); ok {
+		return
+	}
+	setComponentError(cr, componentName, fmt.Sprintf("%s failed: %v", operation, err))
+	if r.EventRecorder == nil {
+		return
+	}
+	r.EventRecorder.Eventf(cr, corev1.EventTypeWarning, operation+"Failed", "Component %s: %s failed: %v", componentName, operation, err)
+}
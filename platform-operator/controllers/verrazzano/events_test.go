@@ -0,0 +1,82 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newEventTestReconciler returns a reconciler with a fake event recorder for the Verrazzano resource
+func newEventTestReconciler(vz *vzapi.Verrazzano) (Reconciler, *record.FakeRecorder) {
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := newVerrazzanoReconciler(c)
+	reconciler.EventRecorder = recorder
+	return reconciler, recorder
+}
+
+// TestUpdateComponentStatusEvents tests the updateComponentStatus func
+// GIVEN a component that changes state
+// WHEN updateComponentStatus is called
+// THEN ensure an Event is recorded for each transition, and not when the component stays in the same state
+func TestUpdateComponentStatusEvents(t *testing.T) {
+	asserts := assert.New(t)
+	vz := &vzapi.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test"}}
+	reconciler, recorder := newEventTestReconciler(vz)
+	compContext := spi.NewFakeContext(reconciler.Client, vz, false).Init("fake")
+
+	asserts.NoError(reconciler.updateComponentStatus(compContext, "PreInstall started", vzapi.CondPreInstall))
+	asserts.NoError(reconciler.updateComponentStatus(compContext, "PreInstall started", vzapi.CondPreInstall))
+	asserts.NoError(reconciler.updateComponentStatus(compContext, "Install started", vzapi.CondInstallStarted))
+	asserts.NoError(reconciler.updateComponentStatus(compContext, "Install complete", vzapi.CondInstallComplete))
+	asserts.NoError(reconciler.updateComponentStatus(compContext, "Component is Ready", vzapi.CondInstallComplete))
+
+	asserts.Len(recorder.Events, 3)
+	asserts.Equal("Normal PreInstall Component fake: PreInstall started", <-recorder.Events)
+	asserts.Equal("Normal InstallStarted Component fake: Install started", <-recorder.Events)
+	asserts.Equal("Normal InstallComplete Component fake: Install complete", <-recorder.Events)
+}
+
+// TestUpdateStatusEvents tests the updateStatus func
+// GIVEN a Verrazzano resource whose upgrade failed
+// WHEN updateStatus is called
+// THEN ensure a Warning Event is recorded with the message
+func TestUpdateStatusEvents(t *testing.T) {
+	asserts := assert.New(t)
+	vz := &vzapi.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test"}}
+	reconciler, recorder := newEventTestReconciler(vz)
+
+	asserts.NoError(reconciler.updateStatus(vzlog.DefaultLogger(), vz, "Verrazzano upgrade failed", vzapi.CondUpgradeFailed))
+	asserts.Len(recorder.Events, 1)
+	asserts.Equal("Warning UpgradeFailed Verrazzano upgrade failed", <-recorder.Events)
+}
+
+// TestRecordOperationError tests the recordOperationError func
+// GIVEN component operations that failed
+// WHEN recordOperationError is called
+// THEN ensure a Warning Event with the component name and error is recorded, except for retryable errors
+func TestRecordOperationError(t *testing.T) {
+	asserts := assert.New(t)
+	vz := &vzapi.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test"}}
+	reconciler, recorder := newEventTestReconciler(vz)
+
+	reconciler.recordOperationError(vz, "fake", "Install", errors.New("helm install failed"))
+	reconciler.recordOperationError(vz, "fake", "PostInstall", ctrlerrors.RetryableError{Source: "fake"})
+	reconciler.recordOperationError(vz, "fake", "PostInstall", nil)
+
+	asserts.Len(recorder.Events, 1)
+	asserts.Equal("Warning InstallFailed Component fake: Install failed: helm install failed", <-recorder.Events)
+}
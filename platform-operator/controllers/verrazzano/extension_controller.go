@@ -0,0 +1,328 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzstring "github.com/verrazzano/verrazzano/pkg/string"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/extension"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// extensionFinalizerName is the finalizer that keeps a VerrazzanoExtension until its component is uninstalled
+const extensionFinalizerName = "extension.install.verrazzano.io"
+
+// VerrazzanoExtensionReconciler registers the components of the VerrazzanoExtension resources.  The components are
+// installed, upgraded and uninstalled by the Verrazzano controller like the built-in components.
+type VerrazzanoExtensionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// SetupWithManager creates a new controller and adds it to the manager
+func (r *VerrazzanoExtensionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vzapi.VerrazzanoExtension{}).
+		Complete(r)
+}
+
+// Reconcile registers the components of the valid VerrazzanoExtension resources, and reports the reason an
+// extension cannot be installed in its status.  A change to an extension that is installed causes the component to
+// be installed again, a deleted extension is kept until its component is uninstalled.
+func (r *VerrazzanoExtensionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	extList, err := r.syncExtensionComponents(ctx)
+	if err != nil {
+		zap.S().Errorf("Failed to register the VerrazzanoExtension components: %v", err)
+		return newRequeueWithDelay(), nil
+	}
+	var ext *vzapi.VerrazzanoExtension
+	for i := range extList.Items {
+		if extList.Items[i].Namespace == req.Namespace && extList.Items[i].Name == req.Name {
+			ext = &extList.Items[i]
+		}
+	}
+	if ext == nil {
+		return ctrl.Result{}, nil
+	}
+
+	// Get the resource logger needed to log message using 'progress' and 'once' methods
+	log, err := vzlog.EnsureResourceLogger(&vzlog.ResourceConfig{
+		Name:           ext.Name,
+		Namespace:      ext.Namespace,
+		ID:             string(ext.UID),
+		Generation:     ext.Generation,
+		ControllerName: "verrazzanoextension",
+	})
+	if err != nil {
+		zap.S().Errorf("Failed to create resource logger for VerrazzanoExtension controller: %v", err)
+		return newRequeueWithDelay(), nil
+	}
+
+	vz, err := r.getVerrazzano(ctx, ext.Namespace)
+	if err != nil {
+		log.Errorf("Failed to fetch the Verrazzano resource in namespace %s: %v", ext.Namespace, err)
+		return newRequeueWithDelay(), nil
+	}
+	if !ext.DeletionTimestamp.IsZero() {
+		return r.procDeleteExtension(ctx, log, ext, vz)
+	}
+
+	message := validateExtensions(extList.Items)[getExtensionKey(ext)]
+	if len(message) == 0 && !vzstring.SliceContainsString(ext.Finalizers, extensionFinalizerName) {
+		ext.Finalizers = append(ext.Finalizers, extensionFinalizerName)
+		if err := r.Update(ctx, ext); err != nil {
+			log.Errorf("Failed to add the finalizer to VerrazzanoExtension %s/%s: %v", ext.Namespace, ext.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+	}
+	if len(message) == 0 {
+		if err := extension.EnsureChart(log, ext); err != nil {
+			message = fmt.Sprintf("Failed to pull the chart: %v", err)
+		}
+	}
+	if len(message) == 0 && ext.Status.ObservedGeneration != 0 && ext.Status.ObservedGeneration != ext.Generation &&
+		isExtensionInstalled(vz, ext.Name) {
+		log.Infof("VerrazzanoExtension %s/%s has changed, the component will be installed again", ext.Namespace, ext.Name)
+		markOverridesChanged(vz, ext.Name)
+	}
+	return r.updateExtensionStatus(ctx, log, ext, message)
+}
+
+// syncExtensionComponents registers the components of the VerrazzanoExtension resources that are valid.  Extensions
+// that are being deleted stay registered until their finalizer is removed, so that they are uninstalled.
+func (r *VerrazzanoExtensionReconciler) syncExtensionComponents(ctx context.Context) (*vzapi.VerrazzanoExtensionList, error) {
+	extList := &vzapi.VerrazzanoExtensionList{}
+	if err := r.List(ctx, extList); err != nil {
+		return nil, err
+	}
+	messages := validateExtensions(extList.Items)
+	var comps []spi.Component
+	for i := range extList.Items {
+		ext := &extList.Items[i]
+		if !ext.DeletionTimestamp.IsZero() && !vzstring.SliceContainsString(ext.Finalizers, extensionFinalizerName) {
+			continue
+		}
+		if _, ok := messages[getExtensionKey(ext)]; ok {
+			continue
+		}
+		comps = append(comps, extension.NewComponent(ext))
+	}
+	registry.SetExtensionComponents(comps)
+	return extList, nil
+}
+
+// validateExtensions returns the reason each extension that cannot be installed is invalid, keyed by the namespace
+// and name of the extension.  An extension that depends on an invalid extension is also invalid.
+func validateExtensions(exts []vzapi.VerrazzanoExtension) map[string]string {
+	messages := map[string]string{}
+	for i := range exts {
+		if message := validateExtension(&exts[i], exts); len(message) > 0 {
+			messages[getExtensionKey(&exts[i])] = message
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := range exts {
+			ext := &exts[i]
+			if _, ok := messages[getExtensionKey(ext)]; ok {
+				continue
+			}
+			for _, dependency := range ext.Spec.Dependencies {
+				if _, ok := messages[ext.Namespace+"/"+dependency]; ok && !registry.IsBuiltInComponent(dependency) {
+					messages[getExtensionKey(ext)] = fmt.Sprintf("The dependency %s cannot be installed", dependency)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return messages
+}
+
+// validateExtension returns the reason the extension cannot be installed, or an empty string if it is valid
+func validateExtension(ext *vzapi.VerrazzanoExtension, exts []vzapi.VerrazzanoExtension) string {
+	chart := ext.Spec.Chart
+	if (len(chart.Path) == 0) == (len(chart.OCIReference) == 0) {
+		return "Exactly one of chart.path and chart.ociReference must be specified"
+	}
+	if len(chart.OCIReference) > 0 && len(chart.Version) == 0 {
+		return "chart.version must be specified with chart.ociReference"
+	}
+	if len(ext.Spec.Namespace) == 0 {
+		return "namespace must be specified"
+	}
+	if registry.IsBuiltInComponent(ext.Name) {
+		return fmt.Sprintf("The name %s is the name of a Verrazzano component", ext.Name)
+	}
+	for i := range exts {
+		if isNameTakenBy(ext, &exts[i]) {
+			return fmt.Sprintf("The name %s is used by VerrazzanoExtension %s", ext.Name, getExtensionKey(&exts[i]))
+		}
+	}
+	byName := map[string]*vzapi.VerrazzanoExtension{}
+	for i := range exts {
+		if exts[i].Namespace == ext.Namespace {
+			byName[exts[i].Name] = &exts[i]
+		}
+	}
+	for _, dependency := range ext.Spec.Dependencies {
+		if _, ok := byName[dependency]; !ok && !registry.IsBuiltInComponent(dependency) {
+			return fmt.Sprintf("The dependency %s is not a Verrazzano component or extension", dependency)
+		}
+		if dependsOnExtension(dependency, ext.Name, byName, map[string]bool{}) {
+			return fmt.Sprintf("The dependency %s depends on extension %s", dependency, ext.Name)
+		}
+	}
+	return ""
+}
+
+// isNameTakenBy returns true if the other extension, in another namespace, has the same name and was created first.
+// The components of the extensions are registered by name, so only the first extension with a given name is installed.
+func isNameTakenBy(ext *vzapi.VerrazzanoExtension, other *vzapi.VerrazzanoExtension) bool {
+	if other.Name != ext.Name || other.Namespace == ext.Namespace {
+		return false
+	}
+	if !other.CreationTimestamp.Equal(&ext.CreationTimestamp) {
+		return other.CreationTimestamp.Before(&ext.CreationTimestamp)
+	}
+	return other.Namespace < ext.Namespace
+}
+
+// getExtensionKey returns the namespace and name of the extension
+func getExtensionKey(ext *vzapi.VerrazzanoExtension) string {
+	return ext.Namespace + "/" + ext.Name
+}
+
+// dependsOnExtension returns true if the extension with the given name depends, directly or indirectly, on the
+// target extension.  Built-in components never depend on extensions.
+func dependsOnExtension(name string, target string, byName map[string]*vzapi.VerrazzanoExtension, visited map[string]bool) bool {
+	if name == target {
+		return true
+	}
+	ext, ok := byName[name]
+	if !ok || visited[name] {
+		return false
+	}
+	visited[name] = true
+	for _, dependency := range ext.Spec.Dependencies {
+		if dependsOnExtension(dependency, target, byName, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// procDeleteExtension waits for the component of a deleted extension to be uninstalled, then removes the finalizer
+// and the component status from the Verrazzano resource
+func (r *VerrazzanoExtensionReconciler) procDeleteExtension(ctx context.Context, log vzlog.VerrazzanoLogger, ext *vzapi.VerrazzanoExtension, vz *vzapi.Verrazzano) (ctrl.Result, error) {
+	if !vzstring.SliceContainsString(ext.Finalizers, extensionFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+	if vz != nil && vz.DeletionTimestamp.IsZero() {
+		if isExtensionInstalled(vz, ext.Name) {
+			markOverridesChanged(vz, ext.Name)
+		}
+		if status, ok := vz.Status.Components[ext.Name]; ok && status.State != vzapi.CompStateDisabled {
+			log.Progressf("VerrazzanoExtension %s/%s is waiting for its component to be uninstalled", ext.Namespace, ext.Name)
+			return newRequeueWithDelay(), nil
+		}
+	}
+
+	ext.Finalizers = vzstring.RemoveStringFromSlice(ext.Finalizers, extensionFinalizerName)
+	if err := r.Update(ctx, ext); err != nil {
+		log.Errorf("Failed to remove the finalizer from VerrazzanoExtension %s/%s: %v", ext.Namespace, ext.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	if _, err := r.syncExtensionComponents(ctx); err != nil {
+		log.Errorf("Failed to register the VerrazzanoExtension components: %v", err)
+		return newRequeueWithDelay(), nil
+	}
+	if vz == nil {
+		return ctrl.Result{}, nil
+	}
+	clearOverridesChanged(vz, ext.Name)
+	if _, ok := vz.Status.Components[ext.Name]; ok {
+		delete(vz.Status.Components, ext.Name)
+		if err := r.Status().Update(ctx, vz); err != nil {
+			log.Errorf("Failed to remove the status of component %s from Verrazzano %s/%s: %v", ext.Name, vz.Namespace, vz.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+	}
+	log.Oncef("VerrazzanoExtension %s/%s has been uninstalled", ext.Namespace, ext.Name)
+	return ctrl.Result{}, nil
+}
+
+// updateExtensionStatus saves the reason the extension cannot be installed in its status.  If the extension is
+// invalid the reconcile is requeued, since it may depend on an extension that doesn't exist yet.
+func (r *VerrazzanoExtensionReconciler) updateExtensionStatus(ctx context.Context, log vzlog.VerrazzanoLogger, ext *vzapi.VerrazzanoExtension, message string) (ctrl.Result, error) {
+	observedGeneration := ext.Status.ObservedGeneration
+	if len(message) == 0 {
+		observedGeneration = ext.Generation
+	} else {
+		log.Progressf("VerrazzanoExtension %s/%s cannot be installed: %s", ext.Namespace, ext.Name, message)
+	}
+	if ext.Status.Message != message || ext.Status.ObservedGeneration != observedGeneration {
+		ext.Status.Message = message
+		ext.Status.ObservedGeneration = observedGeneration
+		if err := r.Status().Update(ctx, ext); err != nil {
+			log.Errorf("Failed to update the status of VerrazzanoExtension %s/%s: %v", ext.Namespace, ext.Name, err)
+			return newRequeueWithDelay(), nil
+		}
+	}
+	if len(message) > 0 {
+		return newRequeueWithDelay(), nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// getVerrazzano returns the Verrazzano resource in the namespace, or nil if there isn't one
+func (r *VerrazzanoExtensionReconciler) getVerrazzano(ctx context.Context, namespace string) (*vzapi.Verrazzano, error) {
+	vzList := &vzapi.VerrazzanoList{}
+	if err := r.List(ctx, vzList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	if len(vzList.Items) == 0 {
+		return nil, nil
+	}
+	return &vzList.Items[0], nil
+}
+
+// isExtensionInstalled returns true if the component of the extension is installed
+func isExtensionInstalled(vz *vzapi.Verrazzano, name string) bool {
+	if vz == nil {
+		return false
+	}
+	status, ok := vz.Status.Components[name]
+	return ok && (status.State == vzapi.CompStateReady || status.State == vzapi.CompStateDegraded)
+}
+
+// watchExtensions watches the VerrazzanoExtension resources in the namespace of the Verrazzano resource, so that the
+// components of the extensions are reconciled once they are registered
+func (r *Reconciler) watchExtensions(namespace string, name string, log vzlog.VerrazzanoLogger) error {
+	inNamespace := func(obj client.Object) bool {
+		return obj.GetNamespace() == namespace
+	}
+	log.Debugf("Watching for VerrazzanoExtensions to activate reconcile for Verrazzano CR %s/%s", namespace, name)
+	return r.Controller.Watch(
+		&source.Kind{Type: &vzapi.VerrazzanoExtension{}},
+		createReconcileEventHandler(namespace, name),
+		predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return inNamespace(e.Object) },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return inNamespace(e.ObjectNew) },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return inNamespace(e.Object) },
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+		})
+}
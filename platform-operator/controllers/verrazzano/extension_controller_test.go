@@ -0,0 +1,203 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/istio"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestExtension returns a VerrazzanoExtension with a chart in the operator image
+func newTestExtension(name string, dependencies ...string) vzapi.VerrazzanoExtension {
+	return vzapi.VerrazzanoExtension{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: name, Generation: 1},
+		Spec: vzapi.VerrazzanoExtensionSpec{
+			Chart:        vzapi.ExtensionChart{Path: "/charts/" + name},
+			Namespace:    name,
+			Dependencies: dependencies,
+		},
+	}
+}
+
+// isExtensionRegistered returns true if the component of the extension is in the registry
+func isExtensionRegistered(name string) bool {
+	found, _ := registry.FindComponent(name)
+	return found
+}
+
+// TestValidateExtensions tests the validateExtensions function
+// GIVEN VerrazzanoExtensions
+// WHEN they are validated
+// THEN a message is returned for each extension that cannot be installed
+func TestValidateExtensions(t *testing.T) {
+	asserts := assert.New(t)
+
+	noChart := newTestExtension("no-chart")
+	noChart.Spec.Chart.Path = ""
+	twoCharts := newTestExtension("two-charts")
+	twoCharts.Spec.Chart.OCIReference = "oci://registry.local/charts/two-charts"
+	noVersion := newTestExtension("no-version")
+	noVersion.Spec.Chart = vzapi.ExtensionChart{OCIReference: "oci://registry.local/charts/no-version"}
+	noNamespace := newTestExtension("no-namespace")
+	noNamespace.Spec.Namespace = ""
+	sameNameEarlier := newTestExtension("same-name")
+	sameNameEarlier.Namespace = "team-b"
+	sameNameEarlier.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	sameNameLater := newTestExtension("same-name")
+	sameNameLater.CreationTimestamp = metav1.Now()
+
+	messages := validateExtensions([]vzapi.VerrazzanoExtension{
+		newTestExtension("valid", istio.ComponentName),
+		newTestExtension("depends-on-valid", "valid"),
+		noChart,
+		twoCharts,
+		noVersion,
+		noNamespace,
+		newTestExtension(istio.ComponentName),
+		newTestExtension("unknown-dependency", "unknown"),
+		newTestExtension("depends-on-invalid", "unknown-dependency"),
+		newTestExtension("cycle-a", "cycle-b"),
+		newTestExtension("cycle-b", "cycle-a"),
+		sameNameEarlier,
+		sameNameLater,
+	})
+	asserts.NotContains(messages, "verrazzano/valid")
+	asserts.NotContains(messages, "verrazzano/depends-on-valid")
+	asserts.Contains(messages["verrazzano/no-chart"], "Exactly one of")
+	asserts.Contains(messages["verrazzano/two-charts"], "Exactly one of")
+	asserts.Contains(messages["verrazzano/no-version"], "chart.version")
+	asserts.Contains(messages["verrazzano/no-namespace"], "namespace")
+	asserts.Contains(messages["verrazzano/istio"], "is the name of a Verrazzano component")
+	asserts.Contains(messages["verrazzano/unknown-dependency"], "is not a Verrazzano component or extension")
+	asserts.Contains(messages["verrazzano/depends-on-invalid"], "cannot be installed")
+	asserts.Contains(messages["verrazzano/cycle-a"], "depends on extension cycle-a")
+	asserts.Contains(messages["verrazzano/cycle-b"], "depends on extension cycle-b")
+	asserts.NotContains(messages, "team-b/same-name")
+	asserts.Contains(messages["verrazzano/same-name"], "is used by VerrazzanoExtension team-b/same-name")
+}
+
+// TestReconcileExtension tests the VerrazzanoExtensionReconciler Reconcile func
+// GIVEN a valid and an invalid VerrazzanoExtension
+// WHEN Reconcile is called
+// THEN the component of the valid extension is registered and the reason the other is invalid is in its status
+func TestReconcileExtension(t *testing.T) {
+	asserts := assert.New(t)
+	defer registry.SetExtensionComponents(nil)
+
+	valid := newTestExtension("addon", istio.ComponentName)
+	invalid := newTestExtension("broken", "unknown")
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(&valid, &invalid).Build()
+	reconciler := VerrazzanoExtensionReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "verrazzano", Name: "addon"}})
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+	asserts.True(isExtensionRegistered("addon"))
+	asserts.False(isExtensionRegistered("broken"))
+
+	actual := &vzapi.VerrazzanoExtension{}
+	asserts.NoError(c.Get(context.TODO(), client.ObjectKeyFromObject(&valid), actual))
+	asserts.Contains(actual.Finalizers, extensionFinalizerName)
+	asserts.Equal(int64(1), actual.Status.ObservedGeneration)
+	asserts.Empty(actual.Status.Message)
+
+	result, err = reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "verrazzano", Name: "broken"}})
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.NoError(c.Get(context.TODO(), client.ObjectKeyFromObject(&invalid), actual))
+	asserts.NotContains(actual.Finalizers, extensionFinalizerName)
+	asserts.Contains(actual.Status.Message, "unknown")
+}
+
+// TestReconcileExtensionChanged tests the VerrazzanoExtensionReconciler Reconcile func
+// GIVEN a VerrazzanoExtension that has changed since its component was installed
+// WHEN Reconcile is called
+// THEN the component needs to be installed again
+func TestReconcileExtensionChanged(t *testing.T) {
+	asserts := assert.New(t)
+	defer registry.SetExtensionComponents(nil)
+
+	ext := newTestExtension("addon")
+	ext.Generation = 2
+	ext.Finalizers = []string{extensionFinalizerName}
+	ext.Status.ObservedGeneration = 1
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-extension-changed"},
+		Status: vzapi.VerrazzanoStatus{
+			Components: vzapi.ComponentStatusMap{"addon": {Name: "addon", State: vzapi.CompStateReady}},
+		},
+	}
+	defer deleteOverridesChanged(vz)
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(&ext, vz).Build()
+	reconciler := VerrazzanoExtensionReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "verrazzano", Name: "addon"}})
+	asserts.NoError(err)
+	asserts.True(isOverridesChanged(vz, "addon"))
+
+	actual := &vzapi.VerrazzanoExtension{}
+	asserts.NoError(c.Get(context.TODO(), client.ObjectKeyFromObject(&ext), actual))
+	asserts.Equal(int64(2), actual.Status.ObservedGeneration)
+}
+
+// TestReconcileExtensionDelete tests the VerrazzanoExtensionReconciler Reconcile func
+// GIVEN a deleted VerrazzanoExtension
+// WHEN Reconcile is called
+// THEN the finalizer is kept until the component is uninstalled, then the component is unregistered
+func TestReconcileExtensionDelete(t *testing.T) {
+	asserts := assert.New(t)
+	defer registry.SetExtensionComponents(nil)
+
+	now := metav1.Now()
+	ext := newTestExtension("addon")
+	ext.Finalizers = []string{extensionFinalizerName}
+	ext.DeletionTimestamp = &now
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-extension-delete"},
+		Status: vzapi.VerrazzanoStatus{
+			Components: vzapi.ComponentStatusMap{"addon": {Name: "addon", State: vzapi.CompStateReady}},
+		},
+	}
+	defer deleteOverridesChanged(vz)
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(&ext, vz).Build()
+	reconciler := VerrazzanoExtensionReconciler{Client: c, Scheme: k8scheme.Scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "verrazzano", Name: "addon"}}
+
+	// The installed component is uninstalled before the finalizer is removed
+	result, err := reconciler.Reconcile(context.TODO(), req)
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.True(isExtensionRegistered("addon"))
+	asserts.True(isOverridesChanged(vz, "addon"))
+
+	vz.Status.Components["addon"].State = vzapi.CompStateDisabled
+	asserts.NoError(c.Status().Update(context.TODO(), vz))
+	result, err = reconciler.Reconcile(context.TODO(), req)
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+	asserts.False(isExtensionRegistered("addon"))
+	asserts.False(isOverridesChanged(vz, "addon"))
+
+	err = c.Get(context.TODO(), client.ObjectKeyFromObject(&ext), &vzapi.VerrazzanoExtension{})
+	asserts.True(errors.IsNotFound(err))
+	actualVz := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), client.ObjectKeyFromObject(vz), actualVz))
+	asserts.NotContains(actualVz.Status.Components, "addon")
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// healthCheckInterval is the time between the health checks of the components once Verrazzano is installed
+const healthCheckInterval = 1 * time.Minute
+
+// componentError is the last error of an operation of a component
+type componentError struct {
+	message string
+	time    string
+}
+
+// lastComponentErrorMap has the last error of each component, by component name, keyed by the namespace and name of
+// the Verrazzano resource.  The error is kept until the component is ready.
+var lastComponentErrorMap = map[string]map[string]componentError{}
+
+// lastComponentErrorMutex guards lastComponentErrorMap, which is updated by the components reconciled in parallel
+var lastComponentErrorMutex sync.Mutex
+
+// checkComponentHealth checks that the components that have been installed are still ready.  A Ready component that
+// is no longer ready is set to Degraded, and a Degraded component is set back to Ready once it recovers.  The health
+// check is requeued so that it is done periodically.
+func (r *Reconciler) checkComponentHealth(vzctx vzcontext.VerrazzanoContext) (ctrl.Result, error) {
+	cr := vzctx.ActualCR
+	spiCtx, err := spi.NewContext(vzctx.Log, r.Client, cr, r.DryRun)
+	if err != nil {
+		vzctx.Log.Errorf("Failed to create component context: %v", err)
+		return newRequeueWithDelay(), err
+	}
+	unready := map[string][]string{}
+	for _, comp := range registry.GetComponents() {
+		compName := comp.Name()
+		compStatus, ok := cr.Status.Components[compName]
+		if !ok || !comp.IsOperatorInstallSupported() || !comp.IsEnabled(spiCtx.EffectiveCR()) {
+			continue
+		}
+		if compStatus.State != vzapi.CompStateReady && compStatus.State != vzapi.CompStateDegraded {
+			continue
+		}
+		compContext := spiCtx.Init(compName).Operation(vzconst.InstallOperation)
+		ready := comp.IsReady(compContext)
+		unready[compName] = nil
+		if !ready {
+			unready[compName] = getUnreadyWorkloads(compContext, comp)
+		}
+		switch {
+		case ready && compStatus.State == vzapi.CompStateDegraded:
+			compContext.Log().Infof("Component %s has recovered and is ready", compName)
+			compStatus.Conditions = removeCondition(compStatus.Conditions, vzapi.CondDegraded)
+			if err := r.updateComponentStatus(compContext, "Component has recovered and is ready", vzapi.CondInstallComplete); err != nil {
+				return newRequeueWithDelay(), err
+			}
+		case !ready && compStatus.State == vzapi.CompStateReady:
+			msg := getDegradedMessage(unready[compName])
+			compContext.Log().Infof("Component %s is degraded: %s", compName, msg)
+			if err := r.updateComponentStatus(compContext, msg, vzapi.CondDegraded); err != nil {
+				return newRequeueWithDelay(), err
+			}
+		case !ready:
+			// Keep the details of the unready workloads current while the component is degraded
+			msg := getDegradedMessage(unready[compName])
+			if updateConditionMessage(compStatus.Conditions, vzapi.CondDegraded, msg) {
+				if err := r.updateVerrazzanoStatus(compContext.Log(), cr); err != nil {
+					return newRequeueWithDelay(), err
+				}
+			}
+		}
+	}
+	if err := r.updateHealth(spiCtx, unready); err != nil {
+		return newRequeueWithDelay(), err
+	}
+	return ctrl.Result{RequeueAfter: healthCheckInterval}, nil
+}
+
+// getUnreadyWorkloads returns the workloads of a component that are not ready, if the component reports them
+func getUnreadyWorkloads(compContext spi.ComponentContext, comp spi.Component) []string {
+	reporter, ok := comp.(spi.ComponentHealthReporter)
+	if !ok {
+		return nil
+	}
+	unready, err := reporter.GetUnreadyWorkloads(compContext)
+	if err != nil {
+		compContext.Log().Errorf("Failed getting the workloads of component %s that are not ready: %v", comp.Name(), err)
+		return nil
+	}
+	return unready
+}
+
+// getDegradedMessage returns the condition message of a component that is not ready, including the workloads that
+// are not ready
+func getDegradedMessage(unready []string) string {
+	const msg = "Component is not ready"
+	if len(unready) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", msg, strings.Join(unready, ", "))
+}
+
+// updateHealth updates the health rollup of the enabled components in the status.  The unready workloads are given by
+// component name, the unready workloads of a component that is not in the map are kept from the previous rollup while
+// the component stays in the same state.  The status is only updated if the health changed.
+func (r *Reconciler) updateHealth(spiCtx spi.ComponentContext, unready map[string][]string) error {
+	cr := spiCtx.ActualCR()
+	health := &vzapi.HealthStatus{Summary: vzapi.HealthAvailable}
+	previous := map[string]vzapi.ComponentHealth{}
+	if cr.Status.Health != nil {
+		for _, compHealth := range cr.Status.Health.Components {
+			previous[compHealth.Name] = compHealth
+		}
+	}
+	var notReady []string
+	for _, comp := range registry.GetComponents() {
+		compName := comp.Name()
+		compStatus, ok := cr.Status.Components[compName]
+		if !ok || compStatus == nil || !comp.IsOperatorInstallSupported() || !comp.IsEnabled(spiCtx.EffectiveCR()) {
+			continue
+		}
+		compHealth := vzapi.ComponentHealth{Name: compName, State: compStatus.State}
+		if workloads, ok := unready[compName]; ok {
+			compHealth.UnreadyWorkloads = workloads
+		} else if prev, ok := previous[compName]; ok && prev.State == compStatus.State {
+			compHealth.UnreadyWorkloads = prev.UnreadyWorkloads
+		}
+		if len(compStatus.Conditions) > 0 {
+			compHealth.LastTransitionTime = compStatus.Conditions[len(compStatus.Conditions)-1].LastTransitionTime
+		}
+		if compStatus.State == vzapi.CompStateReady && len(compHealth.UnreadyWorkloads) == 0 {
+			clearComponentError(cr, compName)
+		} else if compErr, ok := getComponentError(cr, compName); ok {
+			compHealth.LastError = compErr.message
+			compHealth.LastErrorTime = compErr.time
+		}
+		health.Components = append(health.Components, compHealth)
+
+		switch compStatus.State {
+		case vzapi.CompStateReady:
+			if len(compHealth.UnreadyWorkloads) > 0 {
+				notReady = append(notReady, compName)
+				if health.Summary == vzapi.HealthAvailable {
+					health.Summary = vzapi.HealthDegraded
+				}
+			}
+		case vzapi.CompStateDegraded, vzapi.CompStateUpgrading:
+			notReady = append(notReady, fmt.Sprintf("%s (%s)", compName, compStatus.State))
+			if health.Summary == vzapi.HealthAvailable {
+				health.Summary = vzapi.HealthDegraded
+			}
+		default:
+			notReady = append(notReady, fmt.Sprintf("%s (%s)", compName, compStatus.State))
+			health.Summary = vzapi.HealthUnavailable
+		}
+	}
+	if len(notReady) > 0 {
+		health.Message = fmt.Sprintf("The components that are not ready are %s", strings.Join(notReady, ", "))
+	} else {
+		health.Message = "All the enabled components are ready"
+	}
+
+	existing := cr.Status.Health
+	if existing != nil && existing.Summary == health.Summary {
+		health.LastTransitionTime = existing.LastTransitionTime
+		if existing.Message == health.Message && reflect.DeepEqual(existing.Components, health.Components) {
+			return nil
+		}
+	} else {
+		health.LastTransitionTime = formatStatusTime(time.Now())
+	}
+	cr.Status.Health = health
+	return r.updateVerrazzanoStatus(spiCtx.Log(), cr)
+}
+
+// setComponentError remembers the last error of an operation of a component, for the health rollup
+func setComponentError(cr *vzapi.Verrazzano, compName string, message string) {
+	lastComponentErrorMutex.Lock()
+	defer lastComponentErrorMutex.Unlock()
+	key := getNSNKey(cr)
+	if _, ok := lastComponentErrorMap[key]; !ok {
+		lastComponentErrorMap[key] = map[string]componentError{}
+	}
+	lastComponentErrorMap[key][compName] = componentError{
+		message: message,
+		time:    formatStatusTime(time.Now()),
+	}
+}
+
+// getComponentError returns the last error of an operation of a component
+func getComponentError(cr *vzapi.Verrazzano, compName string) (componentError, bool) {
+	lastComponentErrorMutex.Lock()
+	defer lastComponentErrorMutex.Unlock()
+	compErr, ok := lastComponentErrorMap[getNSNKey(cr)][compName]
+	return compErr, ok
+}
+
+// clearComponentError forgets the last error of a component once it is ready
+func clearComponentError(cr *vzapi.Verrazzano, compName string) {
+	lastComponentErrorMutex.Lock()
+	defer lastComponentErrorMutex.Unlock()
+	delete(lastComponentErrorMap[getNSNKey(cr)], compName)
+}
+
+// deleteComponentErrors forgets the errors of the components of the Verrazzano resource
+func deleteComponentErrors(cr *vzapi.Verrazzano) {
+	lastComponentErrorMutex.Lock()
+	defer lastComponentErrorMutex.Unlock()
+	delete(lastComponentErrorMap, getNSNKey(cr))
+}
+
+// removeCondition returns the conditions without the conditions of the given type
+func removeCondition(conditions []vzapi.Condition, conditionType vzapi.ConditionType) []vzapi.Condition {
+	var result []vzapi.Condition
+	for _, condition := range conditions {
+		if condition.Type != conditionType {
+			result = append(result, condition)
+		}
+	}
+	return result
+}
+
+// updateConditionMessage sets the message of the condition of the given type, returns true if the message changed
+func updateConditionMessage(conditions []vzapi.Condition, conditionType vzapi.ConditionType, message string) bool {
+	for i := range conditions {
+		if conditions[i].Type == conditionType && conditions[i].Message != message {
+			conditions[i].Message = message
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestCheckComponentHealth tests the checkComponentHealth method for the following use case
+// GIVEN an installed component that is Ready
+// WHEN the component is no longer ready, and then recovers
+// THEN the component is set to Degraded with the details of the unready workloads, and then back to Ready
+func TestCheckComponentHealth(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	ready := "false"
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeComponent{
+				HelmComponent: helm.HelmComponent{ReleaseName: "a", SupportsOperatorInstall: true},
+				ready:         ready,
+			},
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-health", Generation: 1},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateReady,
+			Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}},
+			Components: vzapi.ComponentStatusMap{
+				"a": {
+					Name:                     "a",
+					State:                    vzapi.CompStateReady,
+					LastReconciledGeneration: 1,
+					Conditions:               []vzapi.Condition{{Type: vzapi.CondInstallComplete}},
+				},
+			},
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "verrazzano",
+			Name:        "a",
+			Annotations: map[string]string{"meta.helm.sh/release-name": "a"},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz, deployment).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	nsn := types.NamespacedName{Namespace: "verrazzano", Name: "test-health"}
+
+	// The component is no longer ready
+	result, err := reconciler.checkComponentHealth(vzcontext.VerrazzanoContext{Log: vzlog.DefaultLogger(), Client: c, ActualCR: vz})
+	asserts.NoError(err)
+	asserts.Equal(healthCheckInterval, result.RequeueAfter)
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	compStatus := vz.Status.Components["a"]
+	asserts.Equal(vzapi.CompStateDegraded, compStatus.State)
+	asserts.Equal(vzapi.CondDegraded, compStatus.Conditions[len(compStatus.Conditions)-1].Type)
+	asserts.Equal("Component is not ready: deployment verrazzano/a has 0 of 1 replicas available",
+		compStatus.Conditions[len(compStatus.Conditions)-1].Message)
+	asserts.NotNil(vz.Status.Health)
+	asserts.Equal(vzapi.HealthDegraded, vz.Status.Health.Summary)
+	asserts.Equal([]string{"deployment verrazzano/a has 0 of 1 replicas available"}, vz.Status.Health.Components[0].UnreadyWorkloads)
+
+	// The component recovers
+	ready = "true"
+	_, err = reconciler.checkComponentHealth(vzcontext.VerrazzanoContext{Log: vzlog.DefaultLogger(), Client: c, ActualCR: vz})
+	asserts.NoError(err)
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	compStatus = vz.Status.Components["a"]
+	asserts.Equal(vzapi.CompStateReady, compStatus.State)
+	for _, condition := range compStatus.Conditions {
+		asserts.NotEqual(vzapi.CondDegraded, condition.Type)
+	}
+	asserts.Equal(vzapi.HealthAvailable, vz.Status.Health.Summary)
+	asserts.Empty(vz.Status.Health.Components[0].UnreadyWorkloads)
+}
+
+// TestUpdateHealth tests the updateHealth method for the following use case
+// GIVEN enabled components in different states, and a component operation that failed
+// WHEN the health rollup is updated
+// THEN the summary is Unavailable, Degraded or Available, and the last error is kept until the component is ready
+func TestUpdateHealth(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "a", SupportsOperatorInstall: true}},
+			fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "b", SupportsOperatorInstall: true}},
+			fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "c", SupportsOperatorInstall: true}, enabled: "false"},
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-update-health"},
+		Status: vzapi.VerrazzanoStatus{
+			Components: vzapi.ComponentStatusMap{
+				"a": {Name: "a", State: vzapi.CompStateReady, Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete, LastTransitionTime: "2022-06-01T10:00:00Z"}}},
+				"b": {Name: "b", State: vzapi.CompStateInstalling, Conditions: []vzapi.Condition{{Type: vzapi.CondInstallStarted, LastTransitionTime: "2022-06-01T10:01:00Z"}}},
+				"c": {Name: "c", State: vzapi.CompStateDisabled},
+			},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	defer deleteComponentErrors(vz)
+	nsn := types.NamespacedName{Namespace: "verrazzano", Name: "test-update-health"}
+	spiCtx := spi.NewFakeContext(c, vz, false)
+
+	// A component is still installing after an error
+	reconciler.recordOperationError(vz, "b", "Install", fmt.Errorf("the chart is invalid"))
+	asserts.NoError(reconciler.updateHealth(spiCtx, nil))
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	health := vz.Status.Health
+	asserts.Equal(vzapi.HealthUnavailable, health.Summary)
+	asserts.Equal("The components that are not ready are b (Installing)", health.Message)
+	asserts.Len(health.Components, 2)
+	asserts.Equal("2022-06-01T10:00:00Z", health.Components[0].LastTransitionTime)
+	asserts.Empty(health.Components[0].LastError)
+	asserts.Equal("Install failed: the chart is invalid", health.Components[1].LastError)
+	asserts.NotEmpty(health.Components[1].LastErrorTime)
+
+	// The status is not updated while the health is unchanged
+	resourceVersion := vz.ResourceVersion
+	asserts.NoError(reconciler.updateHealth(spi.NewFakeContext(c, vz, false), nil))
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	asserts.Equal(resourceVersion, vz.ResourceVersion)
+
+	// A component is degraded
+	vz.Status.Components["b"].State = vzapi.CompStateDegraded
+	asserts.NoError(reconciler.updateHealth(spi.NewFakeContext(c, vz, false), map[string][]string{"b": {"deployment verrazzano/b has 0 of 1 replicas available"}}))
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	asserts.Equal(vzapi.HealthDegraded, vz.Status.Health.Summary)
+	asserts.Equal([]string{"deployment verrazzano/b has 0 of 1 replicas available"}, vz.Status.Health.Components[1].UnreadyWorkloads)
+	asserts.Equal("Install failed: the chart is invalid", vz.Status.Health.Components[1].LastError)
+
+	// All the components are ready, the error is cleared
+	vz.Status.Components["b"].State = vzapi.CompStateReady
+	asserts.NoError(reconciler.updateHealth(spi.NewFakeContext(c, vz, false), map[string][]string{"b": nil}))
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	asserts.Equal(vzapi.HealthAvailable, vz.Status.Health.Summary)
+	asserts.Equal("All the enabled components are ready", vz.Status.Health.Message)
+	asserts.Empty(vz.Status.Health.Components[1].LastError)
+	asserts.NotEmpty(vz.Status.Health.LastTransitionTime)
+	_, found := getComponentError(vz, "b")
+	asserts.False(found)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// overridesChangedMap has the names of the components whose monitored Helm value overrides, or VerrazzanoExtension,
+// have changed, keyed by the namespace and name of the Verrazzano resource
+var overridesChangedMap = map[string]map[string]bool{}
+
+// overridesChangedMutex guards overridesChangedMap, which is updated by the watches and read by the reconcile
+var overridesChangedMutex sync.Mutex
+
+// watchHelmOverrides watches the ConfigMaps and Secrets in the namespace of the Verrazzano resource.  A change to a
+// ConfigMap or Secret that has Helm value overrides of a component with monitorChanges set triggers a reconcile that
+// installs the component again.
+func (r *Reconciler) watchHelmOverrides(namespace string, name string, log vzlog.VerrazzanoLogger) error {
+	p := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return e.Object.GetNamespace() == namespace
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectNew.GetNamespace() == namespace && isOverrideDataChanged(e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return e.Object.GetNamespace() == namespace
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+	eventHandler := handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		nsn := types.NamespacedName{Namespace: namespace, Name: name}
+		if !r.setOverridesChanged(nsn, obj, log) {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: nsn}}
+	})
+
+	log.Debugf("Watching for ConfigMaps and Secrets with Helm value overrides for Verrazzano CR %s/%s", namespace, name)
+	if err := r.Controller.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, eventHandler, p); err != nil {
+		return err
+	}
+	return r.Controller.Watch(&source.Kind{Type: &corev1.Secret{}}, eventHandler, p)
+}
+
+// isOverrideDataChanged returns true if the data of a ConfigMap or Secret has changed
+func isOverrideDataChanged(oldObj client.Object, newObj client.Object) bool {
+	switch newRes := newObj.(type) {
+	case *corev1.ConfigMap:
+		oldRes, ok := oldObj.(*corev1.ConfigMap)
+		return !ok || !reflect.DeepEqual(oldRes.Data, newRes.Data) || !reflect.DeepEqual(oldRes.BinaryData, newRes.BinaryData)
+	case *corev1.Secret:
+		oldRes, ok := oldObj.(*corev1.Secret)
+		return !ok || !reflect.DeepEqual(oldRes.Data, newRes.Data) || !reflect.DeepEqual(oldRes.StringData, newRes.StringData)
+	}
+	return false
+}
+
+// setOverridesChanged records each enabled component of the Verrazzano resource that monitors the Helm value
+// overrides in the ConfigMap or Secret that has changed.  Returns true if there is such a component.
+func (r *Reconciler) setOverridesChanged(nsn types.NamespacedName, obj client.Object, log vzlog.VerrazzanoLogger) bool {
+	cr := &vzapi.Verrazzano{}
+	if err := r.Get(context.TODO(), nsn, cr); err != nil {
+		log.Debugf("Failed getting Verrazzano CR %s: %v", nsn.String(), err)
+		return false
+	}
+	spiCtx, err := spi.NewContext(log, r.Client, cr, r.DryRun)
+	if err != nil {
+		log.Errorf("Failed to create component context: %v", err)
+		return false
+	}
+
+	changed := false
+	for _, comp := range registry.GetComponents() {
+		monitor, ok := comp.(spi.ComponentOverridesMonitor)
+		if !ok || !comp.IsEnabled(spiCtx.EffectiveCR()) {
+			continue
+		}
+		for _, override := range monitor.GetMonitoredOverrides(spiCtx.Init(comp.Name())) {
+			if isOverrideResource(override, obj) {
+				log.Infof("Helm value overrides of component %s in %T %s/%s have changed, the component will be installed again",
+					comp.Name(), obj, obj.GetNamespace(), obj.GetName())
+				markOverridesChanged(cr, comp.Name())
+				changed = true
+				break
+			}
+		}
+	}
+	return changed
+}
+
+// isOverrideResource returns true if the ConfigMap or Secret is referenced by the override
+func isOverrideResource(override vzapi.Overrides, obj client.Object) bool {
+	switch obj.(type) {
+	case *corev1.ConfigMap:
+		return override.ConfigMapRef != nil && override.ConfigMapRef.Name == obj.GetName()
+	case *corev1.Secret:
+		return override.SecretRef != nil && override.SecretRef.Name == obj.GetName()
+	}
+	return false
+}
+
+// markOverridesChanged records that the monitored Helm value overrides of the component have changed
+func markOverridesChanged(cr *vzapi.Verrazzano, compName string) {
+	overridesChangedMutex.Lock()
+	defer overridesChangedMutex.Unlock()
+	key := getNSNKey(cr)
+	if _, ok := overridesChangedMap[key]; !ok {
+		overridesChangedMap[key] = map[string]bool{}
+	}
+	overridesChangedMap[key][compName] = true
+}
+
+// isOverridesChanged returns true if the monitored Helm value overrides of the component have changed since the
+// component was last installed
+func isOverridesChanged(cr *vzapi.Verrazzano, compName string) bool {
+	overridesChangedMutex.Lock()
+	defer overridesChangedMutex.Unlock()
+	return overridesChangedMap[getNSNKey(cr)][compName]
+}
+
+// clearOverridesChanged forgets the change to the Helm value overrides of the component once the component is
+// being installed again
+func clearOverridesChanged(cr *vzapi.Verrazzano, compName string) {
+	overridesChangedMutex.Lock()
+	defer overridesChangedMutex.Unlock()
+	delete(overridesChangedMap[getNSNKey(cr)], compName)
+}
+
+// deleteOverridesChanged forgets the changes to the Helm value overrides of the Verrazzano resource
+func deleteOverridesChanged(cr *vzapi.Verrazzano) {
+	overridesChangedMutex.Lock()
+	defer overridesChangedMutex.Unlock()
+	delete(overridesChangedMap, getNSNKey(cr))
+}
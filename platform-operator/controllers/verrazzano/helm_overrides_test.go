@@ -0,0 +1,109 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newFakeOverridesComponent returns a component with Helm value overrides in the given ConfigMap
+func newFakeOverridesComponent(name string, configMapName string, monitorChanges bool) fakeComponent {
+	return fakeComponent{HelmComponent: helm.HelmComponent{
+		ReleaseName:             name,
+		SupportsOperatorInstall: true,
+		GetHelmValueOverrides: func(_ spi.ComponentContext) []vzapi.HelmValueOverrides {
+			return []vzapi.HelmValueOverrides{{
+				MonitorChanges: &monitorChanges,
+				ValueOverrides: []vzapi.Overrides{{
+					ConfigMapRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+						Key:                  "values.yaml",
+					},
+				}},
+			}}
+		},
+	}}
+}
+
+// TestSetOverridesChanged tests the setOverridesChanged method for the following use case
+// GIVEN components with Helm value overrides in ConfigMaps
+// WHEN a ConfigMap or Secret changes
+// THEN the component that monitors the changed ConfigMap needs to be installed again
+func TestSetOverridesChanged(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			newFakeOverridesComponent("a", "a-values", true),
+			newFakeOverridesComponent("b", "b-values", false),
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-overrides", Generation: 1},
+		Status: vzapi.VerrazzanoStatus{
+			State: vzapi.VzStateReady,
+			Components: vzapi.ComponentStatusMap{
+				"a": {Name: "a", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+				"b": {Name: "b", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+			},
+		},
+	}
+	defer deleteOverridesChanged(vz)
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	nsn := types.NamespacedName{Namespace: "verrazzano", Name: "test-overrides"}
+	log := vzlog.DefaultLogger()
+	ctx := spi.NewFakeContext(c, vz, false)
+
+	// Changes to resources that aren't monitored are ignored
+	asserts.False(reconciler.setOverridesChanged(nsn, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "b-values"}}, log))
+	asserts.False(reconciler.setOverridesChanged(nsn, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "a-values"}}, log))
+	asserts.False(checkConfigUpdated(ctx, vz.Status.Components["a"], "a"))
+	asserts.False(checkConfigUpdated(ctx, vz.Status.Components["b"], "b"))
+
+	// A change to a monitored ConfigMap requires the component to be installed again
+	asserts.True(reconciler.setOverridesChanged(nsn, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "a-values"}}, log))
+	asserts.True(checkConfigUpdated(ctx, vz.Status.Components["a"], "a"))
+	asserts.False(checkConfigUpdated(ctx, vz.Status.Components["b"], "b"))
+
+	// The change is forgotten once the component is being installed again
+	clearOverridesChanged(vz, "a")
+	asserts.False(checkConfigUpdated(ctx, vz.Status.Components["a"], "a"))
+}
+
+// TestIsOverrideDataChanged tests the isOverrideDataChanged function
+// GIVEN an update of a ConfigMap or Secret
+// WHEN the data is or isn't changed
+// THEN true is only returned when the data has changed
+func TestIsOverrideDataChanged(t *testing.T) {
+	asserts := assert.New(t)
+	oldCM := &corev1.ConfigMap{Data: map[string]string{"values.yaml": "replicas: 1"}}
+	asserts.False(isOverrideDataChanged(oldCM, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "bar"}},
+		Data:       map[string]string{"values.yaml": "replicas: 1"},
+	}))
+	asserts.True(isOverrideDataChanged(oldCM, &corev1.ConfigMap{Data: map[string]string{"values.yaml": "replicas: 2"}}))
+
+	oldSecret := &corev1.Secret{Data: map[string][]byte{"values.yaml": []byte("replicas: 1")}}
+	asserts.False(isOverrideDataChanged(oldSecret, &corev1.Secret{Data: map[string][]byte{"values.yaml": []byte("replicas: 1")}}))
+	asserts.True(isOverrideDataChanged(oldSecret, &corev1.Secret{Data: map[string][]byte{"values.yaml": []byte("replicas: 2")}}))
+}
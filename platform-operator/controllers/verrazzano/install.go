@@ -4,14 +4,21 @@
 package verrazzano
 
 import (
+	"sync"
+	"time"
+
+	vzctrl "github.com/verrazzano/verrazzano/pkg/controller"
+	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
 	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
 	"github.com/verrazzano/verrazzano/pkg/semver"
+	vzstring "github.com/verrazzano/verrazzano/pkg/string"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/mysql"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -22,140 +29,295 @@ import (
 // 3. Loop through all components before returning, except for the case
 //    where update status fails, in which case we exit the function and requeue
 //    immediately.
+// 4. Components are reconciled in dependency order, components that don't depend on each other
+//    are reconciled at the same time
 func (r *Reconciler) reconcileComponents(vzctx vzcontext.VerrazzanoContext) (ctrl.Result, error) {
 	spiCtx, err := spi.NewContext(vzctx.Log, r.Client, vzctx.ActualCR, r.DryRun)
 	if err != nil {
-		spiCtx.Log().Errorf("Failed to create component context: %v", err)
+		vzctx.Log.Errorf("Failed to create component context: %v", err)
 		return newRequeueWithDelay(), err
 	}
-
-	cr := spiCtx.ActualCR()
 	spiCtx.Log().Progress("Reconciling components for Verrazzano installation")
 
+	// Allow the network traffic the enabled components need before they are installed, and remove the NetworkPolicies
+	// of the components that have been disabled
+	if err := r.reconcileNetworkPolicies(spiCtx); err != nil {
+		spiCtx.Log().Errorf("Failed reconciling the NetworkPolicies of the components: %v", err)
+		return newRequeueWithDelay(), err
+	}
+
+	components, err := registry.GetComponentsInInstallOrder()
+	if err != nil {
+		spiCtx.Log().Errorf("Failed to get the component install order: %v", err)
+		return newRequeueWithDelay(), err
+	}
+
+	shared := &sharedVerrazzano{cr: spiCtx.ActualCR()}
+	var lock sync.Mutex
 	var requeue bool
+	var errResult ctrl.Result
 
-	// Loop through all of the Verrazzano components and upgrade each one sequentially for now; will parallelize later
-	for _, comp := range registry.GetComponents() {
-		compName := comp.Name()
-		compContext := spiCtx.Init(compName).Operation(vzconst.InstallOperation)
-		compLog := compContext.Log()
+	err = forEachComponent(components, getComponentConcurrency(), func(comp spi.Component) error {
+		result, err := r.reconcileSingleComponent(vzctx, spiCtx, shared, comp)
+		if vzctrl.ShouldRequeue(result) {
+			metrics.CountRequeue(comp.Name())
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if err != nil {
+			errResult = result
+			return err
+		}
+		requeue = requeue || result.Requeue
+		return nil
+	})
+	if err != nil {
+		return errResult, err
+	}
+	if requeue {
+		return newRequeueWithDelay(), nil
+	}
+	return ctrl.Result{}, nil
+}
 
-		compLog.Oncef("Component %s is being reconciled", compName)
+// reconcileSingleComponent reconciles a single component.  The component works on its own copy of the Verrazzano
+// resource so that it can be reconciled at the same time as other components, status updates are made to the
+// shared resource.
+func (r *Reconciler) reconcileSingleComponent(vzctx vzcontext.VerrazzanoContext, spiCtx spi.ComponentContext, shared *sharedVerrazzano, comp spi.Component) (ctrl.Result, error) {
+	compName := comp.Name()
+	cr := shared.copy()
+	compSpiCtx, err := spi.NewContext(vzctx.Log, r.Client, cr, r.DryRun)
+	if err != nil {
+		return newRequeueWithDelay(), err
+	}
+	compContext := compSpiCtx.Init(compName).Operation(vzconst.InstallOperation)
+	compLog := compContext.Log()
 
-		if !comp.IsOperatorInstallSupported() {
-			compLog.Debugf("Component based install not supported for %s", compName)
-			continue
+	// updateStatus updates the component status in the shared Verrazzano resource
+	updateStatus := func(message string, conditionType vzapi.ConditionType) error {
+		return shared.update(cr, func(_ *vzapi.Verrazzano) error {
+			return r.updateComponentStatus(spiCtx.Init(compName).Operation(vzconst.InstallOperation), message, conditionType)
+		})
+	}
+
+	// failInstall sets the component and the shared Verrazzano resource to Failed when the install has timed out
+	failInstall := func(timeout time.Duration) (ctrl.Result, error) {
+		msg := getTimedOutMessage(cr, compName, "Install", timeout)
+		compLog.Errorf("Component %s failed: %s", compName, msg)
+		clearInstallStartTime(cr, compName)
+		err := shared.update(cr, func(_ *vzapi.Verrazzano) error {
+			return r.failComponent(spiCtx.Init(compName).Operation(vzconst.InstallOperation), msg, vzapi.CondInstallFailed)
+		})
+		return ctrl.Result{}, err
+	}
+
+	compLog.Oncef("Component %s is being reconciled", compName)
+
+	if !comp.IsOperatorInstallSupported() {
+		compLog.Debugf("Component based install not supported for %s", compName)
+		return ctrl.Result{}, nil
+	}
+	componentStatus, ok := cr.Status.Components[comp.Name()]
+	if !ok {
+		compLog.Debugf("Did not find status details in map for component %s", comp.Name())
+		return ctrl.Result{}, nil
+	}
+	if checkConfigUpdated(compContext, componentStatus, compName) && comp.IsEnabled(compContext.EffectiveCR()) {
+		oldState := componentStatus.State
+		oldGen := componentStatus.ReconcilingGeneration
+		err := shared.update(cr, func(sharedCR *vzapi.Verrazzano) error {
+			sharedCR.Status.Components[compName].ReconcilingGeneration = 0
+			return r.updateComponentStatus(spiCtx.Init(compName).Operation(vzconst.InstallOperation), "PreInstall started", vzapi.CondPreInstall)
+		})
+		if err != nil {
+			return ctrl.Result{Requeue: true}, err
 		}
-		componentStatus, ok := cr.Status.Components[comp.Name()]
-		if !ok {
-			compLog.Debugf("Did not find status details in map for component %s", comp.Name())
-			continue
+		clearOverridesChanged(cr, compName)
+		componentStatus = cr.Status.Components[compName]
+		compLog.Oncef("CR.generation: %v reset component %s state: %v generation: %v to state: %v generation: %v ",
+			cr.Generation, compName, oldState, oldGen, componentStatus.State, componentStatus.ReconcilingGeneration)
+		err = shared.update(cr, func(sharedCR *vzapi.Verrazzano) error {
+			if sharedCR.Status.State != vzapi.VzStateReady {
+				return nil
+			}
+			err := r.setInstallingState(compLog, sharedCR)
+			compLog.Oncef("Reset Verrazzano state to %v for generation %v", sharedCR.Status.State, sharedCR.Generation)
+			return err
+		})
+		if err != nil {
+			compLog.Errorf("Failed to reset state: %v", err)
+			return newRequeueWithDelay(), err
 		}
-		if checkConfigUpdated(spiCtx, componentStatus, compName) && comp.IsEnabled(compContext.EffectiveCR()) {
-			oldState := componentStatus.State
-			oldGen := componentStatus.ReconcilingGeneration
-			componentStatus.ReconcilingGeneration = 0
-			if err := r.updateComponentStatus(compContext, "PreInstall started", vzapi.CondPreInstall); err != nil {
+	}
+	switch componentStatus.State {
+	case vzapi.CompStateReady, vzapi.CompStateDegraded:
+		// Don't reconcile (updates) during install
+		if !isInstalled(cr.Status) {
+			return ctrl.Result{}, nil
+		}
+		if !checkConfigUpdated(compContext, componentStatus, compName) {
+			return ctrl.Result{}, nil
+		}
+		if !comp.IsEnabled(compContext.EffectiveCR()) {
+			// User has disabled the installed component in the Verrazzano CR, uninstall it
+			compLog.Oncef("Component %s has been disabled and will be uninstalled", compName)
+			if err := updateStatus("Uninstall started", vzapi.CondUninstallStarted); err != nil {
 				return ctrl.Result{Requeue: true}, err
 			}
-			compLog.Oncef("CR.generation: %v reset component %s state: %v generation: %v to state: %v generation: %v ",
-				spiCtx.ActualCR().Generation, compName, oldState, oldGen, componentStatus.State, componentStatus.ReconcilingGeneration)
-			if spiCtx.ActualCR().Status.State == vzapi.VzStateReady {
-				err = r.setInstallingState(vzctx.Log, spiCtx.ActualCR())
-				compLog.Oncef("Reset Verrazzano state to %v for generation %v", spiCtx.ActualCR().Status.State, spiCtx.ActualCR().Generation)
-				if err != nil {
-					spiCtx.Log().Errorf("Failed to reset state: %v", err)
-					return newRequeueWithDelay(), err
-				}
-			}
+			clearOverridesChanged(cr, compName)
+			return newRequeueWithDelay(), nil
+		}
+		if componentStatus.State == vzapi.CompStateDegraded {
+			// The health check moves the component back to Ready once it has recovered, see checkComponentHealth
+			return ctrl.Result{}, nil
 		}
-		switch componentStatus.State {
-		case vzapi.CompStateReady:
-			// Don't reconcile (updates) during install
-			if !isInstalled(cr.Status) {
-				continue
-			}
-			if !checkConfigUpdated(spiCtx, componentStatus, compName) {
-				continue
-			}
 
-			// For delete, we should look at the VZ resource delete timestamp and shift into Quiescing/Uninstalling state
-			compLog.Oncef("Component %s is ready", compName)
-			if err := comp.Reconcile(compContext); err != nil {
-				return newRequeueWithDelay(), err
-			}
-			// After restore '.status.instance' is empty and not updated. Below change will populate the correct values when comp state is Ready
-			if err := r.updateComponentStatus(compContext, "Component is Ready", vzapi.CondInstallComplete); err != nil {
+		// For delete, we should look at the VZ resource delete timestamp and shift into Quiescing/Uninstalling state
+		compLog.Oncef("Component %s is ready", compName)
+		if err := comp.Reconcile(compContext); err != nil {
+			r.recordOperationError(cr, compName, metrics.ReconcileOperation, err)
+			return newRequeueWithDelay(), err
+		}
+		// After restore '.status.instance' is empty and not updated. Below change will populate the correct values when comp state is Ready
+		if err := updateStatus("Component is Ready", vzapi.CondInstallComplete); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{}, nil
+	case vzapi.CompStateDisabled:
+		if !comp.IsEnabled(compContext.EffectiveCR()) {
+			compLog.Oncef("Component %s is disabled, skipping install", compName)
+			// User has disabled component in Verrazzano CR, don't install
+			return ctrl.Result{}, nil
+		}
+		if !isVersionOk(compLog, comp.GetMinVerrazzanoVersion(), cr.Status.Version) {
+			// User needs to do upgrade before this component can be installed
+			compLog.Progressf("Component %s cannot be installed until Verrazzano is upgraded to at least version %s",
+				comp.Name(), comp.GetMinVerrazzanoVersion())
+			return ctrl.Result{}, nil
+		}
+		if err := updateStatus("PreInstall started", vzapi.CondPreInstall); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+		return newRequeueWithDelay(), nil
+
+	case vzapi.CompStatePreInstalling:
+		if !registry.ComponentDependenciesMet(comp, compContext) {
+			compLog.Progressf("Component %s waiting for dependencies %v to be ready", comp.Name(), comp.GetDependencies())
+			return newRequeueWithDelay(), nil
+		}
+		if timedOut, timeout := isInstallTimedOut(compContext, comp); timedOut {
+			return failInstall(timeout)
+		}
+		compLog.Progressf("Component %s pre-install is running ", compName)
+		if err := metrics.TimeOperation(compName, metrics.PreInstallOperation, func() error { return comp.PreInstall(compContext) }); err != nil {
+			r.recordOperationError(cr, compName, metrics.PreInstallOperation, err)
+			if err := shared.update(cr, func(sharedCR *vzapi.Verrazzano) error {
+				return r.updatePreInstallProgress(compContext, comp, sharedCR, err)
+			}); err != nil {
 				return ctrl.Result{Requeue: true}, err
 			}
-			continue
-		case vzapi.CompStateDisabled:
-			if !comp.IsEnabled(compContext.EffectiveCR()) {
-				compLog.Oncef("Component %s is disabled, skipping install", compName)
-				// User has disabled component in Verrazzano CR, don't install
-				continue
-			}
-			if !isVersionOk(compLog, comp.GetMinVerrazzanoVersion(), cr.Status.Version) {
-				// User needs to do upgrade before this component can be installed
-				compLog.Progressf("Component %s cannot be installed until Verrazzano is upgraded to at least version %s",
-					comp.Name(), comp.GetMinVerrazzanoVersion())
-				continue
+			return newRequeueWithDelay(), nil
+		}
+		// If component is not installed,install it
+		compLog.Oncef("Component %s install started ", compName)
+		if err := metrics.TimeOperation(compName, metrics.InstallOperation, func() error { return comp.Install(compContext) }); err != nil {
+			r.recordOperationError(cr, compName, metrics.InstallOperation, err)
+			return newRequeueWithDelay(), nil
+		}
+		if err := updateStatus("Install started", vzapi.CondInstallStarted); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+		// Install started requeue to check status
+		return newRequeueWithDelay(), nil
+	case vzapi.CompStateInstalling:
+		// For delete, we should look at the VZ resource delete timestamp and shift into Quiescing/Uninstalling state
+		// If component is enabled -- need to replicate scripts' config merging logic here
+		// If component is in deployed state, continue
+		if comp.IsReady(compContext) {
+			compLog.Progressf("Component %s post-install is running ", compName)
+			if err := metrics.TimeOperation(compName, metrics.PostInstallOperation, func() error { return comp.PostInstall(compContext) }); err != nil {
+				r.recordOperationError(cr, compName, metrics.PostInstallOperation, err)
+				return newRequeueWithDelay(), nil
 			}
-			if err := r.updateComponentStatus(compContext, "PreInstall started", vzapi.CondPreInstall); err != nil {
+			compLog.Oncef("Component %s successfully installed", comp.Name())
+			if err := updateStatus("Install complete", vzapi.CondInstallComplete); err != nil {
 				return ctrl.Result{Requeue: true}, err
 			}
-			requeue = true
-
-		case vzapi.CompStatePreInstalling:
-			if !registry.ComponentDependenciesMet(comp, compContext) {
-				compLog.Progressf("Component %s waiting for dependencies %v to be ready", comp.Name(), comp.GetDependencies())
-				requeue = true
-				continue
-			}
-			compLog.Progressf("Component %s pre-install is running ", compName)
-			if err := comp.PreInstall(compContext); err != nil {
-				requeue = true
-				continue
-			}
-			// If component is not installed,install it
-			compLog.Oncef("Component %s install started ", compName)
-			if err := comp.Install(compContext); err != nil {
-				requeue = true
-				continue
+			clearInstallStartTime(cr, compName)
+			// Don't requeue because of this component, it is done install
+			return ctrl.Result{}, nil
+		}
+		if timedOut, timeout := isInstallTimedOut(compContext, comp); timedOut {
+			return failInstall(timeout)
+		}
+		// Install of this component is not done, requeue to check status
+		compLog.Progressf("Component %s waiting to finish installing", compName)
+		return newRequeueWithDelay(), nil
+	case vzapi.CompStateFailed:
+		// The install or upgrade of the component has failed and the Verrazzano resource has been retried, see
+		// ProcFailedState, install the component again
+		if !comp.IsEnabled(compContext.EffectiveCR()) {
+			return ctrl.Result{}, nil
+		}
+		compLog.Oncef("Component %s has failed and will be installed again", compName)
+		if err := updateStatus("PreInstall started", vzapi.CondPreInstall); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+		return newRequeueWithDelay(), nil
+	case vzapi.CompStateUninstalling:
+		// Don't remove the component until the components that depend on it have been disabled and removed
+		if dependents := getDependentsNotUninstalled(comp, compContext); len(dependents) > 0 {
+			compLog.Progressf("Component %s waiting for dependent components %v to be uninstalled", compName, dependents)
+			return newRequeueWithDelay(), nil
+		}
+		uninstalled := compContext.IsDryRun()
+		if !uninstalled {
+			if uninstalled, err = comp.IsUninstalled(compContext); err != nil {
+				return newRequeueWithDelay(), nil
 			}
-			if err := r.updateComponentStatus(compContext, "Install started", vzapi.CondInstallStarted); err != nil {
-				return ctrl.Result{Requeue: true}, err
+		}
+		if !uninstalled {
+			compLog.Progressf("Component %s uninstall is running", compName)
+			if err := comp.PreUninstall(compContext); err != nil {
+				r.recordOperationError(cr, compName, metrics.PreUninstallOperation, err)
+				return newRequeueWithDelay(), nil
 			}
-			// Install started requeue to check status
-			requeue = true
-		case vzapi.CompStateInstalling:
-			// For delete, we should look at the VZ resource delete timestamp and shift into Quiescing/Uninstalling state
-			// If component is enabled -- need to replicate scripts' config merging logic here
-			// If component is in deployed state, continue
-			if comp.IsReady(compContext) {
-				compLog.Progressf("Component %s post-install is running ", compName)
-				if err := comp.PostInstall(compContext); err != nil {
-					requeue = true
-					continue
-				}
-				compLog.Oncef("Component %s successfully installed", comp.Name())
-				if err := r.updateComponentStatus(compContext, "Install complete", vzapi.CondInstallComplete); err != nil {
-					return ctrl.Result{Requeue: true}, err
-				}
-				// Don't requeue because of this component, it is done install
-				continue
+			if err := comp.Uninstall(compContext); err != nil {
+				r.recordOperationError(cr, compName, metrics.UninstallOperation, err)
+				return newRequeueWithDelay(), nil
 			}
-			// Install of this component is not done, requeue to check status
-			compLog.Progressf("Component %s waiting to finish installing", compName)
-			requeue = true
+			// Uninstall of this component is not done, requeue to check status
+			return newRequeueWithDelay(), nil
+		}
+		compLog.Progressf("Component %s post-uninstall is running", compName)
+		if err := comp.PostUninstall(compContext); err != nil {
+			r.recordOperationError(cr, compName, metrics.PostUninstallOperation, err)
+			return newRequeueWithDelay(), nil
+		}
+		compLog.Oncef("Component %s successfully uninstalled", compName)
+		if err := updateStatus("Uninstall complete", vzapi.CondUninstallComplete); err != nil {
+			return ctrl.Result{Requeue: true}, err
 		}
-	}
-	if requeue {
-		return newRequeueWithDelay(), nil
 	}
 	return ctrl.Result{}, nil
 }
 
+// getDependentsNotUninstalled returns the names of the components that depend on the given component and are either
+// still enabled or have not finished uninstalling
+func getDependentsNotUninstalled(comp spi.Component, ctx spi.ComponentContext) []string {
+	_, dependents := registry.ComponentDependentsDisabled(comp, ctx.EffectiveCR())
+	for _, dependent := range registry.GetComponents() {
+		if vzstring.SliceContainsString(dependents, dependent.Name()) ||
+			!vzstring.SliceContainsString(dependent.GetDependencies(), comp.Name()) {
+			continue
+		}
+		if status, ok := ctx.ActualCR().Status.Components[dependent.Name()]; ok && status.State != vzapi.CompStateDisabled {
+			dependents = append(dependents, dependent.Name())
+		}
+	}
+	return dependents
+}
+
 // checkConfigUpdated checks if the component confg in the VZ CR has been updated and the component needs to
 // reset the state back to pre-install to re-enter install flow
 func checkConfigUpdated(ctx spi.ComponentContext, componentStatus *vzapi.ComponentStatusDetails, name string) bool {
@@ -175,8 +337,9 @@ func checkConfigUpdated(ctx spi.ComponentContext, componentStatus *vzapi.Compone
 	}
 	// The component has been reconciled/installed with LastReconciledGeneration of the CR
 	// if CR.Generation > LastReconciledGeneration then re-enter install flow
-	return (componentStatus.State == vzapi.CompStateReady) &&
-		(ctx.ActualCR().Generation > componentStatus.LastReconciledGeneration)
+	// if the monitored Helm value overrides of the component have changed then re-enter install flow
+	return (componentStatus.State == vzapi.CompStateReady || componentStatus.State == vzapi.CompStateDegraded) &&
+		(ctx.ActualCR().Generation > componentStatus.LastReconciledGeneration || isOverridesChanged(ctx.ActualCR(), name))
 }
 
 // Check if the component can be installed in this Verrazzano installation based on version
@@ -199,3 +362,17 @@ func isVersionOk(log vzlog.VerrazzanoLogger, compVersion string, vzVersion strin
 	// return false if VZ version is too low to install component, else true
 	return !vzSemver.IsLessThan(compSemver)
 }
+
+// updatePreInstallProgress saves what the pre-install of a component is waiting for in the message of its PreInstall
+// condition, when the pre-install returned a retryable error with its progress
+func (r *Reconciler) updatePreInstallProgress(compContext spi.ComponentContext, comp spi.Component, cr *vzapi.Verrazzano, preInstallErr error) error {
+	retryErr, ok := preInstallErr.(ctrlerrors.RetryableError)
+	if !ok || len(retryErr.Progress) == 0 {
+		return nil
+	}
+	compStatus, ok := cr.Status.Components[comp.Name()]
+	if !ok || !updateConditionMessage(compStatus.Conditions, vzapi.CondPreInstall, retryErr.Progress) {
+		return nil
+	}
+	return r.updateVerrazzanoStatus(compContext.Log(), cr)
+}
@@ -5,21 +5,28 @@ package verrazzano
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
 	"github.com/verrazzano/verrazzano/pkg/helm"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	helmcomp "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"github.com/verrazzano/verrazzano/platform-operator/mocks"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 const testBomFile = "../../verrazzano-bom.json"
@@ -171,6 +178,7 @@ func testUpdate(t *testing.T,
 			verrazzano.Status.Components = compStatusMap
 			return nil
 		})
+	expectPreflightChecks(mock)
 	// The mocks are added to accomodate the expected calls to List instance when component is Ready
 	mock.EXPECT().
 		List(gomock.Any(), gomock.Not(gomock.Nil())).
@@ -178,6 +186,8 @@ func testUpdate(t *testing.T,
 			ingressList.Items = []networkingv1.Ingress{}
 			return nil
 		}).AnyTimes()
+	expectNoCARotation(mock)
+	expectCertificateInventory(mock)
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 	mockStatus.EXPECT().
 		Update(gomock.Any(), gomock.Any()).
@@ -212,3 +222,298 @@ func testUpdate(t *testing.T,
 	mocker.Finish()
 	return asserts, vz, result, fakeCompUpdated, err
 }
+
+// TestReconcileDisabledComponent tests the reconcileComponents func for a component that has been disabled after install
+// GIVEN a Ready component which has been disabled by a newer generation of the Verrazzano CR
+// WHEN reconcileComponents is called repeatedly
+// THEN ensure the component moves to Uninstalling, is uninstalled through the spi, and ends up Disabled
+func TestReconcileDisabledComponent(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	comp := mocks.NewMockComponent(mocker)
+	comp.EXPECT().Name().Return("fake").AnyTimes()
+	comp.EXPECT().IsOperatorInstallSupported().Return(true).AnyTimes()
+	comp.EXPECT().IsEnabled(gomock.Any()).Return(false).AnyTimes()
+	comp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
+	gomock.InOrder(
+		comp.EXPECT().IsUninstalled(gomock.Any()).Return(false, nil),
+		comp.EXPECT().PreUninstall(gomock.Any()).Return(nil),
+		comp.EXPECT().Uninstall(gomock.Any()).Return(nil),
+		comp.EXPECT().IsUninstalled(gomock.Any()).Return(true, nil),
+		comp.EXPECT().PostUninstall(gomock.Any()).Return(nil),
+	)
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{comp}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test", Generation: 2},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateReady,
+			Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}},
+			Components: vzapi.ComponentStatusMap{
+				"fake": {Name: "fake", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+			},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+
+	reconcile := func() (ctrl.Result, *vzapi.Verrazzano) {
+		actual := &vzapi.Verrazzano{}
+		asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, actual))
+		vzctx, err := vzcontext.NewVerrazzanoContext(vzlog.DefaultLogger(), c, actual, false)
+		asserts.NoError(err)
+		result, err := reconciler.reconcileComponents(vzctx)
+		asserts.NoError(err)
+		return result, actual
+	}
+
+	// Ready -> Uninstalling
+	result, actual := reconcile()
+	asserts.True(result.Requeue)
+	asserts.Equal(vzapi.CompStateUninstalling, actual.Status.Components["fake"].State)
+
+	// Uninstall started, not yet removed
+	result, actual = reconcile()
+	asserts.True(result.Requeue)
+	asserts.Equal(vzapi.CompStateUninstalling, actual.Status.Components["fake"].State)
+
+	// Uninstalled -> Disabled
+	_, actual = reconcile()
+	asserts.Equal(vzapi.CompStateDisabled, actual.Status.Components["fake"].State)
+	mocker.Finish()
+}
+
+// TestReconcileDisabledDegradedComponent tests the reconcileComponents func for a degraded component that has been disabled
+// GIVEN a Degraded component which has been disabled by a newer generation of the Verrazzano CR
+// WHEN reconcileComponents is called
+// THEN ensure the component moves to Uninstalling like a Ready component
+func TestReconcileDisabledDegradedComponent(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	comp := mocks.NewMockComponent(mocker)
+	comp.EXPECT().Name().Return("fake").AnyTimes()
+	comp.EXPECT().IsOperatorInstallSupported().Return(true).AnyTimes()
+	comp.EXPECT().IsEnabled(gomock.Any()).Return(false).AnyTimes()
+	comp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{comp}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test", Generation: 2},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateReady,
+			Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}},
+			Components: vzapi.ComponentStatusMap{
+				"fake": {Name: "fake", State: vzapi.CompStateDegraded, LastReconciledGeneration: 1},
+			},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+
+	actual := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, actual))
+	vzctx, err := vzcontext.NewVerrazzanoContext(vzlog.DefaultLogger(), c, actual, false)
+	asserts.NoError(err)
+	result, err := reconciler.reconcileComponents(vzctx)
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.Equal(vzapi.CompStateUninstalling, actual.Status.Components["fake"].State)
+	mocker.Finish()
+}
+
+// TestReconcileDegradedComponent tests the reconcileComponents func for a degraded component
+// GIVEN an enabled Degraded component whose configuration has not changed
+// WHEN reconcileComponents is called
+// THEN ensure the component is left Degraded for the health check, and is not reconciled
+func TestReconcileDegradedComponent(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	comp := mocks.NewMockComponent(mocker)
+	comp.EXPECT().Name().Return("fake").AnyTimes()
+	comp.EXPECT().IsOperatorInstallSupported().Return(true).AnyTimes()
+	comp.EXPECT().IsEnabled(gomock.Any()).Return(true).AnyTimes()
+	comp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
+	comp.EXPECT().Reconcile(gomock.Any()).Times(0)
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{comp}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test", Generation: 1},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateReady,
+			Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}},
+			Components: vzapi.ComponentStatusMap{
+				"fake": {Name: "fake", State: vzapi.CompStateDegraded, LastReconciledGeneration: 1},
+			},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+
+	actual := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, actual))
+	vzctx, err := vzcontext.NewVerrazzanoContext(vzlog.DefaultLogger(), c, actual, false)
+	asserts.NoError(err)
+	_, err = reconciler.reconcileComponents(vzctx)
+	asserts.NoError(err)
+	asserts.Equal(vzapi.CompStateDegraded, actual.Status.Components["fake"].State)
+	mocker.Finish()
+}
+
+// TestReconcileDisabledComponentWithDependents tests the reconcileComponents func for a disabled component that others depend on
+// GIVEN an Uninstalling component which is a dependency of another enabled component
+// WHEN reconcileComponents is called
+// THEN ensure the component is not uninstalled and the reconcile is requeued
+func TestReconcileDisabledComponentWithDependents(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	comp1 := mocks.NewMockComponent(mocker)
+	comp1.EXPECT().Name().Return("fake1").AnyTimes()
+	comp1.EXPECT().IsOperatorInstallSupported().Return(true).AnyTimes()
+	comp1.EXPECT().IsEnabled(gomock.Any()).Return(false).AnyTimes()
+	comp1.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
+	comp2 := mocks.NewMockComponent(mocker)
+	comp2.EXPECT().Name().Return("fake2").AnyTimes()
+	comp2.EXPECT().IsOperatorInstallSupported().Return(true).AnyTimes()
+	comp2.EXPECT().IsEnabled(gomock.Any()).Return(true).AnyTimes()
+	comp2.EXPECT().GetDependencies().Return([]string{"fake1"}).AnyTimes()
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{comp1, comp2}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test", Generation: 1},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateReady,
+			Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}},
+			Components: vzapi.ComponentStatusMap{
+				"fake1": {Name: "fake1", State: vzapi.CompStateUninstalling, LastReconciledGeneration: 1},
+				"fake2": {Name: "fake2", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+			},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+
+	vzctx, err := vzcontext.NewVerrazzanoContext(vzlog.DefaultLogger(), c, vz, false)
+	asserts.NoError(err)
+	result, err := reconciler.reconcileComponents(vzctx)
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.Equal(vzapi.CompStateUninstalling, vz.Status.Components["fake1"].State)
+	mocker.Finish()
+}
+
+// TestReconcileComponentsConcurrently tests the reconcileComponents func with components that are reconciled at the same time
+// GIVEN several independent components which are enabled but not yet installed
+// WHEN reconcileComponents is called with a component concurrency greater than one
+// THEN ensure the status of every component is updated without conflicts
+func TestReconcileComponentsConcurrently(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{ComponentConcurrency: 4})
+
+	names := []string{"fake1", "fake2", "fake3", "fake4"}
+	var comps []spi.Component
+	statusMap := vzapi.ComponentStatusMap{}
+	for _, name := range names {
+		comp := mocks.NewMockComponent(mocker)
+		comp.EXPECT().Name().Return(name).AnyTimes()
+		comp.EXPECT().IsOperatorInstallSupported().Return(true).AnyTimes()
+		comp.EXPECT().IsEnabled(gomock.Any()).Return(true).AnyTimes()
+		comp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
+		comp.EXPECT().GetMinVerrazzanoVersion().Return("1.0.0").AnyTimes()
+		comps = append(comps, comp)
+		statusMap[name] = &vzapi.ComponentStatusDetails{Name: name, State: vzapi.CompStateDisabled}
+	}
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return comps
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test", Generation: 1},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateInstalling,
+			Components: statusMap,
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+
+	vzctx, err := vzcontext.NewVerrazzanoContext(vzlog.DefaultLogger(), c, vz, false)
+	asserts.NoError(err)
+	result, err := reconciler.reconcileComponents(vzctx)
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+
+	actual := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, actual))
+	for _, name := range names {
+		asserts.Equal(vzapi.CompStatePreInstalling, actual.Status.Components[name].State)
+	}
+	mocker.Finish()
+}
+
+// TestUpdatePreInstallProgress tests the updatePreInstallProgress func
+// GIVEN a component whose pre-install is waiting
+// WHEN updatePreInstallProgress is called with the error returned by the pre-install
+// THEN the progress of a retryable error is saved in the PreInstall condition, and other errors are ignored
+func TestUpdatePreInstallProgress(t *testing.T) {
+	asserts := assert.New(t)
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test"},
+		Status: vzapi.VerrazzanoStatus{
+			Components: vzapi.ComponentStatusMap{
+				"fake": &vzapi.ComponentStatusDetails{Name: "fake", State: vzapi.CompStatePreInstalling,
+					Conditions: []vzapi.Condition{{Type: vzapi.CondPreInstall, Message: "PreInstall started"}}},
+			},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	comp := helmcomp.HelmComponent{ReleaseName: "fake"}
+	compContext := spi.NewFakeContext(c, vz, false)
+
+	asserts.NoError(reconciler.updatePreInstallProgress(compContext, comp, vz, errors.New("failed")))
+	asserts.Equal("PreInstall started", vz.Status.Components["fake"].Conditions[0].Message)
+
+	asserts.NoError(reconciler.updatePreInstallProgress(compContext, comp, vz, ctrlerrors.RetryableError{Source: "fake"}))
+	asserts.Equal("PreInstall started", vz.Status.Components["fake"].Conditions[0].Message)
+
+	asserts.NoError(reconciler.updatePreInstallProgress(compContext, comp, vz, ctrlerrors.RetryableError{Source: "fake", Progress: "Waiting"}))
+	asserts.Equal("Waiting", vz.Status.Components["fake"].Conditions[0].Message)
+	stored := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, stored))
+	asserts.Equal("Waiting", stored.Status.Components["fake"].Conditions[0].Message)
+}
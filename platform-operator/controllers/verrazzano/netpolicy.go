@@ -0,0 +1,189 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"sort"
+
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// reconcileNetworkPolicies creates or updates the NetworkPolicies of the enabled components that provide the network
+// flows of their pods, and deletes the policies of the components that are no longer enabled.  The traffic of the pods
+// of a namespace that isn't allowed by a policy is denied once all the enabled components of the namespace provide
+// their policies, a namespace shared with a component that doesn't is left open so that the component isn't cut off.
+func (r *Reconciler) reconcileNetworkPolicies(spiCtx spi.ComponentContext) error {
+	if unitTesting {
+		return nil
+	}
+	desired, err := getDesiredNetworkPolicies(spiCtx)
+	if err != nil {
+		return err
+	}
+
+	// The policies are created once the namespace of the component exists, which may not be until the component is
+	// installed
+	namespaces := map[string]bool{}
+	for _, nsn := range sortedPolicyNames(desired) {
+		exists, ok := namespaces[nsn.Namespace]
+		if !ok {
+			err := r.Get(context.TODO(), types.NamespacedName{Name: nsn.Namespace}, &corev1.Namespace{})
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			exists = err == nil
+			namespaces[nsn.Namespace] = exists
+		}
+		if !exists {
+			continue
+		}
+		if err := r.createOrUpdateNetworkPolicy(spiCtx.Log(), desired[nsn]); err != nil {
+			return err
+		}
+	}
+
+	existing := netv1.NetworkPolicyList{}
+	if err := r.List(context.TODO(), &existing, client.MatchingLabels{netpolicy.ManagedLabel: "true"}); err != nil {
+		return err
+	}
+	for i := range existing.Items {
+		policy := &existing.Items[i]
+		if _, ok := desired[types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}]; ok {
+			continue
+		}
+		spiCtx.Log().Infof("Deleting NetworkPolicy %s/%s that is no longer needed", policy.Namespace, policy.Name)
+		if err := r.Delete(context.TODO(), policy); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDesiredNetworkPolicies returns the NetworkPolicies of the enabled components, the policies that allow the pods of
+// their namespaces to reach the cluster services, and the policies that deny the other traffic of the namespaces whose
+// enabled components all provide their policies
+func getDesiredNetworkPolicies(spiCtx spi.ComponentContext) (map[types.NamespacedName]netv1.NetworkPolicy, error) {
+	desired := map[types.NamespacedName]netv1.NetworkPolicy{}
+	namespaces := map[string]bool{}
+	var open []string
+	for _, comp := range registry.GetComponents() {
+		if !comp.IsOperatorInstallSupported() || !comp.IsEnabled(spiCtx.EffectiveCR()) {
+			continue
+		}
+		provider, ok := comp.(spi.ComponentNetworkPolicyProvider)
+		if !ok {
+			if namespaced, ok := comp.(spi.ComponentNamespaceProvider); ok {
+				open = append(open, namespaced.GetNamespace(spiCtx))
+			}
+			continue
+		}
+		policies, err := provider.GetNetworkPolicies(spiCtx.Init(comp.Name()))
+		if err != nil {
+			spiCtx.Log().Errorf("Failed getting the NetworkPolicies of component %s: %v", comp.Name(), err)
+			return nil, err
+		}
+		for _, policy := range policies {
+			policy.Labels = map[string]string{
+				netpolicy.ManagedLabel:   "true",
+				netpolicy.ComponentLabel: comp.Name(),
+			}
+			desired[types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}] = policy
+			namespaces[policy.Namespace] = true
+		}
+	}
+	for _, namespace := range open {
+		if _, ok := namespaces[namespace]; ok {
+			namespaces[namespace] = false
+		}
+	}
+	if len(namespaces) == 0 {
+		return desired, nil
+	}
+
+	apiServerIP, apiServerPort, err := netpolicy.GetAPIServerIPAndPort(spiCtx.Client())
+	if err != nil {
+		spiCtx.Log().Errorf("Failed getting the address of the Kubernetes API server: %v", err)
+		return nil, err
+	}
+	for namespace, closed := range namespaces {
+		// The pods selected by the policies of the components can reach DNS and the API server whether or not the
+		// namespace is closed
+		policies := []netv1.NetworkPolicy{netpolicy.NewClusterServicesNetworkPolicy(namespace, apiServerIP, apiServerPort)}
+		if closed {
+			policies = append(policies, netpolicy.NewDefaultDenyNetworkPolicy(namespace))
+		}
+		for _, policy := range policies {
+			policy.Labels = map[string]string{netpolicy.ManagedLabel: "true"}
+			desired[types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}] = policy
+		}
+	}
+	return desired, nil
+}
+
+// createOrUpdateNetworkPolicy creates or updates a NetworkPolicy to match the desired policy
+func (r *Reconciler) createOrUpdateNetworkPolicy(log vzlog.VerrazzanoLogger, desired netv1.NetworkPolicy) error {
+	policy := &netv1.NetworkPolicy{}
+	policy.Namespace = desired.Namespace
+	policy.Name = desired.Name
+	opResult, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, policy, func() error {
+		if policy.Labels == nil {
+			policy.Labels = map[string]string{}
+		}
+		for key, value := range desired.Labels {
+			policy.Labels[key] = value
+		}
+		desired.Spec.DeepCopyInto(&policy.Spec)
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Failed creating or updating NetworkPolicy %s/%s: %v", desired.Namespace, desired.Name, err)
+		return err
+	}
+	if opResult != controllerutil.OperationResultNone {
+		log.Debugf("NetworkPolicy %s/%s was %s", desired.Namespace, desired.Name, opResult)
+	}
+	return nil
+}
+
+// deleteNetworkPolicies deletes the NetworkPolicies of the components when Verrazzano is uninstalled
+func (r *Reconciler) deleteNetworkPolicies(log vzlog.VerrazzanoLogger) error {
+	if unitTesting {
+		return nil
+	}
+	existing := netv1.NetworkPolicyList{}
+	if err := r.List(context.TODO(), &existing, client.MatchingLabels{netpolicy.ManagedLabel: "true"}); err != nil {
+		return err
+	}
+	for i := range existing.Items {
+		policy := &existing.Items[i]
+		log.Debugf("Deleting NetworkPolicy %s/%s", policy.Namespace, policy.Name)
+		if err := r.Delete(context.TODO(), policy); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedPolicyNames returns the names of the policies sorted by namespace and name, so that they are reconciled in the
+// same order each time
+func sortedPolicyNames(policies map[types.NamespacedName]netv1.NetworkPolicy) []types.NamespacedName {
+	names := make([]types.NamespacedName, 0, len(policies))
+	for nsn := range policies {
+		names = append(names, nsn)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].String() < names[j].String()
+	})
+	return names
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// netPolicyComponent is a fake component that provides the NetworkPolicies of its pods
+type netPolicyComponent struct {
+	fakeComponent
+}
+
+func (n netPolicyComponent) GetNetworkPolicies(_ spi.ComponentContext) ([]netv1.NetworkPolicy, error) {
+	return []netv1.NetworkPolicy{
+		netpolicy.NewComponentNetworkPolicy(n.ChartNamespace, "allow-"+n.ReleaseName, map[string]string{"app": n.ReleaseName},
+			[]netv1.NetworkPolicyIngressRule{netpolicy.IngressFrom(nil, 443)}, nil),
+	}, nil
+}
+
+// TestReconcileNetworkPolicies tests the reconcileNetworkPolicies func for the following use case
+// GIVEN components that provide NetworkPolicies, in a namespace shared with a component that doesn't
+// WHEN the policies are reconciled, and then the component that doesn't is disabled, and then a component that does
+// THEN the namespace is closed by the default deny policy only once all its enabled components provide policies, and
+// the policies of the disabled components are deleted
+func TestReconcileNetworkPolicies(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+	defer func(previous bool) { unitTesting = previous }(unitTesting)
+	unitTesting = false
+
+	newComponent := func(name string, namespace string) fakeComponent {
+		return fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: name, JSONName: name, ChartNamespace: namespace,
+			IgnoreNamespaceOverride: true, SupportsOperatorInstall: true}}
+	}
+	a := netPolicyComponent{newComponent("a", "ns1")}
+	b := netPolicyComponent{newComponent("b", "ns2")}
+	c := newComponent("c", "ns1")
+	// The namespace of a component that isn't installed yet is skipped
+	d := netPolicyComponent{newComponent("d", "ns3")}
+	components := []spi.Component{a, b, c, d}
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return components
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-netpolicy"}}
+	cli := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "kubernetes"},
+			Subsets: []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{IP: "1.2.3.4"}},
+				Ports:     []corev1.EndpointPort{{Port: 6443}},
+			}},
+		}).Build()
+	reconciler := newVerrazzanoReconciler(cli)
+
+	reconcile := func() []string {
+		spiCtx, err := spi.NewContext(vzlog.DefaultLogger(), cli, vz, false)
+		asserts.NoError(err)
+		asserts.NoError(reconciler.reconcileNetworkPolicies(spiCtx))
+		return getManagedPolicyNames(asserts, cli)
+	}
+
+	// ns1 is left open for component c
+	asserts.ElementsMatch([]string{
+		"ns1/allow-a",
+		"ns1/" + netpolicy.ClusterServicesPolicyName,
+		"ns2/allow-b",
+		"ns2/" + netpolicy.ClusterServicesPolicyName,
+		"ns2/" + netpolicy.DefaultDenyPolicyName,
+	}, reconcile())
+	policy := &netv1.NetworkPolicy{}
+	asserts.NoError(cli.Get(context.TODO(), client.ObjectKey{Namespace: "ns1", Name: "allow-a"}, policy))
+	asserts.Equal("a", policy.Labels[netpolicy.ComponentLabel])
+
+	// ns1 is closed once component c is disabled
+	c.enabled = "false"
+	components = []spi.Component{a, b, c, d}
+	asserts.ElementsMatch([]string{
+		"ns1/allow-a",
+		"ns1/" + netpolicy.ClusterServicesPolicyName,
+		"ns1/" + netpolicy.DefaultDenyPolicyName,
+		"ns2/allow-b",
+		"ns2/" + netpolicy.ClusterServicesPolicyName,
+		"ns2/" + netpolicy.DefaultDenyPolicyName,
+	}, reconcile())
+
+	// The policies of ns2 are deleted once component b is disabled
+	b.enabled = "false"
+	components = []spi.Component{a, b, c, d}
+	asserts.ElementsMatch([]string{
+		"ns1/allow-a",
+		"ns1/" + netpolicy.ClusterServicesPolicyName,
+		"ns1/" + netpolicy.DefaultDenyPolicyName,
+	}, reconcile())
+
+	// All the policies are deleted when Verrazzano is uninstalled
+	asserts.NoError(reconciler.deleteNetworkPolicies(vzlog.DefaultLogger()))
+	asserts.Empty(getManagedPolicyNames(asserts, cli))
+}
+
+// getManagedPolicyNames returns the namespaces and names of the NetworkPolicies managed by the reconciler
+func getManagedPolicyNames(asserts *assert.Assertions, cli client.Client) []string {
+	policies := netv1.NetworkPolicyList{}
+	asserts.NoError(cli.List(context.TODO(), &policies, client.MatchingLabels{netpolicy.ManagedLabel: "true"}))
+	var names []string
+	for _, policy := range policies.Items {
+		names = append(names, policy.Namespace+"/"+policy.Name)
+	}
+	return names
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"sync"
+
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+)
+
+// forEachComponent calls fn for each of the components, running up to limit calls at the same time.  A component is
+// not started until fn has returned for all of its dependencies that appear before it in the list, so the list
+// should be sorted in dependency order.  Independent components are processed in parallel.
+// Once fn returns an error no more components are started, and the first error is returned after the calls that
+// are running have finished.
+func forEachComponent(components []spi.Component, limit int, fn func(comp spi.Component) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+	done := make(map[string]chan struct{}, len(components))
+	slots := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var firstErr error
+
+	for _, comp := range components {
+		// Only wait for the dependencies that have already been scheduled, this also guards against cycles
+		var waitFor []chan struct{}
+		for _, dependencyName := range comp.GetDependencies() {
+			if ch, ok := done[dependencyName]; ok {
+				waitFor = append(waitFor, ch)
+			}
+		}
+		compDone := make(chan struct{})
+		done[comp.Name()] = compDone
+
+		wg.Add(1)
+		go func(comp spi.Component) {
+			defer wg.Done()
+			defer close(compDone)
+			for _, ch := range waitFor {
+				<-ch
+			}
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			lock.Lock()
+			failed := firstErr != nil
+			lock.Unlock()
+			if failed {
+				return
+			}
+			if err := fn(comp); err != nil {
+				lock.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				lock.Unlock()
+			}
+		}(comp)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// getComponentConcurrency returns the maximum number of components that are processed at the same time
+func getComponentConcurrency() int {
+	return config.Get().ComponentConcurrency
+}
+
+// sharedVerrazzano serializes the updates to a Verrazzano resource made by components that are being reconciled
+// at the same time.  Each component works on its own copy of the resource, which is refreshed after every update.
+type sharedVerrazzano struct {
+	lock sync.Mutex
+	cr   *vzapi.Verrazzano
+}
+
+// copy returns a copy of the shared Verrazzano resource
+func (s *sharedVerrazzano) copy() *vzapi.Verrazzano {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.cr.DeepCopy()
+}
+
+// update calls fn to update the shared Verrazzano resource, then refreshes the component copy from the result
+func (s *sharedVerrazzano) update(compCR *vzapi.Verrazzano, fn func(cr *vzapi.Verrazzano) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := fn(s.cr)
+	s.cr.DeepCopyInto(compCR)
+	return err
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/mocks"
+)
+
+// newDependentMockComponent returns a mock component with the given name and dependencies
+func newDependentMockComponent(mocker *gomock.Controller, name string, dependencies ...string) spi.Component {
+	comp := mocks.NewMockComponent(mocker)
+	comp.EXPECT().Name().Return(name).AnyTimes()
+	comp.EXPECT().GetDependencies().Return(dependencies).AnyTimes()
+	return comp
+}
+
+// TestForEachComponentDependencyOrder tests the forEachComponent func
+// GIVEN a list of components where some components depend on others
+// WHEN forEachComponent is called
+// THEN ensure each component is started after its dependencies finish, and independent components run at the same time
+func TestForEachComponentDependencyOrder(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	defer mocker.Finish()
+
+	components := []spi.Component{
+		newDependentMockComponent(mocker, "a"),
+		newDependentMockComponent(mocker, "b", "a"),
+		newDependentMockComponent(mocker, "c", "a"),
+		newDependentMockComponent(mocker, "d", "b", "c"),
+	}
+
+	var lock sync.Mutex
+	finished := map[string]bool{}
+	// b and c don't depend on each other, each one waits for the other to start
+	started := map[string]chan struct{}{"b": make(chan struct{}), "c": make(chan struct{})}
+	peers := map[string]string{"b": "c", "c": "b"}
+
+	err := forEachComponent(components, 2, func(comp spi.Component) error {
+		lock.Lock()
+		for _, dependencyName := range comp.GetDependencies() {
+			asserts.True(finished[dependencyName], "%s started before dependency %s finished", comp.Name(), dependencyName)
+		}
+		lock.Unlock()
+
+		if peer, ok := peers[comp.Name()]; ok {
+			close(started[comp.Name()])
+			select {
+			case <-started[peer]:
+			case <-time.After(5 * time.Second):
+				asserts.Fail("components were not run at the same time", "%s timed out waiting for %s", comp.Name(), peer)
+			}
+		}
+
+		lock.Lock()
+		finished[comp.Name()] = true
+		lock.Unlock()
+		return nil
+	})
+	asserts.NoError(err)
+	asserts.Len(finished, 4)
+}
+
+// TestForEachComponentLimit tests the forEachComponent func
+// GIVEN a list of independent components
+// WHEN forEachComponent is called with a limit
+// THEN ensure no more than limit components run at the same time
+func TestForEachComponentLimit(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	defer mocker.Finish()
+
+	var components []spi.Component
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		components = append(components, newDependentMockComponent(mocker, name))
+	}
+
+	for _, limit := range []int{0, 1, 3} {
+		var lock sync.Mutex
+		running := 0
+		maxRunning := 0
+		count := 0
+		err := forEachComponent(components, limit, func(comp spi.Component) error {
+			lock.Lock()
+			running++
+			count++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			lock.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			lock.Lock()
+			running--
+			lock.Unlock()
+			return nil
+		})
+		asserts.NoError(err)
+		asserts.Equal(len(components), count)
+		if limit < 1 {
+			asserts.Equal(1, maxRunning)
+		} else {
+			asserts.LessOrEqual(maxRunning, limit)
+		}
+	}
+}
+
+// TestForEachComponentError tests the forEachComponent func
+// GIVEN a list of components where a component returns an error
+// WHEN forEachComponent is called
+// THEN ensure the error is returned and the components that depend on the failed component are not started
+func TestForEachComponentError(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	defer mocker.Finish()
+
+	components := []spi.Component{
+		newDependentMockComponent(mocker, "a"),
+		newDependentMockComponent(mocker, "b", "a"),
+		newDependentMockComponent(mocker, "c", "b"),
+	}
+
+	var lock sync.Mutex
+	var called []string
+	err := forEachComponent(components, 4, func(comp spi.Component) error {
+		lock.Lock()
+		called = append(called, comp.Name())
+		lock.Unlock()
+		if comp.Name() == "a" {
+			return errors.New("unexpected error")
+		}
+		return nil
+	})
+	asserts.Error(err)
+	asserts.Equal([]string{"a"}, called)
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/plan"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// isPlanRequested returns true if the Verrazzano resource has the plan annotation
+func isPlanRequested(cr *installv1alpha1.Verrazzano) bool {
+	return cr.Annotations[vzconst.PlanAnnotation] == "true"
+}
+
+// procPlan computes the plan of the changes that would be made to the components for the current spec and saves it
+// in the Verrazzano status, without installing, upgrading or reconciling any component
+func (r *Reconciler) procPlan(log vzlog.VerrazzanoLogger, cr *installv1alpha1.Verrazzano) (ctrl.Result, error) {
+	vzPlan, err := plan.Generate(log, r.Client, cr, nil)
+	if err != nil {
+		log.Errorf("Failed to compute the plan for Verrazzano %s/%s: %v", cr.Namespace, cr.Name, err)
+		return newRequeueWithDelay(), err
+	}
+	if plan.Equal(cr.Status.Plan, vzPlan) {
+		return ctrl.Result{}, nil
+	}
+	log.Oncef("Computed the plan for generation %v, components will not be changed until the %s annotation is removed",
+		cr.Generation, vzconst.PlanAnnotation)
+	cr.Status.Plan = vzPlan
+	if err := r.updateVerrazzanoStatus(log, cr); err != nil {
+		return newRequeueWithDelay(), err
+	}
+	return ctrl.Result{}, nil
+}
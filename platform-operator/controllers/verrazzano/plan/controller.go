@@ -0,0 +1,106 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	vzctrl "github.com/verrazzano/verrazzano/pkg/controller"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VerrazzanoPlanReconciler computes the plan for a VerrazzanoPlan resource.  The plan is computed once for each
+// generation of the VerrazzanoPlan, the Verrazzano resource is never changed.
+type VerrazzanoPlanReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// SetupWithManager creates a new controller and adds it to the manager
+func (r *VerrazzanoPlanReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vzapi.VerrazzanoPlan{}).
+		Complete(r)
+}
+
+// Reconcile computes the plan and saves it in the VerrazzanoPlan status
+func (r *VerrazzanoPlanReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	vzPlan := &vzapi.VerrazzanoPlan{}
+	if err := r.Get(ctx, req.NamespacedName, vzPlan); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		zap.S().Errorf("Failed to fetch VerrazzanoPlan %s/%s: %v", req.Namespace, req.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	if !vzPlan.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	if vzPlan.Status.Plan != nil && vzPlan.Status.ObservedGeneration == vzPlan.Generation {
+		return ctrl.Result{}, nil
+	}
+
+	// Get the resource logger needed to log message using 'progress' and 'once' methods
+	log, err := vzlog.EnsureResourceLogger(&vzlog.ResourceConfig{
+		Name:           vzPlan.Name,
+		Namespace:      vzPlan.Namespace,
+		ID:             string(vzPlan.UID),
+		Generation:     vzPlan.Generation,
+		ControllerName: "verrazzanoplan",
+	})
+	if err != nil {
+		zap.S().Errorf("Failed to create resource logger for VerrazzanoPlan controller: %v", err)
+		return newRequeueWithDelay(), nil
+	}
+
+	vz := &vzapi.Verrazzano{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: vzPlan.Namespace, Name: vzPlan.Spec.VerrazzanoName}, vz); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Errorf("Failed to fetch Verrazzano %s/%s: %v", vzPlan.Namespace, vzPlan.Spec.VerrazzanoName, err)
+			return newRequeueWithDelay(), nil
+		}
+		log.Progressf("Waiting for Verrazzano %s/%s to exist", vzPlan.Namespace, vzPlan.Spec.VerrazzanoName)
+		return r.updateStatus(log, vzPlan, nil, fmt.Sprintf("Verrazzano %s not found", vzPlan.Spec.VerrazzanoName))
+	}
+
+	plan, err := Generate(log, r.Client, vz, vzPlan.Spec.Verrazzano)
+	if err != nil {
+		log.Errorf("Failed to compute the plan for Verrazzano %s/%s: %v", vz.Namespace, vz.Name, err)
+		return r.updateStatus(log, vzPlan, nil, fmt.Sprintf("Failed to compute the plan: %v", err))
+	}
+	log.Oncef("Computed the plan for Verrazzano %s/%s", vz.Namespace, vz.Name)
+	return r.updateStatus(log, vzPlan, plan, "")
+}
+
+// updateStatus saves the plan in the VerrazzanoPlan status.  If the plan could not be computed the reconcile is
+// requeued.
+func (r *VerrazzanoPlanReconciler) updateStatus(log vzlog.VerrazzanoLogger, vzPlan *vzapi.VerrazzanoPlan, plan *vzapi.Plan, message string) (ctrl.Result, error) {
+	vzPlan.Status.Plan = plan
+	vzPlan.Status.Message = message
+	if plan != nil {
+		vzPlan.Status.ObservedGeneration = vzPlan.Generation
+	}
+	if err := r.Status().Update(context.TODO(), vzPlan); err != nil {
+		log.Errorf("Failed to update the status of VerrazzanoPlan %s/%s: %v", vzPlan.Namespace, vzPlan.Name, err)
+		return newRequeueWithDelay(), nil
+	}
+	if plan == nil {
+		return newRequeueWithDelay(), nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// Create a new Result that will cause a reconcile requeue after a short delay
+func newRequeueWithDelay() ctrl.Result {
+	return vzctrl.NewRequeueWithDelay(3, 5, time.Second)
+}
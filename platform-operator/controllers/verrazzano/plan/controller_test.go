@@ -0,0 +1,96 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestReconcile tests the VerrazzanoPlanReconciler Reconcile func
+// GIVEN a VerrazzanoPlan for an existing Verrazzano resource with a proposed spec
+// WHEN Reconcile is called
+// THEN ensure the plan is saved in the VerrazzanoPlan status and the Verrazzano resource is not changed
+func TestReconcile(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	defer mocker.Finish()
+	config.TestProfilesDir = "../../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{newPlanMockComponent(mocker, "fake", true)}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test", Generation: 1},
+		Spec:       vzapi.VerrazzanoSpec{Profile: vzapi.Dev},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateReady,
+			Components: vzapi.ComponentStatusMap{"fake": {Name: "fake", State: vzapi.CompStateReady, LastReconciledGeneration: 1}},
+		},
+	}
+	proposed := vz.Spec.DeepCopy()
+	proposed.EnvironmentName = "proposed"
+	vzPlan := &vzapi.VerrazzanoPlan{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "plan", Generation: 1},
+		Spec:       vzapi.VerrazzanoPlanSpec{VerrazzanoName: "test", Verrazzano: proposed},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz, vzPlan).Build()
+	reconciler := VerrazzanoPlanReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "verrazzano", Name: "plan"}})
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	actual := &vzapi.VerrazzanoPlan{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "plan"}, actual))
+	asserts.Equal(int64(1), actual.Status.ObservedGeneration)
+	asserts.NotNil(actual.Status.Plan)
+	asserts.Len(actual.Status.Plan.Components, 1)
+	asserts.Equal(vzapi.PlanActionReconcile, actual.Status.Plan.Components[0].Action)
+
+	actualVz := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, actualVz))
+	asserts.Empty(actualVz.Spec.EnvironmentName)
+	asserts.Nil(actualVz.Status.Plan)
+}
+
+// TestReconcileVerrazzanoNotFound tests the VerrazzanoPlanReconciler Reconcile func
+// GIVEN a VerrazzanoPlan for a Verrazzano resource that does not exist
+// WHEN Reconcile is called
+// THEN ensure the reason is saved in the VerrazzanoPlan status and the reconcile is requeued
+func TestReconcileVerrazzanoNotFound(t *testing.T) {
+	asserts := assert.New(t)
+	vzPlan := &vzapi.VerrazzanoPlan{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "plan", Generation: 1},
+		Spec:       vzapi.VerrazzanoPlanSpec{VerrazzanoName: "test"},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vzPlan).Build()
+	reconciler := VerrazzanoPlanReconciler{Client: c, Scheme: k8scheme.Scheme}
+
+	result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "verrazzano", Name: "plan"}})
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+
+	actual := &vzapi.VerrazzanoPlan{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "plan"}, actual))
+	asserts.Nil(actual.Status.Plan)
+	asserts.Equal("Verrazzano test not found", actual.Status.Message)
+}
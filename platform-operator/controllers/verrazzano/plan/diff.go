@@ -0,0 +1,68 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package plan
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/verrazzano/verrazzano/pkg/helm"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+)
+
+// redactedValue replaces the values of sensitive keys in the diff
+const redactedValue = "<redacted>"
+
+// sensitiveKeys are the key fragments of Helm values that are never reported
+var sensitiveKeys = []string{"password", "pwd", "secret", "token", "privatekey"}
+
+// diffValues returns the changes between the current and planned Helm values, sorted by path
+func diffValues(current map[string]interface{}, planned map[string]interface{}) []vzapi.HelmValueChange {
+	currentValues := helm.FlattenValues(current)
+	plannedValues := helm.FlattenValues(planned)
+
+	paths := map[string]bool{}
+	for path := range currentValues {
+		paths[path] = true
+	}
+	for path := range plannedValues {
+		paths[path] = true
+	}
+
+	var changes []vzapi.HelmValueChange
+	for path := range paths {
+		currentValue, plannedValue := currentValues[path], plannedValues[path]
+		if currentValue == plannedValue {
+			continue
+		}
+		if isSensitive(path) {
+			currentValue = redact(currentValue)
+			plannedValue = redact(plannedValue)
+		}
+		changes = append(changes, vzapi.HelmValueChange{Path: path, Current: currentValue, Planned: plannedValue})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// isSensitive returns true if the value at the path must not be reported
+func isSensitive(path string) bool {
+	lowerPath := strings.ToLower(path)
+	for _, key := range sensitiveKeys {
+		if strings.Contains(lowerPath, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact replaces a value that is set with the redacted value
+func redact(value string) string {
+	if len(value) == 0 {
+		return value
+	}
+	return redactedValue
+}
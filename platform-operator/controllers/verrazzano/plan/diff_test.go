@@ -0,0 +1,46 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+)
+
+// TestDiffValues tests the diffValues func
+// GIVEN current and planned Helm values
+// WHEN diffValues is called
+// THEN ensure the added, changed and removed values are returned sorted by path, and sensitive values are redacted
+func TestDiffValues(t *testing.T) {
+	asserts := assert.New(t)
+	current := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "ghcr.io/verrazzano/foo",
+			"tag":        "1.0",
+		},
+		"replicas":      1,
+		"adminPassword": "old",
+		"removed":       true,
+	}
+	planned := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "ghcr.io/verrazzano/foo",
+			"tag":        "2.0",
+		},
+		"replicas":      1,
+		"adminPassword": "new",
+		"args":          []interface{}{"--debug"},
+	}
+	asserts.Equal([]vzapi.HelmValueChange{
+		{Path: "adminPassword", Current: redactedValue, Planned: redactedValue},
+		{Path: "args", Planned: `["--debug"]`},
+		{Path: "image.tag", Current: `"1.0"`, Planned: `"2.0"`},
+		{Path: "removed", Current: "true"},
+	}, diffValues(current, planned))
+
+	asserts.Empty(diffValues(current, current))
+	asserts.Empty(diffValues(nil, map[string]interface{}{}))
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package plan
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/verrazzano/verrazzano/pkg/helm"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// helmValuesComponent is implemented by the components that are installed with Helm
+type helmValuesComponent interface {
+	// GetOverrideValues returns the Helm values that the component would be installed with
+	GetOverrideValues(ctx spi.ComponentContext) (map[string]interface{}, error)
+	// GetDeployedValues returns the Helm values of the live release, and false if the release is not installed
+	GetDeployedValues(ctx spi.ComponentContext) (map[string]interface{}, bool, error)
+}
+
+// Generate computes the plan of the changes that would be made to the components if the current Verrazzano
+// resource was updated with the proposed spec.  If the proposed spec is nil, the plan is computed for the current
+// spec.  The effective CR is computed from the profiles the same way as it is for an install, but nothing is changed
+// in the cluster.
+func Generate(log vzlog.VerrazzanoLogger, client clipkg.Client, current *vzapi.Verrazzano, proposed *vzapi.VerrazzanoSpec) (*vzapi.Plan, error) {
+	cr := current.DeepCopy()
+	specChanged := false
+	if proposed != nil {
+		specChanged = !reflect.DeepEqual(current.Spec, *proposed)
+		cr.Spec = *proposed.DeepCopy()
+	}
+
+	ctx, err := spi.NewContext(log, client, cr, true)
+	if err != nil {
+		return nil, err
+	}
+	components, err := registry.GetComponentsInInstallOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &vzapi.Plan{
+		Generation:        current.Generation,
+		LastGeneratedTime: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, comp := range components {
+		compCtx := ctx.Init(comp.Name()).Operation(vzconst.PlanOperation)
+		plan.Components = append(plan.Components, planComponent(compCtx, comp, specChanged))
+	}
+	return plan, nil
+}
+
+// Equal returns true if the plans have the same changes, the time the plans were generated is ignored
+func Equal(a *vzapi.Plan, b *vzapi.Plan) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Generation == b.Generation && reflect.DeepEqual(a.Components, b.Components)
+}
+
+// planComponent returns the action that would be taken for a single component
+func planComponent(ctx spi.ComponentContext, comp spi.Component, specChanged bool) vzapi.ComponentPlan {
+	compPlan := vzapi.ComponentPlan{Name: comp.Name(), Action: vzapi.PlanActionSkip}
+	if !comp.IsOperatorInstallSupported() {
+		compPlan.Message = "Component is not installed by the operator"
+		return compPlan
+	}
+
+	cr := ctx.ActualCR()
+	compStatus := cr.Status.Components[comp.Name()]
+	installed := isComponentInstalled(compStatus)
+	if !comp.IsEnabled(ctx.EffectiveCR()) {
+		compPlan.Message = "Component is disabled"
+		if installed {
+			compPlan.Action = vzapi.PlanActionUninstall
+			compPlan.Message = "Component is disabled and will be uninstalled"
+		}
+		return compPlan
+	}
+
+	switch {
+	case !installed:
+		compPlan.Action = vzapi.PlanActionInstall
+		compPlan.Message = "Component is enabled and is not installed"
+	case len(cr.Spec.Version) > 0 && cr.Spec.Version != cr.Status.Version:
+		compPlan.Action = vzapi.PlanActionUpgrade
+		compPlan.Message = fmt.Sprintf("Component will be upgraded from version %s to %s", cr.Status.Version, cr.Spec.Version)
+	case specChanged || cr.Generation > compStatus.LastReconciledGeneration:
+		compPlan.Action = vzapi.PlanActionReconcile
+		compPlan.Message = "Verrazzano configuration has changed"
+	}
+
+	helmComp, ok := comp.(helmValuesComponent)
+	if !ok {
+		if compPlan.Action == vzapi.PlanActionSkip {
+			compPlan.Message = "No changes"
+		}
+		return compPlan
+	}
+	diff, err := diffHelmValues(ctx, helmComp, compPlan.Action)
+	if err != nil {
+		ctx.Log().Errorf("Failed computing the Helm values for component %s: %v", comp.Name(), err)
+		compPlan.Message = fmt.Sprintf("%s, unable to compute the Helm values: %v", compPlan.Message, err)
+		return compPlan
+	}
+	compPlan.ValuesDiff = diff
+	if compPlan.Action == vzapi.PlanActionSkip {
+		compPlan.Message = "No changes"
+		if len(diff) > 0 {
+			// The rendered values can change without a change to the Verrazzano resource, for example when a
+			// referenced override ConfigMap or Secret changes
+			compPlan.Action = vzapi.PlanActionReconcile
+			compPlan.Message = "Helm values have changed"
+		}
+	}
+	return compPlan
+}
+
+// diffHelmValues returns the difference between the values of the live Helm release and the values that would be
+// applied by the action
+func diffHelmValues(ctx spi.ComponentContext, comp helmValuesComponent, action vzapi.PlanAction) ([]vzapi.HelmValueChange, error) {
+	planned, err := comp.GetOverrideValues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var current map[string]interface{}
+	if action != vzapi.PlanActionInstall {
+		current, _, err = comp.GetDeployedValues(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if action == vzapi.PlanActionUpgrade {
+		// An upgrade retains the values of the live release
+		planned = helm.MergeMaps(current, planned)
+	}
+	return diffValues(current, planned), nil
+}
+
+// isComponentInstalled returns true if the component status shows that the component has been installed
+func isComponentInstalled(compStatus *vzapi.ComponentStatusDetails) bool {
+	if compStatus == nil {
+		return false
+	}
+	switch compStatus.State {
+	case "", vzapi.CompStateDisabled, vzapi.CompStatePreInstalling:
+		return false
+	}
+	return true
+}
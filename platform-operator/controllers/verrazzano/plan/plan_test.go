@@ -0,0 +1,178 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/mocks"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeHelmComponent is a component with Helm values
+type fakeHelmComponent struct {
+	spi.Component
+	overrideValues map[string]interface{}
+	deployedValues map[string]interface{}
+}
+
+func (f fakeHelmComponent) GetOverrideValues(_ spi.ComponentContext) (map[string]interface{}, error) {
+	return f.overrideValues, nil
+}
+
+func (f fakeHelmComponent) GetDeployedValues(_ spi.ComponentContext) (map[string]interface{}, bool, error) {
+	return f.deployedValues, f.deployedValues != nil, nil
+}
+
+// newPlanMockComponent returns a mock component with the given name that is enabled or disabled
+func newPlanMockComponent(mocker *gomock.Controller, name string, enabled bool) *mocks.MockComponent {
+	comp := mocks.NewMockComponent(mocker)
+	comp.EXPECT().Name().Return(name).AnyTimes()
+	comp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
+	comp.EXPECT().IsOperatorInstallSupported().Return(true).AnyTimes()
+	comp.EXPECT().IsEnabled(gomock.Any()).Return(enabled).AnyTimes()
+	return comp
+}
+
+// TestGenerate tests the Generate func
+// GIVEN a Verrazzano resource with installed, disabled and changed components
+// WHEN Generate is called
+// THEN ensure the action for each component and the Helm values diff are reported
+func TestGenerate(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	defer mocker.Finish()
+	config.TestProfilesDir = "../../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeHelmComponent{
+				Component:      newPlanMockComponent(mocker, "install", true),
+				overrideValues: map[string]interface{}{"replicas": 1},
+			},
+			fakeHelmComponent{
+				Component:      newPlanMockComponent(mocker, "reconcile", true),
+				overrideValues: map[string]interface{}{"image": map[string]interface{}{"tag": "2.0"}},
+				deployedValues: map[string]interface{}{"image": map[string]interface{}{"tag": "1.0"}},
+			},
+			fakeHelmComponent{
+				Component:      newPlanMockComponent(mocker, "unchanged", true),
+				overrideValues: map[string]interface{}{"replicas": 1},
+				deployedValues: map[string]interface{}{"replicas": 1},
+			},
+			newPlanMockComponent(mocker, "uninstall", false),
+			newPlanMockComponent(mocker, "disabled", false),
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test", Generation: 1},
+		Spec:       vzapi.VerrazzanoSpec{Profile: vzapi.Dev},
+		Status: vzapi.VerrazzanoStatus{
+			State: vzapi.VzStateReady,
+			Components: vzapi.ComponentStatusMap{
+				"install":   {Name: "install", State: vzapi.CompStatePreInstalling},
+				"reconcile": {Name: "reconcile", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+				"unchanged": {Name: "unchanged", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+				"uninstall": {Name: "uninstall", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+				"disabled":  {Name: "disabled", State: vzapi.CompStateDisabled},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+
+	plan, err := Generate(vzlog.DefaultLogger(), c, vz, nil)
+	asserts.NoError(err)
+	asserts.Equal(int64(1), plan.Generation)
+	asserts.NotEmpty(plan.LastGeneratedTime)
+	asserts.Len(plan.Components, 5)
+
+	actions := map[string]vzapi.ComponentPlan{}
+	for _, compPlan := range plan.Components {
+		actions[compPlan.Name] = compPlan
+	}
+	asserts.Equal(vzapi.PlanActionInstall, actions["install"].Action)
+	asserts.Equal([]vzapi.HelmValueChange{{Path: "replicas", Planned: "1"}}, actions["install"].ValuesDiff)
+	asserts.Equal(vzapi.PlanActionReconcile, actions["reconcile"].Action)
+	asserts.Equal([]vzapi.HelmValueChange{{Path: "image.tag", Current: `"1.0"`, Planned: `"2.0"`}}, actions["reconcile"].ValuesDiff)
+	asserts.Equal(vzapi.PlanActionSkip, actions["unchanged"].Action)
+	asserts.Empty(actions["unchanged"].ValuesDiff)
+	asserts.Equal(vzapi.PlanActionUninstall, actions["uninstall"].Action)
+	asserts.Equal(vzapi.PlanActionSkip, actions["disabled"].Action)
+}
+
+// TestGenerateProposedSpec tests the Generate func
+// GIVEN a Verrazzano resource and a proposed spec with a new version
+// WHEN Generate is called
+// THEN ensure the installed components are upgraded and the Helm values of the live release are retained
+func TestGenerateProposedSpec(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	defer mocker.Finish()
+	config.TestProfilesDir = "../../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeHelmComponent{
+				Component:      newPlanMockComponent(mocker, "upgrade", true),
+				overrideValues: map[string]interface{}{"image": map[string]interface{}{"tag": "2.0"}},
+				deployedValues: map[string]interface{}{"image": map[string]interface{}{"tag": "1.0"}, "replicas": 3},
+			},
+			newPlanMockComponent(mocker, "reconcile", true),
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test", Generation: 1},
+		Spec:       vzapi.VerrazzanoSpec{Profile: vzapi.Dev, Version: "1.3.0"},
+		Status: vzapi.VerrazzanoStatus{
+			State:   vzapi.VzStateReady,
+			Version: "1.3.0",
+			Components: vzapi.ComponentStatusMap{
+				"upgrade":   {Name: "upgrade", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+				"reconcile": {Name: "reconcile", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+			},
+		},
+	}
+	proposed := vz.Spec.DeepCopy()
+	proposed.Version = "1.4.0"
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build()
+
+	plan, err := Generate(vzlog.DefaultLogger(), c, vz, proposed)
+	asserts.NoError(err)
+	asserts.Len(plan.Components, 2)
+	asserts.Equal(vzapi.PlanActionUpgrade, plan.Components[0].Action)
+	asserts.Equal([]vzapi.HelmValueChange{{Path: "image.tag", Current: `"1.0"`, Planned: `"2.0"`}}, plan.Components[0].ValuesDiff)
+	asserts.Equal(vzapi.PlanActionUpgrade, plan.Components[1].Action)
+	asserts.Equal("1.3.0", vz.Spec.Version)
+}
+
+// TestEqual tests the Equal func
+// GIVEN two plans
+// WHEN Equal is called
+// THEN ensure the time the plans were generated is ignored
+func TestEqual(t *testing.T) {
+	asserts := assert.New(t)
+	a := &vzapi.Plan{Generation: 1, LastGeneratedTime: "2022-01-01T00:00:00Z", Components: []vzapi.ComponentPlan{{Name: "a", Action: vzapi.PlanActionSkip}}}
+	b := a.DeepCopy()
+	b.LastGeneratedTime = "2022-01-02T00:00:00Z"
+	asserts.True(Equal(a, b))
+	asserts.True(Equal(nil, nil))
+	asserts.False(Equal(a, nil))
+	b.Components[0].Action = vzapi.PlanActionInstall
+	asserts.False(Equal(a, b))
+}
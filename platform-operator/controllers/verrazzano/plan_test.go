@@ -0,0 +1,81 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/mocks"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestProcReadyStatePlan tests the ProcReadyState func in plan mode
+// GIVEN an installed Verrazzano resource with the plan annotation and an updated spec
+// WHEN ProcReadyState is called
+// THEN ensure the plan is saved in the status and the components are not reconciled
+func TestProcReadyStatePlan(t *testing.T) {
+	asserts := assert.New(t)
+	mocker := gomock.NewController(t)
+	defer mocker.Finish()
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	// Reconcile, Install and Upgrade must not be called
+	comp := mocks.NewMockComponent(mocker)
+	comp.EXPECT().Name().Return("fake").AnyTimes()
+	comp.EXPECT().IsOperatorInstallSupported().Return(true).AnyTimes()
+	comp.EXPECT().IsEnabled(gomock.Any()).Return(true).AnyTimes()
+	comp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{comp}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "verrazzano",
+			Name:        "test",
+			Generation:  2,
+			Annotations: map[string]string{vzconst.PlanAnnotation: "true"},
+		},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateReady,
+			Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}},
+			Components: vzapi.ComponentStatusMap{
+				"fake": {Name: "fake", State: vzapi.CompStateReady, LastReconciledGeneration: 1},
+			},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+
+	vzctx, err := vzcontext.NewVerrazzanoContext(vzlog.DefaultLogger(), c, vz, false)
+	asserts.NoError(err)
+	result, err := reconciler.ProcReadyState(vzctx)
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	actual := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, actual))
+	asserts.NotNil(actual.Status.Plan)
+	asserts.Equal(int64(2), actual.Status.Plan.Generation)
+	asserts.Equal([]vzapi.ComponentPlan{{Name: "fake", Action: vzapi.PlanActionReconcile, Message: "Verrazzano configuration has changed"}},
+		actual.Status.Plan.Components)
+	asserts.Equal(vzapi.CompStateReady, actual.Status.Components["fake"].State)
+	asserts.Equal(int64(1), actual.Status.Components["fake"].LastReconciledGeneration)
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/verrazzano/verrazzano/pkg/semver"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/transform"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// minKubernetesVersion is the oldest Kubernetes version supported by Verrazzano
+	minKubernetesVersion = "1.20.0"
+
+	// maxKubernetesVersion is the newest Kubernetes version Verrazzano has been tested with, newer minor versions are
+	// reported as a warning
+	maxKubernetesVersion = "1.23.0"
+
+	// defaultStorageClassAnnotation is the annotation of the default StorageClass
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+	// betaDefaultStorageClassAnnotation is the beta annotation of the default StorageClass
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// minNodeMemory is the minimum allocatable memory of the schedulable nodes of the cluster for each profile
+var minNodeMemory = map[vzapi.ProfileType]resource.Quantity{
+	vzapi.Dev:            resource.MustParse("8Gi"),
+	vzapi.ManagedCluster: resource.MustParse("8Gi"),
+	vzapi.Prod:           resource.MustParse("24Gi"),
+}
+
+// runPreflightChecks checks the target cluster before the install or upgrade is started, with the checks of the cluster
+// and the checks contributed by the enabled components.  The results are stored in the preflight status.  Returns
+// false if a check failed, the install or upgrade is blocked until the failure is fixed.
+func (r *Reconciler) runPreflightChecks(vzctx vzcontext.VerrazzanoContext, operation string) (bool, error) {
+	cr := vzctx.ActualCR
+	spiCtx, err := spi.NewContext(vzctx.Log, r.Client, cr, r.DryRun)
+	if err != nil {
+		vzctx.Log.Errorf("Failed to create component context: %v", err)
+		return false, err
+	}
+
+	checks, err := getClusterPreflightChecks(spiCtx)
+	if err != nil {
+		spiCtx.Log().Errorf("Failed checking the cluster before the %s: %v", operation, err)
+		return false, err
+	}
+	for _, comp := range registry.GetComponents() {
+		checker, ok := comp.(spi.ComponentPreflightChecker)
+		if !ok || !comp.IsOperatorInstallSupported() || !comp.IsEnabled(spiCtx.EffectiveCR()) {
+			continue
+		}
+		compChecks, err := checker.RunPreflightChecks(spiCtx.Init(comp.Name()).Operation(operation))
+		if err != nil {
+			spiCtx.Log().Errorf("Failed checking the cluster for component %s before the %s: %v", comp.Name(), operation, err)
+			return false, err
+		}
+		checks = append(checks, compChecks...)
+	}
+
+	passed := true
+	var failures []string
+	for _, check := range checks {
+		if check.Result == vzapi.PreflightCheckFailed {
+			passed = false
+			failures = append(failures, check.Message)
+		}
+	}
+	if !passed {
+		vzctx.Log.Progressf("Verrazzano %s is blocked by failed preflight checks: %s", operation, strings.Join(failures, "; "))
+	}
+	if !setPreflightStatus(cr, operation, passed, checks) {
+		return passed, nil
+	}
+	if err := r.updateVerrazzanoStatus(vzctx.Log, cr); err != nil {
+		return false, err
+	}
+	if !passed && r.EventRecorder != nil {
+		r.EventRecorder.Eventf(cr, corev1.EventTypeWarning, "PreflightFailed", "Verrazzano %s is blocked by failed preflight checks: %s",
+			operation, strings.Join(failures, "; "))
+	}
+	return passed, nil
+}
+
+// setPreflightStatus sets the results of the preflight checks in the status.  Returns true if the results changed.
+func setPreflightStatus(cr *vzapi.Verrazzano, operation string, passed bool, checks []vzapi.PreflightCheck) bool {
+	existing := cr.Status.Preflight
+	if existing != nil && existing.Operation == operation && existing.Passed == passed && reflect.DeepEqual(existing.Checks, checks) {
+		return false
+	}
+	cr.Status.Preflight = &vzapi.PreflightStatus{
+		Operation:          operation,
+		Passed:             passed,
+		Checks:             checks,
+		LastTransitionTime: formatStatusTime(time.Now()),
+	}
+	return true
+}
+
+// getClusterPreflightChecks returns the results of the checks of the cluster that are not specific to a component
+func getClusterPreflightChecks(ctx spi.ComponentContext) ([]vzapi.PreflightCheck, error) {
+	nodes := corev1.NodeList{}
+	if err := ctx.Client().List(context.TODO(), &nodes); err != nil {
+		return nil, err
+	}
+	storageCheck, err := checkDefaultStorageClass(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versionCheck, err := checkKubernetesVersion(nodes.Items)
+	if err != nil {
+		return nil, err
+	}
+	return []vzapi.PreflightCheck{versionCheck, checkNodeMemory(ctx.EffectiveCR(), nodes.Items), storageCheck}, nil
+}
+
+// checkKubernetesVersion checks that the Kubernetes version of the nodes is supported.  An unsupported version fails
+// the check, a version newer than the versions Verrazzano has been tested with is a warning.
+func checkKubernetesVersion(nodes []corev1.Node) (vzapi.PreflightCheck, error) {
+	check := vzapi.PreflightCheck{Name: "KubernetesVersion"}
+	minVersion, err := semver.NewSemVersion(minKubernetesVersion)
+	if err != nil {
+		return check, err
+	}
+	maxVersion, err := semver.NewSemVersion(maxKubernetesVersion)
+	if err != nil {
+		return check, err
+	}
+	var oldest, newest *semver.SemVersion
+	for _, node := range nodes {
+		version, err := semver.NewSemVersion(node.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			return check, fmt.Errorf("Failed parsing the Kubernetes version of node %s: %v", node.Name, err)
+		}
+		if oldest == nil || version.IsLessThan(oldest) {
+			oldest = version
+		}
+		if newest == nil || version.IsGreatherThan(newest) {
+			newest = version
+		}
+	}
+	switch {
+	case oldest == nil:
+		check.Result = vzapi.PreflightCheckWarning
+		check.Message = "There are no nodes in the cluster"
+	case compareMinorVersions(oldest, minVersion) < 0:
+		check.Result = vzapi.PreflightCheckFailed
+		check.Message = fmt.Sprintf("The Kubernetes version %s is not supported, the oldest supported version is %d.%d",
+			oldest.ToString(), minVersion.Major, minVersion.Minor)
+	case compareMinorVersions(newest, maxVersion) > 0:
+		check.Result = vzapi.PreflightCheckWarning
+		check.Message = fmt.Sprintf("The Kubernetes version %s is newer than the versions Verrazzano has been tested with, the newest tested version is %d.%d",
+			newest.ToString(), maxVersion.Major, maxVersion.Minor)
+	default:
+		check.Result = vzapi.PreflightCheckPassed
+		check.Message = fmt.Sprintf("The Kubernetes version %s is supported", oldest.ToString())
+	}
+	return check, nil
+}
+
+// compareMinorVersions compares the major and minor versions of two versions, ignoring the patch versions.  Returns a
+// negative number if v1 is older than v2, zero if they have the same minor version, and a positive number if v1 is
+// newer than v2.
+func compareMinorVersions(v1 *semver.SemVersion, v2 *semver.SemVersion) int64 {
+	if v1.Major != v2.Major {
+		return v1.Major - v2.Major
+	}
+	return v1.Minor - v2.Minor
+}
+
+// checkNodeMemory checks that the schedulable nodes have the minimum allocatable memory of the profile.  Not enough
+// memory is a warning, since the cluster may be scaled up when the pods are pending.
+func checkNodeMemory(cr *vzapi.Verrazzano, nodes []corev1.Node) vzapi.PreflightCheck {
+	check := vzapi.PreflightCheck{Name: "NodeMemory"}
+	profile := cr.Spec.Profile
+	if len(profile) == 0 {
+		profile = vzapi.Prod
+	}
+	minMemory := getMinNodeMemory(cr)
+	memory := resource.Quantity{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		memory.Add(*node.Status.Allocatable.Memory())
+	}
+	if memory.Cmp(minMemory) < 0 {
+		check.Result = vzapi.PreflightCheckWarning
+		check.Message = fmt.Sprintf("The schedulable nodes have %s of allocatable memory, the %s profile needs at least %s", memory.String(), profile, minMemory.String())
+		return check
+	}
+	check.Result = vzapi.PreflightCheckPassed
+	check.Message = fmt.Sprintf("The schedulable nodes have %s of allocatable memory", memory.String())
+	return check
+}
+
+// getMinNodeMemory returns the minimum allocatable memory of the built-in profile that the requested profiles are
+// layered on.  When several built-in profiles are in the chain, the last one is used since it is merged over the others.
+func getMinNodeMemory(cr *vzapi.Verrazzano) resource.Quantity {
+	minMemory := minNodeMemory[vzapi.Prod]
+	chain, err := transform.GetProfileChain(cr)
+	if err != nil {
+		return minMemory
+	}
+	for _, name := range chain {
+		if memory, ok := minNodeMemory[vzapi.ProfileType(name)]; ok {
+			minMemory = memory
+		}
+	}
+	return minMemory
+}
+
+// checkDefaultStorageClass checks that there is a StorageClass for the persistent volumes of the components.  The
+// StorageClass of the volume claim template of the default volume source must exist, otherwise there must be a default
+// StorageClass.
+func checkDefaultStorageClass(ctx spi.ComponentContext) (vzapi.PreflightCheck, error) {
+	check := vzapi.PreflightCheck{Name: "StorageClass"}
+	cr := ctx.EffectiveCR()
+	volumeSource := cr.Spec.DefaultVolumeSource
+	if volumeSource != nil && volumeSource.EmptyDir != nil {
+		check.Result = vzapi.PreflightCheckPassed
+		check.Message = "The default volume source is an emptyDir, no StorageClass is needed"
+		return check, nil
+	}
+	if volumeSource != nil && volumeSource.PersistentVolumeClaim != nil {
+		claimName := volumeSource.PersistentVolumeClaim.ClaimName
+		claimSpec, found := vzconfig.FindVolumeTemplate(claimName, cr.Spec.VolumeClaimSpecTemplates)
+		if found && claimSpec.StorageClassName != nil && len(*claimSpec.StorageClassName) > 0 {
+			className := *claimSpec.StorageClassName
+			err := ctx.Client().Get(context.TODO(), types.NamespacedName{Name: className}, &storagev1.StorageClass{})
+			if errors.IsNotFound(err) {
+				check.Result = vzapi.PreflightCheckFailed
+				check.Message = fmt.Sprintf("The StorageClass %s of the volume claim template %s does not exist", className, claimName)
+				return check, nil
+			}
+			if err != nil {
+				return check, err
+			}
+			check.Result = vzapi.PreflightCheckPassed
+			check.Message = fmt.Sprintf("The StorageClass %s of the volume claim template %s exists", className, claimName)
+			return check, nil
+		}
+	}
+
+	classes := storagev1.StorageClassList{}
+	if err := ctx.Client().List(context.TODO(), &classes); err != nil {
+		return check, err
+	}
+	for _, class := range classes.Items {
+		if class.Annotations[defaultStorageClassAnnotation] == "true" || class.Annotations[betaDefaultStorageClassAnnotation] == "true" {
+			check.Result = vzapi.PreflightCheckPassed
+			check.Message = fmt.Sprintf("The StorageClass %s is the default StorageClass", class.Name)
+			return check, nil
+		}
+	}
+	check.Result = vzapi.PreflightCheckFailed
+	check.Message = "There is no default StorageClass for the persistent volumes of the components, mark a StorageClass as the default or set the storageClassName of the volume claim template of the default volume source"
+	return check, nil
+}
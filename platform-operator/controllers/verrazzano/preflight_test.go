@@ -0,0 +1,265 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/transform"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/mocks"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakePreflightComponent is a component that returns the preflight checks it is given
+type fakePreflightComponent struct {
+	fakeComponent
+	checks *[]vzapi.PreflightCheck
+}
+
+func (f fakePreflightComponent) RunPreflightChecks(_ spi.ComponentContext) ([]vzapi.PreflightCheck, error) {
+	return *f.checks, nil
+}
+
+// TestRunPreflightChecks tests the runPreflightChecks method for the following use case
+// GIVEN a cluster that meets the requirements of the cluster checks, and a component that contributes a check
+// WHEN the component check fails, and then passes
+// THEN the install is blocked while the check fails, and the results are stored in the preflight status
+func TestRunPreflightChecks(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	checks := []vzapi.PreflightCheck{{Name: "Secret", Component: "a", Result: vzapi.PreflightCheckFailed, Message: "The secret does not exist"}}
+	components := []spi.Component{
+		fakePreflightComponent{
+			fakeComponent: fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "a", SupportsOperatorInstall: true}},
+			checks:        &checks,
+		},
+	}
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return components
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-preflight"},
+		Spec:       vzapi.VerrazzanoSpec{Profile: vzapi.Dev},
+		Status:     vzapi.VerrazzanoStatus{State: vzapi.VzStateReady},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz, newTestNode("node1", "v1.22.5", "16Gi")).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := newVerrazzanoReconciler(c)
+	reconciler.EventRecorder = recorder
+	vzctx := vzcontext.VerrazzanoContext{Log: vzlog.DefaultLogger(), Client: c, ActualCR: vz}
+
+	passed, err := reconciler.runPreflightChecks(vzctx, vzconst.InstallOperation)
+	asserts.NoError(err)
+	asserts.False(passed)
+	actual := &vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test-preflight"}, actual))
+	asserts.NotNil(actual.Status.Preflight)
+	asserts.Equal(vzconst.InstallOperation, actual.Status.Preflight.Operation)
+	asserts.False(actual.Status.Preflight.Passed)
+	asserts.Len(actual.Status.Preflight.Checks, 4)
+	for _, check := range actual.Status.Preflight.Checks[:3] {
+		asserts.Equal(vzapi.PreflightCheckPassed, check.Result, check.Name)
+	}
+	asserts.Equal(checks[0], actual.Status.Preflight.Checks[3])
+	asserts.Len(recorder.Events, 1)
+	asserts.Contains(<-recorder.Events, "The secret does not exist")
+
+	// The event is not recorded again while the results are unchanged
+	passed, err = reconciler.runPreflightChecks(vzctx, vzconst.InstallOperation)
+	asserts.NoError(err)
+	asserts.False(passed)
+	asserts.Len(recorder.Events, 0)
+
+	checks[0].Result = vzapi.PreflightCheckPassed
+	checks[0].Message = "The secret exists"
+	passed, err = reconciler.runPreflightChecks(vzctx, vzconst.InstallOperation)
+	asserts.NoError(err)
+	asserts.True(passed)
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test-preflight"}, actual))
+	asserts.True(actual.Status.Preflight.Passed)
+	asserts.Equal(checks[0], actual.Status.Preflight.Checks[3])
+}
+
+// TestCheckKubernetesVersion tests the checkKubernetesVersion func
+// GIVEN nodes with supported, unsupported and untested Kubernetes versions
+// WHEN checkKubernetesVersion is called
+// THEN the check passes for the supported versions, fails for an unsupported version and warns for an untested version
+func TestCheckKubernetesVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		expected vzapi.PreflightCheckResult
+	}{
+		{name: "supported", versions: []string{"v1.21.5-gke.1302", "v1.22.5"}, expected: vzapi.PreflightCheckPassed},
+		{name: "oldest", versions: []string{"v1.20.0"}, expected: vzapi.PreflightCheckPassed},
+		{name: "newest", versions: []string{"v1.23.9+k3s1"}, expected: vzapi.PreflightCheckPassed},
+		{name: "unsupported", versions: []string{"v1.22.5", "v1.19.16"}, expected: vzapi.PreflightCheckFailed},
+		{name: "untested", versions: []string{"v1.22.5", "v1.24.1"}, expected: vzapi.PreflightCheckWarning},
+		{name: "no nodes", expected: vzapi.PreflightCheckWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []corev1.Node
+			for _, version := range tt.versions {
+				nodes = append(nodes, *newTestNode(version, version, "16Gi"))
+			}
+			check, err := checkKubernetesVersion(nodes)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, check.Result, check.Message)
+		})
+	}
+
+	_, err := checkKubernetesVersion([]corev1.Node{*newTestNode("node1", "unknown", "16Gi")})
+	assert.Error(t, err)
+}
+
+// newCustomProfileConfigMap returns the labelled ConfigMap of a custom profile
+func newCustomProfileConfigMap(name string, profile string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: vzconst.VerrazzanoInstallNamespace,
+			Name:      name,
+			Labels:    map[string]string{vzconst.VerrazzanoProfileLabel: "true"},
+		},
+		Data: map[string]string{vzconst.VerrazzanoProfileKey: profile},
+	}
+}
+
+// TestCheckNodeMemory tests the checkNodeMemory func
+// GIVEN schedulable and unschedulable nodes
+// WHEN checkNodeMemory is called for the dev and prod profiles, and for custom profiles layered on them
+// THEN the check warns when the schedulable nodes have less memory than the minimum of the built-in profile
+func TestCheckNodeMemory(t *testing.T) {
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+	defer transform.SetProfileReader(nil)
+	transform.SetProfileReader(fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		newCustomProfileConfigMap("staging", "spec:\n  profile: dev\n"),
+		newCustomProfileConfigMap("large", "spec:\n  profile: prod\n"),
+	).Build())
+
+	unschedulable := newTestNode("node3", "v1.22.5", "64Gi")
+	unschedulable.Spec.Unschedulable = true
+	nodes := []corev1.Node{*newTestNode("node1", "v1.22.5", "8Gi"), *newTestNode("node2", "v1.22.5", "8Gi"), *unschedulable}
+
+	check := checkNodeMemory(&vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{Profile: vzapi.Dev}}, nodes)
+	assert.Equal(t, vzapi.PreflightCheckPassed, check.Result, check.Message)
+	check = checkNodeMemory(&vzapi.Verrazzano{}, nodes)
+	assert.Equal(t, vzapi.PreflightCheckWarning, check.Result, check.Message)
+	assert.Contains(t, check.Message, "16Gi")
+
+	// Custom profiles use the minimum of the built-in profile they are layered on
+	check = checkNodeMemory(&vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{Profile: "dev,staging"}}, nodes)
+	assert.Equal(t, vzapi.PreflightCheckPassed, check.Result, check.Message)
+	check = checkNodeMemory(&vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{Profile: "staging"}}, nodes)
+	assert.Equal(t, vzapi.PreflightCheckPassed, check.Result, check.Message)
+	check = checkNodeMemory(&vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{Profile: "large"}}, nodes)
+	assert.Equal(t, vzapi.PreflightCheckWarning, check.Result, check.Message)
+}
+
+// TestCheckDefaultStorageClass tests the checkDefaultStorageClass func
+// GIVEN default volume sources that use emptyDir, the default StorageClass and a named StorageClass
+// WHEN checkDefaultStorageClass is called
+// THEN the check fails if the StorageClass of the persistent volumes doesn't exist
+func TestCheckDefaultStorageClass(t *testing.T) {
+	emptyDir := &vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{DefaultVolumeSource: &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	className := "fast"
+	namedClass := &vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{
+		DefaultVolumeSource: &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "vmi"}},
+		VolumeClaimSpecTemplates: []vzapi.VolumeClaimSpecTemplate{
+			{ObjectMeta: metav1.ObjectMeta{Name: "vmi"}, Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &className}},
+		},
+	}}
+	defaultClass := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard", Annotations: map[string]string{betaDefaultStorageClassAnnotation: "true"}}}
+	fastClass := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: className}}
+
+	tests := []struct {
+		name     string
+		cr       *vzapi.Verrazzano
+		classes  []*storagev1.StorageClass
+		expected vzapi.PreflightCheckResult
+	}{
+		{name: "emptyDir", cr: emptyDir, expected: vzapi.PreflightCheckPassed},
+		{name: "default class", cr: &vzapi.Verrazzano{}, classes: []*storagev1.StorageClass{fastClass, defaultClass}, expected: vzapi.PreflightCheckPassed},
+		{name: "no default class", cr: &vzapi.Verrazzano{}, classes: []*storagev1.StorageClass{fastClass}, expected: vzapi.PreflightCheckFailed},
+		{name: "named class", cr: namedClass, classes: []*storagev1.StorageClass{fastClass}, expected: vzapi.PreflightCheckPassed},
+		{name: "missing named class", cr: namedClass, classes: []*storagev1.StorageClass{defaultClass}, expected: vzapi.PreflightCheckFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(k8scheme.Scheme)
+			for _, class := range tt.classes {
+				builder = builder.WithObjects(class.DeepCopy())
+			}
+			check, err := checkDefaultStorageClass(spi.NewFakeContext(builder.Build(), tt.cr, false))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, check.Result, check.Message)
+		})
+	}
+}
+
+// newTestNode returns a node with a Kubernetes version and allocatable memory
+func newTestNode(name string, version string, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			NodeInfo:    corev1.NodeSystemInfo{KubeletVersion: version},
+			Allocatable: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+		},
+	}
+}
+
+// newPreflightObjects returns the objects of a cluster that passes the cluster preflight checks
+func newPreflightObjects() []client.Object {
+	return []client.Object{
+		newTestNode("node1", "v1.22.5", "32Gi"),
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard",
+			Annotations: map[string]string{defaultStorageClassAnnotation: "true"}}},
+	}
+}
+
+// expectPreflightChecks expects the calls made by the cluster preflight checks, and returns a cluster that passes the
+// checks
+func expectPreflightChecks(mock *mocks.MockClient) {
+	mock.EXPECT().
+		List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.NodeList{})).
+		DoAndReturn(func(ctx context.Context, nodes *corev1.NodeList, opts ...client.ListOption) error {
+			nodes.Items = []corev1.Node{*newTestNode("node1", "v1.22.5", "32Gi")}
+			return nil
+		}).AnyTimes()
+	mock.EXPECT().
+		List(gomock.Any(), gomock.AssignableToTypeOf(&storagev1.StorageClassList{})).
+		DoAndReturn(func(ctx context.Context, classes *storagev1.StorageClassList, opts ...client.ListOption) error {
+			classes.Items = []storagev1.StorageClass{{ObjectMeta: metav1.ObjectMeta{Name: "standard",
+				Annotations: map[string]string{defaultStorageClassAnnotation: "true"}}}}
+			return nil
+		}).AnyTimes()
+	mock.EXPECT().
+		List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.ServiceList{})).
+		Return(nil).AnyTimes()
+}
@@ -64,3 +64,15 @@ func AddGlobalImagePullSecretHelmOverride(log vzlog.VerrazzanoLogger, client cli
 	}
 	return kvs, nil
 }
+
+// GlobalImagePullSecretExists returns true if the global image pull secret exists in the default namespace
+func GlobalImagePullSecretExists(client client.Client) (bool, error) {
+	var sourceSecret v1.Secret
+	if err := client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: constants.GlobalImagePullSecName}, &sourceSecret); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
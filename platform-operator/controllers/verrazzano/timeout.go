@@ -0,0 +1,138 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+)
+
+// installStartTimeMap has the time each component started to install, by component name, keyed by the namespace and
+// name of the Verrazzano resource.  The install of a component starts once the components it depends on are ready.
+// The times are kept in memory, so the timeouts start over when the operator is restarted.
+var installStartTimeMap = map[string]map[string]time.Time{}
+
+// installStartTimeMutex guards installStartTimeMap, which is updated by the components reconciled in parallel
+var installStartTimeMutex sync.Mutex
+
+// getInstallTimeout returns the install timeout of a component, zero if there is no timeout
+func getInstallTimeout(effectiveCR *vzapi.Verrazzano, comp spi.Component) time.Duration {
+	timeouts := effectiveCR.Spec.ComponentTimeouts
+	if timeouts == nil {
+		return 0
+	}
+	for _, compTimeout := range timeouts.Components {
+		if compTimeout.Name == comp.GetJSONName() && compTimeout.Install != nil {
+			return compTimeout.Install.Duration
+		}
+	}
+	if timeouts.Install != nil {
+		return timeouts.Install.Duration
+	}
+	return 0
+}
+
+// getUpgradeTimeout returns the upgrade timeout of a component, zero if there is no timeout
+func getUpgradeTimeout(effectiveCR *vzapi.Verrazzano, comp spi.Component) time.Duration {
+	timeouts := effectiveCR.Spec.ComponentTimeouts
+	if timeouts == nil {
+		return 0
+	}
+	for _, compTimeout := range timeouts.Components {
+		if compTimeout.Name == comp.GetJSONName() && compTimeout.Upgrade != nil {
+			return compTimeout.Upgrade.Duration
+		}
+	}
+	if timeouts.Upgrade != nil {
+		return timeouts.Upgrade.Duration
+	}
+	return 0
+}
+
+// isInstallTimedOut records the time the install of the component started the first time it is called, and returns
+// true if the install has taken longer than the install timeout of the component
+func isInstallTimedOut(compContext spi.ComponentContext, comp spi.Component) (bool, time.Duration) {
+	timeout := getInstallTimeout(compContext.EffectiveCR(), comp)
+	installStartTimeMutex.Lock()
+	defer installStartTimeMutex.Unlock()
+	key := getNSNKey(compContext.ActualCR())
+	if _, ok := installStartTimeMap[key]; !ok {
+		installStartTimeMap[key] = map[string]time.Time{}
+	}
+	startTime, ok := installStartTimeMap[key][comp.Name()]
+	if !ok {
+		installStartTimeMap[key][comp.Name()] = time.Now()
+		return false, timeout
+	}
+	return timeout > 0 && time.Since(startTime) > timeout, timeout
+}
+
+// clearInstallStartTime forgets the time the install of a component started, once the install is done
+func clearInstallStartTime(cr *vzapi.Verrazzano, compName string) {
+	installStartTimeMutex.Lock()
+	defer installStartTimeMutex.Unlock()
+	delete(installStartTimeMap[getNSNKey(cr)], compName)
+}
+
+// deleteInstallStartTimes forgets the times the installs of the components of the Verrazzano resource started
+func deleteInstallStartTimes(cr *vzapi.Verrazzano) {
+	installStartTimeMutex.Lock()
+	defer installStartTimeMutex.Unlock()
+	delete(installStartTimeMap, getNSNKey(cr))
+}
+
+// getTimedOutMessage returns the condition message of a component whose operation has timed out, with the last error
+// of the component if there is one
+func getTimedOutMessage(cr *vzapi.Verrazzano, compName string, operation string, timeout time.Duration) string {
+	msg := fmt.Sprintf("%s did not complete within %v", operation, timeout)
+	if compErr, ok := getComponentError(cr, compName); ok {
+		msg = fmt.Sprintf("%s, the last error was: %s", msg, compErr.message)
+	}
+	return msg
+}
+
+// failComponent sets the component to Failed with the failed condition, and sets the Verrazzano resource to Failed.
+// The failed condition of an earlier failure is replaced, so that the condition has the time of this failure.
+func (r *Reconciler) failComponent(compContext spi.ComponentContext, message string, conditionType vzapi.ConditionType) error {
+	cr := compContext.ActualCR()
+	compName := compContext.GetComponent()
+	if compStatus, ok := cr.Status.Components[compName]; ok {
+		compStatus.Conditions = removeCondition(compStatus.Conditions, conditionType)
+	}
+	if err := r.updateComponentStatus(compContext, message, conditionType); err != nil {
+		return err
+	}
+	return r.updateStatus(compContext.Log(), cr, fmt.Sprintf("Component %s: %s", compName, message), conditionType)
+}
+
+// updateUpgradeFailedStatus sets the components whose upgrade has timed out to Failed, and sets the Verrazzano resource
+// to Failed
+func (r *Reconciler) updateUpgradeFailedStatus(log vzlog.VerrazzanoLogger, cr *vzapi.Verrazzano, tracker *upgradeTracker) error {
+	spiCtx, err := spi.NewContext(log, r.Client, cr, r.DryRun)
+	if err != nil {
+		return err
+	}
+	failed := tracker.failedComponents()
+	for _, compName := range failed {
+		compStatus, ok := cr.Status.Components[compName]
+		if !ok {
+			continue
+		}
+		compStatus.Conditions = removeCondition(compStatus.Conditions, vzapi.CondUpgradeFailed)
+		compContext := spiCtx.Init(compName).Operation(vzconst.UpgradeOperation)
+		if err := r.updateComponentStatus(compContext, tracker.compMap[compName].failedMessage, vzapi.CondUpgradeFailed); err != nil {
+			return err
+		}
+	}
+	msg := fmt.Sprintf("Verrazzano upgrade to version %s failed, the upgrade of %s did not complete within the upgrade timeout",
+		cr.Spec.Version, strings.Join(failed, ", "))
+	return r.updateStatus(log, cr, msg, vzapi.CondUpgradeFailed)
+}
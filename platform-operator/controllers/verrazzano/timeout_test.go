@@ -0,0 +1,180 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestGetComponentTimeouts tests the getInstallTimeout and getUpgradeTimeout funcs
+// GIVEN component timeouts with defaults and an override of a component
+// WHEN the timeouts of the components are requested
+// THEN the override is returned for the component, and the defaults for the other components
+func TestGetComponentTimeouts(t *testing.T) {
+	cr := &vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{ComponentTimeouts: &vzapi.ComponentTimeouts{
+		Install: &metav1.Duration{Duration: 30 * time.Minute},
+		Upgrade: &metav1.Duration{Duration: 20 * time.Minute},
+		Components: []vzapi.ComponentTimeout{
+			{Name: "a", Install: &metav1.Duration{Duration: time.Hour}},
+		},
+	}}}
+	a := fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "a", JSONName: "a"}}
+	b := fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "b", JSONName: "b"}}
+
+	assert.Equal(t, time.Hour, getInstallTimeout(cr, a))
+	assert.Equal(t, 20*time.Minute, getUpgradeTimeout(cr, a))
+	assert.Equal(t, 30*time.Minute, getInstallTimeout(cr, b))
+	assert.Equal(t, 20*time.Minute, getUpgradeTimeout(cr, b))
+	assert.Equal(t, time.Duration(0), getInstallTimeout(&vzapi.Verrazzano{}, a))
+	assert.Equal(t, time.Duration(0), getUpgradeTimeout(&vzapi.Verrazzano{}, a))
+}
+
+// TestInstallTimeout tests the install timeout of a component for the following use case
+// GIVEN a component that doesn't become ready after an install error
+// WHEN the install timeout expires, and then the install is retried with the annotation
+// THEN the component and the Verrazzano resource are Failed with the last error, and the component is installed again
+// after the retry
+func TestInstallTimeout(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "a", JSONName: "a", SupportsOperatorInstall: true}, ready: "false"},
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-install-timeout", Generation: 1},
+		Spec: vzapi.VerrazzanoSpec{
+			ComponentTimeouts: &vzapi.ComponentTimeouts{Install: &metav1.Duration{Duration: 10 * time.Minute}},
+		},
+		Status: vzapi.VerrazzanoStatus{
+			State: vzapi.VzStateInstalling,
+			Components: vzapi.ComponentStatusMap{
+				"a": {Name: "a", State: vzapi.CompStateInstalling, Conditions: []vzapi.Condition{{Type: vzapi.CondInstallStarted}}},
+			},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	defer deleteInstallStartTimes(vz)
+	defer deleteComponentErrors(vz)
+	nsn := types.NamespacedName{Namespace: "verrazzano", Name: "test-install-timeout"}
+
+	getVZContext := func() vzcontext.VerrazzanoContext {
+		actual := &vzapi.Verrazzano{}
+		asserts.NoError(c.Get(context.TODO(), nsn, actual))
+		vzctx, err := vzcontext.NewVerrazzanoContext(vzlog.DefaultLogger(), c, actual, false)
+		asserts.NoError(err)
+		return vzctx
+	}
+	reconciler.recordOperationError(vz, "a", "PostInstall", fmt.Errorf("the secret is missing"))
+
+	// The install timeout starts
+	result, err := reconciler.reconcileComponents(getVZContext())
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	asserts.Equal(vzapi.CompStateInstalling, vz.Status.Components["a"].State)
+
+	// The install timeout expires
+	installStartTimeMap[getNSNKey(vz)]["a"] = time.Now().Add(-11 * time.Minute)
+	_, err = reconciler.reconcileComponents(getVZContext())
+	asserts.NoError(err)
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	compStatus := vz.Status.Components["a"]
+	asserts.Equal(vzapi.CompStateFailed, compStatus.State)
+	asserts.Equal(vzapi.CondInstallFailed, compStatus.Conditions[len(compStatus.Conditions)-1].Type)
+	asserts.Equal("Install did not complete within 10m0s, the last error was: PostInstall failed: the secret is missing",
+		compStatus.Conditions[len(compStatus.Conditions)-1].Message)
+	asserts.Equal(vzapi.VzStateFailed, vz.Status.State)
+	asserts.Equal(vzapi.CondInstallFailed, vz.Status.Conditions[len(vz.Status.Conditions)-1].Type)
+
+	// The install is retried
+	vz.Annotations = map[string]string{vzconst.InstallRetryVersion: "1"}
+	asserts.NoError(c.Update(context.TODO(), vz))
+	_, err = reconciler.ProcFailedState(getVZContext())
+	asserts.NoError(err)
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	asserts.Equal(vzapi.VzStateReady, vz.Status.State)
+	asserts.Equal("1", vz.Annotations[vzconst.ObservedInstallRetryVersion])
+
+	_, err = reconciler.reconcileComponents(getVZContext())
+	asserts.NoError(err)
+	asserts.NoError(c.Get(context.TODO(), nsn, vz))
+	asserts.Equal(vzapi.CompStatePreInstalling, vz.Status.Components["a"].State)
+}
+
+// TestUpgradeTimeout tests the upgrade timeout of a component for the following use case
+// GIVEN a component that doesn't become ready after it is upgraded
+// WHEN the upgrade timeout expires
+// THEN the component upgrade fails, and the component and the Verrazzano resource are set to Failed
+func TestUpgradeTimeout(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	comp := fakeComponent{HelmComponent: helm.HelmComponent{ReleaseName: "a", JSONName: "a", SupportsOperatorInstall: true}, ready: "false"}
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-upgrade-timeout", Generation: 2},
+		Spec:       vzapi.VerrazzanoSpec{Version: "1.4.0"},
+		Status: vzapi.VerrazzanoStatus{
+			State:   vzapi.VzStateUpgrading,
+			Version: "1.3.0",
+			Components: vzapi.ComponentStatusMap{
+				"a": {Name: "a", State: vzapi.CompStateReady, Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}}},
+			},
+		},
+	}
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	spiCtx, err := spi.NewContext(vzlog.DefaultLogger(), c, vz, false)
+	asserts.NoError(err)
+
+	// The component is still waiting to be ready
+	upgradeContext := &componentUpgradeContext{state: compStateWaitReady, startTime: time.Now(), timeout: 30 * time.Minute}
+	result, err := reconciler.upgradeSingleComponent(spiCtx, upgradeContext, comp)
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.Equal(compStateWaitReady, upgradeContext.state)
+
+	// The upgrade timeout expires
+	upgradeContext.startTime = time.Now().Add(-31 * time.Minute)
+	_, err = reconciler.upgradeSingleComponent(spiCtx, upgradeContext, comp)
+	asserts.NoError(err)
+	asserts.Equal(compStateFailed, upgradeContext.state)
+	asserts.Equal("Upgrade did not complete within 30m0s", upgradeContext.failedMessage)
+
+	tracker := &upgradeTracker{compMap: map[string]*componentUpgradeContext{"a": upgradeContext}}
+	asserts.NoError(reconciler.updateUpgradeFailedStatus(vzlog.DefaultLogger(), vz, tracker))
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test-upgrade-timeout"}, vz))
+	compStatus := vz.Status.Components["a"]
+	asserts.Equal(vzapi.CompStateFailed, compStatus.State)
+	asserts.Equal("Upgrade did not complete within 30m0s", compStatus.Conditions[len(compStatus.Conditions)-1].Message)
+	asserts.Equal(vzapi.VzStateFailed, vz.Status.State)
+	asserts.Equal("Verrazzano upgrade to version 1.4.0 failed, the upgrade of a did not complete within the upgrade timeout",
+		vz.Status.Conditions[len(vz.Status.Conditions)-1].Message)
+}
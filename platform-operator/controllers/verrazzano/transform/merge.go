@@ -4,10 +4,9 @@
 package transform
 
 import (
-	"github.com/verrazzano/verrazzano/pkg/constants"
-	"strings"
+	"io/ioutil"
 
-	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/pkg/constants"
 	"sigs.k8s.io/yaml"
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
@@ -22,8 +21,22 @@ const (
 // MergeProfiles merges a list of Verrazzano profile files with an existing Verrazzano CR.
 // The profiles must be in the Verrazzano CR format
 func MergeProfiles(cr *vzapi.Verrazzano, profileFiles ...string) (*vzapi.Verrazzano, error) {
+	var profiles []string
+	for _, profileFile := range profileFiles {
+		data, err := ioutil.ReadFile(profileFile)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, string(data))
+	}
+	return MergeProfileYAMLs(cr, profiles...)
+}
+
+// MergeProfileYAMLs merges a list of Verrazzano profiles with an existing Verrazzano CR.
+// The profiles must be YAML in the Verrazzano CR format
+func MergeProfileYAMLs(cr *vzapi.Verrazzano, profiles ...string) (*vzapi.Verrazzano, error) {
 	// First merge the profiles
-	merged, err := vzyaml.StrategicMergeFiles(vzapi.Verrazzano{}, profileFiles...)
+	merged, err := vzyaml.StrategicMerge(vzapi.Verrazzano{}, profiles...)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +46,6 @@ func MergeProfiles(cr *vzapi.Verrazzano, profileFiles ...string) (*vzapi.Verrazz
 	if err != nil {
 		return nil, err
 	}
-
 	merged, err = vzyaml.StrategicMerge(vzapi.Verrazzano{}, merged, string(crYAML))
 	if err != nil {
 		return nil, err
@@ -42,7 +54,6 @@ func MergeProfiles(cr *vzapi.Verrazzano, profileFiles ...string) (*vzapi.Verrazz
 	// Return a new CR
 	var newCR vzapi.Verrazzano
 	yaml.Unmarshal([]byte(merged), &newCR)
-
 	return &newCR, nil
 }
 
@@ -53,17 +64,13 @@ func GetEffectiveCR(actualCR *vzapi.Verrazzano) (*vzapi.Verrazzano, error) {
 	if actualCR == nil {
 		return nil, nil
 	}
-	// Identify the set of profiles, base + declared
-	profiles := []string{baseProfile, string(vzapi.Prod)}
-	if len(actualCR.Spec.Profile) > 0 {
-		profiles = append([]string{baseProfile}, strings.Split(string(actualCR.Spec.Profile), ",")...)
-	}
-	var profileFiles []string
-	for _, profile := range profiles {
-		profileFiles = append(profileFiles, config.GetProfile(profile))
+	// Identify the chain of profiles, base + declared profiles and the custom profiles they are layered on
+	_, profiles, err := resolveProfiles(actualCR)
+	if err != nil {
+		return nil, err
 	}
-	// Merge the profile files into an effective profile YAML string
-	effectiveCR, err := MergeProfiles(actualCR, profileFiles...)
+	// Merge the profiles into an effective profile YAML string
+	effectiveCR, err := MergeProfileYAMLs(actualCR, profiles...)
 	if err != nil {
 		return nil, err
 	}
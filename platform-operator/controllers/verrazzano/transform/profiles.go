@@ -0,0 +1,140 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// profileReader reads the ConfigMaps of the custom profiles, only the built-in profiles can be used until it is set
+var profileReader client.Reader
+
+// SetProfileReader sets the client used to read the ConfigMaps of the custom profiles
+func SetProfileReader(r client.Reader) {
+	profileReader = r
+}
+
+// GetProfileChain returns the names of the profiles that are merged, in order, to create the effective CR.  The
+// base profile is first, each custom profile follows the profiles it is layered on.
+func GetProfileChain(cr *vzapi.Verrazzano) ([]string, error) {
+	chain, _, err := resolveProfiles(cr)
+	return chain, err
+}
+
+// ValidateProfileChain resolves and merges the requested profiles, it returns an error when a custom profile is not a valid
+// Verrazzano resource, is layered on a missing profile or on itself, or when the profiles can not be merged
+func ValidateProfileChain(requestedProfile vzapi.ProfileType) error {
+	_, err := GetEffectiveCR(&vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{Profile: requestedProfile}})
+	return err
+}
+
+// resolveProfiles returns the names and YAML of the profiles that are merged, in order, to create the effective CR
+func resolveProfiles(cr *vzapi.Verrazzano) ([]string, []string, error) {
+	declared := []string{string(vzapi.Prod)}
+	if len(cr.Spec.Profile) > 0 {
+		declared = strings.Split(string(cr.Spec.Profile), ",")
+	}
+	r := profileResolver{visiting: map[string]bool{}}
+	if err := r.add(baseProfile); err != nil {
+		return nil, nil, err
+	}
+	for _, name := range declared {
+		if err := r.add(strings.TrimSpace(name)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return r.chain, r.profiles, nil
+}
+
+// profileResolver resolves the chain of profiles, a custom profile is layered on the profiles in its spec.profile
+type profileResolver struct {
+	chain    []string
+	profiles []string
+	visiting map[string]bool
+}
+
+// add adds the profile to the chain after the profiles it is layered on, unless it is already in the chain
+func (r *profileResolver) add(name string) error {
+	for _, profile := range r.chain {
+		if profile == name {
+			return nil
+		}
+	}
+	if IsBuiltInProfile(name) {
+		data, err := ioutil.ReadFile(config.GetProfile(name))
+		if err != nil {
+			return err
+		}
+		r.chain = append(r.chain, name)
+		r.profiles = append(r.profiles, string(data))
+		return nil
+	}
+
+	if r.visiting[name] {
+		return fmt.Errorf("Profile %s is layered on itself", name)
+	}
+	r.visiting[name] = true
+	defer delete(r.visiting, name)
+
+	data, err := getCustomProfile(name)
+	if err != nil {
+		return err
+	}
+	profile := vzapi.Verrazzano{}
+	if err := yaml.Unmarshal([]byte(data), &profile); err != nil {
+		return fmt.Errorf("Profile %s is not a valid Verrazzano resource: %v", name, err)
+	}
+	if len(profile.Spec.Profile) > 0 {
+		for _, parent := range strings.Split(string(profile.Spec.Profile), ",") {
+			if err := r.add(strings.TrimSpace(parent)); err != nil {
+				return err
+			}
+		}
+	}
+	r.chain = append(r.chain, name)
+	r.profiles = append(r.profiles, data)
+	return nil
+}
+
+// IsBuiltInProfile returns true if the profile is one of the profiles in the platform operator image
+func IsBuiltInProfile(name string) bool {
+	switch vzapi.ProfileType(name) {
+	case baseProfile, vzapi.Dev, vzapi.Prod, vzapi.ManagedCluster:
+		return true
+	}
+	return false
+}
+
+// getCustomProfile returns the YAML of a custom profile from the labelled ConfigMap in the install namespace
+func getCustomProfile(name string) (string, error) {
+	if profileReader == nil {
+		return "", fmt.Errorf("Profile %s not found", name)
+	}
+	cm := &corev1.ConfigMap{}
+	err := profileReader.Get(context.TODO(), types.NamespacedName{Namespace: vzconst.VerrazzanoInstallNamespace, Name: name}, cm)
+	if errors.IsNotFound(err) || (err == nil && cm.Labels[vzconst.VerrazzanoProfileLabel] != "true") {
+		return "", fmt.Errorf("Profile %s not found, a custom profile is a ConfigMap in namespace %s with label %s=true",
+			name, vzconst.VerrazzanoInstallNamespace, vzconst.VerrazzanoProfileLabel)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Failed to get the ConfigMap of profile %s: %v", name, err)
+	}
+	data, ok := cm.Data[vzconst.VerrazzanoProfileKey]
+	if !ok {
+		return "", fmt.Errorf("The ConfigMap of profile %s does not have the key %s", name, vzconst.VerrazzanoProfileKey)
+	}
+	return data, nil
+}
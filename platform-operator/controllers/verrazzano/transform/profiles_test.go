@@ -0,0 +1,139 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newProfileConfigMap returns the ConfigMap of a custom profile
+func newProfileConfigMap(name string, labelled bool, profile string) client.Object {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: vzconst.VerrazzanoInstallNamespace, Name: name},
+		Data:       map[string]string{vzconst.VerrazzanoProfileKey: profile},
+	}
+	if labelled {
+		cm.Labels = map[string]string{vzconst.VerrazzanoProfileLabel: "true"}
+	}
+	return cm
+}
+
+// TestGetProfileChain tests the GetProfileChain function
+// GIVEN a Verrazzano resource with built-in and custom profiles
+// WHEN GetProfileChain is called
+// THEN the profiles are returned in merge order, or an error is returned if a profile cannot be resolved
+func TestGetProfileChain(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+	defer SetProfileReader(nil)
+
+	SetProfileReader(fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		newProfileConfigMap("small", true, "spec:\n  profile: dev\n"),
+		newProfileConfigMap("small-ha", true, "spec:\n  profile: small\n"),
+		newProfileConfigMap("unlabelled", false, "spec: {}\n"),
+		newProfileConfigMap("cycle-a", true, "spec:\n  profile: cycle-b\n"),
+		newProfileConfigMap("cycle-b", true, "spec:\n  profile: cycle-a\n"),
+	).Build())
+
+	tests := []struct {
+		profile string
+		chain   []string
+		err     string
+	}{
+		{profile: "", chain: []string{"base", "prod"}},
+		{profile: "dev", chain: []string{"base", "dev"}},
+		{profile: "small-ha", chain: []string{"base", "dev", "small", "small-ha"}},
+		{profile: "prod,small", chain: []string{"base", "prod", "dev", "small"}},
+		{profile: "unlabelled", err: "not found"},
+		{profile: "missing", err: "not found"},
+		{profile: "cycle-a", err: "layered on itself"},
+	}
+	for _, test := range tests {
+		chain, err := GetProfileChain(&vzapi.Verrazzano{Spec: vzapi.VerrazzanoSpec{Profile: vzapi.ProfileType(test.profile)}})
+		if len(test.err) > 0 {
+			asserts.Error(err, test.profile)
+			asserts.Contains(err.Error(), test.err, test.profile)
+			continue
+		}
+		asserts.NoError(err, test.profile)
+		asserts.Equal(test.chain, chain, test.profile)
+	}
+}
+
+// TestGetEffectiveCRCustomProfile tests the GetEffectiveCR function
+// GIVEN a Verrazzano resource with a custom profile layered on the dev profile
+// WHEN GetEffectiveCR is called
+// THEN the custom profile is merged over the dev profile, and the Verrazzano resource over the custom profile
+func TestGetEffectiveCRCustomProfile(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+	defer SetProfileReader(nil)
+
+	SetProfileReader(fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		newProfileConfigMap("small", true, `
+spec:
+  profile: dev
+  environmentName: small
+  components:
+    console:
+      enabled: false
+    grafana:
+      enabled: false
+`),
+	).Build())
+
+	grafanaEnabled := true
+	cr := &vzapi.Verrazzano{
+		Spec: vzapi.VerrazzanoSpec{
+			Profile: "small",
+			Components: vzapi.ComponentSpec{
+				Grafana: &vzapi.GrafanaComponent{Enabled: &grafanaEnabled},
+			},
+		},
+	}
+	effectiveCR, err := GetEffectiveCR(cr)
+	asserts.NoError(err)
+	asserts.Equal("small", effectiveCR.Spec.EnvironmentName)
+	asserts.False(*effectiveCR.Spec.Components.Console.Enabled)
+	asserts.True(*effectiveCR.Spec.Components.Grafana.Enabled)
+	// The default volume source of the dev profile
+	asserts.NotNil(effectiveCR.Spec.DefaultVolumeSource.EmptyDir)
+}
+
+// TestValidateProfileChain tests the ValidateProfileChain function
+// GIVEN custom profiles that are valid, not valid Verrazzano resources, layered on missing profiles or on themselves
+// WHEN ValidateProfileChain is called
+// THEN an error is only returned for the profiles that can not be resolved or merged
+func TestValidateProfileChain(t *testing.T) {
+	asserts := assert.New(t)
+	config.TestProfilesDir = "../../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+	defer SetProfileReader(nil)
+
+	SetProfileReader(fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		newProfileConfigMap("small", true, "spec:\n  profile: dev\n"),
+		newProfileConfigMap("orphan", true, "spec:\n  profile: missing\n"),
+		newProfileConfigMap("invalid", true, "spec: [\n"),
+		newProfileConfigMap("cycle-a", true, "spec:\n  profile: cycle-b\n"),
+		newProfileConfigMap("cycle-b", true, "spec:\n  profile: cycle-a\n"),
+	).Build())
+
+	asserts.NoError(ValidateProfileChain(""))
+	asserts.NoError(ValidateProfileChain("small"))
+	asserts.Error(ValidateProfileChain("orphan"))
+	asserts.Error(ValidateProfileChain("invalid"))
+	asserts.Error(ValidateProfileChain("cycle-a"))
+}
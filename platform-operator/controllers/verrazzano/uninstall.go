@@ -0,0 +1,344 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"strings"
+
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzstring "github.com/verrazzano/verrazzano/pkg/string"
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/common"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// vzStateUninstallStart is the state where Verrazzano is starting the uninstall flow
+	vzStateUninstallStart VerrazzanoUninstallState = "vzUninstallStart"
+
+	// vzStateUninstallComponents is the state where the components are being uninstalled
+	vzStateUninstallComponents VerrazzanoUninstallState = "vzUninstallComponents"
+
+	// vzStateUninstallDone is the state when uninstall is done
+	vzStateUninstallDone VerrazzanoUninstallState = "vzUninstallDone"
+
+	// vzStateUninstallEnd is the terminal state
+	vzStateUninstallEnd VerrazzanoUninstallState = "vzUninstallEnd"
+)
+
+// installGroup is the API group of the CRDs of the platform operator, which are kept when Verrazzano is uninstalled
+const installGroup = "install.verrazzano.io"
+
+// keptCRDs are the other CRDs of the platform operator
+var keptCRDs = []string{"verrazzanomanagedclusters.clusters.verrazzano.io"}
+
+// keptClusterRoleBindings are the cluster role bindings of the platform operator
+var keptClusterRoleBindings = []string{"verrazzano-platform-operator", "verrazzano-install", "verrazzano-managed-cluster"}
+
+// keptClusterRoles are the cluster roles of the platform operator
+var keptClusterRoles = []string{"verrazzano-platform-operator", "verrazzano-managed-cluster"}
+
+// namespaceLabels are the labels of the namespaces Verrazzano creates
+var namespaceLabels = []client.MatchingLabels{
+	{"k8s-app": "verrazzano.io"},
+	{"verrazzano.io/namespace": "monitoring"},
+}
+
+// VerrazzanoUninstallState identifies the state of a Verrazzano uninstall operation
+type VerrazzanoUninstallState string
+
+// uninstallTracker has the uninstall context for the Verrazzano uninstall
+// This tracker keeps an in-memory uninstall state for Verrazzano and the components that
+// are being uninstalled.
+type uninstallTracker struct {
+	vzState VerrazzanoUninstallState
+	gen     int64
+	compMap map[string]*componentUninstallContext
+}
+
+// uninstallTrackerMap has a map of uninstallTrackers, one entry per Verrazzano CR resource generation
+var uninstallTrackerMap = make(map[string]*uninstallTracker)
+
+// reconcileUninstall will uninstall all of the Verrazzano components
+func (r *Reconciler) reconcileUninstall(log vzlog.VerrazzanoLogger, cr *installv1alpha1.Verrazzano) (ctrl.Result, error) {
+	log.Once("Uninstalling Verrazzano")
+
+	tracker := getUninstallTracker(cr)
+	done := false
+	for !done {
+		switch tracker.vzState {
+		case vzStateUninstallStart:
+			// Only write the uninstall started message once
+			if !isLastCondition(cr.Status, installv1alpha1.CondUninstallStarted) {
+				err := r.updateStatus(log, cr, "Verrazzano uninstall in progress", installv1alpha1.CondUninstallStarted)
+				// Always requeue to get a fresh copy of status and avoid potential conflict
+				return newRequeueWithDelay(), err
+			}
+			tracker.vzState = vzStateUninstallComponents
+
+		case vzStateUninstallComponents:
+			log.Once("Uninstalling all Verrazzano components")
+			res, err := r.uninstallComponents(log, cr, tracker)
+			if err != nil || res.Requeue {
+				return res, err
+			}
+			tracker.vzState = vzStateUninstallDone
+
+		case vzStateUninstallDone:
+			log.Once("Verrazzano successfully uninstalled all components")
+			if err := r.updateStatus(log, cr, "Verrazzano uninstall completed successfully", installv1alpha1.CondUninstallComplete); err != nil {
+				return newRequeueWithDelay(), err
+			}
+			tracker.vzState = vzStateUninstallEnd
+
+		case vzStateUninstallEnd:
+			done = true
+			// Uninstall completely done
+			deleteUninstallTracker(cr)
+		}
+	}
+	// Uninstall done, no need to requeue
+	return ctrl.Result{}, nil
+}
+
+// isUninstallDone returns true if the uninstall of the Verrazzano components has completed or failed
+func isUninstallDone(st installv1alpha1.VerrazzanoStatus) bool {
+	for _, cond := range st.Conditions {
+		if cond.Type == installv1alpha1.CondUninstallComplete || cond.Type == installv1alpha1.CondUninstallFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// getUninstallTracker gets the uninstall tracker for Verrazzano
+func getUninstallTracker(cr *installv1alpha1.Verrazzano) *uninstallTracker {
+	key := getNSNKey(cr)
+	vuc, ok := uninstallTrackerMap[key]
+	// If the entry is missing or the generation is different create a new entry
+	if !ok || vuc.gen != cr.Generation {
+		vuc = &uninstallTracker{
+			vzState: vzStateUninstallStart,
+			gen:     cr.Generation,
+			compMap: make(map[string]*componentUninstallContext),
+		}
+		uninstallTrackerMap[key] = vuc
+	}
+	return vuc
+}
+
+// deleteUninstallTracker deletes the uninstall tracker for the Verrazzano resource
+func deleteUninstallTracker(cr *installv1alpha1.Verrazzano) {
+	key := getNSNKey(cr)
+	_, ok := uninstallTrackerMap[key]
+	if ok {
+		delete(uninstallTrackerMap, key)
+	}
+}
+
+// ComponentUninstallState identifies the state of a component during uninstall
+type ComponentUninstallState string
+
+const (
+	// compStateUninstallStart is the state when a component is starting the uninstall flow
+	compStateUninstallStart ComponentUninstallState = "UninstallStart"
+
+	// compStatePreUninstall is the state when a component does a pre-uninstall
+	compStatePreUninstall ComponentUninstallState = "PreUninstall"
+
+	// compStateUninstall is the state where a component does an uninstall
+	compStateUninstall ComponentUninstallState = "Uninstall"
+
+	// compStateWaitUninstalled is the state when a component is waiting for the uninstall to finish
+	compStateWaitUninstalled ComponentUninstallState = "WaitUninstalled"
+
+	// compStatePostUninstall is the state when a component is doing a post-uninstall
+	compStatePostUninstall ComponentUninstallState = "PostUninstall"
+
+	// compStateUninstallDone is the state when component uninstall is done
+	compStateUninstallDone ComponentUninstallState = "UninstallDone"
+
+	// compStateUninstallEnd is the terminal state
+	compStateUninstallEnd ComponentUninstallState = "UninstallEnd"
+)
+
+// componentUninstallContext has the uninstall context for a Verrazzano component uninstall
+type componentUninstallContext struct {
+	state ComponentUninstallState
+}
+
+// uninstallComponents will uninstall the components in reverse dependency order
+func (r *Reconciler) uninstallComponents(log vzlog.VerrazzanoLogger, cr *installv1alpha1.Verrazzano, tracker *uninstallTracker) (ctrl.Result, error) {
+	spiCtx, err := spi.NewContext(log, r.Client, cr, r.DryRun)
+	if err != nil {
+		return newRequeueWithDelay(), err
+	}
+
+	comps, err := registry.GetComponentsInUninstallOrder()
+	if err != nil {
+		log.Errorf("Failed determining the component uninstall order: %v", err)
+		return newRequeueWithDelay(), err
+	}
+
+	// Loop through all of the Verrazzano components and uninstall each one.
+	// Don't move to the next component until the current one has been successfully uninstalled
+	for _, comp := range comps {
+		uninstallContext := tracker.getComponentUninstallContext(comp.Name())
+		result, err := r.uninstallSingleComponent(spiCtx, uninstallContext, comp)
+		if err != nil || result.Requeue {
+			return result, err
+		}
+	}
+	// All components have been uninstalled
+	return ctrl.Result{}, nil
+}
+
+// uninstallSingleComponent uninstalls a single component
+func (r *Reconciler) uninstallSingleComponent(spiCtx spi.ComponentContext, uninstallContext *componentUninstallContext, comp spi.Component) (ctrl.Result, error) {
+	compName := comp.Name()
+	compContext := spiCtx.Init(compName).Operation(vzconst.UninstallOperation)
+	compLog := compContext.Log()
+
+	for uninstallContext.state != compStateUninstallEnd {
+		switch uninstallContext.state {
+		case compStateUninstallStart:
+			// Check if component is installed, if not continue
+			installed, err := comp.IsInstalled(compContext)
+			if err != nil {
+				compLog.Errorf("Failed checking if component %s is installed: %v", compName, err)
+				return newRequeueWithDelay(), err
+			}
+			if !installed {
+				compLog.Oncef("Component %s is not installed; uninstall being skipped", compName)
+				uninstallContext.state = compStateUninstallEnd
+				continue
+			}
+			compLog.Oncef("Component %s is installed and will be uninstalled", compName)
+			if err := r.updateComponentStatus(compContext, "Uninstall started", installv1alpha1.CondUninstallStarted); err != nil {
+				return ctrl.Result{Requeue: true}, err
+			}
+			uninstallContext.state = compStatePreUninstall
+
+		case compStatePreUninstall:
+			compLog.Oncef("Component %s pre-uninstall running", compName)
+			if err := comp.PreUninstall(compContext); err != nil {
+				r.recordOperationError(compContext.ActualCR(), compName, metrics.PreUninstallOperation, err)
+				compLog.Errorf("Failed pre-uninstalling component %s: %v", compName, err)
+				return newRequeueWithDelay(), err
+			}
+			uninstallContext.state = compStateUninstall
+
+		case compStateUninstall:
+			compLog.Progressf("Component %s uninstall running", compName)
+			if err := comp.Uninstall(compContext); err != nil {
+				r.recordOperationError(compContext.ActualCR(), compName, metrics.UninstallOperation, err)
+				compLog.Errorf("Failed uninstalling component %s, will retry: %v", compName, err)
+				return newRequeueWithDelay(), nil
+			}
+			uninstallContext.state = compStateWaitUninstalled
+
+		case compStateWaitUninstalled:
+			if !compContext.IsDryRun() {
+				uninstalled, err := comp.IsUninstalled(compContext)
+				if err != nil {
+					compLog.Errorf("Failed checking if component %s is uninstalled: %v", compName, err)
+					return newRequeueWithDelay(), err
+				}
+				if !uninstalled {
+					compLog.Progressf("Component %s is being uninstalled. Waiting for the component resources to be removed", compName)
+					return newRequeueWithDelay(), nil
+				}
+			}
+			uninstallContext.state = compStatePostUninstall
+
+		case compStatePostUninstall:
+			compLog.Oncef("Component %s post-uninstall running", compName)
+			if err := comp.PostUninstall(compContext); err != nil {
+				r.recordOperationError(compContext.ActualCR(), compName, metrics.PostUninstallOperation, err)
+				compLog.Errorf("Failed post-uninstalling component %s: %v", compName, err)
+				return newRequeueWithDelay(), err
+			}
+			uninstallContext.state = compStateUninstallDone
+
+		case compStateUninstallDone:
+			compLog.Oncef("Component %s has successfully uninstalled", compName)
+			if err := r.updateComponentStatus(compContext, "Uninstall complete", installv1alpha1.CondUninstallComplete); err != nil {
+				return ctrl.Result{Requeue: true}, err
+			}
+			uninstallContext.state = compStateUninstallEnd
+		}
+	}
+	// Component has been uninstalled
+	return ctrl.Result{}, nil
+}
+
+// getComponentUninstallContext gets the uninstall context for the component
+func (vuc *uninstallTracker) getComponentUninstallContext(compName string) *componentUninstallContext {
+	context, ok := vuc.compMap[compName]
+	if !ok {
+		context = &componentUninstallContext{
+			state: compStateUninstallStart,
+		}
+		vuc.compMap[compName] = context
+	}
+	return context
+}
+
+// deleteVerrazzanoResources deletes the Verrazzano resources that are not owned by a component once the components are
+// uninstalled.  The CRDs and RBAC resources of the platform operator are kept.
+func (r *Reconciler) deleteVerrazzanoResources(log vzlog.VerrazzanoLogger, vz *installv1alpha1.Verrazzano) error {
+	spiCtx, err := spi.NewContext(log, r.Client, vz, r.DryRun)
+	if err != nil {
+		return err
+	}
+	if err := common.ForceDeleteResources(spiCtx, &apiextv1.CustomResourceDefinitionList{}, isVerrazzanoCRD); err != nil {
+		return err
+	}
+	if err := common.DeleteResources(spiCtx, &rbacv1.ClusterRoleBindingList{}, isVerrazzanoResource(keptClusterRoleBindings)); err != nil {
+		return err
+	}
+	if err := common.DeleteResources(spiCtx, &rbacv1.ClusterRoleList{}, isVerrazzanoResource(keptClusterRoles)); err != nil {
+		return err
+	}
+
+	namespaces := []string{vzconst.VerrazzanoMultiClusterNamespace}
+	for _, labels := range namespaceLabels {
+		nsList := &corev1.NamespaceList{}
+		if err := r.List(context.TODO(), nsList, labels); err != nil {
+			log.Errorf("Failed listing the Verrazzano namespaces: %v", err)
+			return err
+		}
+		for _, ns := range nsList.Items {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+	return common.DeleteNamespaces(spiCtx, namespaces...)
+}
+
+// isVerrazzanoCRD returns true if the CRD is a Verrazzano CRD that is not a CRD of the platform operator
+func isVerrazzanoCRD(obj client.Object) bool {
+	crd, ok := obj.(*apiextv1.CustomResourceDefinition)
+	if !ok || !strings.HasSuffix(crd.Spec.Group, "verrazzano.io") {
+		return false
+	}
+	return crd.Spec.Group != installGroup && !vzstring.SliceContainsString(keptCRDs, crd.Name)
+}
+
+// isVerrazzanoResource returns a function that selects the resources with verrazzano in their name, except the ones
+// whose name contains one of the kept names
+func isVerrazzanoResource(kept []string) common.ResourceMatchFunc {
+	keep := common.NameContains(kept...)
+	return func(obj client.Object) bool {
+		return strings.Contains(obj.GetName(), "verrazzano") && !keep(obj)
+	}
+}
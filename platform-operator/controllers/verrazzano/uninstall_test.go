@@ -0,0 +1,93 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestDeleteVerrazzanoResources tests the deleteVerrazzanoResources func for the following use case
+// GIVEN the Verrazzano CRDs, RBAC resources and namespaces left once the components are uninstalled
+// WHEN deleteVerrazzanoResources is called
+// THEN they are deleted, and the CRDs and RBAC resources of the platform operator are kept
+func TestDeleteVerrazzanoResources(t *testing.T) {
+	a := assert.New(t)
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	scheme := newScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = apiextv1.AddToScheme(scheme)
+	newCRD := func(name string, group string) *apiextv1.CustomResourceDefinition {
+		return &apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Group: group},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newCRD("verrazzanos.install.verrazzano.io", installGroup),
+		newCRD("verrazzanomanagedclusters.clusters.verrazzano.io", "clusters.verrazzano.io"),
+		newCRD("verrazzanoprojects.clusters.verrazzano.io", "clusters.verrazzano.io"),
+		newCRD("ingresstraits.oam.verrazzano.io", "oam.verrazzano.io"),
+		newCRD("gateways.networking.istio.io", "networking.istio.io"),
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "verrazzano-platform-operator"}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "verrazzano-monitoring-operator"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "verrazzano-managed-cluster"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "verrazzano-project-admin"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: vzconst.VerrazzanoMultiClusterNamespace}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: vzconst.VerrazzanoMonitoringNamespace,
+			Labels: map[string]string{"verrazzano.io/namespace": "monitoring"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	).Build()
+
+	r := newVerrazzanoReconciler(c)
+	a.NoError(r.deleteVerrazzanoResources(vzlog.DefaultLogger(), &vzapi.Verrazzano{}))
+
+	deleted := []struct {
+		name types.NamespacedName
+		obj  client.Object
+	}{
+		{types.NamespacedName{Name: "verrazzanoprojects.clusters.verrazzano.io"}, &apiextv1.CustomResourceDefinition{}},
+		{types.NamespacedName{Name: "ingresstraits.oam.verrazzano.io"}, &apiextv1.CustomResourceDefinition{}},
+		{types.NamespacedName{Name: "verrazzano-monitoring-operator"}, &rbacv1.ClusterRoleBinding{}},
+		{types.NamespacedName{Name: "verrazzano-project-admin"}, &rbacv1.ClusterRole{}},
+		{types.NamespacedName{Name: vzconst.VerrazzanoMultiClusterNamespace}, &corev1.Namespace{}},
+		{types.NamespacedName{Name: vzconst.VerrazzanoMonitoringNamespace}, &corev1.Namespace{}},
+	}
+	for _, d := range deleted {
+		err := c.Get(context.TODO(), d.name, d.obj)
+		a.True(errors.IsNotFound(err), "expected %s to be deleted", d.name)
+	}
+
+	kept := []struct {
+		name types.NamespacedName
+		obj  client.Object
+	}{
+		{types.NamespacedName{Name: "verrazzanos.install.verrazzano.io"}, &apiextv1.CustomResourceDefinition{}},
+		{types.NamespacedName{Name: "verrazzanomanagedclusters.clusters.verrazzano.io"}, &apiextv1.CustomResourceDefinition{}},
+		{types.NamespacedName{Name: "gateways.networking.istio.io"}, &apiextv1.CustomResourceDefinition{}},
+		{types.NamespacedName{Name: "verrazzano-platform-operator"}, &rbacv1.ClusterRoleBinding{}},
+		{types.NamespacedName{Name: "verrazzano-managed-cluster"}, &rbacv1.ClusterRole{}},
+		{types.NamespacedName{Name: "default"}, &corev1.Namespace{}},
+	}
+	for _, k := range kept {
+		a.NoError(c.Get(context.TODO(), k.name, k.obj), "expected %s to be kept", k.name)
+	}
+}
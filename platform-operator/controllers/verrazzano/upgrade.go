@@ -6,7 +6,7 @@ package verrazzano
 import (
 	"context"
 	"fmt"
-	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
+	"time"
 
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/transform"
 
@@ -21,6 +21,7 @@ import (
 
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/istio"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/vzconfig"
 
 	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
 	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
@@ -50,6 +51,17 @@ const (
 
 	// vzStateEnd is the terminal state
 	vzStateEnd VerrazzanoUpgradeState = "vzStateEnd"
+
+	// vzStateStageGate is the state when the next stage of a staged upgrade is waiting for the upgraded components
+	// to be ready, and for approval if it is required
+	vzStateStageGate VerrazzanoUpgradeState = "vzStageGate"
+
+	// vzStateRollback is the state when a component has failed to upgrade and the upgraded components are being
+	// rolled back
+	vzStateRollback VerrazzanoUpgradeState = "vzRollback"
+
+	// vzStateFailed is the state when a component upgrade has timed out and the upgrade is not rolled back
+	vzStateFailed VerrazzanoUpgradeState = "vzFailed"
 )
 
 // VerrazzanoUpgradeState identifies the state of a Verrazzano upgrade operation
@@ -62,6 +74,10 @@ type upgradeTracker struct {
 	vzState VerrazzanoUpgradeState
 	gen     int64
 	compMap map[string]*componentUpgradeContext
+	// stage is the index of the upgrade stage that is in progress, see getUpgradeStages
+	stage int
+	// stageReadyTime is the time the components upgraded by the previous stages were found to be ready
+	stageReadyTime time.Time
 }
 
 // upgradeTrackerMap has a map of upgradeTrackers, one entry per Verrazzano CR resource generation
@@ -89,13 +105,54 @@ func (r *Reconciler) reconcileUpgrade(log vzlog.VerrazzanoLogger, cr *installv1a
 			tracker.vzState = vzStateUpgradeComponents
 
 		case vzStateUpgradeComponents:
-			// Upgrade the components
+			// Upgrade the components of the current stage
 			log.Once("Upgrading all Verrazzano components")
 			res, err := r.upgradeComponents(log, cr, tracker)
 			if err != nil || res.Requeue {
 				return res, err
 			}
-			tracker.vzState = vzStatePostUpgrade
+			if tracker.upgradeFailed() && isRollbackOnFailure(cr) {
+				tracker.vzState = vzStateRollback
+			} else if tracker.upgradeFailed() {
+				tracker.vzState = vzStateFailed
+			} else if tracker.stage < len(getUpgradeStages(cr))-1 {
+				tracker.stage++
+				tracker.stageReadyTime = time.Time{}
+				tracker.vzState = vzStateStageGate
+			} else {
+				tracker.vzState = vzStatePostUpgrade
+			}
+
+		case vzStateStageGate:
+			// Wait for the upgraded components to be ready, and for approval, before the next stage is started
+			res, err := r.checkStageGate(log, cr, tracker)
+			if err != nil || res.Requeue {
+				return res, err
+			}
+			tracker.vzState = vzStateUpgradeComponents
+
+		case vzStateRollback:
+			// Roll back the components that were upgraded, see UpgradePolicy.RollbackOnFailure
+			log.Oncef("Rolling back the components upgraded to version %s", cr.Spec.Version)
+			res, err := r.rollbackComponents(log, cr, tracker)
+			if err != nil || res.Requeue {
+				return res, err
+			}
+			if err := r.updateRolledBackStatus(log, cr, tracker); err != nil {
+				return newRequeueWithDelay(), err
+			}
+			deleteUpgradeTracker(cr)
+			// Requeue to process the Verrazzano resource in the failed state
+			return newRequeueWithDelay(), nil
+
+		case vzStateFailed:
+			// Fail the components whose upgrade has timed out, see ComponentTimeouts
+			if err := r.updateUpgradeFailedStatus(log, cr, tracker); err != nil {
+				return newRequeueWithDelay(), err
+			}
+			deleteUpgradeTracker(cr)
+			// Requeue to process the Verrazzano resource in the failed state
+			return newRequeueWithDelay(), nil
 
 		case vzStatePostUpgrade:
 			// Invoke the global post upgrade function after all components are upgraded.
@@ -150,6 +207,9 @@ func (r *Reconciler) reconcileUpgrade(log vzlog.VerrazzanoLogger, cr *installv1a
 				if componentStatus != nil && (effectiveCR != nil && comp.IsEnabled(effectiveCR)) {
 					componentStatus.LastReconciledGeneration = cr.Generation
 				}
+				if componentStatus != nil {
+					componentStatus.PreviousRelease = nil
+				}
 			}
 			// Update the status with the new version and component generations
 			cr.Status.Version = targetVersion
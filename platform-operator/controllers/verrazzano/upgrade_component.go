@@ -4,6 +4,7 @@
 package verrazzano
 
 import (
+	"sync"
 	"time"
 
 	"github.com/verrazzano/verrazzano/pkg/controller"
@@ -12,6 +13,7 @@ import (
 	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -39,11 +41,42 @@ const (
 
 	// compStateEnd is the terminal state
 	compStateEnd ComponentUpgradeState = "End"
+
+	// compStateFailed is the terminal state when the component upgrade has failed, the upgrade is rolled back or
+	// failed
+	compStateFailed ComponentUpgradeState = "Failed"
 )
 
+// maxUpgradeAttempts is the number of times a component upgrade step is attempted before the upgrade is rolled back,
+// see UpgradePolicy.RollbackOnFailure
+const maxUpgradeAttempts = 3
+
+// rollbackReadyTimeout is how long a component can take to be ready after it is upgraded before the upgrade is rolled
+// back, when no upgrade timeout is set for the component
+const rollbackReadyTimeout = 30 * time.Minute
+
 // componentUpgradeContext has the upgrade context for a Verrazzano component upgrade
 type componentUpgradeContext struct {
 	state ComponentUpgradeState
+	// previousRelease is the release of the component before the upgrade, only recorded if the upgrade is rolled
+	// back on failure
+	previousRelease *installv1alpha1.ComponentRelease
+	// previousReleaseSaved is true when the previous release has been saved in the component status
+	previousReleaseSaved bool
+	// upgradeAttempted is true when the component upgrade has been started and the component needs to be rolled back
+	upgradeAttempted bool
+	// failedAttempts is the number of consecutive times the current upgrade step has failed
+	failedAttempts int
+	// rolledBack is true when the component has been rolled back to the previous release
+	rolledBack bool
+	// startTime is the time the component upgrade started, see ComponentTimeouts
+	startTime time.Time
+	// timeout is how long the component upgrade can take, zero if there is no timeout
+	timeout time.Duration
+	// readyStartTime is the time the component started waiting to be ready after it was upgraded
+	readyStartTime time.Time
+	// failedMessage describes why the component upgrade has failed
+	failedMessage string
 }
 
 // upgradeComponents will upgrade the components as required
@@ -53,15 +86,65 @@ func (r *Reconciler) upgradeComponents(log vzlog.VerrazzanoLogger, cr *installv1
 		return newRequeueWithDelay(), err
 	}
 
-	// Loop through all of the Verrazzano components and upgrade each one.
-	// Don't move to the next component until the current one has been succcessfully upgraded
-	for _, comp := range registry.GetComponents() {
-		upgradeContext := tracker.getComponentUpgradeContext(comp.Name())
-		result, err := r.upgradeSingleComponent(spiCtx, upgradeContext, comp)
-		if err != nil || result.Requeue {
-			return result, err
+	components, err := registry.GetComponentsInInstallOrder()
+	if err != nil {
+		log.Errorf("Failed to get the component upgrade order: %v", err)
+		return newRequeueWithDelay(), err
+	}
+	// Create the upgrade contexts up front, they are read by the components that depend on them
+	for _, comp := range components {
+		tracker.getComponentUpgradeContext(comp.Name())
+	}
+	// Only upgrade the components of the current stage, the dependencies of a component are in the same stage or in
+	// an earlier stage, see the validation of the upgrade stages
+	stages := getUpgradeStages(cr)
+	var stageComponents []spi.Component
+	for _, comp := range components {
+		if getStageIndex(stages, comp.Name()) == tracker.stage {
+			stageComponents = append(stageComponents, comp)
 		}
+	}
 
+	// Upgrade the components in dependency order, components that don't depend on each other are upgraded
+	// at the same time.  Don't upgrade a component until all of its dependencies have been successfully upgraded.
+	var lock sync.Mutex
+	var result ctrl.Result
+	err = forEachComponent(stageComponents, getComponentConcurrency(), func(comp spi.Component) error {
+		for _, dependencyName := range comp.GetDependencies() {
+			if dependencyContext, ok := tracker.compMap[dependencyName]; ok && dependencyContext.state != compStateEnd {
+				metrics.CountRequeue(comp.Name())
+				lock.Lock()
+				defer lock.Unlock()
+				if !result.Requeue {
+					result = newRequeueWithDelay()
+				}
+				return nil
+			}
+		}
+		compResult, err := r.upgradeSingleComponent(spiCtx, tracker.compMap[comp.Name()], comp)
+		if controller.ShouldRequeue(compResult) {
+			metrics.CountRequeue(comp.Name())
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if err != nil {
+			result = compResult
+			return err
+		}
+		if compResult.Requeue && !result.Requeue {
+			result = compResult
+		}
+		return nil
+	})
+	if saveErr := r.savePreviousReleases(log, cr, tracker); saveErr != nil && err == nil {
+		return newRequeueWithDelay(), saveErr
+	}
+	if tracker.upgradeFailed() {
+		// Stop upgrading the components, the upgrade is rolled back by the caller
+		return ctrl.Result{}, nil
+	}
+	if err != nil || result.Requeue {
+		return result, err
 	}
 	// All components have been upgraded
 	return ctrl.Result{}, nil
@@ -74,6 +157,17 @@ func (r *Reconciler) upgradeSingleComponent(spiCtx spi.ComponentContext, upgrade
 	compLog := compContext.Log()
 
 	for upgradeContext.state != compStateEnd {
+		// Fail the component upgrade if it is still running after the upgrade timeout
+		switch upgradeContext.state {
+		case compStatePreUpgrade, compStateUpgrade, compStateWaitReady, compStatePostUpgrade:
+			if upgradeContext.timeout > 0 && time.Since(upgradeContext.startTime) > upgradeContext.timeout {
+				upgradeContext.failedMessage = getTimedOutMessage(compContext.ActualCR(), compName, "Upgrade", upgradeContext.timeout)
+				compLog.Errorf("Component %s failed: %s", compName, upgradeContext.failedMessage)
+				upgradeContext.state = compStateFailed
+				return ctrl.Result{}, nil
+			}
+		}
+
 		switch upgradeContext.state {
 		case compStateInit:
 			// Check if component is installed, if not continue
@@ -84,6 +178,14 @@ func (r *Reconciler) upgradeSingleComponent(spiCtx spi.ComponentContext, upgrade
 			}
 			if installed {
 				compLog.Oncef("Component %s is installed and will be upgraded", compName)
+				if isRollbackOnFailure(compContext.ActualCR()) {
+					if err := recordPreviousRelease(compContext, upgradeContext, comp); err != nil {
+						compLog.Errorf("Failed recording the release of component %s before the upgrade: %v", compName, err)
+						return ctrl.Result{}, err
+					}
+				}
+				upgradeContext.startTime = time.Now()
+				upgradeContext.timeout = getUpgradeTimeout(compContext.EffectiveCR(), comp)
 				upgradeContext.state = compStatePreUpgrade
 			} else {
 				compLog.Oncef("Component %s is not installed; upgrade being skipped", compName)
@@ -92,25 +194,42 @@ func (r *Reconciler) upgradeSingleComponent(spiCtx spi.ComponentContext, upgrade
 
 		case compStatePreUpgrade:
 			compLog.Oncef("Component %s pre-upgrade running", compName)
-			if err := comp.PreUpgrade(compContext); err != nil {
+			if err := metrics.TimeOperation(compName, metrics.PreUpgradeOperation, func() error { return comp.PreUpgrade(compContext) }); err != nil {
+				r.recordOperationError(compContext.ActualCR(), compName, metrics.PreUpgradeOperation, err)
+				if failUpgradeAttempt(compContext, upgradeContext, "pre-upgrading", err) {
+					return ctrl.Result{}, nil
+				}
 				compLog.Errorf("Failed pre-upgrading component %s: %v", compName, err)
 				return ctrl.Result{}, err
 			}
+			upgradeContext.failedAttempts = 0
 			upgradeContext.state = compStateUpgrade
 
 		case compStateUpgrade:
 			compLog.Progressf("Component %s upgrade running", compName)
-			if err := comp.Upgrade(compContext); err != nil {
+			upgradeContext.upgradeAttempted = true
+			if err := metrics.TimeOperation(compName, metrics.UpgradeOperation, func() error { return comp.Upgrade(compContext) }); err != nil {
+				r.recordOperationError(compContext.ActualCR(), compName, metrics.UpgradeOperation, err)
+				if failUpgradeAttempt(compContext, upgradeContext, "upgrading", err) {
+					return ctrl.Result{}, nil
+				}
 				compLog.Errorf("Failed upgrading component %s, will retry: %v", compName, err)
 				// check to see whether this is due to a pending upgrade
 				r.resolvePendingUpgrades(compName, compLog)
 				// requeue for 30 to 60 seconds later
 				return controller.NewRequeueWithDelay(30, 60, time.Second), nil
 			}
+			upgradeContext.failedAttempts = 0
+			upgradeContext.readyStartTime = time.Now()
 			upgradeContext.state = compStateWaitReady
 
 		case compStateWaitReady:
 			if !comp.IsReady(compContext) {
+				if isReadyTimedOut(compContext, upgradeContext) {
+					compLog.Errorf("Component %s is not ready %v after being upgraded, the upgrade will be rolled back", compName, rollbackReadyTimeout)
+					upgradeContext.state = compStateFailed
+					return ctrl.Result{}, nil
+				}
 				compLog.Progressf("Component %s has been upgraded. Waiting for the component to be ready", compName)
 				return newRequeueWithDelay(), nil
 			}
@@ -119,14 +238,23 @@ func (r *Reconciler) upgradeSingleComponent(spiCtx spi.ComponentContext, upgrade
 
 		case compStatePostUpgrade:
 			compLog.Oncef("Component %s post-upgrade running", compName)
-			if err := comp.PostUpgrade(compContext); err != nil {
+			if err := metrics.TimeOperation(compName, metrics.PostUpgradeOperation, func() error { return comp.PostUpgrade(compContext) }); err != nil {
+				r.recordOperationError(compContext.ActualCR(), compName, metrics.PostUpgradeOperation, err)
+				if failUpgradeAttempt(compContext, upgradeContext, "post-upgrading", err) {
+					return ctrl.Result{}, nil
+				}
 				return ctrl.Result{}, err
 			}
+			upgradeContext.failedAttempts = 0
 			upgradeContext.state = compStateUpgradeDone
 
 		case compStateUpgradeDone:
 			compLog.Oncef("Component %s has successfully upgraded", compName)
 			upgradeContext.state = compStateEnd
+
+		case compStateFailed:
+			// The upgrade is rolled back once the components being upgraded have stopped
+			return ctrl.Result{}, nil
 		}
 	}
 	// Component has been upgraded
@@ -0,0 +1,186 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// isRollbackOnFailure returns true if a failed upgrade is rolled back
+func isRollbackOnFailure(cr *installv1alpha1.Verrazzano) bool {
+	return cr.Spec.UpgradePolicy != nil && cr.Spec.UpgradePolicy.RollbackOnFailure
+}
+
+// recordPreviousRelease records the release of the component before it is upgraded.  A release already saved in the
+// component status is used, since the component may have been upgraded before the operator was restarted.
+func recordPreviousRelease(compContext spi.ComponentContext, upgradeContext *componentUpgradeContext, comp spi.Component) error {
+	if upgradeContext.previousRelease != nil {
+		return nil
+	}
+	cr := compContext.ActualCR()
+	if compStatus, ok := cr.Status.Components[comp.Name()]; ok && compStatus.PreviousRelease != nil {
+		upgradeContext.previousRelease = compStatus.PreviousRelease.DeepCopy()
+		upgradeContext.previousReleaseSaved = true
+		return nil
+	}
+	release := &installv1alpha1.ComponentRelease{}
+	if rollbacker, ok := comp.(spi.ComponentRollbacker); ok {
+		revision, err := rollbacker.GetReleaseRevision(compContext)
+		if err != nil {
+			return err
+		}
+		version, err := rollbacker.GetReleaseVersion(compContext)
+		if err != nil {
+			return err
+		}
+		release.HelmRevision = revision
+		release.Version = version
+	}
+	upgradeContext.previousRelease = release
+	return nil
+}
+
+// savePreviousReleases saves the recorded releases in the component status, so the upgrade can be rolled back after
+// the operator is restarted
+func (r *Reconciler) savePreviousReleases(log vzlog.VerrazzanoLogger, cr *installv1alpha1.Verrazzano, tracker *upgradeTracker) error {
+	var saved []*componentUpgradeContext
+	for compName, upgradeContext := range tracker.compMap {
+		if upgradeContext.previousRelease == nil || upgradeContext.previousReleaseSaved {
+			continue
+		}
+		compStatus, ok := cr.Status.Components[compName]
+		if !ok {
+			continue
+		}
+		compStatus.PreviousRelease = upgradeContext.previousRelease.DeepCopy()
+		saved = append(saved, upgradeContext)
+	}
+	if len(saved) == 0 {
+		return nil
+	}
+	if err := r.updateVerrazzanoStatus(log, cr); err != nil {
+		return err
+	}
+	for _, upgradeContext := range saved {
+		upgradeContext.previousReleaseSaved = true
+	}
+	return nil
+}
+
+// failUpgradeAttempt counts a failed attempt of a component upgrade step.  The component upgrade fails once the step has
+// failed maxUpgradeAttempts times in a row and the upgrade is rolled back on failure, in which case true is returned.
+func failUpgradeAttempt(compContext spi.ComponentContext, upgradeContext *componentUpgradeContext, step string, err error) bool {
+	upgradeContext.failedAttempts++
+	if !isRollbackOnFailure(compContext.ActualCR()) || upgradeContext.failedAttempts < maxUpgradeAttempts {
+		return false
+	}
+	compContext.Log().Errorf("Failed %s component %s after %d attempts, the upgrade will be rolled back: %v",
+		step, compContext.GetComponent(), upgradeContext.failedAttempts, err)
+	upgradeContext.state = compStateFailed
+	return true
+}
+
+// isReadyTimedOut returns true if the upgrade is rolled back on failure and the component has not been ready for
+// rollbackReadyTimeout after being upgraded.  The upgrade timeout of the component is used instead if it is set.
+func isReadyTimedOut(compContext spi.ComponentContext, upgradeContext *componentUpgradeContext) bool {
+	return isRollbackOnFailure(compContext.ActualCR()) && upgradeContext.timeout == 0 &&
+		time.Since(upgradeContext.readyStartTime) > rollbackReadyTimeout
+}
+
+// upgradeFailed returns true if a component upgrade has failed and the upgrade needs to be rolled back
+func (vuc *upgradeTracker) upgradeFailed() bool {
+	return len(vuc.failedComponents()) > 0
+}
+
+// failedComponents returns the sorted names of the components that failed to upgrade
+func (vuc *upgradeTracker) failedComponents() []string {
+	var names []string
+	for compName, upgradeContext := range vuc.compMap {
+		if upgradeContext.state == compStateFailed {
+			names = append(names, compName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rollbackComponents rolls the components that were upgraded back to their previous release, in the reverse of the
+// install order so that a component is rolled back before any of the components it depends on
+func (r *Reconciler) rollbackComponents(log vzlog.VerrazzanoLogger, cr *installv1alpha1.Verrazzano, tracker *upgradeTracker) (ctrl.Result, error) {
+	spiCtx, err := spi.NewContext(log, r.Client, cr, r.DryRun)
+	if err != nil {
+		return newRequeueWithDelay(), err
+	}
+	components, err := registry.GetComponentsInUninstallOrder()
+	if err != nil {
+		log.Errorf("Failed to get the component rollback order: %v", err)
+		return newRequeueWithDelay(), err
+	}
+	for _, comp := range components {
+		compName := comp.Name()
+		upgradeContext, ok := tracker.compMap[compName]
+		if !ok || !upgradeContext.upgradeAttempted || upgradeContext.rolledBack || upgradeContext.previousRelease == nil {
+			continue
+		}
+		compContext := spiCtx.Init(compName).Operation(vzconst.UpgradeOperation)
+		if err := metrics.TimeOperation(compName, metrics.RollbackOperation, func() error {
+			return rollbackComponent(compContext, comp, upgradeContext.previousRelease)
+		}); err != nil {
+			r.recordOperationError(cr, compName, metrics.RollbackOperation, err)
+			compContext.Log().Errorf("Failed rolling back component %s: %v", compName, err)
+			return newRequeueWithDelay(), err
+		}
+		upgradeContext.rolledBack = true
+	}
+	return ctrl.Result{}, nil
+}
+
+// rollbackComponent rolls a single component back to the previous release if the release has changed.  Components
+// that can't be rolled back are left as they are.
+func rollbackComponent(compContext spi.ComponentContext, comp spi.Component, previousRelease *installv1alpha1.ComponentRelease) error {
+	compName := comp.Name()
+	rollbacker, ok := comp.(spi.ComponentRollbacker)
+	if !ok || previousRelease.HelmRevision == 0 {
+		compContext.Log().Oncef("Component %s does not support rollback and is left at the upgraded release", compName)
+		return nil
+	}
+	revision, err := rollbacker.GetReleaseRevision(compContext)
+	if err != nil {
+		return err
+	}
+	if revision == previousRelease.HelmRevision {
+		compContext.Log().Oncef("Component %s is already at revision %d", compName, revision)
+		return nil
+	}
+	compContext.Log().Oncef("Rolling back component %s to revision %d of version %s", compName, previousRelease.HelmRevision, previousRelease.Version)
+	return rollbacker.Rollback(compContext, previousRelease.HelmRevision)
+}
+
+// updateRolledBackStatus clears the previous releases from the component status and sets the rolled back condition
+func (r *Reconciler) updateRolledBackStatus(log vzlog.VerrazzanoLogger, cr *installv1alpha1.Verrazzano, tracker *upgradeTracker) error {
+	var rolledBack []string
+	for compName, upgradeContext := range tracker.compMap {
+		if upgradeContext.rolledBack {
+			rolledBack = append(rolledBack, compName)
+		}
+	}
+	sort.Strings(rolledBack)
+	for _, compStatus := range cr.Status.Components {
+		compStatus.PreviousRelease = nil
+	}
+	msg := fmt.Sprintf("Verrazzano upgrade to version %s failed upgrading %s, rolled back %s to version %s",
+		cr.Spec.Version, strings.Join(tracker.failedComponents(), ", "), strings.Join(rolledBack, ", "), cr.Status.Version)
+	return r.updateStatus(log, cr, msg, installv1alpha1.CondUpgradeRolledBack)
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeReleases tracks the Helm release revisions of the fake rollback components
+type fakeReleases struct {
+	lock       sync.Mutex
+	revisions  map[string]int
+	rolledBack []string
+}
+
+// fakeRollbackComponent is a component with a Helm release that can be rolled back
+type fakeRollbackComponent struct {
+	fakeComponent
+	releases       *fakeReleases
+	postUpgradeErr error
+}
+
+func (f fakeRollbackComponent) GetReleaseRevision(_ spi.ComponentContext) (int, error) {
+	f.releases.lock.Lock()
+	defer f.releases.lock.Unlock()
+	return f.releases.revisions[f.Name()], nil
+}
+
+// GetReleaseVersion returns a chart version that is incremented with the release revision
+func (f fakeRollbackComponent) GetReleaseVersion(_ spi.ComponentContext) (string, error) {
+	f.releases.lock.Lock()
+	defer f.releases.lock.Unlock()
+	return fmt.Sprintf("%d.0.0", f.releases.revisions[f.Name()]), nil
+}
+
+func (f fakeRollbackComponent) Rollback(_ spi.ComponentContext, revision int) error {
+	f.releases.lock.Lock()
+	defer f.releases.lock.Unlock()
+	f.releases.revisions[f.Name()] = revision
+	f.releases.rolledBack = append(f.releases.rolledBack, f.Name())
+	return nil
+}
+
+func (f fakeRollbackComponent) PostUpgrade(_ spi.ComponentContext) error {
+	return f.postUpgradeErr
+}
+
+// newFakeRollbackComponent returns a component that creates a new release revision when it is upgraded, the upgrade
+// fails if failUpgrade is true
+func newFakeRollbackComponent(releases *fakeReleases, name string, failUpgrade bool, dependencies ...string) fakeRollbackComponent {
+	releases.revisions[name] = 1
+	return fakeRollbackComponent{
+		fakeComponent: fakeComponent{
+			HelmComponent: helm.HelmComponent{
+				ReleaseName:             name,
+				Dependencies:            dependencies,
+				SupportsOperatorInstall: true,
+			},
+			upgradeFunc: func(ctx spi.ComponentContext) error {
+				releases.lock.Lock()
+				defer releases.lock.Unlock()
+				releases.revisions[name]++
+				if failUpgrade {
+					return fmt.Errorf("Error running upgrade")
+				}
+				return nil
+			},
+		},
+		releases: releases,
+	}
+}
+
+// newRollbackTestVerrazzano returns a Verrazzano resource that is being upgraded
+func newRollbackTestVerrazzano(rollbackOnFailure bool, compNames ...string) *vzapi.Verrazzano {
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-rollback", Generation: 1},
+		Spec: vzapi.VerrazzanoSpec{
+			Version:       "1.2.0",
+			UpgradePolicy: &vzapi.UpgradePolicy{RollbackOnFailure: rollbackOnFailure},
+		},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateUpgrading,
+			Version:    "1.0.0",
+			Conditions: []vzapi.Condition{{Type: vzapi.CondUpgradeStarted}},
+			Components: vzapi.ComponentStatusMap{},
+		},
+	}
+	for _, compName := range compNames {
+		vz.Status.Components[compName] = &vzapi.ComponentStatusDetails{Name: compName, State: vzapi.CompStateReady}
+	}
+	return vz
+}
+
+// reconcileRollbackLoop runs reconcileUpgrade with a fresh copy of the Verrazzano resource until it is no longer
+// requeued, or the number of reconciles is reached
+func reconcileRollbackLoop(reconciler Reconciler, c client.Client, count int) (*vzapi.Verrazzano, error) {
+	vz := &vzapi.Verrazzano{}
+	for i := 0; i < count; i++ {
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test-rollback"}, vz); err != nil {
+			return nil, err
+		}
+		result, err := reconciler.reconcileUpgrade(vzlog.DefaultLogger(), vz)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Requeue || vz.Status.State == vzapi.VzStateFailed {
+			break
+		}
+	}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test-rollback"}, vz)
+	return vz, err
+}
+
+// TestUpgradeRollbackOnFailure tests the reconcileUpgrade method for the following use case
+// GIVEN a Verrazzano resource with rollbackOnFailure set
+// WHEN a component fails to upgrade after its dependencies have been upgraded
+// THEN the upgraded components are rolled back in the reverse order and the UpgradeRolledBack condition is set
+func TestUpgradeRollbackOnFailure(t *testing.T) {
+	asserts := assert.New(t)
+	config.SetDefaultBomFilePath(unitTestBomFile)
+	defer config.SetDefaultBomFilePath("")
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	releases := &fakeReleases{revisions: map[string]int{}}
+	components := []spi.Component{
+		newFakeRollbackComponent(releases, "a", false),
+		newFakeRollbackComponent(releases, "b", false, "a"),
+		newFakeRollbackComponent(releases, "c", true, "b"),
+		newFakeRollbackComponent(releases, "d", false, "c"),
+	}
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return components
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := newRollbackTestVerrazzano(true, "a", "b", "c", "d")
+	defer deleteUpgradeTracker(vz)
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+
+	// The release of each component is saved before it is upgraded
+	vz, err := reconcileRollbackLoop(reconciler, c, 1)
+	asserts.NoError(err)
+	asserts.Equal(&vzapi.ComponentRelease{Version: "1.0.0", HelmRevision: 1}, vz.Status.Components["a"].PreviousRelease)
+
+	vz, err = reconcileRollbackLoop(reconciler, c, 20)
+	asserts.NoError(err)
+	asserts.Equal([]string{"c", "b", "a"}, releases.rolledBack)
+	asserts.Equal(map[string]int{"a": 1, "b": 1, "c": 1, "d": 1}, releases.revisions)
+	asserts.Equal(vzapi.VzStateFailed, vz.Status.State)
+	asserts.Equal("1.0.0", vz.Status.Version)
+	asserts.True(isLastCondition(vz.Status, vzapi.CondUpgradeRolledBack))
+	asserts.Contains(vz.Status.Conditions[len(vz.Status.Conditions)-1].Message, "failed upgrading c, rolled back a, b, c to version 1.0.0")
+	for _, compStatus := range vz.Status.Components {
+		asserts.Nil(compStatus.PreviousRelease)
+	}
+	_, ok := upgradeTrackerMap[getNSNKey(vz)]
+	asserts.False(ok)
+}
+
+// TestUpgradeNoRollbackOnFailure tests the reconcileUpgrade method for the following use case
+// GIVEN a Verrazzano resource without rollbackOnFailure set
+// WHEN a component fails to upgrade
+// THEN the upgrade is retried and nothing is rolled back
+func TestUpgradeNoRollbackOnFailure(t *testing.T) {
+	asserts := assert.New(t)
+	config.SetDefaultBomFilePath(unitTestBomFile)
+	defer config.SetDefaultBomFilePath("")
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	releases := &fakeReleases{revisions: map[string]int{}}
+	components := []spi.Component{
+		newFakeRollbackComponent(releases, "a", false),
+		newFakeRollbackComponent(releases, "b", true, "a"),
+	}
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return components
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := newRollbackTestVerrazzano(false, "a", "b")
+	defer deleteUpgradeTracker(vz)
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+
+	vz, err := reconcileRollbackLoop(newVerrazzanoReconciler(c), c, 2*maxUpgradeAttempts)
+	asserts.NoError(err)
+	asserts.Empty(releases.rolledBack)
+	asserts.Equal(vzapi.VzStateUpgrading, vz.Status.State)
+	asserts.Nil(vz.Status.Components["a"].PreviousRelease)
+	asserts.True(upgradeTrackerMap[getNSNKey(vz)].compMap["b"].failedAttempts > maxUpgradeAttempts)
+}
+
+// TestRecordPreviousRelease tests the recordPreviousRelease func
+// GIVEN a component that is about to be upgraded
+// WHEN the previous release is recorded, and when it has already been saved in the component status
+// THEN the chart version and revision of the current release are recorded, or the saved release is used
+func TestRecordPreviousRelease(t *testing.T) {
+	asserts := assert.New(t)
+	releases := &fakeReleases{revisions: map[string]int{}}
+	comp := newFakeRollbackComponent(releases, "a", false)
+	releases.revisions["a"] = 3
+
+	vz := newRollbackTestVerrazzano(true, "a")
+	ctx := spi.NewFakeContext(nil, vz, false)
+	upgradeContext := &componentUpgradeContext{state: compStateInit}
+	asserts.NoError(recordPreviousRelease(ctx, upgradeContext, comp))
+	asserts.Equal(&vzapi.ComponentRelease{Version: "3.0.0", HelmRevision: 3}, upgradeContext.previousRelease)
+	asserts.False(upgradeContext.previousReleaseSaved)
+
+	vz.Status.Components["a"].PreviousRelease = &vzapi.ComponentRelease{Version: "0.9.0", HelmRevision: 2}
+	upgradeContext = &componentUpgradeContext{state: compStateInit}
+	asserts.NoError(recordPreviousRelease(ctx, upgradeContext, comp))
+	asserts.Equal(&vzapi.ComponentRelease{Version: "0.9.0", HelmRevision: 2}, upgradeContext.previousRelease)
+	asserts.True(upgradeContext.previousReleaseSaved)
+}
+
+// TestUpgradeStepFailure tests the upgradeSingleComponent method for the following use case
+// GIVEN a Verrazzano resource with rollbackOnFailure set
+// WHEN the post-upgrade of a component keeps failing, or the component is not ready after being upgraded
+// THEN the component upgrade fails so that the upgrade is rolled back
+func TestUpgradeStepFailure(t *testing.T) {
+	asserts := assert.New(t)
+	releases := &fakeReleases{revisions: map[string]int{}}
+	comp := newFakeRollbackComponent(releases, "a", false)
+	comp.postUpgradeErr = fmt.Errorf("Error running post-upgrade")
+
+	vz := newRollbackTestVerrazzano(true, "a")
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+	ctx := spi.NewFakeContext(c, vz, false)
+
+	// The post-upgrade is retried until it has failed maxUpgradeAttempts times
+	upgradeContext := &componentUpgradeContext{state: compStatePostUpgrade}
+	for i := 1; i < maxUpgradeAttempts; i++ {
+		_, err := reconciler.upgradeSingleComponent(ctx, upgradeContext, comp)
+		asserts.Error(err)
+		asserts.Equal(compStatePostUpgrade, upgradeContext.state)
+	}
+	_, err := reconciler.upgradeSingleComponent(ctx, upgradeContext, comp)
+	asserts.NoError(err)
+	asserts.Equal(compStateFailed, upgradeContext.state)
+
+	// The component is waiting to be ready
+	comp.ready = "false"
+	upgradeContext = &componentUpgradeContext{state: compStateWaitReady, readyStartTime: time.Now()}
+	result, err := reconciler.upgradeSingleComponent(ctx, upgradeContext, comp)
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.Equal(compStateWaitReady, upgradeContext.state)
+
+	// The component is not ready after rollbackReadyTimeout
+	upgradeContext.readyStartTime = time.Now().Add(-rollbackReadyTimeout - time.Minute)
+	_, err = reconciler.upgradeSingleComponent(ctx, upgradeContext, comp)
+	asserts.NoError(err)
+	asserts.Equal(compStateFailed, upgradeContext.state)
+
+	// The component keeps waiting to be ready if the upgrade is not rolled back on failure
+	vz.Spec.UpgradePolicy.RollbackOnFailure = false
+	upgradeContext = &componentUpgradeContext{state: compStateWaitReady, readyStartTime: time.Now().Add(-rollbackReadyTimeout - time.Minute)}
+	result, err = reconciler.upgradeSingleComponent(ctx, upgradeContext, comp)
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.Equal(compStateWaitReady, upgradeContext.state)
+}
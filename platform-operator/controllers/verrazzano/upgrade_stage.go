@@ -0,0 +1,166 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// maxSoakCheckInterval is the longest time between the health checks of the components while a stage is soaking
+const maxSoakCheckInterval = 15 * time.Second
+
+// upgradeStage is a group of components that are upgraded together, see UpgradePolicy.Stages
+type upgradeStage struct {
+	name            string
+	components      map[string]bool
+	requireApproval bool
+	soakTime        time.Duration
+}
+
+// getUpgradeStages returns the stages the components are upgraded in.  The components that are not in a stage of
+// the upgrade policy are upgraded in a final stage, which is the only stage if the upgrade policy has no stages.
+func getUpgradeStages(cr *installv1alpha1.Verrazzano) []upgradeStage {
+	var stages []upgradeStage
+	staged := map[string]bool{}
+	if cr.Spec.UpgradePolicy != nil && len(cr.Spec.UpgradePolicy.Stages) > 0 {
+		namesByJSONName := map[string]string{}
+		for _, comp := range registry.GetComponents() {
+			namesByJSONName[comp.GetJSONName()] = comp.Name()
+		}
+		for _, policyStage := range cr.Spec.UpgradePolicy.Stages {
+			stage := upgradeStage{
+				name:            policyStage.Name,
+				components:      map[string]bool{},
+				requireApproval: policyStage.RequireApproval,
+			}
+			if policyStage.SoakTime != nil {
+				stage.soakTime = policyStage.SoakTime.Duration
+			}
+			for _, jsonName := range policyStage.Components {
+				if compName, ok := namesByJSONName[jsonName]; ok {
+					stage.components[compName] = true
+					staged[compName] = true
+				}
+			}
+			stages = append(stages, stage)
+		}
+	}
+
+	finalStage := upgradeStage{components: map[string]bool{}}
+	for _, comp := range registry.GetComponents() {
+		if !staged[comp.Name()] {
+			finalStage.components[comp.Name()] = true
+		}
+	}
+	if len(finalStage.components) > 0 || len(stages) == 0 {
+		stages = append(stages, finalStage)
+	}
+	return stages
+}
+
+// getStageIndex returns the index of the stage that upgrades the component
+func getStageIndex(stages []upgradeStage, compName string) int {
+	for i, stage := range stages {
+		if stage.components[compName] {
+			return i
+		}
+	}
+	return len(stages) - 1
+}
+
+// getStageApproval returns the annotation value that approves the stage for the upgrade to the target version
+func getStageApproval(cr *installv1alpha1.Verrazzano, stageName string) string {
+	return fmt.Sprintf("%s/%s", cr.Spec.Version, stageName)
+}
+
+// isStageApproved returns true if the stage, or a stage after it, has been approved with the annotation.  The approval
+// must name the target version of the upgrade, so an approval left over from an earlier upgrade is ignored.
+func isStageApproved(cr *installv1alpha1.Verrazzano, stages []upgradeStage, stageIndex int) bool {
+	approved, ok := cr.Annotations[vzconst.UpgradeApprovedStage]
+	if !ok {
+		return false
+	}
+	for i := stageIndex; i < len(stages); i++ {
+		if len(stages[i].name) > 0 && getStageApproval(cr, stages[i].name) == approved {
+			return true
+		}
+	}
+	return false
+}
+
+// getPausedUpgradeStage returns the stage that a paused upgrade is waiting to start, and true if the stage has been
+// approved.  Nil is returned if the upgrade is not paused before a stage that requires approval.
+func getPausedUpgradeStage(cr *installv1alpha1.Verrazzano) (*upgradeStage, bool) {
+	tracker, ok := upgradeTrackerMap[getNSNKey(cr)]
+	if !ok || tracker.gen != cr.Generation || tracker.vzState != vzStateStageGate {
+		return nil, false
+	}
+	stages := getUpgradeStages(cr)
+	if tracker.stage >= len(stages) || !stages[tracker.stage].requireApproval {
+		return nil, false
+	}
+	return &stages[tracker.stage], isStageApproved(cr, stages, tracker.stage)
+}
+
+// checkStageGate returns a requeue result until the next stage of the upgrade can be started.  The components
+// upgraded by the previous stages must be ready for the soak time of the stage, then the upgrade is paused until the
+// stage is approved if approval is required.
+func (r *Reconciler) checkStageGate(log vzlog.VerrazzanoLogger, cr *installv1alpha1.Verrazzano, tracker *upgradeTracker) (ctrl.Result, error) {
+	stages := getUpgradeStages(cr)
+	if tracker.stage >= len(stages) {
+		return ctrl.Result{}, nil
+	}
+	stage := stages[tracker.stage]
+
+	spiCtx, err := spi.NewContext(log, r.Client, cr, r.DryRun)
+	if err != nil {
+		return newRequeueWithDelay(), err
+	}
+	for _, comp := range registry.GetComponents() {
+		if getStageIndex(stages, comp.Name()) >= tracker.stage {
+			continue
+		}
+		compContext := spiCtx.Init(comp.Name()).Operation(vzconst.UpgradeOperation)
+		installed, err := comp.IsInstalled(compContext)
+		if err != nil {
+			return newRequeueWithDelay(), err
+		}
+		if installed && !comp.IsReady(compContext) {
+			// The soak time starts again once all the components are ready
+			tracker.stageReadyTime = time.Time{}
+			log.Progressf("Waiting for component %s to be ready before starting upgrade stage %s", comp.Name(), stage.name)
+			return newRequeueWithDelay(), nil
+		}
+	}
+	if tracker.stageReadyTime.IsZero() {
+		tracker.stageReadyTime = time.Now()
+	}
+	if remaining := stage.soakTime - time.Since(tracker.stageReadyTime); remaining > 0 {
+		log.Progressf("Waiting for the upgraded components to be ready for %v before starting upgrade stage %s", stage.soakTime, stage.name)
+		if remaining > maxSoakCheckInterval {
+			remaining = maxSoakCheckInterval
+		}
+		return ctrl.Result{Requeue: true, RequeueAfter: remaining}, nil
+	}
+
+	if stage.requireApproval && !isStageApproved(cr, stages, tracker.stage) {
+		msg := fmt.Sprintf("Verrazzano upgrade to version %s paused. Upgrade stage %s will be started when it is approved with the %s annotation set to %s",
+			cr.Spec.Version, stage.name, vzconst.UpgradeApprovedStage, getStageApproval(cr, stage.name))
+		log.Progress(msg)
+		if !isLastCondition(cr.Status, installv1alpha1.CondUpgradePaused) {
+			return newRequeueWithDelay(), r.updateStatus(log, cr, msg, installv1alpha1.CondUpgradePaused)
+		}
+		return newRequeueWithDelay(), nil
+	}
+	log.Oncef("Starting upgrade stage %s", stage.name)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	oam "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/pkg/k8sutil"
+	"github.com/verrazzano/verrazzano/pkg/log/vzlog"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	vzconst "github.com/verrazzano/verrazzano/platform-operator/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
+	vzcontext "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/context"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newFakeStagedComponent returns a component that records when it is upgraded
+func newFakeStagedComponent(lock *sync.Mutex, upgraded *[]string, name string, ready string, dependencies ...string) fakeComponent {
+	return fakeComponent{
+		HelmComponent: helm.HelmComponent{
+			ReleaseName:             name,
+			JSONName:                name,
+			Dependencies:            dependencies,
+			SupportsOperatorInstall: true,
+		},
+		ready: ready,
+		upgradeFunc: func(ctx spi.ComponentContext) error {
+			lock.Lock()
+			defer lock.Unlock()
+			*upgraded = append(*upgraded, name)
+			return nil
+		},
+	}
+}
+
+// newStagedTestVerrazzano returns a Verrazzano resource that is being upgraded in stages
+func newStagedTestVerrazzano(stages ...vzapi.UpgradeStage) *vzapi.Verrazzano {
+	return &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test-staged", Generation: 1},
+		Spec: vzapi.VerrazzanoSpec{
+			Version:       "1.2.0",
+			UpgradePolicy: &vzapi.UpgradePolicy{Stages: stages},
+		},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateUpgrading,
+			Version:    "1.0.0",
+			Conditions: []vzapi.Condition{{Type: vzapi.CondInstallComplete}, {Type: vzapi.CondUpgradeStarted}},
+			Components: vzapi.ComponentStatusMap{},
+		},
+	}
+}
+
+// reconcileStagedUpgrade runs reconcileUpgrade with a fresh copy of the Verrazzano resource while the upgrade is in
+// progress, or until the number of reconciles is reached
+func reconcileStagedUpgrade(reconciler Reconciler, c client.Client, count int) (*vzapi.Verrazzano, error) {
+	nsn := types.NamespacedName{Namespace: "verrazzano", Name: "test-staged"}
+	vz := &vzapi.Verrazzano{}
+	for i := 0; i < count; i++ {
+		if err := c.Get(context.TODO(), nsn, vz); err != nil {
+			return nil, err
+		}
+		if vz.Status.State != vzapi.VzStateUpgrading {
+			break
+		}
+		result, err := reconciler.reconcileUpgrade(vzlog.DefaultLogger(), vz)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Requeue {
+			break
+		}
+	}
+	err := c.Get(context.TODO(), nsn, vz)
+	return vz, err
+}
+
+// TestStagedUpgrade tests the reconcileUpgrade method for the following use case
+// GIVEN a Verrazzano resource with upgrade stages
+// WHEN the upgrade reaches a stage that requires approval
+// THEN the upgrade is paused until the stage is approved, and the components are upgraded in the order of the stages
+func TestStagedUpgrade(t *testing.T) {
+	asserts := assert.New(t)
+	config.SetDefaultBomFilePath(unitTestBomFile)
+	defer config.SetDefaultBomFilePath("")
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	var lock sync.Mutex
+	var upgraded []string
+	// Component a depends on component c in the same stage, it is upgraded after c
+	components := []spi.Component{
+		newFakeStagedComponent(&lock, &upgraded, "c", "true"),
+		newFakeStagedComponent(&lock, &upgraded, "a", "true", "c"),
+		newFakeStagedComponent(&lock, &upgraded, "b", "true"),
+	}
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return components
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := newStagedTestVerrazzano(
+		vzapi.UpgradeStage{Name: "first", Components: []string{"a", "c"}},
+		vzapi.UpgradeStage{Name: "second", Components: []string{"b"}, RequireApproval: true},
+	)
+	defer deleteUpgradeTracker(vz)
+	_ = vzapi.AddToScheme(k8scheme.Scheme)
+	_ = oam.SchemeBuilder.AddToScheme(k8scheme.Scheme)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(vz).Build()
+	reconciler := newVerrazzanoReconciler(c)
+
+	// Setup fake client to provide workloads for restart platform testing
+	goClient, err := initFakeClient()
+	asserts.NoError(err)
+	k8sutil.SetFakeClient(goClient)
+	defer k8sutil.ClearFakeClient()
+
+	// The upgrade is paused before the second stage
+	vz, err = reconcileStagedUpgrade(reconciler, c, 20)
+	asserts.NoError(err)
+	asserts.Equal([]string{"c", "a"}, upgraded)
+	asserts.Equal(vzapi.VzStatePaused, vz.Status.State)
+	asserts.True(isLastCondition(vz.Status, vzapi.CondUpgradePaused))
+
+	// The upgrade stays paused until the stage is approved
+	vzctx := vzcontext.VerrazzanoContext{Log: vzlog.DefaultLogger(), Client: c, ActualCR: vz}
+	_, err = reconciler.ProcPausedUpgradeState(vzctx)
+	asserts.NoError(err)
+	asserts.Equal(vzapi.VzStatePaused, vz.Status.State)
+
+	// An approval left over from an earlier upgrade does not approve the stage
+	vz.Annotations = map[string]string{vzconst.UpgradeApprovedStage: "1.1.0/second"}
+	asserts.NoError(c.Update(context.TODO(), vz))
+	_, err = reconciler.ProcPausedUpgradeState(vzctx)
+	asserts.NoError(err)
+	asserts.Equal(vzapi.VzStatePaused, vz.Status.State)
+
+	vz.Annotations = map[string]string{vzconst.UpgradeApprovedStage: "1.2.0/second"}
+	asserts.NoError(c.Update(context.TODO(), vz))
+	_, err = reconciler.ProcPausedUpgradeState(vzctx)
+	asserts.NoError(err)
+	asserts.Equal(vzapi.VzStateUpgrading, vz.Status.State)
+
+	// The remaining stages are upgraded
+	vz, err = reconcileStagedUpgrade(reconciler, c, 20)
+	asserts.NoError(err)
+	asserts.Equal([]string{"c", "a", "b"}, upgraded)
+	asserts.Equal("1.2.0", vz.Status.Version)
+}
+
+// TestIsStageApproved tests the isStageApproved method
+// GIVEN an upgrade with stages
+// WHEN the approval annotation names a stage of the target version, or of an earlier version
+// THEN the stage and the stages before it are approved only for the target version
+func TestIsStageApproved(t *testing.T) {
+	vz := newStagedTestVerrazzano()
+	stages := []upgradeStage{{name: "first"}, {name: "second"}, {name: "third"}}
+
+	assert.False(t, isStageApproved(vz, stages, 1))
+
+	vz.Annotations = map[string]string{vzconst.UpgradeApprovedStage: "1.2.0/second"}
+	assert.True(t, isStageApproved(vz, stages, 0))
+	assert.True(t, isStageApproved(vz, stages, 1))
+	assert.False(t, isStageApproved(vz, stages, 2))
+
+	// An approval left over from the upgrade to an earlier version is ignored
+	vz.Annotations = map[string]string{vzconst.UpgradeApprovedStage: "1.1.0/third"}
+	assert.False(t, isStageApproved(vz, stages, 1))
+	vz.Annotations = map[string]string{vzconst.UpgradeApprovedStage: "second"}
+	assert.False(t, isStageApproved(vz, stages, 1))
+}
+
+// TestCheckStageGateSoak tests the checkStageGate method for the following use case
+// GIVEN a stage with a soak time
+// WHEN the components of the previous stages are not ready, or have not been ready for the soak time
+// THEN the next stage is not started
+func TestCheckStageGateSoak(t *testing.T) {
+	asserts := assert.New(t)
+	config.SetDefaultBomFilePath(unitTestBomFile)
+	defer config.SetDefaultBomFilePath("")
+	config.TestProfilesDir = "../../manifests/profiles"
+	defer func() { config.TestProfilesDir = "" }()
+
+	var lock sync.Mutex
+	var upgraded []string
+	ready := "false"
+	registry.OverrideGetComponentsFn(func() []spi.Component {
+		return []spi.Component{
+			newFakeStagedComponent(&lock, &upgraded, "a", ready),
+			newFakeStagedComponent(&lock, &upgraded, "b", "true"),
+		}
+	})
+	defer registry.ResetGetComponentsFn()
+
+	vz := newStagedTestVerrazzano(
+		vzapi.UpgradeStage{Name: "first", Components: []string{"a"}},
+		vzapi.UpgradeStage{Name: "second", Components: []string{"b"}, SoakTime: &metav1.Duration{Duration: time.Hour}},
+	)
+	reconciler := newVerrazzanoReconciler(fake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build())
+	tracker := &upgradeTracker{vzState: vzStateStageGate, gen: 1, stage: 1, compMap: map[string]*componentUpgradeContext{}}
+
+	// Component a is not ready, the soak time has not started
+	result, err := reconciler.checkStageGate(vzlog.DefaultLogger(), vz, tracker)
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.True(tracker.stageReadyTime.IsZero())
+
+	// Component a is ready, the stage waits for the soak time
+	ready = "true"
+	result, err = reconciler.checkStageGate(vzlog.DefaultLogger(), vz, tracker)
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.Equal(maxSoakCheckInterval, result.RequeueAfter)
+	asserts.False(tracker.stageReadyTime.IsZero())
+
+	// The soak time has passed
+	tracker.stageReadyTime = time.Now().Add(-time.Hour)
+	result, err = reconciler.checkStageGate(vzlog.DefaultLogger(), vz, tracker)
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+}
@@ -34,12 +34,9 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"github.com/verrazzano/verrazzano/platform-operator/mocks"
 	appsv1 "k8s.io/api/apps/v1"
-	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
-	errors2 "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	gofake "k8s.io/client-go/kubernetes/fake"
@@ -157,7 +154,7 @@ func TestUpgradeNoVersion(t *testing.T) {
 	// Validate the results
 	asserts.NoError(err)
 	asserts.Equal(false, result.Requeue)
-	asserts.Equal(time.Duration(0), result.RequeueAfter)
+	asserts.Equal(healthCheckInterval, result.RequeueAfter)
 	verrazzano := vzapi.Verrazzano{}
 	err = c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, &verrazzano)
 	asserts.NoError(err)
@@ -250,7 +247,7 @@ func TestUpgradeSameVersion(t *testing.T) {
 	// Validate the results
 	asserts.NoError(err)
 	asserts.Equal(false, result.Requeue)
-	asserts.Equal(time.Duration(0), result.RequeueAfter)
+	asserts.Equal(healthCheckInterval, result.RequeueAfter)
 	verrazzano := vzapi.Verrazzano{}
 	err = c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, &verrazzano)
 	asserts.NoError(err)
@@ -350,7 +347,7 @@ func TestUpgradeStarted(t *testing.T) {
 		},
 		rbac.NewServiceAccount(namespace, name, []string{}, nil),
 		rbac.NewClusterRoleBinding(&verrazzanoToUse, name, getInstallNamespace(), buildServiceAccountName(name)),
-	).Build()
+	).WithObjects(newPreflightObjects()...).Build()
 
 	config.TestProfilesDir = "../../manifests/profiles"
 	defer func() { config.TestProfilesDir = "" }()
@@ -429,26 +426,14 @@ func TestDeleteDuringUpgrade(t *testing.T) {
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
-	// Expect a call to get the uninstall Job - return that it does not exist
-	mock.EXPECT().
-		Get(gomock.Any(), types.NamespacedName{Namespace: getInstallNamespace(), Name: buildUninstallJobName(name)}, gomock.Not(gomock.Nil())).
-		Return(errors2.NewNotFound(schema.GroupResource{Group: namespace, Resource: "Job"}, buildUninstallJobName(name)))
-
-	// Expect a call to create the uninstall Job - return success
-	mock.EXPECT().
-		Create(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(ctx context.Context, job *batchv1.Job, opts ...client.CreateOption) error {
-			asserts.Equalf(getInstallNamespace(), job.Namespace, "Job namespace did not match")
-			asserts.Equalf(buildUninstallJobName(name), job.Name, "Job name did not match")
+	// Expect a status update with the uninstall started condition
+	mockStatus.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
+			asserts.Equal(vzapi.VzStateUninstalling, verrazzano.Status.State)
 			return nil
 		})
 
-	// Expect a call to update the job - return success
-	mock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-
-	// Expect a status update on the job
-	mockStatus.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
-
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
@@ -545,6 +530,10 @@ func TestUpgradeStartedWhenPrevFailures(t *testing.T) {
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
+	// Expect the calls of the preflight checks, and the status update with their results
+	expectPreflightChecks(mock)
+	expectPreflightStatusUpdate(mockStatus, asserts)
+
 	// Expect a call to update the status of the Verrazzano resource
 	mockStatus.EXPECT().
 		Update(gomock.Any(), gomock.Any()).
@@ -1062,6 +1051,8 @@ func TestUpgradeComponent(t *testing.T) {
 	mockComp.EXPECT().Upgrade(gomock.Any()).Return(nil).Times(1)
 	mockComp.EXPECT().PostUpgrade(gomock.Any()).Return(nil).Times(1)
 	mockComp.EXPECT().Name().Return(componentName).AnyTimes()
+	mockComp.EXPECT().GetJSONName().Return(componentName).AnyTimes()
+	mockComp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
 	mockComp.EXPECT().IsReady(gomock.Any()).Return(true).AnyTimes()
 
 	mock.EXPECT().
@@ -1154,6 +1145,8 @@ func TestUpgradeComponentWithBlockingStatus(t *testing.T) {
 	mockComp.EXPECT().PreUpgrade(gomock.Any()).Return(nil).Times(1)
 	mockComp.EXPECT().Upgrade(gomock.Any()).Return(fmt.Errorf("Upgrade in progress")).AnyTimes()
 	mockComp.EXPECT().Name().Return("testcomp").Times(1).AnyTimes()
+	mockComp.EXPECT().GetJSONName().Return("testcomp").AnyTimes()
+	mockComp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
 
 	// expect a call to list any secrets with a status other than "deployed" for the component
 	statuses := []string{"unknown", "uninstalled", "superseded", "failed", "uninstalling", "pending-install", "pending-upgrade", "pending-rollback"}
@@ -1247,6 +1240,8 @@ func TestUpgradeMultipleComponentsOneDisabled(t *testing.T) {
 
 	// Set enabled mock component expectations
 	mockEnabledComp.EXPECT().Name().Return("EnabledComponent").AnyTimes()
+	mockEnabledComp.EXPECT().GetJSONName().Return("enabledComponent").AnyTimes()
+	mockEnabledComp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
 	mockEnabledComp.EXPECT().IsInstalled(gomock.Any()).Return(true, nil).Times(2)
 	mockEnabledComp.EXPECT().PreUpgrade(gomock.Any()).Return(nil).Times(1)
 	mockEnabledComp.EXPECT().Upgrade(gomock.Any()).Return(nil).Times(1)
@@ -1255,6 +1250,7 @@ func TestUpgradeMultipleComponentsOneDisabled(t *testing.T) {
 
 	// Set disabled mock component expectations
 	mockDisabledComp.EXPECT().Name().Return("DisabledComponent").Times(1).AnyTimes()
+	mockDisabledComp.EXPECT().GetDependencies().Return([]string{}).AnyTimes()
 	mockDisabledComp.EXPECT().IsInstalled(gomock.Any()).Return(false, nil).AnyTimes()
 	mockDisabledComp.EXPECT().PreUpgrade(gomock.Any()).Return(nil).Times(0)
 	mockDisabledComp.EXPECT().Upgrade(gomock.Any()).Return(nil).Times(0)
@@ -1802,7 +1798,7 @@ func TestInstanceRestoreWithEmptyStatus(t *testing.T) {
 	// Validate the results
 	asserts.NoError(err)
 	asserts.Equal(false, result.Requeue)
-	asserts.Equal(time.Duration(0), result.RequeueAfter)
+	asserts.Equal(healthCheckInterval, result.RequeueAfter)
 
 	// validating instance urls are updated
 	// Status is empty in this case
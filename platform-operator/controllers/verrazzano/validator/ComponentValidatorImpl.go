@@ -4,8 +4,11 @@
 package validator
 
 import (
+	"fmt"
+
 	"github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/spi"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/transform"
 )
 
@@ -26,7 +29,11 @@ func (c ComponentValidatorImpl) ValidateInstall(vz *v1alpha1.Verrazzano) []error
 		if err := comp.ValidateInstall(effectiveCR); err != nil {
 			errs = append(errs, err)
 		}
+		if err := validateKubernetesSettings(comp, effectiveCR); err != nil {
+			errs = append(errs, err)
+		}
 	}
+	errs = append(errs, validateUpgradeStages(vz)...)
 
 	return errs
 }
@@ -49,6 +56,94 @@ func (c ComponentValidatorImpl) ValidateUpdate(old *v1alpha1.Verrazzano, new *v1
 		if err := comp.ValidateUpdate(effectiveOld, effectiveNew); err != nil {
 			errs = append(errs, err)
 		}
+		if err := validateDisable(comp, effectiveOld, effectiveNew); err != nil {
+			errs = append(errs, err)
+		}
+		if err := validateKubernetesSettings(comp, effectiveNew); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, validateUpgradeStages(new)...)
+	return errs
+}
+
+// validateDisable checks that a component being disabled is not a dependency of any component that remains enabled
+func validateDisable(comp spi.Component, old *v1alpha1.Verrazzano, new *v1alpha1.Verrazzano) error {
+	if !comp.IsEnabled(old) || comp.IsEnabled(new) {
+		return nil
+	}
+	if ok, dependents := registry.ComponentDependentsDisabled(comp, new); !ok {
+		return fmt.Errorf("Disabling component %s is not allowed, it is required by the enabled components %v", comp.GetJSONName(), dependents)
+	}
+	return nil
+}
+
+// validateKubernetesSettings checks the kubernetes section of the component, for the components that support it
+func validateKubernetesSettings(comp spi.Component, vz *v1alpha1.Verrazzano) error {
+	if validator, ok := comp.(spi.ComponentKubernetesSettingsValidator); ok {
+		return validator.ValidateKubernetesSettings(vz)
+	}
+	return nil
+}
+
+// validateUpgradeStages checks that the upgrade stages have unique names, that each component is a known
+// component that is only in one stage, and that no component is upgraded before its dependencies.  The components
+// that are not in a stage are upgraded in a final stage.
+func validateUpgradeStages(vz *v1alpha1.Verrazzano) []error {
+	if vz.Spec.UpgradePolicy == nil {
+		return nil
+	}
+	jsonNames := map[string]bool{}
+	jsonNamesByName := map[string]string{}
+	for _, comp := range registry.GetComponents() {
+		jsonNames[comp.GetJSONName()] = true
+		jsonNamesByName[comp.Name()] = comp.GetJSONName()
+	}
+	var errs []error
+	stageNames := map[string]bool{}
+	stageOfComponent := map[string]string{}
+	for _, stage := range vz.Spec.UpgradePolicy.Stages {
+		if len(stage.Name) == 0 {
+			errs = append(errs, fmt.Errorf("Upgrade stage name is required"))
+		} else if stageNames[stage.Name] {
+			errs = append(errs, fmt.Errorf("Upgrade stage %s is defined more than once", stage.Name))
+		}
+		stageNames[stage.Name] = true
+		for _, jsonName := range stage.Components {
+			if !jsonNames[jsonName] {
+				errs = append(errs, fmt.Errorf("Upgrade stage %s has an unknown component %s", stage.Name, jsonName))
+				continue
+			}
+			if otherStage, ok := stageOfComponent[jsonName]; ok {
+				errs = append(errs, fmt.Errorf("Component %s is in both upgrade stages %s and %s", jsonName, otherStage, stage.Name))
+				continue
+			}
+			stageOfComponent[jsonName] = stage.Name
+		}
+	}
+
+	stageIndexes := map[string]int{}
+	for i, stage := range vz.Spec.UpgradePolicy.Stages {
+		stageIndexes[stage.Name] = i
+	}
+	getStage := func(jsonName string) (int, string) {
+		if stageName, ok := stageOfComponent[jsonName]; ok {
+			return stageIndexes[stageName], stageName
+		}
+		return len(vz.Spec.UpgradePolicy.Stages), "the final stage"
+	}
+	for _, comp := range registry.GetComponents() {
+		compStage, compStageName := getStage(comp.GetJSONName())
+		for _, dependency := range comp.GetDependencies() {
+			dependencyJSONName, ok := jsonNamesByName[dependency]
+			if !ok {
+				continue
+			}
+			if dependencyStage, dependencyStageName := getStage(dependencyJSONName); dependencyStage > compStage {
+				errs = append(errs, fmt.Errorf("Component %s in upgrade stage %s depends on component %s, which is upgraded in the later stage %s",
+					comp.GetJSONName(), compStageName, dependencyJSONName, dependencyStageName))
+			}
+		}
 	}
 	return errs
 }
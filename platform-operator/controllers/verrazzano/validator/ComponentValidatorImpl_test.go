@@ -8,6 +8,7 @@ import (
 
 	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var disabled = false
@@ -43,6 +44,82 @@ func TestComponentValidatorImpl_ValidateInstall(t *testing.T) {
 			},
 			numberOfErrors: 0,
 		},
+		{
+			name: "valid upgrade stages",
+			vz: &vzapi.Verrazzano{
+				Spec: vzapi.VerrazzanoSpec{
+					UpgradePolicy: &vzapi.UpgradePolicy{
+						Stages: []vzapi.UpgradeStage{
+							{Name: "network", Components: []string{"istio", "ingress"}},
+							{Name: "observability", Components: []string{"prometheusOperator", "kubeStateMetrics"}, RequireApproval: true},
+						},
+					},
+				},
+			},
+			numberOfErrors: 0,
+		},
+		{
+			name: "upgrade stage before a dependency",
+			vz: &vzapi.Verrazzano{
+				Spec: vzapi.VerrazzanoSpec{
+					UpgradePolicy: &vzapi.UpgradePolicy{
+						Stages: []vzapi.UpgradeStage{
+							{Name: "security", Components: []string{"keycloak", "certManager"}},
+							{Name: "network", Components: []string{"istio", "ingress"}},
+						},
+					},
+				},
+			},
+			// Keycloak depends on istio and ingress-controller
+			numberOfErrors: 2,
+		},
+		{
+			name: "invalid upgrade stages",
+			vz: &vzapi.Verrazzano{
+				Spec: vzapi.VerrazzanoSpec{
+					UpgradePolicy: &vzapi.UpgradePolicy{
+						Stages: []vzapi.UpgradeStage{
+							{Name: "network", Components: []string{"istio", "unknown"}},
+							{Name: "network", Components: []string{"istio"}},
+							{Components: []string{"coherenceOperator"}},
+						},
+					},
+				},
+			},
+			numberOfErrors: 4,
+		},
+		{
+			name: "supported kubernetes settings",
+			vz: &vzapi.Verrazzano{
+				Spec: vzapi.VerrazzanoSpec{
+					Components: vzapi.ComponentSpec{
+						Kiali: &vzapi.KialiComponent{
+							Kubernetes: &vzapi.CommonKubernetesSpec{Replicas: 2, NodeSelector: map[string]string{"disk": "ssd"}},
+						},
+						Grafana: &vzapi.GrafanaComponent{
+							Kubernetes: &vzapi.CommonKubernetesSpec{Resources: &corev1.ResourceRequirements{}},
+						},
+					},
+				},
+			},
+			numberOfErrors: 0,
+		},
+		{
+			name: "unsupported kubernetes settings",
+			vz: &vzapi.Verrazzano{
+				Spec: vzapi.VerrazzanoSpec{
+					Components: vzapi.ComponentSpec{
+						Rancher: &vzapi.RancherComponent{
+							Kubernetes: &vzapi.CommonKubernetesSpec{NodeSelector: map[string]string{"disk": "ssd"}},
+						},
+						Grafana: &vzapi.GrafanaComponent{
+							Kubernetes: &vzapi.CommonKubernetesSpec{Replicas: 2},
+						},
+					},
+				},
+			},
+			numberOfErrors: 2,
+		},
 	}
 	config.TestProfilesDir = "../../../manifests/profiles"
 	defer func() { config.TestProfilesDir = "" }()
@@ -60,7 +137,7 @@ func TestComponentValidatorImpl_ValidateInstall(t *testing.T) {
 // TestComponentValidatorImpl_ValidateUpdate tests the ValidateUpdate function
 // GIVEN a valid CR
 // WHEN ValidateUpdate is called
-// THEN ensure that no error is raised
+// THEN ensure that an error is raised only when disabling a component that enabled components depend on
 func TestComponentValidatorImpl_ValidateUpdate(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -86,10 +163,10 @@ func TestComponentValidatorImpl_ValidateUpdate(t *testing.T) {
 					},
 				},
 			},
-			numberOfErrors: 1,
+			numberOfErrors: 0,
 		},
 		{
-			name: "disable cert",
+			name: "disable cert required by enabled components",
 			old:  &vzapi.Verrazzano{},
 			new: &vzapi.Verrazzano{
 				Spec: vzapi.VerrazzanoSpec{
@@ -103,7 +180,7 @@ func TestComponentValidatorImpl_ValidateUpdate(t *testing.T) {
 			numberOfErrors: 1,
 		},
 		{
-			name: "disabled cert and ingress",
+			name: "disabled cert and ingress required by enabled components",
 			old:  &vzapi.Verrazzano{},
 			new: &vzapi.Verrazzano{
 				Spec: vzapi.VerrazzanoSpec{
@@ -119,6 +196,32 @@ func TestComponentValidatorImpl_ValidateUpdate(t *testing.T) {
 			},
 			numberOfErrors: 2,
 		},
+		{
+			name: "disable cert and all components that require it",
+			old:  &vzapi.Verrazzano{},
+			new: &vzapi.Verrazzano{
+				Spec: vzapi.VerrazzanoSpec{
+					Components: vzapi.ComponentSpec{
+						CertManager: &vzapi.CertManagerComponent{
+							Enabled: &disabled,
+						},
+						Verrazzano: &vzapi.VerrazzanoComponent{
+							Enabled: &disabled,
+						},
+						Rancher: &vzapi.RancherComponent{
+							Enabled: &disabled,
+						},
+						Kiali: &vzapi.KialiComponent{
+							Enabled: &disabled,
+						},
+						Keycloak: &vzapi.KeycloakComponent{
+							Enabled: &disabled,
+						},
+					},
+				},
+			},
+			numberOfErrors: 0,
+		},
 	}
 	config.TestProfilesDir = "../../../manifests/profiles"
 	defer func() { config.TestProfilesDir = "" }()
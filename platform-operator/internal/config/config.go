@@ -65,6 +65,9 @@ type OperatorConfig struct {
 
 	// DryRun Run installs in a dry-run mode
 	DryRun bool
+
+	// ComponentConcurrency is the maximum number of components that are installed or upgraded at the same time
+	ComponentConcurrency int
 }
 
 // The singleton instance of the operator config
@@ -77,6 +80,7 @@ var instance = OperatorConfig{
 	WebhooksEnabled:          true,
 	WebhookValidationEnabled: true,
 	VerrazzanoRootDir:        rootDir,
+	ComponentConcurrency:     4,
 }
 
 // Set saves the operator config.  This should only be called at operator startup and during unit tests
@@ -0,0 +1,101 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+
+	vzconst "github.com/verrazzano/verrazzano/pkg/constants"
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CABundleKey is the key of the CA bundle in the CA rotation bundle secret
+const CABundleKey = "ca-bundle"
+
+// CARotationBundleSecretName is the name of the secret with the CA bundle of the previous and the new CA, while the
+// CA of the Verrazzano ClusterIssuer is being rotated
+var CARotationBundleSecretName = types.NamespacedName{Namespace: vzconst.CertManagerNamespace, Name: constants.VerrazzanoCARotationBundleSecret}
+
+// GetCARotationBundle returns the CA bundle with the previous and the new CA of the Verrazzano ClusterIssuer while the
+// CA is being rotated, or nil if no rotation is in progress
+func GetCARotationBundle(cli client.Client) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := cli.Get(context.TODO(), CARotationBundleSecretName, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret.Data[CABundleKey], nil
+}
+
+// AppendCABundle returns the CA bundle with the certificates of the other bundle that it doesn't contain already
+func AppendCABundle(caBundle []byte, other []byte) []byte {
+	result := make([]byte, len(caBundle))
+	copy(result, caBundle)
+	for _, cert := range splitCABundle(other) {
+		if ContainsCABundle(result, cert) {
+			continue
+		}
+		if len(result) > 0 && !bytes.HasSuffix(result, []byte("\n")) {
+			result = append(result, '\n')
+		}
+		result = append(result, cert...)
+	}
+	return result
+}
+
+// ContainsCABundle returns true if the CA bundle contains all the certificates of the other bundle
+func ContainsCABundle(caBundle []byte, other []byte) bool {
+	certs := map[string]bool{}
+	for _, cert := range splitCABundle(caBundle) {
+		if block, _ := pem.Decode(cert); block != nil {
+			certs[string(block.Bytes)] = true
+		}
+	}
+	for _, cert := range splitCABundle(other) {
+		if block, _ := pem.Decode(cert); block != nil && !certs[string(block.Bytes)] {
+			return false
+		}
+	}
+	return true
+}
+
+// GetCACommonNames returns the common names of the certificates of the CA bundle
+func GetCACommonNames(caBundle []byte) []string {
+	var names []string
+	for _, cert := range splitCABundle(caBundle) {
+		block, _ := pem.Decode(cert)
+		if block == nil {
+			continue
+		}
+		if parsed, err := x509.ParseCertificate(block.Bytes); err == nil {
+			names = append(names, parsed.Subject.CommonName)
+		}
+	}
+	return names
+}
+
+// splitCABundle returns the PEM encoded certificates of the CA bundle
+func splitCABundle(caBundle []byte) [][]byte {
+	var certs [][]byte
+	rest := caBundle
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return certs
+		}
+		if block.Type == "CERTIFICATE" {
+			certs = append(certs, pem.EncodeToMemory(block))
+		}
+	}
+}
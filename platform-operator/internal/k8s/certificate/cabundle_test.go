@@ -0,0 +1,84 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestCA returns a PEM encoded self-signed CA certificate with the common name
+func newTestCA(t *testing.T, commonName string) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caBytes})
+}
+
+// TestAppendCABundle tests the AppendCABundle and ContainsCABundle functions
+// GIVEN CA bundles with different and common certificates
+// WHEN the CA bundles are appended
+// THEN the result contains the certificates of both bundles, without duplicates
+func TestAppendCABundle(t *testing.T) {
+	asserts := assert.New(t)
+	oldCA := newTestCA(t, "old-ca")
+	newCA := newTestCA(t, "new-ca")
+
+	asserts.Equal(oldCA, AppendCABundle(nil, oldCA))
+	asserts.Equal(oldCA, AppendCABundle(oldCA, nil))
+
+	both := AppendCABundle(oldCA, newCA)
+	asserts.Equal([]string{"old-ca", "new-ca"}, GetCACommonNames(both))
+	asserts.Equal(both, AppendCABundle(both, newCA))
+	asserts.Equal(both, AppendCABundle(both, oldCA))
+	// The input bundle is not modified
+	asserts.Equal([]string{"old-ca"}, GetCACommonNames(oldCA))
+
+	asserts.True(ContainsCABundle(both, oldCA))
+	asserts.True(ContainsCABundle(both, newCA))
+	asserts.False(ContainsCABundle(oldCA, both))
+	asserts.True(ContainsCABundle(oldCA, nil))
+}
+
+// TestGetCARotationBundle tests the GetCARotationBundle function
+// GIVEN a client with or without the CA rotation bundle secret
+// WHEN GetCARotationBundle is called
+// THEN the CA bundle of the secret is returned, or nil if the secret doesn't exist
+func TestGetCARotationBundle(t *testing.T) {
+	asserts := assert.New(t)
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	bundle, err := GetCARotationBundle(cli)
+	asserts.NoError(err)
+	asserts.Nil(bundle)
+
+	caBundle := newTestCA(t, "old-ca")
+	cli = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: CARotationBundleSecretName.Namespace, Name: CARotationBundleSecretName.Name},
+		Data:       map[string][]byte{CABundleKey: caBundle},
+	}).Build()
+	bundle, err = GetCARotationBundle(cli)
+	asserts.NoError(err)
+	asserts.Equal(caBundle, bundle)
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package netpolicy
+
+import (
+	"github.com/verrazzano/verrazzano/platform-operator/constants"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// ManagedLabel is the label of the NetworkPolicies that the Verrazzano controller reconciles for the components
+	ManagedLabel = "verrazzano.io/network-policy"
+
+	// ComponentLabel is the label with the name of the component whose pods a NetworkPolicy allows the traffic of
+	ComponentLabel = "verrazzano.io/network-policy-component"
+
+	// DefaultDenyPolicyName is the name of the NetworkPolicy that denies the traffic of the pods of a namespace that
+	// isn't allowed by another policy
+	DefaultDenyPolicyName = "verrazzano-default-deny"
+
+	// ClusterServicesPolicyName is the name of the NetworkPolicy that allows the pods of a namespace to reach DNS and
+	// the Kubernetes API server
+	ClusterServicesPolicyName = "verrazzano-allow-cluster-services"
+
+	// namespaceNameLabel is the label the Kubernetes API server sets on each namespace with the name of the namespace
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+
+	istioSystemNamespace = "istio-system"
+	istiodAppName        = "istiod"
+	istiodXDSPort        = 15012
+	dnsPort              = 53
+
+	prometheusNameLabel     = "app.kubernetes.io/name"
+	prometheusPodName       = "prometheus"
+	legacyPrometheusPodName = "system-prometheus"
+)
+
+// NewComponentNetworkPolicy returns a NetworkPolicy that allows the ingress and egress traffic of the pods with the
+// labels, all the pods of the namespace are selected when there are no labels.  The other traffic of the pods is
+// denied.
+func NewComponentNetworkPolicy(namespace string, name string, podLabels map[string]string, ingress []netv1.NetworkPolicyIngressRule, egress []netv1.NetworkPolicyEgressRule) netv1.NetworkPolicy {
+	return netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: podLabels,
+			},
+			PolicyTypes: []netv1.PolicyType{
+				netv1.PolicyTypeIngress,
+				netv1.PolicyTypeEgress,
+			},
+			Ingress: ingress,
+			Egress:  egress,
+		},
+	}
+}
+
+// NewDefaultDenyNetworkPolicy returns a NetworkPolicy that denies the ingress and egress traffic of the pods of a
+// namespace, other than the traffic allowed by the policies of the components
+func NewDefaultDenyNetworkPolicy(namespace string) netv1.NetworkPolicy {
+	return NewComponentNetworkPolicy(namespace, DefaultDenyPolicyName, nil, nil, nil)
+}
+
+// NewClusterServicesNetworkPolicy returns a NetworkPolicy that allows all the pods of a namespace to reach DNS and the
+// Kubernetes API server
+func NewClusterServicesNetworkPolicy(namespace string, apiServerIP string, apiServerPort int32) netv1.NetworkPolicy {
+	policy := NewComponentNetworkPolicy(namespace, ClusterServicesPolicyName, nil, nil, []netv1.NetworkPolicyEgressRule{
+		DNSEgress([]netv1.NetworkPolicyPeer{PodPeer(kubeSystemNamespace, map[string]string{k8sAppLabel: kubeDNSPodName})}),
+		EgressTo([]netv1.NetworkPolicyPeer{{IPBlock: &netv1.IPBlock{CIDR: apiServerIP + "/32"}}}, int(apiServerPort)),
+	})
+	policy.Spec.PolicyTypes = []netv1.PolicyType{netv1.PolicyTypeEgress}
+	return policy
+}
+
+// IngressFrom returns an ingress rule that allows TCP traffic from the peers to the ports.  The traffic is allowed from
+// anywhere when there are no peers, and to any port when there are no ports.
+func IngressFrom(peers []netv1.NetworkPolicyPeer, ports ...int) netv1.NetworkPolicyIngressRule {
+	return netv1.NetworkPolicyIngressRule{
+		From:  peers,
+		Ports: newPorts(corev1.ProtocolTCP, ports),
+	}
+}
+
+// EgressTo returns an egress rule that allows TCP traffic to the ports of the peers.  The traffic is allowed to
+// anywhere when there are no peers, and to any port when there are no ports.
+func EgressTo(peers []netv1.NetworkPolicyPeer, ports ...int) netv1.NetworkPolicyEgressRule {
+	return netv1.NetworkPolicyEgressRule{
+		To:    peers,
+		Ports: newPorts(corev1.ProtocolTCP, ports),
+	}
+}
+
+// DNSEgress returns an egress rule that allows DNS queries to the peers, to any DNS server when there are no peers
+func DNSEgress(peers []netv1.NetworkPolicyPeer) netv1.NetworkPolicyEgressRule {
+	return netv1.NetworkPolicyEgressRule{
+		To:    peers,
+		Ports: append(newPorts(corev1.ProtocolTCP, []int{dnsPort}), newPorts(corev1.ProtocolUDP, []int{dnsPort})...),
+	}
+}
+
+// IstiodEgress returns an egress rule that allows the Istio proxy sidecars of the pods to reach istiod
+func IstiodEgress() netv1.NetworkPolicyEgressRule {
+	return EgressTo([]netv1.NetworkPolicyPeer{PodPeer(istioSystemNamespace, map[string]string{podAppLabel: istiodAppName})}, istiodXDSPort)
+}
+
+// PrometheusIngress returns an ingress rule that allows Prometheus to scrape the ports of the pods
+func PrometheusIngress(ports ...int) netv1.NetworkPolicyIngressRule {
+	return IngressFrom(PrometheusPeers(), ports...)
+}
+
+// PrometheusPeers returns the peers for the Prometheus pods of Verrazzano
+func PrometheusPeers() []netv1.NetworkPolicyPeer {
+	return []netv1.NetworkPolicyPeer{
+		PodPeer(constants.VerrazzanoMonitoringNamespace, map[string]string{prometheusNameLabel: prometheusPodName}),
+		PodPeer(constants.VerrazzanoSystemNamespace, map[string]string{podAppLabel: legacyPrometheusPodName}),
+	}
+}
+
+// PodPeer returns a peer for the pods with the labels in a namespace.  All the pods of the namespace are selected when
+// there are no labels, the pods of the namespace of the policy are selected when there is no namespace.
+func PodPeer(namespace string, podLabels map[string]string) netv1.NetworkPolicyPeer {
+	peer := netv1.NetworkPolicyPeer{}
+	if len(namespace) > 0 {
+		peer.NamespaceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				namespaceNameLabel: namespace,
+			},
+		}
+	}
+	if len(podLabels) > 0 || len(namespace) == 0 {
+		peer.PodSelector = &metav1.LabelSelector{
+			MatchLabels: podLabels,
+		}
+	}
+	return peer
+}
+
+// ClusterPeer returns a peer for all the pods of the cluster
+func ClusterPeer() netv1.NetworkPolicyPeer {
+	return netv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{},
+	}
+}
+
+// newPorts returns the policy ports for the port numbers with a protocol
+func newPorts(protocol corev1.Protocol, ports []int) []netv1.NetworkPolicyPort {
+	var policyPorts []netv1.NetworkPolicyPort
+	for _, port := range ports {
+		policyProtocol := protocol
+		policyPort := intstr.FromInt(port)
+		policyPorts = append(policyPorts, netv1.NetworkPolicyPort{
+			Protocol: &policyProtocol,
+			Port:     &policyPort,
+		})
+	}
+	return policyPorts
+}
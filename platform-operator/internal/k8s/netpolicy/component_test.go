@@ -0,0 +1,100 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package netpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestGetAPIServerIPAndPort tests the GetAPIServerIPAndPort func
+// GIVEN the Endpoints of the Kubernetes API server
+// WHEN the address of the API server is requested
+// THEN the IP and port of the Endpoints are returned, and an error is returned when there are no Endpoints
+func TestGetAPIServerIPAndPort(t *testing.T) {
+	asserts := assert.New(t)
+
+	ip, port, err := GetAPIServerIPAndPort(ctrlfake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(makeKubeAPIServerEndpoint()).Build())
+	asserts.NoError(err)
+	asserts.Equal(apiServerIP, ip)
+	asserts.Equal(int32(apiServerPort), port)
+
+	_, _, err = GetAPIServerIPAndPort(ctrlfake.NewClientBuilder().WithScheme(k8scheme.Scheme).Build())
+	asserts.Error(err)
+}
+
+// TestNewComponentNetworkPolicy tests the NewComponentNetworkPolicy and rule funcs
+// GIVEN ingress and egress rules for the pods of a component
+// WHEN the NetworkPolicy of the component is created
+// THEN the policy selects the pods, and has the ports and peers of the rules
+func TestNewComponentNetworkPolicy(t *testing.T) {
+	asserts := assert.New(t)
+
+	policy := NewComponentNetworkPolicy("ns", "allow-a", map[string]string{"app": "a"},
+		[]netv1.NetworkPolicyIngressRule{
+			IngressFrom([]netv1.NetworkPolicyPeer{PodPeer("other", map[string]string{"app": "b"})}, 80, 443),
+			IngressFrom(nil),
+		},
+		[]netv1.NetworkPolicyEgressRule{
+			EgressTo([]netv1.NetworkPolicyPeer{PodPeer("", nil)}),
+			IstiodEgress(),
+		})
+
+	asserts.Equal("ns", policy.Namespace)
+	asserts.Equal("allow-a", policy.Name)
+	asserts.Equal(map[string]string{"app": "a"}, policy.Spec.PodSelector.MatchLabels)
+	asserts.Equal([]netv1.PolicyType{netv1.PolicyTypeIngress, netv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+
+	// The ingress from the pods of another namespace to two ports, and from anywhere to any port
+	asserts.Len(policy.Spec.Ingress, 2)
+	asserts.Len(policy.Spec.Ingress[0].Ports, 2)
+	asserts.Equal(corev1.ProtocolTCP, *policy.Spec.Ingress[0].Ports[0].Protocol)
+	asserts.Equal(int32(443), policy.Spec.Ingress[0].Ports[1].Port.IntVal)
+	peer := policy.Spec.Ingress[0].From[0]
+	asserts.Equal(map[string]string{namespaceNameLabel: "other"}, peer.NamespaceSelector.MatchLabels)
+	asserts.Equal(map[string]string{"app": "b"}, peer.PodSelector.MatchLabels)
+	asserts.Nil(policy.Spec.Ingress[1].From)
+	asserts.Nil(policy.Spec.Ingress[1].Ports)
+
+	// The egress to all the pods of the namespace, and to istiod
+	peer = policy.Spec.Egress[0].To[0]
+	asserts.Nil(peer.NamespaceSelector)
+	asserts.Equal(&metav1.LabelSelector{}, peer.PodSelector)
+	peer = policy.Spec.Egress[1].To[0]
+	asserts.Equal(map[string]string{namespaceNameLabel: istioSystemNamespace}, peer.NamespaceSelector.MatchLabels)
+	asserts.Equal(int32(istiodXDSPort), policy.Spec.Egress[1].Ports[0].Port.IntVal)
+}
+
+// TestNewNamespaceNetworkPolicies tests the NewDefaultDenyNetworkPolicy and NewClusterServicesNetworkPolicy funcs
+// GIVEN a namespace
+// WHEN the policies of the namespace are created
+// THEN the default deny policy selects all the pods without any rules, and the cluster services policy allows the
+// pods to reach DNS and the API server
+func TestNewNamespaceNetworkPolicies(t *testing.T) {
+	asserts := assert.New(t)
+
+	deny := NewDefaultDenyNetworkPolicy("ns")
+	asserts.Equal(DefaultDenyPolicyName, deny.Name)
+	asserts.Empty(deny.Spec.PodSelector.MatchLabels)
+	asserts.Len(deny.Spec.PolicyTypes, 2)
+	asserts.Empty(deny.Spec.Ingress)
+	asserts.Empty(deny.Spec.Egress)
+
+	services := NewClusterServicesNetworkPolicy("ns", apiServerIP, apiServerPort)
+	asserts.Equal(ClusterServicesPolicyName, services.Name)
+	asserts.Equal([]netv1.PolicyType{netv1.PolicyTypeEgress}, services.Spec.PolicyTypes)
+	asserts.Len(services.Spec.Egress, 2)
+	dns := services.Spec.Egress[0]
+	asserts.Equal(map[string]string{k8sAppLabel: kubeDNSPodName}, dns.To[0].PodSelector.MatchLabels)
+	asserts.Len(dns.Ports, 2)
+	asserts.Equal(corev1.ProtocolUDP, *dns.Ports[1].Protocol)
+	asserts.Equal(apiServerIP+"/32", services.Spec.Egress[1].To[0].IPBlock.CIDR)
+	asserts.Equal(int32(apiServerPort), services.Spec.Egress[1].Ports[0].Port.IntVal)
+}
@@ -11,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -58,7 +59,20 @@ func getAPIServerIPAndPort(clientset kubernetes.Interface) (string, int32, error
 	if err != nil {
 		return "", 0, err
 	}
+	return getEndpointsIPAndPort(endpoints)
+}
+
+// GetAPIServerIPAndPort returns the IP address and port of the Kubernetes API server, using a controller-runtime client.
+func GetAPIServerIPAndPort(cli client.Client) (string, int32, error) {
+	endpoints := &corev1.Endpoints{}
+	if err := cli.Get(context.TODO(), types.NamespacedName{Namespace: corev1.NamespaceDefault, Name: apiServerEndpointName}, endpoints); err != nil {
+		return "", 0, err
+	}
+	return getEndpointsIPAndPort(endpoints)
+}
 
+// getEndpointsIPAndPort returns the first IP address and port of the Kubernetes API server endpoints.
+func getEndpointsIPAndPort(endpoints *corev1.Endpoints) (string, int32, error) {
 	if len(endpoints.Subsets) > 0 && len(endpoints.Subsets[0].Addresses) > 0 && len(endpoints.Subsets[0].Ports) > 0 {
 		return endpoints.Subsets[0].Addresses[0].IP, endpoints.Subsets[0].Ports[0].Port, nil
 	}
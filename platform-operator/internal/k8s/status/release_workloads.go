@@ -0,0 +1,80 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package status
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// helmReleaseNameAnnotation is the annotation Helm sets on the resources it creates for a release
+const helmReleaseNameAnnotation = "meta.helm.sh/release-name"
+
+// GetUnreadyReleaseWorkloads returns a description of each deployment, statefulset and daemonset created by the Helm
+// release in the namespace that does not have all of its replicas ready
+func GetUnreadyReleaseWorkloads(client clipkg.Client, releaseName string, namespace string) ([]string, error) {
+	var unready []string
+
+	deployments := appsv1.DeploymentList{}
+	if err := client.List(context.TODO(), &deployments, clipkg.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments.Items {
+		if !isReleaseResource(deployment.ObjectMeta, releaseName) {
+			continue
+		}
+		expectedReplicas := getExpectedReplicas(deployment.Spec.Replicas)
+		if deployment.Status.AvailableReplicas < expectedReplicas || deployment.Status.UpdatedReplicas < expectedReplicas {
+			unready = append(unready, fmt.Sprintf("deployment %s/%s has %v of %v replicas available", namespace, deployment.Name,
+				deployment.Status.AvailableReplicas, expectedReplicas))
+		}
+	}
+
+	statefulsets := appsv1.StatefulSetList{}
+	if err := client.List(context.TODO(), &statefulsets, clipkg.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for _, statefulset := range statefulsets.Items {
+		if !isReleaseResource(statefulset.ObjectMeta, releaseName) {
+			continue
+		}
+		expectedReplicas := getExpectedReplicas(statefulset.Spec.Replicas)
+		if statefulset.Status.ReadyReplicas < expectedReplicas || statefulset.Status.UpdatedReplicas < expectedReplicas {
+			unready = append(unready, fmt.Sprintf("statefulset %s/%s has %v of %v replicas ready", namespace, statefulset.Name,
+				statefulset.Status.ReadyReplicas, expectedReplicas))
+		}
+	}
+
+	daemonsets := appsv1.DaemonSetList{}
+	if err := client.List(context.TODO(), &daemonsets, clipkg.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for _, daemonset := range daemonsets.Items {
+		if !isReleaseResource(daemonset.ObjectMeta, releaseName) {
+			continue
+		}
+		if daemonset.Status.NumberAvailable < daemonset.Status.DesiredNumberScheduled {
+			unready = append(unready, fmt.Sprintf("daemonset %s/%s has %v of %v pods available", namespace, daemonset.Name,
+				daemonset.Status.NumberAvailable, daemonset.Status.DesiredNumberScheduled))
+		}
+	}
+	return unready, nil
+}
+
+// isReleaseResource returns true if the resource was created by the Helm release
+func isReleaseResource(meta metav1.ObjectMeta, releaseName string) bool {
+	return meta.Annotations[helmReleaseNameAnnotation] == releaseName
+}
+
+// getExpectedReplicas returns the number of replicas in the spec, which defaults to 1
+func getExpectedReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
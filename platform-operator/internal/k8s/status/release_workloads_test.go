@@ -0,0 +1,67 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestGetUnreadyReleaseWorkloads tests the GetUnreadyReleaseWorkloads func
+// GIVEN deployments, statefulsets and daemonsets of a Helm release and of other releases
+// WHEN GetUnreadyReleaseWorkloads is called
+// THEN only the workloads of the release that are not ready are returned
+func TestGetUnreadyReleaseWorkloads(t *testing.T) {
+	releaseMeta := func(name string, release string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "foo",
+			Annotations: map[string]string{helmReleaseNameAnnotation: release},
+		}
+	}
+	replicas := int32(2)
+	c := fake.NewClientBuilder().WithScheme(k8scheme.Scheme).WithObjects(
+		&appsv1.Deployment{
+			ObjectMeta: releaseMeta("ready", "rel"),
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 1, UpdatedReplicas: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: releaseMeta("unready", "rel"),
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 1, UpdatedReplicas: 2},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: releaseMeta("other", "other"),
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: releaseMeta("unready", "rel"),
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: 0, UpdatedReplicas: 1},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: releaseMeta("unready", "rel"),
+			Status:     appsv1.DaemonSetStatus{NumberAvailable: 2, DesiredNumberScheduled: 3},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: releaseMeta("ready", "rel"),
+			Status:     appsv1.DaemonSetStatus{NumberAvailable: 3, DesiredNumberScheduled: 3},
+		},
+	).Build()
+
+	unready, err := GetUnreadyReleaseWorkloads(c, "rel", "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"deployment foo/unready has 1 of 2 replicas available",
+		"statefulset foo/unready has 0 of 1 replicas ready",
+		"daemonset foo/unready has 2 of 3 pods available",
+	}, unready)
+
+	unready, err = GetUnreadyReleaseWorkloads(c, "rel", "bar")
+	assert.NoError(t, err)
+	assert.Empty(t, unready)
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// MasterRealm is the realm of the Keycloak admin user
+	MasterRealm = "master"
+
+	// adminCLIClientID is the client that the admin user logs in with
+	adminCLIClientID = "admin-cli"
+
+	// tokenExpiryMargin is how long before it expires that the access token is renewed
+	tokenExpiryMargin = 10 * time.Second
+
+	// requestTimeout is the timeout of the requests made by the default HTTP client
+	requestTimeout = 30 * time.Second
+)
+
+// AdminClient is a client of the Keycloak Admin REST API.  The client logs in to the master realm as the admin user
+// when it first needs an access token, and again when the token is about to expire or is rejected.
+type AdminClient struct {
+	baseURL     string
+	username    string
+	password    string
+	httpClient  *http.Client
+	token       string
+	tokenExpiry time.Time
+}
+
+// APIError is returned when Keycloak responds to a request with an unexpected status code
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+// Error returns the method, path and status of the failed request, and the error message from Keycloak
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Keycloak request %s %s failed with status %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is a Keycloak response with status 404 Not Found
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict returns true if the error is a Keycloak response with status 409 Conflict, which Keycloak returns when
+// creating a resource that already exists
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// hasStatus returns true if the error is a Keycloak response with the status code
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// tokenResponse is the response of the OpenID Connect token endpoint
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// NewAdminClient returns a client of the Keycloak at baseURL, for example http://localhost:8080/auth, that logs in
+// to the master realm with the username and password of an admin user.  A default HTTP client is used if httpClient
+// is nil.
+func NewAdminClient(baseURL string, username string, password string, httpClient *http.Client) *AdminClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+	return &AdminClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: httpClient,
+	}
+}
+
+// Login logs in to the master realm as the admin user and keeps the access token for the following requests
+func (c *AdminClient) Login() error {
+	form := url.Values{
+		"grant_type": {"password"},
+		"client_id":  {adminCLIClientID},
+		"username":   {c.username},
+		"password":   {c.password},
+	}
+	tokenPath := "/realms/" + MasterRealm + "/protocol/openid-connect/token"
+	resp, err := c.httpClient.PostForm(c.baseURL+tokenPath, form)
+	if err != nil {
+		return fmt.Errorf("Failed to log in to Keycloak as %s: %v", c.username, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(http.MethodPost, tokenPath, resp)
+	}
+	token := tokenResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("Failed to decode the Keycloak access token: %v", err)
+	}
+	c.token = token.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpiryMargin)
+	return nil
+}
+
+// ensureToken logs in unless the access token is still valid
+func (c *AdminClient) ensureToken() error {
+	if len(c.token) > 0 && time.Now().Before(c.tokenExpiry) {
+		return nil
+	}
+	return c.Login()
+}
+
+// do sends a request to the Admin REST API of a realm.  The request body is the JSON encoding of in, unless in is
+// nil, and the JSON response body is decoded into out, unless out is nil.  The headers of the response are returned.
+func (c *AdminClient) do(method string, adminPath string, query url.Values, in interface{}, out interface{}) (http.Header, error) {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return nil, err
+		}
+	}
+	fullPath := path.Join("/admin/realms", adminPath)
+	reqURL := c.baseURL + fullPath
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	// A rejected access token is renewed once, Keycloak revokes the tokens of the admin user when it restarts
+	for attempt := 0; ; attempt++ {
+		if err := c.ensureToken(); err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest(method, reqURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Accept", "application/json")
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("Keycloak request %s %s failed: %v", method, fullPath, err)
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			resp.Body.Close()
+			c.token = ""
+			continue
+		}
+		header, err := readResponse(method, fullPath, resp, out)
+		resp.Body.Close()
+		return header, err
+	}
+}
+
+// readResponse decodes the JSON response body into out, unless out is nil, and returns the headers of the response,
+// or the error of a failed request.  The caller closes the response body.
+func readResponse(method string, path string, resp *http.Response, out interface{}) (http.Header, error) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newAPIError(method, path, resp)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("Failed to decode the response of Keycloak request %s %s: %v", method, path, err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// newAPIError returns the error of a failed request, with the error message from the response body
+func newAPIError(method string, path string, resp *http.Response) *APIError {
+	message := http.StatusText(resp.StatusCode)
+	data, _ := ioutil.ReadAll(resp.Body)
+	kcError := struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+		ErrorMessage     string `json:"errorMessage"`
+	}{}
+	if json.Unmarshal(data, &kcError) == nil {
+		switch {
+		case len(kcError.ErrorMessage) > 0:
+			message = kcError.ErrorMessage
+		case len(kcError.ErrorDescription) > 0:
+			message = kcError.ErrorDescription
+		case len(kcError.Error) > 0:
+			message = kcError.Error
+		}
+	} else if len(data) > 0 {
+		message = string(data)
+	}
+	return &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: message}
+}
+
+// getCreatedID returns the ID of a created resource, which is the last element of the path in the Location header
+func getCreatedID(header http.Header) (string, error) {
+	location := header.Get("Location")
+	if len(location) == 0 {
+		return "", errors.New("Keycloak did not return the location of the created resource")
+	}
+	return path.Base(location), nil
+}
@@ -0,0 +1,371 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/keycloak"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/keycloak/fake"
+)
+
+const (
+	testUsername = "keycloakadmin"
+	testPassword = "secret"
+	testRealm    = "test"
+)
+
+// newTestClient returns a started fake Keycloak with a test realm, and a client of it
+func newTestClient(t *testing.T) (*fake.Server, *keycloak.AdminClient) {
+	server := fake.NewServer(testUsername, testPassword)
+	kc := keycloak.NewAdminClient(server.BaseURL(), testUsername, testPassword, nil)
+	assert.NoError(t, kc.CreateRealm(keycloak.Realm{Realm: testRealm}))
+	return server, kc
+}
+
+// TestLogin tests the Login function
+// GIVEN a Keycloak
+// WHEN the admin client logs in
+// THEN an access token is acquired if the credentials are valid, otherwise the error from Keycloak is returned
+func TestLogin(t *testing.T) {
+	asserts := assert.New(t)
+	server := fake.NewServer(testUsername, testPassword)
+	defer server.Close()
+
+	asserts.NoError(keycloak.NewAdminClient(server.BaseURL(), testUsername, testPassword, nil).Login())
+
+	err := keycloak.NewAdminClient(server.BaseURL(), testUsername, "wrong", nil).Login()
+	asserts.Error(err)
+	asserts.Contains(err.Error(), "Invalid user credentials")
+	asserts.Contains(err.Error(), "401")
+}
+
+// TestRevokedToken tests that a rejected access token is renewed
+// GIVEN a client that has logged in
+// WHEN Keycloak revokes the access token
+// THEN the client logs in again and the request succeeds
+func TestRevokedToken(t *testing.T) {
+	asserts := assert.New(t)
+	server, kc := newTestClient(t)
+	defer server.Close()
+
+	server.RevokeTokens()
+	realm, err := kc.GetRealm(testRealm)
+	asserts.NoError(err)
+	asserts.Equal(testRealm, realm.Realm)
+}
+
+// closeTracker is an HTTP transport that counts the response bodies that are not closed
+type closeTracker struct {
+	open int
+}
+
+// trackedBody is a response body that is counted until it is closed
+type trackedBody struct {
+	io.ReadCloser
+	tracker *closeTracker
+}
+
+// RoundTrip sends the request with the default transport and tracks the response body
+func (c *closeTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	c.open++
+	resp.Body = &trackedBody{ReadCloser: resp.Body, tracker: c}
+	return resp, nil
+}
+
+// Close closes the response body
+func (b *trackedBody) Close() error {
+	b.tracker.open--
+	return b.ReadCloser.Close()
+}
+
+// TestResponseBodiesClosed tests that the response bodies are closed
+// GIVEN a client that has logged in
+// WHEN Keycloak revokes the access token and a request is retried
+// THEN the response body of each attempt is closed once the request returns
+func TestResponseBodiesClosed(t *testing.T) {
+	asserts := assert.New(t)
+	server := fake.NewServer(testUsername, testPassword)
+	defer server.Close()
+	tracker := &closeTracker{}
+	kc := keycloak.NewAdminClient(server.BaseURL(), testUsername, testPassword, &http.Client{Transport: tracker})
+	asserts.NoError(kc.CreateRealm(keycloak.Realm{Realm: testRealm}))
+
+	server.RevokeTokens()
+	_, err := kc.GetRealm(testRealm)
+	asserts.NoError(err)
+	_, err = kc.GetGroupRealmRoles(testRealm, "missing")
+	asserts.True(keycloak.IsNotFound(err))
+	asserts.Equal(0, tracker.open)
+}
+
+// TestRealms tests the realm functions
+// GIVEN a Keycloak
+// WHEN a realm is created, read and updated
+// THEN only the fields that are set are updated and creating an existing realm is a conflict
+func TestRealms(t *testing.T) {
+	asserts := assert.New(t)
+	server, kc := newTestClient(t)
+	defer server.Close()
+
+	realm, err := kc.GetRealm("missing")
+	asserts.NoError(err)
+	asserts.Nil(realm)
+
+	err = kc.CreateRealm(keycloak.Realm{Realm: testRealm})
+	asserts.True(keycloak.IsConflict(err))
+
+	enabled := true
+	asserts.NoError(kc.UpdateRealm(keycloak.Realm{Realm: testRealm, PasswordPolicy: "length(8)"}))
+	asserts.NoError(kc.UpdateRealm(keycloak.Realm{Realm: testRealm, Enabled: &enabled, LoginTheme: "oracle"}))
+	realm, err = kc.GetRealm(testRealm)
+	asserts.NoError(err)
+	asserts.Equal("length(8)", realm.PasswordPolicy)
+	asserts.Equal("oracle", realm.LoginTheme)
+	asserts.True(*realm.Enabled)
+}
+
+// TestGroups tests the group functions
+// GIVEN a realm
+// WHEN groups and subgroups are created, updated, granted roles and deleted
+// THEN the groups can be found by name with their attributes and roles, and the roles can be revoked
+func TestGroups(t *testing.T) {
+	asserts := assert.New(t)
+	server, kc := newTestClient(t)
+	defer server.Close()
+
+	parentID, err := kc.CreateGroup(testRealm, keycloak.Group{Name: "parent"})
+	asserts.NoError(err)
+	childID, err := kc.CreateChildGroup(testRealm, parentID, keycloak.Group{Name: "child"})
+	asserts.NoError(err)
+	_, err = kc.CreateChildGroup(testRealm, parentID, keycloak.Group{Name: "child"})
+	asserts.True(keycloak.IsConflict(err))
+
+	groups, err := kc.GetGroups(testRealm)
+	asserts.NoError(err)
+	asserts.Equal(parentID, keycloak.FindGroup(groups, "parent").ID)
+	asserts.Equal(childID, keycloak.FindGroup(groups, "child").ID)
+	asserts.Equal("/parent/child", keycloak.FindGroup(groups, "child").Path)
+	asserts.Nil(keycloak.FindGroup(groups, "missing"))
+
+	asserts.NoError(kc.CreateRole(testRealm, keycloak.Role{Name: "access"}))
+	role, err := kc.GetRole(testRealm, "access")
+	asserts.NoError(err)
+	asserts.NoError(kc.AddGroupRealmRoles(testRealm, childID, []keycloak.Role{*role}))
+	asserts.NoError(kc.AddGroupRealmRoles(testRealm, childID, []keycloak.Role{*role}))
+	roles, err := kc.GetGroupRealmRoles(testRealm, childID)
+	asserts.NoError(err)
+	asserts.Len(roles, 1)
+	asserts.Equal("access", roles[0].Name)
+	asserts.NoError(kc.DeleteGroupRealmRoles(testRealm, childID, roles))
+	roles, err = kc.GetGroupRealmRoles(testRealm, childID)
+	asserts.NoError(err)
+	asserts.Empty(roles)
+
+	child := keycloak.FindGroup(groups, "child")
+	child.Attributes = map[string][]string{"owner": {"test"}}
+	asserts.NoError(kc.UpdateGroup(testRealm, *child))
+	groups, err = kc.GetGroups(testRealm)
+	asserts.NoError(err)
+	asserts.Equal([]string{"test"}, keycloak.FindGroup(groups, "child").Attributes["owner"])
+
+	asserts.NoError(kc.DeleteGroup(testRealm, parentID))
+	groups, err = kc.GetGroups(testRealm)
+	asserts.NoError(err)
+	asserts.Nil(keycloak.FindGroup(groups, "parent"))
+	asserts.Nil(keycloak.FindGroup(groups, "child"))
+}
+
+// TestRoles tests the role functions
+// GIVEN a realm
+// WHEN a realm role is created
+// THEN it can be read, and creating it again is a conflict
+func TestRoles(t *testing.T) {
+	asserts := assert.New(t)
+	server, kc := newTestClient(t)
+	defer server.Close()
+
+	role, err := kc.GetRole(testRealm, "access")
+	asserts.NoError(err)
+	asserts.Nil(role)
+
+	asserts.NoError(kc.CreateRole(testRealm, keycloak.Role{Name: "access", Description: "API access"}))
+	asserts.True(keycloak.IsConflict(kc.CreateRole(testRealm, keycloak.Role{Name: "access"})))
+	roles, err := kc.GetRoles(testRealm)
+	asserts.NoError(err)
+	asserts.Len(roles, 1)
+	asserts.Equal("API access", roles[0].Description)
+}
+
+// TestUsers tests the user functions
+// GIVEN a realm
+// WHEN users are created in groups and their passwords are set
+// THEN users are found by exact username
+func TestUsers(t *testing.T) {
+	asserts := assert.New(t)
+	server, kc := newTestClient(t)
+	defer server.Close()
+
+	_, err := kc.CreateGroup(testRealm, keycloak.Group{Name: "admins"})
+	asserts.NoError(err)
+	enabled := true
+	userID, err := kc.CreateUser(testRealm, keycloak.User{Username: "admin-user", Enabled: &enabled, Groups: []string{"/admins"}})
+	asserts.NoError(err)
+	_, err = kc.CreateUser(testRealm, keycloak.User{Username: "admin"})
+	asserts.NoError(err)
+	_, err = kc.CreateUser(testRealm, keycloak.User{Username: "admin"})
+	asserts.True(keycloak.IsConflict(err))
+
+	user, err := kc.FindUser(testRealm, "admin-user")
+	asserts.NoError(err)
+	asserts.Equal(userID, user.ID)
+	user, err = kc.FindUser(testRealm, "admin")
+	asserts.NoError(err)
+	asserts.Equal("admin", user.Username)
+	user, err = kc.FindUser(testRealm, "adm")
+	asserts.NoError(err)
+	asserts.Nil(user)
+
+	asserts.NoError(kc.SetPassword(testRealm, userID, "changeme"))
+	asserts.Equal("changeme", server.Realms[testRealm].Passwords["admin-user"])
+}
+
+// TestClients tests the client functions
+// GIVEN a realm
+// WHEN a client is created and updated
+// THEN the client is found by client ID and only the fields that are set are updated
+func TestClients(t *testing.T) {
+	asserts := assert.New(t)
+	server, kc := newTestClient(t)
+	defer server.Close()
+
+	client, err := kc.FindClient(testRealm, "app")
+	asserts.NoError(err)
+	asserts.Nil(client)
+
+	public := true
+	id, err := kc.CreateClient(testRealm, keycloak.Client{ClientID: "app", PublicClient: &public, RedirectURIs: []string{"https://old/*"}})
+	asserts.NoError(err)
+	_, err = kc.CreateClient(testRealm, keycloak.Client{ClientID: "app"})
+	asserts.True(keycloak.IsConflict(err))
+
+	asserts.NoError(kc.UpdateClient(testRealm, keycloak.Client{ID: id, ClientID: "app", RedirectURIs: []string{"https://new/*"}}))
+	client, err = kc.FindClient(testRealm, "app")
+	asserts.NoError(err)
+	asserts.Equal(id, client.ID)
+	asserts.Equal([]string{"https://new/*"}, client.RedirectURIs)
+	asserts.True(*client.PublicClient)
+
+	clients, err := kc.GetClients(testRealm)
+	asserts.NoError(err)
+	asserts.Len(clients, 1)
+}
+
+// TestAPIError tests the errors returned for failed requests
+// GIVEN a Keycloak that fails a request
+// WHEN the request is made
+// THEN an APIError with the status and the error message from Keycloak is returned
+func TestAPIError(t *testing.T) {
+	asserts := assert.New(t)
+	server, kc := newTestClient(t)
+	defer server.Close()
+
+	server.Failures["GET /auth/admin/realms/test/groups"] = http.StatusInternalServerError
+	_, err := kc.GetGroups(testRealm)
+	asserts.Error(err)
+	apiErr, ok := err.(*keycloak.APIError)
+	asserts.True(ok)
+	asserts.Equal(http.StatusInternalServerError, apiErr.StatusCode)
+	asserts.Equal("injected failure", apiErr.Message)
+	asserts.Equal("/admin/realms/test/groups", apiErr.Path)
+	asserts.False(keycloak.IsNotFound(err))
+
+	_, err = kc.GetGroups("missing")
+	asserts.True(keycloak.IsNotFound(err))
+}
+
+// TestIdentityProviders tests the identity provider functions
+// GIVEN a realm
+// WHEN an identity provider is created, updated and deleted
+// THEN the client secret is masked when it is read, and updating it to the mask keeps it
+func TestIdentityProviders(t *testing.T) {
+	asserts := assert.New(t)
+	server, kc := newTestClient(t)
+	defer server.Close()
+
+	idp, err := kc.GetIdentityProvider(testRealm, "upstream")
+	asserts.NoError(err)
+	asserts.Nil(idp)
+
+	asserts.NoError(kc.CreateIdentityProvider(testRealm, keycloak.IdentityProvider{
+		Alias:      "upstream",
+		ProviderID: "oidc",
+		Config:     map[string]string{"clientId": "app", "clientSecret": "secret"},
+	}))
+	asserts.True(keycloak.IsConflict(kc.CreateIdentityProvider(testRealm, keycloak.IdentityProvider{Alias: "upstream", ProviderID: "oidc"})))
+	idp, err = kc.GetIdentityProvider(testRealm, "upstream")
+	asserts.NoError(err)
+	asserts.Equal(keycloak.SecretMask, idp.Config["clientSecret"])
+
+	idp.DisplayName = "Upstream"
+	asserts.NoError(kc.UpdateIdentityProvider(testRealm, *idp))
+	asserts.Equal("Upstream", server.Realms[testRealm].IdentityProviders[0].DisplayName)
+	asserts.Equal("secret", server.Realms[testRealm].IdentityProviders[0].Config["clientSecret"])
+
+	idps, err := kc.GetIdentityProviders(testRealm)
+	asserts.NoError(err)
+	asserts.Len(idps, 1)
+	asserts.Equal(keycloak.SecretMask, idps[0].Config["clientSecret"])
+	asserts.NoError(kc.DeleteIdentityProvider(testRealm, "upstream"))
+	idps, err = kc.GetIdentityProviders(testRealm)
+	asserts.NoError(err)
+	asserts.Empty(idps)
+}
+
+// TestComponents tests the component functions
+// GIVEN a realm
+// WHEN a user federation provider is created, updated and deleted
+// THEN it is found by type and name, and the bind credential is masked when it is read
+func TestComponents(t *testing.T) {
+	asserts := assert.New(t)
+	server, kc := newTestClient(t)
+	defer server.Close()
+
+	component, err := kc.FindComponent(testRealm, keycloak.UserStorageProviderType, "ldap")
+	asserts.NoError(err)
+	asserts.Nil(component)
+
+	id, err := kc.CreateComponent(testRealm, keycloak.Component{
+		Name:         "ldap",
+		ProviderID:   "ldap",
+		ProviderType: keycloak.UserStorageProviderType,
+		ParentID:     testRealm,
+		Config:       map[string][]string{"connectionUrl": {"ldap://old"}, "bindCredential": {"secret"}},
+	})
+	asserts.NoError(err)
+	component, err = kc.FindComponent(testRealm, keycloak.UserStorageProviderType, "ldap")
+	asserts.NoError(err)
+	asserts.Equal(id, component.ID)
+	asserts.Equal([]string{keycloak.SecretMask}, component.Config["bindCredential"])
+
+	component.Config["connectionUrl"] = []string{"ldap://new"}
+	asserts.NoError(kc.UpdateComponent(testRealm, *component))
+	asserts.Equal([]string{"ldap://new"}, server.Realms[testRealm].Components[0].Config["connectionUrl"])
+	asserts.Equal([]string{"secret"}, server.Realms[testRealm].Components[0].Config["bindCredential"])
+
+	components, err := kc.GetComponents(testRealm, keycloak.UserStorageProviderType)
+	asserts.NoError(err)
+	asserts.Len(components, 1)
+	asserts.NoError(kc.DeleteComponent(testRealm, id))
+	components, err = kc.GetComponents(testRealm, keycloak.UserStorageProviderType)
+	asserts.NoError(err)
+	asserts.Empty(components)
+}
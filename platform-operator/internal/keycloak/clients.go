@@ -0,0 +1,48 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// GetClients returns the clients of the realm
+func (c *AdminClient) GetClients(realm string) ([]Client, error) {
+	var clients []Client
+	if _, err := c.do(http.MethodGet, realmPath(realm, "clients"), nil, nil, &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// FindClient returns the client with the client ID, or nil if there is none
+func (c *AdminClient) FindClient(realm string, clientID string) (*Client, error) {
+	var clients []Client
+	query := url.Values{"clientId": {clientID}}
+	if _, err := c.do(http.MethodGet, realmPath(realm, "clients"), query, nil, &clients); err != nil {
+		return nil, err
+	}
+	for i := range clients {
+		if clients[i].ClientID == clientID {
+			return &clients[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateClient creates a client and returns the ID that Keycloak generated for it
+func (c *AdminClient) CreateClient(realm string, client Client) (string, error) {
+	header, err := c.do(http.MethodPost, realmPath(realm, "clients"), nil, client, nil)
+	if err != nil {
+		return "", err
+	}
+	return getCreatedID(header)
+}
+
+// UpdateClient updates the fields of the client that are set, the client is identified by its ID
+func (c *AdminClient) UpdateClient(realm string, client Client) error {
+	_, err := c.do(http.MethodPut, realmPath(realm, "clients", client.ID), nil, client, nil)
+	return err
+}
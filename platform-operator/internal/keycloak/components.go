@@ -0,0 +1,58 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// UserStorageProviderType is the provider type of the user federation providers
+const UserStorageProviderType = "org.keycloak.storage.UserStorageProvider"
+
+// GetComponents returns the components of the provider type
+func (c *AdminClient) GetComponents(realm string, providerType string) ([]Component, error) {
+	var components []Component
+	query := url.Values{"type": {providerType}}
+	if _, err := c.do(http.MethodGet, realmPath(realm, "components"), query, nil, &components); err != nil {
+		return nil, err
+	}
+	return components, nil
+}
+
+// FindComponent returns the component of the provider type with the name, or nil if there is none
+func (c *AdminClient) FindComponent(realm string, providerType string, name string) (*Component, error) {
+	var components []Component
+	query := url.Values{"type": {providerType}, "name": {name}}
+	if _, err := c.do(http.MethodGet, realmPath(realm, "components"), query, nil, &components); err != nil {
+		return nil, err
+	}
+	for i := range components {
+		if components[i].ProviderType == providerType && components[i].Name == name {
+			return &components[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateComponent creates a component and returns the ID that Keycloak generated for it
+func (c *AdminClient) CreateComponent(realm string, component Component) (string, error) {
+	header, err := c.do(http.MethodPost, realmPath(realm, "components"), nil, component, nil)
+	if err != nil {
+		return "", err
+	}
+	return getCreatedID(header)
+}
+
+// UpdateComponent updates the component, which is identified by its ID
+func (c *AdminClient) UpdateComponent(realm string, component Component) error {
+	_, err := c.do(http.MethodPut, realmPath(realm, "components", component.ID), nil, component, nil)
+	return err
+}
+
+// DeleteComponent deletes the component with the ID
+func (c *AdminClient) DeleteComponent(realm string, componentID string) error {
+	_, err := c.do(http.MethodDelete, realmPath(realm, "components", componentID), nil, nil, nil)
+	return err
+}
@@ -0,0 +1,694 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/verrazzano/verrazzano/platform-operator/internal/keycloak"
+)
+
+const (
+	// contextPath is the path that Keycloak serves under
+	contextPath = "/auth"
+
+	// accessToken is the access token issued to the admin user
+	accessToken = "fake-access-token"
+)
+
+// RealmState is the configuration of a realm in the fake Keycloak
+type RealmState struct {
+	Realm   keycloak.Realm
+	Groups  []keycloak.Group
+	Roles   []keycloak.Role
+	Users   []keycloak.User
+	Clients []keycloak.Client
+	// IdentityProviders and Components have their secrets, which Keycloak masks when they are read
+	IdentityProviders []keycloak.IdentityProvider
+	Components        []keycloak.Component
+	// Passwords are the passwords of the users by username
+	Passwords map[string]string
+	// GroupRoles are the names of the realm roles granted to groups, by group ID
+	GroupRoles map[string][]string
+}
+
+// Server is an in-memory stand-in for the Keycloak Admin REST API, for unit testing
+type Server struct {
+	*httptest.Server
+
+	// Realms are the realms by name, the master realm exists when the server starts
+	Realms map[string]*RealmState
+	// Failures are the status codes to respond with instead of handling requests, by "METHOD path", for example
+	// "POST /auth/admin/realms/test/groups"
+	Failures map[string]int
+	// Requests are the "METHOD path" of the requests to the Admin REST API, in the order they were received
+	Requests []string
+
+	username        string
+	password        string
+	nextID          int
+	tokenGeneration int
+	mutex           sync.Mutex
+}
+
+// NewServer returns a started fake Keycloak with the username and password of the admin user.  The caller must
+// Close it.
+func NewServer(username string, password string) *Server {
+	s := &Server{
+		Realms:   map[string]*RealmState{},
+		Failures: map[string]int{},
+		username: username,
+		password: password,
+	}
+	s.Realms[keycloak.MasterRealm] = newRealmState(keycloak.Realm{ID: keycloak.MasterRealm, Realm: keycloak.MasterRealm})
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// BaseURL returns the URL that Keycloak is served at
+func (s *Server) BaseURL() string {
+	return s.URL + contextPath
+}
+
+// RevokeTokens makes the server reject the access tokens it has issued until the admin user logs in again
+func (s *Server) RevokeTokens() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tokenGeneration++
+}
+
+// newRealmState returns the empty configuration of a realm
+func newRealmState(realm keycloak.Realm) *RealmState {
+	return &RealmState{
+		Realm:      realm,
+		Passwords:  map[string]string{},
+		GroupRoles: map[string][]string{},
+	}
+}
+
+// newID returns a new ID for a created resource
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("id-%d", s.nextID)
+}
+
+// token returns the access token that is currently valid
+func (s *Server) token() string {
+	return fmt.Sprintf("%s-%d", accessToken, s.tokenGeneration)
+}
+
+// handle handles a request to the fake Keycloak
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if status, ok := s.Failures[r.Method+" "+r.URL.Path]; ok {
+		writeError(w, status, "injected failure")
+		return
+	}
+	if r.URL.Path == contextPath+"/realms/"+keycloak.MasterRealm+"/protocol/openid-connect/token" && r.Method == http.MethodPost {
+		s.handleToken(w, r)
+		return
+	}
+	adminPrefix := contextPath + "/admin/realms"
+	if !strings.HasPrefix(r.URL.Path, adminPrefix) {
+		writeError(w, http.StatusNotFound, "RESTEASY003210: Could not find resource for full path")
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+s.token() {
+		writeError(w, http.StatusUnauthorized, "HTTP 401 Unauthorized")
+		return
+	}
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.Path)
+
+	var segments []string
+	if trimmed := strings.Trim(strings.TrimPrefix(r.URL.Path, adminPrefix), "/"); len(trimmed) > 0 {
+		segments = strings.Split(trimmed, "/")
+	}
+	if len(segments) == 0 {
+		s.handleRealms(w, r)
+		return
+	}
+	state, ok := s.Realms[segments[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Realm not found.")
+		return
+	}
+	if len(segments) == 1 {
+		s.handleRealm(w, r, state)
+		return
+	}
+	switch segments[1] {
+	case "groups":
+		s.handleGroups(w, r, state, segments[2:])
+	case "roles":
+		s.handleRoles(w, r, state, segments[2:])
+	case "users":
+		s.handleUsers(w, r, state, segments[2:])
+	case "clients":
+		s.handleClients(w, r, state, segments[2:])
+	case "identity-provider":
+		s.handleIdentityProviders(w, r, state, segments[2:])
+	case "components":
+		s.handleComponents(w, r, state, segments[2:])
+	default:
+		writeError(w, http.StatusNotFound, "RESTEASY003210: Could not find resource for full path")
+	}
+}
+
+// handleToken issues an access token if the username and password are those of the admin user
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if r.PostForm.Get("grant_type") != "password" || r.PostForm.Get("username") != s.username || r.PostForm.Get("password") != s.password {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "Invalid user credentials"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": s.token(), "expires_in": 60})
+}
+
+// handleRealms handles the realms collection
+func (s *Server) handleRealms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "")
+		return
+	}
+	realm := keycloak.Realm{}
+	if !readJSON(w, r, &realm) {
+		return
+	}
+	if _, ok := s.Realms[realm.Realm]; ok {
+		writeError(w, http.StatusConflict, "Conflict detected. See logs for details")
+		return
+	}
+	realm.ID = realm.Realm
+	s.Realms[realm.Realm] = newRealmState(realm)
+	w.Header().Set("Location", s.BaseURL()+"/admin/realms/"+realm.Realm)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// handleRealm handles a realm
+func (s *Server) handleRealm(w http.ResponseWriter, r *http.Request, state *RealmState) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, state.Realm)
+	case http.MethodPut:
+		// Only the fields in the request are updated
+		if !readJSON(w, r, &state.Realm) {
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "")
+	}
+}
+
+// handleGroups handles the groups of a realm
+func (s *Server) handleGroups(w http.ResponseWriter, r *http.Request, state *RealmState, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, state.Groups)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		group := keycloak.Group{}
+		if !readJSON(w, r, &group) {
+			return
+		}
+		if findGroupByName(state.Groups, group.Name) != nil {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Top level group named '%s' already exists.", group.Name))
+			return
+		}
+		group.ID = s.newID()
+		group.Path = "/" + group.Name
+		state.Groups = append(state.Groups, group)
+		w.Header().Set("Location", fmt.Sprintf("%s%s/%s", s.BaseURL(), "/admin/realms/"+state.Realm.Realm+"/groups", group.ID))
+		w.WriteHeader(http.StatusCreated)
+	case len(segments) == 2 && segments[1] == "children" && r.Method == http.MethodPost:
+		parent := findGroupByID(state.Groups, segments[0])
+		if parent == nil {
+			writeError(w, http.StatusNotFound, "Could not find parent group")
+			return
+		}
+		group := keycloak.Group{}
+		if !readJSON(w, r, &group) {
+			return
+		}
+		if findGroupByName(parent.SubGroups, group.Name) != nil {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Sibling group named '%s' already exists.", group.Name))
+			return
+		}
+		group.ID = s.newID()
+		group.Path = parent.Path + "/" + group.Name
+		parent.SubGroups = append(parent.SubGroups, group)
+		w.Header().Set("Location", fmt.Sprintf("%s%s/%s", s.BaseURL(), "/admin/realms/"+state.Realm.Realm+"/groups", group.ID))
+		w.WriteHeader(http.StatusCreated)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		group := findGroupByID(state.Groups, segments[0])
+		if group == nil {
+			writeError(w, http.StatusNotFound, "Could not find group by id")
+			return
+		}
+		// Only the fields in the request are updated
+		if !readJSON(w, r, group) {
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		groups, ok := removeGroup(state.Groups, segments[0])
+		if !ok {
+			writeError(w, http.StatusNotFound, "Could not find group by id")
+			return
+		}
+		state.Groups = groups
+		delete(state.GroupRoles, segments[0])
+		w.WriteHeader(http.StatusNoContent)
+	case len(segments) == 3 && segments[1] == "role-mappings" && segments[2] == "realm":
+		if findGroupByID(state.Groups, segments[0]) == nil {
+			writeError(w, http.StatusNotFound, "Could not find group by id")
+			return
+		}
+		s.handleGroupRoles(w, r, state, segments[0])
+	default:
+		writeError(w, http.StatusNotFound, "RESTEASY003210: Could not find resource for full path")
+	}
+}
+
+// handleGroupRoles handles the realm roles granted to a group
+func (s *Server) handleGroupRoles(w http.ResponseWriter, r *http.Request, state *RealmState, groupID string) {
+	switch r.Method {
+	case http.MethodGet:
+		roles := []keycloak.Role{}
+		for _, name := range state.GroupRoles[groupID] {
+			roles = append(roles, *findRole(state.Roles, name))
+		}
+		writeJSON(w, http.StatusOK, roles)
+	case http.MethodPost:
+		var roles []keycloak.Role
+		if !readJSON(w, r, &roles) {
+			return
+		}
+		for _, role := range roles {
+			if findRole(state.Roles, role.Name) == nil {
+				writeError(w, http.StatusNotFound, "Could not find role")
+				return
+			}
+		}
+		for _, role := range roles {
+			if !contains(state.GroupRoles[groupID], role.Name) {
+				state.GroupRoles[groupID] = append(state.GroupRoles[groupID], role.Name)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		var roles []keycloak.Role
+		if !readJSON(w, r, &roles) {
+			return
+		}
+		var granted []string
+		for _, name := range state.GroupRoles[groupID] {
+			if findRole(roles, name) == nil {
+				granted = append(granted, name)
+			}
+		}
+		state.GroupRoles[groupID] = granted
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "")
+	}
+}
+
+// handleRoles handles the realm roles of a realm
+func (s *Server) handleRoles(w http.ResponseWriter, r *http.Request, state *RealmState, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, state.Roles)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		role := keycloak.Role{}
+		if !readJSON(w, r, &role) {
+			return
+		}
+		if findRole(state.Roles, role.Name) != nil {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Role with name %s already exists", role.Name))
+			return
+		}
+		role.ID = s.newID()
+		role.ContainerID = state.Realm.ID
+		state.Roles = append(state.Roles, role)
+		w.Header().Set("Location", s.BaseURL()+"/admin/realms/"+state.Realm.Realm+"/roles/"+role.Name)
+		w.WriteHeader(http.StatusCreated)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		role := findRole(state.Roles, segments[0])
+		if role == nil {
+			writeError(w, http.StatusNotFound, "Could not find role")
+			return
+		}
+		writeJSON(w, http.StatusOK, role)
+	default:
+		writeError(w, http.StatusNotFound, "RESTEASY003210: Could not find resource for full path")
+	}
+}
+
+// handleUsers handles the users of a realm
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request, state *RealmState, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		// Like Keycloak, the username is a search string
+		users := []keycloak.User{}
+		for _, user := range state.Users {
+			if strings.Contains(user.Username, r.URL.Query().Get("username")) {
+				user.Groups = nil
+				users = append(users, user)
+			}
+		}
+		writeJSON(w, http.StatusOK, users)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		user := keycloak.User{}
+		if !readJSON(w, r, &user) {
+			return
+		}
+		for _, existing := range state.Users {
+			if existing.Username == user.Username {
+				writeError(w, http.StatusConflict, "User exists with same username")
+				return
+			}
+		}
+		for _, path := range user.Groups {
+			if findGroupByPath(state.Groups, path) == nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Group %s not found", path))
+				return
+			}
+		}
+		user.ID = s.newID()
+		state.Users = append(state.Users, user)
+		w.Header().Set("Location", s.BaseURL()+"/admin/realms/"+state.Realm.Realm+"/users/"+user.ID)
+		w.WriteHeader(http.StatusCreated)
+	case len(segments) == 2 && segments[1] == "reset-password" && r.Method == http.MethodPut:
+		credential := keycloak.Credential{}
+		if !readJSON(w, r, &credential) {
+			return
+		}
+		for _, user := range state.Users {
+			if user.ID == segments[0] {
+				state.Passwords[user.Username] = credential.Value
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "User not found")
+	default:
+		writeError(w, http.StatusNotFound, "RESTEASY003210: Could not find resource for full path")
+	}
+}
+
+// handleClients handles the clients of a realm
+func (s *Server) handleClients(w http.ResponseWriter, r *http.Request, state *RealmState, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		clients := []keycloak.Client{}
+		for _, client := range state.Clients {
+			if clientID := r.URL.Query().Get("clientId"); len(clientID) == 0 || client.ClientID == clientID {
+				clients = append(clients, client)
+			}
+		}
+		writeJSON(w, http.StatusOK, clients)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		client := keycloak.Client{}
+		if !readJSON(w, r, &client) {
+			return
+		}
+		for _, existing := range state.Clients {
+			if existing.ClientID == client.ClientID {
+				writeError(w, http.StatusConflict, fmt.Sprintf("Client %s already exists", client.ClientID))
+				return
+			}
+		}
+		client.ID = s.newID()
+		state.Clients = append(state.Clients, client)
+		w.Header().Set("Location", s.BaseURL()+"/admin/realms/"+state.Realm.Realm+"/clients/"+client.ID)
+		w.WriteHeader(http.StatusCreated)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		for i := range state.Clients {
+			if state.Clients[i].ID == segments[0] {
+				// Only the fields in the request are updated
+				if !readJSON(w, r, &state.Clients[i]) {
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Could not find client")
+	default:
+		writeError(w, http.StatusNotFound, "RESTEASY003210: Could not find resource for full path")
+	}
+}
+
+// handleIdentityProviders handles the identity providers of a realm
+func (s *Server) handleIdentityProviders(w http.ResponseWriter, r *http.Request, state *RealmState, segments []string) {
+	switch {
+	case len(segments) == 1 && segments[0] == "instances" && r.Method == http.MethodGet:
+		idps := []keycloak.IdentityProvider{}
+		for _, idp := range state.IdentityProviders {
+			idp.Config = maskSecret(idp.Config, "clientSecret")
+			idps = append(idps, idp)
+		}
+		writeJSON(w, http.StatusOK, idps)
+	case len(segments) == 1 && segments[0] == "instances" && r.Method == http.MethodPost:
+		idp := keycloak.IdentityProvider{}
+		if !readJSON(w, r, &idp) {
+			return
+		}
+		if findIdentityProvider(state.IdentityProviders, idp.Alias) != nil {
+			writeError(w, http.StatusConflict, "Identity Provider "+idp.Alias+" already exists")
+			return
+		}
+		state.IdentityProviders = append(state.IdentityProviders, idp)
+		w.Header().Set("Location", s.BaseURL()+"/admin/realms/"+state.Realm.Realm+"/identity-provider/instances/"+idp.Alias)
+		w.WriteHeader(http.StatusCreated)
+	case len(segments) == 2 && segments[0] == "instances":
+		existing := findIdentityProvider(state.IdentityProviders, segments[1])
+		if existing == nil {
+			writeError(w, http.StatusNotFound, "Could not find identity provider")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			idp := *existing
+			idp.Config = maskSecret(idp.Config, "clientSecret")
+			writeJSON(w, http.StatusOK, idp)
+		case http.MethodPut:
+			idp := keycloak.IdentityProvider{}
+			if !readJSON(w, r, &idp) {
+				return
+			}
+			if idp.Config["clientSecret"] == keycloak.SecretMask {
+				idp.Config["clientSecret"] = existing.Config["clientSecret"]
+			}
+			*existing = idp
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			var idps []keycloak.IdentityProvider
+			for _, idp := range state.IdentityProviders {
+				if idp.Alias != segments[1] {
+					idps = append(idps, idp)
+				}
+			}
+			state.IdentityProviders = idps
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "RESTEASY003210: Could not find resource for full path")
+	}
+}
+
+// handleComponents handles the components of a realm
+func (s *Server) handleComponents(w http.ResponseWriter, r *http.Request, state *RealmState, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		components := []keycloak.Component{}
+		for _, component := range state.Components {
+			providerType, name := r.URL.Query().Get("type"), r.URL.Query().Get("name")
+			if (len(providerType) == 0 || component.ProviderType == providerType) && (len(name) == 0 || component.Name == name) {
+				component.Config = maskSecrets(component.Config, "bindCredential")
+				components = append(components, component)
+			}
+		}
+		writeJSON(w, http.StatusOK, components)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		component := keycloak.Component{}
+		if !readJSON(w, r, &component) {
+			return
+		}
+		if component.ParentID != state.Realm.ID {
+			writeError(w, http.StatusBadRequest, "Parent is not the realm")
+			return
+		}
+		component.ID = s.newID()
+		state.Components = append(state.Components, component)
+		w.Header().Set("Location", s.BaseURL()+"/admin/realms/"+state.Realm.Realm+"/components/"+component.ID)
+		w.WriteHeader(http.StatusCreated)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		for i := range state.Components {
+			if state.Components[i].ID == segments[0] {
+				component := keycloak.Component{}
+				if !readJSON(w, r, &component) {
+					return
+				}
+				if contains(component.Config["bindCredential"], keycloak.SecretMask) {
+					component.Config["bindCredential"] = state.Components[i].Config["bindCredential"]
+				}
+				state.Components[i] = component
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Could not find component")
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		for i := range state.Components {
+			if state.Components[i].ID == segments[0] {
+				state.Components = append(state.Components[:i], state.Components[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Could not find component")
+	default:
+		writeError(w, http.StatusNotFound, "RESTEASY003210: Could not find resource for full path")
+	}
+}
+
+// maskSecret returns a copy of the config with the secret masked, like Keycloak does
+func maskSecret(config map[string]string, key string) map[string]string {
+	masked := map[string]string{}
+	for k, v := range config {
+		masked[k] = v
+	}
+	if _, ok := masked[key]; ok {
+		masked[key] = keycloak.SecretMask
+	}
+	return masked
+}
+
+// maskSecrets returns a copy of the multivalued config with the secret masked, like Keycloak does
+func maskSecrets(config map[string][]string, key string) map[string][]string {
+	masked := map[string][]string{}
+	for k, v := range config {
+		masked[k] = v
+	}
+	if _, ok := masked[key]; ok {
+		masked[key] = []string{keycloak.SecretMask}
+	}
+	return masked
+}
+
+// findIdentityProvider returns the identity provider with the alias, or nil
+func findIdentityProvider(idps []keycloak.IdentityProvider, alias string) *keycloak.IdentityProvider {
+	for i := range idps {
+		if idps[i].Alias == alias {
+			return &idps[i]
+		}
+	}
+	return nil
+}
+
+// findGroupByName returns the group with the name, or nil
+func findGroupByName(groups []keycloak.Group, name string) *keycloak.Group {
+	for i := range groups {
+		if groups[i].Name == name {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+// findGroupByID returns the group, or subgroup, with the ID, or nil
+func findGroupByID(groups []keycloak.Group, id string) *keycloak.Group {
+	for i := range groups {
+		if groups[i].ID == id {
+			return &groups[i]
+		}
+		if group := findGroupByID(groups[i].SubGroups, id); group != nil {
+			return group
+		}
+	}
+	return nil
+}
+
+// removeGroup returns the groups without the group, or subgroup, with the ID, and whether it was found
+func removeGroup(groups []keycloak.Group, id string) ([]keycloak.Group, bool) {
+	for i := range groups {
+		if groups[i].ID == id {
+			return append(groups[:i:i], groups[i+1:]...), true
+		}
+		if subGroups, ok := removeGroup(groups[i].SubGroups, id); ok {
+			groups[i].SubGroups = subGroups
+			return groups, true
+		}
+	}
+	return groups, false
+}
+
+// findGroupByPath returns the group, or subgroup, with the path, or nil
+func findGroupByPath(groups []keycloak.Group, path string) *keycloak.Group {
+	for i := range groups {
+		if groups[i].Path == path {
+			return &groups[i]
+		}
+		if group := findGroupByPath(groups[i].SubGroups, path); group != nil {
+			return group
+		}
+	}
+	return nil
+}
+
+// findRole returns the role with the name, or nil
+func findRole(roles []keycloak.Role, name string) *keycloak.Role {
+	for i := range roles {
+		if roles[i].Name == name {
+			return &roles[i]
+		}
+	}
+	return nil
+}
+
+// contains returns true if the string is in the slice
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// readJSON decodes the JSON request body, responding with 400 Bad Request if it can't be decoded
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// writeJSON responds with the JSON encoding of v
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError responds with a Keycloak error message
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"errorMessage": message})
+}
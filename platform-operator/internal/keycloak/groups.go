@@ -0,0 +1,70 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// GetGroups returns the top level groups of the realm and their subgroups, with their attributes
+func (c *AdminClient) GetGroups(realm string) ([]Group, error) {
+	var groups []Group
+	query := url.Values{"briefRepresentation": {"false"}}
+	if _, err := c.do(http.MethodGet, realmPath(realm, "groups"), query, nil, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// CreateGroup creates a top level group in the realm and returns its ID
+func (c *AdminClient) CreateGroup(realm string, group Group) (string, error) {
+	header, err := c.do(http.MethodPost, realmPath(realm, "groups"), nil, group, nil)
+	if err != nil {
+		return "", err
+	}
+	return getCreatedID(header)
+}
+
+// CreateChildGroup creates a subgroup of the parent group and returns its ID
+func (c *AdminClient) CreateChildGroup(realm string, parentID string, group Group) (string, error) {
+	header, err := c.do(http.MethodPost, realmPath(realm, "groups", parentID, "children"), nil, group, nil)
+	if err != nil {
+		return "", err
+	}
+	return getCreatedID(header)
+}
+
+// UpdateGroup updates the name and attributes of the group, which is identified by its ID
+func (c *AdminClient) UpdateGroup(realm string, group Group) error {
+	_, err := c.do(http.MethodPut, realmPath(realm, "groups", group.ID), nil, Group{ID: group.ID, Name: group.Name, Attributes: group.Attributes}, nil)
+	return err
+}
+
+// DeleteGroup deletes the group and its subgroups
+func (c *AdminClient) DeleteGroup(realm string, groupID string) error {
+	_, err := c.do(http.MethodDelete, realmPath(realm, "groups", groupID), nil, nil, nil)
+	return err
+}
+
+// GetGroupRealmRoles returns the realm roles granted to the group
+func (c *AdminClient) GetGroupRealmRoles(realm string, groupID string) ([]Role, error) {
+	var roles []Role
+	if _, err := c.do(http.MethodGet, realmPath(realm, "groups", groupID, "role-mappings", "realm"), nil, nil, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// AddGroupRealmRoles grants realm roles to the group, roles that are already granted are ignored by Keycloak
+func (c *AdminClient) AddGroupRealmRoles(realm string, groupID string, roles []Role) error {
+	_, err := c.do(http.MethodPost, realmPath(realm, "groups", groupID, "role-mappings", "realm"), nil, roles, nil)
+	return err
+}
+
+// DeleteGroupRealmRoles revokes realm roles from the group
+func (c *AdminClient) DeleteGroupRealmRoles(realm string, groupID string, roles []Role) error {
+	_, err := c.do(http.MethodDelete, realmPath(realm, "groups", groupID, "role-mappings", "realm"), nil, roles, nil)
+	return err
+}
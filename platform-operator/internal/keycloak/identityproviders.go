@@ -0,0 +1,47 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"net/http"
+)
+
+// GetIdentityProviders returns the identity providers of the realm
+func (c *AdminClient) GetIdentityProviders(realm string) ([]IdentityProvider, error) {
+	var idps []IdentityProvider
+	if _, err := c.do(http.MethodGet, realmPath(realm, "identity-provider", "instances"), nil, nil, &idps); err != nil {
+		return nil, err
+	}
+	return idps, nil
+}
+
+// GetIdentityProvider returns the identity provider with the alias, or nil if it doesn't exist
+func (c *AdminClient) GetIdentityProvider(realm string, alias string) (*IdentityProvider, error) {
+	idp := &IdentityProvider{}
+	if _, err := c.do(http.MethodGet, realmPath(realm, "identity-provider", "instances", alias), nil, nil, idp); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return idp, nil
+}
+
+// CreateIdentityProvider creates an identity provider
+func (c *AdminClient) CreateIdentityProvider(realm string, idp IdentityProvider) error {
+	_, err := c.do(http.MethodPost, realmPath(realm, "identity-provider", "instances"), nil, idp, nil)
+	return err
+}
+
+// UpdateIdentityProvider updates the identity provider, which is identified by its alias
+func (c *AdminClient) UpdateIdentityProvider(realm string, idp IdentityProvider) error {
+	_, err := c.do(http.MethodPut, realmPath(realm, "identity-provider", "instances", idp.Alias), nil, idp, nil)
+	return err
+}
+
+// DeleteIdentityProvider deletes the identity provider with the alias
+func (c *AdminClient) DeleteIdentityProvider(realm string, alias string) error {
+	_, err := c.do(http.MethodDelete, realmPath(realm, "identity-provider", "instances", alias), nil, nil, nil)
+	return err
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// realmPath returns the admin path of a realm resource, with the escaped realm name and path elements
+func realmPath(realm string, elems ...string) string {
+	escaped := []string{url.PathEscape(realm)}
+	for _, elem := range elems {
+		escaped = append(escaped, url.PathEscape(elem))
+	}
+	return path.Join(escaped...)
+}
+
+// GetRealm returns the realm, or nil if it doesn't exist
+func (c *AdminClient) GetRealm(name string) (*Realm, error) {
+	realm := &Realm{}
+	if _, err := c.do(http.MethodGet, realmPath(name), nil, nil, realm); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return realm, nil
+}
+
+// CreateRealm creates the realm
+func (c *AdminClient) CreateRealm(realm Realm) error {
+	_, err := c.do(http.MethodPost, "", nil, realm, nil)
+	return err
+}
+
+// UpdateRealm updates the fields of the realm that are set
+func (c *AdminClient) UpdateRealm(realm Realm) error {
+	_, err := c.do(http.MethodPut, realmPath(realm.Realm), nil, realm, nil)
+	return err
+}
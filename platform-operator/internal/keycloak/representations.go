@@ -0,0 +1,130 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+// The representations only have the fields that Verrazzano configures.  Keycloak only updates the fields that are
+// set, so optional fields are omitted when empty and booleans are pointers.
+
+// SecretMask is the value that Keycloak returns instead of the secrets in the config of identity providers and
+// components.  Updating a secret to the mask keeps the secret.
+const SecretMask = "**********"
+
+// Realm is a Keycloak realm
+type Realm struct {
+	ID             string `json:"id,omitempty"`
+	Realm          string `json:"realm"`
+	Enabled        *bool  `json:"enabled,omitempty"`
+	PasswordPolicy string `json:"passwordPolicy,omitempty"`
+	LoginTheme     string `json:"loginTheme,omitempty"`
+}
+
+// Group is a Keycloak group and its subgroups
+type Group struct {
+	ID        string  `json:"id,omitempty"`
+	Name      string  `json:"name"`
+	Path      string  `json:"path,omitempty"`
+	SubGroups []Group `json:"subGroups,omitempty"`
+	// Attributes are only returned by GetGroups, an empty value removes the attribute when the group is updated
+	Attributes map[string][]string `json:"attributes,omitempty"`
+}
+
+// Role is a Keycloak realm role
+type Role struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Composite   bool   `json:"composite,omitempty"`
+	ClientRole  bool   `json:"clientRole,omitempty"`
+	ContainerID string `json:"containerId,omitempty"`
+}
+
+// User is a Keycloak user.  Groups are the paths of the groups of a user that is created.
+type User struct {
+	ID       string   `json:"id,omitempty"`
+	Username string   `json:"username"`
+	Enabled  *bool    `json:"enabled,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+// Credential is a credential of a Keycloak user
+type Credential struct {
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	Temporary bool   `json:"temporary"`
+}
+
+// Client is a Keycloak client.  ID is the ID that Keycloak generates, ClientID is the name of the client.
+type Client struct {
+	ID                                 string            `json:"id,omitempty"`
+	ClientID                           string            `json:"clientId"`
+	Enabled                            *bool             `json:"enabled,omitempty"`
+	RootURL                            *string           `json:"rootUrl,omitempty"`
+	AdminURL                           *string           `json:"adminUrl,omitempty"`
+	SurrogateAuthRequired              *bool             `json:"surrogateAuthRequired,omitempty"`
+	AlwaysDisplayInConsole             *bool             `json:"alwaysDisplayInConsole,omitempty"`
+	ClientAuthenticatorType            string            `json:"clientAuthenticatorType,omitempty"`
+	Secret                             string            `json:"secret,omitempty"`
+	RedirectURIs                       []string          `json:"redirectUris,omitempty"`
+	WebOrigins                         []string          `json:"webOrigins,omitempty"`
+	NotBefore                          *int              `json:"notBefore,omitempty"`
+	BearerOnly                         *bool             `json:"bearerOnly,omitempty"`
+	ConsentRequired                    *bool             `json:"consentRequired,omitempty"`
+	StandardFlowEnabled                *bool             `json:"standardFlowEnabled,omitempty"`
+	ImplicitFlowEnabled                *bool             `json:"implicitFlowEnabled,omitempty"`
+	DirectAccessGrantsEnabled          *bool             `json:"directAccessGrantsEnabled,omitempty"`
+	ServiceAccountsEnabled             *bool             `json:"serviceAccountsEnabled,omitempty"`
+	PublicClient                       *bool             `json:"publicClient,omitempty"`
+	FrontchannelLogout                 *bool             `json:"frontchannelLogout,omitempty"`
+	Protocol                           string            `json:"protocol,omitempty"`
+	Attributes                         map[string]string `json:"attributes,omitempty"`
+	AuthenticationFlowBindingOverrides map[string]string `json:"authenticationFlowBindingOverrides,omitempty"`
+	FullScopeAllowed                   *bool             `json:"fullScopeAllowed,omitempty"`
+	NodeReRegistrationTimeout          *int              `json:"nodeReRegistrationTimeout,omitempty"`
+	ProtocolMappers                    []ProtocolMapper  `json:"protocolMappers,omitempty"`
+	DefaultClientScopes                []string          `json:"defaultClientScopes,omitempty"`
+	OptionalClientScopes               []string          `json:"optionalClientScopes,omitempty"`
+}
+
+// ProtocolMapper maps user and session data to the claims of the tokens of a client
+type ProtocolMapper struct {
+	Name            string            `json:"name"`
+	Protocol        string            `json:"protocol"`
+	ProtocolMapper  string            `json:"protocolMapper"`
+	ConsentRequired *bool             `json:"consentRequired,omitempty"`
+	Config          map[string]string `json:"config,omitempty"`
+}
+
+// IdentityProvider is an upstream identity provider that users of a realm can log in with.  Keycloak masks the
+// secrets in the config of the identity providers it returns.
+type IdentityProvider struct {
+	Alias       string            `json:"alias"`
+	DisplayName string            `json:"displayName,omitempty"`
+	ProviderID  string            `json:"providerId"`
+	Enabled     *bool             `json:"enabled,omitempty"`
+	Config      map[string]string `json:"config,omitempty"`
+}
+
+// Component is a provider of a realm, for example a user federation provider.  Keycloak masks the secrets in the
+// config of the components it returns.
+type Component struct {
+	ID           string              `json:"id,omitempty"`
+	Name         string              `json:"name"`
+	ProviderID   string              `json:"providerId"`
+	ProviderType string              `json:"providerType"`
+	ParentID     string              `json:"parentId,omitempty"`
+	Config       map[string][]string `json:"config,omitempty"`
+}
+
+// FindGroup returns the group with the name, searching the groups and their subgroups, or nil if there is none
+func FindGroup(groups []Group, name string) *Group {
+	for i := range groups {
+		if groups[i].Name == name {
+			return &groups[i]
+		}
+		if group := FindGroup(groups[i].SubGroups, name); group != nil {
+			return group
+		}
+	}
+	return nil
+}
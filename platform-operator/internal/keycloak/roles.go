@@ -0,0 +1,35 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"net/http"
+)
+
+// GetRoles returns the realm roles of the realm
+func (c *AdminClient) GetRoles(realm string) ([]Role, error) {
+	var roles []Role
+	if _, err := c.do(http.MethodGet, realmPath(realm, "roles"), nil, nil, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetRole returns the realm role, or nil if it doesn't exist
+func (c *AdminClient) GetRole(realm string, name string) (*Role, error) {
+	role := &Role{}
+	if _, err := c.do(http.MethodGet, realmPath(realm, "roles", name), nil, nil, role); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+// CreateRole creates a realm role
+func (c *AdminClient) CreateRole(realm string, role Role) error {
+	_, err := c.do(http.MethodPost, realmPath(realm, "roles"), nil, role, nil)
+	return err
+}
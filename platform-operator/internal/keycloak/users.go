@@ -0,0 +1,41 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package keycloak
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// FindUser returns the user with the username, or nil if there is none
+func (c *AdminClient) FindUser(realm string, username string) (*User, error) {
+	// Keycloak returns the users whose username contains the search string
+	var users []User
+	query := url.Values{"username": {username}}
+	if _, err := c.do(http.MethodGet, realmPath(realm, "users"), query, nil, &users); err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if users[i].Username == username {
+			return &users[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateUser creates a user and returns its ID
+func (c *AdminClient) CreateUser(realm string, user User) (string, error) {
+	header, err := c.do(http.MethodPost, realmPath(realm, "users"), nil, user, nil)
+	if err != nil {
+		return "", err
+	}
+	return getCreatedID(header)
+}
+
+// SetPassword sets the password of a user, the user isn't asked to change it
+func (c *AdminClient) SetPassword(realm string, userID string, password string) error {
+	credential := Credential{Type: "password", Value: password, Temporary: false}
+	_, err := c.do(http.MethodPut, realmPath(realm, "users", userID, "reset-password"), nil, credential, nil)
+	return err
+}
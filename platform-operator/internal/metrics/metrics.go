@@ -0,0 +1,226 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// metricsNamespace is the prefix of the platform operator metric names
+const metricsNamespace = "vpo"
+
+// Names of the component operations
+const (
+	PreInstallOperation    = "PreInstall"
+	InstallOperation       = "Install"
+	PostInstallOperation   = "PostInstall"
+	PreUpgradeOperation    = "PreUpgrade"
+	UpgradeOperation       = "Upgrade"
+	PostUpgradeOperation   = "PostUpgrade"
+	RollbackOperation      = "Rollback"
+	ReconcileOperation     = "Reconcile"
+	RemediateOperation     = "Remediate"
+	PreUninstallOperation  = "PreUninstall"
+	UninstallOperation     = "Uninstall"
+	PostUninstallOperation = "PostUninstall"
+)
+
+// vzStates are the states of the Verrazzano resource reported by the state gauge
+var vzStates = []vzapi.VzStateType{
+	vzapi.VzStateInstalling,
+	vzapi.VzStateUninstalling,
+	vzapi.VzStateUpgrading,
+	vzapi.VzStatePaused,
+	vzapi.VzStateReady,
+	vzapi.VzStateFailed,
+}
+
+var (
+	componentStateSecondsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "component_state_seconds_total",
+		Help:      "Total seconds a component has spent in a state, updated when the component leaves the state",
+	}, []string{"component", "state"})
+
+	componentOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "component_operation_duration_seconds",
+		Help:      "Duration of the component install and upgrade operations",
+		Buckets:   []float64{0.5, 1, 5, 15, 30, 60, 120, 300, 600, 1200},
+	}, []string{"component", "operation"})
+
+	componentOperationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "component_operation_errors_total",
+		Help:      "Number of component install and upgrade operations that failed",
+	}, []string{"component", "operation"})
+
+	componentRequeues = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "component_requeues_total",
+		Help:      "Number of times the reconcile was requeued because of a component",
+	}, []string{"component"})
+
+	verrazzanoState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "verrazzano_state",
+		Help:      "State of the Verrazzano resource, 1 for the current state and 0 for the other states",
+	}, []string{"namespace", "name", "state"})
+
+	certificateExpiration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "certificate_expiration_timestamp_seconds",
+		Help:      "Time a certificate of the Verrazzano installation expires, in seconds since the epoch",
+	}, []string{"namespace", "name", "source"})
+
+	certificatesExpiringSoon = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "certificates_expiring_soon",
+		Help:      "Number of certificates of the Verrazzano resource that expire within the certificate expiry threshold, or have expired",
+	}, []string{"namespace", "name"})
+
+	componentStates = newComponentStateCollector()
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		componentStates,
+		componentStateSecondsTotal,
+		componentOperationDuration,
+		componentOperationErrors,
+		componentRequeues,
+		verrazzanoState,
+		certificateExpiration,
+		certificatesExpiringSoon,
+	)
+}
+
+// RecordVerrazzanoStatus records the state of the Verrazzano resource and the states of its components
+func RecordVerrazzanoStatus(vz *vzapi.Verrazzano) {
+	for _, state := range vzStates {
+		value := 0.0
+		if vz.Status.State == state {
+			value = 1
+		}
+		verrazzanoState.WithLabelValues(vz.Namespace, vz.Name, string(state)).Set(value)
+	}
+	for _, compStatus := range vz.Status.Components {
+		if compStatus != nil && len(compStatus.State) > 0 {
+			componentStates.setState(compStatus.Name, compStatus.State)
+		}
+	}
+}
+
+// DeleteVerrazzano removes the metrics of a Verrazzano resource that has been deleted
+func DeleteVerrazzano(namespace string, name string) {
+	for _, state := range vzStates {
+		verrazzanoState.DeleteLabelValues(namespace, name, string(state))
+	}
+	componentStates.reset()
+	certificateExpiration.Reset()
+	certificatesExpiringSoon.DeleteLabelValues(namespace, name)
+}
+
+// RecordCertificates records the expiration times of the certificates of the Verrazzano resource, and the number of
+// certificates that are expiring soon or have expired
+func RecordCertificates(vz *vzapi.Verrazzano) {
+	certificateExpiration.Reset()
+	expiringSoon := 0
+	for _, cert := range vz.Status.Certificates {
+		if notAfter, err := time.Parse(time.RFC3339, cert.NotAfter); err == nil {
+			certificateExpiration.WithLabelValues(cert.Namespace, cert.Name, string(cert.Source)).Set(float64(notAfter.Unix()))
+		}
+		if cert.RenewalState == vzapi.CertificateExpiringSoon || cert.RenewalState == vzapi.CertificateExpired {
+			expiringSoon++
+		}
+	}
+	certificatesExpiringSoon.WithLabelValues(vz.Namespace, vz.Name).Set(float64(expiringSoon))
+}
+
+// TimeOperation calls the component operation, recording its duration and whether it failed.  Retryable errors,
+// such as waiting for a resource to be ready, are not counted as failures.
+func TimeOperation(component string, operation string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	componentOperationDuration.WithLabelValues(component, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		if _, ok := err.(ctrlerrors.RetryableError); !ok {
+			componentOperationErrors.WithLabelValues(component, operation).Inc()
+		}
+	}
+	return err
+}
+
+// CountRequeue records that the reconcile was requeued because of the component
+func CountRequeue(component string) {
+	componentRequeues.WithLabelValues(component).Inc()
+}
+
+// componentState is the current state of a component and the time the state was first observed
+type componentState struct {
+	state vzapi.CompStateType
+	since time.Time
+}
+
+// componentStateCollector reports the number of seconds each component has been in its current state.  The time
+// is computed when the metrics are collected, so the gauge keeps increasing while a component is stuck in a state.
+type componentStateCollector struct {
+	lock   sync.Mutex
+	desc   *prometheus.Desc
+	states map[string]componentState
+	now    func() time.Time
+}
+
+func newComponentStateCollector() *componentStateCollector {
+	return &componentStateCollector{
+		desc: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "component_state_seconds"),
+			"Seconds a component has been in its current state", []string{"component", "state"}, nil),
+		states: map[string]componentState{},
+		now:    time.Now,
+	}
+}
+
+// setState records the current state of a component.  When the state changes the time spent in the previous state
+// is added to the state total.
+func (c *componentStateCollector) setState(component string, state vzapi.CompStateType) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	current, ok := c.states[component]
+	if ok && current.state == state {
+		return
+	}
+	if ok {
+		componentStateSecondsTotal.WithLabelValues(component, string(current.state)).Add(now.Sub(current.since).Seconds())
+	}
+	c.states[component] = componentState{state: state, since: now}
+}
+
+// reset forgets the states of all of the components
+func (c *componentStateCollector) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.states = map[string]componentState{}
+}
+
+// Describe implements prometheus.Collector
+func (c *componentStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *componentStateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	for component, current := range c.states {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(current.since).Seconds(), component, string(current.state))
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	ctrlerrors "github.com/verrazzano/verrazzano/pkg/controller/errors"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestRecordVerrazzanoStatus tests the RecordVerrazzanoStatus func
+// GIVEN a Verrazzano resource whose component changes state
+// WHEN RecordVerrazzanoStatus is called
+// THEN ensure the Verrazzano state gauge is set, and the time in the current and previous component states are recorded
+func TestRecordVerrazzanoStatus(t *testing.T) {
+	asserts := assert.New(t)
+	now := time.Now()
+	componentStates.now = func() time.Time { return now }
+	defer func() { componentStates.now = time.Now }()
+	defer DeleteVerrazzano("verrazzano", "test")
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test"},
+		Status: vzapi.VerrazzanoStatus{
+			State:      vzapi.VzStateInstalling,
+			Components: vzapi.ComponentStatusMap{"fake": {Name: "fake", State: vzapi.CompStateInstalling}},
+		},
+	}
+	RecordVerrazzanoStatus(vz)
+	asserts.Equal(1.0, testutil.ToFloat64(verrazzanoState.WithLabelValues("verrazzano", "test", string(vzapi.VzStateInstalling))))
+	asserts.Equal(0.0, testutil.ToFloat64(verrazzanoState.WithLabelValues("verrazzano", "test", string(vzapi.VzStateReady))))
+
+	// The time in the current state keeps increasing while the state does not change
+	now = now.Add(90 * time.Second)
+	RecordVerrazzanoStatus(vz)
+	asserts.NoError(testutil.CollectAndCompare(componentStates, strings.NewReader(`
+# HELP vpo_component_state_seconds Seconds a component has been in its current state
+# TYPE vpo_component_state_seconds gauge
+vpo_component_state_seconds{component="fake",state="Installing"} 90
+`)))
+
+	// When the state changes the time in the previous state is added to the total
+	now = now.Add(10 * time.Second)
+	vz.Status.State = vzapi.VzStateReady
+	vz.Status.Components["fake"].State = vzapi.CompStateReady
+	RecordVerrazzanoStatus(vz)
+	asserts.Equal(100.0, testutil.ToFloat64(componentStateSecondsTotal.WithLabelValues("fake", string(vzapi.CompStateInstalling))))
+	asserts.Equal(0.0, testutil.ToFloat64(verrazzanoState.WithLabelValues("verrazzano", "test", string(vzapi.VzStateInstalling))))
+	asserts.Equal(1.0, testutil.ToFloat64(verrazzanoState.WithLabelValues("verrazzano", "test", string(vzapi.VzStateReady))))
+	asserts.NoError(testutil.CollectAndCompare(componentStates, strings.NewReader(`
+# HELP vpo_component_state_seconds Seconds a component has been in its current state
+# TYPE vpo_component_state_seconds gauge
+vpo_component_state_seconds{component="fake",state="Ready"} 0
+`)))
+}
+
+// TestTimeOperation tests the TimeOperation func
+// GIVEN component operations that succeed, fail and need to be retried
+// WHEN TimeOperation is called
+// THEN ensure the operation durations are recorded and only the failed operation is counted as an error
+func TestTimeOperation(t *testing.T) {
+	asserts := assert.New(t)
+
+	asserts.NoError(TimeOperation("timed", InstallOperation, func() error { return nil }))
+	asserts.Error(TimeOperation("timed", InstallOperation, func() error { return errors.New("unexpected error") }))
+	asserts.Error(TimeOperation("timed", PostInstallOperation, func() error { return ctrlerrors.RetryableError{Source: "timed"} }))
+
+	asserts.Equal(1.0, testutil.ToFloat64(componentOperationErrors.WithLabelValues("timed", InstallOperation)))
+	asserts.Equal(0.0, testutil.ToFloat64(componentOperationErrors.WithLabelValues("timed", PostInstallOperation)))
+	asserts.Equal(2, testutil.CollectAndCount(componentOperationDuration, "vpo_component_operation_duration_seconds"))
+}
+
+// TestCountRequeue tests the CountRequeue func
+// GIVEN a component that requeues the reconcile
+// WHEN CountRequeue is called
+// THEN ensure the requeue counter is incremented
+func TestCountRequeue(t *testing.T) {
+	CountRequeue("requeued")
+	CountRequeue("requeued")
+	assert.Equal(t, 2.0, testutil.ToFloat64(componentRequeues.WithLabelValues("requeued")))
+}
+
+// TestRecordCertificates tests the RecordCertificates func
+// GIVEN a Verrazzano resource with a valid certificate and a certificate that is expiring soon
+// WHEN RecordCertificates is called
+// THEN ensure the certificate expiration times and the number of certificates expiring soon are recorded
+func TestRecordCertificates(t *testing.T) {
+	asserts := assert.New(t)
+	defer DeleteVerrazzano("verrazzano", "test")
+
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "test"},
+		Status: vzapi.VerrazzanoStatus{
+			Certificates: []vzapi.CertificateStatus{
+				{Name: "a-cert", Namespace: "istio-system", Source: vzapi.CertificateSourceIngressTrait, NotAfter: "2022-06-01T00:00:00Z", RenewalState: vzapi.CertificateExpiringSoon},
+				{Name: "webhook", Source: vzapi.CertificateSourceWebhook, NotAfter: "2023-01-01T00:00:00Z", RenewalState: vzapi.CertificateValid},
+			},
+		},
+	}
+	RecordCertificates(vz)
+	asserts.Equal(1654041600.0, testutil.ToFloat64(certificateExpiration.WithLabelValues("istio-system", "a-cert", string(vzapi.CertificateSourceIngressTrait))))
+	asserts.Equal(1672531200.0, testutil.ToFloat64(certificateExpiration.WithLabelValues("", "webhook", string(vzapi.CertificateSourceWebhook))))
+	asserts.Equal(1.0, testutil.ToFloat64(certificatesExpiringSoon.WithLabelValues("verrazzano", "test")))
+
+	// Certificates that are no longer in the status are removed
+	vz.Status.Certificates = vz.Status.Certificates[1:]
+	RecordCertificates(vz)
+	asserts.Equal(1, testutil.CollectAndCount(certificateExpiration))
+	asserts.Equal(0.0, testutil.ToFloat64(certificatesExpiringSoon.WithLabelValues("verrazzano", "test")))
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vzconfig
+
+import (
+	"fmt"
+	"strings"
+
+	vzstring "github.com/verrazzano/verrazzano/pkg/string"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+)
+
+// The JSON names of the settings of the kubernetes section of the components
+const (
+	KubernetesReplicas            = "replicas"
+	KubernetesAffinity            = "affinity"
+	KubernetesTolerations         = "tolerations"
+	KubernetesNodeSelector        = "nodeSelector"
+	KubernetesPriorityClassName   = "priorityClassName"
+	KubernetesResources           = "resources"
+	KubernetesPodDisruptionBudget = "podDisruptionBudget"
+)
+
+// GetKubernetesSettings returns the JSON names of the settings that are set in the kubernetes section of a component
+func GetKubernetesSettings(spec *vzapi.CommonKubernetesSpec) []string {
+	if spec == nil {
+		return nil
+	}
+	var settings []string
+	if spec.Replicas > 0 {
+		settings = append(settings, KubernetesReplicas)
+	}
+	if spec.Affinity != nil {
+		settings = append(settings, KubernetesAffinity)
+	}
+	if len(spec.Tolerations) > 0 {
+		settings = append(settings, KubernetesTolerations)
+	}
+	if len(spec.NodeSelector) > 0 {
+		settings = append(settings, KubernetesNodeSelector)
+	}
+	if len(spec.PriorityClassName) > 0 {
+		settings = append(settings, KubernetesPriorityClassName)
+	}
+	if spec.Resources != nil {
+		settings = append(settings, KubernetesResources)
+	}
+	if spec.PodDisruptionBudget != nil {
+		settings = append(settings, KubernetesPodDisruptionBudget)
+	}
+	return settings
+}
+
+// ValidateKubernetesSettings checks that the kubernetes section of a component only has the settings the component
+// supports, and that its pod disruption budget is valid
+func ValidateKubernetesSettings(componentJSONName string, spec *vzapi.CommonKubernetesSpec, supported ...string) error {
+	var unsupported []string
+	for _, setting := range GetKubernetesSettings(spec) {
+		if !vzstring.SliceContainsString(supported, setting) {
+			unsupported = append(unsupported, setting)
+		}
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("The kubernetes settings %s are not supported by the component %s",
+			strings.Join(unsupported, ", "), componentJSONName)
+	}
+	if spec != nil && spec.PodDisruptionBudget != nil {
+		pdb := spec.PodDisruptionBudget
+		if (pdb.MinAvailable == nil) == (pdb.MaxUnavailable == nil) {
+			return fmt.Errorf("The kubernetes podDisruptionBudget of the component %s must have one of minAvailable or maxUnavailable",
+				componentJSONName)
+		}
+	}
+	return nil
+}
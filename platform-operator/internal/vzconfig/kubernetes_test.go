@@ -0,0 +1,90 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vzconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// TestGetKubernetesSettings tests the GetKubernetesSettings function
+// GIVEN a call to GetKubernetesSettings
+// WHEN the kubernetes section is nil, empty or has settings
+// THEN the JSON names of the settings that are set are returned
+func TestGetKubernetesSettings(t *testing.T) {
+	assert.Empty(t, GetKubernetesSettings(nil))
+	assert.Empty(t, GetKubernetesSettings(&vzapi.CommonKubernetesSpec{}))
+
+	minAvailable := intstr.FromInt(1)
+	spec := &vzapi.CommonKubernetesSpec{
+		Replicas:            2,
+		Tolerations:         []corev1.Toleration{{Key: "key", Operator: corev1.TolerationOpExists}},
+		PriorityClassName:   "high",
+		PodDisruptionBudget: &vzapi.PodDisruptionBudgetSpec{MinAvailable: &minAvailable},
+	}
+	assert.Equal(t, []string{KubernetesReplicas, KubernetesTolerations, KubernetesPriorityClassName, KubernetesPodDisruptionBudget},
+		GetKubernetesSettings(spec))
+}
+
+// TestValidateKubernetesSettings tests the ValidateKubernetesSettings function
+// GIVEN a call to ValidateKubernetesSettings
+// WHEN for valid and invalid kubernetes sections
+// THEN an error is returned when appropriate
+func TestValidateKubernetesSettings(t *testing.T) {
+	one := intstr.FromInt(1)
+	percent := intstr.FromString("50%")
+	tests := []struct {
+		name        string
+		spec        *vzapi.CommonKubernetesSpec
+		supported   []string
+		expectedErr string
+	}{
+		{
+			name: "NoKubernetesSection",
+		},
+		{
+			name:      "SupportedSettings",
+			spec:      &vzapi.CommonKubernetesSpec{Replicas: 3, NodeSelector: map[string]string{"disk": "ssd"}},
+			supported: []string{KubernetesReplicas, KubernetesNodeSelector},
+		},
+		{
+			name:        "UnsupportedSettings",
+			spec:        &vzapi.CommonKubernetesSpec{Replicas: 3, NodeSelector: map[string]string{"disk": "ssd"}, PriorityClassName: "high"},
+			supported:   []string{KubernetesNodeSelector},
+			expectedErr: "The kubernetes settings replicas, priorityClassName are not supported by the component test",
+		},
+		{
+			name:      "PodDisruptionBudgetWithMaxUnavailable",
+			spec:      &vzapi.CommonKubernetesSpec{PodDisruptionBudget: &vzapi.PodDisruptionBudgetSpec{MaxUnavailable: &percent}},
+			supported: []string{KubernetesPodDisruptionBudget},
+		},
+		{
+			name:        "PodDisruptionBudgetWithBothValues",
+			spec:        &vzapi.CommonKubernetesSpec{PodDisruptionBudget: &vzapi.PodDisruptionBudgetSpec{MinAvailable: &one, MaxUnavailable: &percent}},
+			supported:   []string{KubernetesPodDisruptionBudget},
+			expectedErr: "must have one of minAvailable or maxUnavailable",
+		},
+		{
+			name:        "PodDisruptionBudgetWithoutValues",
+			spec:        &vzapi.CommonKubernetesSpec{PodDisruptionBudget: &vzapi.PodDisruptionBudgetSpec{}},
+			supported:   []string{KubernetesPodDisruptionBudget},
+			expectedErr: "must have one of minAvailable or maxUnavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKubernetesSettings("test", tt.spec, tt.supported...)
+			if len(tt.expectedErr) > 0 {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vzconfig
+
+import (
+	"fmt"
+
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetVolumeSource returns the volume source of a component, which is the DefaultVolumeSource of the Verrazzano CR
+// when the component has none
+func GetVolumeSource(vz *vzapi.Verrazzano, componentSource *corev1.VolumeSource) *corev1.VolumeSource {
+	if componentSource != nil {
+		return componentSource
+	}
+	if vz == nil {
+		return nil
+	}
+	return vz.Spec.DefaultVolumeSource
+}
+
+// FindVolumeClaimSpec returns the spec of the VolumeClaimSpecTemplate referenced by a volume source, nil is returned
+// when there is no volume source or it is an emptyDir
+func FindVolumeClaimSpec(vz *vzapi.Verrazzano, source *corev1.VolumeSource) (*corev1.PersistentVolumeClaimSpec, error) {
+	if source == nil || source.EmptyDir != nil {
+		return nil, nil
+	}
+	if source.PersistentVolumeClaim == nil {
+		return nil, fmt.Errorf("Failed, unsupported volume source: %v", source)
+	}
+	claimName := source.PersistentVolumeClaim.ClaimName
+	spec, found := FindVolumeTemplate(claimName, vz.Spec.VolumeClaimSpecTemplates)
+	if !found {
+		return nil, fmt.Errorf("Failed, did not find matching storage volume template for claim %s", claimName)
+	}
+	return spec, nil
+}
+
+// ValidateVolumeSource checks that the volume source of a component is either an emptyDir or references one of the
+// VolumeClaimSpecTemplates of the Verrazzano CR
+func ValidateVolumeSource(vz *vzapi.Verrazzano, source *corev1.VolumeSource, name string) error {
+	if source == nil {
+		return nil
+	}
+	if (source.EmptyDir == nil) == (source.PersistentVolumeClaim == nil) {
+		return fmt.Errorf("The volume source of %s must have one of emptyDir or persistentVolumeClaim", name)
+	}
+	if source.PersistentVolumeClaim == nil {
+		return nil
+	}
+	claimName := source.PersistentVolumeClaim.ClaimName
+	if _, found := FindVolumeTemplate(claimName, vz.Spec.VolumeClaimSpecTemplates); !found {
+		return fmt.Errorf("The volume source of %s references the volume claim template %s, which does not exist", name, claimName)
+	}
+	return nil
+}
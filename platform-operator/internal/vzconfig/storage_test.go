@@ -0,0 +1,126 @@
+// Copyright (c) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vzconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var emptyDirSource = &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+
+var vmiClaimSource = &corev1.VolumeSource{
+	PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "vmi"},
+}
+
+var storageVZ = &vzapi.Verrazzano{
+	Spec: vzapi.VerrazzanoSpec{
+		DefaultVolumeSource: emptyDirSource,
+		VolumeClaimSpecTemplates: []vzapi.VolumeClaimSpecTemplate{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "vmi"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("100Gi")},
+					},
+				},
+			},
+		},
+	},
+}
+
+// TestGetVolumeSource tests the GetVolumeSource function
+// GIVEN a Verrazzano CR with a DefaultVolumeSource
+// WHEN GetVolumeSource is called with or without the volume source of a component
+// THEN the volume source of the component takes precedence over the DefaultVolumeSource
+func TestGetVolumeSource(t *testing.T) {
+	assert.Nil(t, GetVolumeSource(nil, nil))
+	assert.Nil(t, GetVolumeSource(&vzapi.Verrazzano{}, nil))
+	assert.Equal(t, emptyDirSource, GetVolumeSource(storageVZ, nil))
+	assert.Equal(t, vmiClaimSource, GetVolumeSource(storageVZ, vmiClaimSource))
+}
+
+// TestFindVolumeClaimSpec tests the FindVolumeClaimSpec function
+// GIVEN a Verrazzano CR with a volume claim template
+// WHEN FindVolumeClaimSpec is called for different volume sources
+// THEN the spec of the referenced template is returned, or an error when it does not exist
+func TestFindVolumeClaimSpec(t *testing.T) {
+	spec, err := FindVolumeClaimSpec(storageVZ, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, spec)
+
+	spec, err = FindVolumeClaimSpec(storageVZ, emptyDirSource)
+	assert.NoError(t, err)
+	assert.Nil(t, spec)
+
+	spec, err = FindVolumeClaimSpec(storageVZ, vmiClaimSource)
+	assert.NoError(t, err)
+	assert.Equal(t, "100Gi", spec.Resources.Requests.Storage().String())
+
+	_, err = FindVolumeClaimSpec(storageVZ, &corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "missing"},
+	})
+	assert.Error(t, err)
+
+	_, err = FindVolumeClaimSpec(storageVZ, &corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/data"}})
+	assert.Error(t, err)
+}
+
+// TestValidateVolumeSource tests the ValidateVolumeSource function
+// GIVEN a Verrazzano CR with a volume claim template
+// WHEN ValidateVolumeSource is called for valid and invalid volume sources
+// THEN an error is returned when appropriate
+func TestValidateVolumeSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      *corev1.VolumeSource
+		expectedErr string
+	}{
+		{
+			name: "NoVolumeSource",
+		},
+		{
+			name:   "EmptyDir",
+			source: emptyDirSource,
+		},
+		{
+			name:   "VolumeClaimTemplate",
+			source: vmiClaimSource,
+		},
+		{
+			name:        "MissingVolumeClaimTemplate",
+			source:      &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "missing"}},
+			expectedErr: "The volume source of test references the volume claim template missing, which does not exist",
+		},
+		{
+			name:        "UnsupportedVolumeSource",
+			source:      &corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/data"}},
+			expectedErr: "must have one of emptyDir or persistentVolumeClaim",
+		},
+		{
+			name: "BothVolumeSources",
+			source: &corev1.VolumeSource{
+				EmptyDir:              &corev1.EmptyDirVolumeSource{},
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "vmi"},
+			},
+			expectedErr: "must have one of emptyDir or persistentVolumeClaim",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateVolumeSource(storageVZ, tt.source, "test")
+			if len(tt.expectedErr) > 0 {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	oam "github.com/crossplane/oam-kubernetes-runtime/apis/core"
 	cmapiv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	vzappclusters "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
 	vzapp "github.com/verrazzano/verrazzano/application-operator/apis/oam/v1alpha1"
 	"github.com/verrazzano/verrazzano/pkg/helm"
 	vzlog "github.com/verrazzano/verrazzano/pkg/log"
@@ -19,6 +20,10 @@ import (
 	clusterscontroller "github.com/verrazzano/verrazzano/platform-operator/controllers/clusters"
 	secretscontroller "github.com/verrazzano/verrazzano/platform-operator/controllers/secrets"
 	vzcontroller "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/backup"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component/registry"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/plan"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/transform"
 	internalconfig "github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/certificate"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s/netpolicy"
@@ -28,6 +33,7 @@ import (
 
 	"os"
 
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -52,6 +58,9 @@ func init() {
 	_ = oam.AddToScheme(scheme)
 
 	_ = vzapp.AddToScheme(scheme)
+	_ = vzappclusters.AddToScheme(scheme)
+
+	_ = apiextv1.AddToScheme(scheme)
 
 	// Add cert-manager components to the scheme
 	cmapiv1.AddToScheme(scheme)
@@ -81,6 +90,8 @@ func main() {
 		"Specify the root directory of Verrazzano (used for development)")
 	flag.StringVar(&bomOverride, "bom-path", "", "BOM file location")
 	flag.BoolVar(&helm.Debug, "helm-debug", helm.Debug, "Add the --debug flag to helm commands")
+	flag.IntVar(&config.ComponentConcurrency, "component-concurrency", config.ComponentConcurrency,
+		"The maximum number of components that are installed or upgraded at the same time")
 
 	// Add the zap logger flag set to the CLI.
 	opts := kzap.Options{}
@@ -96,6 +107,12 @@ func main() {
 
 	log.Info("Starting Verrazzano Platform Operator")
 
+	// Components are installed and upgraded in dependency order, make sure the dependency graph is valid
+	if err := registry.ValidateDependencies(); err != nil {
+		log.Errorf("Invalid component dependencies: %v", err)
+		os.Exit(1)
+	}
+
 	// Set the BOM file path for the operator
 	if len(bomOverride) > 0 {
 		log.Infof("Using BOM override file %s", bomOverride)
@@ -159,18 +176,53 @@ func main() {
 	}
 
 	installv1alpha1.SetComponentValidator(validator.ComponentValidatorImpl{})
+	installv1alpha1.SetProfileMerger(transform.ValidateProfileChain)
+	transform.SetProfileReader(mgr.GetClient())
 
 	// Setup the reconciler
 	reconciler := vzcontroller.Reconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		DryRun: config.DryRun,
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		DryRun:        config.DryRun,
+		EventRecorder: mgr.GetEventRecorderFor("verrazzano-platform-operator"),
 	}
 	if err = reconciler.SetupWithManager(mgr); err != nil {
 		log.Error(err, "Failed to setup controller", vzlog.FieldController, "Verrazzano")
 		os.Exit(1)
 	}
 
+	if err = (&plan.VerrazzanoPlanReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "Failed to setup controller", vzlog.FieldController, "VerrazzanoPlan")
+		os.Exit(1)
+	}
+
+	if err = (&vzcontroller.VerrazzanoExtensionReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "Failed to setup controller", vzlog.FieldController, "VerrazzanoExtension")
+		os.Exit(1)
+	}
+
+	if err = (&backup.VerrazzanoBackupReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "Failed to setup controller", vzlog.FieldController, "VerrazzanoBackup")
+		os.Exit(1)
+	}
+
+	if err = (&backup.VerrazzanoRestoreReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "Failed to setup controller", vzlog.FieldController, "VerrazzanoRestore")
+		os.Exit(1)
+	}
+
 	// Setup the validation webhook
 	if config.WebhooksEnabled {
 		log.Debug("Setting up Verrazzano webhook with manager")
@@ -383,6 +383,21 @@ func (mr *MockComponentInstallerMockRecorder) IsOperatorInstallSupported() *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOperatorInstallSupported", reflect.TypeOf((*MockComponentInstaller)(nil).IsOperatorInstallSupported))
 }
 
+// IsUninstalled mocks base method.
+func (m *MockComponentInstaller) IsUninstalled(arg0 spi.ComponentContext) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsUninstalled", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsUninstalled indicates an expected call of IsUninstalled.
+func (mr *MockComponentInstallerMockRecorder) IsUninstalled(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUninstalled", reflect.TypeOf((*MockComponentInstaller)(nil).IsUninstalled), arg0)
+}
+
 // PostInstall mocks base method.
 func (m *MockComponentInstaller) PostInstall(arg0 spi.ComponentContext) error {
 	m.ctrl.T.Helper()
@@ -397,6 +412,20 @@ func (mr *MockComponentInstallerMockRecorder) PostInstall(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInstall", reflect.TypeOf((*MockComponentInstaller)(nil).PostInstall), arg0)
 }
 
+// PostUninstall mocks base method.
+func (m *MockComponentInstaller) PostUninstall(arg0 spi.ComponentContext) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostUninstall", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostUninstall indicates an expected call of PostUninstall.
+func (mr *MockComponentInstallerMockRecorder) PostUninstall(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostUninstall", reflect.TypeOf((*MockComponentInstaller)(nil).PostUninstall), arg0)
+}
+
 // PreInstall mocks base method.
 func (m *MockComponentInstaller) PreInstall(arg0 spi.ComponentContext) error {
 	m.ctrl.T.Helper()
@@ -411,6 +440,34 @@ func (mr *MockComponentInstallerMockRecorder) PreInstall(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreInstall", reflect.TypeOf((*MockComponentInstaller)(nil).PreInstall), arg0)
 }
 
+// PreUninstall mocks base method.
+func (m *MockComponentInstaller) PreUninstall(arg0 spi.ComponentContext) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreUninstall", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PreUninstall indicates an expected call of PreUninstall.
+func (mr *MockComponentInstallerMockRecorder) PreUninstall(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreUninstall", reflect.TypeOf((*MockComponentInstaller)(nil).PreUninstall), arg0)
+}
+
+// Uninstall mocks base method.
+func (m *MockComponentInstaller) Uninstall(arg0 spi.ComponentContext) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Uninstall", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Uninstall indicates an expected call of Uninstall.
+func (mr *MockComponentInstallerMockRecorder) Uninstall(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Uninstall", reflect.TypeOf((*MockComponentInstaller)(nil).Uninstall), arg0)
+}
+
 // MockComponentUpgrader is a mock of ComponentUpgrader interface.
 type MockComponentUpgrader struct {
 	ctrl     *gomock.Controller
@@ -640,6 +697,21 @@ func (mr *MockComponentMockRecorder) IsReady(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReady", reflect.TypeOf((*MockComponent)(nil).IsReady), arg0)
 }
 
+// IsUninstalled mocks base method.
+func (m *MockComponent) IsUninstalled(arg0 spi.ComponentContext) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsUninstalled", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsUninstalled indicates an expected call of IsUninstalled.
+func (mr *MockComponentMockRecorder) IsUninstalled(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUninstalled", reflect.TypeOf((*MockComponent)(nil).IsUninstalled), arg0)
+}
+
 // Name mocks base method.
 func (m *MockComponent) Name() string {
 	m.ctrl.T.Helper()
@@ -668,6 +740,20 @@ func (mr *MockComponentMockRecorder) PostInstall(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInstall", reflect.TypeOf((*MockComponent)(nil).PostInstall), arg0)
 }
 
+// PostUninstall mocks base method.
+func (m *MockComponent) PostUninstall(arg0 spi.ComponentContext) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostUninstall", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostUninstall indicates an expected call of PostUninstall.
+func (mr *MockComponentMockRecorder) PostUninstall(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostUninstall", reflect.TypeOf((*MockComponent)(nil).PostUninstall), arg0)
+}
+
 // PostUpgrade mocks base method.
 func (m *MockComponent) PostUpgrade(arg0 spi.ComponentContext) error {
 	m.ctrl.T.Helper()
@@ -696,6 +782,20 @@ func (mr *MockComponentMockRecorder) PreInstall(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreInstall", reflect.TypeOf((*MockComponent)(nil).PreInstall), arg0)
 }
 
+// PreUninstall mocks base method.
+func (m *MockComponent) PreUninstall(arg0 spi.ComponentContext) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreUninstall", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PreUninstall indicates an expected call of PreUninstall.
+func (mr *MockComponentMockRecorder) PreUninstall(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreUninstall", reflect.TypeOf((*MockComponent)(nil).PreUninstall), arg0)
+}
+
 // PreUpgrade mocks base method.
 func (m *MockComponent) PreUpgrade(arg0 spi.ComponentContext) error {
 	m.ctrl.T.Helper()
@@ -724,6 +824,20 @@ func (mr *MockComponentMockRecorder) Reconcile(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockComponent)(nil).Reconcile), arg0)
 }
 
+// Uninstall mocks base method.
+func (m *MockComponent) Uninstall(arg0 spi.ComponentContext) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Uninstall", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Uninstall indicates an expected call of Uninstall.
+func (mr *MockComponentMockRecorder) Uninstall(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Uninstall", reflect.TypeOf((*MockComponent)(nil).Uninstall), arg0)
+}
+
 // Upgrade mocks base method.
 func (m *MockComponent) Upgrade(arg0 spi.ComponentContext) error {
 	m.ctrl.T.Helper()
@@ -57,4 +57,6 @@ else
 fi
 sed -i"" -e "s|VERRAZZANO_PLATFORM_OPERATOR_IMAGE|${VERRAZZANO_PLATFORM_OPERATOR_IMAGE_NAME}|g" ${GENERATED_BOM_FILE}
 sed -i"" -e "s|VERRAZZANO_PLATFORM_OPERATOR_TAG|${IMAGE_TAG}|g" ${GENERATED_BOM_FILE}
+# The Verrazzano charts are versioned with the Verrazzano version without its pre-release and build metadata
+sed -i"" -e "s|VERRAZZANO_CHART_VERSION|${VERRAZZANO_VERSION%%[-+]*}|g" ${GENERATED_BOM_FILE}
 sed -i"" -e "s|VERRAZZANO_VERSION|${VERRAZZANO_VERSION}|g" ${GENERATED_BOM_FILE}